@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+
+	"argazer/internal/config"
+)
+
+// Application annotations overriding the central configuration for a single application, so teams can
+// control how their applications are scanned from their own manifests
+const (
+	ignoreAnnotation     = "argazer.io/ignore"      // "true" reports the application as ignored without checking it
+	constraintAnnotation = "argazer.io/constraint"  // Version constraint, overriding constraints and version_constraint
+	sourceNameAnnotation = "argazer.io/source-name" // Source to check in multi-source applications, overriding source_name
+)
+
+// annotationIgnored reports whether the application opted out of scanning with argazer.io/ignore
+// Values that aren't booleans are returned with an error and don't ignore the application.
+func annotationIgnored(app *v1alpha1.Application) (bool, error) {
+	value, ok := app.Annotations[ignoreAnnotation]
+	if !ok {
+		return false, nil
+	}
+	ignored, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return false, fmt.Errorf("invalid %s annotation '%s', expected true or false", ignoreAnnotation, value)
+	}
+	return ignored, nil
+}
+
+// annotationSourceName returns the source name set with argazer.io/source-name, or the fallback
+func annotationSourceName(app *v1alpha1.Application, fallback string) string {
+	if name := strings.TrimSpace(app.Annotations[sourceNameAnnotation]); name != "" {
+		return name
+	}
+	return fallback
+}
+
+// applicationConstraint returns the version constraint of an application: its argazer.io/constraint
+// annotation, the constraints entry of its name or chart, or version_constraint
+// An invalid annotation is returned with an error, as it's only validated here.
+func applicationConstraint(app *v1alpha1.Application, chartName string, cfg *config.Config) (string, error) {
+	if constraint, ok := app.Annotations[constraintAnnotation]; ok {
+		constraint = strings.TrimSpace(constraint)
+		if err := config.ValidateConstraint(constraint); err != nil {
+			return constraint, fmt.Errorf("invalid %s annotation: %w", constraintAnnotation, err)
+		}
+		return constraint, nil
+	}
+
+	// Viper lowercases map keys
+	if constraint, ok := cfg.Constraints[strings.ToLower(app.Name)]; ok {
+		return constraint, nil
+	}
+	if constraint, ok := cfg.Constraints[strings.ToLower(chartName)]; ok {
+		return constraint, nil
+	}
+	return cfg.VersionConstraint, nil
+}
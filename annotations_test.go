@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"argazer/internal/config"
+)
+
+func TestAnnotationIgnored(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+		expectedErr bool
+	}{
+		{name: "no annotation"},
+		{name: "true", annotations: map[string]string{"argazer.io/ignore": "true"}, expected: true},
+		{name: "false", annotations: map[string]string{"argazer.io/ignore": "false"}},
+		{name: "invalid", annotations: map[string]string{"argazer.io/ignore": "please"}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: tt.annotations}}
+			ignored, err := annotationIgnored(app)
+			assert.Equal(t, tt.expected, ignored)
+			assert.Equal(t, tt.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestFindHelmSource_SourceNameAnnotation(t *testing.T) {
+	app := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: map[string]string{"argazer.io/source-name": "upstream"}},
+		Spec: v1alpha1.ApplicationSpec{
+			Sources: []v1alpha1.ApplicationSource{
+				{Name: "chart-repo", RepoURL: "https://charts.example.com", Chart: "web", TargetRevision: "1.0.0"},
+				{Name: "upstream", RepoURL: "https://upstream.example.com", Chart: "web", TargetRevision: "2.0.0"},
+			},
+		},
+	}
+
+	source := findHelmSource(app, "chart-repo", logrus.NewEntry(logrus.New()))
+	require.NotNil(t, source)
+	assert.Equal(t, "upstream", source.Name)
+
+	delete(app.Annotations, "argazer.io/source-name")
+	source = findHelmSource(app, "chart-repo", logrus.NewEntry(logrus.New()))
+	require.NotNil(t, source)
+	assert.Equal(t, "chart-repo", source.Name)
+}
+
+func TestApplicationConstraint(t *testing.T) {
+	cfg := &config.Config{
+		VersionConstraint: "major",
+		Constraints:       map[string]string{"frontend": "minor", "redis": ">=17.0.0 <18.0.0"},
+	}
+	app := func(name string, annotations map[string]string) *v1alpha1.Application {
+		return &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	}
+
+	tests := []struct {
+		name        string
+		app         *v1alpha1.Application
+		chart       string
+		expected    string
+		expectedErr string
+	}{
+		{name: "global", app: app("api", nil), chart: "nginx", expected: "major"},
+		{name: "by application name", app: app("Frontend", nil), chart: "redis", expected: "minor"},
+		{name: "by chart name", app: app("cache", nil), chart: "redis", expected: ">=17.0.0 <18.0.0"},
+		{name: "annotation wins", app: app("frontend", map[string]string{"argazer.io/constraint": " ~1.4.x "}), chart: "redis", expected: "~1.4.x"},
+		{name: "invalid annotation", app: app("api", map[string]string{"argazer.io/constraint": "soon"}), chart: "nginx", expected: "soon", expectedErr: "invalid argazer.io/constraint annotation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			constraint, err := applicationConstraint(tt.app, tt.chart, cfg)
+			assert.Equal(t, tt.expected, constraint)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
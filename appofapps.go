@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+)
+
+// includeChildApplications adds the child Applications of app-of-apps applications to apps, following
+// nested app-of-apps up to maxDepth levels
+// Children are included whether or not they match the scan filters, as the parent matched them; each
+// application is included once, so cycles end. Parents whose children can't be read are logged and skipped.
+func includeChildApplications(ctx context.Context, apps []*v1alpha1.Application, childrenOf func(context.Context, *v1alpha1.Application) ([]*v1alpha1.Application, error), maxDepth int, logger *logrus.Entry) []*v1alpha1.Application {
+	seen := make(map[string]bool, len(apps))
+	for _, app := range apps {
+		seen[applicationKey(app)] = true
+	}
+
+	parents := apps
+	for depth := 1; depth <= maxDepth && len(parents) > 0; depth++ {
+		var found []*v1alpha1.Application
+		for _, parent := range parents {
+			children, err := childrenOf(ctx, parent)
+			if err != nil {
+				logger.WithError(err).WithField("app_name", parent.Name).Warn("Failed to discover child applications")
+				continue
+			}
+			for _, child := range children {
+				key := applicationKey(child)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				found = append(found, child)
+				logger.WithFields(logrus.Fields{
+					"app_name": child.Name,
+					"parent":   parent.Name,
+					"depth":    depth,
+				}).Debug("Discovered child application")
+			}
+		}
+		apps = append(apps, found...)
+		parents = found
+	}
+	return apps
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIncludeChildApplications(t *testing.T) {
+	app := func(name string) *v1alpha1.Application {
+		return &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "argocd"}}
+	}
+	// root -> platform -> (ingress, monitoring -> alerts), platform also lists root (cycle)
+	tree := map[string][]string{
+		"root":       {"platform", "web"},
+		"platform":   {"ingress", "monitoring", "root"},
+		"monitoring": {"alerts"},
+	}
+	childrenOf := func(ctx context.Context, parent *v1alpha1.Application) ([]*v1alpha1.Application, error) {
+		if parent.Name == "web" {
+			return nil, errors.New("permission denied")
+		}
+		var children []*v1alpha1.Application
+		for _, name := range tree[parent.Name] {
+			children = append(children, app(name))
+		}
+		return children, nil
+	}
+	names := func(apps []*v1alpha1.Application) []string {
+		var result []string
+		for _, a := range apps {
+			result = append(result, a.Name)
+		}
+		return result
+	}
+	logger := logrus.NewEntry(logrus.New())
+
+	apps := includeChildApplications(context.Background(), []*v1alpha1.Application{app("root"), app("web")}, childrenOf, 3, logger)
+	assert.Equal(t, []string{"root", "web", "platform", "ingress", "monitoring", "alerts"}, names(apps))
+
+	apps = includeChildApplications(context.Background(), []*v1alpha1.Application{app("root")}, childrenOf, 1, logger)
+	assert.Equal(t, []string{"root", "platform", "web"}, names(apps), "nested children beyond the depth are left out")
+}
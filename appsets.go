@@ -0,0 +1,106 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"argazer/internal/i18n"
+)
+
+// applicationSetSummary aggregates the applications one ApplicationSet generates for a chart,
+// so an outdated template shows up once instead of as many identical rows
+type applicationSetSummary struct {
+	ApplicationSet string   `json:"application_set"`
+	ChartName      string   `json:"chart_name"`
+	Apps           int      `json:"apps"`
+	Outdated       int      `json:"outdated"`
+	Versions       []string `json:"versions"`                 // Distinct current versions, oldest first
+	LatestVersion  string   `json:"latest_version,omitempty"` // Newest latest version among the applications
+}
+
+// summarizeApplicationSets groups checked applications by ApplicationSet and chart
+// Applications without an ApplicationSet and failed checks are left out.
+func summarizeApplicationSets(results []ApplicationCheckResult) []applicationSetSummary {
+	type key struct{ appSet, chart string }
+
+	var keys []key
+	summaries := make(map[key]*applicationSetSummary)
+	for _, result := range results {
+		if result.AppName == "" || result.ApplicationSet == "" || result.Error != "" {
+			continue
+		}
+
+		k := key{result.ApplicationSet, result.ChartName}
+		summary, ok := summaries[k]
+		if !ok {
+			summary = &applicationSetSummary{ApplicationSet: result.ApplicationSet, ChartName: result.ChartName}
+			summaries[k] = summary
+			keys = append(keys, k)
+		}
+
+		summary.Apps++
+		if result.HasUpdate {
+			summary.Outdated++
+		}
+		if !slices.Contains(summary.Versions, result.CurrentVersion) {
+			summary.Versions = append(summary.Versions, result.CurrentVersion)
+		}
+		if compareVersions(result.LatestVersion, summary.LatestVersion) > 0 {
+			summary.LatestVersion = result.LatestVersion
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].appSet != keys[j].appSet {
+			return keys[i].appSet < keys[j].appSet
+		}
+		return keys[i].chart < keys[j].chart
+	})
+
+	result := make([]applicationSetSummary, 0, len(keys))
+	for _, k := range keys {
+		summary := summaries[k]
+		sort.Slice(summary.Versions, func(i, j int) bool {
+			return compareVersions(summary.Versions[i], summary.Versions[j]) < 0
+		})
+		result = append(result, *summary)
+	}
+	return result
+}
+
+// compareVersions compares two versions semantically, falling back to string order for non-semver values
+func compareVersions(a, b string) int {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA == nil && errB == nil {
+		return va.Compare(vb)
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// outdatedApplicationSets returns the summaries with at least one outdated application
+func outdatedApplicationSets(summaries []applicationSetSummary) []applicationSetSummary {
+	var outdated []applicationSetSummary
+	for _, summary := range summaries {
+		if summary.Outdated > 0 {
+			outdated = append(outdated, summary)
+		}
+	}
+	return outdated
+}
+
+// formatApplicationSetSummary renders a summary as a sentence such as
+// "chart nginx used by 14 apps, 12 outdated (versions: 1.0.0, 1.1.0; latest: 1.4.0)"
+func formatApplicationSetSummary(summary applicationSetSummary, tr *i18n.Localizer) string {
+	return tr.T(i18n.AppSetSummary, summary.ChartName, summary.Apps, summary.Outdated, strings.Join(summary.Versions, ", "), summary.LatestVersion)
+}
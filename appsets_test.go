@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeApplicationSets(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "nginx-eu", ApplicationSet: "nginx", ChartName: "nginx", CurrentVersion: "1.10.0", LatestVersion: "1.12.0", HasUpdate: true},
+		{AppName: "nginx-us", ApplicationSet: "nginx", ChartName: "nginx", CurrentVersion: "1.9.0", LatestVersion: "1.12.0", HasUpdate: true},
+		{AppName: "nginx-ap", ApplicationSet: "nginx", ChartName: "nginx", CurrentVersion: "1.12.0", LatestVersion: "1.12.0"},
+		{AppName: "nginx-sa", ApplicationSet: "nginx", ChartName: "nginx", CurrentVersion: "1.10.0", LatestVersion: "1.12.0", HasUpdate: true},
+		{AppName: "nginx-broken", ApplicationSet: "nginx", ChartName: "nginx", Error: "repository unreachable"},
+		{AppName: "redis", ApplicationSet: "cache", ChartName: "redis", CurrentVersion: "18.0.0", LatestVersion: "18.0.0"},
+		{AppName: "standalone", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.12.0", HasUpdate: true},
+	}
+
+	summaries := summarizeApplicationSets(results)
+	require.Len(t, summaries, 2)
+
+	assert.Equal(t, applicationSetSummary{ApplicationSet: "cache", ChartName: "redis", Apps: 1, Outdated: 0, Versions: []string{"18.0.0"}, LatestVersion: "18.0.0"}, summaries[0])
+	assert.Equal(t, applicationSetSummary{ApplicationSet: "nginx", ChartName: "nginx", Apps: 4, Outdated: 3, Versions: []string{"1.9.0", "1.10.0", "1.12.0"}, LatestVersion: "1.12.0"}, summaries[1])
+
+	outdated := outdatedApplicationSets(summaries)
+	require.Len(t, outdated, 1)
+	assert.Equal(t, "nginx", outdated[0].ApplicationSet)
+}
+
+func TestOutputResults_ApplicationSets(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "nginx-eu", ApplicationSet: "nginx", ChartName: "nginx", CurrentVersion: "1.10.0", LatestVersion: "1.12.0", HasUpdate: true},
+		{AppName: "nginx-us", ApplicationSet: "nginx", ChartName: "nginx", CurrentVersion: "1.9.0", LatestVersion: "1.12.0", HasUpdate: true},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Contains(t, table.String(), "UPDATES BY APPLICATIONSET:")
+	assert.Contains(t, table.String(), "ApplicationSet: nginx\n  chart nginx used by 2 apps, 2 outdated (versions: 1.9.0, 1.10.0; latest: 1.12.0)")
+
+	var markdown bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &markdown))
+	assert.Contains(t, markdown.String(), "## Updates by ApplicationSet")
+	assert.Contains(t, markdown.String(), "- **nginx:** chart nginx used by 2 apps, 2 outdated")
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, outputResults(results, "json", nil, &jsonOut))
+	assert.Contains(t, jsonOut.String(), `"application_sets"`)
+	assert.Contains(t, jsonOut.String(), `"outdated": 2`)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"argazer/internal/config"
+	"argazer/internal/helm"
+)
+
+// benchTarget is a repository whose version listing is benchmarked
+type benchTarget struct {
+	RepoURL string `json:"repo_url"`
+	Chart   string `json:"chart,omitempty"` // Only OCI listings are per chart
+	Apps    int    `json:"apps,omitempty"`  // Applications using the repository (discovered targets only)
+}
+
+// benchStats summarizes the probes of one repository
+type benchStats struct {
+	benchTarget
+	Kind       string        `json:"kind"`
+	Iterations int           `json:"iterations"`
+	Errors     int           `json:"errors"`
+	P50        time.Duration `json:"p50_ns"`
+	P95        time.Duration `json:"p95_ns"`
+	Bytes      int64         `json:"bytes"`           // Payload size of the last successful probe
+	LastError  string        `json:"error,omitempty"` // Error of the last failed probe
+}
+
+// probeFunc fetches the version listing of a repository once
+type probeFunc func(ctx context.Context, repoURL, chartName string) (helm.ProbeResult, error)
+
+// newBenchCmd creates the bench command
+func newBenchCmd() *cobra.Command {
+	benchCmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure repository latency and payload size",
+		Long: `Bench fetches the version listing of each repository several times (index.yaml for
+Helm repositories, the tags list for OCI registries, ls-remote for Git repositories) and
+reports p50/p95 latency and payload size, to help tune concurrency, timeouts and mirrors.
+Repositories are discovered from the ArgoCD applications matching the filters, unless
+given with --repo. They are measured one at a time, so results aren't skewed by each other.`,
+		RunE: runBench,
+	}
+
+	benchCmd.Flags().Int("iterations", 5, "Number of times each repository is fetched")
+	benchCmd.Flags().StringSlice("repo", nil, "Repositories to measure instead of discovering them (comma-separated); OCI repositories include the chart, e.g. ghcr.io/org/charts/nginx")
+
+	return benchCmd
+}
+
+// runBench measures the repositories and prints the results
+func runBench(cmd *cobra.Command, args []string) error {
+	iterations, err := cmd.Flags().GetInt("iterations")
+	if err != nil {
+		return err
+	}
+	if iterations <= 0 {
+		return fmt.Errorf("iterations must be positive, got %d", iterations)
+	}
+	repos, err := cmd.Flags().GetStringSlice("repo")
+	if err != nil {
+		return err
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Setup logging
+	logger, err := setupConfiguredLogging(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Set up context with signal handling for graceful shutdown
+	ctx, cancel := setupSignalHandler(logger)
+	defer cancel()
+
+	// Initialize clients
+	clients, err := initializeClients(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	targets := parseBenchRepos(repos)
+	if len(targets) == 0 {
+		var apps []*v1alpha1.Application
+		err := withTimeout(ctx, cfg.ArgocdTimeout, func(ctx context.Context) error {
+			var err error
+			apps, _, err = fetchApplications(ctx, clients, cfg, logger)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		targets = benchTargets(apps, cfg.SourceName, logger)
+	}
+	if len(targets) == 0 {
+		logger.Info("No Helm repositories to benchmark")
+		return nil
+	}
+
+	logger.WithFields(logrus.Fields{
+		"repositories": len(targets),
+		"iterations":   iterations,
+	}).Info("Benchmarking repositories")
+
+	stats := runBenchmark(ctx, clients.helm.Probe, targets, iterations)
+	return renderBench(stats, cfg.OutputFormat, os.Stdout)
+}
+
+// parseBenchRepos turns --repo values into targets
+// OCI references end with the chart name, since OCI registries list tags per chart.
+func parseBenchRepos(repos []string) []benchTarget {
+	var targets []benchTarget
+	for _, repo := range repos {
+		repo = strings.TrimRight(strings.TrimSpace(repo), "/")
+		if repo == "" {
+			continue
+		}
+		target := benchTarget{RepoURL: repo}
+		if helm.RepositoryKind(repo) == helm.RepositoryKindOCI {
+			if i := strings.LastIndex(repo, "/"); i > 0 {
+				target.RepoURL, target.Chart = repo[:i], repo[i+1:]
+			}
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// benchTargets collects the distinct repositories of Helm-based applications
+// Helm and Git repositories are fetched whole, so each is measured once whatever charts it serves.
+func benchTargets(apps []*v1alpha1.Application, sourceName string, logger *logrus.Entry) []benchTarget {
+	byKey := make(map[string]*benchTarget)
+	for _, app := range apps {
+		source := findHelmSource(app, sourceName, logger.WithField("app_name", app.Name))
+		if source == nil {
+			continue
+		}
+
+		target := benchTarget{RepoURL: source.RepoURL}
+		key := source.RepoURL
+		if helm.RepositoryKind(source.RepoURL) == helm.RepositoryKindOCI {
+			target.Chart = source.Chart
+			key += "/" + source.Chart
+		}
+		if existing, ok := byKey[key]; ok {
+			existing.Apps++
+			continue
+		}
+		target.Apps = 1
+		byKey[key] = &target
+	}
+
+	targets := make([]benchTarget, 0, len(byKey))
+	for _, target := range byKey {
+		targets = append(targets, *target)
+	}
+	slices.SortFunc(targets, func(a, b benchTarget) int {
+		return cmp.Or(cmp.Compare(a.RepoURL, b.RepoURL), cmp.Compare(a.Chart, b.Chart))
+	})
+	return targets
+}
+
+// runBenchmark probes each target the given number of times, one target after the other
+func runBenchmark(ctx context.Context, probe probeFunc, targets []benchTarget, iterations int) []benchStats {
+	stats := make([]benchStats, 0, len(targets))
+	for _, target := range targets {
+		s := benchStats{benchTarget: target, Kind: helm.RepositoryKind(target.RepoURL), Iterations: iterations}
+
+		var latencies []time.Duration
+		for range iterations {
+			if ctx.Err() != nil {
+				break
+			}
+			result, err := probe(ctx, target.RepoURL, target.Chart)
+			if err != nil {
+				s.Errors++
+				s.LastError = err.Error()
+				continue
+			}
+			latencies = append(latencies, result.Latency)
+			s.Bytes = result.Bytes
+		}
+
+		slices.Sort(latencies)
+		s.P50 = percentile(latencies, 50)
+		s.P95 = percentile(latencies, 95)
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted durations, or 0 if there are none
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// renderBench writes the benchmark results as JSON or as a table
+func renderBench(stats []benchStats, format string, w io.Writer) error {
+	if format == config.OutputFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(stats); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPOSITORY\tKIND\tAPPS\tP50\tP95\tSIZE\tERRORS")
+	for _, s := range stats {
+		repo := s.RepoURL
+		if s.Chart != "" {
+			repo += "/" + s.Chart
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%d/%d\n", repo, s.Kind, s.Apps,
+			s.P50.Round(time.Millisecond), s.P95.Round(time.Millisecond), formatBytes(s.Bytes), s.Errors, s.Iterations)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+
+	for _, s := range stats {
+		if s.LastError != "" {
+			fmt.Fprintf(w, "\n%s: %s", s.RepoURL, s.LastError)
+		}
+	}
+	if slices.ContainsFunc(stats, func(s benchStats) bool { return s.LastError != "" }) {
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// formatBytes formats a payload size with a binary unit, e.g. "1.5 MiB"
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
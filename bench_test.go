@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"argazer/internal/helm"
+)
+
+func TestParseBenchRepos(t *testing.T) {
+	targets := parseBenchRepos([]string{"https://charts.example.com/", " ghcr.io/org/charts/nginx ", "", "https://github.com/org/charts.git"})
+	assert.Equal(t, []benchTarget{
+		{RepoURL: "https://charts.example.com"},
+		{RepoURL: "ghcr.io/org/charts", Chart: "nginx"},
+		{RepoURL: "https://github.com/org/charts.git"},
+	}, targets)
+}
+
+func TestBenchTargets(t *testing.T) {
+	app := func(name, repoURL, chart string) *v1alpha1.Application {
+		return &v1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.ApplicationSpec{
+				Source: &v1alpha1.ApplicationSource{RepoURL: repoURL, Chart: chart, TargetRevision: "1.0.0"},
+			},
+		}
+	}
+	apps := []*v1alpha1.Application{
+		app("nginx", "https://charts.example.com", "nginx"),
+		app("redis", "https://charts.example.com", "redis"),
+		app("api", "ghcr.io/org/charts", "api"),
+		app("worker", "ghcr.io/org/charts", "worker"),
+		app("manifests", "https://github.com/org/manifests", ""),
+	}
+
+	targets := benchTargets(apps, "", logrus.NewEntry(logrus.New()))
+
+	// One index.yaml per Helm repository, one tags list per OCI chart, non-Helm applications left out
+	assert.Equal(t, []benchTarget{
+		{RepoURL: "ghcr.io/org/charts", Chart: "api", Apps: 1},
+		{RepoURL: "ghcr.io/org/charts", Chart: "worker", Apps: 1},
+		{RepoURL: "https://charts.example.com", Apps: 2},
+	}, targets)
+}
+
+func TestRunBenchmark(t *testing.T) {
+	latencies := map[string][]time.Duration{
+		"https://charts.example.com": {30 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond, 100 * time.Millisecond},
+	}
+	calls := map[string]int{}
+	probe := func(ctx context.Context, repoURL, chartName string) (helm.ProbeResult, error) {
+		i := calls[repoURL]
+		calls[repoURL]++
+		if repoURL == "https://down.example.com" {
+			return helm.ProbeResult{}, errors.New("connection refused")
+		}
+		return helm.ProbeResult{Kind: helm.RepositoryKindHelm, Latency: latencies[repoURL][i], Bytes: 2048}, nil
+	}
+
+	stats := runBenchmark(context.Background(), probe, []benchTarget{
+		{RepoURL: "https://charts.example.com", Apps: 3},
+		{RepoURL: "https://down.example.com"},
+	}, 4)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, helm.RepositoryKindHelm, stats[0].Kind)
+	assert.Equal(t, 20*time.Millisecond, stats[0].P50)
+	assert.Equal(t, 100*time.Millisecond, stats[0].P95)
+	assert.Equal(t, int64(2048), stats[0].Bytes)
+	assert.Zero(t, stats[0].Errors)
+
+	assert.Equal(t, 4, stats[1].Errors)
+	assert.Equal(t, "connection refused", stats[1].LastError)
+	assert.Zero(t, stats[1].P50)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderBench(stats, "table", &buf))
+	assert.Contains(t, buf.String(), "https://charts.example.com")
+	assert.Contains(t, buf.String(), "2.0 KiB")
+	assert.Contains(t, buf.String(), "4/4")
+	assert.Contains(t, buf.String(), "https://down.example.com: connection refused")
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, time.Duration(5), percentile(sorted, 50))
+	assert.Equal(t, time.Duration(10), percentile(sorted, 95))
+	assert.Equal(t, time.Duration(7), percentile([]time.Duration{7}, 95))
+	assert.Zero(t, percentile(nil, 50))
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "3.0 MiB", formatBytes(3*1024*1024))
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/config"
+	"argazer/internal/i18n"
+	"argazer/internal/server"
+)
+
+// chatOpsPath is where serve mode receives slash commands
+const chatOpsPath = "/chatops"
+
+// chatOpsMaxUpdates is the number of updates listed in a chatops reply, the rest are counted
+const chatOpsMaxUpdates = 15
+
+// chatOpsScan returns the scan run for chatops commands
+// On-demand scans only reply with their summary: they don't notify, record history or replace the
+// results served by /metrics and the dashboard.
+func chatOpsScan(cfg *config.Config, clients *clients, logger *logrus.Entry) server.ChatOpsScan {
+	return func(ctx context.Context, filters server.ChatOpsFilters) (string, error) {
+		scanCfg := *cfg
+		if len(filters.Projects) > 0 {
+			scanCfg.Projects = filters.Projects
+		}
+		if len(filters.AppNames) > 0 {
+			scanCfg.AppNames = filters.AppNames
+		}
+
+		if cfg.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+			defer cancel()
+		}
+
+		results, _, err := scan(ctx, &scanCfg, clients, nil, logger.WithField("filters", filters.String()))
+		if err != nil {
+			return "", err
+		}
+		if cfg.Redact {
+			results = redactResults(results)
+		}
+		return chatOpsSummary(results, filters, i18n.New(cfg.Language)), nil
+	}
+}
+
+// chatOpsSummary describes a scan in Slack mrkdwn: the totals and the available updates
+func chatOpsSummary(results []ApplicationCheckResult, filters server.ChatOpsFilters, tr *i18n.Localizer) string {
+	lines := []string{
+		fmt.Sprintf("*Argazer scan of %s*", filters),
+		notificationSummary(results, tr),
+	}
+
+	updates := processResults(results).updatesAvailable
+	for i, result := range updates {
+		if i == chatOpsMaxUpdates {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(updates)-chatOpsMaxUpdates))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("• %s: %s %s → %s", resultDisplayName(result), result.ChartName, result.CurrentVersion, result.LatestVersion))
+	}
+	return strings.Join(lines, "\n")
+}
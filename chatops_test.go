@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"argazer/internal/i18n"
+	"argazer/internal/server"
+)
+
+func TestChatOpsSummary(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "api", Namespace: "argocd", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		{AppName: "web", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+	}
+	summary := chatOpsSummary(results, server.ChatOpsFilters{Projects: []string{"payments"}}, i18n.New("en"))
+	assert.Equal(t, "*Argazer scan of project=payments*\n"+
+		"Total applications checked: 2 · Up to date: 1 · Updates available: 1\n"+
+		"• argocd/api: nginx 1.0.0 → 2.0.0", summary)
+
+	results = nil
+	for i := 0; i < chatOpsMaxUpdates+3; i++ {
+		results = append(results, ApplicationCheckResult{AppName: fmt.Sprintf("app-%02d", i), ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true})
+	}
+	lines := strings.Split(chatOpsSummary(results, server.ChatOpsFilters{}, i18n.New("en")), "\n")
+	assert.Equal(t, "*Argazer scan of all applications*", lines[0])
+	assert.Len(t, lines, 2+chatOpsMaxUpdates+1)
+	assert.Equal(t, "…and 3 more", lines[len(lines)-1])
+}
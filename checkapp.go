@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"argazer/internal/config"
+	"argazer/internal/helm"
+	"argazer/internal/i18n"
+)
+
+// newCheckAppCmd creates the check-app command
+func newCheckAppCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check-app <name>",
+		Short: "Check one application for chart updates",
+		Long: `Check-app checks a single ArgoCD application for a newer chart version, with the same
+settings as a full scan (version constraints, ignore rules, image and dependency checks), and prints
+the report in the configured output format. Nothing is notified or recorded in the history.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runCheckApp,
+	}
+}
+
+// newCheckChartCmd creates the check-chart command
+func newCheckChartCmd() *cobra.Command {
+	checkChartCmd := &cobra.Command{
+		Use:   "check-chart",
+		Short: "Check an arbitrary chart version for updates",
+		Long: `Check-chart looks up the newer versions of a chart in a Helm repository, OCI registry or Git
+repository, as if an application deployed it, without ArgoCD. Repository credentials, the version
+constraint and the other lookup settings of the configuration apply.`,
+		Example: `  argazer check-chart --repo https://charts.bitnami.com/bitnami --chart nginx --version 15.0.0
+  argazer check-chart --repo ghcr.io/myorg/charts --chart backend --version 1.2.0 --version-constraint minor
+  argazer check-chart --repo https://github.com/myorg/charts.git --chart charts/api --version v1.4.0`,
+		Args: cobra.NoArgs,
+		RunE: runCheckChart,
+	}
+
+	checkChartCmd.Flags().String("repo", "", "Repository URL: a Helm repository, an OCI registry or a Git repository")
+	checkChartCmd.Flags().String("chart", "", "Chart name, or the chart's path in a Git repository")
+	checkChartCmd.Flags().String("version", "", "Current chart version, or the Git revision")
+	_ = checkChartCmd.MarkFlagRequired("repo")
+	_ = checkChartCmd.MarkFlagRequired("chart")
+	_ = checkChartCmd.MarkFlagRequired("version")
+
+	return checkChartCmd
+}
+
+// runCheckApp scans the application with the given name and prints its report
+func runCheckApp(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Setup logging
+	logger, err := setupConfiguredLogging(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Set up context with signal handling for graceful shutdown
+	ctx, cancel := setupSignalHandler(logger)
+	defer cancel()
+	if cfg.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancelTimeout()
+	}
+
+	// Initialize clients
+	clients, err := initializeClients(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	// The application is found like in a scan limited to its name
+	appCfg := *cfg
+	appCfg.AppNames = []string{args[0]}
+	appCfg.MaxApps = 0
+
+	results, _, err := scan(ctx, &appCfg, clients, nil, logger.WithField("app_name", args[0]))
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("application %s not found (or excluded by the filters)", args[0])
+	}
+	if !slices.ContainsFunc(results, func(result ApplicationCheckResult) bool { return result.AppName != "" }) {
+		return fmt.Errorf("application %s doesn't deploy a Helm chart", args[0])
+	}
+
+	return renderCheckResults(cmd, cfg, results, logger)
+}
+
+// runCheckChart checks a chart version as if an application deployed it
+func runCheckChart(cmd *cobra.Command, args []string) error {
+	repoURL, _ := cmd.Flags().GetString("repo")
+	chart, _ := cmd.Flags().GetString("chart")
+	version, _ := cmd.Flags().GetString("version")
+
+	// ArgoCD isn't contacted, so its connection settings aren't needed
+	cfg, err := config.LoadWithoutArgocd()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Setup logging
+	logger, err := setupConfiguredLogging(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Set up context with signal handling for graceful shutdown
+	ctx, cancel := setupSignalHandler(logger)
+	defer cancel()
+	if cfg.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancelTimeout()
+	}
+
+	authProvider, err := newAuthProvider(cfg, logger)
+	if err != nil {
+		return err
+	}
+	helmChecker, err := newHelmChecker(cfg, authProvider, logger)
+	if err != nil {
+		return err
+	}
+	defer helmChecker.ReleaseClones()
+
+	result := checkApplication(ctx, chartApplication(repoURL, chart, version), helmChecker, cfg, logger)
+	applyIgnoreRules([]ApplicationCheckResult{result}, cfg.Ignore, time.Now(), logger)
+	return renderCheckResults(cmd, cfg, []ApplicationCheckResult{result}, logger)
+}
+
+// chartApplication returns an application deploying the chart, for checkApplication
+// Charts of Git repositories are found by their path, as in Git-based applications.
+func chartApplication(repoURL, chart, version string) *v1alpha1.Application {
+	source := &v1alpha1.ApplicationSource{RepoURL: repoURL, Chart: chart, TargetRevision: version}
+	if helm.RepositoryKind(repoURL) == helm.RepositoryKindGit {
+		source.Chart = ""
+		source.Path = chart
+		source.Helm = &v1alpha1.ApplicationSourceHelm{}
+	}
+	return &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: chart},
+		Spec:       v1alpha1.ApplicationSpec{Source: source},
+	}
+}
+
+// renderCheckResults prints the report of an ad-hoc check and returns its exit code like a run
+func renderCheckResults(cmd *cobra.Command, cfg *config.Config, results []ApplicationCheckResult, logger *logrus.Entry) error {
+	reportResults := results
+	if cfg.Redact {
+		reportResults = redactResults(results)
+	}
+	report := processResults(reportResults)
+	report.layout = reportLayoutFromConfig(cfg)
+	if err := renderResults(report, cfg.OutputFormat, i18n.New(cfg.Language), os.Stdout); err != nil {
+		return fmt.Errorf("failed to output results: %w", err)
+	}
+	if err := writeOutputFiles(report, cfg.OutputFiles, i18n.New(cfg.Language), logger); err != nil {
+		return err
+	}
+	return exitCodeResult(cmd, outcomeExitCode(results, cfg.ExitCodeMode, cfg.FailOn))
+}
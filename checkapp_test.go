@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartApplication(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	app := chartApplication("https://charts.example.com", "nginx", "15.0.0")
+	assert.Equal(t, "nginx", app.Name)
+	source := findHelmSource(app, "", logger)
+	require.NotNil(t, source)
+	assert.Equal(t, "https://charts.example.com", source.RepoURL)
+	assert.Equal(t, "nginx", source.Chart)
+	assert.Equal(t, "15.0.0", source.TargetRevision)
+
+	// Git-based charts are found by their path
+	app = chartApplication("https://github.com/org/charts.git", "charts/api", "v1.4.0")
+	source = findHelmSource(app, "", logger)
+	require.NotNil(t, source)
+	assert.Empty(t, source.Chart)
+	assert.Equal(t, "charts/api", source.Path)
+	assert.Equal(t, "v1.4.0", source.TargetRevision)
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/spf13/cobra"
+
+	"argazer/internal/config"
+	"argazer/internal/helm"
+)
+
+// Outcomes of a connection check
+const (
+	connectionOK      = "ok"
+	connectionFailed  = "failed"
+	connectionSkipped = "skipped"
+)
+
+// connectionCheck is the outcome of one connection check
+type connectionCheck struct {
+	Check   string `json:"check"`  // "argocd", "repository" or "notification"
+	Target  string `json:"target"` // ArgoCD server, repository URL or notification channel
+	Status  string `json:"status"` // connectionOK, connectionFailed or connectionSkipped
+	Details string `json:"details,omitempty"`
+}
+
+// newCheckConnectionCmd creates the check-connection command
+func newCheckConnectionCmd() *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check-connection",
+		Short: "Verify the connections to ArgoCD, repositories and the notification channel",
+		Long: `Check-connection verifies every connection a scan needs, instead of finding misconfigurations
+mid-scan, and prints a pass/fail table:
+
+- ArgoCD: lists the applications matching the filters with each client, the username/password
+  account and every project token, of every instance
+- repositories: fetches the version listing of each repository with configured credentials
+  (repository_auth and repositories), index.yaml for Helm repositories, the tags list for OCI
+  registries and ls-remote for Git repositories. The repository of an application using the
+  credentials is listed, since OCI registries only list tags per chart; without one, only Helm
+  repositories are checked
+- notification channel: sends a test message, unless --notify=false
+
+It exits non-zero when a check fails.`,
+		Args: cobra.NoArgs,
+		RunE: runCheckConnection,
+	}
+
+	checkCmd.Flags().Bool("notify", true, "Send a test message to the notification channel")
+
+	return checkCmd
+}
+
+// runCheckConnection runs the connection checks and prints their outcome
+func runCheckConnection(cmd *cobra.Command, args []string) error {
+	notify, err := cmd.Flags().GetBool("notify")
+	if err != nil {
+		return err
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Setup logging
+	logger, err := setupConfiguredLogging(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Set up context with signal handling for graceful shutdown
+	ctx, cancel := setupSignalHandler(logger)
+	defer cancel()
+
+	// Initialize clients
+	clients, err := initializeClients(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	checks, apps := checkArgocdConnections(ctx, clients.instances, cfg.ArgocdTimeout)
+	targets := benchTargets(apps, cfg.SourceName, logger)
+	checks = append(checks, checkRepositoryConnections(ctx, clients.helm.Probe, connectionRepositories(cfg), targets)...)
+	checks = append(checks, checkNotificationConnection(ctx, cfg, clients, notify))
+
+	if err := renderConnectionChecks(checks, cfg.OutputFormat, os.Stdout); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if check.Status == connectionFailed {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+
+	// The failures were printed, usage wouldn't help
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return fmt.Errorf("%d connection check(s) failed", failed)
+}
+
+// checkArgocdConnections lists the applications with each ArgoCD client and returns the applications
+// found, whose repositories are then probed
+func checkArgocdConnections(ctx context.Context, instances []*argocdInstance, timeout time.Duration) ([]connectionCheck, []*v1alpha1.Application) {
+	var checks []connectionCheck
+	var apps []*v1alpha1.Application
+	for _, instance := range instances {
+		target := instance.cfg.ArgocdURL
+		if instance.cfg.Mode == config.ModeKubernetes {
+			target = "in-cluster Application resources"
+		}
+		if instance.name != "" {
+			target = instance.name + " (" + target + ")"
+		}
+
+		scopes, uncovered := scanScopes(instance.cfg, instance.argocd != nil)
+		if len(uncovered) > 0 {
+			checks = append(checks, connectionCheck{
+				Check:   "argocd",
+				Target:  target,
+				Status:  connectionFailed,
+				Details: "no ArgoCD token configured for projects " + strings.Join(uncovered, ", "),
+			})
+		}
+
+		for _, scope := range scopes {
+			client := instance.argocd
+			check := connectionCheck{Check: "argocd", Target: target}
+			if scope.project != "" {
+				client = instance.projectArgocd[scope.project]
+				check.Target += " project " + scope.project
+			}
+
+			var scopeApps []*v1alpha1.Application
+			err := withTimeout(ctx, timeout, func(ctx context.Context) error {
+				var err error
+				scopeApps, err = listScopeApplications(ctx, client, scope)
+				return err
+			})
+			if err != nil {
+				check.Status, check.Details = connectionFailed, err.Error()
+			} else {
+				check.Status, check.Details = connectionOK, fmt.Sprintf("%d applications", len(scopeApps))
+				apps = append(apps, scopeApps...)
+			}
+			checks = append(checks, check)
+		}
+	}
+	return checks, apps
+}
+
+// connectionRepositories returns the URLs of the repositories with configured credentials, in order
+func connectionRepositories(cfg *config.Config) []config.Repository {
+	var repositories []config.Repository
+	seen := make(map[string]bool)
+	add := func(repository config.Repository) {
+		repository.URL = strings.TrimRight(repository.URL, "/")
+		if repository.URL == "" || seen[repository.URL] {
+			return
+		}
+		seen[repository.URL] = true
+		repositories = append(repositories, repository)
+	}
+
+	for _, ra := range cfg.RepositoryAuth {
+		add(config.Repository{URL: ra.URL})
+	}
+	for _, repository := range cfg.Repositories {
+		if repository.Username != "" || repository.Password != "" {
+			add(repository)
+		}
+	}
+	return repositories
+}
+
+// checkRepositoryConnections fetches the version listing of each repository once
+// A chart of an application using the repository is listed. Without one, Helm repositories are fetched
+// at the configured URL; OCI registries and Git hosts are skipped, their URL rarely names a chart.
+func checkRepositoryConnections(ctx context.Context, probe probeFunc, repositories []config.Repository, targets []benchTarget) []connectionCheck {
+	checks := make([]connectionCheck, 0, len(repositories))
+	for _, repository := range repositories {
+		check := connectionCheck{Check: "repository", Target: repository.URL}
+		if repository.Disabled {
+			check.Status, check.Details = connectionSkipped, "repository is disabled"
+			checks = append(checks, check)
+			continue
+		}
+
+		target, ok := repositoryProbeTarget(repository.URL, targets)
+		if !ok && helm.RepositoryKind(repository.URL) != helm.RepositoryKindHelm {
+			check.Status, check.Details = connectionSkipped, "no application uses the repository, so there is no chart to list"
+			checks = append(checks, check)
+			continue
+		}
+
+		result, err := probe(ctx, target.RepoURL, target.Chart)
+		switch {
+		case err != nil:
+			check.Status, check.Details = connectionFailed, err.Error()
+		case result.Kind == helm.RepositoryKindOCI:
+			check.Status, check.Details = connectionOK, fmt.Sprintf("listed tags of %s/%s in %s", target.RepoURL, target.Chart, result.Latency.Round(time.Millisecond))
+		case result.Kind == helm.RepositoryKindGit:
+			check.Status, check.Details = connectionOK, fmt.Sprintf("listed refs of %s in %s", target.RepoURL, result.Latency.Round(time.Millisecond))
+		default:
+			check.Status, check.Details = connectionOK, fmt.Sprintf("fetched index.yaml of %s (%s) in %s", target.RepoURL, formatBytes(result.Bytes), result.Latency.Round(time.Millisecond))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// repositoryProbeTarget picks the repository an application uses that the credentials of repoURL apply to:
+// one below repoURL, or else one on the same host
+// Without one, ok is false and the target is repoURL itself.
+func repositoryProbeTarget(repoURL string, targets []benchTarget) (target benchTarget, ok bool) {
+	prefix := normalizeRepoURL(repoURL)
+	host := urlHost(repoURL)
+
+	var sameHost *benchTarget
+	for i, candidate := range targets {
+		path := normalizeRepoURL(candidate.RepoURL)
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return candidate, true
+		}
+		if sameHost == nil && host != "" && urlHost(candidate.RepoURL) == host {
+			sameHost = &targets[i]
+		}
+	}
+	if sameHost != nil {
+		return *sameHost, true
+	}
+	return benchTarget{RepoURL: repoURL}, false
+}
+
+// checkNotificationConnection sends a test message to the notification channel
+func checkNotificationConnection(ctx context.Context, cfg *config.Config, clients *clients, notify bool) connectionCheck {
+	check := connectionCheck{Check: "notification", Target: cfg.NotificationChannel}
+	switch {
+	case clients.notifier == nil:
+		check.Target, check.Status, check.Details = "none", connectionSkipped, "no notification channel is configured"
+	case !notify:
+		check.Status, check.Details = connectionSkipped, "test message disabled by --notify=false"
+	default:
+		err := withTimeout(ctx, cfg.NotifyTimeout, func(ctx context.Context) error {
+			return clients.notifier.Send(ctx, "Argazer Connection Test", "This is a test message from argazer check-connection.\nIf you see this, your notification channel is working correctly!")
+		})
+		if err != nil {
+			check.Status, check.Details = connectionFailed, err.Error()
+		} else {
+			check.Status, check.Details = connectionOK, "test message sent"
+		}
+	}
+	return check
+}
+
+// renderConnectionChecks writes the checks as JSON or as a table
+func renderConnectionChecks(checks []connectionCheck, format string, w io.Writer) error {
+	if format == config.OutputFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(checks); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tTARGET\tSTATUS\tDETAILS")
+	for _, check := range checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", check.Check, check.Target, strings.ToUpper(check.Status), check.Details)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+
+	if !slices.ContainsFunc(checks, func(c connectionCheck) bool { return c.Status == connectionFailed }) {
+		fmt.Fprintln(w, "\nAll connections are working")
+	}
+	return nil
+}
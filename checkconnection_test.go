@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/config"
+	"argazer/internal/helm"
+)
+
+func TestConnectionRepositories(t *testing.T) {
+	cfg := &config.Config{
+		RepositoryAuth: []config.RepositoryAuth{
+			{URL: "https://charts.example.com/", Username: "reader", Password: "secret"},
+			{URL: "ghcr.io/org/charts", Username: "reader", Password: "secret"},
+		},
+		Repositories: []config.Repository{
+			{URL: "https://charts.example.com", Username: "reader", Password: "secret"},
+			{URL: "https://mirror.example.com", Timeout: time.Second},
+			{URL: "https://private.example.com", Username: "reader", Password: "secret", Disabled: true},
+		},
+	}
+
+	// Repositories without credentials aren't checked, duplicates are checked once
+	assert.Equal(t, []config.Repository{
+		{URL: "https://charts.example.com"},
+		{URL: "ghcr.io/org/charts"},
+		{URL: "https://private.example.com", Username: "reader", Password: "secret", Disabled: true},
+	}, connectionRepositories(cfg))
+}
+
+func TestRepositoryProbeTarget(t *testing.T) {
+	targets := []benchTarget{
+		{RepoURL: "ghcr.io/other/charts", Chart: "db"},
+		{RepoURL: "ghcr.io/org/charts", Chart: "api"},
+		{RepoURL: "https://charts.example.com/stable"},
+	}
+
+	target, ok := repositoryProbeTarget("oci://ghcr.io/org", targets)
+	assert.True(t, ok)
+	assert.Equal(t, benchTarget{RepoURL: "ghcr.io/org/charts", Chart: "api"}, target, "a repository below the URL is preferred")
+
+	target, ok = repositoryProbeTarget("ghcr.io", targets)
+	assert.True(t, ok)
+	assert.Equal(t, benchTarget{RepoURL: "ghcr.io/other/charts", Chart: "db"}, target)
+
+	target, ok = repositoryProbeTarget("https://charts.example.com", targets)
+	assert.True(t, ok)
+	assert.Equal(t, "https://charts.example.com/stable", target.RepoURL)
+
+	target, ok = repositoryProbeTarget("https://unused.example.com", targets)
+	assert.False(t, ok)
+	assert.Equal(t, benchTarget{RepoURL: "https://unused.example.com"}, target)
+}
+
+func TestCheckRepositoryConnections(t *testing.T) {
+	probe := func(ctx context.Context, repoURL, chartName string) (helm.ProbeResult, error) {
+		switch repoURL {
+		case "https://charts.example.com":
+			return helm.ProbeResult{Kind: helm.RepositoryKindHelm, Latency: 120 * time.Millisecond, Bytes: 2048}, nil
+		case "ghcr.io/org/charts":
+			return helm.ProbeResult{Kind: helm.RepositoryKindOCI, Latency: 80 * time.Millisecond}, nil
+		}
+		return helm.ProbeResult{}, errors.New("401 Unauthorized")
+	}
+	repositories := []config.Repository{
+		{URL: "https://charts.example.com"},
+		{URL: "ghcr.io/org"},
+		{URL: "registry.example.com/charts"},
+		{URL: "https://private.example.com"},
+		{URL: "https://disabled.example.com", Disabled: true},
+	}
+	targets := []benchTarget{{RepoURL: "ghcr.io/org/charts", Chart: "api"}}
+
+	checks := checkRepositoryConnections(context.Background(), probe, repositories, targets)
+	require.Len(t, checks, 5)
+	assert.Equal(t, connectionCheck{Check: "repository", Target: "https://charts.example.com", Status: connectionOK, Details: "fetched index.yaml of https://charts.example.com (2.0 KiB) in 120ms"}, checks[0])
+	assert.Equal(t, connectionCheck{Check: "repository", Target: "ghcr.io/org", Status: connectionOK, Details: "listed tags of ghcr.io/org/charts/api in 80ms"}, checks[1])
+	assert.Equal(t, connectionSkipped, checks[2].Status, "OCI registries without an application have no chart to list")
+	assert.Equal(t, connectionCheck{Check: "repository", Target: "https://private.example.com", Status: connectionFailed, Details: "401 Unauthorized"}, checks[3])
+	assert.Equal(t, connectionSkipped, checks[4].Status)
+}
+
+func TestRenderConnectionChecks(t *testing.T) {
+	checks := []connectionCheck{
+		{Check: "argocd", Target: "https://argocd.example.com", Status: connectionOK, Details: "12 applications"},
+		{Check: "notification", Target: "none", Status: connectionSkipped, Details: "no notification channel is configured"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderConnectionChecks(checks, config.OutputFormatTable, &buf))
+	assert.Contains(t, buf.String(), "CHECK")
+	assert.Regexp(t, `argocd\s+https://argocd.example.com\s+OK\s+12 applications`, buf.String())
+	assert.Contains(t, buf.String(), "All connections are working")
+
+	buf.Reset()
+	checks[0].Status = connectionFailed
+	require.NoError(t, renderConnectionChecks(checks, config.OutputFormatTable, &buf))
+	assert.NotContains(t, buf.String(), "All connections are working")
+
+	buf.Reset()
+	require.NoError(t, renderConnectionChecks(checks, config.OutputFormatJSON, &buf))
+	assert.Contains(t, buf.String(), `"status": "failed"`)
+}
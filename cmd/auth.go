@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"argazer/internal/keychain"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+)
+
+// NewAuthCmd creates the auth subcommand
+func NewAuthCmd() *cobra.Command {
+	authCmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage credentials stored in the OS keychain",
+		Long: `Store ArgoCD and repository credentials in the OS keychain (macOS Keychain,
+Windows Credential Manager, or the Secret Service on Linux) instead of config files.
+
+Reference a stored credential from the configuration with "keychain:<name>", e.g.:
+
+  argazer auth set argocd
+  argocd_password: keychain:argocd`,
+	}
+
+	authCmd.AddCommand(&cobra.Command{
+		Use:   "set <name>",
+		Short: "Store a credential (prompted for, or read from stdin)",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runAuthSet,
+	})
+	authCmd.AddCommand(&cobra.Command{
+		Use:   "get <name>",
+		Short: "Print a stored credential",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secret, err := keychain.Get(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), secret)
+			return nil
+		},
+	})
+	authCmd.AddCommand(&cobra.Command{
+		Use:   "delete <name>",
+		Short: "Remove a stored credential",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := keychain.Delete(args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted %q from the keychain\n", args[0])
+			return nil
+		},
+	})
+
+	return authCmd
+}
+
+// runAuthSet stores a credential read from a prompt, or from stdin when it isn't a terminal
+func runAuthSet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	var secret string
+	if isTerminal(os.Stdin) {
+		prompt := &survey.Password{Message: fmt.Sprintf("Secret for %q:", name)}
+		if err := survey.AskOne(prompt, &secret, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if secret, err = readSecret(cmd.InOrStdin()); err != nil {
+			return err
+		}
+	}
+
+	if err := keychain.Set(name, secret); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Stored %q in the keychain; reference it as %s%s\n", name, keychain.Prefix, name)
+	return nil
+}
+
+// readSecret reads the first line of piped input
+func readSecret(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read secret: %w", err)
+	}
+	secret := strings.TrimRight(line, "\r\n")
+	if secret == "" {
+		return "", errors.New("no secret given on stdin")
+	}
+	return secret, nil
+}
+
+// isTerminal reports whether the file is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
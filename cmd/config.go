@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+
+	"argazer/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// NewConfigCmd creates the config subcommand
+func NewConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the Argazer configuration",
+	}
+
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration for errors and unknown keys",
+		Long: `Validate loads the configuration like a run does (the file given with -c or found in the
+default locations, AG_* environment variables and flags) and reports every problem:
+
+- keys of the configuration file argazer doesn't know, e.g. a typo like argocd_pasword, which
+  would otherwise be silently ignored
+- values of the wrong type
+- invalid or incomplete settings, e.g. a notification channel missing its required settings
+
+It exits non-zero when it finds a problem, so it can check a configuration in CI before deploying it.
+Keychain references aren't resolved and nothing is contacted.`,
+		Args: cobra.NoArgs,
+		RunE: runConfigValidate,
+	})
+
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Print the effective configuration with secrets redacted",
+		Long: `Show prints the configuration a run would use as YAML: the defaults merged with the file given
+with -c or found in the default locations, AG_* environment variables and flags. It helps find out
+which source a setting comes from when a run doesn't behave as configured.
+
+Passwords, tokens, signing secrets, webhook URLs and headers, and passwords in URLs are redacted, so
+the output can be shared in an issue. Keychain references are shown as they are. The configuration
+isn't validated, see argazer config validate.`,
+		Args: cobra.NoArgs,
+		RunE: runConfigShow,
+	})
+
+	return configCmd
+}
+
+// runConfigValidate prints the problems of the configuration
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	file, problems, err := config.Check()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	source := file
+	if source == "" {
+		source = "environment variables and flags (no config file found)"
+	}
+
+	if len(problems) == 0 {
+		fmt.Fprintf(out, "Configuration is valid: %s\n", source)
+		return nil
+	}
+
+	fmt.Fprintf(out, "Configuration has %d problem(s): %s\n", len(problems), source)
+	for _, problem := range problems {
+		fmt.Fprintf(out, "  %s\n", problem)
+	}
+
+	// The problems were printed, usage wouldn't help
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return fmt.Errorf("configuration has %d problem(s)", len(problems))
+}
+
+// runConfigShow prints the effective configuration
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadUnvalidated()
+	if err != nil {
+		return err
+	}
+
+	out, err := config.Effective(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(out)
+	return err
+}
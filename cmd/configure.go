@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,9 @@ import (
 	"time"
 
 	"argazer/internal/config"
+	"argazer/internal/kafka"
+	"argazer/internal/keychain"
+	"argazer/internal/mqtt"
 	"argazer/internal/notification"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -73,7 +77,39 @@ type ConfigWizard struct {
 	SlackWebhook string
 
 	// Teams
-	TeamsWebhook string
+	TeamsWebhook    string
+	TeamsCardFormat string
+
+	// Discord
+	DiscordWebhook string
+
+	// Google Chat
+	GoogleChatWebhook string
+
+	// Opsgenie
+	OpsgenieAPIKey     string
+	OpsgenieAPIURL     string
+	OpsgenieResponders []string
+
+	// Webex
+	WebexBotToken string
+	WebexRoomID   string
+
+	// Kafka
+	KafkaBrokers       []string
+	KafkaTopic         string
+	KafkaTLS           bool
+	KafkaSASLMechanism string
+	KafkaUsername      string
+	KafkaPassword      string
+
+	// MQTT
+	MQTTBroker   string
+	MQTTTopic    string
+	MQTTQoS      int
+	MQTTRetain   bool
+	MQTTUsername string
+	MQTTPassword string
 
 	// Webhook
 	WebhookURL string
@@ -177,7 +213,36 @@ func configureArgoCD(wizard *ConfigWizard) error {
 		},
 	}
 
-	return survey.Ask(questions, wizard)
+	if err := survey.Ask(questions, wizard); err != nil {
+		return err
+	}
+
+	return storeArgoCDPassword(wizard)
+}
+
+// storeArgoCDPassword offers to keep the ArgoCD password in the OS keychain,
+// so config.yaml only holds a reference to it
+func storeArgoCDPassword(wizard *ConfigWizard) error {
+	var useKeychain bool
+	prompt := &survey.Confirm{
+		Message: "Store the password in the OS keychain instead of config.yaml?",
+		Default: true,
+	}
+	if err := survey.AskOne(prompt, &useKeychain); err != nil {
+		return err
+	}
+	if !useKeychain {
+		return nil
+	}
+
+	const name = "argocd"
+	if err := keychain.Set(name, wizard.ArgocdPassword); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		fmt.Println("The password will be saved in config.yaml instead.")
+		return nil
+	}
+	wizard.ArgocdPassword = keychain.Prefix + name
+	return nil
 }
 
 func configureFiltering(wizard *ConfigWizard) error {
@@ -243,7 +308,7 @@ func configureGeneral(wizard *ConfigWizard) error {
 			Name: "outputFormat",
 			Prompt: &survey.Select{
 				Message: "Default output format:",
-				Options: []string{"table", "json", "markdown"},
+				Options: []string{"table", "json", "markdown", "markdown-compact", "junit"},
 				Default: "table",
 			},
 		},
@@ -278,6 +343,12 @@ func configureNotifications(wizard *ConfigWizard) error {
 		"Email",
 		"Slack",
 		"Microsoft Teams",
+		"Discord",
+		"Google Chat",
+		"Webex",
+		"Opsgenie",
+		"Kafka",
+		"MQTT",
 		"Generic Webhook",
 	}
 
@@ -304,6 +375,24 @@ func configureNotifications(wizard *ConfigWizard) error {
 	case "Microsoft Teams":
 		wizard.NotificationChannel = "teams"
 		return configureTeams(wizard)
+	case "Discord":
+		wizard.NotificationChannel = "discord"
+		return configureDiscord(wizard)
+	case "Google Chat":
+		wizard.NotificationChannel = "googlechat"
+		return configureGoogleChat(wizard)
+	case "Webex":
+		wizard.NotificationChannel = "webex"
+		return configureWebex(wizard)
+	case "Opsgenie":
+		wizard.NotificationChannel = "opsgenie"
+		return configureOpsgenie(wizard)
+	case "Kafka":
+		wizard.NotificationChannel = "kafka"
+		return configureKafka(wizard)
+	case "MQTT":
+		wizard.NotificationChannel = "mqtt"
+		return configureMQTT(wizard)
 	case "Generic Webhook":
 		wizard.NotificationChannel = "webhook"
 		return configureWebhook(wizard)
@@ -422,7 +511,227 @@ func configureTeams(wizard *ConfigWizard) error {
 		Help:    "Format: https://outlook.office.com/webhook/YOUR/WEBHOOK/URL",
 	}
 
-	return survey.AskOne(question, &wizard.TeamsWebhook, survey.WithValidator(survey.Required))
+	if err := survey.AskOne(question, &wizard.TeamsWebhook, survey.WithValidator(survey.Required)); err != nil {
+		return err
+	}
+
+	formatQuestion := &survey.Select{
+		Message: "Card format:",
+		Options: []string{config.TeamsCardFormatMessageCard, config.TeamsCardFormatAdaptive},
+		Default: config.TeamsCardFormatMessageCard,
+		Help:    "Workflows webhooks (Power Automate) require Adaptive Cards; legacy connector webhooks accept both",
+	}
+	return survey.AskOne(formatQuestion, &wizard.TeamsCardFormat)
+}
+
+func configureDiscord(wizard *ConfigWizard) error {
+	question := &survey.Input{
+		Message: "Discord Webhook URL:",
+		Help:    "Format: https://discord.com/api/webhooks/WEBHOOK_ID/WEBHOOK_TOKEN",
+	}
+
+	return survey.AskOne(question, &wizard.DiscordWebhook, survey.WithValidator(survey.Required))
+}
+
+func configureGoogleChat(wizard *ConfigWizard) error {
+	question := &survey.Input{
+		Message: "Google Chat Webhook URL:",
+		Help:    "Format: https://chat.googleapis.com/v1/spaces/SPACE_ID/messages?key=KEY&token=TOKEN",
+	}
+
+	return survey.AskOne(question, &wizard.GoogleChatWebhook, survey.WithValidator(survey.Required))
+}
+
+func configureWebex(wizard *ConfigWizard) error {
+	questions := []*survey.Question{
+		{
+			Name: "webexBotToken",
+			Prompt: &survey.Password{
+				Message: "Webex Bot Access Token:",
+				Help:    "Create a bot at https://developer.webex.com/my-apps and copy its access token",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "webexRoomID",
+			Prompt: &survey.Input{
+				Message: "Webex Room ID:",
+				Help:    "The bot must be a member of the room. List room IDs via https://developer.webex.com/docs/api/v1/rooms/list-rooms",
+			},
+			Validate: survey.Required,
+		},
+	}
+
+	return survey.Ask(questions, wizard)
+}
+
+func configureOpsgenie(wizard *ConfigWizard) error {
+	prompt := &survey.Password{
+		Message: "Opsgenie API Key:",
+		Help:    "Add an API integration to your team in Opsgenie and copy its API key",
+	}
+	if err := survey.AskOne(prompt, &wizard.OpsgenieAPIKey, survey.WithValidator(survey.Required)); err != nil {
+		return err
+	}
+
+	var region string
+	regionPrompt := &survey.Select{
+		Message: "Opsgenie Region:",
+		Options: []string{"US", "EU"},
+		Default: "US",
+	}
+	if err := survey.AskOne(regionPrompt, &region); err != nil {
+		return err
+	}
+	wizard.OpsgenieAPIURL = notification.OpsgenieAPIURL
+	if region == "EU" {
+		wizard.OpsgenieAPIURL = "https://api.eu.opsgenie.com"
+	}
+
+	var respondersInput string
+	respondersPrompt := &survey.Input{
+		Message: "Responders (comma-separated, optional):",
+		Help:    "Team names, or team:<name>, user:<username>, escalation:<name>, schedule:<name>. Leave empty to use the integration's team",
+	}
+	if err := survey.AskOne(respondersPrompt, &respondersInput); err != nil {
+		return err
+	}
+	for _, responder := range strings.Split(respondersInput, ",") {
+		if responder = strings.TrimSpace(responder); responder != "" {
+			wizard.OpsgenieResponders = append(wizard.OpsgenieResponders, responder)
+		}
+	}
+
+	return nil
+}
+
+func configureKafka(wizard *ConfigWizard) error {
+	// Ask for brokers
+	var brokersInput string
+	prompt := &survey.Input{
+		Message: "Kafka Brokers (comma-separated host:port):",
+		Help:    "Example: kafka-1.example.com:9092,kafka-2.example.com:9092",
+	}
+	if err := survey.AskOne(prompt, &brokersInput, survey.WithValidator(survey.Required)); err != nil {
+		return err
+	}
+
+	wizard.KafkaBrokers = strings.Split(brokersInput, ",")
+	for i := range wizard.KafkaBrokers {
+		wizard.KafkaBrokers[i] = strings.TrimSpace(wizard.KafkaBrokers[i])
+	}
+
+	questions := []*survey.Question{
+		{
+			Name: "kafkaTopic",
+			Prompt: &survey.Input{
+				Message: "Kafka Topic:",
+				Help:    "One JSON event per outdated application is published to this topic",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "kafkaTLS",
+			Prompt: &survey.Confirm{
+				Message: "Use TLS?",
+				Default: true,
+			},
+		},
+		{
+			Name: "kafkaSASLMechanism",
+			Prompt: &survey.Select{
+				Message: "SASL Mechanism:",
+				Options: []string{"None", "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512"},
+				Default: "None",
+			},
+		},
+	}
+
+	if err := survey.Ask(questions, wizard); err != nil {
+		return err
+	}
+
+	if wizard.KafkaSASLMechanism == "None" {
+		wizard.KafkaSASLMechanism = ""
+		return nil
+	}
+
+	credentials := []*survey.Question{
+		{
+			Name: "kafkaUsername",
+			Prompt: &survey.Input{
+				Message: "Kafka Username:",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "kafkaPassword",
+			Prompt: &survey.Password{
+				Message: "Kafka Password:",
+			},
+			Validate: survey.Required,
+		},
+	}
+
+	return survey.Ask(credentials, wizard)
+}
+
+func configureMQTT(wizard *ConfigWizard) error {
+	questions := []*survey.Question{
+		{
+			Name: "mqttBroker",
+			Prompt: &survey.Input{
+				Message: "MQTT Broker URL:",
+				Help:    "Example: mqtt://broker.local:1883 or mqtts://broker.example.com:8883",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "mqttTopic",
+			Prompt: &survey.Input{
+				Message: "Topic template:",
+				Default: notification.DefaultMQTTTopic,
+				Help:    "Placeholders: {app}, {project}, {chart}",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "mqttQoS",
+			Prompt: &survey.Select{
+				Message: "QoS:",
+				Options: []string{"0", "1", "2"},
+				Default: "1",
+				Help:    "0: at most once, 1: at least once, 2: exactly once",
+			},
+		},
+		{
+			Name: "mqttRetain",
+			Prompt: &survey.Confirm{
+				Message: "Retain messages (dashboards show the last event after restarting)?",
+				Default: false,
+			},
+		},
+		{
+			Name: "mqttUsername",
+			Prompt: &survey.Input{
+				Message: "MQTT Username (optional):",
+			},
+		},
+	}
+
+	if err := survey.Ask(questions, wizard); err != nil {
+		return err
+	}
+
+	if wizard.MQTTUsername == "" {
+		return nil
+	}
+
+	question := &survey.Password{
+		Message: "MQTT Password:",
+	}
+
+	return survey.AskOne(question, &wizard.MQTTPassword)
 }
 
 func configureWebhook(wizard *ConfigWizard) error {
@@ -455,7 +764,7 @@ func testNotification(wizard *ConfigWizard) error {
 			logger,
 		)
 	case "email":
-		notifier = notification.NewEmailNotifier(
+		emailNotifier := notification.NewEmailNotifier(
 			wizard.EmailSMTPHost,
 			wizard.EmailSMTPPort,
 			wizard.EmailSMTPUsername,
@@ -465,10 +774,36 @@ func testNotification(wizard *ConfigWizard) error {
 			wizard.EmailUseTLS,
 			logger,
 		)
+		emailNotifier.SetTLS(wizard.EmailSMTPPort == 465, nil)
+		notifier = emailNotifier
 	case "slack":
 		notifier = notification.NewSlackNotifier(wizard.SlackWebhook, logger)
 	case "teams":
-		notifier = notification.NewTeamsNotifier(wizard.TeamsWebhook, logger)
+		teamsNotifier := notification.NewTeamsNotifier(wizard.TeamsWebhook, logger)
+		teamsNotifier.SetAdaptiveCards(wizard.TeamsCardFormat == config.TeamsCardFormatAdaptive)
+		notifier = teamsNotifier
+	case "discord":
+		notifier = notification.NewDiscordNotifier(wizard.DiscordWebhook, logger)
+	case "googlechat":
+		notifier = notification.NewGoogleChatNotifier(wizard.GoogleChatWebhook, logger)
+	case "webex":
+		notifier = notification.NewWebexNotifier(wizard.WebexBotToken, wizard.WebexRoomID, logger)
+	case "opsgenie":
+		notifier = notification.NewOpsgenieNotifier(notification.OpsgenieConfig{
+			APIURL:     wizard.OpsgenieAPIURL,
+			APIKey:     wizard.OpsgenieAPIKey,
+			Responders: wizard.OpsgenieResponders,
+		}, logger)
+	case "kafka":
+		notifier, err = notification.NewKafkaNotifier(wizardKafkaConfig(wizard), wizard.KafkaTopic, logger)
+		if err != nil {
+			return err
+		}
+	case "mqtt":
+		notifier, err = notification.NewMQTTNotifier(wizardMQTTConfig(wizard), wizard.MQTTTopic, logger)
+		if err != nil {
+			return err
+		}
 	case "webhook":
 		notifier = notification.NewWebhookNotifier(wizard.WebhookURL, logger)
 	default:
@@ -485,6 +820,31 @@ func testNotification(wizard *ConfigWizard) error {
 	return nil
 }
 
+// wizardKafkaConfig builds the Kafka producer configuration from the wizard answers
+func wizardKafkaConfig(wizard *ConfigWizard) kafka.Config {
+	kc := kafka.Config{
+		Brokers:       wizard.KafkaBrokers,
+		SASLMechanism: wizard.KafkaSASLMechanism,
+		Username:      wizard.KafkaUsername,
+		Password:      wizard.KafkaPassword,
+	}
+	if wizard.KafkaTLS {
+		kc.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return kc
+}
+
+// wizardMQTTConfig builds the MQTT client configuration from the wizard answers
+func wizardMQTTConfig(wizard *ConfigWizard) mqtt.Config {
+	return mqtt.Config{
+		BrokerURL: wizard.MQTTBroker,
+		Username:  wizard.MQTTUsername,
+		Password:  wizard.MQTTPassword,
+		QoS:       byte(wizard.MQTTQoS),
+		Retain:    wizard.MQTTRetain,
+	}
+}
+
 func saveConfiguration(wizard *ConfigWizard) error {
 	fmt.Println("\nSaving Configuration")
 	fmt.Println(strings.Repeat("-", 60))
@@ -521,6 +881,32 @@ func saveConfiguration(wizard *ConfigWizard) error {
 		cfg.SlackWebhook = wizard.SlackWebhook
 	case "teams":
 		cfg.TeamsWebhook = wizard.TeamsWebhook
+		cfg.TeamsCardFormat = wizard.TeamsCardFormat
+	case "discord":
+		cfg.DiscordWebhook = wizard.DiscordWebhook
+	case "googlechat":
+		cfg.GoogleChatWebhook = wizard.GoogleChatWebhook
+	case "webex":
+		cfg.WebexBotToken = wizard.WebexBotToken
+		cfg.WebexRoomID = wizard.WebexRoomID
+	case "opsgenie":
+		cfg.OpsgenieAPIKey = wizard.OpsgenieAPIKey
+		cfg.OpsgenieAPIURL = wizard.OpsgenieAPIURL
+		cfg.OpsgenieResponders = wizard.OpsgenieResponders
+	case "kafka":
+		cfg.KafkaBrokers = wizard.KafkaBrokers
+		cfg.KafkaTopic = wizard.KafkaTopic
+		cfg.KafkaTLS = wizard.KafkaTLS
+		cfg.KafkaSASLMechanism = wizard.KafkaSASLMechanism
+		cfg.KafkaUsername = wizard.KafkaUsername
+		cfg.KafkaPassword = wizard.KafkaPassword
+	case "mqtt":
+		cfg.MQTTBroker = wizard.MQTTBroker
+		cfg.MQTTTopic = wizard.MQTTTopic
+		cfg.MQTTQoS = wizard.MQTTQoS
+		cfg.MQTTRetain = wizard.MQTTRetain
+		cfg.MQTTUsername = wizard.MQTTUsername
+		cfg.MQTTPassword = wizard.MQTTPassword
 	case "webhook":
 		cfg.WebhookURL = wizard.WebhookURL
 	}
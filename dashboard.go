@@ -0,0 +1,290 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dashboardPath is where serve mode serves the dashboard; {$} keeps it from matching every other path
+const dashboardPath = "/{$}"
+
+// dashboardSeverityOrder ranks the rows of a status by severity, the largest changes first
+var dashboardSeverityOrder = map[string]int{"major": 0, "minor": 1, "patch": 2, "": 3}
+
+// dashboardStatusOrder ranks the rows of the table by status, updates first
+var dashboardStatusOrder = map[string]int{
+	reportStatusUpdateAvailable: 0,
+	reportStatusDrifted:         1,
+	reportStatusRelocated:       2,
+	reportStatusTrackingBranch:  3,
+	reportStatusIgnored:         4,
+	reportStatusUpToDate:        5,
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{- if not .Static }}
+<meta http-equiv="refresh" content="300">
+{{- end }}
+<title>Argazer</title>
+<style>
+body{margin:0;padding:24px;font-family:-apple-system,'Segoe UI',Helvetica,Arial,sans-serif;font-size:14px;color:#24292f;background:#f6f8fa}
+h1{margin:0;font-size:22px}h2{font-size:16px;margin:32px 0 8px}
+.muted{color:#57606a}
+.cards{display:flex;flex-wrap:wrap;gap:12px;margin:20px 0}
+.card{background:#fff;border:1px solid #d0d7de;border-radius:6px;padding:12px 20px;min-width:120px}
+.card .value{font-size:28px;font-weight:600}
+.card.updates .value{color:#bf8700}.card.errors .value{color:#cf222e}.card.security .value{color:#8250df}.card.current .value{color:#1a7f37}
+.filters{display:flex;flex-wrap:wrap;gap:8px;margin-bottom:8px}
+input,select{font:inherit;padding:4px 8px;border:1px solid #d0d7de;border-radius:6px}
+input{flex:1;min-width:200px}
+table{border-collapse:collapse;width:100%;background:#fff;border:1px solid #d0d7de}
+th,td{text-align:left;padding:6px 10px;border-bottom:1px solid #d0d7de;vertical-align:top}
+th{background:#f6f8fa}
+tr.group th{background:#eaeef2}
+.status{white-space:nowrap;font-weight:600}
+.update_available{color:#bf8700}.drifted,.relocated{color:#bc4c00}.up_to_date{color:#1a7f37}.ignored,.tracking_branch{color:#57606a}
+.badge{display:inline-block;border-radius:10px;padding:0 6px;font-size:12px;background:#eaeef2}
+.badge.security{background:#fbefff;color:#8250df}
+a{color:#0969da}
+</style>
+</head>
+<body>
+<h1>Argazer</h1>
+{{- if .Scanned.IsZero }}
+<p class="muted">No scan has completed yet; this page refreshes every 5 minutes.</p>
+{{- else }}
+<p class="muted">Last scan: <time datetime="{{ .Scanned.Format "2006-01-02T15:04:05Z07:00" }}">{{ .Scanned.Format "2006-01-02 15:04:05 MST" }}</time></p>
+
+<div class="cards">
+<div class="card"><div class="value">{{ .Total }}</div><div class="muted">Applications</div></div>
+<div class="card updates"><div class="value">{{ .Updates }}</div><div class="muted">Updates available</div></div>
+<div class="card security"><div class="value">{{ .SecurityUpdates }}</div><div class="muted">Security updates</div></div>
+<div class="card current"><div class="value">{{ .UpToDate }}</div><div class="muted">Up to date</div></div>
+<div class="card errors"><div class="value">{{ len .Errors }}</div><div class="muted">Errors</div></div>
+</div>
+
+<div class="filters">
+<input id="search" type="search" placeholder="Search applications, charts and repositories" autofocus>
+<select id="project"><option value="">All projects</option>{{ range .Projects }}<option>{{ . }}</option>{{ end }}</select>
+<select id="severity"><option value="">All severities</option><option>major</option><option>minor</option><option>patch</option><option value="security">security</option></select>
+</div>
+<table id="applications">
+<thead><tr><th>Status</th><th>Application</th><th>Project</th><th>Chart</th><th>Current</th><th>Latest</th><th>Severity</th></tr></thead>
+<tbody>
+{{- range .Rows }}
+{{- if .GroupStart }}
+<tr class="group" data-group="{{ .Group }}"><th colspan="7">{{ $.GroupLabel }}: {{ if .Group }}{{ .Group }}{{ else }}(none){{ end }}</th></tr>
+{{- end }}
+<tr data-search="{{ .Search }}" data-project="{{ .Project }}" data-severity="{{ .Severity }}" data-security="{{ .Security }}" data-group="{{ .Group }}">
+<td class="status {{ .Status }}">{{ .StatusLabel }}</td>
+<td>{{ if .URL }}<a href="{{ .URL }}">{{ .Application }}</a>{{ else }}{{ .Application }}{{ end }}</td>
+<td>{{ .Project }}</td>
+<td>{{ .Chart }}<div class="muted">{{ .Repository }}</div></td>
+<td>{{ .Current }}</td>
+<td>{{ .Latest }}</td>
+<td>{{ if .Severity }}<span class="badge">{{ .Severity }}</span>{{ end }}{{ if .Security }} <span class="badge security">security</span>{{ end }}</td>
+</tr>
+{{- end }}
+</tbody>
+</table>
+<p id="empty" class="muted" hidden>No applications match the filters.</p>
+
+{{- if .Errors }}
+<h2>Errors</h2>
+<table>
+<thead><tr><th>Application</th><th>Project</th><th>Chart</th><th>Error</th></tr></thead>
+<tbody>
+{{- range .Errors }}
+<tr>
+<td>{{ if .URL }}<a href="{{ .URL }}">{{ .Application }}</a>{{ else }}{{ .Application }}{{ end }}</td>
+<td>{{ .Project }}</td>
+<td>{{ .Chart }}<div class="muted">{{ .Repository }}</div></td>
+<td>{{ if .ErrorCode }}<span class="badge">{{ .ErrorCode }}</span> {{ end }}{{ .Error }}</td>
+</tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+
+<script>
+(function () {
+  var search = document.getElementById('search'), project = document.getElementById('project'), severity = document.getElementById('severity');
+  function filter() {
+    var text = search.value.toLowerCase(), shown = 0;
+    var groups = {};
+    document.querySelectorAll('#applications tbody tr[data-search]').forEach(function (row) {
+      var match = row.dataset.search.indexOf(text) >= 0 &&
+        (!project.value || row.dataset.project === project.value) &&
+        (!severity.value || (severity.value === 'security' ? row.dataset.security === 'true' : row.dataset.severity === severity.value));
+      row.hidden = !match;
+      if (match) {
+        shown++;
+        groups[row.dataset.group] = true;
+      }
+    });
+    document.querySelectorAll('#applications tbody tr.group').forEach(function (row) {
+      row.hidden = !groups[row.dataset.group];
+    });
+    document.getElementById('empty').hidden = shown > 0;
+  }
+  [search, project, severity].forEach(function (el) { el.addEventListener('input', filter); });
+})();
+</script>
+{{- end }}
+</body>
+</html>
+`))
+
+// dashboardPage is the data of the dashboard
+type dashboardPage struct {
+	Scanned         time.Time // Zero until the first scan completed
+	Total           int
+	Updates         int
+	SecurityUpdates int
+	UpToDate        int
+	Rows            []dashboardRow // Checked applications, updates first, by group with group_by
+	Errors          []dashboardRow // Applications that couldn't be checked
+	Projects        []string
+	GroupLabel      string // Label of the group_by value, empty without grouping
+	Static          bool   // Written to a file rather than served, so it doesn't refresh
+}
+
+// dashboardRow is an application of the dashboard
+type dashboardRow struct {
+	Status      string // Report status, e.g. "update_available"
+	StatusLabel string
+	Application string
+	Project     string
+	Chart       string
+	Repository  string
+	Current     string
+	Latest      string
+	Severity    string
+	Security    bool
+	URL         string
+	ErrorCode   string
+	Error       string
+	Search      string // Lowercase text the search box matches
+	Group       string // group_by value of the application
+	GroupStart  bool   // First row of its group, preceded by the group heading
+}
+
+// dashboardHandler serves an HTML page summarizing the last completed scan
+type dashboardHandler struct {
+	groupBy string
+	mu      sync.RWMutex
+	page    dashboardPage
+}
+
+// update replaces the served page with the results of a completed scan
+func (h *dashboardHandler) update(results []ApplicationCheckResult, scanned time.Time) {
+	page := newDashboardPage(results, scanned, h.groupBy)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.page = page
+}
+
+func (h *dashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := dashboardTemplate.Execute(w, h.page); err != nil {
+		http.Error(w, "failed to render dashboard", http.StatusInternalServerError)
+	}
+}
+
+// renderHTML displays results as a standalone copy of the dashboard
+func renderHTML(cat categorizedResults, w io.Writer) error {
+	page := newDashboardPage(cat.results, time.Now(), cat.layout.groupBy)
+	page.Static = true
+	if err := dashboardTemplate.Execute(w, page); err != nil {
+		return fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return nil
+}
+
+// newDashboardPage builds the dashboard of a scan, following the categories of processResults
+// With group_by, the rows are grouped in the order of groupResults first.
+func newDashboardPage(results []ApplicationCheckResult, scanned time.Time, groupBy string) dashboardPage {
+	page := dashboardPage{Scanned: scanned}
+	stats := processResults(results).stats
+	page.Total, page.Updates, page.UpToDate = stats.total, stats.updates, stats.upToDate
+
+	for _, result := range results {
+		if result.AppName == "" {
+			continue
+		}
+		status := reportStatus(result)
+		row := dashboardRow{
+			Status:      status,
+			StatusLabel: reportStatusLabels[status],
+			Application: resultDisplayName(result),
+			Project:     instanceProject(result),
+			Chart:       result.ChartName,
+			Repository:  result.RepoURL,
+			Current:     result.CurrentVersion,
+			Latest:      result.LatestVersion,
+			URL:         result.URL,
+		}
+		if !slices.Contains(page.Projects, row.Project) {
+			page.Projects = append(page.Projects, row.Project)
+		}
+
+		if status == reportStatusError {
+			row.ErrorCode, row.Error = result.ErrorCode, result.Error
+			page.Errors = append(page.Errors, row)
+			continue
+		}
+		if status == reportStatusUpdateAvailable {
+			row.Severity, row.Security = result.Severity, result.SecurityUpdate
+			if row.Security {
+				page.SecurityUpdates++
+			}
+		}
+		row.Search = strings.ToLower(strings.Join([]string{row.Application, row.Project, row.Chart, row.Repository, row.StatusLabel}, " "))
+		if groupingEnabled(groupBy) {
+			row.Group = resultGroupKey(result, groupBy)
+		}
+		page.Rows = append(page.Rows, row)
+	}
+
+	sort.Strings(page.Projects)
+	sort.SliceStable(page.Rows, func(i, j int) bool {
+		a, b := page.Rows[i], page.Rows[j]
+		if a.Group != b.Group {
+			return groupKeyLess(a.Group, b.Group, groupBy)
+		}
+		if a.Status != b.Status {
+			return dashboardStatusOrder[a.Status] < dashboardStatusOrder[b.Status]
+		}
+		if a.Severity != b.Severity {
+			return dashboardSeverityOrder[a.Severity] < dashboardSeverityOrder[b.Severity]
+		}
+		return a.Application < b.Application
+	})
+	sort.SliceStable(page.Errors, func(i, j int) bool {
+		return page.Errors[i].Application < page.Errors[j].Application
+	})
+
+	if groupingEnabled(groupBy) {
+		page.GroupLabel = groupLabel(groupBy, nil)
+		for i := range page.Rows {
+			page.Rows[i].GroupStart = i == 0 || page.Rows[i].Group != page.Rows[i-1].Group
+		}
+	}
+	return page
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/config"
+	"argazer/internal/helm"
+)
+
+func TestNewDashboardPage(t *testing.T) {
+	scanned := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	page := newDashboardPage([]ApplicationCheckResult{
+		{AppName: "web", Project: "frontend", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+		{AppName: "queue", Project: "backend", ChartName: "rabbitmq", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true, Severity: "minor"},
+		{AppName: "cache", Namespace: "argocd", Project: "backend", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true, Severity: "major", SecurityUpdate: true},
+		{AppName: "db", Project: "backend", ChartName: "postgresql", Error: "authentication failed", ErrorCode: helm.ErrorCodeAuthFailed},
+		{}, // Not a Helm application
+	}, scanned, config.GroupByNone)
+
+	assert.Equal(t, scanned, page.Scanned)
+	assert.Equal(t, 4, page.Total)
+	assert.Equal(t, 2, page.Updates)
+	assert.Equal(t, 1, page.SecurityUpdates)
+	assert.Equal(t, 1, page.UpToDate)
+	assert.Equal(t, []string{"backend", "frontend"}, page.Projects)
+
+	require.Len(t, page.Rows, 3)
+	assert.Equal(t, "argocd/cache", page.Rows[0].Application, "updates come first, the largest changes first")
+	assert.Equal(t, "queue", page.Rows[1].Application)
+	assert.Equal(t, "web", page.Rows[2].Application)
+	assert.Equal(t, "argocd/cache backend redis  update", page.Rows[0].Search)
+	assert.Empty(t, page.Rows[2].Severity)
+
+	require.Len(t, page.Errors, 1)
+	assert.Equal(t, helm.ErrorCodeAuthFailed, page.Errors[0].ErrorCode)
+	assert.Equal(t, "authentication failed", page.Errors[0].Error)
+}
+
+func TestNewDashboardPage_GroupBy(t *testing.T) {
+	page := newDashboardPage([]ApplicationCheckResult{
+		{AppName: "web", Project: "frontend", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+		{AppName: "queue", Project: "backend", ChartName: "rabbitmq", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true, Severity: "minor"},
+		{AppName: "cache", Project: "backend", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true, Severity: "major"},
+	}, time.Now(), config.GroupByProject)
+
+	assert.Equal(t, "Project", page.GroupLabel)
+	require.Len(t, page.Rows, 3)
+	assert.Equal(t, []string{"cache", "queue", "web"}, []string{page.Rows[0].Application, page.Rows[1].Application, page.Rows[2].Application})
+	assert.Equal(t, []bool{true, false, true}, []bool{page.Rows[0].GroupStart, page.Rows[1].GroupStart, page.Rows[2].GroupStart})
+
+	var buf strings.Builder
+	require.NoError(t, dashboardTemplate.Execute(&buf, page))
+	assert.Contains(t, buf.String(), `<tr class="group" data-group="backend"><th colspan="7">Project: backend</th></tr>`)
+}
+
+func TestDashboardHandler(t *testing.T) {
+	handler := &dashboardHandler{}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), "No scan has completed yet")
+
+	handler.update([]ApplicationCheckResult{
+		{AppName: "<script>", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true, Severity: "major", URL: "https://argocd.example.com/applications/argocd/web"},
+		{AppName: "db", Project: "default", Error: "timeout", ErrorCode: helm.ErrorCodeTimeout},
+	}, time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+	assert.Contains(t, body, "Last scan: <time datetime=\"2024-01-01T08:00:00Z\">2024-01-01 08:00:00 UTC</time>")
+	assert.Contains(t, body, `<a href="https://argocd.example.com/applications/argocd/web">&lt;script&gt;</a>`)
+	assert.NotContains(t, body, "<td><script>", "application names are escaped")
+	assert.Contains(t, body, "<option>default</option>")
+	assert.Contains(t, body, "<h2>Errors</h2>")
+	assert.Contains(t, body, "TIMEOUT</span> timeout")
+}
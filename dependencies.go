@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/helm"
+)
+
+// DependencyCheckResult is the version check of one dependency (subchart) of a Git-based chart
+type DependencyCheckResult struct {
+	Name                       string `json:"name"`
+	Repository                 string `json:"repository"`
+	CurrentVersion             string `json:"current_version"`              // Version locked in Chart.lock, or the version or range declared in Chart.yaml
+	LatestVersion              string `json:"latest_version,omitempty"`     // Latest version within the constraint
+	LatestVersionAll           string `json:"latest_version_all,omitempty"` // Latest version without constraint (if different)
+	HasUpdate                  bool   `json:"has_update"`
+	HasUpdateOutsideConstraint bool   `json:"has_update_outside_constraint,omitempty"`
+	Error                      string `json:"error,omitempty"`
+}
+
+// dependencyUpdate is an outdated subchart, with the application whose chart bundles it
+type dependencyUpdate struct {
+	AppName   string `json:"app_name"`
+	Namespace string `json:"namespace,omitempty"`
+	Project   string `json:"project"`
+	Instance  string `json:"instance,omitempty"`
+	URL       string `json:"url,omitempty"`
+	DependencyCheckResult
+}
+
+// chartDependenciesLookup returns the dependencies of a chart in a Git repository at a revision
+type chartDependenciesLookup func(ctx context.Context, repoURL, chartPath, revision string) ([]helm.ChartDependency, error)
+
+// dependencyVersionLookup returns the latest version of a chart dependency within a version constraint
+type dependencyVersionLookup func(ctx context.Context, dependency helm.ChartDependency, constraint string) (*helm.VersionConstraintResult, error)
+
+// checkDependencies checks the dependencies of checked Git-based charts for newer versions, using each
+// application's version constraint
+// Dependencies are read from Chart.yaml (with Chart.lock) at the deployed revision; local file://
+// subcharts are skipped. Semver ranges only apply to the chart itself, so dependencies of applications
+// constrained by one are compared with all versions. Each chart revision and each dependency version
+// is looked up once, with up to concurrency lookups in flight.
+func checkDependencies(ctx context.Context, results []ApplicationCheckResult, dependenciesOf chartDependenciesLookup, lookup dependencyVersionLookup, concurrency int, logger *logrus.Entry) {
+	type chartRevision struct {
+		repoURL, chartPath, revision string
+	}
+	type dependencyCheck struct {
+		dependency helm.ChartDependency
+		constraint string
+	}
+
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+
+	// Read the dependencies of each chart revision
+	var charts []chartRevision
+	seen := make(map[chartRevision]bool)
+	resultCharts := make(map[int]chartRevision)
+	for i, result := range results {
+		// Teams opting out with the annotation don't want their subcharts checked either
+		if result.AppName == "" || result.Error != "" || result.IgnoredBy == ignoreAnnotation+" annotation" || !helm.IsGitRepository(result.RepoURL) {
+			continue
+		}
+		chart := chartRevision{result.RepoURL, result.ChartName, dependencyRevision(result)}
+		if !seen[chart] {
+			seen[chart] = true
+			charts = append(charts, chart)
+		}
+		resultCharts[i] = chart
+	}
+	if len(charts) == 0 {
+		return
+	}
+
+	// Workers write to a separate map, the chart list isn't modified while iterated
+	chartDependencies := make(map[chartRevision][]helm.ChartDependency, len(charts))
+	var mu sync.Mutex
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, chart := range charts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			dependencies, err := dependenciesOf(ctx, chart.repoURL, chart.chartPath, chart.revision)
+			if err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{"repo_url": chart.repoURL, "chart": chart.chartPath}).Warn("Failed to read chart dependencies")
+				return
+			}
+			mu.Lock()
+			chartDependencies[chart] = dependencies
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Look up each dependency version once
+	checkIndex := make(map[dependencyCheck]int)
+	var checks []dependencyCheck
+	resultChecks := make(map[int][]int)
+	for i := range results {
+		chart, ok := resultCharts[i]
+		if !ok {
+			continue
+		}
+		constraint := results[i].ConstraintApplied
+		if !helm.IsConstraintKeyword(constraint) {
+			constraint = "major"
+		}
+		for _, dependency := range chartDependencies[chart] {
+			if dependency.IsLocal() {
+				continue
+			}
+			check := dependencyCheck{dependency, constraint}
+			index, ok := checkIndex[check]
+			if !ok {
+				index = len(checks)
+				checkIndex[check] = index
+				checks = append(checks, check)
+			}
+			resultChecks[i] = append(resultChecks[i], index)
+		}
+	}
+
+	checked := make([]DependencyCheckResult, len(checks))
+	for i, check := range checks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			checked[i] = checkDependency(ctx, check.dependency, check.constraint, lookup, logger)
+		}()
+	}
+	wg.Wait()
+
+	for i, indexes := range resultChecks {
+		for _, index := range indexes {
+			results[i].Dependencies = append(results[i].Dependencies, checked[index])
+		}
+	}
+}
+
+// dependencyRevision returns the Git revision an application deploys its chart from
+func dependencyRevision(result ApplicationCheckResult) string {
+	switch {
+	case result.PinnedRevision != "":
+		return result.PinnedRevision
+	case result.TrackingBranch != "":
+		return result.TrackingBranch
+	default:
+		return result.CurrentVersion
+	}
+}
+
+// checkDependency looks up the latest version of one dependency
+// A dependency declared as a range is outdated once the latest version falls outside the range.
+func checkDependency(ctx context.Context, dependency helm.ChartDependency, constraint string, lookup dependencyVersionLookup, logger *logrus.Entry) DependencyCheckResult {
+	result := DependencyCheckResult{Name: dependency.Name, Repository: dependency.Repository, CurrentVersion: dependency.Version}
+	dependencyLogger := logger.WithFields(logrus.Fields{"dependency": dependency.Name, "version": dependency.Version})
+
+	latest, err := lookup(ctx, dependency, constraint)
+	if err != nil {
+		result.Error = err.Error()
+		dependencyLogger.WithError(err).Warn("Failed to check chart dependency")
+		return result
+	}
+
+	result.LatestVersion = latest.LatestVersion
+	result.HasUpdate = latest.LatestVersion != dependency.Version
+	if versionRange, err := semver.NewConstraint(dependency.Version); err == nil {
+		if version, err := semver.NewVersion(latest.LatestVersion); err == nil {
+			result.HasUpdate = !versionRange.Check(version)
+		}
+	}
+	result.HasUpdateOutsideConstraint = latest.HasUpdateOutsideConstraint
+	if latest.LatestVersionAll != latest.LatestVersion {
+		result.LatestVersionAll = latest.LatestVersionAll
+	}
+
+	if result.HasUpdate {
+		dependencyLogger.WithField("latest_version", result.LatestVersion).Info("Chart dependency update available")
+	}
+	return result
+}
+
+// dependencyUpdates lists the outdated subcharts, by application
+func dependencyUpdates(results []ApplicationCheckResult) []dependencyUpdate {
+	var updates []dependencyUpdate
+	for _, result := range results {
+		for _, dependency := range result.Dependencies {
+			if !dependency.HasUpdate {
+				continue
+			}
+			updates = append(updates, dependencyUpdate{
+				AppName:               result.AppName,
+				Namespace:             result.Namespace,
+				Project:               result.Project,
+				Instance:              result.Instance,
+				URL:                   result.URL,
+				DependencyCheckResult: dependency,
+			})
+		}
+	}
+	return updates
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/helm"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "web", RepoURL: "https://github.com/org/charts.git", ChartName: "charts/app", CurrentVersion: "v1.1.0", ConstraintApplied: "major"},
+		{AppName: "api", RepoURL: "https://github.com/org/charts.git", ChartName: "charts/app", CurrentVersion: "v1.1.0", ConstraintApplied: ">=1.0.0 <2.0.0"},
+		{AppName: "edge", RepoURL: "https://github.com/org/charts.git", ChartName: "charts/app", TrackingBranch: "main", ConstraintApplied: "major"},
+		{AppName: "helm", RepoURL: "https://charts.example.com", ChartName: "nginx", CurrentVersion: "1.0.0"},
+		{AppName: "opted-out", RepoURL: "https://github.com/org/charts.git", ChartName: "charts/app", CurrentVersion: "v1.1.0", IgnoredBy: ignoreAnnotation + " annotation"},
+	}
+
+	var mu sync.Mutex
+	var reads, lookups []string
+	dependenciesOf := func(ctx context.Context, repoURL, chartPath, revision string) ([]helm.ChartDependency, error) {
+		mu.Lock()
+		reads = append(reads, chartPath+"@"+revision)
+		mu.Unlock()
+		if revision == "main" {
+			return nil, errors.New("clone failed")
+		}
+		return []helm.ChartDependency{
+			{Name: "postgresql", Version: "12.5.8", Repository: "oci://registry-1.docker.io/bitnamicharts"},
+			{Name: "redis", Version: "17.x.x", Repository: "https://charts.bitnami.com/bitnami"},
+			{Name: "common", Version: "1.0.0", Repository: "file://../common"},
+		}, nil
+	}
+	lookup := func(ctx context.Context, dependency helm.ChartDependency, constraint string) (*helm.VersionConstraintResult, error) {
+		mu.Lock()
+		lookups = append(lookups, dependency.Name+" "+constraint)
+		mu.Unlock()
+		if dependency.Name == "redis" {
+			return &helm.VersionConstraintResult{LatestVersion: "17.11.3", LatestVersionAll: "17.11.3"}, nil
+		}
+		return &helm.VersionConstraintResult{LatestVersion: "13.2.0", LatestVersionAll: "13.2.0"}, nil
+	}
+
+	checkDependencies(context.Background(), results, dependenciesOf, lookup, 2, logrus.NewEntry(logrus.New()))
+
+	assert.ElementsMatch(t, []string{"charts/app@v1.1.0", "charts/app@main"}, reads, "each chart revision is read once")
+	assert.ElementsMatch(t, []string{"postgresql major", "redis major"}, lookups, "each dependency is looked up once, ranges fall back to major")
+
+	assert.Equal(t, []DependencyCheckResult{
+		{Name: "postgresql", Repository: "oci://registry-1.docker.io/bitnamicharts", CurrentVersion: "12.5.8", LatestVersion: "13.2.0", HasUpdate: true},
+		{Name: "redis", Repository: "https://charts.bitnami.com/bitnami", CurrentVersion: "17.x.x", LatestVersion: "17.11.3"},
+	}, results[0].Dependencies, "a range is up to date while it takes the latest version")
+	assert.Equal(t, results[0].Dependencies, results[1].Dependencies)
+	assert.Empty(t, results[2].Dependencies)
+	assert.Empty(t, results[3].Dependencies, "only Git-based charts have their dependencies checked")
+	assert.Empty(t, results[4].Dependencies)
+}
+
+func TestOutputResults_DependencyUpdates(t *testing.T) {
+	results := []ApplicationCheckResult{{
+		AppName:        "web",
+		Project:        "default",
+		CurrentVersion: "v1.1.0",
+		LatestVersion:  "v1.1.0",
+		Dependencies: []DependencyCheckResult{
+			{Name: "postgresql", Repository: "https://charts.bitnami.com/bitnami", CurrentVersion: "12.5.8", LatestVersion: "12.12.10", LatestVersionAll: "13.2.0", HasUpdate: true, HasUpdateOutsideConstraint: true},
+			{Name: "redis", Repository: "https://charts.bitnami.com/bitnami", CurrentVersion: "17.x.x", LatestVersion: "17.11.3"},
+		},
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Contains(t, table.String(), "Subchart updates available: 1\n")
+	assert.Contains(t, table.String(), "SUBCHARTS WITH UPDATES AVAILABLE:")
+	assert.Regexp(t, `\nweb\s+default\s+postgresql\s+https://charts.bitnami.com/bitnami\s+12.5.8\s+12.12.10\s+Version 13.2.0 available outside constraint\n`, table.String())
+	assert.NotContains(t, table.String(), "redis")
+
+	var md bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &md))
+	assert.Contains(t, md.String(), "## Subcharts with Updates Available\n\n")
+	assert.Contains(t, md.String(), "| web | postgresql | 12.5.8 | 12.12.10 |\n")
+
+	var out bytes.Buffer
+	require.NoError(t, outputResults(results, "json", nil, &out))
+	assert.Contains(t, out.String(), `"dependency_updates": 1`)
+}
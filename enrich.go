@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/artifacthub"
+)
+
+// artifactHubLookup returns the Artifact Hub metadata of a chart version, nil if it isn't listed
+type artifactHubLookup func(ctx context.Context, repoURL, chartName, version string) (*artifacthub.Package, error)
+
+// enrichArtifactHub attaches the Artifact Hub metadata of their chart to successfully checked results
+// Each chart version is looked up once, with up to concurrency lookups in flight; failed lookups are
+// logged and leave the results unenriched.
+func enrichArtifactHub(ctx context.Context, results []ApplicationCheckResult, lookup artifactHubLookup, concurrency int, logger *logrus.Entry) {
+	type chartVersion struct {
+		repoURL, chartName, version string
+	}
+
+	byChart := make(map[chartVersion][]int)
+	var charts []chartVersion
+	for i, result := range results {
+		if result.AppName == "" || result.Error != "" || result.ChartName == "" {
+			continue
+		}
+		key := chartVersion{result.RepoURL, result.ChartName, result.CurrentVersion}
+		if _, ok := byChart[key]; !ok {
+			charts = append(charts, key)
+		}
+		byChart[key] = append(byChart[key], i)
+	}
+	if len(charts) == 0 {
+		return
+	}
+
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	packages := make([]*artifacthub.Package, len(charts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, chart := range charts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			pkg, err := lookup(ctx, chart.repoURL, chart.chartName, chart.version)
+			if err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{
+					"repo":  chart.repoURL,
+					"chart": chart.chartName,
+				}).Warn("Failed to fetch Artifact Hub metadata")
+				return
+			}
+			packages[i] = pkg
+		}()
+	}
+	wg.Wait()
+
+	for i, chart := range charts {
+		if packages[i] == nil {
+			continue
+		}
+		for _, index := range byChart[chart] {
+			results[index].ArtifactHub = packages[i]
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"argazer/internal/artifacthub"
+)
+
+func TestEnrichArtifactHub(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "web-a", RepoURL: "https://charts.bitnami.com/bitnami", ChartName: "nginx", CurrentVersion: "15.0.0"},
+		{AppName: "web-b", RepoURL: "https://charts.bitnami.com/bitnami", ChartName: "nginx", CurrentVersion: "15.0.0"},
+		{AppName: "cache", RepoURL: "https://charts.bitnami.com/bitnami", ChartName: "redis", CurrentVersion: "18.0.0"},
+		{AppName: "internal", RepoURL: "https://charts.internal.example.com", ChartName: "api", CurrentVersion: "1.0.0"},
+		{AppName: "broken", RepoURL: "https://charts.bitnami.com/bitnami", ChartName: "nginx", Error: "timeout"},
+	}
+
+	var lookups atomic.Int32
+	lookup := func(ctx context.Context, repoURL, chartName, version string) (*artifacthub.Package, error) {
+		lookups.Add(1)
+		switch chartName {
+		case "nginx":
+			return &artifacthub.Package{URL: "https://artifacthub.io/packages/helm/bitnami/nginx", Deprecated: true}, nil
+		case "redis":
+			return nil, errors.New("unexpected status 429")
+		}
+		return nil, nil
+	}
+
+	enrichArtifactHub(context.Background(), results, lookup, 2, logrus.NewEntry(logrus.New()))
+
+	assert.Equal(t, int32(3), lookups.Load(), "each chart version must be looked up once")
+	assert.True(t, results[0].ArtifactHub.Deprecated)
+	assert.Same(t, results[0].ArtifactHub, results[1].ArtifactHub)
+	assert.Nil(t, results[2].ArtifactHub, "failed lookups leave results unenriched")
+	assert.Nil(t, results[3].ArtifactHub, "unlisted charts leave results unenriched")
+	assert.Nil(t, results[4].ArtifactHub, "failed checks aren't looked up")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/argocd"
+	"argazer/internal/config"
+)
+
+// hasExclusions reports whether any exclusion filter is configured
+func hasExclusions(cfg *config.Config) bool {
+	return len(cfg.ExcludeProjects) > 0 || len(cfg.ExcludeAppNames) > 0 || len(cfg.ExcludeRepos) > 0
+}
+
+// excludeApplications drops the applications of excluded projects, names and repositories, keeping the order
+func excludeApplications(apps []*v1alpha1.Application, cfg *config.Config, logger *logrus.Entry) []*v1alpha1.Application {
+	kept := make([]*v1alpha1.Application, 0, len(apps))
+	for _, app := range apps {
+		if !applicationExcluded(app, cfg, logger) {
+			kept = append(kept, app)
+		}
+	}
+	return kept
+}
+
+// applicationExcluded reports whether an application matches one of the exclusion filters
+// Repositories are matched against the Helm source, the one a scan would check.
+func applicationExcluded(app *v1alpha1.Application, cfg *config.Config, logger *logrus.Entry) bool {
+	if len(cfg.ExcludeProjects) > 0 && argocd.MatchesName(cfg.ExcludeProjects, app.Spec.Project) {
+		return true
+	}
+	if len(cfg.ExcludeAppNames) > 0 && argocd.MatchesName(cfg.ExcludeAppNames, app.Name) {
+		return true
+	}
+	if len(cfg.ExcludeRepos) == 0 {
+		return false
+	}
+
+	source := findHelmSource(app, cfg.SourceName, logger.WithField("app_name", app.Name))
+	if source == nil {
+		return false
+	}
+	repo := normalizeRepoURL(source.RepoURL)
+	for _, excluded := range cfg.ExcludeRepos {
+		prefix := normalizeRepoURL(excluded)
+		if repo == prefix || strings.HasPrefix(repo, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"argazer/internal/config"
+)
+
+func TestExcludeApplications(t *testing.T) {
+	app := func(name, project, repoURL string) *v1alpha1.Application {
+		return &v1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.ApplicationSpec{
+				Project: project,
+				Source:  &v1alpha1.ApplicationSource{RepoURL: repoURL, Chart: name, TargetRevision: "1.0.0"},
+			},
+		}
+	}
+	apps := []*v1alpha1.Application{
+		app("api", "payments", "https://charts.example.com"),
+		app("sandbox-web", "payments", "https://charts.example.com"),
+		app("db", "platform", "https://charts.example.com"),
+		app("cache", "team-a", "https://charts.example.com"),
+		app("mirrored", "platform", "oci://mirror.internal/charts/stable"),
+		app("lookalike", "platform", "https://mirror.internal-other/charts"),
+	}
+	cfg := &config.Config{
+		ExcludeProjects: []string{"/^team-/"},
+		ExcludeAppNames: []string{"sandbox-*", "db"},
+		ExcludeRepos:    []string{"https://mirror.internal/"},
+	}
+
+	kept := excludeApplications(apps, cfg, logrus.NewEntry(logrus.New()))
+	names := make([]string, 0, len(kept))
+	for _, app := range kept {
+		names = append(names, app.Name)
+	}
+	assert.Equal(t, []string{"api", "lookalike"}, names)
+	assert.False(t, hasExclusions(&config.Config{}))
+}
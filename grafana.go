@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"argazer/internal/notification"
+)
+
+// grafanaDashboardUID is the UID of the exported dashboard unless --uid is set
+const grafanaDashboardUID = "argazer"
+
+// newGrafanaCmd creates the grafana command
+func newGrafanaCmd() *cobra.Command {
+	grafanaCmd := &cobra.Command{
+		Use:   "grafana",
+		Short: "Grafana integration",
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Print a Grafana dashboard of the serve mode metrics",
+		Long: `Export prints a Grafana dashboard of the Prometheus metrics served at /metrics by argazer serve:
+outdated applications, versions behind and staleness per project over time, check errors and the
+applications with the stalest updates. The dashboard shows the annotations argazer creates for new
+updates (see grafana_url). Import it in Grafana or add it to a dashboard provisioning directory.`,
+		Args: cobra.NoArgs,
+		RunE: runGrafanaExport,
+	}
+	exportCmd.Flags().String("uid", grafanaDashboardUID, "UID of the dashboard (set grafana_dashboard_uid to the same value to attach annotations to it)")
+	exportCmd.Flags().String("file", "", "Write the dashboard to a file instead of stdout")
+
+	grafanaCmd.AddCommand(exportCmd)
+	return grafanaCmd
+}
+
+// runGrafanaExport writes the dashboard JSON
+func runGrafanaExport(cmd *cobra.Command, args []string) error {
+	uid, _ := cmd.Flags().GetString("uid")
+	file, _ := cmd.Flags().GetString("file")
+
+	if file == "" {
+		return writeGrafanaDashboard(cmd.OutOrStdout(), uid)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to create dashboard file: %w", err)
+	}
+	if err := writeGrafanaDashboard(f, uid); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeGrafanaDashboard encodes the dashboard as indented JSON
+func writeGrafanaDashboard(w io.Writer, uid string) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(newGrafanaDashboard(uid)); err != nil {
+		return fmt.Errorf("failed to encode dashboard: %w", err)
+	}
+	return nil
+}
+
+// grafanaDashboard is the JSON model of a Grafana dashboard, limited to the fields argazer sets
+type grafanaDashboard struct {
+	UID           string                              `json:"uid"`
+	Title         string                              `json:"title"`
+	Tags          []string                            `json:"tags"`
+	SchemaVersion int                                 `json:"schemaVersion"`
+	Refresh       string                              `json:"refresh"`
+	Time          grafanaTimeRange                    `json:"time"`
+	Annotations   grafanaList[grafanaAnnotationQuery] `json:"annotations"`
+	Templating    grafanaList[grafanaVariable]        `json:"templating"`
+	Panels        []grafanaPanel                      `json:"panels"`
+}
+
+// grafanaList is the {"list": [...]} wrapper of annotations and variables
+type grafanaList[T any] struct {
+	List []T `json:"list"`
+}
+
+// grafanaTimeRange is the time range shown when the dashboard opens
+type grafanaTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// grafanaDatasource references a data source by type and UID
+type grafanaDatasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+// grafanaAnnotationQuery is an annotation query of the dashboard
+type grafanaAnnotationQuery struct {
+	Name       string            `json:"name"`
+	Datasource grafanaDatasource `json:"datasource"`
+	Enable     bool              `json:"enable"`
+	IconColor  string            `json:"iconColor"`
+	Target     map[string]any    `json:"target"`
+}
+
+// grafanaVariable is a template variable of the dashboard
+type grafanaVariable struct {
+	Name       string             `json:"name"`
+	Label      string             `json:"label"`
+	Type       string             `json:"type"`
+	Query      string             `json:"query"`
+	Datasource *grafanaDatasource `json:"datasource,omitempty"`
+	Refresh    int                `json:"refresh,omitempty"` // 2 reloads the values when the time range changes
+	Multi      bool               `json:"multi,omitempty"`
+	IncludeAll bool               `json:"includeAll,omitempty"`
+	AllValue   string             `json:"allValue,omitempty"`
+}
+
+// grafanaPanel is a panel of the dashboard
+type grafanaPanel struct {
+	ID          int               `json:"id"`
+	Type        string            `json:"type"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	GridPos     grafanaGridPos    `json:"gridPos"`
+	Datasource  grafanaDatasource `json:"datasource"`
+	Targets     []grafanaTarget   `json:"targets"`
+	FieldConfig map[string]any    `json:"fieldConfig"`
+	Options     map[string]any    `json:"options,omitempty"`
+}
+
+// grafanaGridPos places a panel on the 24 columns wide grid
+type grafanaGridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// grafanaTarget is a PromQL query of a panel
+type grafanaTarget struct {
+	RefID        string            `json:"refId"`
+	Datasource   grafanaDatasource `json:"datasource"`
+	Expr         string            `json:"expr"`
+	LegendFormat string            `json:"legendFormat,omitempty"`
+	Instant      bool              `json:"instant,omitempty"`
+	Format       string            `json:"format,omitempty"`
+}
+
+// grafanaPrometheus is the Prometheus data source picked in the dashboard's datasource variable
+var grafanaPrometheus = grafanaDatasource{Type: "prometheus", UID: "${datasource}"}
+
+// newGrafanaDashboard builds the dashboard of the metrics written by writeMetrics
+func newGrafanaDashboard(uid string) grafanaDashboard {
+	dashboard := grafanaDashboard{
+		UID:           uid,
+		Title:         "Argazer",
+		Tags:          []string{notification.GrafanaAnnotationTag},
+		SchemaVersion: 39,
+		Refresh:       "5m",
+		Time:          grafanaTimeRange{From: "now-30d", To: "now"},
+		Annotations: grafanaList[grafanaAnnotationQuery]{List: []grafanaAnnotationQuery{{
+			Name:       "Chart updates",
+			Datasource: grafanaDatasource{Type: "grafana", UID: "-- Grafana --"},
+			Enable:     true,
+			IconColor:  "purple",
+			Target:     map[string]any{"type": "tags", "tags": []string{notification.GrafanaAnnotationTag}, "limit": 100, "matchAny": false},
+		}}},
+		Templating: grafanaList[grafanaVariable]{List: []grafanaVariable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+			{
+				Name:       "project",
+				Label:      "Project",
+				Type:       "query",
+				Query:      "label_values(argazer_project_staleness_score, project)",
+				Datasource: &grafanaPrometheus,
+				Refresh:    2,
+				Multi:      true,
+				IncludeAll: true,
+				AllValue:   ".*",
+			},
+		}},
+	}
+
+	stat := func(title, description, expr, unit string, x int) grafanaPanel {
+		return grafanaPanel{
+			Type:        "stat",
+			Title:       title,
+			Description: description,
+			GridPos:     grafanaGridPos{X: x, Y: 0, W: 6, H: 4},
+			Targets:     []grafanaTarget{{Expr: expr, Instant: true}},
+			FieldConfig: map[string]any{"defaults": map[string]any{"unit": unit}},
+			Options:     map[string]any{"reduceOptions": map[string]any{"calcs": []string{"lastNotNull"}}},
+		}
+	}
+	timeseries := func(title, description, expr string, x int) grafanaPanel {
+		return grafanaPanel{
+			Type:        "timeseries",
+			Title:       title,
+			Description: description,
+			GridPos:     grafanaGridPos{X: x, Y: 4, W: 12, H: 9},
+			Targets:     []grafanaTarget{{Expr: expr, LegendFormat: "{{project}}"}},
+			FieldConfig: map[string]any{"defaults": map[string]any{"unit": "short", "min": 0}},
+			Options:     map[string]any{"legend": map[string]any{"displayMode": "table", "placement": "right", "calcs": []string{"lastNotNull"}}},
+		}
+	}
+
+	dashboard.Panels = []grafanaPanel{
+		stat("Outdated applications", "Applications with an update available", `sum(argazer_project_outdated_applications{project=~"$project"})`, "short", 0),
+		stat("Versions behind", "Releases behind, summed over the applications", `sum(argazer_project_versions_behind{project=~"$project"})`, "short", 6),
+		stat("Check errors", "Applications whose check failed in the last scan", `sum(argazer_check_errors)`, "short", 12),
+		stat("Last scan", "Time since the last scan completed", `time() - argazer_last_scan_timestamp_seconds`, "s", 18),
+		timeseries("Staleness score by project", "Staleness score summed over the project's applications", `argazer_project_staleness_score{project=~"$project"}`, 0),
+		timeseries("Outdated applications by project", "Applications of the project with an update available", `argazer_project_outdated_applications{project=~"$project"}`, 12),
+		{
+			Type:        "table",
+			Title:       "Stalest applications",
+			Description: "Applications with the highest staleness score of their pending update",
+			GridPos:     grafanaGridPos{X: 0, Y: 13, W: 24, H: 10},
+			Targets: []grafanaTarget{{
+				Expr:    `topk(20, argazer_application_staleness_score{project=~"$project"} > 0)`,
+				Instant: true,
+				Format:  "table",
+			}},
+			FieldConfig: map[string]any{"defaults": map[string]any{}},
+			Options:     map[string]any{"sortBy": []map[string]any{{"displayName": "Value", "desc": true}}},
+		},
+	}
+
+	for i := range dashboard.Panels {
+		panel := &dashboard.Panels[i]
+		panel.ID = i + 1
+		panel.Datasource = grafanaPrometheus
+		for j := range panel.Targets {
+			panel.Targets[j].RefID = string(rune('A' + j))
+			panel.Targets[j].Datasource = grafanaPrometheus
+		}
+	}
+	return dashboard
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGrafanaDashboard(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeGrafanaDashboard(&out, "fleet"))
+
+	var dashboard map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &dashboard))
+	assert.Equal(t, "fleet", dashboard["uid"])
+	assert.Equal(t, "Argazer", dashboard["title"])
+
+	annotations := dashboard["annotations"].(map[string]any)["list"].([]any)
+	require.Len(t, annotations, 1)
+	assert.Equal(t, []any{"argazer"}, annotations[0].(map[string]any)["target"].(map[string]any)["tags"])
+}
+
+func TestNewGrafanaDashboard_Metrics(t *testing.T) {
+	// Every metric queried by the dashboard is one argazer serves
+	var metrics strings.Builder
+	writeMetrics(&metrics, nil, time.Now())
+
+	dashboard := newGrafanaDashboard(grafanaDashboardUID)
+	metricName := regexp.MustCompile(`argazer_[a-z_]+`)
+	ids := map[int]bool{}
+	for _, panel := range dashboard.Panels {
+		assert.False(t, ids[panel.ID], "panel IDs are unique")
+		ids[panel.ID] = true
+		assert.Equal(t, grafanaPrometheus, panel.Datasource)
+		require.NotEmpty(t, panel.Targets, panel.Title)
+		for _, target := range panel.Targets {
+			assert.Equal(t, "A", target.RefID)
+			for _, name := range metricName.FindAllString(target.Expr, -1) {
+				assert.Contains(t, metrics.String(), "# TYPE "+name+" gauge", panel.Title)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+
+	"argazer/internal/config"
+	"argazer/internal/i18n"
+)
+
+// resultGroup holds the results of a report section sharing the same group_by value
+type resultGroup struct {
+	key     string // Empty for results without a value, e.g. the severity of an up-to-date application
+	results []ApplicationCheckResult
+}
+
+// groupResults splits the results of a report section by their group_by value, keeping their order
+// within a group. Groups are sorted by value, severities from major to patch, with the results
+// without a value last. Without grouping, all results form a single group.
+func groupResults(results []ApplicationCheckResult, groupBy string) []resultGroup {
+	if !groupingEnabled(groupBy) {
+		return []resultGroup{{results: results}}
+	}
+
+	var groups []resultGroup
+	positions := make(map[string]int)
+	for _, result := range results {
+		key := resultGroupKey(result, groupBy)
+		i, ok := positions[key]
+		if !ok {
+			i = len(groups)
+			positions[key] = i
+			groups = append(groups, resultGroup{key: key})
+		}
+		groups[i].results = append(groups[i].results, result)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groupKeyLess(groups[i].key, groups[j].key, groupBy)
+	})
+	return groups
+}
+
+// groupKeyLess orders group_by values: by name, severities from major to patch, and empty values last
+func groupKeyLess(a, b, groupBy string) bool {
+	if (a == "") != (b == "") {
+		return b == ""
+	}
+	if groupBy == config.GroupBySeverity {
+		return dashboardSeverityOrder[a] < dashboardSeverityOrder[b]
+	}
+	return a < b
+}
+
+// groupingEnabled reports whether a group_by value groups the reports
+func groupingEnabled(groupBy string) bool {
+	return groupBy != "" && groupBy != config.GroupByNone
+}
+
+// resultGroupKey returns the group_by value of a result
+// Projects are qualified by their ArgoCD instance, like in the other reports.
+func resultGroupKey(result ApplicationCheckResult, groupBy string) string {
+	switch groupBy {
+	case config.GroupByProject:
+		return instanceProject(result)
+	case config.GroupByRepo:
+		return result.RepoURL
+	case config.GroupByChart:
+		return result.ChartName
+	case config.GroupBySeverity:
+		return result.Severity
+	case config.GroupByCluster:
+		return result.Cluster
+	}
+	return ""
+}
+
+// groupHeading returns the heading of a group in the table and markdown reports, e.g. "Project: payments (3)";
+// empty without grouping
+func groupHeading(group resultGroup, groupBy string, tr *i18n.Localizer) string {
+	if !groupingEnabled(groupBy) {
+		return ""
+	}
+	key := group.key
+	if key == "" {
+		key = tr.T(i18n.GroupUnset)
+	}
+	return fmt.Sprintf("%s: %s (%d)", groupLabel(groupBy, tr), key, len(group.results))
+}
+
+// groupByLabels are the field labels naming the group_by values
+var groupByLabels = map[string]string{
+	config.GroupByProject:  i18n.FieldProject,
+	config.GroupByRepo:     i18n.FieldRepository,
+	config.GroupByChart:    i18n.FieldChart,
+	config.GroupBySeverity: i18n.FieldSeverity,
+	config.GroupByCluster:  i18n.FieldCluster,
+}
+
+// groupLabel returns the localized name of a group_by value, e.g. "Project"
+func groupLabel(groupBy string, tr *i18n.Localizer) string {
+	return tr.T(groupByLabels[groupBy])
+}
+
+// destinationCluster returns the cluster an application deploys to: its name, else its server URL
+func destinationCluster(app *v1alpha1.Application) string {
+	if app.Spec.Destination.Name != "" {
+		return app.Spec.Destination.Name
+	}
+	return app.Spec.Destination.Server
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/config"
+	"argazer/internal/i18n"
+)
+
+func TestGroupResults(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "web", Project: "frontend", Severity: "patch"},
+		{AppName: "queue", Project: "backend", Severity: "major", Instance: "prod"},
+		{AppName: "cache", Project: "backend", Severity: "major"},
+		{AppName: "db", Project: "backend"},
+	}
+
+	groups := groupResults(results, config.GroupByNone)
+	require.Len(t, groups, 1)
+	assert.Equal(t, results, groups[0].results)
+
+	groups = groupResults(results, config.GroupByProject)
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"backend", "frontend", "prod/backend"}, []string{groups[0].key, groups[1].key, groups[2].key})
+	assert.Equal(t, "cache", groups[0].results[0].AppName, "results keep their order within a group")
+	assert.Equal(t, "db", groups[0].results[1].AppName)
+
+	groups = groupResults(results, config.GroupBySeverity)
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"major", "patch", ""}, []string{groups[0].key, groups[1].key, groups[2].key}, "results without a value come last")
+	assert.Len(t, groups[0].results, 2)
+}
+
+func TestGroupHeading(t *testing.T) {
+	group := resultGroup{key: "https://charts.example.com", results: make([]ApplicationCheckResult, 2)}
+	assert.Equal(t, "Repository: https://charts.example.com (2)", groupHeading(group, config.GroupByRepo, i18n.New("en")))
+	assert.Equal(t, "Schweregrad: (keine) (1)", groupHeading(resultGroup{results: make([]ApplicationCheckResult, 1)}, config.GroupBySeverity, i18n.New("de")))
+	assert.Empty(t, groupHeading(group, config.GroupByNone, i18n.New("en")))
+}
+
+func TestRenderResults_GroupBy(t *testing.T) {
+	cat := processResults([]ApplicationCheckResult{
+		{AppName: "api", Project: "payments", Cluster: "prod-eu", ChartName: "api", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "web", Project: "frontend", Cluster: "prod-us", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		{AppName: "worker", Project: "payments", Cluster: "prod-eu", ChartName: "worker", CurrentVersion: "1.0.0", LatestVersion: "1.0.1", HasUpdate: true},
+	})
+	cat.layout.groupBy = config.GroupByCluster
+
+	var table bytes.Buffer
+	require.NoError(t, renderResults(cat, config.OutputFormatTable, i18n.New("en"), &table))
+	assert.Regexp(t, `(?s)== Cluster: prod-eu \(2\) ==\n\nAPPLICATION[^\n]*\napi\s[^\n]*\nworker\s[^\n]*\n\n== Cluster: prod-us \(1\) ==\n\nAPPLICATION[^\n]*\nweb\s`, table.String())
+
+	var markdown bytes.Buffer
+	require.NoError(t, renderResults(cat, config.OutputFormatMarkdown, i18n.New("en"), &markdown))
+	assert.Contains(t, markdown.String(), "### Cluster: prod-eu (2)\n\n#### api\n")
+	assert.Contains(t, markdown.String(), "### Cluster: prod-us (1)\n\n#### web\n")
+}
+
+func TestDestinationCluster(t *testing.T) {
+	app := &v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Destination: v1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc"}}}
+	assert.Equal(t, "https://kubernetes.default.svc", destinationCluster(app))
+
+	app.Spec.Destination.Name = "in-cluster"
+	assert.Equal(t, "in-cluster", destinationCluster(app))
+}
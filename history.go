@@ -0,0 +1,197 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"argazer/internal/config"
+	"argazer/internal/state"
+)
+
+// newDiffCmd creates the diff command
+func newDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff",
+		Short: "Show how the updates of the last scan differ from the previous scan",
+		Long: `Diff compares the last two scans recorded in the state file (see --history and
+--notify-only-new) and lists the updates that are new, still pending, or resolved since.
+It only reads the state file and doesn't contact ArgoCD.`,
+		RunE: runDiff,
+	}
+}
+
+// runDiff prints the difference between the last two recorded scans
+func runDiff(cmd *cobra.Command, args []string) error {
+	// ArgoCD settings aren't needed, so the configuration isn't validated
+	cfg, err := config.LoadUnvalidated()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	logger := setupLogging(cfg.Verbose, cfg.LogFormat)
+
+	if _, err := os.Stat(cfg.StateFile); err != nil {
+		return fmt.Errorf("no scan history in %s; run argazer with --history first", cfg.StateFile)
+	}
+	store, err := state.NewStore(cfg.StateFile, logger.WithField("component", "state"))
+	if err != nil {
+		return fmt.Errorf("failed to open state file: %w", err)
+	}
+
+	scans := store.Scans()
+	if len(scans) == 0 {
+		return fmt.Errorf("no scan history in %s; run argazer with --history first", cfg.StateFile)
+	}
+	current := scans[len(scans)-1]
+	var previous state.Scan
+	if len(scans) > 1 {
+		previous = scans[len(scans)-2]
+	}
+
+	return renderDiff(previous, current, state.DiffScans(previous, current), cfg.OutputFormat, cmd.OutOrStdout())
+}
+
+// scanDiffReport is the JSON output of the diff command
+type scanDiffReport struct {
+	Previous *time.Time `json:"previous,omitempty"` // Nil when only one scan is recorded
+	Current  time.Time  `json:"current"`
+	state.ScanDiff
+}
+
+// renderDiff writes a scan diff as JSON or as a table
+func renderDiff(previous, current state.Scan, diff state.ScanDiff, format string, w io.Writer) error {
+	if format == config.OutputFormatJSON {
+		report := scanDiffReport{Current: current.At, ScanDiff: diff}
+		if !previous.At.IsZero() {
+			report.Previous = &previous.At
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return nil
+	}
+
+	if previous.At.IsZero() {
+		fmt.Fprintf(w, "Only one scan recorded (%s); every update is new\n\n", current.At.Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(w, "Scan %s compared with %s\n\n", current.At.Format(time.RFC3339), previous.At.Format(time.RFC3339))
+	}
+
+	if len(diff.New) == 0 && len(diff.Resolved) == 0 {
+		fmt.Fprintf(w, "No changes (%d updates still pending)\n", len(diff.Unchanged))
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHANGE\tAPPLICATION\tCHART\tCURRENT\tLATEST")
+	for _, group := range []struct {
+		change  string
+		updates []state.ScannedUpdate
+	}{{"new", diff.New}, {"resolved", diff.Resolved}} {
+		for _, u := range group.updates {
+			app := u.AppName
+			if u.Namespace != "" {
+				app = u.Namespace + "/" + app
+			}
+			if u.Instance != "" {
+				app = u.Instance + ":" + app
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", group.change, app, u.ChartName, u.CurrentVersion, u.LatestVersion)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+	fmt.Fprintf(w, "\n%d new, %d resolved, %d still pending\n", len(diff.New), len(diff.Resolved), len(diff.Unchanged))
+	return nil
+}
+
+// recordScan adds the scan's updates to the history and returns how they differ from the previous scan
+// Applications that couldn't be checked keep their previous update, so a transient error doesn't make
+// an update look resolved and then new again.
+func recordScan(store *state.Store, results []ApplicationCheckResult, now time.Time) (state.ScanDiff, error) {
+	previous, _ := store.LastScan()
+
+	failed := make(map[string]bool)
+	current := state.Scan{At: now}
+	for _, result := range results {
+		if result.AppName == "" {
+			continue
+		}
+		if result.Error != "" {
+			failed[resultKey(result)] = true
+			continue
+		}
+		if result.HasUpdate {
+			current.Updates = append(current.Updates, state.ScannedUpdate{
+				AppName:        result.AppName,
+				Namespace:      result.Namespace,
+				Project:        result.Project,
+				Instance:       result.Instance,
+				ChartName:      result.ChartName,
+				CurrentVersion: result.CurrentVersion,
+				LatestVersion:  result.LatestVersion,
+			})
+		}
+	}
+	for _, update := range previous.Updates {
+		if failed[update.Instance+"/"+update.Namespace+"/"+update.AppName] {
+			current.Updates = append(current.Updates, update)
+		}
+	}
+	// Results arrive in completion order
+	slices.SortFunc(current.Updates, func(a, b state.ScannedUpdate) int {
+		return cmp.Or(cmp.Compare(a.Instance, b.Instance), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.AppName, b.AppName))
+	})
+
+	return state.DiffScans(previous, current), store.RecordScan(current)
+}
+
+// newUpdatesOnly leaves out the updates that were already available in the previous scan
+func newUpdatesOnly(results []ApplicationCheckResult, diff state.ScanDiff) []ApplicationCheckResult {
+	filtered := make([]ApplicationCheckResult, 0, len(results))
+	for _, result := range results {
+		if result.HasUpdate && !diff.IsNew(result.Instance, result.Namespace, result.AppName, result.LatestVersion) {
+			continue
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
+
+// trackHistory records the scan when the history is enabled and returns the results to notify, and
+// the results leaving out updates already available in the previous scan (nil without history)
+// With notify_only_new, the results to notify are the new updates. If the scan can't be recorded,
+// every update is new rather than risking silently dropping new ones.
+func trackHistory(store *state.Store, cfg *config.Config, results []ApplicationCheckResult, logger *logrus.Entry) (notify, newUpdates []ApplicationCheckResult) {
+	if store == nil || !cfg.RecordsHistory() {
+		return results, nil
+	}
+
+	diff, err := recordScan(store, results, time.Now())
+	if err != nil {
+		logger.WithError(err).Warn("Failed to record scan history")
+		return results, results
+	}
+	logger.WithFields(logrus.Fields{
+		"new":       len(diff.New),
+		"unchanged": len(diff.Unchanged),
+		"resolved":  len(diff.Resolved),
+	}).Info("Compared scan with the previous one")
+
+	newUpdates = newUpdatesOnly(results, diff)
+	if !cfg.NotifyOnlyNew {
+		return results, newUpdates
+	}
+	return newUpdates, newUpdates
+}
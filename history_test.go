@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/config"
+	"argazer/internal/state"
+)
+
+func TestTrackHistory(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "state.json"), logger)
+	require.NoError(t, err)
+	cfg := &config.Config{NotifyOnlyNew: true}
+
+	first := []ApplicationCheckResult{
+		{AppName: "frontend", Namespace: "argocd", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "api", Namespace: "argocd", CurrentVersion: "2.0.0", LatestVersion: "2.1.0", HasUpdate: true},
+		{AppName: "worker", Namespace: "argocd", CurrentVersion: "3.0.0", LatestVersion: "3.0.0"},
+	}
+	notified, newUpdates := trackHistory(store, cfg, first, logger)
+	assert.Equal(t, first, notified, "every update is new in the first scan")
+	assert.Equal(t, first, newUpdates)
+
+	second := []ApplicationCheckResult{
+		{AppName: "frontend", Namespace: "argocd", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "api", Namespace: "argocd", CurrentVersion: "2.0.0", Error: "timeout"},
+		{AppName: "worker", Namespace: "argocd", CurrentVersion: "3.0.0", LatestVersion: "3.1.0", HasUpdate: true},
+	}
+	notified, newUpdates = trackHistory(store, cfg, second, logger)
+	require.Len(t, notified, 2)
+	assert.Equal(t, "api", notified[0].AppName, "results without updates are kept")
+	assert.Equal(t, "worker", notified[1].AppName)
+	assert.Equal(t, notified, newUpdates)
+
+	// The failed application keeps its update instead of being resolved
+	scans := store.Scans()
+	require.Len(t, scans, 2)
+	diff := state.DiffScans(scans[0], scans[1])
+	assert.Empty(t, diff.Resolved)
+	require.Len(t, scans[1].Updates, 3)
+	assert.Equal(t, "api", scans[1].Updates[0].AppName, "updates are sorted by application")
+
+	// With history only, every update is notified
+	third := []ApplicationCheckResult{
+		{AppName: "frontend", Namespace: "argocd", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "worker", Namespace: "argocd", CurrentVersion: "3.0.0", LatestVersion: "3.2.0", HasUpdate: true},
+	}
+	notified, newUpdates = trackHistory(store, &config.Config{History: true}, third, logger)
+	assert.Equal(t, third, notified)
+	require.Len(t, newUpdates, 1)
+	assert.Equal(t, "worker", newUpdates[0].AppName, "a newer release is a new update")
+
+	// Without notify_only_new, history or Grafana annotations nothing is recorded
+	notified, newUpdates = trackHistory(store, &config.Config{}, second, logger)
+	assert.Equal(t, second, notified)
+	assert.Nil(t, newUpdates)
+	assert.Len(t, store.Scans(), 3)
+}
+
+func TestRenderDiff(t *testing.T) {
+	previous := state.Scan{At: time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)}
+	current := state.Scan{At: time.Date(2025, 3, 2, 8, 0, 0, 0, time.UTC)}
+	diff := state.ScanDiff{
+		New:       []state.ScannedUpdate{{AppName: "redis", Namespace: "argocd", ChartName: "redis", CurrentVersion: "17.0.0", LatestVersion: "18.0.0"}},
+		Resolved:  []state.ScannedUpdate{{AppName: "nginx", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"}},
+		Unchanged: []state.ScannedUpdate{{AppName: "api"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderDiff(previous, current, diff, config.OutputFormatTable, &buf))
+	out := buf.String()
+	assert.Contains(t, out, "Scan 2025-03-02T08:00:00Z compared with 2025-03-01T08:00:00Z")
+	assert.Regexp(t, `new\s+argocd/redis\s+redis\s+17\.0\.0\s+18\.0\.0`, out)
+	assert.Regexp(t, `resolved\s+nginx\s+nginx\s+1\.0\.0\s+1\.1\.0`, out)
+	assert.Contains(t, out, "1 new, 1 resolved, 1 still pending")
+
+	buf.Reset()
+	require.NoError(t, renderDiff(state.Scan{}, current, diff, config.OutputFormatJSON, &buf))
+	var report map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.NotContains(t, report, "previous")
+	assert.Len(t, report["new"], 1)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/config"
+	"argazer/internal/i18n"
+)
+
+// applyIgnoreRules moves available updates matching an active ignore rule out of the updates:
+// HasUpdate is cleared so they are neither counted, notified nor reflected in exit codes, and
+// IgnoredBy reports the rule. Expired rules are logged once and otherwise left alone.
+func applyIgnoreRules(results []ApplicationCheckResult, rules []config.IgnoreRule, now time.Time, logger *logrus.Entry) {
+	active := make([]config.IgnoreRule, 0, len(rules))
+	expiries := make([]time.Time, 0, len(rules))
+	for _, rule := range rules {
+		// Rules are validated when the configuration is loaded
+		expiry, _ := rule.Expiry()
+		if !expiry.IsZero() && !now.Before(expiry) {
+			logger.WithFields(logrus.Fields{"rule": describeIgnoreRule(rule), "until": rule.Until}).Info("Ignore rule has expired")
+			continue
+		}
+		active = append(active, rule)
+		expiries = append(expiries, expiry)
+	}
+
+	for i := range results {
+		result := &results[i]
+		if !result.HasUpdate || result.Error != "" {
+			continue
+		}
+		for j, rule := range active {
+			if !ignoreRuleMatches(rule, *result) {
+				continue
+			}
+
+			result.HasUpdate = false
+			result.IgnoredBy = rule.Reason
+			if result.IgnoredBy == "" {
+				result.IgnoredBy = describeIgnoreRule(rule)
+			}
+			if !expiries[j].IsZero() {
+				result.IgnoredUntil = expiries[j].UTC().Format(time.RFC3339)
+			}
+			logger.WithFields(logrus.Fields{
+				"app_name":       result.AppName,
+				"latest_version": result.LatestVersion,
+				"ignored_by":     result.IgnoredBy,
+			}).Info("Update ignored by rule")
+			break
+		}
+	}
+}
+
+// ignoreRuleMatches reports whether a rule matches a result: every field set in the rule must match
+// The version pattern is matched against the latest version within the constraint.
+func ignoreRuleMatches(rule config.IgnoreRule, result ApplicationCheckResult) bool {
+	if !globMatches(rule.App, result.AppName) || !globMatches(rule.Chart, result.ChartName) || !globMatches(rule.Version, result.LatestVersion) {
+		return false
+	}
+	if rule.Repo != "" {
+		prefix, repo := normalizeRepoURL(rule.Repo), normalizeRepoURL(result.RepoURL)
+		if repo != prefix && !strings.HasPrefix(repo, prefix+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// globMatches reports whether a value matches a glob, an empty glob matching everything
+func globMatches(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+// normalizeRepoURL strips the scheme, trailing slashes and ".git" so repository URLs compare by location
+func normalizeRepoURL(repoURL string) string {
+	if _, rest, ok := strings.Cut(repoURL, "://"); ok {
+		repoURL = rest
+	}
+	return strings.TrimSuffix(strings.TrimRight(repoURL, "/"), ".git")
+}
+
+// describeIgnoreRule summarizes the fields a rule matches on, for rules without a reason
+func describeIgnoreRule(rule config.IgnoreRule) string {
+	var parts []string
+	for _, field := range []struct{ key, value string }{
+		{"app", rule.App},
+		{"chart", rule.Chart},
+		{"repo", rule.Repo},
+		{"version", rule.Version},
+	} {
+		if field.value != "" {
+			parts = append(parts, field.key+"="+field.value)
+		}
+	}
+	return "ignore " + strings.Join(parts, " ")
+}
+
+// formatIgnoredBy returns why an update is ignored, with the date the rule expires
+func formatIgnoredBy(result ApplicationCheckResult, tr *i18n.Localizer) string {
+	if result.IgnoredUntil == "" {
+		return result.IgnoredBy
+	}
+	until, err := time.Parse(time.RFC3339, result.IgnoredUntil)
+	if err != nil {
+		return result.IgnoredBy
+	}
+	// The expiry is the end of the last ignored day
+	return tr.T(i18n.IgnoredUntil, result.IgnoredBy, until.Add(-time.Second).Format(time.DateOnly))
+}
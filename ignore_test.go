@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/config"
+)
+
+func TestApplyIgnoreRules(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	update := ApplicationCheckResult{
+		AppName:        "team-a-redis",
+		ChartName:      "redis",
+		RepoURL:        "oci://registry.example.com/charts/bitnami",
+		CurrentVersion: "17.0.0",
+		LatestVersion:  "18.1.0",
+		HasUpdate:      true,
+	}
+
+	tests := []struct {
+		name          string
+		rule          config.IgnoreRule
+		expectIgnored bool
+		expectedBy    string
+		expectedUntil string
+	}{
+		{name: "app glob", rule: config.IgnoreRule{App: "team-a-*"}, expectIgnored: true, expectedBy: "ignore app=team-a-*"},
+		{name: "other app", rule: config.IgnoreRule{App: "team-b-*"}},
+		{name: "chart and version", rule: config.IgnoreRule{Chart: "redis", Version: "18.*", Reason: "18.x breaks persistence"}, expectIgnored: true, expectedBy: "18.x breaks persistence"},
+		{name: "other version", rule: config.IgnoreRule{Chart: "redis", Version: "19.*"}},
+		{name: "repository prefix", rule: config.IgnoreRule{Repo: "registry.example.com/charts/"}, expectIgnored: true, expectedBy: "ignore repo=registry.example.com/charts/"},
+		{name: "repository name prefix only", rule: config.IgnoreRule{Repo: "oci://registry.example.com/chart"}},
+		{name: "until date", rule: config.IgnoreRule{App: "team-a-redis", Until: "2025-06-15"}, expectIgnored: true, expectedBy: "ignore app=team-a-redis", expectedUntil: "2025-06-16T00:00:00Z"},
+		{name: "expired", rule: config.IgnoreRule{App: "team-a-redis", Until: "2025-06-14"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := []ApplicationCheckResult{update}
+			applyIgnoreRules(results, []config.IgnoreRule{tt.rule}, now, logrus.NewEntry(logrus.New()))
+
+			assert.Equal(t, !tt.expectIgnored, results[0].HasUpdate)
+			assert.Equal(t, tt.expectedBy, results[0].IgnoredBy)
+			assert.Equal(t, tt.expectedUntil, results[0].IgnoredUntil)
+		})
+	}
+}
+
+func TestApplyIgnoreRules_OnlyUpdates(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "current", LatestVersion: "1.0.0"},
+		{AppName: "failed", Error: "timeout"},
+	}
+	applyIgnoreRules(results, []config.IgnoreRule{{App: "*"}}, time.Now(), logrus.NewEntry(logrus.New()))
+
+	assert.Empty(t, results[0].IgnoredBy)
+	assert.Empty(t, results[1].IgnoredBy)
+}
+
+func TestProcessResults_Ignored(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "ignored", ChartName: "redis", CurrentVersion: "17.0.0", LatestVersion: "18.0.0", IgnoredBy: "known bad", IgnoredUntil: "2025-07-01T00:00:00Z"},
+		{AppName: "outdated", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+	}
+
+	cat := processResults(results)
+	assert.Equal(t, 2, cat.stats.total)
+	assert.Equal(t, 1, cat.stats.ignored)
+	assert.Equal(t, 1, cat.stats.updates)
+	assert.Equal(t, 0, cat.stats.upToDate)
+	require.Len(t, cat.ignored, 1)
+	assert.Equal(t, "ignored", cat.ignored[0].AppName)
+	assert.Equal(t, exitCodeUpdates, scanExitCode(results))
+
+	var buf bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &buf))
+	assert.Contains(t, buf.String(), "UPDATES IGNORED BY RULES:")
+	assert.Contains(t, buf.String(), "Reason: known bad (until 2025-06-30)")
+
+	buf.Reset()
+	require.NoError(t, outputResults(results, "json", nil, &buf))
+	assert.Contains(t, buf.String(), `"ignored": 1`)
+	assert.Contains(t, buf.String(), `"ignored_by": "known bad"`)
+
+	buf.Reset()
+	require.NoError(t, outputResults(results, "markdown-compact", nil, &buf))
+	assert.Contains(t, buf.String(), "Updates Ignored by Rules (1)")
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/argocd"
+	"argazer/internal/helm"
+)
+
+// ImageCheckResult is the tag check of one container image of an application
+type ImageCheckResult struct {
+	Image                      string `json:"image"` // Image without tag, e.g. "docker.io/library/nginx"
+	CurrentTag                 string `json:"current_tag"`
+	LatestTag                  string `json:"latest_tag,omitempty"`     // Latest tag within the constraint
+	LatestTagAll               string `json:"latest_tag_all,omitempty"` // Latest tag without constraint (if different)
+	HasUpdate                  bool   `json:"has_update"`
+	HasUpdateOutsideConstraint bool   `json:"has_update_outside_constraint,omitempty"`
+	Error                      string `json:"error,omitempty"`
+}
+
+// imageUpdate is a container image with a newer tag, with the application running it
+type imageUpdate struct {
+	AppName   string `json:"app_name"`
+	Namespace string `json:"namespace,omitempty"`
+	Project   string `json:"project"`
+	Instance  string `json:"instance,omitempty"`
+	URL       string `json:"url,omitempty"`
+	ImageCheckResult
+}
+
+// imageTagLookup returns the latest tag of an image within a version constraint
+type imageTagLookup func(ctx context.Context, image helm.ImageReference, constraint string) (*helm.VersionConstraintResult, error)
+
+// checkImages checks the container images of checked applications for newer tags, using each
+// application's version constraint
+// Semver ranges only apply to chart versions, so images of applications constrained by one are checked
+// against all tags. Images referenced by digest or a non-version tag (e.g. "latest") are skipped. Each
+// image, tag and constraint is looked up once, with up to concurrency lookups in flight.
+func checkImages(ctx context.Context, apps []*v1alpha1.Application, results []ApplicationCheckResult, lookup imageTagLookup, concurrency int, logger *logrus.Entry) {
+	type imageCheck struct {
+		image      helm.ImageReference
+		constraint string
+	}
+	type resultImage struct {
+		result, check int
+	}
+
+	appsByName := make(map[string]*v1alpha1.Application, len(apps))
+	for _, app := range apps {
+		appsByName[applicationKey(app)] = app
+	}
+
+	checkIndex := make(map[imageCheck]int)
+	var checks []imageCheck
+	var resultImages []resultImage
+	for i, result := range results {
+		// Teams opting out with the annotation don't want their images checked either
+		if result.AppName == "" || result.IgnoredBy == ignoreAnnotation+" annotation" {
+			continue
+		}
+		app, ok := appsByName[resultKey(result)]
+		if !ok {
+			continue
+		}
+
+		constraint := result.ConstraintApplied
+		if !helm.IsConstraintKeyword(constraint) {
+			constraint = "major"
+		}
+		// The same image may be listed with and without its registry
+		seen := make(map[int]bool)
+		for _, raw := range argocd.ApplicationImages(app) {
+			image, err := helm.ParseImageReference(raw)
+			if err != nil || !isVersionTag(image.Tag) {
+				logger.WithFields(logrus.Fields{"app_name": result.AppName, "image": raw}).Debug("Skipping image without a version tag")
+				continue
+			}
+
+			check := imageCheck{image, constraint}
+			index, ok := checkIndex[check]
+			if !ok {
+				index = len(checks)
+				checkIndex[check] = index
+				checks = append(checks, check)
+			}
+			if !seen[index] {
+				seen[index] = true
+				resultImages = append(resultImages, resultImage{i, index})
+			}
+		}
+	}
+	if len(checks) == 0 {
+		return
+	}
+
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	checked := make([]ImageCheckResult, len(checks))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			checked[i] = checkImage(ctx, check.image, check.constraint, lookup, logger)
+		}()
+	}
+	wg.Wait()
+
+	for _, ri := range resultImages {
+		results[ri.result].Images = append(results[ri.result].Images, checked[ri.check])
+	}
+}
+
+// checkImage looks up the latest tag of one image
+func checkImage(ctx context.Context, image helm.ImageReference, constraint string, lookup imageTagLookup, logger *logrus.Entry) ImageCheckResult {
+	result := ImageCheckResult{Image: image.Name(), CurrentTag: image.Tag}
+	imageLogger := logger.WithFields(logrus.Fields{"image": image.Name(), "tag": image.Tag})
+
+	latest, err := lookup(ctx, image, constraint)
+	if err != nil {
+		result.Error = err.Error()
+		imageLogger.WithError(err).Warn("Failed to check image tags")
+		return result
+	}
+
+	result.LatestTag = latest.LatestVersion
+	result.HasUpdate = latest.LatestVersion != image.Tag
+	result.HasUpdateOutsideConstraint = latest.HasUpdateOutsideConstraint
+	if latest.LatestVersionAll != latest.LatestVersion {
+		result.LatestTagAll = latest.LatestVersionAll
+	}
+	if result.HasUpdate {
+		imageLogger.WithField("latest_tag", result.LatestTag).Info("Image update available")
+	}
+	return result
+}
+
+// isVersionTag reports whether an image tag is a version rather than a mutable tag or none
+func isVersionTag(tag string) bool {
+	_, err := semver.NewVersion(tag)
+	return err == nil
+}
+
+// imageUpdates lists the images with a newer tag, by application
+func imageUpdates(results []ApplicationCheckResult) []imageUpdate {
+	var updates []imageUpdate
+	for _, result := range results {
+		for _, image := range result.Images {
+			if !image.HasUpdate {
+				continue
+			}
+			updates = append(updates, imageUpdate{
+				AppName:          result.AppName,
+				Namespace:        result.Namespace,
+				Project:          result.Project,
+				Instance:         result.Instance,
+				URL:              result.URL,
+				ImageCheckResult: image,
+			})
+		}
+	}
+	return updates
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"argazer/internal/helm"
+)
+
+func TestCheckImages(t *testing.T) {
+	app := func(name string, images ...string) *v1alpha1.Application {
+		a := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "argocd"}}
+		a.Status.Summary.Images = images
+		return a
+	}
+	apps := []*v1alpha1.Application{
+		app("web", "nginx:1.25.3", "docker.io/library/nginx:1.25.3", "busybox:latest"),
+		app("api", "nginx:1.25.3", "ghcr.io/org/api:2.0.0"),
+		app("pinned", "nginx:1.25.3"),
+	}
+	results := []ApplicationCheckResult{
+		{AppName: "web", Namespace: "argocd", ConstraintApplied: "major"},
+		{AppName: "api", Namespace: "argocd", ConstraintApplied: ">=1.0.0 <3.0.0"},
+		{AppName: "pinned", Namespace: "argocd", ConstraintApplied: "patch"},
+	}
+
+	var mu sync.Mutex
+	var lookups []string
+	lookup := func(ctx context.Context, image helm.ImageReference, constraint string) (*helm.VersionConstraintResult, error) {
+		mu.Lock()
+		lookups = append(lookups, image.String()+" "+constraint)
+		mu.Unlock()
+		switch {
+		case image.Repository == "org/api":
+			return nil, errors.New("registry unavailable")
+		case constraint == "patch":
+			return &helm.VersionConstraintResult{LatestVersion: "1.25.4", LatestVersionAll: "1.27.0", HasUpdateOutsideConstraint: true}, nil
+		}
+		return &helm.VersionConstraintResult{LatestVersion: "1.27.0", LatestVersionAll: "1.27.0"}, nil
+	}
+
+	checkImages(context.Background(), apps, results, lookup, 2, logrus.NewEntry(logrus.New()))
+
+	assert.ElementsMatch(t, []string{
+		"docker.io/library/nginx:1.25.3 major",
+		"ghcr.io/org/api:2.0.0 major",
+		"docker.io/library/nginx:1.25.3 patch",
+	}, lookups, "each image and constraint is looked up once, ranges fall back to major")
+
+	assert.Equal(t, []ImageCheckResult{{Image: "docker.io/library/nginx", CurrentTag: "1.25.3", LatestTag: "1.27.0", HasUpdate: true}}, results[0].Images)
+	require.Len(t, results[1].Images, 2)
+	assert.Equal(t, "registry unavailable", results[1].Images[1].Error)
+	assert.Equal(t, []ImageCheckResult{{
+		Image: "docker.io/library/nginx", CurrentTag: "1.25.3", LatestTag: "1.25.4", LatestTagAll: "1.27.0",
+		HasUpdate: true, HasUpdateOutsideConstraint: true,
+	}}, results[2].Images)
+}
+
+func TestOutputResults_ImageUpdates(t *testing.T) {
+	results := []ApplicationCheckResult{{
+		AppName:        "web",
+		Project:        "default",
+		CurrentVersion: "1.0.0",
+		LatestVersion:  "1.0.0",
+		Images: []ImageCheckResult{
+			{Image: "docker.io/library/nginx", CurrentTag: "1.25.3", LatestTag: "1.25.4", LatestTagAll: "1.27.0", HasUpdate: true, HasUpdateOutsideConstraint: true},
+			{Image: "docker.io/library/redis", CurrentTag: "7.2.4", LatestTag: "7.2.4"},
+		},
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Contains(t, table.String(), "Image updates available: 1\n")
+	assert.Contains(t, table.String(), "CONTAINER IMAGES WITH UPDATES AVAILABLE:")
+	assert.Regexp(t, `APPLICATION\s+PROJECT\s+IMAGE\s+CURRENT TAG\s+LATEST TAG\s+NOTE\nweb\s+default\s+docker.io/library/nginx\s+1.25.3\s+1.25.4\s+Version 1.27.0 available outside constraint\n`, table.String())
+	assert.NotContains(t, table.String(), "redis")
+
+	var md bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &md))
+	assert.Contains(t, md.String(), "## Container Images with Updates Available\n\n")
+	assert.Contains(t, md.String(), "| web | docker.io/library/nginx | 1.25.3 | 1.25.4 |\n")
+
+	var out bytes.Buffer
+	require.NoError(t, outputResults(results, "json", nil, &out))
+	assert.Contains(t, out.String(), `"image_updates": 1`)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/argocd"
+	"argazer/internal/config"
+)
+
+// instanceAnnotation records the ArgoCD instance an application was listed from when argocd_instances is
+// configured. Argazer sets it on the applications it reads; it's never written back to ArgoCD.
+const instanceAnnotation = "argazer.io/instance"
+
+// argocdInstance holds the ArgoCD clients of one ArgoCD server
+type argocdInstance struct {
+	name          string                    // Name in argocd_instances, empty for argocd_url
+	cfg           *config.Config            // Configuration with the instance's connection settings
+	argocd        *argocd.Client            // Username/password client, nil when only project tokens are configured
+	projectArgocd map[string]*argocd.Client // Project-scoped clients, keyed by project name
+}
+
+// instanceConfigs returns the configuration of each ArgoCD instance to scan, keyed by name in order:
+// the argocd_instances, or argocd_url as a single unnamed instance
+func instanceConfigs(cfg *config.Config) ([]string, []*config.Config) {
+	if len(cfg.ArgocdInstances) == 0 {
+		return []string{""}, []*config.Config{cfg}
+	}
+
+	names := make([]string, 0, len(cfg.ArgocdInstances))
+	configs := make([]*config.Config, 0, len(cfg.ArgocdInstances))
+	for _, instance := range cfg.ArgocdInstances {
+		names = append(names, instance.Name)
+		configs = append(configs, cfg.InstanceConfig(instance))
+	}
+	return names, configs
+}
+
+// newArgocdInstance creates the ArgoCD clients of an instance: the API client, or one reading the
+// Application resources directly in Kubernetes mode, and a client per project token
+func newArgocdInstance(name string, cfg *config.Config, logger *logrus.Entry) (*argocdInstance, error) {
+	instance := &argocdInstance{name: name, cfg: cfg}
+	if name != "" {
+		logger = logger.WithField("instance", name)
+	}
+
+	if cfg.Mode == config.ModeKubernetes {
+		argoClient, err := argocd.NewKubernetesClient(cfg.ArgocdNamespace, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ArgoCD client: %w", err)
+		}
+		instance.argocd = argoClient
+	} else if cfg.ArgocdUsername != "" {
+		argoClient, err := argocd.NewClient(cfg.ArgocdURL, cfg.ArgocdUsername, cfg.ArgocdPassword, cfg.ArgocdInsecure, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ArgoCD client: %w", err)
+		}
+		instance.argocd = argoClient
+	}
+
+	if len(cfg.ArgocdProjectTokens) > 0 {
+		instance.projectArgocd = make(map[string]*argocd.Client, len(cfg.ArgocdProjectTokens))
+		for project, token := range cfg.ArgocdProjectTokens {
+			projectClient, err := argocd.NewClientWithToken(cfg.ArgocdURL, token, cfg.ArgocdInsecure, logger.WithField("project", project))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create ArgoCD client for project %s: %w", project, err)
+			}
+			instance.projectArgocd[project] = projectClient
+		}
+	}
+
+	return instance, nil
+}
+
+// listInstanceApplications lists the applications of every ArgoCD instance concurrently
+// A single instance's error is returned as is. With several instances, a failing instance is logged and
+// skipped so one unreachable server doesn't hide the others' results; the scan only fails if all do.
+func listInstanceApplications(ctx context.Context, instances []*argocdInstance, logger *logrus.Entry) ([]*v1alpha1.Application, error) {
+	if len(instances) == 1 {
+		return instances[0].listApplications(ctx, logger)
+	}
+
+	instanceApps := make([][]*v1alpha1.Application, len(instances))
+	instanceErrs := make([]error, len(instances))
+
+	var wg sync.WaitGroup
+	for i, instance := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instanceApps[i], instanceErrs[i] = instance.listApplications(ctx, logger.WithField("instance", instance.name))
+		}()
+	}
+	wg.Wait()
+
+	var apps []*v1alpha1.Application
+	failed := 0
+	for i, instance := range instances {
+		if instanceErrs[i] != nil {
+			failed++
+			logger.WithError(instanceErrs[i]).WithField("instance", instance.name).Error("Failed to list applications of ArgoCD instance")
+			continue
+		}
+		apps = append(apps, instanceApps[i]...)
+	}
+	if failed == len(instances) {
+		return nil, fmt.Errorf("failed to list applications of every ArgoCD instance: %w", instanceErrs[0])
+	}
+	return apps, nil
+}
+
+// listApplications lists the instance's applications, marking them with the instance's name
+// With project tokens, each project is listed concurrently with its own client. A failing scope is
+// logged and skipped so one expired token doesn't hide every other project's results.
+func (i *argocdInstance) listApplications(ctx context.Context, logger *logrus.Entry) ([]*v1alpha1.Application, error) {
+	scopes, uncovered := scanScopes(i.cfg, i.argocd != nil)
+	if len(uncovered) > 0 {
+		logger.WithField("projects", uncovered).Warn("No ArgoCD token configured for projects, skipping them")
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("no ArgoCD client is configured for the selected projects")
+	}
+
+	scopeApps := make([][]*v1alpha1.Application, len(scopes))
+	scopeErrs := make([]error, len(scopes))
+
+	var wg sync.WaitGroup
+	for s, scope := range scopes {
+		client := i.argocd
+		if scope.project != "" {
+			client = i.projectArgocd[scope.project]
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scopeApps[s], scopeErrs[s] = listScopeApplications(ctx, client, scope)
+		}()
+	}
+	wg.Wait()
+
+	var apps []*v1alpha1.Application
+	failed := 0
+	for s, scope := range scopes {
+		if scopeErrs[s] != nil {
+			failed++
+			logger.WithError(scopeErrs[s]).WithField("project", scope.project).Error("Failed to list applications")
+			continue
+		}
+		apps = append(apps, scopeApps[s]...)
+	}
+	if failed == len(scopes) {
+		return nil, fmt.Errorf("failed to list applications: %w", scopeErrs[0])
+	}
+
+	for _, app := range apps {
+		setApplicationInstance(app, i.name)
+	}
+	return apps, nil
+}
+
+// setApplicationInstance marks an application with the name of the ArgoCD instance it was read from
+// Applications of argocd_url aren't marked, and lose a copy of the annotation from their manifest.
+func setApplicationInstance(app *v1alpha1.Application, name string) {
+	if name == "" {
+		delete(app.Annotations, instanceAnnotation)
+		return
+	}
+	if app.Annotations == nil {
+		app.Annotations = make(map[string]string)
+	}
+	app.Annotations[instanceAnnotation] = name
+}
+
+// applicationInstance returns the name of the ArgoCD instance an application was read from, empty for argocd_url
+func applicationInstance(app *v1alpha1.Application) string {
+	return app.Annotations[instanceAnnotation]
+}
+
+// applicationKey identifies an application across ArgoCD instances
+func applicationKey(app *v1alpha1.Application) string {
+	return applicationInstance(app) + "/" + app.Namespace + "/" + app.Name
+}
+
+// resultKey identifies the application of a check result, matching its applicationKey
+func resultKey(result ApplicationCheckResult) string {
+	return result.Instance + "/" + result.Namespace + "/" + result.AppName
+}
+
+// instanceProject returns the project of a result, prefixed by its instance when several are scanned
+func instanceProject(result ApplicationCheckResult) string {
+	if result.Instance == "" {
+		return result.Project
+	}
+	return result.Instance + "/" + result.Project
+}
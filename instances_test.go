@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"argazer/internal/config"
+)
+
+func TestInstanceConfigs(t *testing.T) {
+	t.Run("argocd_url", func(t *testing.T) {
+		cfg := &config.Config{ArgocdURL: "https://argocd.example.com"}
+		names, configs := instanceConfigs(cfg)
+		assert.Equal(t, []string{""}, names)
+		assert.Equal(t, []*config.Config{cfg}, configs)
+	})
+
+	t.Run("argocd_instances", func(t *testing.T) {
+		cfg := &config.Config{
+			Projects: []string{"*"},
+			ArgocdInstances: []config.ArgocdInstance{
+				{Name: "production", URL: "https://argocd.prod.example.com", Username: "admin", Password: "secret"},
+				{Name: "staging", URL: "https://argocd.staging.example.com", ProjectTokens: map[string]string{"team-a": "token"}},
+			},
+		}
+		names, configs := instanceConfigs(cfg)
+		assert.Equal(t, []string{"production", "staging"}, names)
+		require.Len(t, configs, 2)
+		assert.Equal(t, "https://argocd.prod.example.com", configs[0].ArgocdURL)
+		assert.Equal(t, "admin", configs[0].ArgocdUsername)
+		assert.Equal(t, map[string]string{"team-a": "token"}, configs[1].ArgocdProjectTokens)
+		assert.Equal(t, []string{"*"}, configs[1].Projects, "filters are shared by all instances")
+	})
+}
+
+func TestApplicationInstance(t *testing.T) {
+	app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "argocd"}}
+	setApplicationInstance(app, "")
+	assert.Nil(t, app.Annotations, "applications of argocd_url aren't marked")
+	assert.Equal(t, "/argocd/web", applicationKey(app))
+
+	setApplicationInstance(app, "production")
+	assert.Equal(t, "production", applicationInstance(app))
+	assert.Equal(t, "production/argocd/web", applicationKey(app))
+	assert.Equal(t, applicationKey(app), resultKey(ApplicationCheckResult{AppName: "web", Namespace: "argocd", Instance: "production"}))
+}
+
+func TestAnnotateSyncWindows_Instances(t *testing.T) {
+	app := func(instance string) *v1alpha1.Application {
+		a := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Spec: v1alpha1.ApplicationSpec{Project: "default"}}
+		setApplicationInstance(a, instance)
+		return a
+	}
+	apps := []*v1alpha1.Application{app("production"), app("staging")}
+	results := []ApplicationCheckResult{
+		{AppName: "web", Project: "default", Instance: "production", HasUpdate: true},
+		{AppName: "web", Project: "default", Instance: "staging", HasUpdate: true},
+	}
+
+	var calls []string
+	windowsFor := func(_ context.Context, instance, project string) (v1alpha1.SyncWindows, error) {
+		calls = append(calls, instance+"/"+project)
+		if instance == "staging" {
+			return nil, nil
+		}
+		return v1alpha1.SyncWindows{&v1alpha1.SyncWindow{Kind: "deny", Schedule: "0 22 * * *", Duration: "8h", Applications: []string{"*"}}}, nil
+	}
+
+	annotateSyncWindows(context.Background(), apps, results, windowsFor, time.Date(2024, 1, 10, 23, 0, 0, 0, time.UTC), logrus.NewEntry(logrus.New()))
+
+	assert.Equal(t, []string{"production/default", "staging/default"}, calls, "projects of each instance are read separately")
+	assert.True(t, results[0].SyncBlocked)
+	assert.False(t, results[1].SyncBlocked)
+}
+
+func TestOutputResults_Instance(t *testing.T) {
+	results := []ApplicationCheckResult{{
+		AppName:        "web",
+		Project:        "default",
+		Instance:       "production",
+		ChartName:      "nginx",
+		CurrentVersion: "1.0.0",
+		LatestVersion:  "1.1.0",
+		HasUpdate:      true,
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Regexp(t, `\nproduction:web\s+default\s+nginx\s+1.0.0\s+1.1.0\s`, table.String())
+
+	var md bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &md))
+	assert.Contains(t, md.String(), "| **Instance** | production |\n")
+
+	var out bytes.Buffer
+	require.NoError(t, outputResults(results, "json", nil, &out))
+	assert.Contains(t, out.String(), `"instance": "production"`)
+
+	updates := toApplicationUpdates(results)
+	require.Len(t, updates, 1)
+	assert.Equal(t, "production", updates[0].Instance)
+}
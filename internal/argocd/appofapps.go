@@ -0,0 +1,94 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"argazer/internal/kube"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// applicationKind is the kind of Application resources
+const applicationKind = "Application"
+
+// applicationRef identifies a child Application resource
+type applicationRef struct {
+	namespace string
+	name      string
+}
+
+// ChildApplications returns the Applications an app-of-apps application manages
+// Children are read from the application's resource tree; in Kubernetes mode, where there is no
+// resource tree, from the resources listed in its status. This requires `applications, get` in the
+// ArgoCD RBAC policy for the children, or `get` on applications in Kubernetes mode.
+func (c *Client) ChildApplications(ctx context.Context, app *v1alpha1.Application) ([]*v1alpha1.Application, error) {
+	refs, err := c.childApplicationRefs(ctx, app)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resources of application %s: %w", app.Name, err)
+	}
+
+	children := make([]*v1alpha1.Application, 0, len(refs))
+	for _, ref := range refs {
+		child, err := c.getApplication(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get child application %s of %s: %w", ref.name, app.Name, err)
+		}
+		children = append(children, child)
+	}
+
+	c.logger.WithField("app", app.Name).WithField("children", len(children)).Debug("Loaded child applications")
+	return children, nil
+}
+
+// childApplicationRefs lists the Application resources among an application's resources
+func (c *Client) childApplicationRefs(ctx context.Context, app *v1alpha1.Application) ([]applicationRef, error) {
+	var refs []applicationRef
+	add := func(group, kind, namespace, name string) {
+		if group != resourceGroup || kind != applicationKind {
+			return
+		}
+		// Application resources without a namespace live in ArgoCD's namespace, like the parent
+		if namespace == "" {
+			namespace = app.Namespace
+		}
+		refs = append(refs, applicationRef{namespace: namespace, name: name})
+	}
+
+	if c.kube != nil {
+		for _, resource := range app.Status.Resources {
+			add(resource.Group, resource.Kind, resource.Namespace, resource.Name)
+		}
+		return refs, nil
+	}
+
+	name, namespace := app.Name, app.Namespace
+	tree, err := c.appClient.ResourceTree(ctx, &application.ResourcesQuery{
+		ApplicationName: &name,
+		AppNamespace:    &namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range tree.Nodes {
+		add(node.Group, node.Kind, node.Namespace, node.Name)
+	}
+	return refs, nil
+}
+
+// getApplication reads a single Application
+func (c *Client) getApplication(ctx context.Context, ref applicationRef) (*v1alpha1.Application, error) {
+	if c.kube != nil {
+		var app v1alpha1.Application
+		path := kube.ResourcePath(resourceGroup, resourceVersion, ref.namespace, "applications") + "/" + url.PathEscape(ref.name)
+		if err := c.kube.Get(ctx, path, &app); err != nil {
+			return nil, err
+		}
+		return &app, nil
+	}
+
+	name, namespace := ref.name, ref.namespace
+	return c.appClient.Get(ctx, &application.ApplicationQuery{Name: &name, AppNamespace: &namespace})
+}
@@ -0,0 +1,55 @@
+package argocd
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesClient_ChildApplications(t *testing.T) {
+	var paths []string
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/apis/argoproj.io/v1alpha1/namespaces/argocd/applications/nginx":
+			w.Write([]byte(`{"metadata":{"name":"nginx","namespace":"argocd"},"spec":{"project":"web","source":{"repoURL":"https://charts.example.com","chart":"nginx","targetRevision":"1.0.0"}}}`))
+		case "/apis/argoproj.io/v1alpha1/namespaces/team-a/applications/redis":
+			w.Write([]byte(`{"metadata":{"name":"redis","namespace":"team-a"},"spec":{"project":"data"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	parent := &v1alpha1.Application{}
+	parent.Name, parent.Namespace = "apps", "argocd"
+	parent.Status.Resources = []v1alpha1.ResourceStatus{
+		{Group: "argoproj.io", Kind: "Application", Name: "nginx"},
+		{Group: "argoproj.io", Kind: "Application", Namespace: "team-a", Name: "redis"},
+		{Group: "apps", Kind: "Deployment", Namespace: "argocd", Name: "nginx"},
+	}
+
+	children, err := client.ChildApplications(context.Background(), parent)
+	require.NoError(t, err)
+	require.Len(t, children, 2)
+	assert.Equal(t, "nginx", children[0].Spec.Source.Chart)
+	assert.Equal(t, "team-a", children[1].Namespace)
+	assert.Len(t, paths, 2, "only Application resources are read")
+}
+
+func TestKubernetesClient_ChildApplications_NotFound(t *testing.T) {
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	parent := &v1alpha1.Application{}
+	parent.Name, parent.Namespace = "apps", "argocd"
+	parent.Status.Resources = []v1alpha1.ResourceStatus{{Group: "argoproj.io", Kind: "Application", Name: "gone"}}
+
+	_, err := client.ChildApplications(context.Background(), parent)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "child application gone of apps")
+}
@@ -0,0 +1,18 @@
+package argocd
+
+import (
+	"strings"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// ApplicationSetName returns the name of the ApplicationSet that generated an application,
+// or an empty string if the application isn't owned by one
+func ApplicationSetName(app *v1alpha1.Application) string {
+	for _, owner := range app.OwnerReferences {
+		if owner.Kind == "ApplicationSet" && strings.HasPrefix(owner.APIVersion, "argoproj.io/") {
+			return owner.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+package argocd
+
+import (
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplicationSetName(t *testing.T) {
+	owned := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{
+		Name: "guestbook-prod",
+		OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "argoproj.io/v1alpha1", Kind: "ApplicationSet", Name: "guestbook"},
+		},
+	}}
+	assert.Equal(t, "guestbook", ApplicationSetName(owned))
+
+	assert.Empty(t, ApplicationSetName(&v1alpha1.Application{}))
+
+	other := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "cm"}},
+	}}
+	assert.Empty(t, ApplicationSetName(other))
+}
@@ -2,26 +2,32 @@ package argocd
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
-	"net/http"
+	"sort"
 	"strings"
 
+	"argazer/internal/kube"
+
 	"github.com/argoproj/argo-cd/v2/pkg/apiclient"
 	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/project"
 	"github.com/argoproj/argo-cd/v2/pkg/apiclient/session"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/sirupsen/logrus"
 )
 
 // Client wraps ArgoCD API client
+// A client created with NewKubernetesClient reads ArgoCD's resources from the Kubernetes API instead.
 type Client struct {
-	apiClient apiclient.Client
-	appClient application.ApplicationServiceClient
-	logger    *logrus.Entry
+	apiClient     apiclient.Client
+	appClient     application.ApplicationServiceClient
+	projectClient project.ProjectServiceClient
+	kube          *kube.Client // Set in Kubernetes mode, where the API clients are nil
+	namespace     string       // Namespace of ArgoCD's AppProjects in Kubernetes mode
+	logger        *logrus.Entry
 }
 
-// NewClient creates a new ArgoCD API client
+// NewClient creates a new ArgoCD API client authenticated with a username and password
 func NewClient(serverURL, username, password string, insecure bool, logger *logrus.Entry) (*Client, error) {
 	logger.WithFields(logrus.Fields{
 		"server":   serverURL,
@@ -29,25 +35,8 @@ func NewClient(serverURL, username, password string, insecure bool, logger *logr
 		"insecure": insecure,
 	}).Info("Creating ArgoCD API client")
 
-	// Create HTTP client with optional TLS skip verification
-	var httpClient *http.Client
-	if insecure {
-		httpClient = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-		}
-	}
-
 	// Create ArgoCD client options
-	opts := apiclient.ClientOptions{
-		ServerAddr: serverURL,
-		PlainText:  strings.HasPrefix(serverURL, "http://"),
-		Insecure:   insecure,
-		GRPCWeb:    true, // Use gRPC-Web mode to avoid warnings and support HTTP proxies
-	}
-
-	_ = httpClient // Will be used for direct HTTP calls if needed
+	opts := clientOptions(serverURL, insecure)
 
 	// Create API client
 	apiClient, err := apiclient.NewClient(&opts)
@@ -76,10 +65,41 @@ func NewClient(serverURL, username, password string, insecure bool, logger *logr
 
 	// Update client options with auth token
 	opts.AuthToken = sessionResp.Token
-	opts.GRPCWeb = true // Ensure gRPC-Web is enabled for authenticated client too
 
-	// Recreate client with auth token
-	apiClient, err = apiclient.NewClient(&opts)
+	return newAuthenticatedClient(opts, logger)
+}
+
+// NewClientWithToken creates a new ArgoCD API client authenticated with an API token,
+// such as a project role token
+func NewClientWithToken(serverURL, token string, insecure bool, logger *logrus.Entry) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("ArgoCD token is required")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"server":   serverURL,
+		"insecure": insecure,
+	}).Info("Creating ArgoCD API client with token")
+
+	opts := clientOptions(serverURL, insecure)
+	opts.AuthToken = token
+
+	return newAuthenticatedClient(opts, logger)
+}
+
+// clientOptions returns the connection options shared by all clients
+func clientOptions(serverURL string, insecure bool) apiclient.ClientOptions {
+	return apiclient.ClientOptions{
+		ServerAddr: serverURL,
+		PlainText:  strings.HasPrefix(serverURL, "http://"),
+		Insecure:   insecure,
+		GRPCWeb:    true, // Use gRPC-Web mode to avoid warnings and support HTTP proxies
+	}
+}
+
+// newAuthenticatedClient creates the application client from options carrying an auth token
+func newAuthenticatedClient(opts apiclient.ClientOptions, logger *logrus.Entry) (*Client, error) {
+	apiClient, err := apiclient.NewClient(&opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authenticated client: %w", err)
 	}
@@ -90,57 +110,78 @@ func NewClient(serverURL, username, password string, insecure bool, logger *logr
 		return nil, fmt.Errorf("failed to create application client: %w", err)
 	}
 
+	// Create project service client
+	_, projectClient, err := apiClient.NewProjectClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project client: %w", err)
+	}
+
 	logger.Info("Successfully created ArgoCD API client")
 
 	return &Client{
-		apiClient: apiClient,
-		appClient: appClient,
-		logger:    logger,
+		apiClient:     apiClient,
+		appClient:     appClient,
+		projectClient: projectClient,
+		logger:        logger,
 	}, nil
 }
 
 // FilterOptions defines filtering criteria for applications
 type FilterOptions struct {
-	Projects []string          // Projects to filter by, ["*"] for all
-	AppNames []string          // App names to filter by, ["*"] for all
-	Labels   map[string]string // Label selectors
+	Projects   []string          // Projects to filter by: names, globs or /regexes/ (see IsNamePattern), ["*"] for all
+	AppNames   []string          // App names to filter by: names, globs or /regexes/, ["*"] for all
+	Namespaces []string          // Application namespaces to filter by, ["*"] or empty for all
+	Labels     map[string]string // Label selectors
+	SyncStatus []string          // Sync statuses to filter by (e.g. "OutOfSync"), empty for all
+	Health     []string          // Health statuses to filter by (e.g. "Degraded"), empty for all
 }
 
 // ListApplications lists ArgoCD applications with optional filtering
 func (c *Client) ListApplications(ctx context.Context, filter FilterOptions) ([]*v1alpha1.Application, error) {
 	c.logger.WithFields(logrus.Fields{
-		"projects":  filter.Projects,
-		"app_names": filter.AppNames,
-		"labels":    filter.Labels,
+		"projects":    filter.Projects,
+		"app_names":   filter.AppNames,
+		"namespaces":  filter.Namespaces,
+		"labels":      filter.Labels,
+		"sync_status": filter.SyncStatus,
+		"health":      filter.Health,
 	}).Debug("Listing ArgoCD applications")
 
+	matcher, err := newApplicationMatcher(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.kube != nil {
+		return c.listKubernetesApplications(ctx, filter, matcher)
+	}
+
 	// Build query - use Projects field directly instead of selector
 	query := &application.ApplicationQuery{}
 
-	// Add project filter using the Projects field
-	if len(filter.Projects) > 0 && !contains(filter.Projects, "*") {
-		query.Projects = filter.Projects
-		c.logger.WithField("projects", filter.Projects).Debug("Filtering by projects")
+	// Add project filter using the Projects field; globs and regular expressions are matched client-side
+	if projects := matcher.projects.exact(); len(projects) > 0 {
+		query.Projects = projects
+		c.logger.WithField("projects", projects).Debug("Filtering by projects")
 	}
 
-	// Add app name filter using the AppNamePattern field for server-side filtering
-	if len(filter.AppNames) > 0 && !contains(filter.AppNames, "*") {
-		// If single app name, use AppNamePattern
-		if len(filter.AppNames) == 1 {
-			query.Name = &filter.AppNames[0]
-			c.logger.WithField("app_name", filter.AppNames[0]).Debug("Filtering by app name")
-		}
-		// For multiple app names, we'll still need to filter client-side
-		// as ArgoCD API doesn't support multiple app names in one query
+	// Add app name filter using the Name field for server-side filtering
+	// The API takes a single exact name, so several names and patterns are matched client-side.
+	if names := matcher.appNames.exact(); len(names) == 1 {
+		query.Name = &names[0]
+		c.logger.WithField("app_name", names[0]).Debug("Filtering by app name")
+	}
+
+	// Without a namespace the API returns applications from every namespace ArgoCD watches
+	// (apps-in-any-namespace, ArgoCD 2.5+), so a single namespace is filtered server-side
+	if len(filter.Namespaces) == 1 && filter.Namespaces[0] != "*" {
+		query.AppNamespace = &filter.Namespaces[0]
+		c.logger.WithField("app_namespace", filter.Namespaces[0]).Debug("Filtering by app namespace")
 	}
 
 	// Build label selector if needed
 	if len(filter.Labels) > 0 {
-		var labelSelectors []string
-		for key, value := range filter.Labels {
-			labelSelectors = append(labelSelectors, fmt.Sprintf("%s=%s", key, value))
-		}
-		selectorStr := strings.Join(labelSelectors, ",")
+		selectorStr := labelSelector(filter.Labels)
 		query.Selector = &selectorStr
 		c.logger.WithField("label_selector", selectorStr).Debug("Filtering by labels")
 	}
@@ -153,15 +194,11 @@ func (c *Client) ListApplications(ctx context.Context, filter FilterOptions) ([]
 
 	var filtered []*v1alpha1.Application
 
-	// Filter by app names if we have multiple (client-side filter)
+	// Patterns, multiple app names, namespaces and statuses are filtered client-side
 	for _, app := range appList.Items {
-		// Check app name filter (only needed if multiple app names specified)
-		if len(filter.AppNames) > 1 && !contains(filter.AppNames, "*") {
-			if !contains(filter.AppNames, app.Name) {
-				continue
-			}
+		if !matcher.matches(&app) {
+			continue
 		}
-
 		filtered = append(filtered, &app)
 	}
 
@@ -170,6 +207,63 @@ func (c *Client) ListApplications(ctx context.Context, filter FilterOptions) ([]
 	return filtered, nil
 }
 
+// applicationMatcher matches applications against a filter's projects, names, namespaces and statuses
+// Labels aren't checked: they are always filtered server-side.
+type applicationMatcher struct {
+	filter   FilterOptions
+	projects *nameMatcher
+	appNames *nameMatcher
+}
+
+// newApplicationMatcher compiles the project and application name patterns of a filter
+func newApplicationMatcher(filter FilterOptions) (*applicationMatcher, error) {
+	projects, err := newNameMatcher(filter.Projects)
+	if err != nil {
+		return nil, fmt.Errorf("projects: %w", err)
+	}
+	appNames, err := newNameMatcher(filter.AppNames)
+	if err != nil {
+		return nil, fmt.Errorf("app_names: %w", err)
+	}
+	return &applicationMatcher{filter: filter, projects: projects, appNames: appNames}, nil
+}
+
+// matches reports whether an application matches the filter
+func (m *applicationMatcher) matches(app *v1alpha1.Application) bool {
+	if !m.projects.match(app.Spec.Project) || !m.appNames.match(app.Name) {
+		return false
+	}
+	if len(m.filter.Namespaces) > 0 && !contains(m.filter.Namespaces, "*") && !contains(m.filter.Namespaces, app.Namespace) {
+		return false
+	}
+	// The API has no sync or health filter, so statuses are always matched client-side
+	return matchesStatus(m.filter.SyncStatus, string(app.Status.Sync.Status)) && matchesStatus(m.filter.Health, string(app.Status.Health.Status))
+}
+
+// labelSelector renders label filters as a Kubernetes label selector, e.g. "team=platform,tier=web"
+func labelSelector(labels map[string]string) string {
+	selectors := make([]string, 0, len(labels))
+	for key, value := range labels {
+		selectors = append(selectors, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(selectors)
+	return strings.Join(selectors, ",")
+}
+
+// matchesStatus reports whether a status is one of the wanted ones, ignoring case
+// An empty list matches every status.
+func matchesStatus(wanted []string, status string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, w := range wanted {
+		if strings.EqualFold(w, status) {
+			return true
+		}
+	}
+	return false
+}
+
 // contains checks if a slice contains a string
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
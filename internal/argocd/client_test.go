@@ -72,6 +72,14 @@ func TestNewClient_EmptyCredentials(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestNewClientWithToken_EmptyToken(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	_, err := NewClientWithToken("http://localhost:8080", "", false, logger)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "token is required")
+}
+
 func TestFilterOptions(t *testing.T) {
 	// Test FilterOptions struct creation
 	filter := FilterOptions{
@@ -92,3 +100,11 @@ func TestFilterOptions(t *testing.T) {
 // or extensive mocking of the ArgoCD API client, which is complex due to the interface structure.
 // The contains() function and basic client creation are tested above.
 // For production, consider using integration tests with a real or containerized ArgoCD instance.
+
+func TestMatchesStatus(t *testing.T) {
+	assert.True(t, matchesStatus(nil, "Synced"))
+	assert.True(t, matchesStatus([]string{"OutOfSync", "Synced"}, "Synced"))
+	assert.True(t, matchesStatus([]string{"degraded"}, "Degraded"))
+	assert.False(t, matchesStatus([]string{"Healthy"}, "Degraded"))
+	assert.False(t, matchesStatus([]string{"Healthy"}, ""))
+}
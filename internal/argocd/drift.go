@@ -0,0 +1,87 @@
+package argocd
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// DeployedRevision returns the chart version of a Helm repository source at the application's
+// last successful sync, taken from its revision history
+// It returns an empty string if the application has no history or the source wasn't part of it.
+func DeployedRevision(app *v1alpha1.Application, source *v1alpha1.ApplicationSource) string {
+	if source.Chart == "" || len(app.Status.History) == 0 {
+		return ""
+	}
+
+	last := app.Status.History.LastRevisionHistory()
+	if len(last.Sources) == 0 {
+		if sameChart(last.Source, *source) {
+			return last.Revision
+		}
+		return ""
+	}
+
+	for i, deployed := range last.Sources {
+		if sameChart(deployed, *source) && i < len(last.Revisions) {
+			return last.Revisions[i]
+		}
+	}
+	return ""
+}
+
+// SyncedCommit returns the commit a Git source was synced to on the application's last successful
+// sync, taken from its revision history
+// It returns an empty string for Helm repository sources, or if the source wasn't part of the history.
+func SyncedCommit(app *v1alpha1.Application, source *v1alpha1.ApplicationSource) string {
+	if source.Chart != "" || len(app.Status.History) == 0 {
+		return ""
+	}
+
+	last := app.Status.History.LastRevisionHistory()
+	if len(last.Sources) == 0 {
+		if sameGitPath(last.Source, *source) {
+			return last.Revision
+		}
+		return ""
+	}
+
+	for i, deployed := range last.Sources {
+		if sameGitPath(deployed, *source) && i < len(last.Revisions) {
+			return last.Revisions[i]
+		}
+	}
+	return ""
+}
+
+// sameGitPath reports whether two sources refer to the same path of the same Git repository
+func sameGitPath(a, b v1alpha1.ApplicationSource) bool {
+	return a.Chart == "" && b.Chart == "" && a.Path == b.Path && strings.TrimSuffix(a.RepoURL, "/") == strings.TrimSuffix(b.RepoURL, "/")
+}
+
+// sameChart reports whether two sources refer to the same chart of the same repository
+func sameChart(a, b v1alpha1.ApplicationSource) bool {
+	return a.Chart == b.Chart && strings.TrimSuffix(a.RepoURL, "/") == strings.TrimSuffix(b.RepoURL, "/")
+}
+
+// RevisionDrifted reports whether the deployed chart version differs from the declared targetRevision
+// Exact versions are compared semantically, and ranges like "1.2.*" or "^1.2" drift when the deployed
+// version no longer satisfies them. An unknown deployed version never drifts.
+func RevisionDrifted(targetRevision, deployedRevision string) bool {
+	if deployedRevision == "" || targetRevision == deployedRevision {
+		return false
+	}
+
+	deployed, err := semver.NewVersion(deployedRevision)
+	if err != nil {
+		return true
+	}
+	if target, err := semver.NewVersion(targetRevision); err == nil {
+		return !target.Equal(deployed)
+	}
+	if constraint, err := semver.NewConstraint(targetRevision); err == nil {
+		return !constraint.Check(deployed)
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package argocd
+
+import (
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeployedRevision(t *testing.T) {
+	source := &v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com", Chart: "nginx", TargetRevision: "1.3.0"}
+
+	t.Run("single source", func(t *testing.T) {
+		app := &v1alpha1.Application{Status: v1alpha1.ApplicationStatus{History: v1alpha1.RevisionHistories{
+			v1alpha1.RevisionHistory{ID: 1, Revision: "1.1.0", Source: v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com/", Chart: "nginx"}},
+			v1alpha1.RevisionHistory{ID: 2, Revision: "1.2.0", Source: v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com/", Chart: "nginx"}},
+		}}}
+		assert.Equal(t, "1.2.0", DeployedRevision(app, source))
+	})
+
+	t.Run("multi source", func(t *testing.T) {
+		app := &v1alpha1.Application{Status: v1alpha1.ApplicationStatus{History: v1alpha1.RevisionHistories{
+			v1alpha1.RevisionHistory{
+				ID:        1,
+				Sources:   v1alpha1.ApplicationSources{v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/values"}, v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com", Chart: "nginx"}},
+				Revisions: []string{"abc123", "1.2.5"},
+			},
+		}}}
+		assert.Equal(t, "1.2.5", DeployedRevision(app, source))
+	})
+
+	t.Run("no history", func(t *testing.T) {
+		assert.Empty(t, DeployedRevision(&v1alpha1.Application{}, source))
+	})
+
+	t.Run("different chart", func(t *testing.T) {
+		app := &v1alpha1.Application{Status: v1alpha1.ApplicationStatus{History: v1alpha1.RevisionHistories{
+			v1alpha1.RevisionHistory{ID: 1, Revision: "2.0.0", Source: v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com", Chart: "redis"}},
+		}}}
+		assert.Empty(t, DeployedRevision(app, source))
+	})
+
+	t.Run("git source", func(t *testing.T) {
+		gitSource := &v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/charts", Path: "nginx"}
+		app := &v1alpha1.Application{Status: v1alpha1.ApplicationStatus{History: v1alpha1.RevisionHistories{
+			v1alpha1.RevisionHistory{ID: 1, Revision: "abc123", Source: *gitSource},
+		}}}
+		assert.Empty(t, DeployedRevision(app, gitSource))
+	})
+}
+
+func TestSyncedCommit(t *testing.T) {
+	source := &v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/charts", Path: "nginx", TargetRevision: "main"}
+
+	t.Run("single source", func(t *testing.T) {
+		app := &v1alpha1.Application{Status: v1alpha1.ApplicationStatus{History: v1alpha1.RevisionHistories{
+			v1alpha1.RevisionHistory{ID: 1, Revision: "1111111", Source: *source},
+			v1alpha1.RevisionHistory{ID: 2, Revision: "2222222", Source: v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/charts/", Path: "nginx"}},
+		}}}
+		assert.Equal(t, "2222222", SyncedCommit(app, source))
+	})
+
+	t.Run("multi source", func(t *testing.T) {
+		app := &v1alpha1.Application{Status: v1alpha1.ApplicationStatus{History: v1alpha1.RevisionHistories{
+			v1alpha1.RevisionHistory{
+				ID:        1,
+				Sources:   v1alpha1.ApplicationSources{v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/charts", Path: "redis"}, *source},
+				Revisions: []string{"aaaaaaa", "bbbbbbb"},
+			},
+		}}}
+		assert.Equal(t, "bbbbbbb", SyncedCommit(app, source))
+	})
+
+	t.Run("other path", func(t *testing.T) {
+		app := &v1alpha1.Application{Status: v1alpha1.ApplicationStatus{History: v1alpha1.RevisionHistories{
+			v1alpha1.RevisionHistory{ID: 1, Revision: "1111111", Source: v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/charts", Path: "redis"}},
+		}}}
+		assert.Empty(t, SyncedCommit(app, source))
+	})
+
+	t.Run("helm repository source", func(t *testing.T) {
+		chartSource := &v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com", Chart: "nginx"}
+		app := &v1alpha1.Application{Status: v1alpha1.ApplicationStatus{History: v1alpha1.RevisionHistories{
+			v1alpha1.RevisionHistory{ID: 1, Revision: "1.2.0", Source: *chartSource},
+		}}}
+		assert.Empty(t, SyncedCommit(app, chartSource))
+	})
+}
+
+func TestRevisionDrifted(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		deployed string
+		expected bool
+	}{
+		{name: "same version", target: "1.2.3", deployed: "1.2.3", expected: false},
+		{name: "v prefix", target: "v1.2.3", deployed: "1.2.3", expected: false},
+		{name: "different version", target: "1.3.0", deployed: "1.2.3", expected: true},
+		{name: "unknown deployed version", target: "1.3.0", deployed: "", expected: false},
+		{name: "range satisfied", target: "1.2.*", deployed: "1.2.7", expected: false},
+		{name: "range not satisfied", target: "^2.0.0", deployed: "1.9.0", expected: true},
+		{name: "non-semver deployed revision", target: "1.2.3", deployed: "stable", expected: true},
+		{name: "same non-semver revision", target: "stable", deployed: "stable", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RevisionDrifted(tt.target, tt.deployed))
+		})
+	}
+}
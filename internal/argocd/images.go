@@ -0,0 +1,67 @@
+package argocd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// ApplicationImages returns the container images of an application: the images of its rendered
+// resources as summarized by ArgoCD, and the images set with Helm parameters such as image.repository
+// and image.tag (also under a prefix, e.g. server.image.tag), which apps not synced yet only have
+func ApplicationImages(app *v1alpha1.Application) []string {
+	var images []string
+	add := func(image string) {
+		if image != "" && !slices.Contains(images, image) {
+			images = append(images, image)
+		}
+	}
+
+	for _, image := range app.Status.Summary.Images {
+		add(image)
+	}
+	for _, source := range applicationSources(app) {
+		if source.Helm == nil {
+			continue
+		}
+		for _, image := range parameterImages(source.Helm.Parameters) {
+			add(image)
+		}
+	}
+	return images
+}
+
+// applicationSources returns the sources of single and multi-source applications
+func applicationSources(app *v1alpha1.Application) v1alpha1.ApplicationSources {
+	if app.Spec.Source != nil {
+		return v1alpha1.ApplicationSources{*app.Spec.Source}
+	}
+	return app.Spec.Sources
+}
+
+// parameterImages builds image references from <prefix>image.repository, <prefix>image.tag and
+// the optional <prefix>image.registry Helm parameters
+func parameterImages(params []v1alpha1.HelmParameter) []string {
+	values := make(map[string]string, len(params))
+	for _, param := range params {
+		values[param.Name] = param.Value
+	}
+
+	var images []string
+	for _, param := range params {
+		if param.Name != "image.tag" && !strings.HasSuffix(param.Name, ".image.tag") {
+			continue
+		}
+		prefix := strings.TrimSuffix(param.Name, "tag")
+		repository := values[prefix+"repository"]
+		if repository == "" || param.Value == "" {
+			continue
+		}
+		if registry := values[prefix+"registry"]; registry != "" {
+			repository = strings.TrimSuffix(registry, "/") + "/" + repository
+		}
+		images = append(images, repository+":"+param.Value)
+	}
+	return images
+}
@@ -0,0 +1,51 @@
+package argocd
+
+import (
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplicationImages(t *testing.T) {
+	t.Run("rendered images and Helm parameters", func(t *testing.T) {
+		app := &v1alpha1.Application{
+			Spec: v1alpha1.ApplicationSpec{Source: &v1alpha1.ApplicationSource{
+				Chart: "nginx",
+				Helm: &v1alpha1.ApplicationSourceHelm{Parameters: []v1alpha1.HelmParameter{
+					{Name: "image.repository", Value: "bitnami/nginx"},
+					{Name: "image.tag", Value: "1.25.3"},
+					{Name: "metrics.image.registry", Value: "quay.io"},
+					{Name: "metrics.image.repository", Value: "nginx/nginx-prometheus-exporter"},
+					{Name: "metrics.image.tag", Value: "0.11.0"},
+					{Name: "sidecar.image.tag", Value: "2.0.0"},
+				}},
+			}},
+			Status: v1alpha1.ApplicationStatus{Summary: v1alpha1.ApplicationSummary{
+				Images: []string{"docker.io/bitnami/nginx:1.25.2", "bitnami/nginx:1.25.3"},
+			}},
+		}
+
+		assert.Equal(t, []string{
+			"docker.io/bitnami/nginx:1.25.2",
+			"bitnami/nginx:1.25.3",
+			"quay.io/nginx/nginx-prometheus-exporter:0.11.0",
+		}, ApplicationImages(app))
+	})
+
+	t.Run("multi source", func(t *testing.T) {
+		app := &v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Sources: v1alpha1.ApplicationSources{
+			v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/values", Ref: "values"},
+			v1alpha1.ApplicationSource{Chart: "app", Helm: &v1alpha1.ApplicationSourceHelm{Parameters: []v1alpha1.HelmParameter{
+				{Name: "image.repository", Value: "ghcr.io/org/app"},
+				{Name: "image.tag", Value: "v1.4.0"},
+			}}},
+		}}}
+
+		assert.Equal(t, []string{"ghcr.io/org/app:v1.4.0"}, ApplicationImages(app))
+	})
+
+	t.Run("no images", func(t *testing.T) {
+		assert.Empty(t, ApplicationImages(&v1alpha1.Application{}))
+	})
+}
@@ -0,0 +1,105 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"argazer/internal/kube"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+)
+
+// API group and version of ArgoCD's custom resources
+const (
+	resourceGroup   = "argoproj.io"
+	resourceVersion = "v1alpha1"
+)
+
+// NewKubernetesClient creates a client that reads Application and AppProject resources straight from
+// the Kubernetes API with the pod's service account, so neither the ArgoCD server nor ArgoCD
+// credentials are needed
+// namespace is where ArgoCD runs; AppProjects and repository Secrets are read from it.
+func NewKubernetesClient(namespace string, logger *logrus.Entry) (*Client, error) {
+	if !kube.InCluster() {
+		return nil, fmt.Errorf("kubernetes mode requires running inside a Kubernetes cluster")
+	}
+
+	kubeClient, err := kube.NewInClusterClient(logger.WithField("type", "kubernetes"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	logger.WithField("namespace", namespace).Info("Reading ArgoCD applications from the Kubernetes API")
+	return newKubernetesClient(kubeClient, namespace, logger), nil
+}
+
+// newKubernetesClient creates a Kubernetes mode client from a Kubernetes API client
+func newKubernetesClient(kubeClient *kube.Client, namespace string, logger *logrus.Entry) *Client {
+	return &Client{
+		kube:      kubeClient,
+		namespace: namespace,
+		logger:    logger,
+	}
+}
+
+// listKubernetesApplications lists Application resources and filters them client-side, except for labels
+// Without a namespace filter, Applications are listed across the cluster, which needs a ClusterRole;
+// with one, each namespace is listed separately so a Role per namespace suffices.
+func (c *Client) listKubernetesApplications(ctx context.Context, filter FilterOptions, matcher *applicationMatcher) ([]*v1alpha1.Application, error) {
+	namespaces := []string{""}
+	if len(filter.Namespaces) > 0 && !contains(filter.Namespaces, "*") {
+		namespaces = filter.Namespaces
+	}
+	selector := labelSelector(filter.Labels)
+
+	var filtered []*v1alpha1.Application
+	for _, namespace := range namespaces {
+		var list v1alpha1.ApplicationList
+		if err := c.kube.List(ctx, kube.ResourcePath(resourceGroup, resourceVersion, namespace, "applications"), selector, &list); err != nil {
+			return nil, fmt.Errorf("failed to list applications: %w", err)
+		}
+
+		for i := range list.Items {
+			if matcher.matches(&list.Items[i]) {
+				filtered = append(filtered, &list.Items[i])
+			}
+		}
+	}
+
+	c.logger.WithField("count", len(filtered)).Info("Found applications")
+
+	return filtered, nil
+}
+
+// kubernetesProject reads an AppProject resource from ArgoCD's namespace
+func (c *Client) kubernetesProject(ctx context.Context, name string) (*v1alpha1.AppProject, error) {
+	var proj v1alpha1.AppProject
+	path := kube.ResourcePath(resourceGroup, resourceVersion, c.namespace, "appprojects") + "/" + url.PathEscape(name)
+	if err := c.kube.Get(ctx, path, &proj); err != nil {
+		return nil, err
+	}
+	return &proj, nil
+}
+
+// listKubernetesRepositoryCredentials reads credentials from ArgoCD's Secrets of a type: repository or repo-creds
+func (c *Client) listKubernetesRepositoryCredentials(ctx context.Context, namespace, secretType string) ([]RepositoryCredential, error) {
+	secrets, err := c.kube.ListSecrets(ctx, namespace, secretTypeLabel+"="+secretType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository credentials: %w", err)
+	}
+
+	creds := make([]RepositoryCredential, 0, len(secrets))
+	for _, secret := range secrets {
+		creds = append(creds, RepositoryCredential{
+			URL:      string(secret.Data["url"]),
+			Username: string(secret.Data["username"]),
+			Password: string(secret.Data["password"]),
+		})
+	}
+
+	logCredentialSummary(c.logger, creds)
+
+	return creds, nil
+}
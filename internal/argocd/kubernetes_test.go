@@ -0,0 +1,116 @@
+package argocd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"argazer/internal/kube"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// applicationsJSON is an Application list as returned by the Kubernetes API
+const applicationsJSON = `{"apiVersion":"argoproj.io/v1alpha1","kind":"ApplicationList","items":[
+	{"metadata":{"name":"nginx","namespace":"argocd"},"spec":{"project":"web","source":{"repoURL":"https://charts.example.com","chart":"nginx","targetRevision":"1.0.0"}},"status":{"sync":{"status":"Synced"},"health":{"status":"Healthy"}}},
+	{"metadata":{"name":"redis","namespace":"argocd"},"spec":{"project":"data","source":{"repoURL":"https://charts.example.com","chart":"redis","targetRevision":"2.0.0"}},"status":{"sync":{"status":"OutOfSync"},"health":{"status":"Healthy"}}}
+]}`
+
+func newTestKubernetesClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	logger := logrus.NewEntry(logrus.New())
+	return newKubernetesClient(kube.NewClient(server.URL, "token", "argocd", nil, logger), "argocd", logger)
+}
+
+func TestKubernetesClient_ListApplications(t *testing.T) {
+	var paths []string
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "team=platform", r.URL.Query().Get("labelSelector"))
+		w.Write([]byte(applicationsJSON))
+	})
+
+	apps, err := client.ListApplications(context.Background(), FilterOptions{
+		Projects:   []string{"web"},
+		AppNames:   []string{"*"},
+		Namespaces: []string{"*"},
+		Labels:     map[string]string{"team": "platform"},
+	})
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, "nginx", apps[0].Name)
+	assert.Equal(t, "nginx", apps[0].Spec.Source.Chart)
+	assert.Equal(t, []string{"/apis/argoproj.io/v1alpha1/applications"}, paths, "all namespaces are listed cluster-wide")
+}
+
+func TestKubernetesClient_ListApplications_Namespaces(t *testing.T) {
+	var paths []string
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"items":[]}`))
+	})
+
+	_, err := client.ListApplications(context.Background(), FilterOptions{Namespaces: []string{"team-a", "team-b"}, SyncStatus: []string{"OutOfSync"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/apis/argoproj.io/v1alpha1/namespaces/team-a/applications",
+		"/apis/argoproj.io/v1alpha1/namespaces/team-b/applications",
+	}, paths)
+}
+
+func TestKubernetesClient_ListApplications_Forbidden(t *testing.T) {
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	_, err := client.ListApplications(context.Background(), FilterOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403")
+}
+
+func TestKubernetesClient_ProjectSyncWindows(t *testing.T) {
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/apis/argoproj.io/v1alpha1/namespaces/argocd/appprojects/web", r.URL.Path)
+		w.Write([]byte(`{"metadata":{"name":"web"},"spec":{"syncWindows":[{"kind":"deny","schedule":"0 22 * * *","duration":"8h","applications":["*"]}]}}`))
+	})
+
+	windows, err := client.ProjectSyncWindows(context.Background(), "web")
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, "deny", windows[0].Kind)
+}
+
+func TestKubernetesClient_ListRepositoryCredentials(t *testing.T) {
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/namespaces/argocd/secrets", r.URL.Path)
+		assert.Equal(t, "argocd.argoproj.io/secret-type=repo-creds", r.URL.Query().Get("labelSelector"))
+		// base64 of "https://charts.example.com", "user" and "secret"
+		w.Write([]byte(`{"items":[{"metadata":{"name":"creds"},"data":{"url":"aHR0cHM6Ly9jaGFydHMuZXhhbXBsZS5jb20=","username":"dXNlcg==","password":"c2VjcmV0"}}]}`))
+	})
+
+	creds, err := client.ListRepositoryCredentials(context.Background(), "argocd")
+	require.NoError(t, err)
+	assert.Equal(t, []RepositoryCredential{{URL: "https://charts.example.com", Username: "user", Password: "secret"}}, creds)
+}
+
+func TestKubernetesClient_ListRepositories(t *testing.T) {
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "argocd.argoproj.io/secret-type=repository", r.URL.Query().Get("labelSelector"))
+		// base64 of "oci://harbor.example.com", "robot" and "token"
+		w.Write([]byte(`{"items":[{"metadata":{"name":"repo-harbor"},"data":{"url":"b2NpOi8vaGFyYm9yLmV4YW1wbGUuY29t","username":"cm9ib3Q=","password":"dG9rZW4="}}]}`))
+	})
+
+	creds, err := client.ListRepositories(context.Background(), "argocd")
+	require.NoError(t, err)
+	assert.Equal(t, []RepositoryCredential{{URL: "oci://harbor.example.com", Username: "robot", Password: "token"}}, creds)
+}
+
+func TestLabelSelector(t *testing.T) {
+	assert.Equal(t, "", labelSelector(nil))
+	assert.Equal(t, "env=prod,team=platform", labelSelector(map[string]string{"team": "platform", "env": "prod"}))
+}
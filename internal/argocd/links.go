@@ -0,0 +1,21 @@
+package argocd
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ApplicationURL returns the ArgoCD web UI URL of an application
+// serverURL is the configured argocd_url, with or without a scheme (HTTPS is assumed without one).
+// The namespace is part of the path since ArgoCD 2.5 (apps-in-any-namespace); it is omitted when empty.
+func ApplicationURL(serverURL, namespace, name string) string {
+	base := strings.TrimSuffix(serverURL, "/")
+	if !strings.HasPrefix(base, "http://") && !strings.HasPrefix(base, "https://") {
+		base = "https://" + base
+	}
+
+	if namespace == "" {
+		return base + "/applications/" + url.PathEscape(name)
+	}
+	return base + "/applications/" + url.PathEscape(namespace) + "/" + url.PathEscape(name)
+}
@@ -0,0 +1,27 @@
+package argocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplicationURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		serverURL string
+		namespace string
+		app       string
+		expected  string
+	}{
+		{name: "hostname only", serverURL: "argocd.example.com", namespace: "argocd", app: "frontend", expected: "https://argocd.example.com/applications/argocd/frontend"},
+		{name: "with scheme and trailing slash", serverURL: "http://localhost:8080/", namespace: "team-a", app: "api", expected: "http://localhost:8080/applications/team-a/api"},
+		{name: "no namespace", serverURL: "https://argocd.example.com", app: "frontend", expected: "https://argocd.example.com/applications/frontend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ApplicationURL(tt.serverURL, tt.namespace, tt.app))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package argocd
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// IsNamePattern reports whether a project or application name filter is a pattern rather than a name:
+// a glob like "payments-*" or a regular expression between slashes like "/^team-a-/"
+// Patterns are matched client-side, the API only filters by exact names.
+func IsNamePattern(value string) bool {
+	return isRegexFilter(value) || strings.ContainsAny(value, "*?[")
+}
+
+// isRegexFilter reports whether a filter is a regular expression between slashes
+func isRegexFilter(value string) bool {
+	return len(value) > 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/")
+}
+
+// nameMatcher matches names against the names, globs and regular expressions of a filter
+type nameMatcher struct {
+	all     bool // The filter is empty or contains "*"
+	names   []string
+	globs   []string
+	regexes []*regexp.Regexp
+}
+
+// newNameMatcher compiles a project or application name filter
+func newNameMatcher(values []string) (*nameMatcher, error) {
+	m := &nameMatcher{all: len(values) == 0}
+	for _, value := range values {
+		switch {
+		case value == "*":
+			m.all = true
+		case isRegexFilter(value):
+			re, err := regexp.Compile(value[1 : len(value)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid name pattern %s: %w", value, err)
+			}
+			m.regexes = append(m.regexes, re)
+		case IsNamePattern(value):
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid name pattern %s: %w", value, err)
+			}
+			m.globs = append(m.globs, value)
+		default:
+			m.names = append(m.names, value)
+		}
+	}
+	return m, nil
+}
+
+// match reports whether a name matches the filter
+func (m *nameMatcher) match(name string) bool {
+	if m.all || contains(m.names, name) {
+		return true
+	}
+	for _, glob := range m.globs {
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+	for _, re := range m.regexes {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// exact returns the names of a filter with no patterns, which the API can filter by; nil otherwise
+func (m *nameMatcher) exact() []string {
+	if m.all || len(m.globs) > 0 || len(m.regexes) > 0 {
+		return nil
+	}
+	return m.names
+}
+
+// MatchesName reports whether a project or application name matches a filter of names and patterns
+// Invalid patterns match nothing; the configuration is validated before filters are used.
+func MatchesName(values []string, name string) bool {
+	m, err := newNameMatcher(values)
+	return err == nil && m.match(name)
+}
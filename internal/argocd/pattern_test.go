@@ -0,0 +1,53 @@
+package argocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNamePattern(t *testing.T) {
+	assert.True(t, IsNamePattern("payments-*"))
+	assert.True(t, IsNamePattern("team-?"))
+	assert.True(t, IsNamePattern("/^team-a-/"))
+	assert.False(t, IsNamePattern("payments"))
+	assert.False(t, IsNamePattern("/"))
+	assert.False(t, IsNamePattern("//"))
+}
+
+func TestNameMatcher(t *testing.T) {
+	m, err := newNameMatcher([]string{"frontend", "payments-*", "/^team-a-/"})
+	require.NoError(t, err)
+	assert.True(t, m.match("frontend"))
+	assert.True(t, m.match("payments-api"))
+	assert.True(t, m.match("team-a-worker"))
+	assert.False(t, m.match("frontend-v2"))
+	assert.False(t, m.match("legacy-payments-api"))
+	assert.False(t, m.match("team-b-worker"))
+	assert.Nil(t, m.exact(), "patterns can't be filtered server-side")
+
+	m, err = newNameMatcher([]string{"frontend", "backend"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend", "backend"}, m.exact())
+
+	m, err = newNameMatcher([]string{"*"})
+	require.NoError(t, err)
+	assert.True(t, m.match("anything"))
+	assert.Nil(t, m.exact())
+
+	m, err = newNameMatcher(nil)
+	require.NoError(t, err)
+	assert.True(t, m.match("anything"))
+
+	_, err = newNameMatcher([]string{"/(unclosed/"})
+	assert.ErrorContains(t, err, "invalid name pattern /(unclosed/")
+	_, err = newNameMatcher([]string{"team-[a"})
+	assert.ErrorContains(t, err, "invalid name pattern team-[a")
+}
+
+func TestMatchesName(t *testing.T) {
+	assert.True(t, MatchesName([]string{"team-*"}, "team-a"))
+	assert.False(t, MatchesName([]string{"team-*"}, "shared"))
+	assert.False(t, MatchesName([]string{"/(unclosed/"}, "team-a"))
+}
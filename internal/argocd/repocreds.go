@@ -0,0 +1,185 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"argazer/internal/kube"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/repocreds"
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// RepositoryCredential holds credentials ArgoCD stores for a repository URL or URL prefix
+type RepositoryCredential struct {
+	URL      string
+	Username string
+	Password string
+	Secret   string // Name of the Secret the credentials were read from, when read from Kubernetes
+}
+
+// secretTypeLabel is the label ArgoCD uses to identify repository secrets
+const secretTypeLabel = "argocd.argoproj.io/secret-type"
+
+// ListRepositoryCredentials returns the credential templates (repo-creds) configured in ArgoCD
+// The ArgoCD API never returns passwords, so when secretNamespace is set and argazer runs
+// in-cluster, passwords are read from the backing repo-creds Secrets instead.
+func (c *Client) ListRepositoryCredentials(ctx context.Context, secretNamespace string) ([]RepositoryCredential, error) {
+	if c.kube != nil {
+		return c.listKubernetesRepositoryCredentials(ctx, secretNamespace, "repo-creds")
+	}
+
+	closer, credsClient, err := c.apiClient.NewRepoCredsClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository credentials client: %w", err)
+	}
+	defer func() {
+		if err := closer.Close(); err != nil {
+			c.logger.WithError(err).Warn("Failed to close repository credentials client")
+		}
+	}()
+
+	list, err := credsClient.ListRepositoryCredentials(ctx, &repocreds.RepoCredsQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository credentials: %w", err)
+	}
+
+	creds := make([]RepositoryCredential, 0, len(list.Items))
+	for _, item := range list.Items {
+		creds = append(creds, RepositoryCredential{
+			URL:      item.URL,
+			Username: item.Username,
+			Password: item.Password,
+		})
+	}
+
+	c.logger.WithField("count", len(creds)).Debug("Listed ArgoCD repository credentials")
+
+	if secretNamespace != "" && needsPasswords(creds) {
+		c.fillPasswordsFromSecrets(ctx, secretNamespace, "repo-creds", creds)
+	}
+
+	logCredentialSummary(c.logger, creds)
+
+	return creds, nil
+}
+
+// ListRepositories returns the credentials of the repositories connected in ArgoCD (`argocd repo list`)
+// Like ListRepositoryCredentials, passwords are read from the backing repository Secrets when
+// secretNamespace is set and argazer runs in-cluster. Repositories without credentials are skipped.
+func (c *Client) ListRepositories(ctx context.Context, secretNamespace string) ([]RepositoryCredential, error) {
+	if c.kube != nil {
+		return c.listKubernetesRepositoryCredentials(ctx, secretNamespace, "repository")
+	}
+
+	closer, repoClient, err := c.apiClient.NewRepoClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository client: %w", err)
+	}
+	defer func() {
+		if err := closer.Close(); err != nil {
+			c.logger.WithError(err).Warn("Failed to close repository client")
+		}
+	}()
+
+	list, err := repoClient.ListRepositories(ctx, &repository.RepoQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	creds := make([]RepositoryCredential, 0, len(list.Items))
+	for _, item := range list.Items {
+		if item.Username == "" && item.Password == "" {
+			continue
+		}
+		creds = append(creds, RepositoryCredential{
+			URL:      item.Repo,
+			Username: item.Username,
+			Password: item.Password,
+		})
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"total":            len(list.Items),
+		"with_credentials": len(creds),
+	}).Debug("Listed ArgoCD repositories")
+
+	if secretNamespace != "" && needsPasswords(creds) {
+		c.fillPasswordsFromSecrets(ctx, secretNamespace, "repository", creds)
+	}
+
+	logCredentialSummary(c.logger, creds)
+
+	return creds, nil
+}
+
+// needsPasswords reports whether any credential is missing its password
+func needsPasswords(creds []RepositoryCredential) bool {
+	for _, cred := range creds {
+		if cred.Password == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fillPasswordsFromSecrets completes credentials with passwords from ArgoCD's Secrets
+// Errors are logged rather than returned: credentials are a best-effort addition
+func (c *Client) fillPasswordsFromSecrets(ctx context.Context, namespace, secretType string, creds []RepositoryCredential) {
+	if !kube.InCluster() {
+		c.logger.Debug("Not running in-cluster, cannot read repository passwords from ArgoCD secrets")
+		return
+	}
+
+	kubeClient, err := kube.NewInClusterClient(c.logger.WithField("type", "kubernetes"))
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to create Kubernetes client")
+		return
+	}
+
+	secrets, err := kubeClient.ListSecrets(ctx, namespace, secretTypeLabel+"="+secretType)
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to read ArgoCD repository secrets")
+		return
+	}
+
+	fillPasswords(creds, secrets)
+}
+
+// fillPasswords sets missing passwords from Secrets whose url matches the credential URL
+func fillPasswords(creds []RepositoryCredential, secrets []kube.Secret) {
+	byURL := make(map[string]kube.Secret, len(secrets))
+	for _, secret := range secrets {
+		byURL[strings.TrimSuffix(string(secret.Data["url"]), "/")] = secret
+	}
+
+	for i := range creds {
+		if creds[i].Password != "" {
+			continue
+		}
+		secret, ok := byURL[strings.TrimSuffix(creds[i].URL, "/")]
+		if !ok {
+			continue
+		}
+		creds[i].Password = string(secret.Data["password"])
+		if creds[i].Username == "" {
+			creds[i].Username = string(secret.Data["username"])
+		}
+	}
+}
+
+// logCredentialSummary logs how many of the credentials are usable
+func logCredentialSummary(logger *logrus.Entry, creds []RepositoryCredential) {
+	usable := 0
+	for _, cred := range creds {
+		if cred.Username != "" && cred.Password != "" {
+			usable++
+		}
+	}
+	logger.WithFields(logrus.Fields{
+		"total":  len(creds),
+		"usable": usable,
+	}).Info("Loaded repository credentials from ArgoCD")
+}
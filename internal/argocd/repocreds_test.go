@@ -0,0 +1,33 @@
+package argocd
+
+import (
+	"testing"
+
+	"argazer/internal/kube"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillPasswords(t *testing.T) {
+	creds := []RepositoryCredential{
+		{URL: "https://charts.example.com/", Username: "user"},
+		{URL: "https://github.com/myorg", Username: "git", Password: "already-set"},
+		{URL: "https://unknown.example.com"},
+	}
+	secrets := []kube.Secret{
+		{Data: map[string][]byte{"url": []byte("https://charts.example.com"), "password": []byte("secret")}},
+		{Data: map[string][]byte{"url": []byte("https://github.com/myorg"), "password": []byte("other")}},
+	}
+
+	fillPasswords(creds, secrets)
+
+	assert.Equal(t, "secret", creds[0].Password)
+	assert.Equal(t, "already-set", creds[1].Password, "existing passwords must not be replaced")
+	assert.Empty(t, creds[2].Password)
+}
+
+func TestNeedsPasswords(t *testing.T) {
+	assert.False(t, needsPasswords(nil))
+	assert.False(t, needsPasswords([]RepositoryCredential{{Password: "x"}}))
+	assert.True(t, needsPasswords([]RepositoryCredential{{Password: "x"}, {}}))
+}
@@ -0,0 +1,63 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+
+	"argazer/internal/kube"
+
+	"github.com/sirupsen/logrus"
+)
+
+// secretCredentialTypes are the ArgoCD Secret types holding repository credentials, in order of
+// precedence: a repository's own credentials come before the templates matching it by URL prefix
+var secretCredentialTypes = []string{"repository", "repo-creds"}
+
+// ListSecretCredentials reads the repository credentials of ArgoCD's repository and repo-creds Secrets
+// in namespace with the pod's service account, so neither the ArgoCD API nor ArgoCD credentials
+// are needed. Requires running in-cluster.
+func ListSecretCredentials(ctx context.Context, namespace string, logger *logrus.Entry) ([]RepositoryCredential, error) {
+	if !kube.InCluster() {
+		return nil, fmt.Errorf("reading credentials from Kubernetes secrets requires running inside a Kubernetes cluster")
+	}
+
+	kubeClient, err := kube.NewInClusterClient(logger.WithField("type", "kubernetes"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	return listSecretCredentials(ctx, kubeClient, namespace, logger)
+}
+
+// listSecretCredentials reads the credentials of each ArgoCD Secret type with a Kubernetes API client
+// Secrets without a url or a password are skipped.
+func listSecretCredentials(ctx context.Context, kubeClient *kube.Client, namespace string, logger *logrus.Entry) ([]RepositoryCredential, error) {
+	var creds []RepositoryCredential
+	for _, secretType := range secretCredentialTypes {
+		secrets, err := kubeClient.ListSecrets(ctx, namespace, secretTypeLabel+"="+secretType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s secrets: %w", secretType, err)
+		}
+
+		for _, secret := range secrets {
+			cred := RepositoryCredential{
+				URL:      string(secret.Data["url"]),
+				Username: string(secret.Data["username"]),
+				Password: string(secret.Data["password"]),
+				Secret:   secret.Metadata.Name,
+			}
+			if cred.URL == "" || cred.Password == "" {
+				logger.WithField("secret", secret.Metadata.Name).Debug("Secret has no repository URL or password, skipping")
+				continue
+			}
+			creds = append(creds, cred)
+		}
+	}
+
+	logger.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"count":     len(creds),
+	}).Info("Loaded repository credentials from Kubernetes secrets")
+
+	return creds, nil
+}
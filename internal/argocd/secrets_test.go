@@ -0,0 +1,58 @@
+package argocd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"argazer/internal/kube"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSecretCredentials(t *testing.T) {
+	var selectors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/namespaces/argocd/secrets", r.URL.Path)
+		selector := r.URL.Query().Get("labelSelector")
+		selectors = append(selectors, selector)
+		// base64 of "https://charts.example.com/private", "https://charts.example.com", "user" and "secret"
+		switch selector {
+		case "argocd.argoproj.io/secret-type=repository":
+			w.Write([]byte(`{"items":[
+				{"metadata":{"name":"repo-private"},"data":{"url":"aHR0cHM6Ly9jaGFydHMuZXhhbXBsZS5jb20vcHJpdmF0ZQ==","username":"dXNlcg==","password":"c2VjcmV0"}},
+				{"metadata":{"name":"repo-public"},"data":{"url":"aHR0cHM6Ly9jaGFydHMuZXhhbXBsZS5jb20="}}
+			]}`))
+		default:
+			w.Write([]byte(`{"items":[{"metadata":{"name":"creds"},"data":{"url":"aHR0cHM6Ly9jaGFydHMuZXhhbXBsZS5jb20=","username":"dXNlcg==","password":"c2VjcmV0"}}]}`))
+		}
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	creds, err := listSecretCredentials(context.Background(), kube.NewClient(server.URL, "token", "argocd", nil, logger), "argocd", logger)
+	require.NoError(t, err)
+	assert.Equal(t, []RepositoryCredential{
+		{URL: "https://charts.example.com/private", Username: "user", Password: "secret", Secret: "repo-private"},
+		{URL: "https://charts.example.com", Username: "user", Password: "secret", Secret: "creds"},
+	}, creds, "repository secrets come first and secrets without a password are skipped")
+	assert.Equal(t, []string{
+		"argocd.argoproj.io/secret-type=repository",
+		"argocd.argoproj.io/secret-type=repo-creds",
+	}, selectors)
+}
+
+func TestListSecretCredentials_Forbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	_, err := listSecretCredentials(context.Background(), kube.NewClient(server.URL, "token", "argocd", nil, logger), "argocd", logger)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list repository secrets")
+}
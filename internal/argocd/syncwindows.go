@@ -0,0 +1,49 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+
+	"argazer/internal/syncwindow"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/project"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// ProjectSyncWindows returns the sync windows defined on an ArgoCD project
+// This requires `projects, get` in the ArgoCD RBAC policy, or `get` on appprojects in Kubernetes mode.
+func (c *Client) ProjectSyncWindows(ctx context.Context, name string) (v1alpha1.SyncWindows, error) {
+	var proj *v1alpha1.AppProject
+	var err error
+	if c.kube != nil {
+		proj, err = c.kubernetesProject(ctx, name)
+	} else {
+		proj, err = c.projectClient.Get(ctx, &project.ProjectQuery{Name: name})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %s: %w", name, err)
+	}
+
+	c.logger.WithField("project", name).WithField("windows", len(proj.Spec.SyncWindows)).Debug("Loaded project sync windows")
+	return proj.Spec.SyncWindows, nil
+}
+
+// MatchingSyncWindows returns the project sync windows that apply to an application,
+// matched by application name, destination cluster or namespace like ArgoCD does
+func MatchingSyncWindows(windows v1alpha1.SyncWindows, app *v1alpha1.Application) []syncwindow.Window {
+	matching := windows.Matches(app)
+	if matching == nil {
+		return nil
+	}
+
+	result := make([]syncwindow.Window, 0, len(*matching))
+	for _, w := range *matching {
+		result = append(result, syncwindow.Window{
+			Kind:     w.Kind,
+			Schedule: w.Schedule,
+			Duration: w.Duration,
+			TimeZone: w.TimeZone,
+		})
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"argazer/internal/kube"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// UpdateTargetRevision changes the targetRevision of an application's source from one version to another
+// sourceIndex is the index in spec.sources, or -1 for the single spec.source. The JSON patch tests the
+// current revision first, so an application changed since it was read is left alone.
+// This requires `applications, update` in the ArgoCD RBAC policy, or `patch` on applications in Kubernetes mode.
+func (c *Client) UpdateTargetRevision(ctx context.Context, app *v1alpha1.Application, sourceIndex int, from, to string) error {
+	patch, err := targetRevisionPatch(sourceIndex, from, to)
+	if err != nil {
+		return err
+	}
+
+	if c.kube != nil {
+		path := kube.ResourcePath(resourceGroup, resourceVersion, app.Namespace, "applications") + "/" + url.PathEscape(app.Name)
+		err = c.kube.Patch(ctx, path, patch, nil)
+	} else {
+		name, namespace, project := app.Name, app.Namespace, app.Spec.Project
+		patchStr, patchType := string(patch), "json"
+		_, err = c.appClient.Patch(ctx, &application.ApplicationPatchRequest{
+			Name:         &name,
+			AppNamespace: &namespace,
+			Project:      &project,
+			Patch:        &patchStr,
+			PatchType:    &patchType,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update application %s: %w", app.Name, err)
+	}
+
+	c.logger.WithField("app", app.Name).WithField("from", from).WithField("to", to).Info("Updated application target revision")
+	return nil
+}
+
+// targetRevisionPatch builds the JSON patch replacing a source's targetRevision
+func targetRevisionPatch(sourceIndex int, from, to string) ([]byte, error) {
+	path := "/spec/source/targetRevision"
+	if sourceIndex >= 0 {
+		path = "/spec/sources/" + strconv.Itoa(sourceIndex) + "/targetRevision"
+	}
+
+	type operation struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value string `json:"value"`
+	}
+	patch, err := json.Marshal([]operation{
+		{Op: "test", Path: path, Value: from},
+		{Op: "replace", Path: path, Value: to},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build patch: %w", err)
+	}
+	return patch, nil
+}
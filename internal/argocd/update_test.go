@@ -0,0 +1,52 @@
+package argocd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTargetRevisionPatch(t *testing.T) {
+	patch, err := targetRevisionPatch(-1, "1.0.0", "1.1.0")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op":"test","path":"/spec/source/targetRevision","value":"1.0.0"},
+		{"op":"replace","path":"/spec/source/targetRevision","value":"1.1.0"}]`, string(patch))
+
+	patch, err = targetRevisionPatch(2, "1.0.0", "1.1.0")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op":"test","path":"/spec/sources/2/targetRevision","value":"1.0.0"},
+		{"op":"replace","path":"/spec/sources/2/targetRevision","value":"1.1.0"}]`, string(patch))
+}
+
+func TestKubernetesClient_UpdateTargetRevision(t *testing.T) {
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/apis/argoproj.io/v1alpha1/namespaces/team-a/applications/nginx", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(body), `"path":"/spec/source/targetRevision","value":"1.1.0"`)
+		w.Write([]byte(`{}`))
+	})
+
+	app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "team-a"}}
+	require.NoError(t, client.UpdateTargetRevision(context.Background(), app, -1, "1.0.0", "1.1.0"))
+}
+
+func TestKubernetesClient_UpdateTargetRevision_Conflict(t *testing.T) {
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"the server rejected our request due to an error in our request"}`))
+	})
+
+	app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "argocd"}}
+	err := client.UpdateTargetRevision(context.Background(), app, 0, "1.0.0", "1.1.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 422")
+}
@@ -0,0 +1,69 @@
+package argocd
+
+import (
+	"strings"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// ValuesRef is a source of a multi-source application that provides value files to the chart
+// through the `ref` pattern (valueFiles: ["$values/path/values.yaml"])
+type ValuesRef struct {
+	Ref            string   `json:"ref"`
+	RepoURL        string   `json:"repo_url"`
+	TargetRevision string   `json:"target_revision,omitempty"`
+	Paths          []string `json:"paths"` // Value file paths within the source's repository
+}
+
+// ValuesRefSources returns the sources whose value files the chart source references, in order of first use
+// References to a ref no source declares are ignored.
+func ValuesRefSources(app *v1alpha1.Application, chartSource *v1alpha1.ApplicationSource) []ValuesRef {
+	if chartSource.Helm == nil || len(app.Spec.Sources) == 0 {
+		return nil
+	}
+
+	var refs []ValuesRef
+	index := make(map[string]int)
+	for _, valueFile := range chartSource.Helm.ValueFiles {
+		if !strings.HasPrefix(valueFile, "$") {
+			continue
+		}
+		ref, path, _ := strings.Cut(strings.TrimPrefix(valueFile, "$"), "/")
+
+		i, ok := index[ref]
+		if !ok {
+			source := findRefSource(app.Spec.Sources, ref)
+			if source == nil {
+				continue
+			}
+			i = len(refs)
+			index[ref] = i
+			refs = append(refs, ValuesRef{
+				Ref:            ref,
+				RepoURL:        source.RepoURL,
+				TargetRevision: source.TargetRevision,
+			})
+		}
+		refs[i].Paths = append(refs[i].Paths, path)
+	}
+	return refs
+}
+
+// findRefSource returns the source declaring a ref name
+func findRefSource(sources v1alpha1.ApplicationSources, ref string) *v1alpha1.ApplicationSource {
+	for i := range sources {
+		if sources[i].Ref == ref {
+			return &sources[i]
+		}
+	}
+	return nil
+}
+
+// String returns the source as "repo@revision: path, path"
+func (r ValuesRef) String() string {
+	location := r.RepoURL
+	if r.TargetRevision != "" {
+		location += "@" + r.TargetRevision
+	}
+	return location + ": " + strings.Join(r.Paths, ", ")
+}
@@ -0,0 +1,42 @@
+package argocd
+
+import (
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValuesRefSources(t *testing.T) {
+	chart := v1alpha1.ApplicationSource{
+		RepoURL:        "https://charts.example.com",
+		Chart:          "nginx",
+		TargetRevision: "1.2.0",
+		Helm: &v1alpha1.ApplicationSourceHelm{ValueFiles: []string{
+			"values.yaml",
+			"$values/apps/nginx/values.yaml",
+			"$values/apps/nginx/values-prod.yaml",
+			"$missing/values.yaml",
+		}},
+	}
+	app := &v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Sources: v1alpha1.ApplicationSources{
+		chart,
+		v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/config", TargetRevision: "main", Ref: "values"},
+	}}}
+
+	refs := ValuesRefSources(app, &app.Spec.Sources[0])
+	assert.Equal(t, []ValuesRef{{
+		Ref:            "values",
+		RepoURL:        "https://github.com/org/config",
+		TargetRevision: "main",
+		Paths:          []string{"apps/nginx/values.yaml", "apps/nginx/values-prod.yaml"},
+	}}, refs)
+	assert.Equal(t, "https://github.com/org/config@main: apps/nginx/values.yaml, apps/nginx/values-prod.yaml", refs[0].String())
+}
+
+func TestValuesRefSources_SingleSource(t *testing.T) {
+	source := &v1alpha1.ApplicationSource{Chart: "nginx", Helm: &v1alpha1.ApplicationSourceHelm{ValueFiles: []string{"$values/values.yaml"}}}
+	app := &v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Source: source}}
+
+	assert.Nil(t, ValuesRefSources(app, source))
+}
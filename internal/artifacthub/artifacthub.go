@@ -0,0 +1,218 @@
+// Package artifacthub looks up the Artifact Hub listing of public Helm charts
+package artifacthub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultAPIURL is the API of artifacthub.io
+const DefaultAPIURL = "https://artifacthub.io/api/v1"
+
+// packagesURL is the base of package pages on artifacthub.io
+const packagesURL = "https://artifacthub.io/packages/helm/"
+
+// kindHelm is Artifact Hub's repository kind of Helm charts
+const kindHelm = "0"
+
+// searchLimit is the number of search results scanned for the chart's repository (Artifact Hub's maximum)
+const searchLimit = 60
+
+// errNotFound is returned by the API for unknown packages and versions
+var errNotFound = errors.New("not found")
+
+// Package holds the Artifact Hub metadata of a chart
+type Package struct {
+	URL            string          `json:"url"`                       // Package page on Artifact Hub
+	Deprecated     bool            `json:"deprecated,omitempty"`      // The chart is marked as deprecated
+	SecurityReport *SecurityReport `json:"security_report,omitempty"` // Vulnerabilities in the images of the checked version, nil if it wasn't scanned
+	Maintainers    []string        `json:"maintainers,omitempty"`
+	Links          []Link          `json:"links,omitempty"` // Links of the chart, e.g. its source or support channel
+}
+
+// SecurityReport summarizes the vulnerabilities Artifact Hub found in a version's images, by severity
+type SecurityReport struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Unknown  int `json:"unknown"`
+}
+
+// Total returns the number of vulnerabilities
+func (r SecurityReport) Total() int {
+	return r.Critical + r.High + r.Medium + r.Low + r.Unknown
+}
+
+// String lists the vulnerabilities by severity, e.g. "2 critical, 5 high", or "none"
+func (r SecurityReport) String() string {
+	var parts []string
+	for _, count := range []struct {
+		severity string
+		n        int
+	}{{"critical", r.Critical}, {"high", r.High}, {"medium", r.Medium}, {"low", r.Low}, {"unknown", r.Unknown}} {
+		if count.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count.n, count.severity))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Link is a named link of a chart
+type Link struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// apiPackage represents the fields of an Artifact Hub package used by argazer
+type apiPackage struct {
+	Name                  string          `json:"name"`
+	Deprecated            bool            `json:"deprecated"`
+	SecurityReportSummary *SecurityReport `json:"security_report_summary"`
+	Maintainers           []struct {
+		Name string `json:"name"`
+	} `json:"maintainers"`
+	Links      []Link `json:"links"`
+	Repository struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"repository"`
+}
+
+// Client looks up charts on Artifact Hub
+type Client struct {
+	apiURL     string
+	httpClient *http.Client
+	logger     *logrus.Entry
+}
+
+// NewClient creates a new Artifact Hub client; an empty API URL uses artifacthub.io
+func NewClient(apiURL string, logger *logrus.Entry) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		logger:     logger,
+	}
+}
+
+// Lookup returns the Artifact Hub metadata of a chart from a repository, with the security report
+// of the given version, or nil if the chart isn't listed on Artifact Hub
+func (c *Client) Lookup(ctx context.Context, repoURL, chartName, version string) (*Package, error) {
+	repository, err := c.findRepository(ctx, repoURL, chartName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search Artifact Hub for %s: %w", chartName, err)
+	}
+	if repository == "" {
+		c.logger.WithFields(logrus.Fields{"repo": repoURL, "chart": chartName}).Debug("Chart is not listed on Artifact Hub")
+		return nil, nil
+	}
+
+	path := "/packages/helm/" + url.PathEscape(repository) + "/" + url.PathEscape(chartName)
+	var latest apiPackage
+	if err := c.get(ctx, path, &latest); err != nil {
+		return nil, fmt.Errorf("failed to fetch Artifact Hub package %s/%s: %w", repository, chartName, err)
+	}
+
+	pkg := &Package{
+		URL:        packagesURL + repository + "/" + chartName,
+		Deprecated: latest.Deprecated,
+		Links:      latest.Links,
+	}
+	for _, maintainer := range latest.Maintainers {
+		if maintainer.Name != "" {
+			pkg.Maintainers = append(pkg.Maintainers, maintainer.Name)
+		}
+	}
+
+	// The package is returned at its latest version; the checked version is what's running
+	pkg.SecurityReport = latest.SecurityReportSummary
+	if version != "" {
+		var current apiPackage
+		err := c.get(ctx, path+"/"+url.PathEscape(version), &current)
+		switch {
+		case err == nil:
+			pkg.SecurityReport = current.SecurityReportSummary
+		case errors.Is(err, errNotFound):
+			pkg.SecurityReport = nil
+		default:
+			return nil, fmt.Errorf("failed to fetch Artifact Hub package %s/%s version %s: %w", repository, chartName, version, err)
+		}
+	}
+	return pkg, nil
+}
+
+// findRepository returns the name of the Artifact Hub repository listing the chart from repoURL,
+// or an empty string if there is none
+func (c *Client) findRepository(ctx context.Context, repoURL, chartName string) (string, error) {
+	query := url.Values{
+		"ts_query_web": {chartName},
+		"kind":         {kindHelm},
+		"limit":        {fmt.Sprint(searchLimit)},
+	}
+	var search struct {
+		Packages []apiPackage `json:"packages"`
+	}
+	if err := c.get(ctx, "/packages/search?"+query.Encode(), &search); err != nil {
+		return "", err
+	}
+
+	want := normalizeRepoURL(repoURL)
+	for _, pkg := range search.Packages {
+		if pkg.Name == chartName && normalizeRepoURL(pkg.Repository.URL) == want {
+			return pkg.Repository.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// normalizeRepoURL makes repository URLs comparable: Artifact Hub lists OCI registries with an
+// oci:// scheme, which ArgoCD applications omit
+func normalizeRepoURL(repoURL string) string {
+	normalized := strings.ToLower(strings.TrimSpace(repoURL))
+	for _, scheme := range []string{"oci://", "https://", "http://"} {
+		normalized = strings.TrimPrefix(normalized, scheme)
+	}
+	return strings.TrimSuffix(normalized, "/")
+}
+
+// get sends a GET request to the API and decodes the JSON response
+func (c *Client) get(ctx context.Context, path string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
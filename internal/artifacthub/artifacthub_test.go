@@ -0,0 +1,96 @@
+package artifacthub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves the search results, the latest package and its 15.0.0 version
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/packages/search":
+			assert.Equal(t, "nginx", r.URL.Query().Get("ts_query_web"))
+			assert.Equal(t, "0", r.URL.Query().Get("kind"))
+			fmt.Fprint(w, `{"packages": [
+				{"name": "nginx", "repository": {"name": "other", "url": "https://charts.example.com"}},
+				{"name": "nginx", "repository": {"name": "bitnami", "url": "oci://registry-1.docker.io/bitnamicharts"}}
+			]}`)
+		case "/packages/helm/bitnami/nginx":
+			fmt.Fprint(w, `{
+				"name": "nginx",
+				"deprecated": true,
+				"security_report_summary": {"low": 1},
+				"maintainers": [{"name": "Broadcom"}, {"name": ""}],
+				"links": [{"name": "Upstream Project", "url": "https://github.com/nginx/nginx"}]
+			}`)
+		case "/packages/helm/bitnami/nginx/15.0.0":
+			fmt.Fprint(w, `{"name": "nginx", "security_report_summary": {"critical": 2, "high": 5, "medium": 0, "low": 3, "unknown": 0}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClient_Lookup(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	client := NewClient(server.URL, logrus.NewEntry(logrus.New()))
+	pkg, err := client.Lookup(context.Background(), "registry-1.docker.io/bitnamicharts", "nginx", "15.0.0")
+	require.NoError(t, err)
+	require.NotNil(t, pkg)
+
+	assert.Equal(t, "https://artifacthub.io/packages/helm/bitnami/nginx", pkg.URL)
+	assert.True(t, pkg.Deprecated)
+	assert.Equal(t, []string{"Broadcom"}, pkg.Maintainers)
+	assert.Equal(t, []Link{{Name: "Upstream Project", URL: "https://github.com/nginx/nginx"}}, pkg.Links)
+	require.NotNil(t, pkg.SecurityReport)
+	assert.Equal(t, "2 critical, 5 high, 3 low", pkg.SecurityReport.String())
+	assert.Equal(t, 10, pkg.SecurityReport.Total())
+}
+
+func TestClient_Lookup_UnknownVersion(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	client := NewClient(server.URL, logrus.NewEntry(logrus.New()))
+	pkg, err := client.Lookup(context.Background(), "oci://registry-1.docker.io/bitnamicharts/", "nginx", "0.1.0")
+	require.NoError(t, err)
+	require.NotNil(t, pkg)
+	assert.Nil(t, pkg.SecurityReport, "the latest version's report must not be reported for another version")
+}
+
+func TestClient_Lookup_NotListed(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	client := NewClient(server.URL, logrus.NewEntry(logrus.New()))
+	pkg, err := client.Lookup(context.Background(), "https://charts.internal.example.com", "nginx", "15.0.0")
+	require.NoError(t, err)
+	assert.Nil(t, pkg)
+}
+
+func TestClient_Lookup_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, logrus.NewEntry(logrus.New()))
+	_, err := client.Lookup(context.Background(), "https://charts.example.com", "nginx", "1.0.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 429")
+}
+
+func TestSecurityReport_String(t *testing.T) {
+	assert.Equal(t, "none", SecurityReport{}.String())
+	assert.Equal(t, "1 medium, 4 unknown", SecurityReport{Medium: 1, Unknown: 4}.String())
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/sirupsen/logrus"
+)
+
+// ACR refresh tokens are renewed azureRefreshMargin before they expire
+const (
+	azureRefreshMargin  = 5 * time.Minute
+	azureFailureBackoff = time.Minute // Time before a registry whose token request failed is tried again
+	azureRequestTimeout = 30 * time.Second
+	azureScope          = "https://management.azure.com/.default"
+	azureUsername       = "00000000-0000-0000-0000-000000000000" // Username ACR expects with a refresh token
+)
+
+// azureHostPattern matches Azure Container Registry hosts
+var azureHostPattern = regexp.MustCompile(`^[a-z0-9-]+\.azurecr\.io$`)
+
+// azureToken is a token with the time it should be renewed at, empty when the request failed
+type azureToken struct {
+	value   string
+	renewAt time.Time
+}
+
+// azureCredentials obtains ACR refresh tokens: a Microsoft Entra ID access token of the Azure SDK's
+// default credential is exchanged for a refresh token of each registry
+type azureCredentials struct {
+	credential  azcore.TokenCredential // Caches and renews the Entra ID access token itself
+	tenantID    string                 // AZURE_TENANT_ID, passed to the exchange when set
+	exchangeURL func(host string) string
+	httpClient  *http.Client
+	now         func() time.Time
+
+	mu     sync.Mutex
+	tokens map[string]azureToken // ACR refresh tokens by registry host
+}
+
+// EnableAzure obtains credentials for Azure Container Registries (*.azurecr.io) with the ACR token
+// exchange, renewing them before they expire
+// The identity is Azure SDK's DefaultAzureCredential: a service principal (AZURE_TENANT_ID,
+// AZURE_CLIENT_ID, AZURE_CLIENT_SECRET or AZURE_CLIENT_CERTIFICATE_PATH), AKS workload identity,
+// a managed identity or the Azure CLI. Credentials from the config file and environment variables
+// take precedence.
+func (p *Provider) EnableAzure() error {
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+	p.azure = &azureCredentials{
+		credential:  credential,
+		tenantID:    os.Getenv("AZURE_TENANT_ID"),
+		exchangeURL: func(host string) string { return "https://" + host + "/oauth2/exchange" },
+		httpClient:  &http.Client{Timeout: azureRequestTimeout},
+		now:         time.Now,
+		tokens:      make(map[string]azureToken),
+	}
+	p.logger.Debug("Enabled Azure Container Registry authentication")
+	return nil
+}
+
+// credentials returns the credentials of an ACR host, nil for other hosts or when no token could be obtained
+func (a *azureCredentials) credentials(host string, logger *logrus.Entry) *Credentials {
+	if !azureHostPattern.MatchString(host) {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if token, ok := a.tokens[host]; ok && now.Before(token.renewAt) {
+		return azureRegistryCredentials(token)
+	}
+
+	token, err := a.registryToken(host, now)
+	if err != nil {
+		logger.WithError(err).WithField("registry", host).Warn("Failed to obtain Azure Container Registry token")
+		a.tokens[host] = azureToken{renewAt: now.Add(azureFailureBackoff)}
+		return nil
+	}
+	a.tokens[host] = token
+
+	logger.WithField("registry", host).Debug("Obtained Azure Container Registry token")
+	return azureRegistryCredentials(token)
+}
+
+// azureRegistryCredentials returns the registry credentials of an ACR refresh token, nil when empty
+func azureRegistryCredentials(token azureToken) *Credentials {
+	if token.value == "" {
+		return nil
+	}
+	return &Credentials{Username: azureUsername, Password: token.value, Source: "azure"}
+}
+
+// registryToken exchanges a Microsoft Entra ID access token for a refresh token of the registry
+func (a *azureCredentials) registryToken(host string, now time.Time) (azureToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), azureRequestTimeout)
+	defer cancel()
+	accessToken, err := a.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureScope}})
+	if err != nil {
+		return azureToken{}, fmt.Errorf("failed to obtain Microsoft Entra ID token: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {host},
+		"access_token": {accessToken.Token},
+	}
+	if a.tenantID != "" {
+		form.Set("tenant", a.tenantID)
+	}
+	body, err := a.do(http.MethodPost, a.exchangeURL(host), form)
+	if err != nil {
+		return azureToken{}, fmt.Errorf("token exchange failed: %w", err)
+	}
+
+	var exchange struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.Unmarshal(body, &exchange); err != nil {
+		return azureToken{}, fmt.Errorf("invalid token exchange response: %w", err)
+	}
+	if exchange.RefreshToken == "" {
+		return azureToken{}, errors.New("token exchange returned no refresh token")
+	}
+
+	// Refresh tokens are JWTs valid for a few hours
+	expiresAt := jwtExpiry(exchange.RefreshToken)
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(time.Hour)
+	}
+	return azureToken{value: exchange.RefreshToken, renewAt: expiresAt.Add(-azureRefreshMargin)}, nil
+}
+
+// do sends a request with an optional form body and returns the body of a successful response
+func (a *azureCredentials) do(method, target string, form url.Values) ([]byte, error) {
+	var reqBody io.Reader
+	if form != nil {
+		reqBody = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, target, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// jwtExpiry returns the exp claim of a JWT without verifying it, zero when it can't be read
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAzureCredential hands out a fixed Microsoft Entra ID access token, or err
+type fakeAzureCredential struct {
+	t        *testing.T
+	requests atomic.Int32
+	err      error
+}
+
+func (c *fakeAzureCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.requests.Add(1)
+	assert.Equal(c.t, []string{"https://management.azure.com/.default"}, options.Scopes)
+	if c.err != nil {
+		return azcore.AccessToken{}, c.err
+	}
+	return azcore.AccessToken{Token: "aad-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// newAzureTestProvider returns a provider whose registries' token exchanges are served by server
+func newAzureTestProvider(t *testing.T, server *httptest.Server, credential azcore.TokenCredential) *Provider {
+	t.Helper()
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+
+	p, err := NewProvider(nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	require.NoError(t, p.EnableAzure())
+	p.azure.credential = credential
+	p.azure.exchangeURL = func(host string) string { return server.URL + "/oauth2/exchange?registry=" + host }
+	return p
+}
+
+// testRefreshToken returns an ACR refresh token expiring at exp
+func testRefreshToken(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp": %d}`, exp.Unix())))
+	return "eyJhbGciOiJSUzI1NiJ9." + payload + ".signature"
+}
+
+func TestProvider_EnableAzure(t *testing.T) {
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	var exchanges atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		exchanges.Add(1)
+		assert.Equal(t, "access_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "aad-token", r.PostForm.Get("access_token"))
+		assert.Equal(t, "tenant", r.PostForm.Get("tenant"))
+		assert.Equal(t, r.URL.Query().Get("registry"), r.PostForm.Get("service"))
+		fmt.Fprintf(w, `{"refresh_token": %q}`, testRefreshToken(now.Add(3*time.Hour)))
+	}))
+	defer server.Close()
+
+	credential := &fakeAzureCredential{t: t}
+	p := newAzureTestProvider(t, server, credential)
+	p.azure.now = func() time.Time { return now }
+
+	creds := p.GetCredentials("oci://myregistry.azurecr.io/helm")
+	require.NotNil(t, creds)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000000", creds.Username)
+	assert.Equal(t, testRefreshToken(now.Add(3*time.Hour)), creds.Password)
+	assert.Equal(t, "azure", creds.Source)
+
+	require.NotNil(t, p.GetCredentials("myregistry.azurecr.io/other"))
+	require.NotNil(t, p.GetCredentials("otherregistry.azurecr.io/helm"))
+	assert.Equal(t, int32(2), exchanges.Load(), "refresh tokens are requested once per registry")
+
+	now = now.Add(2*time.Hour + 56*time.Minute)
+	require.NotNil(t, p.GetCredentials("myregistry.azurecr.io/helm"))
+	assert.Equal(t, int32(3), exchanges.Load(), "refresh tokens are renewed before they expire")
+	assert.Equal(t, int32(3), credential.requests.Load())
+
+	assert.Nil(t, p.GetCredentials("ghcr.io/org/charts"))
+}
+
+func TestProvider_EnableAzure_Failure(t *testing.T) {
+	var exchanges atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exchanges.Add(1) == 1 {
+			http.Error(w, `{"errors":[{"code":"UNAUTHORIZED"}]}`, http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"refresh_token": "acr-token"}`)
+	}))
+	defer server.Close()
+
+	p := newAzureTestProvider(t, server, &fakeAzureCredential{t: t})
+	now := time.Now()
+	p.azure.now = func() time.Time { return now }
+
+	assert.Nil(t, p.GetCredentials("myregistry.azurecr.io"))
+	assert.Nil(t, p.GetCredentials("myregistry.azurecr.io"))
+	assert.Equal(t, int32(1), exchanges.Load(), "failures aren't retried right away")
+
+	now = now.Add(2 * time.Minute)
+	creds := p.GetCredentials("myregistry.azurecr.io")
+	require.NotNil(t, creds)
+	assert.Equal(t, "acr-token", creds.Password)
+}
+
+func TestProvider_EnableAzure_NoIdentity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the token exchange should not be called without an access token")
+	}))
+	defer server.Close()
+
+	p := newAzureTestProvider(t, server, &fakeAzureCredential{t: t, err: errors.New("DefaultAzureCredential: failed to acquire a token")})
+	assert.Nil(t, p.GetCredentials("myregistry.azurecr.io"))
+}
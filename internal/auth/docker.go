@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dockerHubHosts are the names of Docker Hub's registry; Docker stores its credentials under
+// "https://index.docker.io/v1/"
+var dockerHubHosts = map[string]bool{"docker.io": true, "index.docker.io": true, "registry-1.docker.io": true}
+
+// dockerConfigFile is the part of a Docker config.json (or a mounted .dockerconfigjson pull secret)
+// used here
+type dockerConfigFile struct {
+	Auths       map[string]dockerAuth `json:"auths"`
+	CredsStore  string                `json:"credsStore"`
+	CredHelpers map[string]string     `json:"credHelpers"`
+}
+
+// dockerAuth is a registry entry of a Docker config file
+type dockerAuth struct {
+	Auth          string `json:"auth"` // base64 of "username:password"
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+// helperCredentials is the output of `docker-credential-<helper> get`
+type helperCredentials struct {
+	Username string `json:"Username"`
+	Secret   string `json:"Secret"`
+}
+
+// dockerCredentials holds the registry credentials of Docker config files, with credential helpers
+// run on first use of each registry
+type dockerCredentials struct {
+	auths   map[string]Credentials // By normalized registry host
+	helpers map[string]string      // Credential helper by normalized registry host
+	store   string                 // Default credential helper (credsStore)
+
+	runHelper func(helper, serverURL string) ([]byte, error)
+
+	mu     sync.Mutex
+	lookup map[string]*Credentials // Credential helper results by host, nil when the helper had none
+}
+
+// DefaultDockerConfig returns the path Docker uses for config.json: $DOCKER_CONFIG/config.json,
+// or ~/.docker/config.json
+func DefaultDockerConfig() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// LoadDockerConfig registers the registry credentials of Docker config files, such as config.json or
+// mounted Kubernetes pull secrets (.dockerconfigjson), including credential helpers
+// No paths uses Docker's default config.json. Missing files are skipped; earlier files take precedence
+// for the same registry. Credentials from the config file, environment variables and ArgoCD take
+// precedence over Docker's.
+func (p *Provider) LoadDockerConfig(paths []string) error {
+	if len(paths) == 0 {
+		paths = []string{DefaultDockerConfig()}
+	}
+
+	docker := &dockerCredentials{
+		auths:     make(map[string]Credentials),
+		helpers:   make(map[string]string),
+		runHelper: runCredentialHelper,
+		lookup:    make(map[string]*Credentials),
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				p.logger.WithField("path", path).Debug("Docker config not found, skipping")
+				continue
+			}
+			return err
+		}
+		if err := docker.load(data, path, p); err != nil {
+			return err
+		}
+	}
+
+	p.docker = docker
+	p.logger.WithFields(logrus.Fields{
+		"auths":   len(docker.auths),
+		"helpers": len(docker.helpers),
+		"store":   docker.store,
+	}).Debug("Loaded Docker registry credentials")
+	return nil
+}
+
+// load adds the entries of a Docker config file that aren't known yet
+func (d *dockerCredentials) load(data []byte, path string, p *Provider) error {
+	var file dockerConfigFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	// Legacy .dockercfg files are the auths map itself
+	if file.Auths == nil && file.CredsStore == "" && file.CredHelpers == nil {
+		if err := json.Unmarshal(data, &file.Auths); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	for registry, entry := range file.Auths {
+		host := dockerHost(registry, p)
+		if _, ok := d.auths[host]; ok {
+			continue
+		}
+		username, password := entry.Username, entry.Password
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				p.logger.WithError(err).WithField("registry", registry).Warn("Invalid auth in Docker config, skipping")
+				continue
+			}
+			username, password, _ = strings.Cut(string(decoded), ":")
+		}
+		// Identity tokens need an OAuth exchange registries don't all support
+		if username == "" || password == "" {
+			if entry.IdentityToken != "" {
+				p.logger.WithField("registry", registry).Debug("Docker config entry only has an identity token, skipping")
+			}
+			continue
+		}
+		d.auths[host] = Credentials{Username: username, Password: password, Source: "docker:" + filepath.Base(path)}
+	}
+
+	for registry, helper := range file.CredHelpers {
+		if host := dockerHost(registry, p); d.helpers[host] == "" {
+			d.helpers[host] = helper
+		}
+	}
+	if d.store == "" {
+		d.store = file.CredsStore
+	}
+	return nil
+}
+
+// credentials returns the Docker credentials of a registry host: a per-registry credential helper,
+// then a static entry, then the default credential store
+func (d *dockerCredentials) credentials(host string, logger *logrus.Entry) *Credentials {
+	helper := d.helpers[host]
+	if helper == "" {
+		if creds, ok := d.auths[host]; ok {
+			return &creds
+		}
+		helper = d.store
+	}
+	if helper == "" {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if creds, ok := d.lookup[host]; ok {
+		return creds
+	}
+
+	serverURL := host
+	if host == "index.docker.io" {
+		serverURL = "https://index.docker.io/v1/"
+	}
+	var creds *Credentials
+	output, err := d.runHelper(helper, serverURL)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"helper": helper, "registry": host}).Debug("Docker credential helper has no credentials")
+	} else {
+		var parsed helperCredentials
+		if err := json.Unmarshal(output, &parsed); err != nil {
+			logger.WithError(err).WithField("helper", helper).Warn("Invalid Docker credential helper output")
+		} else if parsed.Username != "" && parsed.Username != "<token>" && parsed.Secret != "" {
+			creds = &Credentials{Username: parsed.Username, Password: parsed.Secret, Source: "docker-credential-" + helper}
+		}
+	}
+	d.lookup[host] = creds
+	return creds
+}
+
+// dockerHost normalizes a Docker config registry key, mapping Docker Hub's names to index.docker.io
+func dockerHost(registry string, p *Provider) string {
+	host := p.normalizeURL(registry)
+	if dockerHubHosts[host] {
+		return "index.docker.io"
+	}
+	return host
+}
+
+// runCredentialHelper runs `docker-credential-<helper> get` for a registry
+func runCredentialHelper(helper, serverURL string) ([]byte, error) {
+	name := "docker-credential-" + helper
+	cmd := exec.Command(name, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("%s is not installed", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %s", name, strings.TrimSpace(stdout.String()+stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestProvider_LoadDockerConfig(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot$argazer:harbor-secret"))
+	config := writeTestFile(t, "config.json", `{
+  "auths": {
+    "harbor.example.com": {"auth": "`+auth+`"},
+    "https://index.docker.io/v1/": {"username": "hub-user", "password": "hub-pass"},
+    "token.example.com": {"identitytoken": "abc"}
+  },
+  "credHelpers": {"123456789.dkr.ecr.eu-west-1.amazonaws.com": "ecr-login"},
+  "credsStore": "desktop"
+}`)
+	pullSecret := writeTestFile(t, ".dockerconfigjson", `{"auths": {"harbor.example.com": {"username": "other", "password": "other"}, "ghcr.io": {"username": "gh-user", "password": "gh-token"}}}`)
+
+	logger := logrus.NewEntry(logrus.New())
+	p, err := NewProvider([]ConfigAuth{{URL: "ghcr.io", Username: "config-user", Password: "config-pass"}}, logger)
+	require.NoError(t, err)
+	require.NoError(t, p.LoadDockerConfig([]string{config, filepath.Join(t.TempDir(), "missing.json"), pullSecret}))
+
+	var helperCalls []string
+	p.docker.runHelper = func(helper, serverURL string) ([]byte, error) {
+		helperCalls = append(helperCalls, helper+" "+serverURL)
+		if helper == "ecr-login" {
+			return []byte(`{"ServerURL": "123456789.dkr.ecr.eu-west-1.amazonaws.com", "Username": "AWS", "Secret": "ecr-token"}`), nil
+		}
+		return nil, errors.New("credentials not found in native keychain")
+	}
+
+	creds := p.GetCredentials("oci://harbor.example.com/charts")
+	require.NotNil(t, creds)
+	assert.Equal(t, "robot$argazer", creds.Username)
+	assert.Equal(t, "harbor-secret", creds.Password, "earlier files take precedence")
+	assert.Equal(t, "docker:config.json", creds.Source)
+
+	creds = p.GetCredentials("registry-1.docker.io")
+	require.NotNil(t, creds)
+	assert.Equal(t, "hub-user", creds.Username)
+
+	creds = p.GetCredentials("ghcr.io/org/charts")
+	require.NotNil(t, creds)
+	assert.Equal(t, "config", creds.Source, "argazer's own credentials take precedence")
+
+	creds = p.GetCredentials("123456789.dkr.ecr.eu-west-1.amazonaws.com/charts")
+	require.NotNil(t, creds)
+	assert.Equal(t, "AWS", creds.Username)
+	assert.Equal(t, "docker-credential-ecr-login", creds.Source)
+
+	assert.Nil(t, p.GetCredentials("quay.io/org/charts"))
+	assert.Nil(t, p.GetCredentials("quay.io/org/other"))
+	assert.Equal(t, []string{
+		"ecr-login 123456789.dkr.ecr.eu-west-1.amazonaws.com",
+		"desktop quay.io",
+	}, helperCalls, "helpers run once per registry")
+}
+
+func TestProvider_LoadDockerConfig_Legacy(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("user:pass"))
+	path := writeTestFile(t, ".dockercfg", `{"registry.example.com": {"auth": "`+auth+`"}}`)
+
+	p, err := NewProvider(nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	require.NoError(t, p.LoadDockerConfig([]string{path}))
+
+	creds := p.GetCredentials("registry.example.com/charts")
+	require.NotNil(t, creds)
+	assert.Equal(t, "user", creds.Username)
+
+	require.Error(t, p.LoadDockerConfig([]string{writeTestFile(t, "broken.json", "{")}))
+}
+
+func TestDefaultDockerConfig_EnvOverride(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", "/etc/docker-ci")
+	assert.Equal(t, filepath.Join("/etc/docker-ci", "config.json"), DefaultDockerConfig())
+}
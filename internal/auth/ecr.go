@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/sirupsen/logrus"
+)
+
+// ECR authorization tokens are valid for 12 hours; they're renewed ecrRefreshMargin before they expire
+// so long-running scans don't use a token that expires halfway
+const (
+	ecrTokenLifetime  = 12 * time.Hour
+	ecrRefreshMargin  = 30 * time.Minute
+	ecrFailureBackoff = time.Minute // Time before a registry whose token request failed is tried again
+	ecrRequestTimeout = 30 * time.Second
+)
+
+// ecrHostPattern matches private ECR registries: <account>.dkr.ecr[-fips].<region>.amazonaws.com[.cn]
+var ecrHostPattern = regexp.MustCompile(`^(\d{12})\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// ecrToken is the cached token of a registry, nil credentials when the request failed
+type ecrToken struct {
+	creds   *Credentials
+	renewAt time.Time
+}
+
+// ecrCredentials obtains ECR authorization tokens with the AWS SDK, which uses the standard AWS
+// credential chain: static keys, profiles, IRSA web identity or the instance profile
+type ecrCredentials struct {
+	request func(ctx context.Context, region string) (token string, expiresAt time.Time, err error)
+	now     func() time.Time
+
+	mu     sync.Mutex
+	tokens map[string]ecrToken // By registry host
+}
+
+// EnableECR obtains credentials for private ECR registries (*.dkr.ecr.*.amazonaws.com) from AWS,
+// renewing them before they expire
+// Credentials from the config file and environment variables take precedence.
+func (p *Provider) EnableECR() {
+	p.ecr = &ecrCredentials{
+		request: requestECRAuthorizationToken,
+		now:     time.Now,
+		tokens:  make(map[string]ecrToken),
+	}
+	p.logger.Debug("Enabled ECR authentication")
+}
+
+// credentials returns the credentials of an ECR registry host, nil for other hosts or when no token
+// could be obtained
+func (e *ecrCredentials) credentials(host string, logger *logrus.Entry) *Credentials {
+	match := ecrHostPattern.FindStringSubmatch(host)
+	if match == nil {
+		return nil
+	}
+	region := match[2]
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.now()
+	if token, ok := e.tokens[host]; ok && now.Before(token.renewAt) {
+		return token.creds
+	}
+
+	creds, expiresAt, err := e.token(region)
+	if err != nil {
+		logger.WithError(err).WithField("registry", host).Warn("Failed to obtain ECR authorization token")
+		e.tokens[host] = ecrToken{renewAt: now.Add(ecrFailureBackoff)}
+		return nil
+	}
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(ecrTokenLifetime)
+	}
+	e.tokens[host] = ecrToken{creds: creds, renewAt: expiresAt.Add(-ecrRefreshMargin)}
+
+	logger.WithFields(logrus.Fields{
+		"registry":   host,
+		"expires_at": expiresAt.Format(time.RFC3339),
+	}).Debug("Obtained ECR authorization token")
+	return creds
+}
+
+// token requests an authorization token for a registry's region, returning its expiry when AWS
+// reports it
+// ECR tokens are valid for every registry the identity can access in the region.
+func (e *ecrCredentials) token(region string) (*Credentials, time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ecrRequestTimeout)
+	defer cancel()
+
+	token, expiresAt, err := e.request(ctx, region)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid authorization token: %w", err)
+	}
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok || password == "" {
+		return nil, time.Time{}, fmt.Errorf("invalid authorization token")
+	}
+
+	return &Credentials{Username: username, Password: password, Source: "ecr"}, expiresAt, nil
+}
+
+// requestECRAuthorizationToken calls ecr:GetAuthorizationToken in a region with the credentials of
+// the AWS SDK's default chain
+func requestECRAuthorizationToken(ctx context.Context, region string) (string, time.Time, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	output, err := ecr.NewFromConfig(cfg).GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("ecr:GetAuthorizationToken failed: %w", err)
+	}
+	if len(output.AuthorizationData) == 0 {
+		return "", time.Time{}, fmt.Errorf("AWS returned no authorization data")
+	}
+
+	data := output.AuthorizationData[0]
+	return aws.ToString(data.AuthorizationToken), aws.ToTime(data.ExpiresAt), nil
+}
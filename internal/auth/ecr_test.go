@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ecrTestRegistry = "123456789012.dkr.ecr.eu-west-1.amazonaws.com"
+
+func TestProvider_EnableECR(t *testing.T) {
+	p, err := NewProvider(nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	p.EnableECR()
+
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	p.ecr.now = func() time.Time { return now }
+	var calls []string
+	p.ecr.request = func(ctx context.Context, region string) (string, time.Time, error) {
+		calls = append(calls, region)
+		token := base64.StdEncoding.EncodeToString([]byte("AWS:token-" + now.Format("15")))
+		return token, time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC), nil
+	}
+
+	creds := p.GetCredentials("oci://" + ecrTestRegistry + "/charts")
+	require.NotNil(t, creds)
+	assert.Equal(t, "AWS", creds.Username)
+	assert.Equal(t, "token-08", creds.Password)
+	assert.Equal(t, "ecr", creds.Source)
+
+	now = now.Add(11 * time.Hour)
+	assert.Equal(t, "token-08", p.GetCredentials(ecrTestRegistry).Password, "tokens are reused until shortly before they expire")
+
+	now = now.Add(45 * time.Minute)
+	assert.Equal(t, "token-19", p.GetCredentials(ecrTestRegistry).Password, "tokens are renewed before they expire")
+
+	assert.Nil(t, p.GetCredentials("ghcr.io/org/charts"))
+	assert.Equal(t, []string{
+		"eu-west-1",
+		"eu-west-1",
+	}, calls)
+}
+
+func TestProvider_EnableECR_Precedence(t *testing.T) {
+	p, err := NewProvider([]ConfigAuth{{URL: ecrTestRegistry, Username: "AWS", Password: "static"}}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	p.EnableECR()
+	p.ecr.request = func(ctx context.Context, region string) (string, time.Time, error) {
+		t.Error("AWS should not be asked for registries with configured credentials")
+		return "", time.Time{}, nil
+	}
+
+	creds := p.GetCredentials(ecrTestRegistry)
+	require.NotNil(t, creds)
+	assert.Equal(t, "static", creds.Password)
+}
+
+func TestProvider_EnableECR_Failure(t *testing.T) {
+	p, err := NewProvider(nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	p.EnableECR()
+
+	now := time.Now()
+	p.ecr.now = func() time.Time { return now }
+	calls := 0
+	p.ecr.request = func(ctx context.Context, region string) (string, time.Time, error) {
+		calls++
+		return "", time.Time{}, errors.New("no EC2 IMDS role found")
+	}
+
+	assert.Nil(t, p.GetCredentials(ecrTestRegistry))
+	assert.Nil(t, p.GetCredentials(ecrTestRegistry))
+	assert.Equal(t, 1, calls, "failures aren't retried right away")
+
+	now = now.Add(2 * time.Minute)
+	assert.Nil(t, p.GetCredentials(ecrTestRegistry))
+	assert.Equal(t, 2, calls)
+}
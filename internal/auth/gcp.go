@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// Google access tokens are valid for an hour; they're renewed gcpRefreshMargin before they expire
+const (
+	gcpRefreshMargin  = 5 * time.Minute
+	gcpFailureBackoff = time.Minute // Time before a failed token request is tried again
+	gcpRequestTimeout = 30 * time.Second
+	gcpScope          = "https://www.googleapis.com/auth/cloud-platform"
+	gcpUsername       = "oauth2accesstoken" // Username registries expect with an access token
+)
+
+// gcpHostPattern matches Artifact Registry (<location>-docker.pkg.dev) and Container Registry
+// ([<region>.]gcr.io) hosts
+var gcpHostPattern = regexp.MustCompile(`^([a-z0-9-]+\.pkg\.dev|([a-z0-9-]+\.)?gcr\.io)$`)
+
+// gcpCredentials obtains Google access tokens with Application Default Credentials, mapped to the
+// username Google registries expect
+// All Google registries share the token of the one identity.
+type gcpCredentials struct {
+	findTokenSource func() (oauth2.TokenSource, error)
+	now             func() time.Time
+
+	mu      sync.Mutex
+	source  oauth2.TokenSource // nil until Application Default Credentials were found
+	creds   *Credentials       // nil when the last request failed
+	renewAt time.Time
+}
+
+// EnableGCP obtains credentials for Artifact Registry (*.pkg.dev) and Container Registry (gcr.io)
+// with Google Application Default Credentials, renewing access tokens before they expire
+// Credentials from the config file and environment variables take precedence.
+func (p *Provider) EnableGCP() {
+	p.gcp = &gcpCredentials{
+		findTokenSource: findGCPTokenSource,
+		now:             time.Now,
+	}
+	p.logger.Debug("Enabled Google Cloud authentication")
+}
+
+// findGCPTokenSource returns the token source of Application Default Credentials: the file in
+// GOOGLE_APPLICATION_CREDENTIALS (service account keys, workload identity federation, impersonation),
+// gcloud's application default credentials, or the metadata server
+func findGCPTokenSource() (oauth2.TokenSource, error) {
+	// The token source sends its requests with the HTTP client of this context
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: gcpRequestTimeout})
+	creds, err := google.FindDefaultCredentials(ctx, gcpScope)
+	if err != nil {
+		return nil, err
+	}
+	return creds.TokenSource, nil
+}
+
+// credentials returns the credentials of a Google registry host, nil for other hosts or when no token
+// could be obtained
+func (g *gcpCredentials) credentials(host string, logger *logrus.Entry) *Credentials {
+	if !gcpHostPattern.MatchString(host) {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	if !g.renewAt.IsZero() && now.Before(g.renewAt) {
+		return g.creds
+	}
+
+	token, err := g.token()
+	if err != nil {
+		logger.WithError(err).WithField("registry", host).Warn("Failed to obtain Google Cloud access token")
+		g.creds, g.renewAt = nil, now.Add(gcpFailureBackoff)
+		return nil
+	}
+	g.creds = &Credentials{Username: gcpUsername, Password: token.AccessToken, Source: "gcp"}
+	g.renewAt = now.Add(gcpRefreshMargin)
+	if !token.Expiry.IsZero() {
+		g.renewAt = token.Expiry.Add(-gcpRefreshMargin)
+	}
+
+	logger.WithField("expires_at", token.Expiry.Format(time.RFC3339)).Debug("Obtained Google Cloud access token")
+	return g.creds
+}
+
+// token requests an access token, finding Application Default Credentials on first use
+func (g *gcpCredentials) token() (*oauth2.Token, error) {
+	if g.source == nil {
+		source, err := g.findTokenSource()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find Google Application Default Credentials: %w", err)
+		}
+		g.source = source
+	}
+
+	token, err := g.source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access token")
+	}
+	return token, nil
+}
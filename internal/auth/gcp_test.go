@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// gcpTokenSourceFunc adapts a function to oauth2.TokenSource
+type gcpTokenSourceFunc func() (*oauth2.Token, error)
+
+func (f gcpTokenSourceFunc) Token() (*oauth2.Token, error) { return f() }
+
+func newGCPTestProvider(t *testing.T, source oauth2.TokenSource, findErr error) *Provider {
+	t.Helper()
+	p, err := NewProvider(nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	p.EnableGCP()
+	p.gcp.findTokenSource = func() (oauth2.TokenSource, error) { return source, findErr }
+	return p
+}
+
+func TestProvider_EnableGCP(t *testing.T) {
+	now := time.Now()
+	requests := 0
+	p := newGCPTestProvider(t, gcpTokenSourceFunc(func() (*oauth2.Token, error) {
+		requests++
+		return &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", requests), Expiry: now.Add(time.Hour)}, nil
+	}), nil)
+	p.gcp.now = func() time.Time { return now }
+
+	creds := p.GetCredentials("oci://europe-west1-docker.pkg.dev/project/charts")
+	require.NotNil(t, creds)
+	assert.Equal(t, "oauth2accesstoken", creds.Username)
+	assert.Equal(t, "token-1", creds.Password)
+	assert.Equal(t, "gcp", creds.Source)
+	assert.Equal(t, "token-1", p.GetCredentials("eu.gcr.io/project").Password, "registries share the token")
+
+	now = now.Add(56 * time.Minute)
+	assert.Equal(t, "token-2", p.GetCredentials("gcr.io/project").Password, "tokens are renewed before they expire")
+
+	assert.Nil(t, p.GetCredentials("ghcr.io/org/charts"))
+	assert.Nil(t, p.GetCredentials("gcr.io.example.com/charts"))
+	assert.Equal(t, 2, requests)
+}
+
+func TestProvider_EnableGCP_ServiceAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		require.Len(t, parts, 3)
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var claims map[string]any
+		require.NoError(t, json.Unmarshal(payload, &claims))
+		assert.Equal(t, "argazer@project.iam.gserviceaccount.com", claims["iss"])
+		assert.Equal(t, "https://www.googleapis.com/auth/cloud-platform", claims["scope"])
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "sa-token", "expires_in": 3600, "token_type": "Bearer"}`)
+	}))
+	defer server.Close()
+
+	file, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "argazer@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL + "/token",
+	})
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", writeTestFile(t, "service-account.json", string(file)))
+
+	p, err := NewProvider(nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	p.EnableGCP()
+
+	creds := p.GetCredentials("us-docker.pkg.dev/project/charts")
+	require.NotNil(t, creds)
+	assert.Equal(t, "sa-token", creds.Password)
+}
+
+func TestProvider_EnableGCP_Failure(t *testing.T) {
+	p := newGCPTestProvider(t, nil, nil)
+	finds := 0
+	p.gcp.findTokenSource = func() (oauth2.TokenSource, error) {
+		finds++
+		return nil, errors.New("could not find default credentials")
+	}
+	assert.Nil(t, p.GetCredentials("gcr.io/project"))
+	assert.Nil(t, p.GetCredentials("gcr.io/project"))
+	assert.Equal(t, 1, finds, "failures aren't retried right away")
+
+	p = newGCPTestProvider(t, gcpTokenSourceFunc(func() (*oauth2.Token, error) {
+		return nil, errors.New("oauth2: cannot fetch token: 400 Bad Request")
+	}), nil)
+	assert.Nil(t, p.GetCredentials("gcr.io/project"))
+}
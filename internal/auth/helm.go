@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// HelmRepository represents a single entry of Helm's repositories.yaml
+type HelmRepository struct {
+	Name               string `yaml:"name"`
+	URL                string `yaml:"url"`
+	Username           string `yaml:"username"`
+	Password           string `yaml:"password"`
+	PassCredentialsAll bool   `yaml:"pass_credentials_all"`
+}
+
+// helmRepositoryFile represents the structure of Helm's repositories.yaml
+type helmRepositoryFile struct {
+	Repositories []HelmRepository `yaml:"repositories"`
+}
+
+// DefaultHelmRepositoryConfig returns the path Helm uses for repositories.yaml
+// It follows Helm's own lookup rules: $HELM_REPOSITORY_CONFIG, then the
+// platform-specific Helm configuration directory
+func DefaultHelmRepositoryConfig() string {
+	if path := os.Getenv("HELM_REPOSITORY_CONFIG"); path != "" {
+		return path
+	}
+
+	if dir := os.Getenv("HELM_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "repositories.yaml")
+	}
+
+	if runtime.GOOS == "darwin" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(home, "Library", "Preferences", "helm", "repositories.yaml")
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "helm", "repositories.yaml")
+}
+
+// LoadHelmRepositories reads repository entries from a Helm repositories.yaml file
+func LoadHelmRepositories(path string) ([]HelmRepository, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file helmRepositoryFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return file.Repositories, nil
+}
+
+// LoadHelmRepositoryConfig registers the repositories from Helm's repositories.yaml
+// An empty path uses Helm's default location. A missing file is not an error.
+func (p *Provider) LoadHelmRepositoryConfig(path string) error {
+	if path == "" {
+		path = DefaultHelmRepositoryConfig()
+	}
+	if path == "" {
+		return nil
+	}
+
+	repos, err := LoadHelmRepositories(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			p.logger.WithField("path", path).Debug("Helm repositories.yaml not found, skipping")
+			return nil
+		}
+		return err
+	}
+
+	for _, repo := range repos {
+		if repo.URL == "" {
+			continue
+		}
+		p.helmRepos = append(p.helmRepos, repo)
+
+		p.logger.WithFields(logrus.Fields{
+			"name":                 repo.Name,
+			"url":                  repo.URL,
+			"has_credentials":      repo.Username != "" && repo.Password != "",
+			"pass_credentials_all": repo.PassCredentialsAll,
+		}).Debug("Loaded Helm repository from local Helm configuration")
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"path":  path,
+		"count": len(p.helmRepos),
+	}).Debug("Loaded Helm repository configuration")
+
+	return nil
+}
+
+// ResolveRepoURL maps a Helm repository alias to its URL
+// Aliases may be written as "@name", "alias:name" or the bare repository name.
+// URLs that don't match any alias are returned unchanged.
+func (p *Provider) ResolveRepoURL(repoURL string) string {
+	name := repoURL
+	switch {
+	case strings.HasPrefix(name, "@"):
+		name = strings.TrimPrefix(name, "@")
+	case strings.HasPrefix(name, "alias:"):
+		name = strings.TrimPrefix(name, "alias:")
+	case strings.Contains(name, "/") || strings.Contains(name, "."):
+		// Looks like a URL or registry reference, not an alias
+		return repoURL
+	}
+
+	for _, repo := range p.helmRepos {
+		if repo.Name == name {
+			p.logger.WithFields(logrus.Fields{
+				"alias": repoURL,
+				"url":   repo.URL,
+			}).Debug("Resolved Helm repository alias")
+			return repo.URL
+		}
+	}
+
+	return repoURL
+}
+
+// helmRepoCredentials finds credentials for a URL among the Helm repository entries
+// Credentials are used for URLs inside the repository. pass_credentials_all is carried along, as it
+// decides whether chart archives the index references on other hosts get them too.
+func (p *Provider) helmRepoCredentials(repoURL string) *Credentials {
+	target := strings.TrimSuffix(repoURL, "/")
+
+	for i := range p.helmRepos {
+		repo := &p.helmRepos[i]
+		if repo.Username == "" || repo.Password == "" {
+			continue
+		}
+
+		repoPrefix := strings.TrimSuffix(repo.URL, "/")
+		if target == repoPrefix || strings.HasPrefix(target, repoPrefix+"/") {
+			return &Credentials{
+				Username:           repo.Username,
+				Password:           repo.Password,
+				Source:             fmt.Sprintf("helm:%s", repo.Name),
+				PassCredentialsAll: repo.PassCredentialsAll,
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRepositoriesYAML = `apiVersion: ""
+generated: "0001-01-01T00:00:00Z"
+repositories:
+- name: bitnami
+  url: https://charts.bitnami.com/bitnami
+- name: internal
+  url: https://charts.internal.example.com/stable
+  username: helm-user
+  password: helm-pass
+  pass_credentials_all: false
+- name: shared
+  url: https://shared.example.com/charts
+  username: shared-user
+  password: shared-pass
+  pass_credentials_all: true
+`
+
+func writeRepositoriesFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "repositories.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testRepositoriesYAML), 0600))
+	return path
+}
+
+func TestLoadHelmRepositories(t *testing.T) {
+	repos, err := LoadHelmRepositories(writeRepositoriesFile(t))
+	require.NoError(t, err)
+	require.Len(t, repos, 3)
+	assert.Equal(t, "bitnami", repos[0].Name)
+	assert.Equal(t, "helm-user", repos[1].Username)
+	assert.True(t, repos[2].PassCredentialsAll)
+}
+
+func TestProvider_LoadHelmRepositoryConfig_MissingFile(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	p, err := NewProvider(nil, logger)
+	require.NoError(t, err)
+
+	err = p.LoadHelmRepositoryConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.NoError(t, err)
+	assert.Empty(t, p.helmRepos)
+}
+
+func TestProvider_ResolveRepoURL(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	p, err := NewProvider(nil, logger)
+	require.NoError(t, err)
+	require.NoError(t, p.LoadHelmRepositoryConfig(writeRepositoriesFile(t)))
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"at alias", "@bitnami", "https://charts.bitnami.com/bitnami"},
+		{"alias prefix", "alias:internal", "https://charts.internal.example.com/stable"},
+		{"bare name", "shared", "https://shared.example.com/charts"},
+		{"unknown alias", "@unknown", "@unknown"},
+		{"url unchanged", "https://charts.example.com", "https://charts.example.com"},
+		{"oci reference unchanged", "ghcr.io/bitnami", "ghcr.io/bitnami"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, p.ResolveRepoURL(tt.input))
+		})
+	}
+}
+
+func TestProvider_GetCredentials_HelmRepositories(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	t.Run("credentials scoped to repository URL", func(t *testing.T) {
+		p, err := NewProvider(nil, logger)
+		require.NoError(t, err)
+		require.NoError(t, p.LoadHelmRepositoryConfig(writeRepositoriesFile(t)))
+
+		creds := p.GetCredentials("https://charts.internal.example.com/stable")
+		require.NotNil(t, creds)
+		assert.Equal(t, "helm-user", creds.Username)
+		assert.Equal(t, "helm:internal", creds.Source)
+		assert.False(t, creds.PassCredentialsAll)
+
+		// Same host, different path, pass_credentials_all disabled
+		assert.Nil(t, p.GetCredentials("https://charts.internal.example.com/other"))
+	})
+
+	t.Run("pass_credentials_all is carried with the repository credentials", func(t *testing.T) {
+		p, err := NewProvider(nil, logger)
+		require.NoError(t, err)
+		require.NoError(t, p.LoadHelmRepositoryConfig(writeRepositoriesFile(t)))
+
+		creds := p.GetCredentials("https://shared.example.com/charts")
+		require.NotNil(t, creds)
+		assert.Equal(t, "shared-user", creds.Username)
+		assert.True(t, creds.PassCredentialsAll)
+
+		// It applies to URLs referenced from the repository's index, not to other repositories on the host
+		assert.Nil(t, p.GetCredentials("https://shared.example.com/other"))
+	})
+
+	t.Run("config credentials take precedence", func(t *testing.T) {
+		p, err := NewProvider([]ConfigAuth{{
+			URL:      "charts.internal.example.com",
+			Username: "config-user",
+			Password: "config-pass",
+		}}, logger)
+		require.NoError(t, err)
+		require.NoError(t, p.LoadHelmRepositoryConfig(writeRepositoriesFile(t)))
+
+		creds := p.GetCredentials("https://charts.internal.example.com/stable")
+		require.NotNil(t, creds)
+		assert.Equal(t, "config-user", creds.Username)
+	})
+}
+
+func TestDefaultHelmRepositoryConfig_EnvOverride(t *testing.T) {
+	t.Setenv("HELM_REPOSITORY_CONFIG", "/custom/repositories.yaml")
+	assert.Equal(t, "/custom/repositories.yaml", DefaultHelmRepositoryConfig())
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/sirupsen/logrus"
+
+	"argazer/internal/keychain"
 )
 
 // Credentials holds authentication credentials for a registry or repository
@@ -13,11 +15,25 @@ type Credentials struct {
 	Username string
 	Password string
 	Source   string // "config", "env", etc.
+
+	// PassCredentialsAll also sends the credentials to chart archives the repository index serves from
+	// other hosts (Helm's pass_credentials_all)
+	PassCredentialsAll bool
+
+	// SSH authentication of Git repositories
+	SSHKey           string // Path to a private key
+	SSHKeyPassphrase string // Passphrase of an encrypted SSHKey
+	SSHAgent         bool   // Use the keys of the ssh-agent at SSH_AUTH_SOCK
 }
 
 // Provider manages authentication for various registries and repositories
 type Provider struct {
 	credentials map[string]Credentials
+	ecr         *ecrCredentials    // ECR authorization tokens, nil unless enabled
+	gcp         *gcpCredentials    // Google Cloud access tokens, nil unless enabled
+	azure       *azureCredentials  // Azure Container Registry tokens, nil unless enabled
+	docker      *dockerCredentials // Docker config files, nil unless loaded
+	helmRepos   []HelmRepository   // Entries from Helm's repositories.yaml (lowest precedence)
 	logger      *logrus.Entry
 }
 
@@ -26,6 +42,10 @@ type ConfigAuth struct {
 	URL      string
 	Username string
 	Password string
+
+	SSHKey           string
+	SSHKeyPassphrase string
+	SSHAgent         bool
 }
 
 // NewProvider creates a new authentication provider
@@ -63,25 +83,104 @@ func (p *Provider) GetCredentials(repoURL string) *Credentials {
 		return &creds
 	}
 
+	// Then ECR authorization tokens
+	if p.ecr != nil {
+		if creds := p.ecr.credentials(normalized, p.logger); creds != nil {
+			p.logger.WithField("source", creds.Source).Debug("Found credentials")
+			return creds
+		}
+	}
+
+	// Then Google Cloud access tokens
+	if p.gcp != nil {
+		if creds := p.gcp.credentials(normalized, p.logger); creds != nil {
+			p.logger.WithField("source", creds.Source).Debug("Found credentials")
+			return creds
+		}
+	}
+
+	// Then Azure Container Registry tokens
+	if p.azure != nil {
+		if creds := p.azure.credentials(normalized, p.logger); creds != nil {
+			p.logger.WithField("source", creds.Source).Debug("Found credentials")
+			return creds
+		}
+	}
+
+	// Then Docker's registry credentials
+	if p.docker != nil {
+		if creds := p.docker.credentials(dockerHost(repoURL, p), p.logger); creds != nil {
+			p.logger.WithField("source", creds.Source).Debug("Found credentials")
+			return creds
+		}
+	}
+
+	// Fall back to credentials from the local Helm configuration
+	if creds := p.helmRepoCredentials(repoURL); creds != nil {
+		p.logger.WithField("source", creds.Source).Debug("Found credentials")
+		return creds
+	}
+
 	p.logger.Debug("No credentials found, will try anonymous access")
 	return nil
 }
 
+// AddCredentials registers credentials from an external source (e.g. ArgoCD)
+// Credentials from the config file and environment variables take precedence,
+// so existing entries for the same registry are not overwritten.
+// Returns true if the credentials were added.
+func (p *Provider) AddCredentials(url, username, password, source string) bool {
+	if url == "" || username == "" || password == "" {
+		return false
+	}
+
+	normalized := p.normalizeURL(url)
+	if existing, ok := p.credentials[normalized]; ok {
+		p.logger.WithFields(logrus.Fields{
+			"url":             url,
+			"source":          source,
+			"existing_source": existing.Source,
+		}).Debug("Credentials already configured, skipping")
+		return false
+	}
+
+	p.credentials[normalized] = Credentials{
+		Username: username,
+		Password: password,
+		Source:   source,
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"url":        url,
+		"normalized": normalized,
+		"source":     source,
+	}).Debug("Added credentials")
+
+	return true
+}
+
 // normalizeURL normalizes a URL for credential matching
 // Examples:
 //   - "https://charts.example.com" -> "charts.example.com"
 //   - "registry.example.com/helm" -> "registry.example.com"
 //   - "ghcr.io/myorg/charts" -> "ghcr.io"
+//   - "git@github.com:myorg/charts.git" -> "github.com"
 func (p *Provider) normalizeURL(repoURL string) string {
 	// Remove protocol if present
 	repoURL = strings.TrimPrefix(repoURL, "https://")
 	repoURL = strings.TrimPrefix(repoURL, "http://")
 	repoURL = strings.TrimPrefix(repoURL, "oci://")
+	repoURL = strings.TrimPrefix(repoURL, "ssh://")
 
 	// Extract hostname/registry part (before first slash or use whole string)
 	parts := strings.SplitN(repoURL, "/", 2)
 	hostname := parts[0]
 
+	// Remove the user of SSH URLs
+	if i := strings.LastIndex(hostname, "@"); i >= 0 {
+		hostname = hostname[i+1:]
+	}
+
 	// Remove port if present
 	hostname = strings.Split(hostname, ":")[0]
 
@@ -91,7 +190,9 @@ func (p *Provider) normalizeURL(repoURL string) string {
 // loadConfigAuth loads authentication from config file
 func (p *Provider) loadConfigAuth(configAuths []ConfigAuth) {
 	for _, auth := range configAuths {
-		if auth.URL == "" || auth.Username == "" || auth.Password == "" {
+		// SSH keys and the agent authenticate Git repositories without a password
+		ssh := auth.SSHKey != "" || auth.SSHAgent
+		if auth.URL == "" || (!ssh && (auth.Username == "" || auth.Password == "")) {
 			p.logger.WithField("url", auth.URL).Warn("Incomplete auth configuration, skipping")
 			continue
 		}
@@ -99,9 +200,12 @@ func (p *Provider) loadConfigAuth(configAuths []ConfigAuth) {
 		// Normalize the URL and store credentials
 		normalized := p.normalizeURL(auth.URL)
 		p.credentials[normalized] = Credentials{
-			Username: auth.Username,
-			Password: auth.Password,
-			Source:   "config",
+			Username:         auth.Username,
+			Password:         auth.Password,
+			Source:           "config",
+			SSHKey:           auth.SSHKey,
+			SSHKeyPassphrase: auth.SSHKeyPassphrase,
+			SSHAgent:         auth.SSHAgent,
 		}
 
 		p.logger.WithFields(logrus.Fields{
@@ -166,6 +270,13 @@ func (p *Provider) loadEnvAuth() {
 			continue
 		}
 
+		// Passwords may reference the OS keychain, e.g. AG_AUTH_PASS_1=keychain:harbor
+		pass, err := keychain.Resolve(pass)
+		if err != nil {
+			p.logger.WithError(err).WithField("id", id).Warn("Failed to resolve auth password from keychain")
+			continue
+		}
+
 		// Normalize the URL and store credentials (env vars override config)
 		normalized := p.normalizeURL(url)
 		p.credentials[normalized] = Credentials{
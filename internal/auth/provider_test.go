@@ -53,6 +53,16 @@ func TestNormalizeURL(t *testing.T) {
 			input:    "localhost:5000",
 			expected: "localhost",
 		},
+		{
+			name:     "scp-like SSH URL",
+			input:    "git@github.com:myorg/charts.git",
+			expected: "github.com",
+		},
+		{
+			name:     "SSH URL with port",
+			input:    "ssh://git@gitlab.example.com:2222/myorg/charts.git",
+			expected: "gitlab.example.com",
+		},
 	}
 
 	for _, tt := range tests {
@@ -129,6 +139,16 @@ func TestNewProvider(t *testing.T) {
 		// Only the valid one should be loaded
 		assert.Equal(t, 1, len(p.credentials))
 	})
+
+	t.Run("SSH key without password", func(t *testing.T) {
+		p, err := NewProvider([]ConfigAuth{{URL: "github.com", SSHKey: "/etc/argazer/id_ed25519", SSHKeyPassphrase: "secret"}}, logger)
+		require.NoError(t, err)
+
+		creds := p.GetCredentials("git@github.com:myorg/charts.git")
+		require.NotNil(t, creds)
+		assert.Equal(t, "/etc/argazer/id_ed25519", creds.SSHKey)
+		assert.Equal(t, "secret", creds.SSHKeyPassphrase)
+	})
 }
 
 func TestGetCredentials(t *testing.T) {
@@ -286,3 +306,26 @@ func TestEnvOverridesConfig(t *testing.T) {
 	assert.Equal(t, "envpass", creds.Password)
 	assert.Equal(t, "env:1", creds.Source)
 }
+
+func TestProvider_AddCredentials(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	p, err := NewProvider([]ConfigAuth{{
+		URL:      "https://charts.example.com",
+		Username: "config-user",
+		Password: "config-pass",
+	}}, logger)
+	require.NoError(t, err)
+
+	assert.True(t, p.AddCredentials("https://ghcr.io/myorg", "argo-user", "argo-pass", "argocd"))
+	creds := p.GetCredentials("ghcr.io")
+	require.NotNil(t, creds)
+	assert.Equal(t, "argo-user", creds.Username)
+	assert.Equal(t, "argocd", creds.Source)
+
+	// Existing config credentials are not overwritten
+	assert.False(t, p.AddCredentials("https://charts.example.com", "argo-user", "argo-pass", "argocd"))
+	assert.Equal(t, "config-user", p.GetCredentials("https://charts.example.com").Username)
+
+	// Incomplete credentials are rejected
+	assert.False(t, p.AddCredentials("https://other.example.com", "user", "", "argocd"))
+}
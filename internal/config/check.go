@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// maxKeySuggestionDistance is the largest edit distance between an unknown key and the key suggested for it
+const maxKeySuggestionDistance = 3
+
+// Problem is an issue found in the configuration by Check
+type Problem struct {
+	Key     string // Key of the problem, e.g. "argocd_instances[0].pasword"; empty for problems of the whole configuration
+	Message string
+	Warning bool // Unknown keys are warnings: they're ignored rather than failing a run
+}
+
+// String formats the problem, e.g. "warning: argocd_pasword: unknown key (did you mean argocd_password?)"
+func (p Problem) String() string {
+	level := "error"
+	if p.Warning {
+		level = "warning"
+	}
+	if p.Key == "" {
+		return level + ": " + p.Message
+	}
+	return level + ": " + p.Key + ": " + p.Message
+}
+
+// Check loads the configuration like Load and reports its problems: every unknown key of the
+// configuration file, and the first value of the wrong type or invalid or incomplete setting
+// Keychain references aren't resolved. file is the configuration file read, empty if there is none;
+// an error is returned when it can't be read at all.
+func Check() (file string, problems []Problem, err error) {
+	setDefaults()
+	if err := loadConfigFile(); err != nil {
+		return "", nil, err
+	}
+	file = viper.ConfigFileUsed()
+
+	// Keys are checked in the file alone, defaults and environment variables would hide typos
+	if file != "" {
+		fileConfig := viper.New()
+		fileConfig.SetConfigFile(file)
+		if err := fileConfig.ReadInConfig(); err != nil {
+			return file, nil, fmt.Errorf("error reading config file %s: %w", file, err)
+		}
+		problems = append(problems, unknownKeys(reflect.TypeOf(Config{}), fileConfig.AllSettings(), "")...)
+	}
+
+	setupEnvironment()
+	registerFlagAliases()
+
+	var cfg Config
+	if err := viper.Unmarshal(&cfg); err != nil {
+		problems = append(problems, Problem{Message: err.Error()})
+		return file, problems, nil
+	}
+	if err := validateConfig(&cfg); err != nil {
+		problems = append(problems, Problem{Message: err.Error()})
+	}
+	return file, problems, nil
+}
+
+// unknownKeys returns a warning for each key of value that isn't a field of the configuration type t
+func unknownKeys(t reflect.Type, value any, path string) []Problem {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var problems []Problem
+	switch t.Kind() {
+	case reflect.Struct:
+		settings, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			fields[name] = field.Type
+		}
+
+		for _, key := range sortedKeys(settings) {
+			fieldType, ok := fields[key]
+			if !ok {
+				message := "unknown key"
+				if suggestion := closestKey(key, fields); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean %s?)", suggestion)
+				}
+				problems = append(problems, Problem{Key: joinKey(path, key), Message: message, Warning: true})
+				continue
+			}
+			problems = append(problems, unknownKeys(fieldType, settings[key], joinKey(path, key))...)
+		}
+	case reflect.Map:
+		// Keys of maps are free-form, e.g. labels; their values may be structs
+		settings, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(settings) {
+			problems = append(problems, unknownKeys(t.Elem(), settings[key], joinKey(path, key))...)
+		}
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			problems = append(problems, unknownKeys(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return problems
+}
+
+// sortedKeys returns the keys of a map in order, so problems are reported in a stable order
+func sortedKeys(settings map[string]any) []string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinKey appends a key to the path of its parent
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// closestKey returns the known key most similar to an unknown one, empty if none is close
+func closestKey(key string, known map[string]reflect.Type) string {
+	best, bestDistance := "", maxKeySuggestionDistance+1
+	for candidate := range known {
+		distance := editDistance(key, candidate)
+		if distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
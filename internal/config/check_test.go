@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	settings := map[string]any{
+		"argocd_url":     "https://argocd.example.com",
+		"argocd_pasword": "secret",
+		"labels":         map[string]any{"team": "payments"},
+		"argocd_instances": []any{
+			map[string]any{"name": "prod", "url": "https://argocd.example.com", "pasword": "secret"},
+		},
+		"notification_templates": map[string]any{
+			"slack": map[string]any{"subject": "subject.tmpl", "bdy": "body.tmpl"},
+		},
+		"completely_unrelated": true,
+	}
+
+	assert.Equal(t, []Problem{
+		{Key: "argocd_instances[0].pasword", Message: "unknown key (did you mean password?)", Warning: true},
+		{Key: "argocd_pasword", Message: "unknown key (did you mean argocd_password?)", Warning: true},
+		{Key: "completely_unrelated", Message: "unknown key", Warning: true},
+		{Key: "notification_templates.slack.bdy", Message: "unknown key (did you mean body?)", Warning: true},
+	}, unknownKeys(reflect.TypeOf(Config{}), settings, ""))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("argocd_url", "argocd_url"))
+	assert.Equal(t, 1, editDistance("argocd_pasword", "argocd_password"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 5, editDistance("", "slack"))
+}
+
+func TestProblem_String(t *testing.T) {
+	assert.Equal(t, "warning: argocd_pasword: unknown key", Problem{Key: "argocd_pasword", Message: "unknown key", Warning: true}.String())
+	assert.Equal(t, "error: argocd_url is required", Problem{Message: "argocd_url is required"}.String())
+}
+
+func TestCheck(t *testing.T) {
+	defer viper.Reset()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`argocd_url: https://argocd.example.com
+argocd_username: admin
+argocd_pasword: secret
+notification_channel: telegram
+`), 0o600))
+
+	viper.Reset()
+	viper.Set("config", file)
+	checkedFile, problems, err := Check()
+	require.NoError(t, err)
+	assert.Equal(t, file, checkedFile)
+	require.Len(t, problems, 2)
+	assert.Equal(t, Problem{Key: "argocd_pasword", Message: "unknown key (did you mean argocd_password?)", Warning: true}, problems[0])
+	assert.False(t, problems[1].Warning)
+	assert.Contains(t, problems[1].Message, "argocd_password is required")
+
+	viper.Reset()
+	viper.Set("config", filepath.Join(t.TempDir(), "missing.yaml"))
+	_, _, err = Check()
+	assert.ErrorContains(t, err, "error reading config file")
+}
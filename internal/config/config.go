@@ -2,16 +2,114 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/spf13/viper"
+
+	"argazer/internal/i18n"
+	"argazer/internal/keychain"
+	"argazer/internal/trivy"
 )
 
 // Output format constants
 const (
-	OutputFormatTable    = "table"
-	OutputFormatJSON     = "json"
-	OutputFormatMarkdown = "markdown"
+	OutputFormatTable           = "table"
+	OutputFormatJSON            = "json"
+	OutputFormatMarkdown        = "markdown"
+	OutputFormatMarkdownCompact = "markdown-compact"
+	OutputFormatJUnit           = "junit"
+	OutputFormatHTML            = "html"
+)
+
+// outputFileFormats are the output formats inferred from the extension of an output file
+var outputFileFormats = map[string]string{
+	".txt":      OutputFormatTable,
+	".log":      OutputFormatTable,
+	".json":     OutputFormatJSON,
+	".md":       OutputFormatMarkdown,
+	".markdown": OutputFormatMarkdown,
+	".xml":      OutputFormatJUnit,
+	".html":     OutputFormatHTML,
+	".htm":      OutputFormatHTML,
+}
+
+// OutputFileFormat returns the output format of a file from its extension, e.g. "json" for "results.json"
+func OutputFileFormat(file string) (string, bool) {
+	format, ok := outputFileFormats[strings.ToLower(filepath.Ext(file))]
+	return format, ok
+}
+
+// Report grouping constants
+const (
+	GroupByNone     = "none"
+	GroupByProject  = "project"
+	GroupByRepo     = "repo"
+	GroupByChart    = "chart"
+	GroupBySeverity = "severity"
+	GroupByCluster  = "cluster"
+)
+
+// groupByValues are the accepted group_by values
+var groupByValues = []string{GroupByNone, GroupByProject, GroupByRepo, GroupByChart, GroupBySeverity, GroupByCluster}
+
+// Table report sorting constants
+const (
+	SortByApp      = "app"
+	SortByProject  = "project"
+	SortByChart    = "chart"
+	SortBySeverity = "severity"
+	SortByCurrent  = "current"
+	SortByLatest   = "latest"
+)
+
+// sortByValues are the accepted sort_by values
+var sortByValues = []string{SortByApp, SortByProject, SortByChart, SortBySeverity, SortByCurrent, SortByLatest}
+
+// Table report column constants
+const (
+	ColumnApp        = "app"
+	ColumnProject    = "project"
+	ColumnChart      = "chart"
+	ColumnCurrent    = "current"
+	ColumnLatest     = "latest"
+	ColumnSeverity   = "severity"
+	ColumnConstraint = "constraint"
+	ColumnRepo       = "repo"
+	ColumnCluster    = "cluster"
+	ColumnLink       = "link"
+	ColumnNote       = "note"
+)
+
+// Columns are the accepted columns of the table report
+var Columns = []string{ColumnApp, ColumnProject, ColumnChart, ColumnCurrent, ColumnLatest, ColumnSeverity, ColumnConstraint, ColumnRepo, ColumnCluster, ColumnLink, ColumnNote}
+
+// DefaultColumns are the columns of the table report when none are configured
+var DefaultColumns = []string{ColumnApp, ColumnProject, ColumnChart, ColumnCurrent, ColumnLatest, ColumnSeverity, ColumnNote}
+
+// Pull/merge request comment constants
+const (
+	PRCommentGitHub          = "github"
+	PRCommentGitLab          = "gitlab"
+	PRCommentBitbucket       = "bitbucket"
+	PRCommentBitbucketServer = "bitbucket-server"
+)
+
+// GitOps pull/merge request provider constants
+const (
+	GitOpsGitHub = "github"
+	GitOpsGitLab = "gitlab"
+)
+
+// Result enrichment sources
+const (
+	EnrichArtifactHub = "artifacthub"
 )
 
 // Version constraint constants
@@ -21,60 +119,397 @@ const (
 	VersionConstraintPatch = "patch"
 )
 
+// Application source modes
+const (
+	ModeAPI        = "api"        // ArgoCD API server
+	ModeKubernetes = "kubernetes" // Application resources read from the Kubernetes API (in-cluster)
+)
+
 // Log format constants
 const (
 	LogFormatJSON = "json"
 	LogFormatText = "text"
 )
 
+// LogComponents are the components whose log level can be set in log_levels
+var LogComponents = []string{"argocd", "helm", "oci", "git", "notifier", "auth", "syslog", "grafana", "pr-comment", "state", "server"}
+
+// logLevels are the accepted log level names
+var logLevels = []string{"panic", "fatal", "error", "warn", "warning", "info", "debug", "trace"}
+
+// Exit code mode constants
+const (
+	ExitCodeModeSimple   = "simple"   // 0 on success, 1 on fatal errors
+	ExitCodeModeDetailed = "detailed" // Also 2 when updates are available and 4 when applications were skipped
+)
+
+// Max-apps selection constants
+const (
+	MaxAppsModeFirst  = "first"
+	MaxAppsModeSample = "sample"
+)
+
+// Fail-on constants
+const (
+	FailOnPatch             = "patch"
+	FailOnMinor             = "minor"
+	FailOnMajor             = "major"
+	FailOnSecurity          = "security"
+	FailOnUpdates           = "updates"            // Any available update
+	FailOnOutsideConstraint = "outside-constraint" // Any update, including newer versions outside the version constraint
+	FailOnErrors            = "errors"             // Applications that couldn't be checked
+	FailOnNone              = "none"               // Never, whatever the exit code mode
+)
+
+// Teams card format constants
+const (
+	TeamsCardFormatMessageCard = "messagecard" // Legacy connector card, deprecated by Microsoft
+	TeamsCardFormatAdaptive    = "adaptive"    // Adaptive Card, for Workflows webhooks
+)
+
+// Webhook payload format constants
+const (
+	WebhookPayloadText       = "text"       // {subject, message} per notification message
+	WebhookPayloadStructured = "structured" // Complete scan results, once per scan
+)
+
+// NotificationTemplateDefault is the notification_templates key applying to every channel
+const NotificationTemplateDefault = "default"
+
+// notificationChannels are the accepted notification channels
+var notificationChannels = []string{"telegram", "email", "slack", "teams", "discord", "googlechat", "webex", "opsgenie", "kafka", "mqtt", "webhook"}
+
+// Notification grouping constants
+const (
+	NotificationGroupingNone    = "none"
+	NotificationGroupingProject = "project"
+)
+
+// SyncStatuses are the ArgoCD sync statuses accepted by the sync_status filter
+var SyncStatuses = []string{"Synced", "OutOfSync", "Unknown"}
+
+// HealthStatuses are the ArgoCD health statuses accepted by the health_status filter
+var HealthStatuses = []string{"Healthy", "Progressing", "Degraded", "Suspended", "Missing", "Unknown"}
+
 // Config holds the application configuration
 type Config struct {
+	// How applications are read: "api" (ArgoCD API, default) or "kubernetes" (Application resources, in-cluster)
+	Mode string `mapstructure:"mode"`
+
 	// ArgoCD connection settings
-	ArgocdURL      string `mapstructure:"argocd_url"`
+	ArgocdURL      string `mapstructure:"argocd_url"` // Optional in kubernetes mode, where it's only used for links
 	ArgocdUsername string `mapstructure:"argocd_username"`
 	ArgocdPassword string `mapstructure:"argocd_password"`
 	ArgocdInsecure bool   `mapstructure:"argocd_insecure"` // Skip TLS verification
 
+	// Project-scoped ArgoCD tokens, keyed by project name
+	// Each project is scanned with its own client; username/password become optional.
+	ArgocdProjectTokens map[string]string `mapstructure:"argocd_project_tokens"`
+
+	// Several ArgoCD servers scanned into one report, instead of argocd_url and its credentials
+	ArgocdInstances []ArgocdInstance `mapstructure:"argocd_instances"`
+
+	// ArgoCD credential reuse
+	ArgocdRepoCredentials bool   `mapstructure:"argocd_repo_credentials"` // Reuse repository credentials stored in ArgoCD
+	ArgocdRepositories    bool   `mapstructure:"argocd_repositories"`     // Reuse the credentials of repositories connected in ArgoCD
+	ArgocdNamespace       string `mapstructure:"argocd_namespace"`        // Namespace of ArgoCD's repository secrets and, in kubernetes mode, AppProjects (in-cluster only)
+
+	// Read repository credentials straight from ArgoCD's repository and repo-creds Secrets in argocd_namespace (in-cluster only)
+	KubernetesSecretCredentials bool `mapstructure:"kubernetes_secret_credentials"`
+
+	// Sync windows
+	CheckSyncWindows bool `mapstructure:"check_sync_windows"` // Annotate updates blocked by a project sync window with the next allowed window
+
+	// Branch tracking
+	TrackBranches bool `mapstructure:"track_branches"` // Compare the chart version on a tracked branch's tip with the commit last synced
+
 	// Search scope
-	Projects []string          `mapstructure:"projects"`  // List of projects to check, or ["*"] for all
-	AppNames []string          `mapstructure:"app_names"` // List of app names to check, or ["*"] for all
-	Labels   map[string]string `mapstructure:"labels"`    // Label filters
+	Projects      []string          `mapstructure:"projects"`       // Projects to check: names, globs like "team-*" or regexes like "/^team-a-/", or ["*"] for all
+	AppNames      []string          `mapstructure:"app_names"`      // App names to check: names, globs or /regexes/, or ["*"] for all
+	AppNamespaces []string          `mapstructure:"app_namespaces"` // Namespaces of the Applications to check, or ["*"] for all
+	Labels        map[string]string `mapstructure:"labels"`         // Label filters
+	SyncStatus    []string          `mapstructure:"sync_status"`    // Only check applications with one of these sync statuses, empty for all
+	HealthStatus  []string          `mapstructure:"health_status"`  // Only check applications with one of these health statuses, empty for all
+
+	// Exclusions, applied after the filters above (and to the children of app-of-apps)
+	ExcludeProjects []string `mapstructure:"exclude_projects"`  // Projects not to check: names, globs or /regexes/
+	ExcludeAppNames []string `mapstructure:"exclude_app_names"` // App names not to check: names, globs or /regexes/
+	ExcludeRepos    []string `mapstructure:"exclude_repos"`     // Repository URLs whose applications aren't checked, matching every repository below them too
+
+	// App-of-apps: child Applications of matching applications are checked too, regardless of the filters
+	AppOfApps         bool `mapstructure:"app_of_apps"`           // Include the child Applications generated by app-of-apps charts
+	AppOfAppsMaxDepth int  `mapstructure:"app_of_apps_max_depth"` // Levels of nested app-of-apps followed
 
 	// Notification settings
-	NotificationChannel string `mapstructure:"notification_channel"` // "telegram", "email", "slack", "teams", "webhook", or empty
+	NotificationChannel  string `mapstructure:"notification_channel"`  // "telegram", "email", "slack", "teams", "discord", "googlechat", "webex", "opsgenie", "kafka", "mqtt", "webhook", or empty
+	NotificationGrouping string `mapstructure:"notification_grouping"` // "none" (all updates together) or "project" (one message per ArgoCD project)
+
+	// Notification templates by notification channel, "default" for every channel
+	NotificationTemplates map[string]NotificationTemplate `mapstructure:"notification_templates"`
+
+	// Notification style, by update severity (major, minor or patch version bump)
+	NotificationEmojiSecurity string `mapstructure:"notification_emoji_security"` // Emoji shown before updates fixing vulnerabilities (check_vulnerabilities)
+	NotificationEmojiMajor    string `mapstructure:"notification_emoji_major"`    // Emoji shown before major updates
+	NotificationEmojiMinor    string `mapstructure:"notification_emoji_minor"`
+	NotificationEmojiPatch    string `mapstructure:"notification_emoji_patch"`
+	NotificationColorSecurity string `mapstructure:"notification_color_security"`
+	NotificationColorMajor    string `mapstructure:"notification_color_major"` // Hex card color of messages containing major updates (Teams)
+	NotificationColorMinor    string `mapstructure:"notification_color_minor"`
+	NotificationColorPatch    string `mapstructure:"notification_color_patch"`
+	NotificationThemeColor    string `mapstructure:"notification_theme_color"` // Hex card color when no severity color is set (Teams)
+	NotificationSenderName    string `mapstructure:"notification_sender_name"` // Sender name override (Slack legacy webhooks)
+	NotificationIconEmoji     string `mapstructure:"notification_icon_emoji"`  // Sender icon emoji, e.g. ":package:" (Slack legacy webhooks)
+	NotificationIconURL       string `mapstructure:"notification_icon_url"`    // Sender avatar URL (Slack legacy webhooks)
 
 	// Telegram settings
-	TelegramWebhook string `mapstructure:"telegram_webhook"`
-	TelegramChatID  string `mapstructure:"telegram_chat_id"`
+	TelegramWebhook       string `mapstructure:"telegram_webhook"`
+	TelegramChatID        string `mapstructure:"telegram_chat_id"`
+	TelegramWebhookSecret string `mapstructure:"telegram_webhook_secret"` // secret_token set with setWebhook, verified on callbacks (serve mode)
 
 	// Email settings
-	EmailSmtpHost     string   `mapstructure:"email_smtp_host"`
-	EmailSmtpPort     int      `mapstructure:"email_smtp_port"`
-	EmailSmtpUsername string   `mapstructure:"email_smtp_username"`
-	EmailSmtpPassword string   `mapstructure:"email_smtp_password"`
-	EmailFrom         string   `mapstructure:"email_from"`
-	EmailTo           []string `mapstructure:"email_to"`
-	EmailUseTLS       bool     `mapstructure:"email_use_tls"`
+	EmailSmtpHost      string   `mapstructure:"email_smtp_host"`
+	EmailSmtpPort      int      `mapstructure:"email_smtp_port"`
+	EmailSmtpUsername  string   `mapstructure:"email_smtp_username"`
+	EmailSmtpPassword  string   `mapstructure:"email_smtp_password"`
+	EmailFrom          string   `mapstructure:"email_from"`
+	EmailTo            []string `mapstructure:"email_to"`
+	EmailUseTLS        bool     `mapstructure:"email_use_tls"`
+	EmailImplicitTLS   bool     `mapstructure:"email_implicit_tls"`    // Connect over TLS (SMTPS) instead of STARTTLS, implied by port 465
+	EmailTLSSkipVerify bool     `mapstructure:"email_tls_skip_verify"` // Skip SMTP server certificate verification
+	EmailTLSCAFile     string   `mapstructure:"email_tls_ca_file"`     // PEM bundle of CAs trusted for the SMTP server, besides the system ones
 
 	// Slack settings
-	SlackWebhook string `mapstructure:"slack_webhook"`
+	SlackWebhook       string `mapstructure:"slack_webhook"`
+	SlackSigningSecret string `mapstructure:"slack_signing_secret"` // Slack app signing secret, verified on interactive actions (serve mode)
 
 	// Microsoft Teams settings
-	TeamsWebhook string `mapstructure:"teams_webhook"`
+	TeamsWebhook    string `mapstructure:"teams_webhook"`
+	TeamsCardFormat string `mapstructure:"teams_card_format"` // "messagecard" (default) or "adaptive"
+
+	// Discord settings
+	DiscordWebhook string `mapstructure:"discord_webhook"`
+
+	// Google Chat settings
+	GoogleChatWebhook string `mapstructure:"googlechat_webhook"` // Incoming webhook URL of the space
+
+	// Webex settings
+	WebexBotToken string `mapstructure:"webex_bot_token"`
+	WebexRoomID   string `mapstructure:"webex_room_id"`
+
+	// Opsgenie settings
+	OpsgenieAPIKey           string   `mapstructure:"opsgenie_api_key"`           // API key of an API integration
+	OpsgenieAPIURL           string   `mapstructure:"opsgenie_api_url"`           // API base URL, https://api.eu.opsgenie.com for EU accounts
+	OpsgenieResponders       []string `mapstructure:"opsgenie_responders"`        // "team:<name>", "user:<username>", "escalation:<name>", "schedule:<name>", or a team name
+	OpsgenieTags             []string `mapstructure:"opsgenie_tags"`              // Tags added to every alert besides "argazer"
+	OpsgeniePrioritySecurity string   `mapstructure:"opsgenie_priority_security"` // Priority of alerts containing updates fixing vulnerabilities
+	OpsgeniePriorityMajor    string   `mapstructure:"opsgenie_priority_major"`    // Priority ("P1" to "P5") of alerts containing major updates
+	OpsgeniePriorityMinor    string   `mapstructure:"opsgenie_priority_minor"`
+	OpsgeniePriorityPatch    string   `mapstructure:"opsgenie_priority_patch"`
+
+	// Kafka settings
+	KafkaBrokers       []string `mapstructure:"kafka_brokers"`        // Bootstrap brokers as host:port
+	KafkaTopic         string   `mapstructure:"kafka_topic"`          // Topic receiving one event per outdated application
+	KafkaTLS           bool     `mapstructure:"kafka_tls"`            // Connect to brokers over TLS
+	KafkaTLSInsecure   bool     `mapstructure:"kafka_tls_insecure"`   // Skip broker certificate verification
+	KafkaSASLMechanism string   `mapstructure:"kafka_sasl_mechanism"` // "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512", or empty to disable SASL
+	KafkaUsername      string   `mapstructure:"kafka_username"`
+	KafkaPassword      string   `mapstructure:"kafka_password"`
+
+	// MQTT settings
+	MQTTBroker      string `mapstructure:"mqtt_broker"`    // Broker URL: mqtt://host:1883 or mqtts://host:8883
+	MQTTTopic       string `mapstructure:"mqtt_topic"`     // Topic template with {app}, {project} and {chart} placeholders
+	MQTTQoS         int    `mapstructure:"mqtt_qos"`       // Quality of service: 0, 1 or 2
+	MQTTRetain      bool   `mapstructure:"mqtt_retain"`    // Retain the last event of each topic for dashboards
+	MQTTClientID    string `mapstructure:"mqtt_client_id"` // Client identifier (default: argazer-<random>)
+	MQTTUsername    string `mapstructure:"mqtt_username"`
+	MQTTPassword    string `mapstructure:"mqtt_password"`
+	MQTTTLSInsecure bool   `mapstructure:"mqtt_tls_insecure"` // Skip broker certificate verification for mqtts:// brokers
 
 	// Generic Webhook settings
-	WebhookURL string `mapstructure:"webhook_url"`
+	WebhookURL           string            `mapstructure:"webhook_url"`
+	WebhookPayloadFormat string            `mapstructure:"webhook_payload_format"` // "text" ({subject, message}) or "structured" (complete scan results)
+	WebhookHeaders       map[string]string `mapstructure:"webhook_headers"`        // Extra request headers, e.g. Authorization
+	WebhookSecret        string            `mapstructure:"webhook_secret"`         // Key signing request bodies (X-Argazer-Signature-256), empty to disable
+
+	// Syslog sink, independent of the notification channel
+	SyslogAddress  string `mapstructure:"syslog_address"`  // "local", udp://host:514, tcp://host:601, tls://host:6514, unix:///path, or empty to disable
+	SyslogFacility string `mapstructure:"syslog_facility"` // Facility name, e.g. "local0"
+	SyslogSeverity string `mapstructure:"syslog_severity"` // Severity name, e.g. "notice"
+
+	// Grafana annotations of new updates, independent of the notification channel (implies history)
+	GrafanaURL          string   `mapstructure:"grafana_url"`           // Grafana base URL, or empty to disable
+	GrafanaToken        string   `mapstructure:"grafana_token"`         // Service account token with the annotations:write permission
+	GrafanaDashboardUID string   `mapstructure:"grafana_dashboard_uid"` // Dashboard of the annotations, empty for organization-wide annotations
+	GrafanaTags         []string `mapstructure:"grafana_tags"`          // Tags added to every annotation besides "argazer"
+
+	// Pull/merge request comment with the report, independent of the notification channel
+	PRComment           string `mapstructure:"pr_comment"`           // "github", "gitlab", "bitbucket", "bitbucket-server", or empty to disable
+	GitHubToken         string `mapstructure:"github_token"`         // Token allowed to comment on pull requests (default: $GITHUB_TOKEN)
+	GitHubRepository    string `mapstructure:"github_repository"`    // Repository as owner/name (default: $GITHUB_REPOSITORY)
+	GitHubPRNumber      int    `mapstructure:"github_pr_number"`     // Pull request number (default: detected in GitHub Actions)
+	GitHubAPIURL        string `mapstructure:"github_api_url"`       // API URL for GitHub Enterprise Server (default: $GITHUB_API_URL or https://api.github.com)
+	GitLabToken         string `mapstructure:"gitlab_token"`         // Access token with the api scope (default: $GITLAB_TOKEN)
+	GitLabProject       string `mapstructure:"gitlab_project"`       // Project ID or path (default: $CI_PROJECT_ID)
+	GitLabMRIID         int    `mapstructure:"gitlab_mr_iid"`        // Merge request IID (default: $CI_MERGE_REQUEST_IID)
+	GitLabAPIURL        string `mapstructure:"gitlab_api_url"`       // API URL including /api/v4 (default: $CI_API_V4_URL or https://gitlab.com/api/v4)
+	BitbucketURL        string `mapstructure:"bitbucket_url"`        // Bitbucket Server base URL; Cloud uses https://api.bitbucket.org/2.0
+	BitbucketToken      string `mapstructure:"bitbucket_token"`      // Repository, project or workspace access token
+	BitbucketUsername   string `mapstructure:"bitbucket_username"`   // Username for app password (Cloud) or password (Server) authentication
+	BitbucketPassword   string `mapstructure:"bitbucket_password"`   // App password (Cloud) or password (Server)
+	BitbucketWorkspace  string `mapstructure:"bitbucket_workspace"`  // Cloud workspace or Server project key (default: $BITBUCKET_WORKSPACE)
+	BitbucketRepository string `mapstructure:"bitbucket_repository"` // Repository slug (default: $BITBUCKET_REPO_SLUG)
+	BitbucketPRID       int    `mapstructure:"bitbucket_pr_id"`      // Pull request ID (default: $BITBUCKET_PR_ID)
+
+	// GitOps pull/merge requests, opened by `argazer update` instead of patching the applications
+	// Tokens and API URLs are shared with pr_comment (github_token, gitlab_token, ...).
+	GitOpsProvider       string             `mapstructure:"gitops_provider"`        // "github", "gitlab", or empty to patch applications directly
+	GitOpsRepositories   []GitOpsRepository `mapstructure:"gitops_repositories"`    // Repositories holding the Application manifests; the first match wins
+	GitOpsBranchTemplate string             `mapstructure:"gitops_branch_template"` // Branch name template (default: "argazer/{{.AppName}}-{{.LatestVersion}}")
+	GitOpsTitleTemplate  string             `mapstructure:"gitops_title_template"`  // Title and commit message template
+	GitOpsBodyTemplate   string             `mapstructure:"gitops_body_template"`   // Description template (default: a summary table of the update)
 
 	// General settings
-	Verbose           bool   `mapstructure:"verbose"`
-	LogFormat         string `mapstructure:"log_format"`         // Log format: "json" or "text" (default: "json")
-	SourceName        string `mapstructure:"source_name"`        // Name of the source to check in multi-source applications
-	Concurrency       int    `mapstructure:"concurrency"`        // Number of concurrent workers for checking applications
-	VersionConstraint string `mapstructure:"version_constraint"` // Version constraint: "major", "minor", "patch" (default: "major")
-	OutputFormat      string `mapstructure:"output_format"`      // Output format: "table", "json", "markdown" (default: "table")
+	Verbose           bool     `mapstructure:"verbose"`
+	LogFormat         string   `mapstructure:"log_format"`         // Log format: "json" or "text" (default: "json")
+	SourceName        string   `mapstructure:"source_name"`        // Name of the source to check in multi-source applications
+	Concurrency       int      `mapstructure:"concurrency"`        // Number of concurrent workers for checking applications
+	MaxApps           int      `mapstructure:"max_apps"`           // Check at most this many applications after filtering (0 = all)
+	MaxAppsMode       string   `mapstructure:"max_apps_mode"`      // Which applications max_apps keeps: "first" (by name) or "sample" (default: "first")
+	SampleSeed        string   `mapstructure:"sample_seed"`        // Seed of the "sample" selection; the same seed picks the same applications
+	VersionConstraint string   `mapstructure:"version_constraint"` // Version constraint: "major", "minor", "patch" (default: "major")
+	OutputFormat      string   `mapstructure:"output_format"`      // Output format: "table", "json", "markdown", "markdown-compact", "junit" (default: "table")
+	GroupBy           string   `mapstructure:"group_by"`           // Groups the table, markdown and dashboard reports: "none", "project", "repo", "chart", "severity" or "cluster" (default: "none")
+	SortBy            string   `mapstructure:"sort_by"`            // Row order of the table report: "app", "project", "chart", "severity", "current" or "latest" (default: "app")
+	Columns           []string `mapstructure:"columns"`            // Columns of the table report, in order (default: app, project, chart, current, latest, severity, note)
+	JSONCompact       bool     `mapstructure:"json_compact"`       // Print the JSON report as one line per application (NDJSON) instead of a document
+	OutputFiles       []string `mapstructure:"output_files"`       // Files the report is also written to, in the format of their extension (.txt, .json, .md, .xml, .html)
+	Language          string   `mapstructure:"language"`           // Language of reports and notifications: "en", "de", "fr", "es" (default: "en")
+	ExitCodeMode      string   `mapstructure:"exit_code_mode"`     // Exit code mode: "simple" or "detailed" (default: "simple")
+	FailOn            string   `mapstructure:"fail_on"`            // Scan outcome that fails: "updates", "outside-constraint", "errors", "none", or updates at or above "patch", "minor", "major" or "security" (default: "")
+	Redact            bool     `mapstructure:"redact"`             // Mask repository hostnames, URLs and project names in reports
+	Progress          bool     `mapstructure:"progress"`           // Draw a progress bar on stderr while scanning for a table report in a terminal (default: true)
+
+	// Version constraints per application or chart name (application names win), overriding
+	// version_constraint: a keyword or a semver range such as ">=1.2.0 <2.0.0"
+	Constraints map[string]string `mapstructure:"constraints"`
+
+	// Logging
+	LogLevels         map[string]string `mapstructure:"log_levels"`          // Log level per component, e.g. helm: debug (overrides verbose for that component)
+	LogSampleBurst    int               `mapstructure:"log_sample_burst"`    // Identical debug lines logged per component and interval before the rest are dropped (0 disables sampling)
+	LogSampleInterval time.Duration     `mapstructure:"log_sample_interval"` // Window of log_sample_burst
+	LogFile           string            `mapstructure:"log_file"`            // Write logs to this file instead of stderr
+
+	// Deadlines (0 disables them)
+	Timeout       time.Duration `mapstructure:"timeout"`        // Whole run, or each scan cycle in serve mode
+	ArgocdTimeout time.Duration `mapstructure:"argocd_timeout"` // Listing applications and sync windows from ArgoCD
+	HelmTimeout   time.Duration `mapstructure:"helm_timeout"`   // Each chart lookup in a Helm repository
+	OCITimeout    time.Duration `mapstructure:"oci_timeout"`    // Each chart lookup in an OCI registry
+	GitTimeout    time.Duration `mapstructure:"git_timeout"`    // Each chart lookup in a Git repository
+	NotifyTimeout time.Duration `mapstructure:"notify_timeout"` // Each notification, event batch and pull request comment
+
+	// Consecutive failures to reach a repository after which its remaining applications are skipped (0 disables it)
+	CircuitBreakerThreshold int `mapstructure:"circuit_breaker_threshold"`
+
+	// Retries of index.yaml and OCI tags list requests failing to reach the repository
+	RegistryRetryAttempts int           `mapstructure:"registry_retry_attempts"` // Requests sent at most, including the first (1 disables retries)
+	RegistryRetryDelay    time.Duration `mapstructure:"registry_retry_delay"`    // Delay before the first retry, doubled for each further one
 
 	// Repository authentication
 	RepositoryAuth []RepositoryAuth `mapstructure:"repository_auth"`
+
+	// Connections to Helm repositories and OCI registries
+	ProxyURL      string          `mapstructure:"proxy_url"`      // Outbound proxy (default: HTTPS_PROXY, HTTP_PROXY and NO_PROXY)
+	RepositoryTLS []RepositoryTLS `mapstructure:"repository_tls"` // TLS settings by repository host
+	Repositories  []Repository    `mapstructure:"repositories"`   // Credentials, TLS, timeout and disabling by repository
+
+	// Non-release tags and versions ignored when looking for the latest version
+	ExcludedTags            []string                 `mapstructure:"excluded_tags"`             // Exact tags, e.g. "latest"
+	ExcludedTagPatterns     []string                 `mapstructure:"excluded_tag_patterns"`     // Regular expressions, e.g. "-nightly$"
+	RepositoryTagExclusions []RepositoryTagExclusion `mapstructure:"repository_tag_exclusions"` // Per-repository overrides
+
+	// Updates left out of update counts, notifications and exit codes, reported as ignored
+	Ignore []IgnoreRule `mapstructure:"ignore"`
+
+	// Serve mode
+	ServeAddress   string        `mapstructure:"serve_address"`   // Listen address for callbacks and health checks
+	ServeInterval  time.Duration `mapstructure:"serve_interval"`  // Time between scans
+	ServeDashboard bool          `mapstructure:"serve_dashboard"` // Serve an HTML dashboard of the last scan at /
+	StateFile      string        `mapstructure:"state_file"`      // JSON file storing acknowledgements, notification state and the scan history
+	ChatOpsToken   string        `mapstructure:"chatops_token"`   // Bearer token of scans requested with POSTs to /chatops (Slack slash commands use slack_signing_secret)
+
+	// Scan history, recorded in state_file
+	History       bool `mapstructure:"history"`         // Record the updates of each scan (compared by `argazer diff`)
+	NotifyOnlyNew bool `mapstructure:"notify_only_new"` // Only notify updates that weren't available in the previous scan (implies history)
+
+	// Local Helm configuration
+	UseHelmConfig        bool   `mapstructure:"use_helm_config"`        // Reuse repositories and credentials from Helm's repositories.yaml
+	HelmRepositoryConfig string `mapstructure:"helm_repository_config"` // Path to repositories.yaml (default: Helm's own location)
+
+	// Docker registry credentials
+	UseDockerConfig   bool     `mapstructure:"use_docker_config"`   // Reuse registry credentials from Docker config files, with credential helpers
+	DockerConfigFiles []string `mapstructure:"docker_config_files"` // config.json or mounted .dockerconfigjson files (default: Docker's config.json)
+
+	// AWS ECR authentication
+	ECRAuth bool `mapstructure:"ecr_auth"` // Obtain tokens for *.dkr.ecr.*.amazonaws.com registries from AWS
+
+	// Google Artifact Registry / Container Registry authentication
+	GCPAuth bool `mapstructure:"gcp_auth"` // Obtain access tokens for *.pkg.dev and gcr.io registries with Application Default Credentials
+
+	// Azure Container Registry authentication
+	AzureAuth bool `mapstructure:"azure_auth"` // Obtain tokens for *.azurecr.io registries with a service principal, workload identity or managed identity
+
+	// Temporary files
+	TempDirMaxAge time.Duration `mapstructure:"temp_dir_max_age"` // Age after which leftover Git clone directories are removed at startup (0 disables cleanup)
+
+	// Helm repository index cache
+	IndexCacheTTL time.Duration `mapstructure:"index_cache_ttl"` // How long a downloaded index.yaml is reused before it's revalidated (0 disables the cache)
+	CacheDir      string        `mapstructure:"cache_dir"`       // Directory keeping indexes across runs (default: memory only)
+	CacheMaxAge   time.Duration `mapstructure:"cache_max_age"`   // Indexes in cache_dir not refreshed for this long are removed (0 keeps them)
+	CacheMaxSize  int           `mapstructure:"cache_max_size"`  // Megabytes cache_dir may hold before the least recently refreshed indexes are removed (0 = unlimited)
+
+	// Release notes of updates, from Artifact Hub annotations or GitHub/GitLab releases
+	// GitHub and GitLab lookups use github_token and gitlab_token when set.
+	ReleaseNotes          bool `mapstructure:"release_notes"`            // Look up the release notes of each update
+	ReleaseNotesMaxLength int  `mapstructure:"release_notes_max_length"` // Characters of the release notes excerpt (0 keeps them whole)
+
+	// Result enrichment with chart metadata from public sources
+	Enrich            []string `mapstructure:"enrich"`              // Sources to look charts up in: "artifacthub"
+	ArtifactHubAPIURL string   `mapstructure:"artifacthub_api_url"` // Artifact Hub API URL (default: https://artifacthub.io/api/v1)
+
+	// Container image tag checks
+	CheckImages bool `mapstructure:"check_images"` // Check the container images of applications for newer tags
+
+	// Subchart checks of Git-based charts
+	CheckDependencies bool `mapstructure:"check_dependencies"` // Check the Chart.yaml dependencies of Git-based charts for newer versions
+
+	// Default values comparison of updates
+	CheckValuesDiff bool `mapstructure:"check_values_diff"` // Report the values.yaml keys added, removed or changed by each update
+
+	// Vulnerability scans of the images of current and latest chart versions
+	CheckVulnerabilities bool     `mapstructure:"check_vulnerabilities"` // Scan the images of each update's versions with Trivy
+	TrivyPath            string   `mapstructure:"trivy_path"`            // Trivy binary (default: trivy in PATH)
+	TrivySeverities      []string `mapstructure:"trivy_severities"`      // Vulnerability severities taken into account, empty for all
+}
+
+// NotificationTemplate holds the paths of Go text/template files replacing the notification layout
+// Empty fields keep the built-in subject or layout.
+type NotificationTemplate struct {
+	Subject string `mapstructure:"subject"`
+	Body    string `mapstructure:"body"`
+}
+
+// NotificationTemplateFor returns the templates of a notification channel, falling back to the
+// "default" templates field by field
+func (c *Config) NotificationTemplateFor(channel string) NotificationTemplate {
+	tmpl := c.NotificationTemplates[channel]
+	fallback := c.NotificationTemplates[NotificationTemplateDefault]
+	if tmpl.Subject == "" {
+		tmpl.Subject = fallback.Subject
+	}
+	if tmpl.Body == "" {
+		tmpl.Body = fallback.Body
+	}
+	return tmpl
 }
 
 // RepositoryAuth holds authentication for a specific repository or registry
@@ -82,10 +517,158 @@ type RepositoryAuth struct {
 	URL      string `mapstructure:"url"`
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
+
+	// SSH authentication of Git repositories (git@host:org/repo.git or ssh:// URLs)
+	SSHKey           string `mapstructure:"ssh_key"`            // Path to a private key
+	SSHKeyPassphrase string `mapstructure:"ssh_key_passphrase"` // Passphrase of an encrypted ssh_key
+	SSHAgent         bool   `mapstructure:"ssh_agent"`          // Use the keys of the ssh-agent at SSH_AUTH_SOCK
+}
+
+// RecordsHistory reports whether scans are recorded in the state file: notify_only_new and Grafana
+// annotations compare each scan with the previous one
+func (cfg *Config) RecordsHistory() bool {
+	return cfg.History || cfg.NotifyOnlyNew || cfg.GrafanaURL != ""
+}
+
+// ArgocdInstance holds the connection settings of one of several ArgoCD servers scanned in one run
+type ArgocdInstance struct {
+	Name          string            `mapstructure:"name"` // Shown with the instance's applications, e.g. "production"
+	URL           string            `mapstructure:"url"`
+	Username      string            `mapstructure:"username"`
+	Password      string            `mapstructure:"password"`
+	Insecure      bool              `mapstructure:"insecure"`       // Skip TLS verification
+	ProjectTokens map[string]string `mapstructure:"project_tokens"` // Project-scoped tokens, like argocd_project_tokens
+}
+
+// InstanceConfig returns a copy of the configuration connecting to an ArgoCD instance instead of argocd_url
+func (cfg *Config) InstanceConfig(instance ArgocdInstance) *Config {
+	instanceCfg := *cfg
+	instanceCfg.ArgocdURL = instance.URL
+	instanceCfg.ArgocdUsername = instance.Username
+	instanceCfg.ArgocdPassword = instance.Password
+	instanceCfg.ArgocdInsecure = instance.Insecure
+	instanceCfg.ArgocdProjectTokens = instance.ProjectTokens
+	instanceCfg.ArgocdInstances = nil
+	return &instanceCfg
+}
+
+// InstanceURL returns the URL of the ArgoCD instance with the given name, or argocd_url for an empty name
+func (cfg *Config) InstanceURL(name string) string {
+	if name == "" {
+		return cfg.ArgocdURL
+	}
+	for _, instance := range cfg.ArgocdInstances {
+		if instance.Name == name {
+			return instance.URL
+		}
+	}
+	return ""
+}
+
+// GitOpsRepository maps applications to the Git repository and file holding their manifests
+type GitOpsRepository struct {
+	Project    string `mapstructure:"project"`     // ArgoCD project, empty for all
+	App        string `mapstructure:"app"`         // Application name glob, e.g. "team-*" (default: all)
+	Repository string `mapstructure:"repository"`  // owner/name on GitHub, project ID or path on GitLab
+	Path       string `mapstructure:"path"`        // Manifest path template, e.g. "apps/{{.AppName}}.yaml"
+	BaseBranch string `mapstructure:"base_branch"` // Target branch (default: the repository's default branch)
+}
+
+// Repository holds all settings of a repository or registry: credentials like repository_auth, TLS
+// settings like repository_tls, and lookup settings for the repository and every repository below its URL
+type Repository struct {
+	URL                string        `mapstructure:"url"`
+	Username           string        `mapstructure:"username"`
+	Password           string        `mapstructure:"password"`
+	CAFile             string        `mapstructure:"ca_file"`              // PEM bundle of CAs trusted besides the system ones (applies to the host)
+	InsecureSkipVerify bool          `mapstructure:"insecure_skip_verify"` // Skip certificate verification (applies to the host)
+	Timeout            time.Duration `mapstructure:"timeout"`              // Each chart lookup, replacing helm_timeout, oci_timeout or git_timeout
+	Disabled           bool          `mapstructure:"disabled"`             // Skip the applications using the repository
+}
+
+// RepositoryTLS holds the TLS settings of a repository or registry host, e.g. for a private CA
+type RepositoryTLS struct {
+	URL                string `mapstructure:"url"`
+	CAFile             string `mapstructure:"ca_file"`              // PEM bundle of CAs trusted besides the system ones
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"` // Skip certificate verification
+}
+
+// RepositoryTagExclusion replaces the excluded tags and patterns for a repository and every
+// repository below its URL
+type RepositoryTagExclusion struct {
+	URL      string   `mapstructure:"url"`
+	Tags     []string `mapstructure:"tags"`
+	Patterns []string `mapstructure:"patterns"`
+}
+
+// IgnoreRule ignores the updates of the applications it matches, e.g. known-bad versions or
+// intentionally pinned applications
+// Empty fields match everything; a rule needs at least one of app, chart, repo or version.
+type IgnoreRule struct {
+	App     string `mapstructure:"app"`     // Application name glob, e.g. "team-*"
+	Chart   string `mapstructure:"chart"`   // Chart name glob
+	Repo    string `mapstructure:"repo"`    // Repository URL, matching every repository below it too
+	Version string `mapstructure:"version"` // Latest version glob, e.g. "2.*"
+	Until   string `mapstructure:"until"`   // Date (2006-01-02, inclusive) or RFC 3339 time the rule expires at
+	Reason  string `mapstructure:"reason"`  // Shown with the ignored update
+}
+
+// Expiry returns the time the rule stops applying, zero if it doesn't expire
+// A date expires at the end of that day (UTC).
+func (r IgnoreRule) Expiry() (time.Time, error) {
+	if r.Until == "" {
+		return time.Time{}, nil
+	}
+	if day, err := time.Parse(time.DateOnly, r.Until); err == nil {
+		return day.AddDate(0, 0, 1), nil
+	}
+	expiry, err := time.Parse(time.RFC3339, r.Until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("until must be a date (YYYY-MM-DD) or an RFC 3339 time (got: '%s')", r.Until)
+	}
+	return expiry, nil
 }
 
 // Load loads configuration from various sources
 func Load() (*Config, error) {
+	cfg, err := LoadUnvalidated()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolveKeychainSecrets(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadWithoutArgocd loads configuration like Load without requiring ArgoCD connection settings,
+// for commands that only look up charts
+func LoadWithoutArgocd() (*Config, error) {
+	cfg, err := LoadUnvalidated()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolveKeychainSecrets(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := validateSettings(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadUnvalidated loads configuration without resolving keychain references or validating it,
+// for commands that only use local settings such as the state file
+func LoadUnvalidated() (*Config, error) {
 	setDefaults()
 
 	if err := loadConfigFile(); err != nil {
@@ -100,11 +683,62 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
-	if err := validateConfig(&cfg); err != nil {
-		return nil, err
+	return &cfg, nil
+}
+
+// keychainResolve resolves "keychain:<name>" references, replaced by tests
+var keychainResolve = keychain.Resolve
+
+// resolveKeychainSecrets replaces ArgoCD and repository credentials that reference the OS keychain
+// with the stored secrets
+func resolveKeychainSecrets(cfg *Config) error {
+	resolve := func(key string, value *string) error {
+		if !keychain.IsReference(*value) {
+			return nil
+		}
+		secret, err := keychainResolve(*value)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", key, err)
+		}
+		*value = secret
+		return nil
 	}
 
-	return &cfg, nil
+	if err := resolve("argocd_password", &cfg.ArgocdPassword); err != nil {
+		return err
+	}
+	for project, token := range cfg.ArgocdProjectTokens {
+		if err := resolve("argocd_project_tokens."+project, &token); err != nil {
+			return err
+		}
+		cfg.ArgocdProjectTokens[project] = token
+	}
+	for i := range cfg.ArgocdInstances {
+		instance := &cfg.ArgocdInstances[i]
+		if err := resolve(fmt.Sprintf("argocd_instances[%d].password", i), &instance.Password); err != nil {
+			return err
+		}
+		for project, token := range instance.ProjectTokens {
+			if err := resolve(fmt.Sprintf("argocd_instances[%d].project_tokens.%s", i, project), &token); err != nil {
+				return err
+			}
+			instance.ProjectTokens[project] = token
+		}
+	}
+	for i := range cfg.RepositoryAuth {
+		if err := resolve(fmt.Sprintf("repository_auth[%d].password", i), &cfg.RepositoryAuth[i].Password); err != nil {
+			return err
+		}
+		if err := resolve(fmt.Sprintf("repository_auth[%d].ssh_key_passphrase", i), &cfg.RepositoryAuth[i].SSHKeyPassphrase); err != nil {
+			return err
+		}
+	}
+	for i := range cfg.Repositories {
+		if err := resolve(fmt.Sprintf("repositories[%d].password", i), &cfg.Repositories[i].Password); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // setDefaults sets default values for all configuration fields
@@ -112,37 +746,195 @@ func setDefaults() {
 	// Boolean and numeric defaults
 	viper.SetDefault("verbose", false)
 	viper.SetDefault("argocd_insecure", false)
+	viper.SetDefault("argocd_repo_credentials", false)
+	viper.SetDefault("argocd_repositories", false)
+	viper.SetDefault("kubernetes_secret_credentials", false)
+	viper.SetDefault("check_sync_windows", false)
+	viper.SetDefault("track_branches", false)
+	viper.SetDefault("kafka_tls", false)
+	viper.SetDefault("kafka_tls_insecure", false)
+	viper.SetDefault("mqtt_qos", 1)
+	viper.SetDefault("mqtt_retain", false)
+	viper.SetDefault("mqtt_tls_insecure", false)
 	viper.SetDefault("email_smtp_port", 587)
 	viper.SetDefault("email_use_tls", true)
+	viper.SetDefault("email_implicit_tls", false)
+	viper.SetDefault("email_tls_skip_verify", false)
+	viper.SetDefault("email_tls_ca_file", "")
 	viper.SetDefault("concurrency", 10)
+	viper.SetDefault("github_pr_number", 0)
+	viper.SetDefault("gitlab_mr_iid", 0)
+	viper.SetDefault("bitbucket_pr_id", 0)
+	viper.SetDefault("use_helm_config", true)
+	viper.SetDefault("progress", true)
+	viper.SetDefault("json_compact", false)
 
 	// String defaults
 	viper.SetDefault("source_name", "chart-repo")
 	viper.SetDefault("version_constraint", VersionConstraintMajor)
 	viper.SetDefault("output_format", OutputFormatTable)
+	viper.SetDefault("group_by", GroupByNone)
+	viper.SetDefault("sort_by", SortByApp)
+	viper.SetDefault("columns", DefaultColumns)
+	viper.SetDefault("output_files", []string{})
+	viper.SetDefault("language", i18n.DefaultLanguage)
 	viper.SetDefault("log_format", LogFormatJSON)
+	viper.SetDefault("log_levels", map[string]string{})
+	viper.SetDefault("log_sample_burst", 0)
+	viper.SetDefault("log_sample_interval", time.Minute)
+	viper.SetDefault("log_file", "")
+	viper.SetDefault("exit_code_mode", ExitCodeModeSimple)
+	viper.SetDefault("fail_on", "")
+	viper.SetDefault("max_apps", 0)
+	viper.SetDefault("max_apps_mode", MaxAppsModeFirst)
+	viper.SetDefault("sample_seed", "")
+	viper.SetDefault("redact", false)
 	viper.SetDefault("argocd_url", "")
 	viper.SetDefault("argocd_username", "")
 	viper.SetDefault("argocd_password", "")
+	viper.SetDefault("argocd_namespace", "argocd")
+	viper.SetDefault("mode", ModeAPI)
 	viper.SetDefault("notification_channel", "")
+	viper.SetDefault("notification_grouping", NotificationGroupingNone)
+	viper.SetDefault("notification_emoji_security", "")
+	viper.SetDefault("notification_emoji_major", "")
+	viper.SetDefault("notification_emoji_minor", "")
+	viper.SetDefault("notification_emoji_patch", "")
+	viper.SetDefault("notification_color_security", "")
+	viper.SetDefault("notification_color_major", "")
+	viper.SetDefault("notification_color_minor", "")
+	viper.SetDefault("notification_color_patch", "")
+	viper.SetDefault("notification_theme_color", "0078D7")
+	viper.SetDefault("notification_sender_name", "")
+	viper.SetDefault("notification_icon_emoji", "")
+	viper.SetDefault("notification_icon_url", "")
 	viper.SetDefault("telegram_webhook", "")
 	viper.SetDefault("telegram_chat_id", "")
+	viper.SetDefault("telegram_webhook_secret", "")
 	viper.SetDefault("email_smtp_host", "")
 	viper.SetDefault("email_smtp_username", "")
 	viper.SetDefault("email_smtp_password", "")
 	viper.SetDefault("email_from", "")
 	viper.SetDefault("slack_webhook", "")
+	viper.SetDefault("slack_signing_secret", "")
 	viper.SetDefault("teams_webhook", "")
+	viper.SetDefault("teams_card_format", TeamsCardFormatMessageCard)
+	viper.SetDefault("discord_webhook", "")
+	viper.SetDefault("googlechat_webhook", "")
+	viper.SetDefault("webex_bot_token", "")
+	viper.SetDefault("webex_room_id", "")
+	viper.SetDefault("opsgenie_api_key", "")
+	viper.SetDefault("opsgenie_api_url", "https://api.opsgenie.com")
+	viper.SetDefault("opsgenie_priority_security", "P2")
+	viper.SetDefault("opsgenie_priority_major", "P3")
+	viper.SetDefault("opsgenie_priority_minor", "P4")
+	viper.SetDefault("opsgenie_priority_patch", "P5")
+	viper.SetDefault("kafka_topic", "")
+	viper.SetDefault("kafka_sasl_mechanism", "")
+	viper.SetDefault("kafka_username", "")
+	viper.SetDefault("kafka_password", "")
+	viper.SetDefault("mqtt_broker", "")
+	viper.SetDefault("mqtt_topic", "argazer/{project}/{app}")
+	viper.SetDefault("mqtt_client_id", "")
+	viper.SetDefault("mqtt_username", "")
+	viper.SetDefault("mqtt_password", "")
 	viper.SetDefault("webhook_url", "")
+	viper.SetDefault("webhook_payload_format", WebhookPayloadText)
+	viper.SetDefault("webhook_secret", "")
+	viper.SetDefault("syslog_address", "")
+	viper.SetDefault("syslog_facility", "local0")
+	viper.SetDefault("syslog_severity", "notice")
+	viper.SetDefault("grafana_url", "")
+	viper.SetDefault("grafana_token", "")
+	viper.SetDefault("grafana_dashboard_uid", "")
+	viper.SetDefault("pr_comment", "")
+	viper.SetDefault("github_token", "")
+	viper.SetDefault("github_repository", "")
+	viper.SetDefault("github_api_url", "")
+	viper.SetDefault("gitlab_token", "")
+	viper.SetDefault("gitlab_project", "")
+	viper.SetDefault("gitlab_api_url", "")
+	viper.SetDefault("bitbucket_url", "")
+	viper.SetDefault("bitbucket_token", "")
+	viper.SetDefault("bitbucket_username", "")
+	viper.SetDefault("bitbucket_password", "")
+	viper.SetDefault("bitbucket_workspace", "")
+	viper.SetDefault("bitbucket_repository", "")
+	viper.SetDefault("gitops_provider", "")
+	viper.SetDefault("gitops_repositories", []GitOpsRepository{})
+	viper.SetDefault("gitops_branch_template", "")
+	viper.SetDefault("gitops_title_template", "")
+	viper.SetDefault("gitops_body_template", "")
+	viper.SetDefault("helm_repository_config", "")
+	viper.SetDefault("use_docker_config", false)
+	viper.SetDefault("docker_config_files", []string{})
+	viper.SetDefault("ecr_auth", false)
+	viper.SetDefault("gcp_auth", false)
+	viper.SetDefault("azure_auth", false)
+	viper.SetDefault("serve_address", ":8080")
+	viper.SetDefault("serve_interval", 24*time.Hour)
+	viper.SetDefault("serve_dashboard", true)
+	viper.SetDefault("chatops_token", "")
+	viper.SetDefault("timeout", time.Duration(0))
+	viper.SetDefault("argocd_timeout", time.Duration(0))
+	viper.SetDefault("helm_timeout", time.Duration(0))
+	viper.SetDefault("oci_timeout", time.Duration(0))
+	viper.SetDefault("git_timeout", time.Duration(0))
+	viper.SetDefault("notify_timeout", time.Duration(0))
+	viper.SetDefault("temp_dir_max_age", time.Hour)
+	viper.SetDefault("index_cache_ttl", 5*time.Minute)
+	viper.SetDefault("cache_dir", "")
+	viper.SetDefault("cache_max_age", 7*24*time.Hour)
+	viper.SetDefault("cache_max_size", 512)
+	viper.SetDefault("release_notes", false)
+	viper.SetDefault("release_notes_max_length", 300)
+	viper.SetDefault("enrich", []string{})
+	viper.SetDefault("artifacthub_api_url", "")
+	viper.SetDefault("check_images", false)
+	viper.SetDefault("check_dependencies", false)
+	viper.SetDefault("check_values_diff", false)
+	viper.SetDefault("check_vulnerabilities", false)
+	viper.SetDefault("trivy_path", trivy.DefaultPath)
+	viper.SetDefault("trivy_severities", []string{})
+	viper.SetDefault("app_of_apps", false)
+	viper.SetDefault("app_of_apps_max_depth", 3)
+	viper.SetDefault("circuit_breaker_threshold", 3)
+	viper.SetDefault("registry_retry_attempts", 3)
+	viper.SetDefault("registry_retry_delay", time.Second)
+	viper.SetDefault("state_file", "argazer-state.json")
+	viper.SetDefault("history", false)
+	viper.SetDefault("notify_only_new", false)
 
 	// Array/slice defaults
 	viper.SetDefault("projects", []string{"*"})
 	viper.SetDefault("app_names", []string{"*"})
+	viper.SetDefault("app_namespaces", []string{"*"})
+	viper.SetDefault("exclude_projects", []string{})
+	viper.SetDefault("exclude_app_names", []string{})
+	viper.SetDefault("exclude_repos", []string{})
+	viper.SetDefault("sync_status", []string{})
+	viper.SetDefault("health_status", []string{})
 	viper.SetDefault("email_to", []string{})
+	viper.SetDefault("kafka_brokers", []string{})
+	viper.SetDefault("opsgenie_responders", []string{})
+	viper.SetDefault("opsgenie_tags", []string{})
+	viper.SetDefault("grafana_tags", []string{})
+	viper.SetDefault("excluded_tags", []string{"latest", "dev", "main", "master", "stable"})
+	viper.SetDefault("excluded_tag_patterns", []string{})
 
 	// Map defaults
 	viper.SetDefault("labels", map[string]string{})
+	viper.SetDefault("argocd_project_tokens", map[string]string{})
+	viper.SetDefault("argocd_instances", []ArgocdInstance{})
+	viper.SetDefault("constraints", map[string]string{})
+	viper.SetDefault("notification_templates", map[string]NotificationTemplate{})
+	viper.SetDefault("webhook_headers", map[string]string{})
 	viper.SetDefault("repository_auth", []RepositoryAuth{})
+	viper.SetDefault("proxy_url", "")
+	viper.SetDefault("repository_tls", []RepositoryTLS{})
+	viper.SetDefault("repositories", []Repository{})
+	viper.SetDefault("repository_tag_exclusions", []RepositoryTagExclusion{})
+	viper.SetDefault("ignore", []IgnoreRule{})
 }
 
 // loadConfigFile loads configuration from file (if specified or found in default paths)
@@ -195,6 +987,16 @@ func setupEnvironment() {
 			viper.Set("labels", labelsMap)
 		}
 	}
+
+	// Project tokens use the same format: AG_ARGOCD_PROJECT_TOKENS=project1=token1,project2=token2
+	if tokensStr, ok := viper.Get("argocd_project_tokens").(string); ok && tokensStr != "" {
+		viper.Set("argocd_project_tokens", parseLabelsFromString(tokensStr))
+	}
+
+	// And so do log levels: AG_LOG_LEVELS=helm=debug,argocd=warn
+	if levelsStr, ok := viper.Get("log_levels").(string); ok && levelsStr != "" {
+		viper.Set("log_levels", parseLabelsFromString(levelsStr))
+	}
 }
 
 // registerFlagAliases registers aliases to map config keys (with underscores) to flag names (with dashes)
@@ -205,26 +1007,167 @@ func registerFlagAliases() {
 	viper.RegisterAlias("argocd_username", "argocd-username")
 	viper.RegisterAlias("argocd_password", "argocd-password")
 	viper.RegisterAlias("argocd_insecure", "argocd-insecure")
+	viper.RegisterAlias("argocd_repo_credentials", "argocd-repo-credentials")
+	viper.RegisterAlias("argocd_repositories", "argocd-repositories")
+	viper.RegisterAlias("kubernetes_secret_credentials", "kubernetes-secret-credentials")
+	viper.RegisterAlias("ecr_auth", "ecr-auth")
+	viper.RegisterAlias("gcp_auth", "gcp-auth")
+	viper.RegisterAlias("azure_auth", "azure-auth")
+	viper.RegisterAlias("check_sync_windows", "check-sync-windows")
+	viper.RegisterAlias("track_branches", "track-branches")
 	viper.RegisterAlias("app_names", "app-names")
+	viper.RegisterAlias("app_namespaces", "app-namespaces")
+	viper.RegisterAlias("exclude_projects", "exclude-projects")
+	viper.RegisterAlias("exclude_app_names", "exclude-app-names")
+	viper.RegisterAlias("sync_status", "sync-status")
+	viper.RegisterAlias("excluded_tags", "excluded-tags")
+	viper.RegisterAlias("excluded_tag_patterns", "excluded-tag-patterns")
 	viper.RegisterAlias("notification_channel", "notification-channel")
+	viper.RegisterAlias("notification_grouping", "notification-grouping")
 	viper.RegisterAlias("version_constraint", "version-constraint")
 	viper.RegisterAlias("output_format", "output-format")
+	viper.RegisterAlias("group_by", "group-by")
+	viper.RegisterAlias("sort_by", "sort-by")
+	viper.RegisterAlias("json_compact", "json-compact")
 	viper.RegisterAlias("log_format", "log-format")
+	viper.RegisterAlias("log_levels", "log-levels")
+	viper.RegisterAlias("log_sample_burst", "log-sample-burst")
+	viper.RegisterAlias("log_sample_interval", "log-sample-interval")
+	viper.RegisterAlias("log_file", "log-file")
+	viper.RegisterAlias("exit_code_mode", "exit-code-mode")
+	viper.RegisterAlias("fail_on", "fail-on")
+	viper.RegisterAlias("max_apps", "max-apps")
+	viper.RegisterAlias("max_apps_mode", "max-apps-mode")
+	viper.RegisterAlias("sample_seed", "sample-seed")
+	viper.RegisterAlias("pr_comment", "pr-comment")
+	viper.RegisterAlias("github_pr_number", "github-pr-number")
+	viper.RegisterAlias("gitlab_mr_iid", "gitlab-mr-iid")
+	viper.RegisterAlias("bitbucket_pr_id", "bitbucket-pr-id")
+	viper.RegisterAlias("gitops_provider", "gitops-provider")
+	viper.RegisterAlias("serve_address", "serve-address")
+	viper.RegisterAlias("serve_interval", "serve-interval")
+	viper.RegisterAlias("serve_dashboard", "serve-dashboard")
+	viper.RegisterAlias("argocd_timeout", "argocd-timeout")
+	viper.RegisterAlias("helm_timeout", "helm-timeout")
+	viper.RegisterAlias("oci_timeout", "oci-timeout")
+	viper.RegisterAlias("git_timeout", "git-timeout")
+	viper.RegisterAlias("notify_timeout", "notify-timeout")
+	viper.RegisterAlias("temp_dir_max_age", "temp-dir-max-age")
+	viper.RegisterAlias("index_cache_ttl", "index-cache-ttl")
+	viper.RegisterAlias("cache_dir", "cache-dir")
+	viper.RegisterAlias("cache_max_age", "cache-max-age")
+	viper.RegisterAlias("cache_max_size", "cache-max-size")
+	viper.RegisterAlias("release_notes", "release-notes")
+	viper.RegisterAlias("check_images", "check-images")
+	viper.RegisterAlias("check_dependencies", "check-dependencies")
+	viper.RegisterAlias("check_values_diff", "check-values-diff")
+	viper.RegisterAlias("check_vulnerabilities", "check-vulnerabilities")
+	viper.RegisterAlias("app_of_apps", "app-of-apps")
+	viper.RegisterAlias("circuit_breaker_threshold", "circuit-breaker-threshold")
+	viper.RegisterAlias("registry_retry_attempts", "registry-retry-attempts")
+	viper.RegisterAlias("registry_retry_delay", "registry-retry-delay")
+	viper.RegisterAlias("proxy_url", "proxy-url")
+	viper.RegisterAlias("state_file", "state-file")
+	viper.RegisterAlias("notify_only_new", "notify-only-new")
+}
+
+// validateArgocdCredentials checks the credentials of an ArgoCD instance, with keys starting with prefix
+// Username and password are optional when project tokens cover the scan.
+func validateArgocdCredentials(prefix, username, password string, projectTokens map[string]string) error {
+	if len(projectTokens) == 0 || username != "" || password != "" {
+		if username == "" {
+			return fmt.Errorf("%susername is required", prefix)
+		}
+		if password == "" {
+			return fmt.Errorf("%spassword is required", prefix)
+		}
+	}
+	return nil
+}
+
+// validateArgocdInstances checks argocd_instances, which replace argocd_url and its credentials
+func validateArgocdInstances(cfg *Config) error {
+	if cfg.Mode != ModeAPI {
+		return fmt.Errorf("argocd_instances is not supported in '%s' mode", cfg.Mode)
+	}
+	if cfg.ArgocdURL != "" || cfg.ArgocdUsername != "" || cfg.ArgocdPassword != "" || len(cfg.ArgocdProjectTokens) > 0 {
+		return fmt.Errorf("argocd_instances can't be combined with argocd_url, argocd_username, argocd_password or argocd_project_tokens")
+	}
+
+	names := make(map[string]bool, len(cfg.ArgocdInstances))
+	for i, instance := range cfg.ArgocdInstances {
+		prefix := fmt.Sprintf("argocd_instances[%d].", i)
+		if instance.Name == "" {
+			return fmt.Errorf("%sname is required", prefix)
+		}
+		if names[instance.Name] {
+			return fmt.Errorf("argocd_instances: duplicate instance name '%s'", instance.Name)
+		}
+		names[instance.Name] = true
+		if instance.URL == "" {
+			return fmt.Errorf("%surl is required", prefix)
+		}
+		if err := validateArgocdCredentials(prefix, instance.Username, instance.Password, instance.ProjectTokens); err != nil {
+			return err
+		}
+		for project, token := range instance.ProjectTokens {
+			if token == "" {
+				return fmt.Errorf("%sproject_tokens: token for project '%s' is empty", prefix, project)
+			}
+		}
+	}
+	return nil
 }
 
 // validateConfig validates the loaded configuration
 func validateConfig(cfg *Config) error {
-	// Validate required fields
-	if cfg.ArgocdURL == "" {
-		return fmt.Errorf("argocd_url is required")
+	if err := validateArgocdConnection(cfg); err != nil {
+		return err
 	}
-	if cfg.ArgocdUsername == "" {
-		return fmt.Errorf("argocd_username is required")
+	return validateSettings(cfg)
+}
+
+// validateArgocdConnection validates the mode and the ArgoCD connection settings it requires
+func validateArgocdConnection(cfg *Config) error {
+	// Validate mode
+	if cfg.Mode != "" && cfg.Mode != ModeAPI && cfg.Mode != ModeKubernetes {
+		return fmt.Errorf("mode must be one of: '%s', '%s' (got: '%s')", ModeAPI, ModeKubernetes, cfg.Mode)
+	}
+	// Normalize empty to "api"
+	if cfg.Mode == "" {
+		cfg.Mode = ModeAPI
+	}
+	// Kubernetes mode reads the resources with the pod's service account instead of ArgoCD credentials
+	if cfg.Mode == ModeKubernetes && len(cfg.ArgocdProjectTokens) > 0 {
+		return fmt.Errorf("argocd_project_tokens is not supported in '%s' mode", ModeKubernetes)
 	}
-	if cfg.ArgocdPassword == "" {
-		return fmt.Errorf("argocd_password is required")
+
+	// Validate required fields
+	if len(cfg.ArgocdInstances) > 0 {
+		if err := validateArgocdInstances(cfg); err != nil {
+			return err
+		}
+	} else {
+		if cfg.ArgocdURL == "" && cfg.Mode == ModeAPI {
+			return fmt.Errorf("argocd_url is required")
+		}
+		if cfg.Mode == ModeAPI {
+			if err := validateArgocdCredentials("argocd_", cfg.ArgocdUsername, cfg.ArgocdPassword, cfg.ArgocdProjectTokens); err != nil {
+				return err
+			}
+		}
+		for project, token := range cfg.ArgocdProjectTokens {
+			if token == "" {
+				return fmt.Errorf("argocd_project_tokens: token for project '%s' is empty", project)
+			}
+		}
 	}
 
+	return nil
+}
+
+// validateSettings validates everything but the ArgoCD connection settings
+func validateSettings(cfg *Config) error {
 	// Validate version constraint
 	if cfg.VersionConstraint != "" && cfg.VersionConstraint != VersionConstraintMajor && cfg.VersionConstraint != VersionConstraintMinor && cfg.VersionConstraint != VersionConstraintPatch {
 		return fmt.Errorf("version_constraint must be one of: '%s', '%s', '%s' (got: '%s')", VersionConstraintMajor, VersionConstraintMinor, VersionConstraintPatch, cfg.VersionConstraint)
@@ -233,16 +1176,66 @@ func validateConfig(cfg *Config) error {
 	if cfg.VersionConstraint == "" {
 		cfg.VersionConstraint = VersionConstraintMajor
 	}
+	for name, constraint := range cfg.Constraints {
+		if err := ValidateConstraint(constraint); err != nil {
+			return fmt.Errorf("constraints: invalid constraint for '%s': %w", name, err)
+		}
+	}
 
 	// Validate output format
-	if cfg.OutputFormat != "" && cfg.OutputFormat != OutputFormatTable && cfg.OutputFormat != OutputFormatJSON && cfg.OutputFormat != OutputFormatMarkdown {
-		return fmt.Errorf("output_format must be one of: '%s', '%s', '%s' (got: '%s')", OutputFormatTable, OutputFormatJSON, OutputFormatMarkdown, cfg.OutputFormat)
+	if cfg.OutputFormat != "" && cfg.OutputFormat != OutputFormatTable && cfg.OutputFormat != OutputFormatJSON && cfg.OutputFormat != OutputFormatMarkdown && cfg.OutputFormat != OutputFormatMarkdownCompact && cfg.OutputFormat != OutputFormatJUnit && cfg.OutputFormat != OutputFormatHTML {
+		return fmt.Errorf("output_format must be one of: '%s', '%s', '%s', '%s', '%s', '%s' (got: '%s')", OutputFormatTable, OutputFormatJSON, OutputFormatMarkdown, OutputFormatMarkdownCompact, OutputFormatJUnit, OutputFormatHTML, cfg.OutputFormat)
 	}
 	// Normalize empty to "table"
 	if cfg.OutputFormat == "" {
 		cfg.OutputFormat = OutputFormatTable
 	}
 
+	// Validate output files, whose format comes from their extension
+	for _, file := range cfg.OutputFiles {
+		if _, ok := OutputFileFormat(file); !ok {
+			return fmt.Errorf("output_files: cannot infer the format of '%s', use one of the extensions: .txt, .log, .json, .md, .markdown, .xml, .html, .htm", file)
+		}
+	}
+
+	// Validate report grouping
+	if cfg.GroupBy != "" && !slices.Contains(groupByValues, cfg.GroupBy) {
+		return fmt.Errorf("group_by must be one of: '%s' (got: '%s')", strings.Join(groupByValues, "', '"), cfg.GroupBy)
+	}
+	// Normalize empty to "none"
+	if cfg.GroupBy == "" {
+		cfg.GroupBy = GroupByNone
+	}
+
+	// Validate table sorting and columns
+	if cfg.SortBy != "" && !slices.Contains(sortByValues, cfg.SortBy) {
+		return fmt.Errorf("sort_by must be one of: '%s' (got: '%s')", strings.Join(sortByValues, "', '"), cfg.SortBy)
+	}
+	// Normalize empty to "app"
+	if cfg.SortBy == "" {
+		cfg.SortBy = SortByApp
+	}
+	for i, column := range cfg.Columns {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !slices.Contains(Columns, column) {
+			return fmt.Errorf("columns: unknown column '%s', must be one of: '%s'", cfg.Columns[i], strings.Join(Columns, "', '"))
+		}
+		cfg.Columns[i] = column
+	}
+	if len(cfg.Columns) == 0 {
+		cfg.Columns = slices.Clone(DefaultColumns)
+	}
+
+	// Validate language
+	if cfg.Language != "" && !i18n.IsSupported(cfg.Language) {
+		return fmt.Errorf("language must be one of: '%s' (got: '%s')", strings.Join(i18n.Languages(), "', '"), cfg.Language)
+	}
+	// Normalize empty to "en"
+	cfg.Language = strings.ToLower(cfg.Language)
+	if cfg.Language == "" {
+		cfg.Language = i18n.DefaultLanguage
+	}
+
 	// Validate log format
 	if cfg.LogFormat != "" && cfg.LogFormat != LogFormatJSON && cfg.LogFormat != LogFormatText {
 		return fmt.Errorf("log_format must be one of: '%s', '%s' (got: '%s')", LogFormatJSON, LogFormatText, cfg.LogFormat)
@@ -252,6 +1245,243 @@ func validateConfig(cfg *Config) error {
 		cfg.LogFormat = LogFormatJSON
 	}
 
+	// Validate log levels
+	for component, level := range cfg.LogLevels {
+		if !slices.Contains(LogComponents, component) {
+			return fmt.Errorf("log_levels: unknown component '%s' (must be one of: %s)", component, strings.Join(LogComponents, ", "))
+		}
+		if !slices.Contains(logLevels, strings.ToLower(level)) {
+			return fmt.Errorf("log_levels: invalid level '%s' for %s (must be one of: %s)", level, component, strings.Join(logLevels, ", "))
+		}
+	}
+	if cfg.LogSampleBurst < 0 {
+		return fmt.Errorf("log_sample_burst must not be negative (got: %d)", cfg.LogSampleBurst)
+	}
+	if cfg.LogSampleBurst > 0 && cfg.LogSampleInterval <= 0 {
+		return fmt.Errorf("log_sample_interval must be positive when log_sample_burst is set (got: %s)", cfg.LogSampleInterval)
+	}
+
+	// Validate exit code mode
+	if cfg.ExitCodeMode != "" && cfg.ExitCodeMode != ExitCodeModeSimple && cfg.ExitCodeMode != ExitCodeModeDetailed {
+		return fmt.Errorf("exit_code_mode must be one of: '%s', '%s' (got: '%s')", ExitCodeModeSimple, ExitCodeModeDetailed, cfg.ExitCodeMode)
+	}
+	// Normalize empty to "simple"
+	if cfg.ExitCodeMode == "" {
+		cfg.ExitCodeMode = ExitCodeModeSimple
+	}
+
+	// Validate app-of-apps recursion
+	if cfg.AppOfApps && cfg.AppOfAppsMaxDepth < 1 {
+		return fmt.Errorf("app_of_apps_max_depth must be at least 1 when app_of_apps is enabled (got: %d)", cfg.AppOfAppsMaxDepth)
+	}
+
+	// Validate application cap
+	if cfg.MaxApps < 0 {
+		return fmt.Errorf("max_apps must not be negative (got: %d)", cfg.MaxApps)
+	}
+	if cfg.MaxAppsMode != "" && cfg.MaxAppsMode != MaxAppsModeFirst && cfg.MaxAppsMode != MaxAppsModeSample {
+		return fmt.Errorf("max_apps_mode must be one of: '%s', '%s' (got: '%s')", MaxAppsModeFirst, MaxAppsModeSample, cfg.MaxAppsMode)
+	}
+	// Normalize empty to "first"
+	if cfg.MaxAppsMode == "" {
+		cfg.MaxAppsMode = MaxAppsModeFirst
+	}
+
+	// Validate fail-on
+	switch cfg.FailOn {
+	case "", FailOnPatch, FailOnMinor, FailOnMajor, FailOnSecurity, FailOnUpdates, FailOnOutsideConstraint, FailOnErrors, FailOnNone:
+	default:
+		return fmt.Errorf("fail_on must be one of: '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s' (got: '%s')",
+			FailOnUpdates, FailOnOutsideConstraint, FailOnErrors, FailOnNone, FailOnPatch, FailOnMinor, FailOnMajor, FailOnSecurity, cfg.FailOn)
+	}
+
+	// Validate deadlines
+	timeouts := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"timeout", cfg.Timeout},
+		{"argocd_timeout", cfg.ArgocdTimeout},
+		{"helm_timeout", cfg.HelmTimeout},
+		{"oci_timeout", cfg.OCITimeout},
+		{"git_timeout", cfg.GitTimeout},
+		{"notify_timeout", cfg.NotifyTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			return fmt.Errorf("%s must not be negative (got: %s)", timeout.key, timeout.value)
+		}
+	}
+	if cfg.CircuitBreakerThreshold < 0 {
+		return fmt.Errorf("circuit_breaker_threshold must not be negative (got: %d)", cfg.CircuitBreakerThreshold)
+	}
+	if cfg.RegistryRetryAttempts < 0 {
+		return fmt.Errorf("registry_retry_attempts must not be negative (got: %d)", cfg.RegistryRetryAttempts)
+	}
+	if cfg.RegistryRetryDelay < 0 {
+		return fmt.Errorf("registry_retry_delay must not be negative (got: %s)", cfg.RegistryRetryDelay)
+	}
+	if cfg.TempDirMaxAge < 0 {
+		return fmt.Errorf("temp_dir_max_age must not be negative (got: %s)", cfg.TempDirMaxAge)
+	}
+	if cfg.IndexCacheTTL < 0 {
+		return fmt.Errorf("index_cache_ttl must not be negative (got: %s)", cfg.IndexCacheTTL)
+	}
+	if cfg.CacheMaxAge < 0 {
+		return fmt.Errorf("cache_max_age must not be negative (got: %s)", cfg.CacheMaxAge)
+	}
+	if cfg.CacheMaxSize < 0 {
+		return fmt.Errorf("cache_max_size must not be negative (got: %d)", cfg.CacheMaxSize)
+	}
+	if cfg.ReleaseNotesMaxLength < 0 {
+		return fmt.Errorf("release_notes_max_length must not be negative (got: %d)", cfg.ReleaseNotesMaxLength)
+	}
+
+	// Validate repository connections
+	if cfg.ProxyURL != "" {
+		if parsed, err := url.Parse(cfg.ProxyURL); err != nil || parsed.Host == "" {
+			return fmt.Errorf("proxy_url must be a URL like http://proxy.example.com:3128 (got: '%s')", cfg.ProxyURL)
+		}
+	}
+	for i, repository := range cfg.RepositoryTLS {
+		if repository.URL == "" {
+			return fmt.Errorf("repository_tls[%d]: url is required", i)
+		}
+		if repository.CAFile == "" && !repository.InsecureSkipVerify {
+			return fmt.Errorf("repository_tls[%d]: ca_file or insecure_skip_verify is required", i)
+		}
+	}
+	for i, repository := range cfg.Repositories {
+		if repository.URL == "" {
+			return fmt.Errorf("repositories[%d]: url is required", i)
+		}
+		if repository.Timeout < 0 {
+			return fmt.Errorf("repositories[%d]: timeout must not be negative (got: %s)", i, repository.Timeout)
+		}
+	}
+
+	// Validate tag exclusions
+	if err := validatePatterns("excluded_tag_patterns", cfg.ExcludedTagPatterns); err != nil {
+		return err
+	}
+	for i, exclusion := range cfg.RepositoryTagExclusions {
+		if exclusion.URL == "" {
+			return fmt.Errorf("repository_tag_exclusions[%d]: url is required", i)
+		}
+		if err := validatePatterns(fmt.Sprintf("repository_tag_exclusions[%d].patterns", i), exclusion.Patterns); err != nil {
+			return err
+		}
+	}
+
+	// Validate the globs and /regexes/ of the name filters
+	if err := validateNameFilters("projects", cfg.Projects); err != nil {
+		return err
+	}
+	if err := validateNameFilters("app_names", cfg.AppNames); err != nil {
+		return err
+	}
+	if err := validateNameFilters("exclude_projects", cfg.ExcludeProjects); err != nil {
+		return err
+	}
+	if err := validateNameFilters("exclude_app_names", cfg.ExcludeAppNames); err != nil {
+		return err
+	}
+
+	// Validate ignore rules
+	for i, rule := range cfg.Ignore {
+		if rule.App == "" && rule.Chart == "" && rule.Repo == "" && rule.Version == "" {
+			return fmt.Errorf("ignore[%d]: at least one of app, chart, repo or version is required", i)
+		}
+		for _, glob := range []struct{ key, pattern string }{{"app", rule.App}, {"chart", rule.Chart}, {"version", rule.Version}} {
+			if _, err := path.Match(glob.pattern, ""); err != nil {
+				return fmt.Errorf("ignore[%d]: invalid %s pattern '%s': %w", i, glob.key, glob.pattern, err)
+			}
+		}
+		if _, err := rule.Expiry(); err != nil {
+			return fmt.Errorf("ignore[%d]: %w", i, err)
+		}
+	}
+
+	// Validate notification grouping
+	if cfg.NotificationGrouping != "" && cfg.NotificationGrouping != NotificationGroupingNone && cfg.NotificationGrouping != NotificationGroupingProject {
+		return fmt.Errorf("notification_grouping must be one of: '%s', '%s' (got: '%s')", NotificationGroupingNone, NotificationGroupingProject, cfg.NotificationGrouping)
+	}
+	// Normalize empty to "none"
+	if cfg.NotificationGrouping == "" {
+		cfg.NotificationGrouping = NotificationGroupingNone
+	}
+
+	// Validate enrichment sources
+	for _, source := range cfg.Enrich {
+		if source != EnrichArtifactHub {
+			return fmt.Errorf("enrich must only contain '%s' (got: '%s')", EnrichArtifactHub, source)
+		}
+	}
+
+	// Validate notification templates
+	for channel := range cfg.NotificationTemplates {
+		if channel != NotificationTemplateDefault && !slices.Contains(notificationChannels, channel) {
+			return fmt.Errorf("notification_templates keys must be '%s' or a notification channel (got: '%s')", NotificationTemplateDefault, channel)
+		}
+	}
+
+	// Validate pull/merge request comment target
+	switch cfg.PRComment {
+	case "", PRCommentGitHub, PRCommentGitLab, PRCommentBitbucket:
+	case PRCommentBitbucketServer:
+		if cfg.BitbucketURL == "" {
+			return fmt.Errorf("bitbucket_url is required when pr_comment is '%s'", PRCommentBitbucketServer)
+		}
+	default:
+		return fmt.Errorf("pr_comment must be one of: '%s', '%s', '%s', '%s' (got: '%s')", PRCommentGitHub, PRCommentGitLab, PRCommentBitbucket, PRCommentBitbucketServer, cfg.PRComment)
+	}
+
+	// Validate GitOps pull/merge requests
+	switch cfg.GitOpsProvider {
+	case "":
+	case GitOpsGitHub, GitOpsGitLab:
+		if len(cfg.GitOpsRepositories) == 0 {
+			return fmt.Errorf("gitops_repositories is required when gitops_provider is set")
+		}
+		for i, repo := range cfg.GitOpsRepositories {
+			if repo.Repository == "" || repo.Path == "" {
+				return fmt.Errorf("gitops_repositories[%d]: repository and path are required", i)
+			}
+		}
+	default:
+		return fmt.Errorf("gitops_provider must be one of: '%s', '%s' (got: '%s')", GitOpsGitHub, GitOpsGitLab, cfg.GitOpsProvider)
+	}
+
+	// Validate status filters and normalize them to ArgoCD's spelling (e.g. "outofsync" -> "OutOfSync")
+	var err error
+	if cfg.SyncStatus, err = normalizeStatuses("sync_status", cfg.SyncStatus, SyncStatuses); err != nil {
+		return err
+	}
+	if cfg.HealthStatus, err = normalizeStatuses("health_status", cfg.HealthStatus, HealthStatuses); err != nil {
+		return err
+	}
+	if cfg.TrivySeverities, err = normalizeStatuses("trivy_severities", cfg.TrivySeverities, trivy.Severities); err != nil {
+		return err
+	}
+
+	// Validate notification colors and normalize them to the bare hex form
+	for _, color := range []struct {
+		key   string
+		value *string
+	}{
+		{"notification_color_security", &cfg.NotificationColorSecurity},
+		{"notification_color_major", &cfg.NotificationColorMajor},
+		{"notification_color_minor", &cfg.NotificationColorMinor},
+		{"notification_color_patch", &cfg.NotificationColorPatch},
+		{"notification_theme_color", &cfg.NotificationThemeColor},
+	} {
+		normalized, err := normalizeHexColor(*color.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", color.key, err)
+		}
+		*color.value = normalized
+	}
+
 	// Validate notification channel settings
 	switch cfg.NotificationChannel {
 	case "telegram":
@@ -279,15 +1509,126 @@ func validateConfig(cfg *Config) error {
 		if cfg.TeamsWebhook == "" {
 			return fmt.Errorf("teams_webhook is required when notification_channel is 'teams'")
 		}
+		if cfg.TeamsCardFormat != TeamsCardFormatMessageCard && cfg.TeamsCardFormat != TeamsCardFormatAdaptive {
+			return fmt.Errorf("teams_card_format must be '%s' or '%s' (got: '%s')", TeamsCardFormatMessageCard, TeamsCardFormatAdaptive, cfg.TeamsCardFormat)
+		}
+	case "discord":
+		if cfg.DiscordWebhook == "" {
+			return fmt.Errorf("discord_webhook is required when notification_channel is 'discord'")
+		}
+	case "googlechat":
+		if cfg.GoogleChatWebhook == "" {
+			return fmt.Errorf("googlechat_webhook is required when notification_channel is 'googlechat'")
+		}
+	case "webex":
+		if cfg.WebexBotToken == "" {
+			return fmt.Errorf("webex_bot_token is required when notification_channel is 'webex'")
+		}
+		if cfg.WebexRoomID == "" {
+			return fmt.Errorf("webex_room_id is required when notification_channel is 'webex'")
+		}
+	case "opsgenie":
+		if cfg.OpsgenieAPIKey == "" {
+			return fmt.Errorf("opsgenie_api_key is required when notification_channel is 'opsgenie'")
+		}
+		if err := validateOpsgenie(cfg); err != nil {
+			return err
+		}
+	case "kafka":
+		if len(cfg.KafkaBrokers) == 0 {
+			return fmt.Errorf("kafka_brokers is required when notification_channel is 'kafka'")
+		}
+		if cfg.KafkaTopic == "" {
+			return fmt.Errorf("kafka_topic is required when notification_channel is 'kafka'")
+		}
+		if cfg.KafkaSASLMechanism != "" && cfg.KafkaUsername == "" {
+			return fmt.Errorf("kafka_username is required when kafka_sasl_mechanism is set")
+		}
+	case "mqtt":
+		if cfg.MQTTBroker == "" {
+			return fmt.Errorf("mqtt_broker is required when notification_channel is 'mqtt'")
+		}
+		if cfg.MQTTTopic == "" {
+			return fmt.Errorf("mqtt_topic is required when notification_channel is 'mqtt'")
+		}
+		if cfg.MQTTQoS < 0 || cfg.MQTTQoS > 2 {
+			return fmt.Errorf("mqtt_qos must be one of: 0, 1, 2 (got: %d)", cfg.MQTTQoS)
+		}
 	case "webhook":
 		if cfg.WebhookURL == "" {
 			return fmt.Errorf("webhook_url is required when notification_channel is 'webhook'")
 		}
+		if cfg.WebhookPayloadFormat != WebhookPayloadText && cfg.WebhookPayloadFormat != WebhookPayloadStructured {
+			return fmt.Errorf("webhook_payload_format must be '%s' or '%s' (got: '%s')", WebhookPayloadText, WebhookPayloadStructured, cfg.WebhookPayloadFormat)
+		}
 	}
 
 	return nil
 }
 
+// opsgenieResponderTypes are the responder types accepted by the Opsgenie alert API
+var opsgenieResponderTypes = []string{"team", "user", "escalation", "schedule"}
+
+// validateOpsgenie checks the Opsgenie priorities and responders
+func validateOpsgenie(cfg *Config) error {
+	priorities := []struct {
+		key   string
+		value string
+	}{
+		{"opsgenie_priority_security", cfg.OpsgeniePrioritySecurity},
+		{"opsgenie_priority_major", cfg.OpsgeniePriorityMajor},
+		{"opsgenie_priority_minor", cfg.OpsgeniePriorityMinor},
+		{"opsgenie_priority_patch", cfg.OpsgeniePriorityPatch},
+	}
+	for _, priority := range priorities {
+		switch priority.value {
+		case "P1", "P2", "P3", "P4", "P5":
+		default:
+			return fmt.Errorf("%s must be one of: 'P1', 'P2', 'P3', 'P4', 'P5' (got: '%s')", priority.key, priority.value)
+		}
+	}
+
+	for _, responder := range cfg.OpsgenieResponders {
+		kind, name, ok := strings.Cut(responder, ":")
+		if !ok {
+			kind, name = "team", responder
+		}
+		if !slices.Contains(opsgenieResponderTypes, kind) || name == "" {
+			return fmt.Errorf("invalid opsgenie_responders entry '%s' (expected a team name or one of %s followed by ':<name>')", responder, strings.Join(opsgenieResponderTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// normalizeHexColor accepts "RRGGBB" or "#RRGGBB" and returns "RRGGBB"
+func normalizeHexColor(color string) (string, error) {
+	if color == "" {
+		return "", nil
+	}
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 || strings.Trim(strings.ToLower(hex), "0123456789abcdef") != "" {
+		return "", fmt.Errorf("invalid hex color '%s' (expected RRGGBB)", color)
+	}
+	return strings.ToUpper(hex), nil
+}
+
+// normalizeStatuses matches status filter values case-insensitively against the valid statuses
+func normalizeStatuses(key string, values, valid []string) ([]string, error) {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		i := slices.IndexFunc(valid, func(status string) bool { return strings.EqualFold(status, value) })
+		if i < 0 {
+			return nil, fmt.Errorf("%s must be one of: '%s' (got: '%s')", key, strings.Join(valid, "', '"), value)
+		}
+		normalized = append(normalized, valid[i])
+	}
+	return normalized, nil
+}
+
 // parseLabelsFromString parses a comma-separated key=value string into a map
 // Example: "key1=value1,key2=value2" -> map[string]string{"key1": "value1", "key2": "value2"}
 func parseLabelsFromString(labelsStr string) map[string]string {
@@ -315,3 +1656,45 @@ func parseLabelsFromString(labelsStr string) map[string]string {
 
 	return labels
 }
+
+// ValidateConstraint checks a per-application version constraint: one of the "major", "minor" or
+// "patch" keywords, or a semver range such as ">=1.2.0 <2.0.0" or "~1.4.x"
+func ValidateConstraint(constraint string) error {
+	switch constraint {
+	case VersionConstraintMajor, VersionConstraintMinor, VersionConstraintPatch:
+		return nil
+	case "":
+		return fmt.Errorf("constraint must not be empty")
+	}
+	if _, err := semver.NewConstraint(constraint); err != nil {
+		return fmt.Errorf("'%s' is neither major, minor, patch nor a semver range: %w", constraint, err)
+	}
+	return nil
+}
+
+// validateNameFilters checks the globs (e.g. "payments-*") and regular expressions between slashes
+// (e.g. "/^team-a-/") of a project or application name filter
+func validateNameFilters(key string, values []string) error {
+	for _, value := range values {
+		if len(value) > 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+			if _, err := regexp.Compile(value[1 : len(value)-1]); err != nil {
+				return fmt.Errorf("%s: invalid pattern '%s': %w", key, value, err)
+			}
+			continue
+		}
+		if _, err := path.Match(value, ""); err != nil {
+			return fmt.Errorf("%s: invalid pattern '%s': %w", key, value, err)
+		}
+	}
+	return nil
+}
+
+// validatePatterns checks that every pattern of a setting is a valid regular expression
+func validatePatterns(key string, patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("%s: invalid pattern '%s': %w", key, pattern, err)
+		}
+	}
+	return nil
+}
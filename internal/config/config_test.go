@@ -2,11 +2,15 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"argazer/internal/keychain"
 )
 
 func TestParseLabelsFromString(t *testing.T) {
@@ -128,6 +132,139 @@ func TestLoad_RequiredFields(t *testing.T) {
 	}
 }
 
+func TestLoad_ArgocdProjectTokens(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		tokens      string
+		username    string
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			name:     "tokens without account",
+			tokens:   "team-a=token-a,team-b=token-b",
+			expected: map[string]string{"team-a": "token-a", "team-b": "token-b"},
+		},
+		{
+			name:        "account without password",
+			tokens:      "team-a=token-a",
+			username:    "admin",
+			expectedErr: "argocd_password is required",
+		},
+		{
+			name:        "empty token",
+			tokens:      "team-a=",
+			expectedErr: "token for project 'team-a' is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_PROJECT_TOKENS", tt.tokens)
+			os.Setenv("AG_ARGOCD_USERNAME", tt.username)
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_PROJECT_TOKENS")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.ArgocdProjectTokens)
+		})
+	}
+}
+
+func TestLoad_ArgocdInstances(t *testing.T) {
+	defer viper.Reset()
+
+	production := map[string]any{"name": "production", "url": "https://argocd.prod.example.com", "username": "admin", "password": "secret"}
+	staging := map[string]any{"name": "staging", "url": "https://argocd.staging.example.com", "project_tokens": map[string]string{"team-a": "token-a"}}
+
+	tests := []struct {
+		name        string
+		instances   []map[string]any
+		env         map[string]string
+		expectedErr string
+	}{
+		{name: "instances", instances: []map[string]any{production, staging}},
+		{name: "argocd_url not required", instances: []map[string]any{staging}},
+		{
+			name:        "combined with argocd_url",
+			instances:   []map[string]any{production},
+			env:         map[string]string{"AG_ARGOCD_URL": "https://argocd.example.com"},
+			expectedErr: "argocd_instances can't be combined with argocd_url",
+		},
+		{
+			name:        "missing name",
+			instances:   []map[string]any{{"url": "https://argocd.example.com", "username": "admin", "password": "secret"}},
+			expectedErr: "argocd_instances[0].name is required",
+		},
+		{
+			name:        "duplicate name",
+			instances:   []map[string]any{production, production},
+			expectedErr: "duplicate instance name 'production'",
+		},
+		{
+			name:        "missing url",
+			instances:   []map[string]any{{"name": "production", "username": "admin", "password": "secret"}},
+			expectedErr: "argocd_instances[0].url is required",
+		},
+		{
+			name:        "missing password",
+			instances:   []map[string]any{staging, {"name": "production", "url": "https://argocd.example.com", "username": "admin"}},
+			expectedErr: "argocd_instances[1].password is required",
+		},
+		{
+			name:        "kubernetes mode",
+			instances:   []map[string]any{production},
+			env:         map[string]string{"AG_MODE": "kubernetes"},
+			expectedErr: "argocd_instances is not supported in 'kubernetes' mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+			defer func() {
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+			viper.Set("argocd_instances", tt.instances)
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.ArgocdInstances, len(tt.instances))
+			assert.Equal(t, tt.instances[0]["url"], cfg.InstanceURL(tt.instances[0]["name"].(string)))
+
+			instanceCfg := cfg.InstanceConfig(cfg.ArgocdInstances[len(tt.instances)-1])
+			assert.Equal(t, "https://argocd.staging.example.com", instanceCfg.ArgocdURL)
+			assert.Equal(t, map[string]string{"team-a": "token-a"}, instanceCfg.ArgocdProjectTokens)
+			assert.Empty(t, instanceCfg.ArgocdInstances)
+			assert.Empty(t, cfg.ArgocdURL, "the configuration itself is unchanged")
+		})
+	}
+}
+
 func TestLoad_TelegramValidation(t *testing.T) {
 	defer viper.Reset()
 
@@ -181,36 +318,26 @@ func TestLoad_TelegramValidation(t *testing.T) {
 	}
 }
 
-func TestLoad_EmailValidation(t *testing.T) {
+func TestLoad_WebexValidation(t *testing.T) {
 	defer viper.Reset()
 
 	tests := []struct {
 		name        string
-		smtpHost    string
-		from        string
-		to          string
+		botToken    string
+		roomID      string
 		expectedErr string
 	}{
 		{
-			name:        "missing smtp_host",
-			smtpHost:    "",
-			from:        "sender@example.com",
-			to:          "recipient@example.com",
-			expectedErr: "email_smtp_host is required",
-		},
-		{
-			name:        "missing from",
-			smtpHost:    "smtp.example.com",
-			from:        "",
-			to:          "recipient@example.com",
-			expectedErr: "email_from is required",
+			name:        "missing bot token",
+			botToken:    "",
+			roomID:      "room",
+			expectedErr: "webex_bot_token is required",
 		},
 		{
-			name:        "missing to",
-			smtpHost:    "smtp.example.com",
-			from:        "sender@example.com",
-			to:          "",
-			expectedErr: "email_to is required",
+			name:        "missing room id",
+			botToken:    "token",
+			roomID:      "",
+			expectedErr: "webex_room_id is required",
 		},
 	}
 
@@ -220,15 +347,12 @@ func TestLoad_EmailValidation(t *testing.T) {
 			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
 			os.Setenv("AG_ARGOCD_USERNAME", "admin")
 			os.Setenv("AG_ARGOCD_PASSWORD", "password")
-			os.Setenv("AG_NOTIFICATION_CHANNEL", "email")
-			if tt.smtpHost != "" {
-				os.Setenv("AG_EMAIL_SMTP_HOST", tt.smtpHost)
-			}
-			if tt.from != "" {
-				os.Setenv("AG_EMAIL_FROM", tt.from)
+			os.Setenv("AG_NOTIFICATION_CHANNEL", "webex")
+			if tt.botToken != "" {
+				os.Setenv("AG_WEBEX_BOT_TOKEN", tt.botToken)
 			}
-			if tt.to != "" {
-				os.Setenv("AG_EMAIL_TO", tt.to)
+			if tt.roomID != "" {
+				os.Setenv("AG_WEBEX_ROOM_ID", tt.roomID)
 			}
 
 			defer func() {
@@ -236,9 +360,8 @@ func TestLoad_EmailValidation(t *testing.T) {
 				os.Unsetenv("AG_ARGOCD_USERNAME")
 				os.Unsetenv("AG_ARGOCD_PASSWORD")
 				os.Unsetenv("AG_NOTIFICATION_CHANNEL")
-				os.Unsetenv("AG_EMAIL_SMTP_HOST")
-				os.Unsetenv("AG_EMAIL_FROM")
-				os.Unsetenv("AG_EMAIL_TO")
+				os.Unsetenv("AG_WEBEX_BOT_TOKEN")
+				os.Unsetenv("AG_WEBEX_ROOM_ID")
 			}()
 
 			_, err := Load()
@@ -248,68 +371,1926 @@ func TestLoad_EmailValidation(t *testing.T) {
 	}
 }
 
-func TestLoad_Success(t *testing.T) {
+func TestLoadWithoutArgocd(t *testing.T) {
 	defer viper.Reset()
 
+	os.Setenv("AG_VERSION_CONSTRAINT", "minor")
+	defer os.Unsetenv("AG_VERSION_CONSTRAINT")
+
+	// ArgoCD connection settings aren't required, the rest is still validated
+	viper.Reset()
+	cfg, err := LoadWithoutArgocd()
+	require.NoError(t, err)
+	assert.Equal(t, VersionConstraintMinor, cfg.VersionConstraint)
+
+	viper.Reset()
+	_, err = Load()
+	assert.ErrorContains(t, err, "argocd_url is required")
+
 	viper.Reset()
+	os.Setenv("AG_VERSION_CONSTRAINT", "latest")
+	_, err = LoadWithoutArgocd()
+	assert.ErrorContains(t, err, "version_constraint must be one of")
+}
+
+func TestLoad_TeamsCardFormat(t *testing.T) {
+	defer viper.Reset()
+
 	os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
 	os.Setenv("AG_ARGOCD_USERNAME", "admin")
-	os.Setenv("AG_ARGOCD_PASSWORD", "password123")
-	os.Setenv("AG_ARGOCD_INSECURE", "true")
-	os.Setenv("AG_VERBOSE", "true")
-	os.Setenv("AG_CONCURRENCY", "20")
-	os.Setenv("AG_SOURCE_NAME", "my-chart")
-	os.Setenv("AG_LABELS", "env=prod,team=platform")
-
+	os.Setenv("AG_ARGOCD_PASSWORD", "password")
+	os.Setenv("AG_NOTIFICATION_CHANNEL", "teams")
+	os.Setenv("AG_TEAMS_WEBHOOK", "https://example.webhook.office.com/webhookb2/token")
 	defer func() {
 		os.Unsetenv("AG_ARGOCD_URL")
 		os.Unsetenv("AG_ARGOCD_USERNAME")
 		os.Unsetenv("AG_ARGOCD_PASSWORD")
-		os.Unsetenv("AG_ARGOCD_INSECURE")
-		os.Unsetenv("AG_VERBOSE")
-		os.Unsetenv("AG_CONCURRENCY")
-		os.Unsetenv("AG_SOURCE_NAME")
-		os.Unsetenv("AG_LABELS")
+		os.Unsetenv("AG_NOTIFICATION_CHANNEL")
+		os.Unsetenv("AG_TEAMS_WEBHOOK")
+		os.Unsetenv("AG_TEAMS_CARD_FORMAT")
 	}()
 
+	viper.Reset()
 	cfg, err := Load()
 	require.NoError(t, err)
-	require.NotNil(t, cfg)
+	assert.Equal(t, TeamsCardFormatMessageCard, cfg.TeamsCardFormat)
 
-	assert.Equal(t, "https://argocd.example.com", cfg.ArgocdURL)
-	assert.Equal(t, "admin", cfg.ArgocdUsername)
-	assert.Equal(t, "password123", cfg.ArgocdPassword)
-	assert.True(t, cfg.ArgocdInsecure)
-	assert.True(t, cfg.Verbose)
-	assert.Equal(t, 20, cfg.Concurrency)
-	assert.Equal(t, "my-chart", cfg.SourceName)
-	assert.Equal(t, map[string]string{"env": "prod", "team": "platform"}, cfg.Labels)
+	viper.Reset()
+	os.Setenv("AG_TEAMS_CARD_FORMAT", "adaptive")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, TeamsCardFormatAdaptive, cfg.TeamsCardFormat)
+
+	viper.Reset()
+	os.Setenv("AG_TEAMS_CARD_FORMAT", "o365")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "teams_card_format must be 'messagecard' or 'adaptive'")
 }
 
-func TestLoad_Defaults(t *testing.T) {
+func TestLoad_WebhookPayloadFormat(t *testing.T) {
 	defer viper.Reset()
 
+	os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+	os.Setenv("AG_ARGOCD_USERNAME", "admin")
+	os.Setenv("AG_ARGOCD_PASSWORD", "password")
+	os.Setenv("AG_NOTIFICATION_CHANNEL", "webhook")
+	os.Setenv("AG_WEBHOOK_URL", "https://hooks.example.com/argazer")
+	defer func() {
+		os.Unsetenv("AG_ARGOCD_URL")
+		os.Unsetenv("AG_ARGOCD_USERNAME")
+		os.Unsetenv("AG_ARGOCD_PASSWORD")
+		os.Unsetenv("AG_NOTIFICATION_CHANNEL")
+		os.Unsetenv("AG_WEBHOOK_URL")
+		os.Unsetenv("AG_WEBHOOK_PAYLOAD_FORMAT")
+		os.Unsetenv("AG_WEBHOOK_SECRET")
+	}()
+
+	viper.Reset()
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, WebhookPayloadText, cfg.WebhookPayloadFormat)
+	assert.Empty(t, cfg.WebhookHeaders)
+
+	viper.Reset()
+	os.Setenv("AG_WEBHOOK_PAYLOAD_FORMAT", "structured")
+	os.Setenv("AG_WEBHOOK_SECRET", "secret")
+	viper.Set("webhook_headers", map[string]string{"authorization": "Bearer token"})
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, WebhookPayloadStructured, cfg.WebhookPayloadFormat)
+	assert.Equal(t, "secret", cfg.WebhookSecret)
+	assert.Equal(t, "Bearer token", cfg.WebhookHeaders["authorization"])
+
 	viper.Reset()
+	os.Setenv("AG_WEBHOOK_PAYLOAD_FORMAT", "xml")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook_payload_format must be 'text' or 'structured'")
+}
+
+func TestLoad_DiscordValidation(t *testing.T) {
+	defer viper.Reset()
+
 	os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
 	os.Setenv("AG_ARGOCD_USERNAME", "admin")
 	os.Setenv("AG_ARGOCD_PASSWORD", "password")
+	os.Setenv("AG_NOTIFICATION_CHANNEL", "discord")
+	defer func() {
+		os.Unsetenv("AG_ARGOCD_URL")
+		os.Unsetenv("AG_ARGOCD_USERNAME")
+		os.Unsetenv("AG_ARGOCD_PASSWORD")
+		os.Unsetenv("AG_NOTIFICATION_CHANNEL")
+		os.Unsetenv("AG_DISCORD_WEBHOOK")
+	}()
+
+	viper.Reset()
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "discord_webhook is required")
+
+	viper.Reset()
+	os.Setenv("AG_DISCORD_WEBHOOK", "https://discord.com/api/webhooks/1/token")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://discord.com/api/webhooks/1/token", cfg.DiscordWebhook)
+}
 
+func TestLoad_GoogleChatValidation(t *testing.T) {
+	defer viper.Reset()
+
+	os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+	os.Setenv("AG_ARGOCD_USERNAME", "admin")
+	os.Setenv("AG_ARGOCD_PASSWORD", "password")
+	os.Setenv("AG_NOTIFICATION_CHANNEL", "googlechat")
 	defer func() {
 		os.Unsetenv("AG_ARGOCD_URL")
 		os.Unsetenv("AG_ARGOCD_USERNAME")
 		os.Unsetenv("AG_ARGOCD_PASSWORD")
+		os.Unsetenv("AG_NOTIFICATION_CHANNEL")
+		os.Unsetenv("AG_GOOGLECHAT_WEBHOOK")
 	}()
 
+	viper.Reset()
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "googlechat_webhook is required")
+
+	viper.Reset()
+	os.Setenv("AG_GOOGLECHAT_WEBHOOK", "https://chat.googleapis.com/v1/spaces/AAAA/messages?key=k&token=t")
 	cfg, err := Load()
 	require.NoError(t, err)
-	require.NotNil(t, cfg)
+	assert.Equal(t, "https://chat.googleapis.com/v1/spaces/AAAA/messages?key=k&token=t", cfg.GoogleChatWebhook)
+}
 
-	// Check defaults
-	assert.False(t, cfg.Verbose)
-	assert.False(t, cfg.ArgocdInsecure)
-	assert.Equal(t, 10, cfg.Concurrency)
-	assert.Equal(t, "chart-repo", cfg.SourceName)
-	assert.Equal(t, []string{"*"}, cfg.Projects)
-	assert.Equal(t, []string{"*"}, cfg.AppNames)
-	assert.Equal(t, map[string]string{}, cfg.Labels)
+func TestLoad_OpsgenieValidation(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "missing api key",
+			env:         map[string]string{},
+			expectedErr: "opsgenie_api_key is required",
+		},
+		{
+			name:        "invalid priority",
+			env:         map[string]string{"AG_OPSGENIE_API_KEY": "key", "AG_OPSGENIE_PRIORITY_MAJOR": "P0"},
+			expectedErr: "opsgenie_priority_major must be one of",
+		},
+		{
+			name:        "invalid responder type",
+			env:         map[string]string{"AG_OPSGENIE_API_KEY": "key", "AG_OPSGENIE_RESPONDERS": "group:platform"},
+			expectedErr: "invalid opsgenie_responders entry 'group:platform'",
+		},
+		{
+			name: "valid",
+			env:  map[string]string{"AG_OPSGENIE_API_KEY": "key", "AG_OPSGENIE_RESPONDERS": "platform,user:jane@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			os.Setenv("AG_NOTIFICATION_CHANNEL", "opsgenie")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_NOTIFICATION_CHANNEL")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "https://api.opsgenie.com", cfg.OpsgenieAPIURL)
+			assert.Equal(t, []string{"platform", "user:jane@example.com"}, cfg.OpsgenieResponders)
+			assert.Equal(t, "P3", cfg.OpsgeniePriorityMajor)
+			assert.Equal(t, "P5", cfg.OpsgeniePriorityPatch)
+		})
+	}
+}
+
+func TestLoad_KafkaValidation(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "missing brokers",
+			env:         map[string]string{"AG_KAFKA_TOPIC": "argazer"},
+			expectedErr: "kafka_brokers is required",
+		},
+		{
+			name:        "missing topic",
+			env:         map[string]string{"AG_KAFKA_BROKERS": "kafka:9092"},
+			expectedErr: "kafka_topic is required",
+		},
+		{
+			name: "SASL without username",
+			env: map[string]string{
+				"AG_KAFKA_BROKERS":        "kafka:9092",
+				"AG_KAFKA_TOPIC":          "argazer",
+				"AG_KAFKA_SASL_MECHANISM": "PLAIN",
+			},
+			expectedErr: "kafka_username is required",
+		},
+		{
+			name: "valid",
+			env: map[string]string{
+				"AG_KAFKA_BROKERS": "kafka-1:9092,kafka-2:9092",
+				"AG_KAFKA_TOPIC":   "argazer",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			os.Setenv("AG_NOTIFICATION_CHANNEL", "kafka")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_NOTIFICATION_CHANNEL")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.KafkaBrokers)
+			assert.Equal(t, "argazer", cfg.KafkaTopic)
+		})
+	}
+}
+
+func TestLoad_MQTTValidation(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "missing broker",
+			env:         map[string]string{},
+			expectedErr: "mqtt_broker is required",
+		},
+		{
+			name: "invalid QoS",
+			env: map[string]string{
+				"AG_MQTT_BROKER": "mqtt://broker.local",
+				"AG_MQTT_QOS":    "3",
+			},
+			expectedErr: "mqtt_qos must be one of",
+		},
+		{
+			name: "valid with defaults",
+			env:  map[string]string{"AG_MQTT_BROKER": "mqtt://broker.local"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			os.Setenv("AG_NOTIFICATION_CHANNEL", "mqtt")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_NOTIFICATION_CHANNEL")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "argazer/{project}/{app}", cfg.MQTTTopic)
+			assert.Equal(t, 1, cfg.MQTTQoS)
+			assert.False(t, cfg.MQTTRetain)
+		})
+	}
+}
+
+func TestLoad_EmailValidation(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		smtpHost    string
+		from        string
+		to          string
+		expectedErr string
+	}{
+		{
+			name:        "missing smtp_host",
+			smtpHost:    "",
+			from:        "sender@example.com",
+			to:          "recipient@example.com",
+			expectedErr: "email_smtp_host is required",
+		},
+		{
+			name:        "missing from",
+			smtpHost:    "smtp.example.com",
+			from:        "",
+			to:          "recipient@example.com",
+			expectedErr: "email_from is required",
+		},
+		{
+			name:        "missing to",
+			smtpHost:    "smtp.example.com",
+			from:        "sender@example.com",
+			to:          "",
+			expectedErr: "email_to is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			os.Setenv("AG_NOTIFICATION_CHANNEL", "email")
+			if tt.smtpHost != "" {
+				os.Setenv("AG_EMAIL_SMTP_HOST", tt.smtpHost)
+			}
+			if tt.from != "" {
+				os.Setenv("AG_EMAIL_FROM", tt.from)
+			}
+			if tt.to != "" {
+				os.Setenv("AG_EMAIL_TO", tt.to)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_NOTIFICATION_CHANNEL")
+				os.Unsetenv("AG_EMAIL_SMTP_HOST")
+				os.Unsetenv("AG_EMAIL_FROM")
+				os.Unsetenv("AG_EMAIL_TO")
+			}()
+
+			_, err := Load()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestLoad_Success(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Reset()
+	os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+	os.Setenv("AG_ARGOCD_USERNAME", "admin")
+	os.Setenv("AG_ARGOCD_PASSWORD", "password123")
+	os.Setenv("AG_ARGOCD_INSECURE", "true")
+	os.Setenv("AG_VERBOSE", "true")
+	os.Setenv("AG_CONCURRENCY", "20")
+	os.Setenv("AG_SOURCE_NAME", "my-chart")
+	os.Setenv("AG_LABELS", "env=prod,team=platform")
+
+	defer func() {
+		os.Unsetenv("AG_ARGOCD_URL")
+		os.Unsetenv("AG_ARGOCD_USERNAME")
+		os.Unsetenv("AG_ARGOCD_PASSWORD")
+		os.Unsetenv("AG_ARGOCD_INSECURE")
+		os.Unsetenv("AG_VERBOSE")
+		os.Unsetenv("AG_CONCURRENCY")
+		os.Unsetenv("AG_SOURCE_NAME")
+		os.Unsetenv("AG_LABELS")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, "https://argocd.example.com", cfg.ArgocdURL)
+	assert.Equal(t, "admin", cfg.ArgocdUsername)
+	assert.Equal(t, "password123", cfg.ArgocdPassword)
+	assert.True(t, cfg.ArgocdInsecure)
+	assert.True(t, cfg.Verbose)
+	assert.Equal(t, 20, cfg.Concurrency)
+	assert.Equal(t, "my-chart", cfg.SourceName)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "platform"}, cfg.Labels)
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Reset()
+	os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+	os.Setenv("AG_ARGOCD_USERNAME", "admin")
+	os.Setenv("AG_ARGOCD_PASSWORD", "password")
+
+	defer func() {
+		os.Unsetenv("AG_ARGOCD_URL")
+		os.Unsetenv("AG_ARGOCD_USERNAME")
+		os.Unsetenv("AG_ARGOCD_PASSWORD")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	// Check defaults
+	assert.False(t, cfg.Verbose)
+	assert.False(t, cfg.ArgocdInsecure)
+	assert.False(t, cfg.CheckSyncWindows)
+	assert.Equal(t, 10, cfg.Concurrency)
+	assert.Equal(t, "chart-repo", cfg.SourceName)
+	assert.Equal(t, []string{"*"}, cfg.Projects)
+	assert.Equal(t, []string{"*"}, cfg.AppNames)
+	assert.Equal(t, []string{"*"}, cfg.AppNamespaces)
+	assert.Equal(t, map[string]string{}, cfg.Labels)
+	assert.True(t, cfg.UseHelmConfig)
+	assert.Empty(t, cfg.HelmRepositoryConfig)
+	assert.Equal(t, ":8080", cfg.ServeAddress)
+	assert.Equal(t, 24*time.Hour, cfg.ServeInterval)
+	assert.Equal(t, "argazer-state.json", cfg.StateFile)
+	assert.Empty(t, cfg.SyslogAddress)
+	assert.Equal(t, NotificationGroupingNone, cfg.NotificationGrouping)
+	assert.Equal(t, "en", cfg.Language)
+	assert.Equal(t, "0078D7", cfg.NotificationThemeColor)
+	assert.Empty(t, cfg.NotificationEmojiMajor)
+	assert.Equal(t, "local0", cfg.SyslogFacility)
+	assert.Equal(t, "notice", cfg.SyslogSeverity)
+	assert.Empty(t, cfg.TelegramWebhookSecret)
+}
+
+func TestLoad_NotificationGrouping(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		grouping    string
+		expected    string
+		expectedErr string
+	}{
+		{name: "project", grouping: "project", expected: NotificationGroupingProject},
+		{name: "none", grouping: "none", expected: NotificationGroupingNone},
+		{name: "invalid", grouping: "team", expectedErr: "notification_grouping must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			os.Setenv("AG_NOTIFICATION_GROUPING", tt.grouping)
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_NOTIFICATION_GROUPING")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.NotificationGrouping)
+		})
+	}
+}
+
+func TestLoad_GroupBy(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		groupBy     string
+		expected    string
+		expectedErr string
+	}{
+		{name: "default", expected: GroupByNone},
+		{name: "repo", groupBy: "repo", expected: GroupByRepo},
+		{name: "cluster", groupBy: "cluster", expected: GroupByCluster},
+		{name: "invalid", groupBy: "team", expectedErr: "group_by must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			if tt.groupBy != "" {
+				viper.Set("group_by", tt.groupBy)
+			}
+
+			cfg, err := LoadWithoutArgocd()
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.GroupBy)
+		})
+	}
+}
+
+func TestLoad_TableLayout(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name            string
+		sortBy          string
+		columns         []string
+		expectedSortBy  string
+		expectedColumns []string
+		expectedErr     string
+	}{
+		{name: "default", expectedSortBy: SortByApp, expectedColumns: DefaultColumns},
+		{name: "severity", sortBy: "severity", expectedSortBy: SortBySeverity, expectedColumns: DefaultColumns},
+		{name: "columns", columns: []string{" App", "REPO ", "link"}, expectedSortBy: SortByApp, expectedColumns: []string{ColumnApp, ColumnRepo, ColumnLink}},
+		{name: "invalid sort", sortBy: "age", expectedErr: "sort_by must be one of"},
+		{name: "invalid column", columns: []string{"app", "owner"}, expectedErr: "columns: unknown column 'owner'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			if tt.sortBy != "" {
+				viper.Set("sort_by", tt.sortBy)
+			}
+			if tt.columns != nil {
+				viper.Set("columns", tt.columns)
+			}
+
+			cfg, err := LoadWithoutArgocd()
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSortBy, cfg.SortBy)
+			assert.Equal(t, tt.expectedColumns, cfg.Columns)
+		})
+	}
+}
+
+func TestLoad_OutputFiles(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Reset()
+	viper.Set("output_files", []string{"results.json", "report.HTML"})
+	cfg, err := LoadWithoutArgocd()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"results.json", "report.HTML"}, cfg.OutputFiles)
+
+	viper.Reset()
+	t.Setenv("AG_OUTPUT_FILES", "/reports/results.json,/reports/report.html")
+	cfg, err = LoadWithoutArgocd()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/reports/results.json", "/reports/report.html"}, cfg.OutputFiles)
+
+	viper.Reset()
+	viper.Set("output_files", []string{"results.yaml"})
+	_, err = LoadWithoutArgocd()
+	assert.ErrorContains(t, err, "output_files: cannot infer the format of 'results.yaml'")
+}
+
+func TestOutputFileFormat(t *testing.T) {
+	tests := map[string]string{
+		"results.json":        OutputFormatJSON,
+		"/reports/scan.md":    OutputFormatMarkdown,
+		"junit.xml":           OutputFormatJUnit,
+		"report.HTML":         OutputFormatHTML,
+		"argazer.txt":         OutputFormatTable,
+		"report.markdown":     OutputFormatMarkdown,
+		"archive/scan.v2.htm": OutputFormatHTML,
+	}
+	for file, expected := range tests {
+		format, ok := OutputFileFormat(file)
+		assert.True(t, ok, file)
+		assert.Equal(t, expected, format, file)
+	}
+
+	_, ok := OutputFileFormat("results")
+	assert.False(t, ok, "files without an extension have no format")
+}
+
+func TestLoad_Language(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		language    string
+		expected    string
+		expectedErr string
+	}{
+		{name: "german", language: "de", expected: "de"},
+		{name: "uppercase", language: "FR", expected: "fr"},
+		{name: "unsupported", language: "it", expectedErr: "language must be one of: 'de', 'en', 'es', 'fr'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			os.Setenv("AG_LANGUAGE", tt.language)
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_LANGUAGE")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.Language)
+		})
+	}
+}
+
+func TestNormalizeHexColor(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "", expected: ""},
+		{input: "d70000", expected: "D70000"},
+		{input: "#2eb886", expected: "2EB886"},
+		{input: "red", wantErr: true},
+		{input: "#12345", wantErr: true},
+		{input: "GGGGGG", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			color, err := normalizeHexColor(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, color)
+		})
+	}
+}
+
+func TestLoad_StatusFilters(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name           string
+		syncStatus     string
+		health         string
+		expectedSync   []string
+		expectedHealth []string
+		expectedErr    string
+	}{
+		{name: "unset", expectedSync: []string{}, expectedHealth: []string{}},
+		{name: "normalized case", syncStatus: "outofsync,Synced", health: "HEALTHY", expectedSync: []string{"OutOfSync", "Synced"}, expectedHealth: []string{"Healthy"}},
+		{name: "invalid sync status", syncStatus: "drifted", expectedErr: "sync_status must be one of: 'Synced', 'OutOfSync', 'Unknown' (got: 'drifted')"},
+		{name: "invalid health", health: "broken", expectedErr: "health_status must be one of:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			if tt.syncStatus != "" {
+				os.Setenv("AG_SYNC_STATUS", tt.syncStatus)
+			}
+			if tt.health != "" {
+				os.Setenv("AG_HEALTH_STATUS", tt.health)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_SYNC_STATUS")
+				os.Unsetenv("AG_HEALTH_STATUS")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSync, cfg.SyncStatus)
+			assert.Equal(t, tt.expectedHealth, cfg.HealthStatus)
+		})
+	}
+}
+
+func TestLoad_PRComment(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		prComment   string
+		expected    string
+		expectedErr string
+	}{
+		{name: "disabled", prComment: "", expected: ""},
+		{name: "github", prComment: "github", expected: PRCommentGitHub},
+		{name: "gitlab", prComment: "gitlab", expected: PRCommentGitLab},
+		{name: "bitbucket", prComment: "bitbucket", expected: PRCommentBitbucket},
+		{name: "bitbucket server without url", prComment: "bitbucket-server", expectedErr: "bitbucket_url is required"},
+		{name: "invalid", prComment: "gitea", expectedErr: "pr_comment must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			os.Setenv("AG_PR_COMMENT", tt.prComment)
+			os.Setenv("AG_GITHUB_PR_NUMBER", "42")
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_PR_COMMENT")
+				os.Unsetenv("AG_GITHUB_PR_NUMBER")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.PRComment)
+			assert.Equal(t, 42, cfg.GitHubPRNumber)
+		})
+	}
+}
+
+func TestLoad_GitOps(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name         string
+		provider     string
+		repositories []map[string]any
+		expectedErr  string
+	}{
+		{name: "disabled", provider: ""},
+		{
+			name:         "github",
+			provider:     "github",
+			repositories: []map[string]any{{"project": "web", "repository": "org/deploy", "path": "apps/{{.AppName}}.yaml", "base_branch": "main"}},
+		},
+		{name: "gitlab without repositories", provider: "gitlab", expectedErr: "gitops_repositories is required"},
+		{
+			name:         "repository without path",
+			provider:     "gitlab",
+			repositories: []map[string]any{{"repository": "group/deploy"}},
+			expectedErr:  "gitops_repositories[0]: repository and path are required",
+		},
+		{name: "invalid", provider: "gitea", expectedErr: "gitops_provider must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			os.Setenv("AG_GITOPS_PROVIDER", tt.provider)
+			if tt.repositories != nil {
+				viper.Set("gitops_repositories", tt.repositories)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_GITOPS_PROVIDER")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.provider, cfg.GitOpsProvider)
+			if tt.repositories != nil {
+				assert.Equal(t, []GitOpsRepository{{Project: "web", Repository: "org/deploy", Path: "apps/{{.AppName}}.yaml", BaseBranch: "main"}}, cfg.GitOpsRepositories)
+			}
+		})
+	}
+}
+
+func TestLoad_Ignore(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		rules       []map[string]any
+		expected    []IgnoreRule
+		expectedErr string
+	}{
+		{name: "none"},
+		{
+			name:     "rules",
+			rules:    []map[string]any{{"chart": "redis", "version": "18.*", "reason": "breaks persistence"}, {"app": "legacy-*", "until": "2025-06-30"}},
+			expected: []IgnoreRule{{Chart: "redis", Version: "18.*", Reason: "breaks persistence"}, {App: "legacy-*", Until: "2025-06-30"}},
+		},
+		{name: "no criteria", rules: []map[string]any{{"reason": "everything"}}, expectedErr: "ignore[0]: at least one of app, chart, repo or version is required"},
+		{name: "invalid glob", rules: []map[string]any{{"version": "[1-"}}, expectedErr: "ignore[0]: invalid version pattern"},
+		{name: "invalid until", rules: []map[string]any{{"app": "web", "until": "next week"}}, expectedErr: "ignore[0]: until must be a date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			if tt.rules != nil {
+				viper.Set("ignore", tt.rules)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expected == nil {
+				assert.Empty(t, cfg.Ignore)
+				return
+			}
+			assert.Equal(t, tt.expected, cfg.Ignore)
+		})
+	}
+}
+
+func TestIgnoreRule_Expiry(t *testing.T) {
+	expiry, err := IgnoreRule{}.Expiry()
+	require.NoError(t, err)
+	assert.True(t, expiry.IsZero())
+
+	// A date covers the whole day
+	expiry, err = IgnoreRule{Until: "2025-06-30"}.Expiry()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), expiry)
+
+	expiry, err = IgnoreRule{Until: "2025-06-30T18:00:00+02:00"}.Expiry()
+	require.NoError(t, err)
+	assert.True(t, time.Date(2025, 6, 30, 16, 0, 0, 0, time.UTC).Equal(expiry))
+
+	_, err = IgnoreRule{Until: "30/06/2025"}.Expiry()
+	assert.Error(t, err)
+}
+
+func TestLoad_Constraints(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		constraints map[string]any
+		expectedErr string
+	}{
+		{name: "keywords and ranges", constraints: map[string]any{"frontend": "minor", "redis": ">=17.0.0 <18.0.0", "nginx": "~1.4.x"}},
+		{name: "invalid range", constraints: map[string]any{"redis": "newest"}, expectedErr: "constraints: invalid constraint for 'redis'"},
+		{name: "empty", constraints: map[string]any{"redis": ""}, expectedErr: "constraint must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			viper.Set("constraints", tt.constraints)
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, cfg.Constraints, len(tt.constraints))
+			assert.Equal(t, "minor", cfg.Constraints["frontend"])
+		})
+	}
+}
+
+func TestLoad_NotificationTemplates(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		templates   map[string]any
+		expectedErr string
+	}{
+		{name: "default and channel", templates: map[string]any{
+			"default": map[string]any{"subject": "/etc/argazer/subject.tmpl", "body": "/etc/argazer/body.tmpl"},
+			"slack":   map[string]any{"body": "/etc/argazer/slack.tmpl"},
+		}},
+		{name: "unknown channel", templates: map[string]any{"pagerduty": map[string]any{"body": "body.tmpl"}}, expectedErr: "notification_templates keys must be 'default' or a notification channel (got: 'pagerduty')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			viper.Set("notification_templates", tt.templates)
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, NotificationTemplate{Subject: "/etc/argazer/subject.tmpl", Body: "/etc/argazer/slack.tmpl"}, cfg.NotificationTemplateFor("slack"))
+			assert.Equal(t, NotificationTemplate{Subject: "/etc/argazer/subject.tmpl", Body: "/etc/argazer/body.tmpl"}, cfg.NotificationTemplateFor("email"))
+		})
+	}
+}
+
+func TestLoad_ExitCodeMode(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		mode        string
+		expected    string
+		expectedErr string
+	}{
+		{name: "default", mode: "", expected: ExitCodeModeSimple},
+		{name: "detailed", mode: "detailed", expected: ExitCodeModeDetailed},
+		{name: "invalid", mode: "terraform", expectedErr: "exit_code_mode must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			if tt.mode != "" {
+				os.Setenv("AG_EXIT_CODE_MODE", tt.mode)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_EXIT_CODE_MODE")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.ExitCodeMode)
+		})
+	}
+}
+
+func TestLoad_FailOn(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		failOn      string
+		expected    string
+		expectedErr string
+	}{
+		{name: "default", failOn: "", expected: ""},
+		{name: "minor", failOn: "minor", expected: FailOnMinor},
+		{name: "security", failOn: "security", expected: FailOnSecurity},
+		{name: "errors", failOn: "errors", expected: FailOnErrors},
+		{name: "outside constraint", failOn: "outside-constraint", expected: FailOnOutsideConstraint},
+		{name: "none", failOn: "none", expected: FailOnNone},
+		{name: "invalid", failOn: "critical", expectedErr: "fail_on must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			if tt.failOn != "" {
+				os.Setenv("AG_FAIL_ON", tt.failOn)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				os.Unsetenv("AG_FAIL_ON")
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.FailOn)
+		})
+	}
+}
+
+func TestLoad_Timeouts(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expected    map[string]time.Duration
+		expectedErr string
+	}{
+		{
+			name:     "disabled by default",
+			expected: map[string]time.Duration{"timeout": 0, "git": 0},
+		},
+		{
+			name:     "global and per-component",
+			env:      map[string]string{"AG_TIMEOUT": "10m", "AG_GIT_TIMEOUT": "2m", "AG_NOTIFY_TIMEOUT": "30s"},
+			expected: map[string]time.Duration{"timeout": 10 * time.Minute, "git": 2 * time.Minute, "notify": 30 * time.Second},
+		},
+		{
+			name:        "negative",
+			env:         map[string]string{"AG_HELM_TIMEOUT": "-1s"},
+			expectedErr: "helm_timeout must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected["timeout"], cfg.Timeout)
+			assert.Equal(t, tt.expected["git"], cfg.GitTimeout)
+			assert.Equal(t, tt.expected["notify"], cfg.NotifyTimeout)
+		})
+	}
+}
+
+func TestLoad_CircuitBreakerThreshold(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expected    int
+		expectedErr string
+	}{
+		{name: "default", expected: 3},
+		{name: "disabled", env: map[string]string{"AG_CIRCUIT_BREAKER_THRESHOLD": "0"}, expected: 0},
+		{name: "negative", env: map[string]string{"AG_CIRCUIT_BREAKER_THRESHOLD": "-1"}, expectedErr: "circuit_breaker_threshold must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.CircuitBreakerThreshold)
+		})
+	}
+}
+
+func TestLoad_TempDirMaxAge(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expected    time.Duration
+		expectedErr string
+	}{
+		{name: "default", expected: time.Hour},
+		{name: "custom", env: map[string]string{"AG_TEMP_DIR_MAX_AGE": "6h"}, expected: 6 * time.Hour},
+		{name: "disabled", env: map[string]string{"AG_TEMP_DIR_MAX_AGE": "0"}, expected: 0},
+		{name: "negative", env: map[string]string{"AG_TEMP_DIR_MAX_AGE": "-1h"}, expectedErr: "temp_dir_max_age must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.TempDirMaxAge)
+		})
+	}
+}
+
+func TestLoad_IndexCache(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name            string
+		env             map[string]string
+		expectedTTL     time.Duration
+		expectedDir     string
+		expectedMaxAge  time.Duration
+		expectedMaxSize int
+		expectedErr     string
+	}{
+		{name: "default", expectedTTL: 5 * time.Minute, expectedMaxAge: 7 * 24 * time.Hour, expectedMaxSize: 512},
+		{name: "custom", env: map[string]string{"AG_INDEX_CACHE_TTL": "1h", "AG_CACHE_DIR": "/var/cache/argazer", "AG_CACHE_MAX_AGE": "24h", "AG_CACHE_MAX_SIZE": "100"}, expectedTTL: time.Hour, expectedDir: "/var/cache/argazer", expectedMaxAge: 24 * time.Hour, expectedMaxSize: 100},
+		{name: "disabled", env: map[string]string{"AG_INDEX_CACHE_TTL": "0", "AG_CACHE_MAX_AGE": "0", "AG_CACHE_MAX_SIZE": "0"}, expectedTTL: 0},
+		{name: "negative", env: map[string]string{"AG_INDEX_CACHE_TTL": "-1m"}, expectedErr: "index_cache_ttl must not be negative"},
+		{name: "negative max age", env: map[string]string{"AG_CACHE_MAX_AGE": "-1h"}, expectedErr: "cache_max_age must not be negative"},
+		{name: "negative max size", env: map[string]string{"AG_CACHE_MAX_SIZE": "-1"}, expectedErr: "cache_max_size must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTTL, cfg.IndexCacheTTL)
+			assert.Equal(t, tt.expectedDir, cfg.CacheDir)
+			assert.Equal(t, tt.expectedMaxAge, cfg.CacheMaxAge)
+			assert.Equal(t, tt.expectedMaxSize, cfg.CacheMaxSize)
+		})
+	}
+}
+
+func TestLoad_AppOfApps(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name             string
+		env              map[string]string
+		expectedEnabled  bool
+		expectedMaxDepth int
+		expectedErr      string
+	}{
+		{name: "default", expectedMaxDepth: 3},
+		{name: "enabled", env: map[string]string{"AG_APP_OF_APPS": "true", "AG_APP_OF_APPS_MAX_DEPTH": "1"}, expectedEnabled: true, expectedMaxDepth: 1},
+		{name: "zero depth", env: map[string]string{"AG_APP_OF_APPS": "true", "AG_APP_OF_APPS_MAX_DEPTH": "0"}, expectedErr: "app_of_apps_max_depth must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedEnabled, cfg.AppOfApps)
+			assert.Equal(t, tt.expectedMaxDepth, cfg.AppOfAppsMaxDepth)
+		})
+	}
+}
+
+func TestLoad_ReleaseNotes(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name              string
+		env               map[string]string
+		expectedEnabled   bool
+		expectedMaxLength int
+		expectedErr       string
+	}{
+		{name: "default", expectedMaxLength: 300},
+		{name: "enabled", env: map[string]string{"AG_RELEASE_NOTES": "true", "AG_RELEASE_NOTES_MAX_LENGTH": "1000"}, expectedEnabled: true, expectedMaxLength: 1000},
+		{name: "negative length", env: map[string]string{"AG_RELEASE_NOTES_MAX_LENGTH": "-1"}, expectedErr: "release_notes_max_length must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedEnabled, cfg.ReleaseNotes)
+			assert.Equal(t, tt.expectedMaxLength, cfg.ReleaseNotesMaxLength)
+		})
+	}
+}
+
+func TestLoad_Enrich(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expected    []string
+		expectedErr string
+	}{
+		{name: "default", expected: []string{}},
+		{name: "artifacthub", env: map[string]string{"AG_ENRICH": "artifacthub"}, expected: []string{"artifacthub"}},
+		{name: "unknown source", env: map[string]string{"AG_ENRICH": "artifacthub,helmhub"}, expectedErr: "enrich must only contain 'artifacthub' (got: 'helmhub')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.Enrich)
+		})
+	}
+}
+
+func TestLoad_TagExclusions(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name             string
+		env              map[string]string
+		repositories     []map[string]any
+		expectedTags     []string
+		expectedPatterns []string
+		expectedErr      string
+	}{
+		{name: "defaults", expectedTags: []string{"latest", "dev", "main", "master", "stable"}, expectedPatterns: []string{}},
+		{
+			name:             "custom",
+			env:              map[string]string{"AG_EXCLUDED_TAGS": "latest,edge", "AG_EXCLUDED_TAG_PATTERNS": "-nightly$"},
+			expectedTags:     []string{"latest", "edge"},
+			expectedPatterns: []string{"-nightly$"},
+		},
+		{name: "invalid pattern", env: map[string]string{"AG_EXCLUDED_TAG_PATTERNS": "(rc"}, expectedErr: "excluded_tag_patterns: invalid pattern '(rc'"},
+		{
+			name:         "repository override without url",
+			repositories: []map[string]any{{"tags": []string{"latest"}}},
+			expectedErr:  "repository_tag_exclusions[0]: url is required",
+		},
+		{
+			name:         "repository override with invalid pattern",
+			repositories: []map[string]any{{"url": "oci://registry.example.com/charts", "patterns": []string{"[a-"}}},
+			expectedErr:  "repository_tag_exclusions[0].patterns: invalid pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+			if tt.repositories != nil {
+				viper.Set("repository_tag_exclusions", tt.repositories)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTags, cfg.ExcludedTags)
+			assert.Equal(t, tt.expectedPatterns, cfg.ExcludedTagPatterns)
+		})
+	}
+}
+
+func TestLoad_NameFilters(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		projects    []string
+		appNames    []string
+		exclusions  map[string][]string
+		expectedErr string
+	}{
+		{name: "names and patterns", projects: []string{"payments", "team-*"}, appNames: []string{"/^api-/", "worker-?"}},
+		{name: "exclusions", exclusions: map[string][]string{"exclude_projects": {"sandbox-*"}, "exclude_app_names": {"/-canary$/"}, "exclude_repos": {"https://mirror.internal"}}},
+		{name: "invalid excluded app regex", exclusions: map[string][]string{"exclude_app_names": {"/[a-/"}}, expectedErr: "exclude_app_names: invalid pattern '/[a-/'"},
+		{name: "invalid project regex", projects: []string{"/(team/"}, expectedErr: "projects: invalid pattern '/(team/'"},
+		{name: "invalid app glob", appNames: []string{"api-[a"}, expectedErr: "app_names: invalid pattern 'api-[a'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			if tt.projects != nil {
+				viper.Set("projects", tt.projects)
+			}
+			if tt.appNames != nil {
+				viper.Set("app_names", tt.appNames)
+			}
+			for key, values := range tt.exclusions {
+				viper.Set(key, values)
+			}
+
+			_, err := LoadWithoutArgocd()
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLoad_RepositoryConnections(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		repositories []map[string]any
+		expectedErr  string
+	}{
+		{name: "defaults"},
+		{
+			name:         "proxy and CA",
+			env:          map[string]string{"AG_PROXY_URL": "http://proxy.example.com:3128"},
+			repositories: []map[string]any{{"url": "oci://harbor.example.com", "ca_file": "/etc/argazer/ca.pem"}},
+		},
+		{name: "invalid proxy", env: map[string]string{"AG_PROXY_URL": "proxy.example.com"}, expectedErr: "proxy_url must be a URL"},
+		{
+			name:         "missing url",
+			repositories: []map[string]any{{"ca_file": "/etc/argazer/ca.pem"}},
+			expectedErr:  "repository_tls[0]: url is required",
+		},
+		{
+			name:         "no settings",
+			repositories: []map[string]any{{"url": "oci://harbor.example.com"}},
+			expectedErr:  "repository_tls[0]: ca_file or insecure_skip_verify is required",
+		},
+		{
+			name:         "repositories",
+			repositories: []map[string]any{{"url": "oci://harbor.example.com", "timeout": "2m", "disabled": true}},
+		},
+		{
+			name:         "repositories without url",
+			repositories: []map[string]any{{"timeout": "2m"}},
+			expectedErr:  "repositories[0]: url is required",
+		},
+		{
+			name:         "repositories with negative timeout",
+			repositories: []map[string]any{{"url": "oci://harbor.example.com", "timeout": "-1s"}},
+			expectedErr:  "repositories[0]: timeout must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+			key := "repository_tls"
+			if strings.HasPrefix(tt.name, "repositories") {
+				key = "repositories"
+			}
+			if tt.repositories != nil {
+				viper.Set(key, tt.repositories)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.env["AG_PROXY_URL"], cfg.ProxyURL)
+			if key == "repositories" {
+				require.Len(t, cfg.Repositories, 1)
+				assert.Equal(t, 2*time.Minute, cfg.Repositories[0].Timeout)
+				assert.True(t, cfg.Repositories[0].Disabled)
+				return
+			}
+			assert.Len(t, cfg.RepositoryTLS, len(tt.repositories))
+		})
+	}
+}
+
+func TestLoad_MaxApps(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expected    Config
+		expectedErr string
+	}{
+		{
+			name:     "no cap by default",
+			expected: Config{MaxApps: 0, MaxAppsMode: MaxAppsModeFirst},
+		},
+		{
+			name:     "deterministic sample",
+			env:      map[string]string{"AG_MAX_APPS": "50", "AG_MAX_APPS_MODE": "sample", "AG_SAMPLE_SEED": "smoke"},
+			expected: Config{MaxApps: 50, MaxAppsMode: MaxAppsModeSample, SampleSeed: "smoke"},
+		},
+		{
+			name:        "negative",
+			env:         map[string]string{"AG_MAX_APPS": "-1"},
+			expectedErr: "max_apps must not be negative",
+		},
+		{
+			name:        "invalid mode",
+			env:         map[string]string{"AG_MAX_APPS_MODE": "random"},
+			expectedErr: "max_apps_mode must be one of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.MaxApps, cfg.MaxApps)
+			assert.Equal(t, tt.expected.MaxAppsMode, cfg.MaxAppsMode)
+			assert.Equal(t, tt.expected.SampleSeed, cfg.SampleSeed)
+		})
+	}
+}
+
+func TestLoad_KeychainReferences(t *testing.T) {
+	defer viper.Reset()
+
+	secrets := map[string]string{"argocd": "argocd-secret", "team-a": "team-a-token", "harbor": "robot-token"}
+	previous := keychainResolve
+	keychainResolve = func(value string) (string, error) {
+		secret, ok := secrets[strings.TrimPrefix(value, keychain.Prefix)]
+		if !ok {
+			return "", keychain.ErrNotFound
+		}
+		return secret, nil
+	}
+	defer func() { keychainResolve = previous }()
+
+	tests := []struct {
+		name        string
+		password    string
+		expectedErr string
+	}{
+		{name: "resolved", password: "keychain:argocd"},
+		{name: "missing", password: "keychain:unknown", expectedErr: "failed to resolve argocd_password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", tt.password)
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+			}()
+			viper.Set("argocd_project_tokens", map[string]string{"team-a": "keychain:team-a", "team-b": "plain-token"})
+			viper.Set("repository_auth", []map[string]string{{"url": "harbor.example.com", "username": "robot", "password": "keychain:harbor"}})
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				assert.ErrorIs(t, err, keychain.ErrNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "argocd-secret", cfg.ArgocdPassword)
+			assert.Equal(t, map[string]string{"team-a": "team-a-token", "team-b": "plain-token"}, cfg.ArgocdProjectTokens)
+			require.Len(t, cfg.RepositoryAuth, 1)
+			assert.Equal(t, "robot-token", cfg.RepositoryAuth[0].Password)
+		})
+	}
+}
+
+func TestLoad_Logging(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name             string
+		env              map[string]string
+		expectedLevels   map[string]string
+		expectedBurst    int
+		expectedInterval time.Duration
+		expectedErr      string
+	}{
+		{name: "defaults", expectedLevels: map[string]string{}, expectedInterval: time.Minute},
+		{
+			name:             "component levels and sampling",
+			env:              map[string]string{"AG_LOG_LEVELS": "helm=debug,argocd=warn", "AG_LOG_SAMPLE_BURST": "10", "AG_LOG_SAMPLE_INTERVAL": "30s"},
+			expectedLevels:   map[string]string{"helm": "debug", "argocd": "warn"},
+			expectedBurst:    10,
+			expectedInterval: 30 * time.Second,
+		},
+		{name: "unknown component", env: map[string]string{"AG_LOG_LEVELS": "kubelet=debug"}, expectedErr: "log_levels: unknown component 'kubelet'"},
+		{name: "invalid level", env: map[string]string{"AG_LOG_LEVELS": "helm=chatty"}, expectedErr: "log_levels: invalid level 'chatty' for helm"},
+		{name: "negative burst", env: map[string]string{"AG_LOG_SAMPLE_BURST": "-1"}, expectedErr: "log_sample_burst must not be negative"},
+		{name: "zero interval", env: map[string]string{"AG_LOG_SAMPLE_BURST": "5", "AG_LOG_SAMPLE_INTERVAL": "0"}, expectedErr: "log_sample_interval must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedLevels, cfg.LogLevels)
+			assert.Equal(t, tt.expectedBurst, cfg.LogSampleBurst)
+			assert.Equal(t, tt.expectedInterval, cfg.LogSampleInterval)
+		})
+	}
+}
+
+func TestLoad_Mode(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expected    string
+		expectedErr string
+	}{
+		{name: "default", env: map[string]string{"AG_ARGOCD_URL": "https://argocd.example.com", "AG_ARGOCD_USERNAME": "admin", "AG_ARGOCD_PASSWORD": "password"}, expected: ModeAPI},
+		{name: "api requires url", env: map[string]string{"AG_MODE": "api"}, expectedErr: "argocd_url is required"},
+		{name: "kubernetes without credentials", env: map[string]string{"AG_MODE": "kubernetes"}, expected: ModeKubernetes},
+		{name: "kubernetes with project tokens", env: map[string]string{"AG_MODE": "kubernetes", "AG_ARGOCD_PROJECT_TOKENS": "team-a=token"}, expectedErr: "argocd_project_tokens is not supported in 'kubernetes' mode"},
+		{name: "invalid", env: map[string]string{"AG_MODE": "grpc"}, expectedErr: "mode must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.Mode)
+		})
+	}
+}
+
+func TestLoad_History(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name              string
+		env               map[string]string
+		expectedHistory   bool
+		expectedOnlyNew   bool
+		expectedStateFile string
+	}{
+		{name: "defaults", env: map[string]string{}, expectedStateFile: "argazer-state.json"},
+		{name: "history", env: map[string]string{"AG_HISTORY": "true", "AG_STATE_FILE": "/data/state.json"}, expectedHistory: true, expectedStateFile: "/data/state.json"},
+		{name: "notify only new", env: map[string]string{"AG_NOTIFY_ONLY_NEW": "true"}, expectedOnlyNew: true, expectedStateFile: "argazer-state.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			os.Setenv("AG_ARGOCD_URL", "https://argocd.example.com")
+			os.Setenv("AG_ARGOCD_USERNAME", "admin")
+			os.Setenv("AG_ARGOCD_PASSWORD", "password")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			defer func() {
+				os.Unsetenv("AG_ARGOCD_URL")
+				os.Unsetenv("AG_ARGOCD_USERNAME")
+				os.Unsetenv("AG_ARGOCD_PASSWORD")
+				for key := range tt.env {
+					os.Unsetenv(key)
+				}
+			}()
+
+			cfg, err := Load()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedHistory, cfg.History)
+			assert.Equal(t, tt.expectedOnlyNew, cfg.NotifyOnlyNew)
+			assert.Equal(t, tt.expectedStateFile, cfg.StateFile)
+		})
+	}
+}
+
+func TestLoadUnvalidated(t *testing.T) {
+	defer viper.Reset()
+	viper.Reset()
+
+	// No ArgoCD settings at all, which Load rejects
+	cfg, err := LoadUnvalidated()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ArgocdURL)
+
+	_, err = Load()
+	require.Error(t, err)
 }
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"argazer/internal/keychain"
+)
+
+// redactedValue replaces secrets in the effective configuration
+const redactedValue = "<redacted>"
+
+// secretKeyParts mark the keys holding secrets, e.g. argocd_password, github_token, opsgenie_api_key
+var secretKeyParts = []string{"password", "passphrase", "token", "secret", "api_key"}
+
+// Effective returns the configuration as YAML, keyed like the configuration file, with secrets redacted:
+// passwords, tokens, signing secrets, webhook URLs and headers, and passwords in URLs
+// Keychain references are shown as they are, they only name the secret.
+func Effective(cfg *Config) ([]byte, error) {
+	out, err := yaml.Marshal(effectiveValue(reflect.ValueOf(*cfg), false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return out, nil
+}
+
+// effectiveValue converts a configuration value to the YAML it's written as, redacting it when secret
+func effectiveValue(value reflect.Value, secret bool) any {
+	if value.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(value.Int()).String()
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+		return effectiveValue(value.Elem(), secret)
+	case reflect.Struct:
+		fields := make(yaml.MapSlice, 0, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			fields = append(fields, yaml.MapItem{Key: name, Value: effectiveValue(value.Field(i), secret || isSecretKey(name))})
+		}
+		return fields
+	case reflect.Map:
+		// Keys are kept, they're names like projects or headers; only their values may be secret
+		keys := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		entries := make(yaml.MapSlice, 0, len(keys))
+		for _, key := range keys {
+			entries = append(entries, yaml.MapItem{Key: key, Value: effectiveValue(value.MapIndex(reflect.ValueOf(key)), secret)})
+		}
+		return entries
+	case reflect.Slice:
+		items := make([]any, value.Len())
+		for i := range items {
+			items[i] = effectiveValue(value.Index(i), secret)
+		}
+		return items
+	case reflect.String:
+		return redactString(value.String(), secret)
+	default:
+		return value.Interface()
+	}
+}
+
+// isSecretKey reports whether the values of a key are secrets
+// Webhook URLs are: most services authenticate them by a token in the URL.
+func isSecretKey(key string) bool {
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return strings.HasSuffix(key, "_webhook") || key == "webhook_url" || key == "webhook_headers"
+}
+
+// redactString redacts a secret, or the password of a URL like proxy_url
+func redactString(value string, secret bool) string {
+	if value == "" || keychain.IsReference(value) {
+		return value
+	}
+	if secret {
+		return redactedValue
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return u.Redacted()
+		}
+	}
+	return value
+}
@@ -0,0 +1,138 @@
+package gitops
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultGitHubAPIURL is the API of github.com
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHubProvider opens pull requests through the GitHub REST API
+type GitHubProvider struct {
+	api    *apiClient
+	logger *logrus.Entry
+}
+
+// NewGitHubProvider creates a GitHub pull request provider
+// The token needs the contents and pull requests write permissions on the manifest repositories.
+func NewGitHubProvider(apiURL, token string, httpClient *http.Client, logger *logrus.Entry) (*GitHubProvider, error) {
+	if token == "" {
+		return nil, fmt.Errorf("github_token is required to open pull requests")
+	}
+	if apiURL == "" {
+		apiURL = DefaultGitHubAPIURL
+	}
+
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"Authorization":        "Bearer " + token,
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+
+	return &GitHubProvider{
+		api:    newAPIClient("GitHub", apiURL, headers, httpClient, logger),
+		logger: logger,
+	}, nil
+}
+
+// OpenPullRequest creates the branch from the base branch, commits the rewritten file to it and opens
+// a pull request (implements Provider)
+func (p *GitHubProvider) OpenPullRequest(ctx context.Context, pr PullRequest) (string, error) {
+	owner, _, ok := strings.Cut(pr.Repository, "/")
+	if !ok {
+		return "", fmt.Errorf("repository must be in the form owner/name (got: '%s')", pr.Repository)
+	}
+	repoPath := "/repos/" + pr.Repository
+
+	// A previous run already opened the pull request
+	var open []struct {
+		HTMLURL string `json:"html_url"`
+	}
+	query := url.Values{"state": {"open"}, "head": {owner + ":" + pr.Branch}}
+	if err := p.api.do(ctx, http.MethodGet, repoPath+"/pulls?"+query.Encode(), nil, &open); err != nil {
+		return "", fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	if len(open) > 0 {
+		p.logger.WithField("url", open[0].HTMLURL).Info("Pull request is already open")
+		return open[0].HTMLURL, nil
+	}
+
+	base := pr.BaseBranch
+	if base == "" {
+		var repo struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := p.api.do(ctx, http.MethodGet, repoPath, nil, &repo); err != nil {
+			return "", fmt.Errorf("failed to get repository: %w", err)
+		}
+		base = repo.DefaultBranch
+	}
+
+	var file struct {
+		Content string `json:"content"`
+		SHA     string `json:"sha"`
+	}
+	contentsPath := repoPath + "/contents/" + escapePath(pr.Path)
+	if err := p.api.do(ctx, http.MethodGet, contentsPath+"?ref="+url.QueryEscape(base), nil, &file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", pr.Path, err)
+	}
+	// GitHub wraps the base64 content in lines
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", pr.Path, err)
+	}
+	rewritten, err := pr.Rewrite(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to update %s: %w", pr.Path, err)
+	}
+
+	var baseRef struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := p.api.do(ctx, http.MethodGet, repoPath+"/git/ref/heads/"+escapePath(base), nil, &baseRef); err != nil {
+		return "", fmt.Errorf("failed to get branch %s: %w", base, err)
+	}
+	ref := map[string]string{"ref": "refs/heads/" + pr.Branch, "sha": baseRef.Object.SHA}
+	if err := p.api.do(ctx, http.MethodPost, repoPath+"/git/refs", ref, nil); err != nil {
+		return "", fmt.Errorf("failed to create branch %s: %w", pr.Branch, err)
+	}
+
+	commit := map[string]string{
+		"message": pr.CommitMessage,
+		"content": base64.StdEncoding.EncodeToString(rewritten),
+		"sha":     file.SHA,
+		"branch":  pr.Branch,
+	}
+	if err := p.api.do(ctx, http.MethodPut, contentsPath, commit, nil); err != nil {
+		return "", fmt.Errorf("failed to commit %s: %w", pr.Path, err)
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	payload := map[string]string{"title": pr.Title, "head": pr.Branch, "base": base, "body": pr.Body}
+	if err := p.api.do(ctx, http.MethodPost, repoPath+"/pulls", payload, &created); err != nil {
+		return "", fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	p.logger.WithFields(logrus.Fields{"repository": pr.Repository, "url": created.HTMLURL}).Info("Opened pull request")
+	return created.HTMLURL, nil
+}
+
+// escapePath escapes each segment of a slash-separated path
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,102 @@
+package gitops
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPullRequest() PullRequest {
+	return PullRequest{
+		Repository:    "org/deploy",
+		Branch:        "argazer/nginx-1.1.0",
+		Path:          "apps/nginx.yaml",
+		Title:         "Update nginx to 1.1.0 in nginx",
+		Body:          "body",
+		CommitMessage: "Update nginx to 1.1.0 in nginx",
+		Rewrite: func(content []byte) ([]byte, error) {
+			return RewriteTargetRevision(content, "1.0.0", "1.1.0")
+		},
+	}
+}
+
+func TestGitHubProvider_OpenPullRequest(t *testing.T) {
+	var committed, created map[string]string
+	var branchRef map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/deploy/pulls":
+			assert.Equal(t, "org:argazer/nginx-1.1.0", r.URL.Query().Get("head"))
+			w.Write([]byte(`[]`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/deploy":
+			w.Write([]byte(`{"default_branch": "main"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/deploy/contents/apps/nginx.yaml":
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			content := base64.StdEncoding.EncodeToString([]byte("spec:\n  source:\n    targetRevision: 1.0.0\n"))
+			json.NewEncoder(w).Encode(map[string]string{"content": content[:10] + "\n" + content[10:], "sha": "blob-sha"})
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/deploy/git/ref/heads/main":
+			w.Write([]byte(`{"object": {"sha": "base-sha"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/org/deploy/git/refs":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&branchRef))
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && r.URL.Path == "/repos/org/deploy/contents/apps/nginx.yaml":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&committed))
+			w.Write([]byte(`{}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/org/deploy/pulls":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"html_url": "https://github.com/org/deploy/pull/7"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewGitHubProvider(server.URL, "test-token", nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	url, err := provider.OpenPullRequest(context.Background(), testPullRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/org/deploy/pull/7", url)
+
+	assert.Equal(t, map[string]string{"ref": "refs/heads/argazer/nginx-1.1.0", "sha": "base-sha"}, branchRef)
+	assert.Equal(t, "blob-sha", committed["sha"])
+	assert.Equal(t, "argazer/nginx-1.1.0", committed["branch"])
+	content, err := base64.StdEncoding.DecodeString(committed["content"])
+	require.NoError(t, err)
+	assert.Equal(t, "spec:\n  source:\n    targetRevision: 1.1.0\n", string(content))
+	assert.Equal(t, "main", created["base"])
+	assert.Equal(t, "argazer/nginx-1.1.0", created["head"])
+}
+
+func TestGitHubProvider_OpenPullRequest_AlreadyOpen(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/repos/org/deploy/pulls" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`[{"html_url": "https://github.com/org/deploy/pull/3"}]`))
+	}))
+	defer server.Close()
+
+	provider, err := NewGitHubProvider(server.URL, "test-token", nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	url, err := provider.OpenPullRequest(context.Background(), testPullRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/org/deploy/pull/3", url)
+}
+
+func TestNewGitHubProvider_RequiresToken(t *testing.T) {
+	_, err := NewGitHubProvider("", "", nil, logrus.NewEntry(logrus.New()))
+	assert.Error(t, err)
+}
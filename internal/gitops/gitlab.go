@@ -0,0 +1,115 @@
+package gitops
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultGitLabAPIURL is the API of gitlab.com
+const DefaultGitLabAPIURL = "https://gitlab.com/api/v4"
+
+// GitLabProvider opens merge requests through the GitLab REST API
+type GitLabProvider struct {
+	api    *apiClient
+	logger *logrus.Entry
+}
+
+// NewGitLabProvider creates a GitLab merge request provider
+// The token needs the api scope and at least the Developer role on the manifest projects.
+func NewGitLabProvider(apiURL, token string, httpClient *http.Client, logger *logrus.Entry) (*GitLabProvider, error) {
+	if token == "" {
+		return nil, fmt.Errorf("gitlab_token is required to open merge requests")
+	}
+	if apiURL == "" {
+		apiURL = DefaultGitLabAPIURL
+	}
+
+	return &GitLabProvider{
+		api:    newAPIClient("GitLab", apiURL, map[string]string{"PRIVATE-TOKEN": token}, httpClient, logger),
+		logger: logger,
+	}, nil
+}
+
+// OpenPullRequest commits the rewritten file to a new branch started from the base branch and opens
+// a merge request that removes the branch when merged (implements Provider)
+func (p *GitLabProvider) OpenPullRequest(ctx context.Context, pr PullRequest) (string, error) {
+	// Project paths such as group/project are URL-encoded as GitLab requires
+	projectPath := "/projects/" + url.PathEscape(pr.Repository)
+
+	// A previous run already opened the merge request
+	var open []struct {
+		WebURL string `json:"web_url"`
+	}
+	query := url.Values{"state": {"opened"}, "source_branch": {pr.Branch}}
+	if err := p.api.do(ctx, http.MethodGet, projectPath+"/merge_requests?"+query.Encode(), nil, &open); err != nil {
+		return "", fmt.Errorf("failed to list merge requests: %w", err)
+	}
+	if len(open) > 0 {
+		p.logger.WithField("url", open[0].WebURL).Info("Merge request is already open")
+		return open[0].WebURL, nil
+	}
+
+	base := pr.BaseBranch
+	if base == "" {
+		var project struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := p.api.do(ctx, http.MethodGet, projectPath, nil, &project); err != nil {
+			return "", fmt.Errorf("failed to get project: %w", err)
+		}
+		base = project.DefaultBranch
+	}
+
+	var file struct {
+		Content string `json:"content"`
+	}
+	filePath := projectPath + "/repository/files/" + url.PathEscape(pr.Path) + "?ref=" + url.QueryEscape(base)
+	if err := p.api.do(ctx, http.MethodGet, filePath, nil, &file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", pr.Path, err)
+	}
+	content, err := base64.StdEncoding.DecodeString(file.Content)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", pr.Path, err)
+	}
+	rewritten, err := pr.Rewrite(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to update %s: %w", pr.Path, err)
+	}
+
+	// A commit with start_branch creates the branch in the same request
+	commit := map[string]interface{}{
+		"branch":         pr.Branch,
+		"start_branch":   base,
+		"commit_message": pr.CommitMessage,
+		"actions": []map[string]string{{
+			"action":    "update",
+			"file_path": pr.Path,
+			"content":   string(rewritten),
+		}},
+	}
+	if err := p.api.do(ctx, http.MethodPost, projectPath+"/repository/commits", commit, nil); err != nil {
+		return "", fmt.Errorf("failed to commit %s: %w", pr.Path, err)
+	}
+
+	var created struct {
+		WebURL string `json:"web_url"`
+	}
+	payload := map[string]interface{}{
+		"source_branch":        pr.Branch,
+		"target_branch":        base,
+		"title":                pr.Title,
+		"description":          pr.Body,
+		"remove_source_branch": true,
+	}
+	if err := p.api.do(ctx, http.MethodPost, projectPath+"/merge_requests", payload, &created); err != nil {
+		return "", fmt.Errorf("failed to create merge request: %w", err)
+	}
+
+	p.logger.WithFields(logrus.Fields{"project": pr.Repository, "url": created.WebURL}).Info("Opened merge request")
+	return created.WebURL, nil
+}
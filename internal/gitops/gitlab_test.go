@@ -0,0 +1,89 @@
+package gitops
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabProvider_OpenPullRequest(t *testing.T) {
+	var commit struct {
+		Branch      string              `json:"branch"`
+		StartBranch string              `json:"start_branch"`
+		Actions     []map[string]string `json:"actions"`
+	}
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-token", r.Header.Get("PRIVATE-TOKEN"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/org/deploy/merge_requests":
+			assert.Equal(t, "argazer/nginx-1.1.0", r.URL.Query().Get("source_branch"))
+			w.Write([]byte(`[]`))
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/org/deploy/repository/files/apps/nginx.yaml":
+			assert.Equal(t, "/projects/org%2Fdeploy/repository/files/apps%2Fnginx.yaml", r.URL.EscapedPath())
+			assert.Equal(t, "release", r.URL.Query().Get("ref"))
+			json.NewEncoder(w).Encode(map[string]string{"content": base64.StdEncoding.EncodeToString([]byte("targetRevision: 1.0.0\n"))})
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/org/deploy/repository/commits":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&commit))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/org/deploy/merge_requests":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"web_url": "https://gitlab.com/org/deploy/-/merge_requests/5"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewGitLabProvider(server.URL, "test-token", nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	pr := testPullRequest()
+	pr.BaseBranch = "release"
+	url, err := provider.OpenPullRequest(context.Background(), pr)
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitlab.com/org/deploy/-/merge_requests/5", url)
+
+	assert.Equal(t, "argazer/nginx-1.1.0", commit.Branch)
+	assert.Equal(t, "release", commit.StartBranch)
+	require.Len(t, commit.Actions, 1)
+	assert.Equal(t, "apps/nginx.yaml", commit.Actions[0]["file_path"])
+	assert.Equal(t, "targetRevision: 1.1.0\n", commit.Actions[0]["content"])
+	assert.Equal(t, "release", created["target_branch"])
+	assert.Equal(t, true, created["remove_source_branch"])
+}
+
+func TestGitLabProvider_OpenPullRequest_RewriteFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/projects/org/deploy/merge_requests":
+			w.Write([]byte(`[]`))
+		case r.URL.Path == "/projects/org/deploy":
+			w.Write([]byte(`{"default_branch": "main"}`))
+		case r.URL.Path == "/projects/org/deploy/repository/files/apps/nginx.yaml":
+			json.NewEncoder(w).Encode(map[string]string{"content": base64.StdEncoding.EncodeToString([]byte("targetRevision: 0.9.0\n"))})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewGitLabProvider(server.URL, "test-token", nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	_, err = provider.OpenPullRequest(context.Background(), testPullRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no targetRevision 1.0.0")
+}
@@ -0,0 +1,349 @@
+// Package gitops opens pull/merge requests that bump an application's chart version in the Git
+// repository holding its manifests, for setups where ArgoCD applications must not be changed directly.
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Default templates, rendered with an Update
+const (
+	DefaultBranchTemplate = "argazer/{{.AppName}}-{{.LatestVersion}}"
+	DefaultTitleTemplate  = "Update {{.ChartName}} to {{.LatestVersion}} in {{.AppName}}"
+	DefaultBodyTemplate   = `Argazer found a newer version of the **{{.ChartName}}** chart used by the ArgoCD application **{{.AppName}}**.
+
+| | |
+|---|---|
+| Chart | ` + "`{{.ChartName}}`" + ` |
+| Repository | {{.RepoURL}} |
+| Current version | ` + "`{{.CurrentVersion}}`" + ` |
+| New version | ` + "`{{.LatestVersion}}`" + ` |
+{{- if .Severity}}
+| Update | {{.Severity}} |
+{{- end}}
+{{- if .URL}}
+
+[Open in ArgoCD]({{.URL}})
+{{- end}}
+`
+)
+
+// defaultTimeout is the timeout of the default HTTP client
+const defaultTimeout = 30 * time.Second
+
+// ErrNoRepository is returned when no repository mapping matches the application
+var ErrNoRepository = errors.New("no GitOps repository is configured for the application")
+
+// Update is an available chart update, the data the templates are rendered with
+type Update struct {
+	AppName        string
+	Namespace      string
+	Project        string
+	ChartName      string
+	RepoURL        string
+	CurrentVersion string
+	LatestVersion  string
+	Severity       string // "major", "minor" or "patch", empty for non-semver versions
+	URL            string // Application page in the ArgoCD web UI
+}
+
+// Repository maps applications to the Git repository and file holding their manifests
+type Repository struct {
+	Project    string // ArgoCD project the mapping applies to, empty for all
+	App        string // Application name glob, e.g. "team-*" (default: all)
+	Repository string // owner/name on GitHub, project ID or path on GitLab
+	Path       string // Manifest path template, e.g. "apps/{{.AppName}}.yaml"
+	BaseBranch string // Branch the pull request targets (default: the repository's default branch)
+}
+
+// Templates holds the templates of the branch name, pull request title and body
+// Empty templates fall back to the defaults.
+type Templates struct {
+	Branch string
+	Title  string
+	Body   string
+}
+
+// PullRequest describes a pull request changing a single file
+type PullRequest struct {
+	Repository    string
+	BaseBranch    string // Empty for the repository's default branch
+	Branch        string
+	Path          string
+	Title         string
+	Body          string
+	CommitMessage string
+	// Rewrite returns the file's new content from its content on the base branch
+	Rewrite func(content []byte) ([]byte, error)
+}
+
+// Provider opens pull requests on a code hosting platform
+type Provider interface {
+	// OpenPullRequest commits the rewritten file to a new branch and opens a pull request from it,
+	// returning the pull request's URL. An open pull request from the branch is returned as is.
+	OpenPullRequest(ctx context.Context, pr PullRequest) (string, error)
+}
+
+// mapping is a Repository with its path template parsed
+type mapping struct {
+	Repository
+	path *template.Template
+}
+
+// Creator opens pull requests for updates using the repository mappings
+type Creator struct {
+	provider Provider
+	mappings []mapping
+	branch   *template.Template
+	title    *template.Template
+	body     *template.Template
+	logger   *logrus.Entry
+}
+
+// NewCreator creates a pull request creator, failing on invalid templates or mappings
+func NewCreator(provider Provider, repositories []Repository, templates Templates, logger *logrus.Entry) (*Creator, error) {
+	c := &Creator{provider: provider, logger: logger}
+
+	var err error
+	if c.branch, err = parseTemplate("branch", templates.Branch, DefaultBranchTemplate); err != nil {
+		return nil, err
+	}
+	if c.title, err = parseTemplate("title", templates.Title, DefaultTitleTemplate); err != nil {
+		return nil, err
+	}
+	if c.body, err = parseTemplate("body", templates.Body, DefaultBodyTemplate); err != nil {
+		return nil, err
+	}
+
+	for i, repo := range repositories {
+		if repo.Repository == "" || repo.Path == "" {
+			return nil, fmt.Errorf("repository %d: repository and path are required", i)
+		}
+		if _, err := path.Match(repo.App, ""); err != nil {
+			return nil, fmt.Errorf("repository %d: invalid app pattern %q: %w", i, repo.App, err)
+		}
+		pathTemplate, err := parseTemplate("path", repo.Path, "")
+		if err != nil {
+			return nil, fmt.Errorf("repository %d: %w", i, err)
+		}
+		c.mappings = append(c.mappings, mapping{Repository: repo, path: pathTemplate})
+	}
+	return c, nil
+}
+
+// Create opens a pull request bumping the application's targetRevision, returning its URL
+func (c *Creator) Create(ctx context.Context, update Update) (string, error) {
+	m := c.match(update)
+	if m == nil {
+		return "", ErrNoRepository
+	}
+
+	manifestPath, err := render(m.path, update)
+	if err != nil {
+		return "", err
+	}
+	branch, err := render(c.branch, update)
+	if err != nil {
+		return "", err
+	}
+	title, err := render(c.title, update)
+	if err != nil {
+		return "", err
+	}
+	body, err := render(c.body, update)
+	if err != nil {
+		return "", err
+	}
+
+	pr := PullRequest{
+		Repository:    m.Repository.Repository,
+		BaseBranch:    m.BaseBranch,
+		Branch:        branchName(branch),
+		Path:          strings.TrimPrefix(manifestPath, "/"),
+		Title:         title,
+		Body:          body,
+		CommitMessage: title,
+		Rewrite: func(content []byte) ([]byte, error) {
+			return RewriteTargetRevision(content, update.CurrentVersion, update.LatestVersion)
+		},
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"app_name":   update.AppName,
+		"repository": pr.Repository,
+		"path":       pr.Path,
+		"branch":     pr.Branch,
+	}).Debug("Opening pull request")
+
+	url, err := c.provider.OpenPullRequest(ctx, pr)
+	if err != nil {
+		return "", fmt.Errorf("failed to open pull request in %s: %w", pr.Repository, err)
+	}
+	return url, nil
+}
+
+// match returns the first mapping matching the update's project and application name
+func (c *Creator) match(update Update) *mapping {
+	for i := range c.mappings {
+		m := &c.mappings[i]
+		if m.Project != "" && m.Project != "*" && m.Project != update.Project {
+			continue
+		}
+		if m.App != "" {
+			if ok, _ := path.Match(m.App, update.AppName); !ok {
+				continue
+			}
+		}
+		return m
+	}
+	return nil
+}
+
+// parseTemplate parses a template, using the fallback if it's empty
+func parseTemplate(name, text, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// render executes a template with the update
+func render(tmpl *template.Template, update Update) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, update); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+// invalidBranchChars matches characters not allowed (or not advisable) in Git branch names
+var invalidBranchChars = regexp.MustCompile(`[^A-Za-z0-9._/-]+`)
+
+// branchName turns a rendered branch template into a valid Git branch name
+func branchName(name string) string {
+	name = invalidBranchChars.ReplaceAllString(strings.TrimSpace(name), "-")
+	name = strings.ReplaceAll(name, "..", ".")
+	return strings.Trim(name, "/.-")
+}
+
+// targetRevisionLine matches a YAML targetRevision field: indentation (and list marker), opening quote,
+// value, closing quote and trailing comment
+var targetRevisionLine = regexp.MustCompile(`(?m)^([ \t]*(?:-[ \t]+)?targetRevision:[ \t]*)(["']?)([^"'\s#]+)(["']?)([ \t]*(?:#[^\r\n]*)?\r?)$`)
+
+// RewriteTargetRevision replaces the targetRevision set to from with to, keeping the rest of the file
+// (formatting, comments, other documents) untouched
+// Exactly one targetRevision must have the old value; otherwise the file is ambiguous and left alone.
+func RewriteTargetRevision(content []byte, from, to string) ([]byte, error) {
+	matches := 0
+	rewritten := targetRevisionLine.ReplaceAllFunc(content, func(line []byte) []byte {
+		parts := targetRevisionLine.FindSubmatch(line)
+		if string(parts[3]) != from || string(parts[2]) != string(parts[4]) {
+			return line
+		}
+		matches++
+		return []byte(string(parts[1]) + string(parts[2]) + to + string(parts[4]) + string(parts[5]))
+	})
+
+	switch matches {
+	case 0:
+		return nil, fmt.Errorf("no targetRevision %s found in the manifest", from)
+	case 1:
+		return rewritten, nil
+	default:
+		return nil, fmt.Errorf("%d targetRevision fields are set to %s in the manifest, can't tell which to update", matches, from)
+	}
+}
+
+// apiClient sends JSON requests to a code hosting API
+type apiClient struct {
+	name       string            // API name used in errors, e.g. "GitHub"
+	baseURL    string            // API base URL without trailing slash
+	headers    map[string]string // Headers sent with every request (e.g. Authorization)
+	httpClient *http.Client
+	logger     *logrus.Entry
+}
+
+// newAPIClient creates an API client, using a default HTTP client if none is given
+func newAPIClient(name, baseURL string, headers map[string]string, httpClient *http.Client, logger *logrus.Entry) *apiClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+
+	return &apiClient{
+		name:       name,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		headers:    headers,
+		httpClient: httpClient,
+		logger:     logger,
+	}
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out (if not nil)
+func (c *apiClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "argazer/1.0")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s API returned status %d: %s", c.name, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return nil
+}
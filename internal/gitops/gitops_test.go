@@ -0,0 +1,132 @@
+package gitops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingProvider records the pull requests it's asked to open
+type recordingProvider struct {
+	requests []PullRequest
+}
+
+func (p *recordingProvider) OpenPullRequest(ctx context.Context, pr PullRequest) (string, error) {
+	p.requests = append(p.requests, pr)
+	return "https://example.com/pr/1", nil
+}
+
+func TestRewriteTargetRevision(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "plain",
+			content:  "spec:\n  source:\n    chart: nginx\n    targetRevision: 1.0.0\n",
+			expected: "spec:\n  source:\n    chart: nginx\n    targetRevision: 1.1.0\n",
+		},
+		{
+			name:     "quoted with comment",
+			content:  "    targetRevision: \"1.0.0\" # pinned by team\n",
+			expected: "    targetRevision: \"1.1.0\" # pinned by team\n",
+		},
+		{
+			name:     "list item among other sources",
+			content:  "  sources:\n  - targetRevision: main\n    ref: values\n  - targetRevision: '1.0.0'\n    chart: nginx\n",
+			expected: "  sources:\n  - targetRevision: main\n    ref: values\n  - targetRevision: '1.1.0'\n    chart: nginx\n",
+		},
+		{
+			name:     "windows line endings",
+			content:  "targetRevision: 1.0.0\r\nchart: nginx\r\n",
+			expected: "targetRevision: 1.1.0\r\nchart: nginx\r\n",
+		},
+		{
+			name:        "version not found",
+			content:     "targetRevision: 2.0.0\n",
+			expectedErr: "no targetRevision 1.0.0",
+		},
+		{
+			name:        "ambiguous",
+			content:     "targetRevision: 1.0.0\n---\ntargetRevision: 1.0.0\n",
+			expectedErr: "can't tell which",
+		},
+		{
+			name:        "prefix of another version",
+			content:     "targetRevision: 1.0.0-rc.1\n",
+			expectedErr: "no targetRevision 1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RewriteTargetRevision([]byte(tt.content), "1.0.0", "1.1.0")
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(got))
+		})
+	}
+}
+
+func TestCreator_Create(t *testing.T) {
+	provider := &recordingProvider{}
+	creator, err := NewCreator(provider, []Repository{
+		{Project: "data", Repository: "org/data-apps", Path: "apps/{{.AppName}}.yaml"},
+		{App: "web-*", Repository: "org/web-apps", Path: "/{{.Namespace}}/{{.AppName}}/application.yaml", BaseBranch: "release"},
+	}, Templates{Branch: "bump/{{.AppName}}@{{.LatestVersion}}"}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	update := Update{
+		AppName:        "web-frontend",
+		Namespace:      "team-a",
+		Project:        "web",
+		ChartName:      "nginx",
+		RepoURL:        "https://charts.example.com",
+		CurrentVersion: "1.0.0",
+		LatestVersion:  "1.1.0+build.2",
+		Severity:       "minor",
+	}
+	url, err := creator.Create(context.Background(), update)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/pr/1", url)
+
+	require.Len(t, provider.requests, 1)
+	pr := provider.requests[0]
+	assert.Equal(t, "org/web-apps", pr.Repository)
+	assert.Equal(t, "release", pr.BaseBranch)
+	assert.Equal(t, "team-a/web-frontend/application.yaml", pr.Path)
+	assert.Equal(t, "bump/web-frontend-1.1.0-build.2", pr.Branch)
+	assert.Equal(t, "Update nginx to 1.1.0+build.2 in web-frontend", pr.Title)
+	assert.Contains(t, pr.Body, "| Current version | `1.0.0` |")
+	assert.Contains(t, pr.Body, "| Update | minor |")
+	assert.NotContains(t, pr.Body, "Open in ArgoCD")
+
+	rewritten, err := pr.Rewrite([]byte("targetRevision: 1.0.0\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "targetRevision: 1.1.0+build.2\n", string(rewritten))
+
+	_, err = creator.Create(context.Background(), Update{AppName: "api", Project: "web"})
+	assert.ErrorIs(t, err, ErrNoRepository)
+}
+
+func TestNewCreator_Invalid(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	_, err := NewCreator(&recordingProvider{}, []Repository{{Repository: "org/apps"}}, Templates{}, logger)
+	assert.ErrorContains(t, err, "repository and path are required")
+
+	_, err = NewCreator(&recordingProvider{}, []Repository{{Repository: "org/apps", Path: "{{.AppName"}}, Templates{}, logger)
+	assert.ErrorContains(t, err, "invalid path template")
+
+	_, err = NewCreator(&recordingProvider{}, nil, Templates{Body: "{{if}}"}, logger)
+	assert.ErrorContains(t, err, "invalid body template")
+}
@@ -0,0 +1,76 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// GetChartImages returns the container images a chart version deploys by default, from the image
+// blocks of its values.yaml (repository, tag and optional registry); a block without tag uses the
+// chart's appVersion, as most charts' templates do
+// Images set by templates alone, or only by the application's own values, aren't found.
+func (c *Checker) GetChartImages(ctx context.Context, repoURL, chartName, version string) ([]ImageReference, error) {
+	values, metadata, err := c.getChartFiles(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var chart ChartMetadata
+	if err := yaml.Unmarshal(metadata, &chart); err != nil {
+		return nil, fmt.Errorf("failed to parse Chart.yaml of %s %s: %w", chartName, version, err)
+	}
+	var parsed map[interface{}]interface{}
+	if err := yaml.Unmarshal(values, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse values of %s %s: %w", chartName, version, err)
+	}
+
+	var images []ImageReference
+	seen := make(map[string]bool)
+	for _, raw := range valuesImages(parsed, chart.AppVersion) {
+		image, err := ParseImageReference(raw)
+		if err != nil || seen[image.String()] {
+			continue
+		}
+		seen[image.String()] = true
+		images = append(images, image)
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].String() < images[j].String() })
+	return images, nil
+}
+
+// valuesImages builds image references from the maps of a values document with a repository key,
+// e.g. {registry: docker.io, repository: bitnami/nginx, tag: 1.25.3}
+func valuesImages(values map[interface{}]interface{}, appVersion string) []string {
+	var images []string
+	// Git or Helm repository URLs aren't images
+	if repository, ok := values["repository"].(string); ok && repository != "" && !strings.Contains(repository, "://") {
+		tag := appVersion
+		if value, ok := values["tag"]; ok && value != nil && fmt.Sprint(value) != "" {
+			tag = fmt.Sprint(value)
+		}
+		if registry, ok := values["registry"].(string); ok && registry != "" {
+			repository = strings.TrimSuffix(registry, "/") + "/" + repository
+		}
+		if tag != "" {
+			images = append(images, repository+":"+tag)
+		}
+	}
+
+	for _, value := range values {
+		switch nested := value.(type) {
+		case map[interface{}]interface{}:
+			images = append(images, valuesImages(nested, appVersion)...)
+		case []interface{}:
+			for _, item := range nested {
+				if m, ok := item.(map[interface{}]interface{}); ok {
+					images = append(images, valuesImages(m, appVersion)...)
+				}
+			}
+		}
+	}
+	return images
+}
@@ -2,6 +2,7 @@ package helm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,28 +17,164 @@ import (
 
 // Checker checks Helm repositories for new chart versions
 type Checker struct {
-	httpClient   *http.Client
-	ociChecker   *OCIChecker
-	gitClient    *GitClient
-	authProvider *auth.Provider
-	logger       *logrus.Entry
+	httpClient    *http.Client
+	ociChecker    *OCIChecker
+	gitClient     *GitClient
+	authProvider  *auth.Provider
+	tagExclusions *TagExclusions
+	circuits      *circuitBreaker     // nil when disabled
+	indexCache    *indexCache         // nil when disabled
+	releaseNotes  *ReleaseNotesClient // nil when disabled
+	indexFlights  flightGroup[*Index] // Index downloads in flight, by repository
+	retry         retryPolicy
+	overrides     map[string]RepositoryOverride // By normalized repository URL
+	logger        *logrus.Entry
+
+	// Deadlines of a single chart lookup by repository type (0 disables them)
+	helmTimeout time.Duration
+	ociTimeout  time.Duration
+	gitTimeout  time.Duration
 }
 
 // NewChecker creates a new Helm checker
+// Git clones are shared between lookups until ReleaseClones.
 func NewChecker(authProvider *auth.Provider, logger *logrus.Entry) (*Checker, error) {
+	gitLogger := logger.WithField("component", "git")
+	gitClient := NewGitClient("", "", gitLogger)
+	gitClient.authProvider = authProvider
+	gitClient.clones = newGitCloneCache(gitLogger)
+
 	return &Checker{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		ociChecker:   NewOCIChecker(authProvider, logger.WithField("type", "oci")),
-		gitClient:    NewGitClient("", "", logger.WithField("type", "git")), // Auth will be set per-request if needed
-		authProvider: authProvider,
-		logger:       logger,
+		ociChecker:    NewOCIChecker(authProvider, logger.WithField("component", "oci")),
+		gitClient:     gitClient,
+		authProvider:  authProvider,
+		tagExclusions: defaultTagExclusions(),
+		logger:        logger,
 	}, nil
 }
 
+// SetTagExclusions replaces the tags and versions ignored by Helm repository, OCI registry and Git lookups
+// Without it, DefaultExcludedTags are ignored everywhere.
+func (c *Checker) SetTagExclusions(exclusions *TagExclusions) {
+	c.tagExclusions = exclusions
+	c.ociChecker.tagExclusions = exclusions
+	c.gitClient.tagExclusions = exclusions
+}
+
+// SetTimeouts bounds each chart lookup in Helm repositories, OCI registries and Git repositories
+// A zero timeout leaves lookups of that repository type unbounded.
+func (c *Checker) SetTimeouts(helmTimeout, ociTimeout, gitTimeout time.Duration) {
+	c.helmTimeout = helmTimeout
+	c.ociTimeout = ociTimeout
+	c.gitTimeout = gitTimeout
+}
+
+// SetRetries sends index.yaml and OCI tags list requests failing to reach the repository up to
+// attempts times, waiting baseDelay before the first retry and twice as long before each further one
+// Retries happen within the lookup timeout; attempts of 1 or less disable them.
+func (c *Checker) SetRetries(attempts int, baseDelay time.Duration) {
+	c.retry = retryPolicy{attempts: attempts, baseDelay: baseDelay}
+	c.ociChecker.retry = c.retry
+}
+
+// SetCircuitBreaker makes lookups in a repository fail fast with ErrCircuitOpen after threshold
+// consecutive failures to reach it, until ResetCircuits
+// A threshold of 0 disables the circuit breaker.
+func (c *Checker) SetCircuitBreaker(threshold int) {
+	c.circuits = nil
+	if threshold > 0 {
+		c.circuits = newCircuitBreaker(threshold, c.logger)
+	}
+}
+
+// ResetCircuits gives every repository another chance, e.g. at the start of a scan
+func (c *Checker) ResetCircuits() {
+	c.circuits.reset()
+}
+
+// SetIndexCache reuses each Helm repository's index for ttl across the applications using it,
+// revalidating it with ETag/Last-Modified afterwards; with a directory, indexes are also kept on disk
+// for later runs. A zero ttl disables the cache.
+func (c *Checker) SetIndexCache(ttl time.Duration, dir string) error {
+	c.indexCache = nil
+	if ttl <= 0 {
+		return nil
+	}
+	cache, err := newIndexCache(ttl, dir, c.logger)
+	if err != nil {
+		return err
+	}
+	c.indexCache = cache
+	return nil
+}
+
+// SetIndexCacheLimits bounds the cache directory of SetIndexCache: indexes not refreshed for maxAge
+// are removed, then the least recently refreshed ones until it holds at most maxSize bytes
+// The limits apply right away and after each index written; 0 disables a limit.
+func (c *Checker) SetIndexCacheLimits(maxAge time.Duration, maxSize int64) {
+	if c.indexCache == nil {
+		return
+	}
+	c.indexCache.maxAge = maxAge
+	c.indexCache.maxSize = maxSize
+	c.indexCache.prune()
+}
+
+// ReleaseClones removes the Git clones shared by the lookups so far, e.g. at the end of a scan
+// It must not run concurrently with lookups.
+func (c *Checker) ReleaseClones() {
+	c.gitClient.clones.release()
+}
+
+// withLookupTimeout bounds a chart lookup by the repository's own timeout, or the timeout of its type
+// The returned function cancels the deadline and annotates errors caused by it.
+func (c *Checker) withLookupTimeout(ctx context.Context, repoURL string) (context.Context, func(error) error) {
+	kind, timeout := "Helm repository", c.helmTimeout
+	switch {
+	case isGitURL(repoURL):
+		kind, timeout = "Git repository", c.gitTimeout
+	case isOCIRepository(repoURL):
+		kind, timeout = "OCI registry", c.ociTimeout
+	}
+	if override, ok := c.repositoryOverride(repoURL); ok && override.Timeout > 0 {
+		timeout = override.Timeout
+	}
+	if timeout <= 0 {
+		return ctx, func(err error) error { return err }
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, timeout)
+	return lookupCtx, func(err error) error {
+		defer cancel()
+		// Only report the lookup's own deadline, not an expired parent context
+		if err != nil && errors.Is(lookupCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return fmt.Errorf("%s lookup timed out after %s: %w", kind, timeout, err)
+		}
+		return err
+	}
+}
+
 // GetLatestVersion gets the latest version of a Helm chart from a repository
 func (c *Checker) GetLatestVersion(ctx context.Context, repoURL, chartName string) (string, error) {
+	// Resolve Helm repository aliases (e.g. "@bitnami") from the local Helm configuration
+	repoURL = c.authProvider.ResolveRepoURL(repoURL)
+
+	if err := c.allow(repoURL); err != nil {
+		return "", err
+	}
+
+	ctx, done := c.withLookupTimeout(ctx, repoURL)
+	version, err := c.getLatestVersion(ctx, repoURL, chartName)
+	err = done(err)
+	c.circuits.record(repoURL, err)
+	return version, err
+}
+
+// getLatestVersion dispatches the lookup to the Git, OCI or Helm repository checker
+func (c *Checker) getLatestVersion(ctx context.Context, repoURL, chartName string) (string, error) {
 	// Check if this is a Git repository
 	if isGitURL(repoURL) {
 		c.logger.WithFields(logrus.Fields{
@@ -45,12 +182,6 @@ func (c *Checker) GetLatestVersion(ctx context.Context, repoURL, chartName strin
 			"chart": chartName,
 		}).Info("Detected Git repository, using Git checker")
 
-		// Get auth credentials for this repo if available
-		if auth := c.authProvider.GetCredentials(repoURL); auth != nil {
-			c.gitClient.username = auth.Username
-			c.gitClient.password = auth.Password
-		}
-
 		// Use chartName as the path within the repo
 		return c.gitClient.GetLatestVersion(ctx, repoURL, chartName)
 	}
@@ -68,6 +199,152 @@ func (c *Checker) GetLatestVersion(ctx context.Context, repoURL, chartName strin
 
 // GetLatestVersionWithConstraint gets the latest version respecting the version constraint
 func (c *Checker) GetLatestVersionWithConstraint(ctx context.Context, repoURL, chartName, currentVersion, constraint string) (*VersionConstraintResult, error) {
+	// Resolve Helm repository aliases (e.g. "@bitnami") from the local Helm configuration
+	repoURL = c.authProvider.ResolveRepoURL(repoURL)
+
+	if err := c.allow(repoURL); err != nil {
+		return nil, err
+	}
+
+	ctx, done := c.withLookupTimeout(ctx, repoURL)
+	result, err := c.getLatestVersionWithPins(ctx, repoURL, chartName, currentVersion, constraint)
+	err = done(err)
+	c.circuits.record(repoURL, err)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getLatestVersionWithPins checks relocations, branches, pinned revisions and mutable tags before
+// the constrained lookup
+func (c *Checker) getLatestVersionWithPins(ctx context.Context, repoURL, chartName, currentVersion, constraint string) (*VersionConstraintResult, error) {
+
+	// Check the relocation knowledge base first: archived repositories either fail
+	// outright or keep serving stale versions that look "up to date"
+	if migration := lookupKnownMigration(repoURL, chartName); migration != nil {
+		c.logger.WithFields(logrus.Fields{
+			"repo":         repoURL,
+			"chart":        chartName,
+			"new_repo_url": migration.NewRepoURL,
+			"new_chart":    migration.NewChart,
+		}).Info("Chart repository has been relocated")
+		return &VersionConstraintResult{
+			LatestVersion:    currentVersion,
+			LatestVersionAll: currentVersion,
+			Migration:        migration,
+		}, nil
+	}
+
+	// Branch-tracking applications always deploy the branch tip, so there is nothing to update
+	if isGitURL(repoURL) && isBranchRevision(currentVersion, chartName) {
+		return c.getBranchVersion(ctx, repoURL, chartName, currentVersion)
+	}
+
+	// Resolve digests and commit SHAs to the version they point at, then compare as usual
+	pinnedVersion, pinnedBy, err := c.resolvePinnedRevision(ctx, repoURL, chartName, currentVersion)
+	if err != nil {
+		return nil, err
+	}
+	if pinnedBy == "" {
+		if isOCIRepository(repoURL) && isMutableTag(currentVersion) {
+			return c.getLatestVersionForMutableTag(ctx, repoURL, chartName, currentVersion, constraint)
+		}
+		return c.getLatestVersionWithConstraint(ctx, repoURL, chartName, currentVersion, constraint)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"repo":      repoURL,
+		"chart":     chartName,
+		"revision":  currentVersion,
+		"version":   pinnedVersion,
+		"pinned_by": pinnedBy,
+	}).Debug("Resolved pinned revision")
+
+	result, err := c.getLatestVersionWithConstraint(ctx, repoURL, chartName, pinnedVersion, constraint)
+	if err != nil {
+		return nil, err
+	}
+	result.CurrentVersion = pinnedVersion
+	result.PinnedBy = pinnedBy
+	return result, nil
+}
+
+// GetChartVersionAtCommit returns the chart version of a Git repository as of a commit, e.g. the commit
+// a branch-tracking application last synced
+func (c *Checker) GetChartVersionAtCommit(ctx context.Context, repoURL, chartName, sha string) (string, error) {
+	if err := c.allow(repoURL); err != nil {
+		return "", err
+	}
+
+	ctx, done := c.withLookupTimeout(ctx, repoURL)
+	version, err := c.gitClient.ResolveCommit(ctx, repoURL, chartName, sha)
+	err = done(err)
+	c.circuits.record(repoURL, err)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit %s: %w", sha, err)
+	}
+	return version, nil
+}
+
+// getBranchVersion reports the Chart.yaml version at the tip of a tracked branch
+func (c *Checker) getBranchVersion(ctx context.Context, repoURL, chartName, branch string) (*VersionConstraintResult, error) {
+	if branch == "" {
+		branch = "HEAD"
+	}
+
+	version, err := c.gitClient.GetChartVersionAtBranch(ctx, repoURL, chartName, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart version on branch %s: %w", branch, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"repo":    repoURL,
+		"chart":   chartName,
+		"branch":  branch,
+		"version": version,
+	}).Info("Application tracks a branch")
+
+	return &VersionConstraintResult{
+		LatestVersion:    version,
+		LatestVersionAll: version,
+		CurrentVersion:   version,
+		TrackingBranch:   branch,
+	}, nil
+}
+
+// getLatestVersionForMutableTag resolves a mutable tag (e.g. "latest") to the version it currently
+// points at and compares that version against the repository
+func (c *Checker) getLatestVersionForMutableTag(ctx context.Context, repoURL, chartName, tag, constraint string) (*VersionConstraintResult, error) {
+	version, digest, err := c.ociChecker.ResolveTag(ctx, repoURL, chartName, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"repo":    repoURL,
+		"chart":   chartName,
+		"tag":     tag,
+		"digest":  digest,
+		"version": version,
+	}).Info("Resolved mutable tag to a concrete version")
+
+	result, err := c.getLatestVersionWithConstraint(ctx, repoURL, chartName, version, constraint)
+	if err != nil {
+		return nil, err
+	}
+	result.CurrentVersion = version
+	result.MutableTag = tag
+	return result, nil
+}
+
+// isOCIRepository reports whether a repository URL refers to an OCI registry
+func isOCIRepository(repoURL string) bool {
+	return !isGitURL(repoURL) && !strings.HasPrefix(repoURL, "http://") && !strings.HasPrefix(repoURL, "https://")
+}
+
+// getLatestVersionWithConstraint dispatches the constrained lookup to the Git, OCI or Helm repository checker
+func (c *Checker) getLatestVersionWithConstraint(ctx context.Context, repoURL, chartName, currentVersion, constraint string) (*VersionConstraintResult, error) {
 	// Check if this is a Git repository
 	if isGitURL(repoURL) {
 		c.logger.WithFields(logrus.Fields{
@@ -76,12 +353,6 @@ func (c *Checker) GetLatestVersionWithConstraint(ctx context.Context, repoURL, c
 			"constraint": constraint,
 		}).Info("Detected Git repository, using Git checker with constraint")
 
-		// Get auth credentials for this repo if available
-		if auth := c.authProvider.GetCredentials(repoURL); auth != nil {
-			c.gitClient.username = auth.Username
-			c.gitClient.password = auth.Password
-		}
-
 		// Get all versions from Git tags
 		versions, err := c.gitClient.GetAllVersions(ctx, repoURL, chartName)
 		if err != nil {
@@ -107,13 +378,132 @@ func (c *Checker) GetLatestVersionWithConstraint(ctx context.Context, repoURL, c
 
 // getChartVersionsFromRepo fetches and returns all available versions for a chart from a Helm repository
 func (c *Checker) getChartVersionsFromRepo(ctx context.Context, repoURL, chartName string) ([]string, error) {
+	entries, err := c.getChartEntriesFromRepo(ctx, repoURL, chartName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract versions
+	versions := make([]string, len(entries))
+	for i, entry := range entries {
+		versions[i] = entry.Version
+	}
+
+	return versions, nil
+}
+
+// getChartEntriesFromRepo fetches and returns all index entries for a chart from a Helm repository
+func (c *Checker) getChartEntriesFromRepo(ctx context.Context, repoURL, chartName string) ([]Entry, error) {
+	index, err := c.getIndex(ctx, repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the chart
+	chart, exists := index.Entries[chartName]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrChartNotFound, chartName)
+	}
+
+	if len(chart) == 0 {
+		return nil, fmt.Errorf("%w: %s (no versions available)", ErrChartNotFound, chartName)
+	}
+
+	// Drop versions configured as non-release tags
+	filter := c.tagExclusions.For(repoURL)
+	var entries []Entry
+	for _, entry := range chart {
+		if !filter.Excludes(entry.Version) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: all versions were filtered out", ErrNoValidVersions)
+	}
+
+	return entries, nil
+}
+
+// getIndex returns a repository's parsed index; concurrent lookups of a repository share one download
+// The index is shared by its callers and must not be modified.
+func (c *Checker) getIndex(ctx context.Context, repoURL string) (*Index, error) {
+	return c.indexFlights.do(ctx, repoURL, func(ctx context.Context) (*Index, error) {
+		return c.loadIndex(ctx, repoURL)
+	})
+}
+
+// loadIndex downloads and parses a repository's index, through the index cache when it's enabled
+func (c *Checker) loadIndex(ctx context.Context, repoURL string) (*Index, error) {
+	if c.indexCache == nil {
+		var resp *indexResponse
+		err := c.retry.do(ctx, c.logger.WithField("repo", repoURL), func() (err error) {
+			resp, err = c.downloadIndex(ctx, repoURL, "", "")
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return decodeIndex(resp.body)
+	}
+
+	entry := c.indexCache.entry(repoURL)
+	defer entry.mu.Unlock()
+
+	now := time.Now()
+	if c.indexCache.fresh(entry, now) {
+		c.logger.WithField("repo", repoURL).Debug("Using cached Helm repository index")
+		return entry.index, nil
+	}
+
+	// A cached index is revalidated instead of downloaded again
+	var etag, lastModified string
+	if entry.index != nil {
+		etag, lastModified = entry.etag, entry.lastModified
+	}
+	var resp *indexResponse
+	err := c.retry.do(ctx, c.logger.WithField("repo", repoURL), func() (err error) {
+		resp, err = c.downloadIndex(ctx, repoURL, etag, lastModified)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.notModified {
+		c.logger.WithField("repo", repoURL).Debug("Cached Helm repository index is still current")
+		entry.fetched = now
+		c.indexCache.store(repoURL, entry, nil)
+		return entry.index, nil
+	}
+
+	index, err := decodeIndex(resp.body)
+	if err != nil {
+		return nil, err
+	}
+	entry.index = index
+	entry.etag = resp.etag
+	entry.lastModified = resp.lastModified
+	entry.fetched = now
+	c.indexCache.store(repoURL, entry, resp.body)
+	return index, nil
+}
+
+// indexResponse is a downloaded index.yaml, or notice that the cached copy is still current
+type indexResponse struct {
+	body         []byte
+	etag         string
+	lastModified string
+	notModified  bool
+}
+
+// downloadIndex downloads a repository's index.yaml
+// With validators of a cached copy, the request is conditional and may report it as not modified.
+func (c *Checker) downloadIndex(ctx context.Context, repoURL, etag, lastModified string) (*indexResponse, error) {
 	// Construct the index URL
 	indexURL := fmt.Sprintf("%s/index.yaml", repoURL)
 
 	c.logger.WithFields(logrus.Fields{
-		"repo":  repoURL,
-		"chart": chartName,
-		"url":   indexURL,
+		"repo": repoURL,
+		"url":  indexURL,
 	}).Debug("Fetching Helm repository index")
 
 	// Create request with context
@@ -125,6 +515,12 @@ func (c *Checker) getChartVersionsFromRepo(ctx context.Context, repoURL, chartNa
 	// Set headers
 	req.Header.Set("User-Agent", "argazer/1.0")
 	req.Header.Set("Accept", "application/x-yaml, application/yaml, text/yaml")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
 
 	// Add authentication if available
 	if creds := c.authProvider.GetCredentials(repoURL); creds != nil {
@@ -143,43 +539,33 @@ func (c *Checker) getChartVersionsFromRepo(ctx context.Context, repoURL, chartNa
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("repository does not provide index.yaml (status %d) - likely an OCI/container registry", resp.StatusCode)
+	switch {
+	case resp.StatusCode == http.StatusNotModified && (etag != "" || lastModified != ""):
+		return &indexResponse{notModified: true}, nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%w for %s (status %d): check credentials", ErrAuthenticationFailed, repoURL, resp.StatusCode)
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: repository returned status %d", ErrRepositoryUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: no index.yaml (status %d) - likely an OCI/container registry", ErrInvalidRepository, resp.StatusCode)
 	}
 
 	// Check content type - if it's HTML, this is likely not a Helm repo
 	contentType := resp.Header.Get("Content-Type")
 	if strings.Contains(contentType, "text/html") {
-		return nil, fmt.Errorf("repository returned HTML instead of YAML - likely an OCI/container registry, not a traditional Helm repository")
+		return nil, fmt.Errorf("%w: returned HTML instead of YAML - likely an OCI/container registry", ErrInvalidRepository)
 	}
 
-	// Parse the index
-	index, err := c.parseIndex(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		// Check if error is due to HTML response (common for OCI repos)
-		if strings.Contains(err.Error(), "<!DOCTY") || strings.Contains(err.Error(), "<html") {
-			return nil, fmt.Errorf("repository is an OCI/container registry, not a traditional Helm repository")
-		}
-		return nil, fmt.Errorf("failed to parse index: %w", err)
-	}
-
-	// Find the chart
-	chart, exists := index.Entries[chartName]
-	if !exists {
-		return nil, fmt.Errorf("%w: %s", ErrChartNotFound, chartName)
+		return nil, fmt.Errorf("%w: failed to parse index: failed to read response body: %w", ErrInvalidRepository, err)
 	}
 
-	if len(chart) == 0 {
-		return nil, fmt.Errorf("%w: %s (no versions available)", ErrChartNotFound, chartName)
-	}
-
-	// Extract versions
-	versions := make([]string, len(chart))
-	for i, entry := range chart {
-		versions[i] = entry.Version
-	}
-
-	return versions, nil
+	return &indexResponse{
+		body:         body,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
 }
 
 func (c *Checker) getLatestVersionFromRepo(ctx context.Context, repoURL, chartName string) (string, error) {
@@ -212,18 +598,30 @@ func (c *Checker) getLatestVersionFromRepoWithConstraint(ctx context.Context, re
 		"constraint": constraint,
 	}).Debug("Fetching Helm repository index with constraint")
 
-	// Fetch all versions using shared helper
-	versions, err := c.getChartVersionsFromRepo(ctx, repoURL, chartName)
+	// Fetch all entries using shared helper
+	entries, err := c.getChartEntriesFromRepo(ctx, repoURL, chartName)
 	if err != nil {
 		return nil, err
 	}
 
+	versions := make([]string, len(entries))
+	for i, entry := range entries {
+		versions[i] = entry.Version
+	}
+
 	// Apply constraint filtering
 	result, err := findLatestSemverWithConstraint(versions, currentVersion, constraint, c.logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine latest version: %w", err)
 	}
 
+	// Flag charts that upstream has deprecated so they aren't reported as simply "up to date"
+	result.Migration = detectDeprecatedEntry(repoURL, entries)
+	result.SecurityUpdate = containsSecurityUpdates(entries, currentVersion, result.LatestVersion)
+	result.LatestReleased = releaseDate(entries, result.LatestVersion)
+	result.Changes, result.ChangelogURL = artifactHubChanges(entries, currentVersion, result.LatestVersion)
+	result.Sources = chartSources(entries, result.LatestVersion)
+
 	c.logger.WithFields(logrus.Fields{
 		"repo":                          repoURL,
 		"chart":                         chartName,
@@ -232,23 +630,45 @@ func (c *Checker) getLatestVersionFromRepoWithConstraint(ctx context.Context, re
 		"latest_version_all":            result.LatestVersionAll,
 		"constraint":                    constraint,
 		"has_update_outside_constraint": result.HasUpdateOutsideConstraint,
+		"security_update":               result.SecurityUpdate,
 	}).Debug("Found latest version with constraint")
 
 	return result, nil
 }
 
-// parseIndex parses the Helm repository index YAML
-func (c *Checker) parseIndex(body io.Reader) (*Index, error) {
-	// Read the body
-	data, err := io.ReadAll(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// releaseDate returns the creation time of a version's index entry, or the zero time if it isn't listed
+func releaseDate(entries []Entry, version string) time.Time {
+	for _, entry := range entries {
+		if entry.Version == version {
+			return entry.Created
+		}
 	}
+	return time.Time{}
+}
 
-	// Parse YAML
+// chartSources returns the source and home URLs of a version's index entry
+func chartSources(entries []Entry, version string) []string {
+	for _, entry := range entries {
+		if entry.Version == version {
+			sources := append([]string{}, entry.Sources...)
+			if entry.Home != "" {
+				sources = append(sources, entry.Home)
+			}
+			return sources
+		}
+	}
+	return nil
+}
+
+// decodeIndex parses the Helm repository index YAML
+func decodeIndex(data []byte) (*Index, error) {
 	var index Index
 	if err := yaml.Unmarshal(data, &index); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+		// Check if error is due to HTML response (common for OCI repos)
+		if strings.Contains(err.Error(), "<!DOCTY") || strings.Contains(err.Error(), "<html") {
+			return nil, fmt.Errorf("%w: repository is an OCI/container registry", ErrInvalidRepository)
+		}
+		return nil, fmt.Errorf("%w: failed to parse index: failed to unmarshal YAML: %w", ErrInvalidRepository, err)
 	}
 
 	return &index, nil
@@ -263,10 +683,14 @@ type Index struct {
 
 // Entry represents a chart entry in the index
 type Entry struct {
-	Name        string    `yaml:"name"`
-	Version     string    `yaml:"version"`
-	Description string    `yaml:"description"`
-	Created     time.Time `yaml:"created"`
-	Digest      string    `yaml:"digest"`
-	URLs        []string  `yaml:"urls"`
+	Name        string            `yaml:"name"`
+	Version     string            `yaml:"version"`
+	Description string            `yaml:"description"`
+	Home        string            `yaml:"home"`
+	Sources     []string          `yaml:"sources"`
+	Created     time.Time         `yaml:"created"`
+	Digest      string            `yaml:"digest"`
+	URLs        []string          `yaml:"urls"`
+	Deprecated  bool              `yaml:"deprecated"`
+	Annotations map[string]string `yaml:"annotations"`
 }
@@ -1,7 +1,12 @@
 package helm
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"argazer/internal/auth"
 
@@ -166,6 +171,43 @@ func TestFindLatestSemverWithConstraint(t *testing.T) {
 			expectedOutsideConstraint: true,
 			hasError:                  false,
 		},
+		{
+			name:                      "range constraint",
+			versions:                  []string{"1.0.0", "1.5.0", "2.0.0", "2.1.0"},
+			currentVersion:            "1.2.0",
+			constraint:                ">=1.2.0 <2.0.0",
+			expectedLatest:            "1.5.0",
+			expectedLatestAll:         "2.1.0",
+			expectedOutsideConstraint: true,
+			hasError:                  false,
+		},
+		{
+			name:                      "tilde range constraint",
+			versions:                  []string{"1.4.0", "1.4.3", "1.5.0"},
+			currentVersion:            "1.4.1",
+			constraint:                "~1.4.x",
+			expectedLatest:            "1.4.3",
+			expectedLatestAll:         "1.5.0",
+			expectedOutsideConstraint: true,
+			hasError:                  false,
+		},
+		{
+			name:                      "range constraint - current version outside range",
+			versions:                  []string{"1.0.0", "1.5.0", "2.0.0"},
+			currentVersion:            "2.0.0",
+			constraint:                "<2.0.0",
+			expectedLatest:            "2.0.0",
+			expectedLatestAll:         "2.0.0",
+			expectedOutsideConstraint: false,
+			hasError:                  false,
+		},
+		{
+			name:           "invalid range constraint",
+			versions:       []string{"1.0.0", "2.0.0"},
+			currentVersion: "1.0.0",
+			constraint:     "newer than 1.0",
+			hasError:       true,
+		},
 		{
 			name:           "empty versions list",
 			versions:       []string{},
@@ -212,3 +254,77 @@ func TestFindLatestSemverWithConstraint(t *testing.T) {
 		})
 	}
 }
+
+func TestFindLatestSemverWithConstraint_VersionsBehind(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	versions := []string{"1.0.0", "1.2.0", "1.2.1", "1.3.0", "2.0.0", "invalid"}
+
+	tests := []struct {
+		name       string
+		current    string
+		constraint string
+		expected   int
+	}{
+		{name: "all newer versions", current: "1.2.0", constraint: "major", expected: 3},
+		{name: "within minor constraint", current: "1.2.0", constraint: "minor", expected: 2},
+		{name: "within patch constraint", current: "1.2.0", constraint: "patch", expected: 1},
+		{name: "up to date", current: "2.0.0", constraint: "major", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := findLatestSemverWithConstraint(versions, tt.current, tt.constraint, logger)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.VersionsBehind != tt.expected {
+				t.Errorf("Expected %d versions behind, got %d", tt.expected, result.VersionsBehind)
+			}
+		})
+	}
+}
+
+func TestReleaseDate(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{{Version: "1.1.0", Created: created}, {Version: "1.0.0"}}
+
+	if got := releaseDate(entries, "1.1.0"); !got.Equal(created) {
+		t.Errorf("Expected %v, got %v", created, got)
+	}
+	if got := releaseDate(entries, "2.0.0"); !got.IsZero() {
+		t.Errorf("Expected zero time for unknown version, got %v", got)
+	}
+}
+
+func TestCheckerSetTimeouts(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hang until the test ends, like a repository stuck mid-response
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, err := NewChecker(authProvider, logger)
+	if err != nil {
+		t.Fatalf("Failed to create checker: %v", err)
+	}
+	checker.SetTimeouts(50*time.Millisecond, 0, 0)
+
+	start := time.Now()
+	_, err = checker.GetLatestVersionWithConstraint(context.Background(), server.URL, "nginx", "1.0.0", "major")
+	if err == nil {
+		t.Fatal("Expected a timeout error")
+	}
+	if !strings.Contains(err.Error(), "Helm repository lookup timed out after 50ms") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Lookup took %s, expected it to stop at the deadline", elapsed)
+	}
+}
@@ -0,0 +1,101 @@
+package helm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// circuitBreaker fast-fails lookups in repositories that failed too many times in a row, so a
+// repository that is down costs one timeout per concurrent worker instead of one per application
+// Only failures to reach the repository count; any other outcome shows it is up and closes the circuit.
+type circuitBreaker struct {
+	threshold int
+	logger    *logrus.Entry
+
+	mu    sync.Mutex
+	repos map[string]*circuitState // By repository URL without scheme and trailing slash
+}
+
+// circuitState tracks the consecutive failures of one repository
+type circuitState struct {
+	failures int
+	lastErr  error
+}
+
+// newCircuitBreaker creates a breaker opening after threshold consecutive failures
+func newCircuitBreaker(threshold int, logger *logrus.Entry) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		logger:    logger,
+		repos:     make(map[string]*circuitState),
+	}
+}
+
+// allow returns an ErrCircuitOpen error if lookups in the repository should fail fast
+// A nil breaker allows every lookup.
+func (b *circuitBreaker) allow(repoURL string) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state, ok := b.repos[circuitKey(repoURL)]
+	if !ok || state.failures < b.threshold {
+		return nil
+	}
+	return fmt.Errorf("%w after %d consecutive failures (last: %v)", ErrCircuitOpen, state.failures, state.lastErr)
+}
+
+// record updates the repository's circuit with the outcome of a lookup
+func (b *circuitBreaker) record(repoURL string, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := circuitKey(repoURL)
+	switch ErrorCode(err) {
+	case ErrorCodeRepoUnreachable, ErrorCodeTimeout:
+	default:
+		delete(b.repos, key)
+		return
+	}
+
+	state, ok := b.repos[key]
+	if !ok {
+		state = &circuitState{}
+		b.repos[key] = state
+	}
+	state.failures++
+	state.lastErr = err
+	if state.failures == b.threshold {
+		b.logger.WithError(err).WithFields(logrus.Fields{
+			"repo":     repoURL,
+			"failures": state.failures,
+		}).Warn("Repository keeps failing, skipping its remaining applications")
+	}
+}
+
+// reset closes all circuits
+func (b *circuitBreaker) reset() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	clear(b.repos)
+}
+
+// circuitKey identifies a repository regardless of scheme and trailing slashes
+func circuitKey(repoURL string) string {
+	if _, rest, ok := strings.Cut(repoURL, "://"); ok {
+		repoURL = rest
+	}
+	return strings.TrimRight(repoURL, "/")
+}
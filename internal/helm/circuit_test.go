@@ -0,0 +1,89 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	breaker := newCircuitBreaker(2, logrus.NewEntry(logrus.New()))
+	unreachable := fmt.Errorf("%w: repository returned status 503", ErrRepositoryUnavailable)
+
+	breaker.record("https://charts.example.com", unreachable)
+	if err := breaker.allow("https://charts.example.com"); err != nil {
+		t.Fatalf("circuit opened after one failure: %v", err)
+	}
+
+	breaker.record("https://charts.example.com/", unreachable)
+	err := breaker.allow("https://charts.example.com")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() = %v, want ErrCircuitOpen", err)
+	}
+	if ErrorCode(err) != ErrorCodeCircuitOpen {
+		t.Errorf("ErrorCode() = %s, want %s", ErrorCode(err), ErrorCodeCircuitOpen)
+	}
+	if err := breaker.allow("https://other.example.com"); err != nil {
+		t.Errorf("circuit of another repository is open: %v", err)
+	}
+
+	// A missing chart shows the repository is reachable
+	breaker.record("https://charts.example.com", fmt.Errorf("%w: nginx", ErrChartNotFound))
+	if err := breaker.allow("https://charts.example.com"); err != nil {
+		t.Errorf("circuit still open after the repository answered: %v", err)
+	}
+
+	breaker.record("https://charts.example.com", unreachable)
+	breaker.record("https://charts.example.com", unreachable)
+	breaker.reset()
+	if err := breaker.allow("https://charts.example.com"); err != nil {
+		t.Errorf("circuit still open after reset: %v", err)
+	}
+
+	var disabled *circuitBreaker
+	disabled.record("https://charts.example.com", unreachable)
+	if err := disabled.allow("https://charts.example.com"); err != nil {
+		t.Errorf("disabled breaker failed a lookup: %v", err)
+	}
+}
+
+func TestCheckerCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, _ := NewChecker(authProvider, logger)
+	checker.SetCircuitBreaker(2)
+
+	var errs []error
+	for _, chart := range []string{"nginx", "redis", "postgresql", "mysql"} {
+		_, err := checker.GetLatestVersionWithConstraint(context.Background(), server.URL, chart, "1.0.0", "major")
+		errs = append(errs, err)
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("repository received %d requests, want 2", got)
+	}
+	if ErrorCode(errs[1]) != ErrorCodeRepoUnreachable || ErrorCode(errs[3]) != ErrorCodeCircuitOpen {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	checker.ResetCircuits()
+	_, _ = checker.GetLatestVersionWithConstraint(context.Background(), server.URL, "nginx", "1.0.0", "major")
+	if got := requests.Load(); got != 3 {
+		t.Errorf("repository received %d requests after reset, want 3", got)
+	}
+}
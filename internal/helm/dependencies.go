@@ -0,0 +1,173 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// ChartDependency is a dependency (subchart) declared in a chart's Chart.yaml
+type ChartDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`    // Version locked in Chart.lock, or the version or range declared in Chart.yaml
+	Repository string `yaml:"repository"` // Repository URL, "oci://" registry, "@name"/"alias:name" Helm repository or "file://" path
+}
+
+// IsLocal reports whether the dependency is vendored in the chart's repository rather than published
+func (d ChartDependency) IsLocal() bool {
+	return d.Repository == "" || strings.HasPrefix(d.Repository, "file://")
+}
+
+// chartLock is the structure of Chart.lock, recording the versions dependency ranges resolved to
+type chartLock struct {
+	Dependencies []ChartDependency `yaml:"dependencies"`
+}
+
+// IsGitRepository reports whether a repository URL refers to a Git repository
+func IsGitRepository(repoURL string) bool {
+	return isGitURL(repoURL)
+}
+
+// GetChartDependencies reads the dependencies of a chart in a Git repository at a revision: a tag,
+// branch or commit SHA, or the default branch when empty
+// Dependencies locked in Chart.lock are returned with their locked version.
+func (c *Checker) GetChartDependencies(ctx context.Context, repoURL, chartPath, revision string) ([]ChartDependency, error) {
+	if !isGitURL(repoURL) {
+		return nil, fmt.Errorf("dependencies are only read from Git repositories, not %s", repoURL)
+	}
+
+	if err := c.allow(repoURL); err != nil {
+		return nil, err
+	}
+	ctx, done := c.withLookupTimeout(ctx, repoURL)
+	dependencies, err := c.gitClient.GetChartDependencies(ctx, repoURL, chartPath, revision)
+	err = done(err)
+	c.circuits.record(repoURL, err)
+	return dependencies, err
+}
+
+// GetLatestDependencyVersion gets the latest version of a chart dependency from its repository
+// A dependency locked to a version is compared within the constraint; one declared as a range (without
+// Chart.lock) gets the latest version overall, as the range already decides which versions it takes.
+func (c *Checker) GetLatestDependencyVersion(ctx context.Context, dependency ChartDependency, constraint string) (*VersionConstraintResult, error) {
+	if dependency.IsLocal() {
+		return nil, fmt.Errorf("dependency %s is a local chart", dependency.Name)
+	}
+	repoURL := strings.TrimPrefix(dependency.Repository, "oci://")
+
+	if _, err := semver.StrictNewVersion(strings.TrimPrefix(dependency.Version, "v")); err == nil {
+		return c.GetLatestVersionWithConstraint(ctx, repoURL, dependency.Name, dependency.Version, constraint)
+	}
+
+	latest, err := c.GetLatestVersion(ctx, repoURL, dependency.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionConstraintResult{LatestVersion: latest, LatestVersionAll: latest}, nil
+}
+
+// GetChartDependencies reads the dependencies from Chart.yaml and Chart.lock at a revision
+// Any revision may be anywhere in history, so this uses the full clone shared with ResolveCommit.
+func (g *GitClient) GetChartDependencies(ctx context.Context, repoURL, chartPath, revision string) ([]ChartDependency, error) {
+	if revision == "" {
+		revision = "HEAD"
+	}
+	g.logger.WithFields(logrus.Fields{
+		"repo":       repoURL,
+		"chart_path": chartPath,
+		"revision":   revision,
+	}).Debug("Reading chart dependencies")
+
+	cloneOpts := &git.CloneOptions{
+		URL:      repoURL,
+		Progress: nil,
+		Tags:     git.AllTags,
+	}
+
+	var chartData, lockData string
+	err := g.withClone(ctx, repoURL+"#full", cloneOpts, func(_ string, repo *git.Repository) error {
+		commit, err := revisionCommit(repo, revision)
+		if err != nil {
+			return err
+		}
+
+		chartData, err = commitFile(commit, filepath.Join(chartPath, "Chart.yaml"))
+		if err != nil {
+			return fmt.Errorf("failed to read Chart.yaml at %s: %w", revision, err)
+		}
+		lockData, err = commitFile(commit, filepath.Join(chartPath, "Chart.lock"))
+		if err != nil && !errors.Is(err, object.ErrFileNotFound) {
+			return fmt.Errorf("failed to read Chart.lock at %s: %w", revision, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var chart ChartMetadata
+	if err := yaml.Unmarshal([]byte(chartData), &chart); err != nil {
+		return nil, fmt.Errorf("failed to parse Chart.yaml: %w", err)
+	}
+	var lock chartLock
+	if err := yaml.Unmarshal([]byte(lockData), &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse Chart.lock: %w", err)
+	}
+
+	locked := make(map[string]string, len(lock.Dependencies))
+	for _, dependency := range lock.Dependencies {
+		locked[dependency.Name+"@"+dependency.Repository] = dependency.Version
+	}
+	dependencies := make([]ChartDependency, 0, len(chart.Dependencies))
+	for _, dependency := range chart.Dependencies {
+		if version, ok := locked[dependency.Name+"@"+dependency.Repository]; ok {
+			dependency.Version = version
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"repo":         repoURL,
+		"chart_path":   chartPath,
+		"dependencies": len(dependencies),
+	}).Debug("Found chart dependencies")
+
+	return dependencies, nil
+}
+
+// revisionCommit resolves a tag, branch, commit SHA or HEAD to its commit in a clone, where
+// branches other than the default one only exist as remote-tracking branches
+func revisionCommit(repo *git.Repository, revision string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		var remoteErr error
+		hash, remoteErr = repo.ResolveRevision(plumbing.Revision("origin/" + revision))
+		if remoteErr != nil {
+			return nil, fmt.Errorf("revision %s not found: %w", revision, err)
+		}
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit: %w", err)
+	}
+	return commit, nil
+}
+
+// commitFile returns the contents of a file as of a commit
+func commitFile(commit *object.Commit, path string) (string, error) {
+	file, err := commit.File(filepath.ToSlash(path))
+	if err != nil {
+		return "", err
+	}
+	return file.Contents()
+}
@@ -0,0 +1,86 @@
+package helm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitClient_GetChartDependencies(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+
+	chartDir := filepath.Join(dir, "charts", "app")
+	require.NoError(t, os.MkdirAll(chartDir, 0o755))
+	commit := func(chart, lock, tag string) {
+		require.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(chart), 0o644))
+		if lock != "" {
+			require.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.lock"), []byte(lock), 0o644))
+		}
+		_, err := worktree.Add("charts")
+		require.NoError(t, err)
+		hash, err := worktree.Commit("Release "+tag, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		_, err = repo.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+
+	commit(`apiVersion: v2
+name: app
+version: 1.0.0
+dependencies:
+  - name: postgresql
+    version: 12.1.6
+    repository: https://charts.bitnami.com/bitnami
+`, "", "v1.0.0")
+	commit(`apiVersion: v2
+name: app
+version: 1.1.0
+dependencies:
+  - name: postgresql
+    version: ~12.5.0
+    repository: oci://registry-1.docker.io/bitnamicharts
+  - name: redis
+    version: 17.x.x
+    repository: "@bitnami"
+  - name: common
+    version: 1.0.0
+    repository: file://../common
+`, `dependencies:
+  - name: postgresql
+    version: 12.5.8
+    repository: oci://registry-1.docker.io/bitnamicharts
+`, "v1.1.0")
+
+	client := NewGitClient("", "", logrus.NewEntry(logrus.New()))
+	ctx := context.Background()
+
+	dependencies, err := client.GetChartDependencies(ctx, dir, "charts/app", "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []ChartDependency{{Name: "postgresql", Version: "12.1.6", Repository: "https://charts.bitnami.com/bitnami"}}, dependencies)
+
+	dependencies, err = client.GetChartDependencies(ctx, dir, "charts/app", "")
+	require.NoError(t, err)
+	assert.Equal(t, []ChartDependency{
+		{Name: "postgresql", Version: "12.5.8", Repository: "oci://registry-1.docker.io/bitnamicharts"},
+		{Name: "redis", Version: "17.x.x", Repository: "@bitnami"},
+		{Name: "common", Version: "1.0.0", Repository: "file://../common"},
+	}, dependencies, "locked versions replace the declared ranges")
+	assert.True(t, dependencies[2].IsLocal())
+
+	_, err = client.GetChartDependencies(ctx, dir, "charts/app", "v9.9.9")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "revision v9.9.9 not found")
+}
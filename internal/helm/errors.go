@@ -1,6 +1,13 @@
 package helm
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
 
 // Common errors that can be checked with errors.Is()
 var (
@@ -15,4 +22,73 @@ var (
 
 	// ErrRepositoryUnavailable indicates that the repository could not be reached
 	ErrRepositoryUnavailable = errors.New("repository unavailable")
+
+	// ErrInvalidRepository indicates that the repository doesn't serve a Helm repository index
+	ErrInvalidRepository = errors.New("not a Helm repository")
+
+	// ErrCircuitOpen indicates that the lookup was skipped because the repository kept failing
+	ErrCircuitOpen = errors.New("repository unavailable (circuit open)")
+
+	// ErrRepositoryDisabled indicates that the lookup was skipped because the repository is disabled in the configuration
+	ErrRepositoryDisabled = errors.New("repository disabled")
 )
+
+// Error codes categorize failed chart lookups for dashboards and alert routing
+const (
+	ErrorCodeAuthFailed        = "AUTH_FAILED"
+	ErrorCodeChartNotFound     = "CHART_NOT_FOUND"
+	ErrorCodeNoValidVersions   = "NO_VALID_VERSIONS"
+	ErrorCodeRepoNotFound      = "REPO_NOT_FOUND"
+	ErrorCodeRepoUnreachable   = "REPO_UNREACHABLE"
+	ErrorCodeInvalidRepository = "INVALID_REPOSITORY"
+	ErrorCodeTimeout           = "TIMEOUT"
+	ErrorCodeCircuitOpen       = "CIRCUIT_OPEN"
+	ErrorCodeRepoDisabled      = "REPO_DISABLED"
+	ErrorCodeCanceled          = "CANCELED"
+	ErrorCodeUnknown           = "UNKNOWN"
+)
+
+// ErrorCode returns the error code of a failed chart lookup, or an empty string for a nil error
+// Codes are derived from the sentinel errors above, Git transport errors and network errors.
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorCodeCircuitOpen
+	case errors.Is(err, ErrRepositoryDisabled):
+		return ErrorCodeRepoDisabled
+	case errors.Is(err, ErrAuthenticationFailed),
+		errors.Is(err, transport.ErrAuthenticationRequired),
+		errors.Is(err, transport.ErrAuthorizationFailed):
+		return ErrorCodeAuthFailed
+	case errors.Is(err, ErrChartNotFound):
+		return ErrorCodeChartNotFound
+	case errors.Is(err, ErrNoValidVersions):
+		return ErrorCodeNoValidVersions
+	case errors.Is(err, transport.ErrRepositoryNotFound):
+		return ErrorCodeRepoNotFound
+	case errors.Is(err, ErrInvalidRepository):
+		return ErrorCodeInvalidRepository
+	// Timeouts are also network errors, so they are checked first
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorCodeTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCanceled
+	case errors.Is(err, ErrRepositoryUnavailable), isNetworkError(err):
+		return ErrorCodeRepoUnreachable
+	}
+	return ErrorCodeUnknown
+}
+
+// isNetworkError reports whether an error comes from reaching the repository, such as a failed
+// DNS lookup, a refused connection or a TLS handshake failure
+func isNetworkError(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var urlErr *url.Error
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr) || errors.As(err, &urlErr)
+}
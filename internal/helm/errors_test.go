@@ -0,0 +1,43 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+func TestErrorCode(t *testing.T) {
+	dnsErr := &url.Error{Op: "Get", URL: "https://charts.example.com/index.yaml", Err: &net.DNSError{Err: "no such host", Name: "charts.example.com"}}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "nil", err: nil, expected: ""},
+		{name: "authentication", err: fmt.Errorf("%w for ghcr.io (status 401)", ErrAuthenticationFailed), expected: ErrorCodeAuthFailed},
+		{name: "git authentication", err: fmt.Errorf("failed to clone repository: %w", transport.ErrAuthenticationRequired), expected: ErrorCodeAuthFailed},
+		{name: "chart not found", err: fmt.Errorf("%w: nginx", ErrChartNotFound), expected: ErrorCodeChartNotFound},
+		{name: "no valid versions", err: fmt.Errorf("failed to determine latest version: %w", ErrNoValidVersions), expected: ErrorCodeNoValidVersions},
+		{name: "git repository not found", err: fmt.Errorf("failed to clone repository: %w", transport.ErrRepositoryNotFound), expected: ErrorCodeRepoNotFound},
+		{name: "invalid repository", err: fmt.Errorf("%w: no index.yaml (status 404)", ErrInvalidRepository), expected: ErrorCodeInvalidRepository},
+		{name: "lookup timeout", err: fmt.Errorf("Helm repository lookup timed out after 30s: %w", context.DeadlineExceeded), expected: ErrorCodeTimeout},
+		{name: "canceled", err: fmt.Errorf("failed to fetch index: %w", context.Canceled), expected: ErrorCodeCanceled},
+		{name: "server error", err: fmt.Errorf("%w: repository returned status 503", ErrRepositoryUnavailable), expected: ErrorCodeRepoUnreachable},
+		{name: "dns failure", err: fmt.Errorf("failed to fetch index: %w", dnsErr), expected: ErrorCodeRepoUnreachable},
+		{name: "other", err: errors.New("something else"), expected: ErrorCodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCode(tt.err); got != tt.expected {
+				t.Errorf("ErrorCode(%v) = %s, want %s", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// flightGroup coalesces concurrent requests for the same key: while one is in flight, later callers
+// wait for its result instead of sending their own
+// Results aren't kept once the request completes; the zero value is ready to use.
+type flightGroup[T any] struct {
+	mu      sync.Mutex
+	flights map[string]*flight[T]
+}
+
+// flight is a request in progress, shared by every caller asking for its key
+type flight[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// do returns the result of fn for key, joining a request already in flight for it
+// fn runs with the context of the caller that started it; callers whose own context is still live
+// retry when that caller was canceled, so one worker's deadline doesn't fail the others.
+func (g *flightGroup[T]) do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	for {
+		g.mu.Lock()
+		if g.flights == nil {
+			g.flights = make(map[string]*flight[T])
+		}
+		f, joined := g.flights[key]
+		if !joined {
+			f = &flight[T]{done: make(chan struct{})}
+			g.flights[key] = f
+		}
+		g.mu.Unlock()
+
+		if !joined {
+			f.value, f.err = fn(ctx)
+			g.mu.Lock()
+			delete(g.flights, key)
+			g.mu.Unlock()
+			close(f.done)
+			return f.value, f.err
+		}
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		if isContextError(f.err) && ctx.Err() == nil {
+			continue
+		}
+		return f.value, f.err
+	}
+}
+
+// isContextError reports whether err comes from a canceled or expired context
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
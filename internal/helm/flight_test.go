@@ -0,0 +1,76 @@
+package helm
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlightGroup_Do(t *testing.T) {
+	var group flightGroup[string]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "index", nil
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := group.do(context.Background(), "https://charts.example.com", fn)
+			assert.NoError(t, err)
+			assert.Equal(t, "index", value)
+		}()
+	}
+	// Let the callers join the request before it completes
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load(), "concurrent callers share one request")
+
+	// Completed requests aren't kept
+	_, err := group.do(context.Background(), "https://charts.example.com", fn)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestFlightGroup_DoLeaderCanceled(t *testing.T) {
+	var group flightGroup[string]
+	started := make(chan struct{})
+	leaderCtx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := group.do(leaderCtx, "key", func(ctx context.Context) (string, error) {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+	<-started
+
+	result := make(chan string)
+	go func() {
+		value, err := group.do(context.Background(), "key", func(ctx context.Context) (string, error) {
+			return "retried", nil
+		})
+		assert.NoError(t, err)
+		result <- value
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, "retried", <-result, "callers with a live context retry after the leader is canceled")
+}
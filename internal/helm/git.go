@@ -2,6 +2,7 @@ package helm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,25 +10,35 @@ import (
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
+
+	"argazer/internal/auth"
 )
 
 // GitClient handles operations with Git repositories containing Helm charts
 type GitClient struct {
-	username string
-	password string
-	logger   *logrus.Entry
+	username      string
+	password      string
+	authProvider  *auth.Provider // Credentials looked up per repository, nil to use username and password
+	tagExclusions *TagExclusions
+	clones        *gitCloneCache // Clones shared between lookups, nil to clone for each lookup
+	logger        *logrus.Entry
 }
 
 // NewGitClient creates a new Git client
 func NewGitClient(username, password string, logger *logrus.Entry) *GitClient {
 	return &GitClient{
-		username: username,
-		password: password,
-		logger:   logger,
+		username:      username,
+		password:      password,
+		tagExclusions: defaultTagExclusions(),
+		logger:        logger,
 	}
 }
 
@@ -35,8 +46,11 @@ func NewGitClient(username, password string, logger *logrus.Entry) *GitClient {
 type ChartMetadata struct {
 	Name        string `yaml:"name"`
 	Version     string `yaml:"version"`
+	AppVersion  string `yaml:"appVersion"`
 	Description string `yaml:"description"`
 	APIVersion  string `yaml:"apiVersion"`
+
+	Dependencies []ChartDependency `yaml:"dependencies"`
 }
 
 // isGitURL determines if a URL is a Git repository
@@ -50,7 +64,7 @@ func isGitURL(repoURL string) bool {
 	lower := strings.ToLower(repoURL)
 
 	// Explicit Git URLs
-	if strings.HasSuffix(lower, ".git") || strings.HasPrefix(lower, "git@") {
+	if strings.HasSuffix(lower, ".git") || strings.HasPrefix(lower, "git@") || strings.HasPrefix(lower, "ssh://") {
 		return true
 	}
 
@@ -68,92 +82,178 @@ func isGitURL(repoURL string) bool {
 	return false
 }
 
-// GetLatestVersion fetches the latest semantic version from Git repository
-// It looks at Git tags for version information
-func (g *GitClient) GetLatestVersion(ctx context.Context, repoURL, chartPath string) (string, error) {
-	g.logger.WithFields(logrus.Fields{
-		"repo":       repoURL,
-		"chart_path": chartPath,
-	}).Debug("Fetching latest version from Git repository")
+// isBranchRevision reports whether a Git target revision tracks a branch rather than a release
+// HEAD, an empty revision and anything that is neither a version tag nor a commit SHA count as branches
+func isBranchRevision(revision, chartPath string) bool {
+	if revision == "" || revision == "HEAD" {
+		return true
+	}
+	if isCommitSHA(revision) {
+		return false
+	}
+	_, err := semver.NewVersion(versionFromTag(revision, chartPath))
+	return err != nil
+}
 
-	// Create temporary directory for cloning
-	tmpDir, err := os.MkdirTemp("", "argazer-git-*")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp directory: %w", err)
+// versionFromTag strips common prefixes from a tag name to obtain its version
+// Tags may look like "v1.2.3", "release-1.2.3", "chart-1.2.3" or "chartname-v1.2.3"
+func versionFromTag(tagName, chartPath string) string {
+	versionStr := strings.TrimPrefix(tagName, "v")
+	versionStr = strings.TrimPrefix(versionStr, "release-")
+	versionStr = strings.TrimPrefix(versionStr, "chart-")
+
+	// If chartPath is specified, look for tags like "chartname-v1.2.3"
+	if chartPath != "" {
+		chartName := filepath.Base(chartPath)
+		prefix := chartName + "-"
+		if strings.HasPrefix(tagName, prefix) {
+			versionStr = strings.TrimPrefix(tagName, prefix)
+			versionStr = strings.TrimPrefix(versionStr, "v")
+		}
 	}
-	defer os.RemoveAll(tmpDir)
 
-	// Clone options
-	cloneOpts := &git.CloneOptions{
-		URL:      repoURL,
-		Progress: nil, // Silent clone
-		Tags:     git.AllTags,
+	return versionStr
+}
+
+// credentials returns the credentials of a repository from the auth provider, falling back to the
+// client's username and password
+// Credentials are looked up for each operation, so concurrent lookups of different repositories
+// never use each other's credentials.
+func (g *GitClient) credentials(repoURL string) *auth.Credentials {
+	if g.authProvider != nil {
+		if creds := g.authProvider.GetCredentials(repoURL); creds != nil {
+			return creds
+		}
 	}
+	return &auth.Credentials{Username: g.username, Password: g.password}
+}
+
+// auth returns the credentials of the repository URL as an auth method, or nil without credentials
+func (g *GitClient) auth(repoURL string) (transport.AuthMethod, error) {
+	return gitAuth(repoURL, g.credentials(repoURL))
+}
 
-	// Add authentication if provided
-	if g.username != "" && g.password != "" {
-		cloneOpts.Auth = &http.BasicAuth{
-			Username: g.username,
-			Password: g.password,
+// gitAuth returns the auth method of credentials for the repository URL, or nil without credentials
+// HTTP URLs use basic auth. SSH URLs ("git@host:org/repo.git", "ssh://") use the private key, the
+// ssh-agent or the password, in that order, as the user of the URL (default "git"); host keys are
+// verified against SSH_KNOWN_HOSTS or ~/.ssh/known_hosts.
+func gitAuth(repoURL string, creds *auth.Credentials) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(repoURL)
+	if err != nil || endpoint.Protocol != "ssh" {
+		if creds.Username != "" && creds.Password != "" {
+			return &http.BasicAuth{
+				Username: creds.Username,
+				Password: creds.Password,
+			}, nil
 		}
+		return nil, nil
 	}
 
-	// Clone the repository
-	repo, err := git.PlainCloneContext(ctx, tmpDir, false, cloneOpts)
-	if err != nil {
-		return "", fmt.Errorf("failed to clone repository: %w", err)
+	user := endpoint.User
+	if user == "" {
+		user = "git"
+	}
+	switch {
+	case creds.SSHKey != "":
+		keys, err := gitssh.NewPublicKeysFromFile(user, creds.SSHKey, creds.SSHKeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SSH key %s: %w", creds.SSHKey, err)
+		}
+		return keys, nil
+	case creds.SSHAgent:
+		agent, err := gitssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+		}
+		return agent, nil
+	case creds.Password != "":
+		return &gitssh.Password{User: user, Password: creds.Password}, nil
 	}
+	return nil, nil
+}
 
-	// Get all tags
-	tags, err := repo.Tags()
+// listTags lists the repository's tags like `git ls-remote --tags`, without cloning it
+// With peeled, annotated tags are also listed as "<tag>^{}" pointing at their commit.
+func (g *GitClient) listTags(ctx context.Context, repoURL string, peeled bool) ([]*plumbing.Reference, error) {
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{Name: "origin", URLs: []string{repoURL}})
+
+	authMethod, err := g.auth(repoURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to list tags: %w", err)
+		return nil, err
+	}
+	listOpts := &git.ListOptions{Auth: authMethod, PeelingOption: git.IgnorePeeled}
+	if peeled {
+		listOpts.PeelingOption = git.AppendPeeled
+	}
+	refs, err := remote.ListContext(ctx, listOpts)
+	if err != nil {
+		if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list remote tags: %w", err)
 	}
 
-	var versions []*semver.Version
-	err = tags.ForEach(func(ref *plumbing.Reference) error {
-		tagName := ref.Name().Short()
-
-		// Try to parse as semantic version
-		// Remove common prefixes (v, release-, etc.)
-		versionStr := strings.TrimPrefix(tagName, "v")
-		versionStr = strings.TrimPrefix(versionStr, "release-")
-		versionStr = strings.TrimPrefix(versionStr, "chart-")
-
-		// If chartPath is specified, look for tags like "chartname-v1.2.3"
-		if chartPath != "" {
-			chartName := filepath.Base(chartPath)
-			prefix := chartName + "-"
-			if strings.HasPrefix(tagName, prefix) {
-				versionStr = strings.TrimPrefix(tagName, prefix)
-				versionStr = strings.TrimPrefix(versionStr, "v")
-			}
+	var tags []*plumbing.Reference
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			tags = append(tags, ref)
 		}
+	}
+	return tags, nil
+}
 
-		v, err := semver.NewVersion(versionStr)
+// withClone runs fn on a clone of the repository, shared with other lookups of the same key when
+// the clone cache is enabled, or cloned into a temporary directory for this lookup otherwise
+func (g *GitClient) withClone(ctx context.Context, key string, cloneOpts *git.CloneOptions, fn func(dir string, repo *git.Repository) error) error {
+	authMethod, err := g.auth(cloneOpts.URL)
+	if err != nil {
+		return err
+	}
+	cloneOpts.Auth = authMethod
+
+	if g.clones != nil {
+		clone, err := g.clones.acquire(ctx, key, cloneOpts)
 		if err != nil {
-			// Not a valid semver tag, skip it
-			g.logger.WithFields(logrus.Fields{
-				"tag":   tagName,
-				"error": err,
-			}).Debug("Skipping non-semver tag")
-			return nil
+			return err
 		}
+		defer clone.mu.Unlock()
+		return fn(clone.dir, clone.repo)
+	}
 
-		versions = append(versions, v)
-		return nil
-	})
+	// Create temporary directory for cloning
+	tmpDir, err := os.MkdirTemp("", gitTempDirPattern)
 	if err != nil {
-		return "", fmt.Errorf("error processing tags: %w", err)
+		return fmt.Errorf("failed to create temp directory: %w", err)
 	}
+	defer os.RemoveAll(tmpDir)
 
-	if len(versions) == 0 {
-		return "", fmt.Errorf("no valid semantic version tags found in repository")
+	repo, err := git.PlainCloneContext(ctx, tmpDir, false, cloneOpts)
+	if err != nil {
+		return fmt.Errorf("failed to clone repository: %w", err)
+	}
+	return fn(tmpDir, repo)
+}
+
+// GetLatestVersion fetches the latest semantic version from Git repository
+// It looks at Git tags for version information
+func (g *GitClient) GetLatestVersion(ctx context.Context, repoURL, chartPath string) (string, error) {
+	g.logger.WithFields(logrus.Fields{
+		"repo":       repoURL,
+		"chart_path": chartPath,
+	}).Debug("Fetching latest version from Git repository")
+
+	versions, err := g.GetAllVersions(ctx, repoURL, chartPath)
+	if err != nil {
+		return "", err
 	}
 
 	// Find the latest version
 	var latest *semver.Version
-	for _, v := range versions {
+	for _, versionStr := range versions {
+		v, err := semver.NewVersion(versionStr)
+		if err != nil {
+			continue
+		}
 		if latest == nil || v.GreaterThan(latest) {
 			latest = v
 		}
@@ -171,46 +271,42 @@ func (g *GitClient) GetLatestVersion(ctx context.Context, repoURL, chartPath str
 // GetChartVersion fetches the chart version from Chart.yaml in the repository
 // This is useful when tags don't follow semver or when you want the chart version directly
 func (g *GitClient) GetChartVersion(ctx context.Context, repoURL, chartPath string) (string, error) {
+	return g.GetChartVersionAtBranch(ctx, repoURL, chartPath, "HEAD")
+}
+
+// GetChartVersionAtBranch fetches the chart version from Chart.yaml at the tip of a branch
+// "HEAD" (or an empty branch) uses the repository's default branch
+func (g *GitClient) GetChartVersionAtBranch(ctx context.Context, repoURL, chartPath, branch string) (string, error) {
 	g.logger.WithFields(logrus.Fields{
 		"repo":       repoURL,
 		"chart_path": chartPath,
+		"branch":     branch,
 	}).Debug("Fetching chart version from Chart.yaml")
 
-	// Create temporary directory for cloning
-	tmpDir, err := os.MkdirTemp("", "argazer-git-*")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp directory: %w", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
 	// Clone options (shallow clone for faster operation)
 	cloneOpts := &git.CloneOptions{
 		URL:      repoURL,
 		Progress: nil,
 		Depth:    1, // Shallow clone
 	}
-
-	// Add authentication if provided
-	if g.username != "" && g.password != "" {
-		cloneOpts.Auth = &http.BasicAuth{
-			Username: g.username,
-			Password: g.password,
-		}
-	}
-
-	// Clone the repository
-	_, err = git.PlainCloneContext(ctx, tmpDir, false, cloneOpts)
-	if err != nil {
-		return "", fmt.Errorf("failed to clone repository: %w", err)
+	if branch != "" && branch != "HEAD" {
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(branch)
+		cloneOpts.SingleBranch = true
+	} else {
+		branch = "HEAD"
 	}
 
-	// Construct path to Chart.yaml
-	chartYAMLPath := filepath.Join(tmpDir, chartPath, "Chart.yaml")
-
-	// Read Chart.yaml
-	data, err := os.ReadFile(chartYAMLPath)
+	var data []byte
+	err := g.withClone(ctx, repoURL+"#"+branch, cloneOpts, func(dir string, _ *git.Repository) error {
+		var err error
+		data, err = os.ReadFile(filepath.Join(dir, chartPath, "Chart.yaml"))
+		if err != nil {
+			return fmt.Errorf("failed to read Chart.yaml: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read Chart.yaml: %w", err)
+		return "", err
 	}
 
 	// Parse Chart.yaml
@@ -225,6 +321,7 @@ func (g *GitClient) GetChartVersion(ctx context.Context, repoURL, chartPath stri
 
 	g.logger.WithFields(logrus.Fields{
 		"repo":    repoURL,
+		"branch":  branch,
 		"chart":   chart.Name,
 		"version": chart.Version,
 	}).Debug("Found version from Chart.yaml")
@@ -233,86 +330,140 @@ func (g *GitClient) GetChartVersion(ctx context.Context, repoURL, chartPath stri
 }
 
 // GetAllVersions fetches all semantic versions from Git tags
+// Tags are listed with ls-remote, so the repository isn't cloned.
 func (g *GitClient) GetAllVersions(ctx context.Context, repoURL, chartPath string) ([]string, error) {
 	g.logger.WithFields(logrus.Fields{
 		"repo":       repoURL,
 		"chart_path": chartPath,
 	}).Debug("Fetching all versions from Git repository")
 
-	// Create temporary directory for cloning
-	tmpDir, err := os.MkdirTemp("", "argazer-git-*")
+	tags, err := g.listTags(ctx, repoURL, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+		return nil, err
 	}
-	defer os.RemoveAll(tmpDir)
 
-	// Clone options
-	cloneOpts := &git.CloneOptions{
-		URL:      repoURL,
-		Progress: nil,
-		Tags:     git.AllTags,
-	}
+	filter := g.tagExclusions.For(repoURL)
+	var versions []string
+	for _, ref := range tags {
+		tagName := ref.Name().Short()
 
-	// Add authentication if provided
-	if g.username != "" && g.password != "" {
-		cloneOpts.Auth = &http.BasicAuth{
-			Username: g.username,
-			Password: g.password,
+		versionStr := versionFromTag(tagName, chartPath)
+		if filter.Excludes(versionStr) {
+			g.logger.WithField("tag", tagName).Debug("Skipping excluded tag")
+			continue
 		}
+
+		if _, err := semver.NewVersion(versionStr); err != nil {
+			// Not a valid semver tag, skip it
+			g.logger.WithFields(logrus.Fields{
+				"tag":   tagName,
+				"error": err,
+			}).Debug("Skipping non-semver tag")
+			continue
+		}
+
+		versions = append(versions, versionStr)
 	}
 
-	// Clone the repository
-	repo, err := git.PlainCloneContext(ctx, tmpDir, false, cloneOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to clone repository: %w", err)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: no semantic version tags in repository", ErrNoValidVersions)
 	}
 
-	// Get all tags
-	tags, err := repo.Tags()
+	g.logger.WithFields(logrus.Fields{
+		"repo":           repoURL,
+		"total_versions": len(versions),
+	}).Debug("Found versions from Git tags")
+
+	return versions, nil
+}
+
+// ResolveCommit maps a commit SHA to the chart version it corresponds to
+// A semver tag pointing at the commit wins, found with ls-remote; otherwise the version is read
+// from Chart.yaml as of that commit, which needs a full clone
+func (g *GitClient) ResolveCommit(ctx context.Context, repoURL, chartPath, sha string) (string, error) {
+	g.logger.WithFields(logrus.Fields{
+		"repo":       repoURL,
+		"chart_path": chartPath,
+		"commit":     sha,
+	}).Debug("Resolving commit to chart version")
+
+	// Prefer a semver tag pointing at the commit; annotated tags are listed peeled as "<tag>^{}"
+	tags, err := g.listTags(ctx, repoURL, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tags: %w", err)
+		return "", err
 	}
 
-	var versions []string
-	err = tags.ForEach(func(ref *plumbing.Reference) error {
-		tagName := ref.Name().Short()
+	var tagged *semver.Version
+	for _, ref := range tags {
+		if !strings.HasPrefix(ref.Hash().String(), strings.ToLower(sha)) {
+			continue
+		}
+		tagName := strings.TrimSuffix(ref.Name().Short(), "^{}")
+		v, err := semver.NewVersion(versionFromTag(tagName, chartPath))
+		if err != nil {
+			continue
+		}
+		if tagged == nil || v.GreaterThan(tagged) {
+			tagged = v
+		}
+	}
 
-		// Try to parse as semantic version
-		versionStr := strings.TrimPrefix(tagName, "v")
-		versionStr = strings.TrimPrefix(versionStr, "release-")
-		versionStr = strings.TrimPrefix(versionStr, "chart-")
-
-		// If chartPath is specified, look for tags like "chartname-v1.2.3"
-		if chartPath != "" {
-			chartName := filepath.Base(chartPath)
-			prefix := chartName + "-"
-			if strings.HasPrefix(tagName, prefix) {
-				versionStr = strings.TrimPrefix(tagName, prefix)
-				versionStr = strings.TrimPrefix(versionStr, "v")
-			}
+	if tagged != nil {
+		g.logger.WithFields(logrus.Fields{
+			"commit":  sha,
+			"version": tagged.String(),
+		}).Debug("Resolved commit from Git tag")
+		return tagged.String(), nil
+	}
+
+	// Fall back to Chart.yaml at that commit
+	// Full clone: the commit may be anywhere in history
+	cloneOpts := &git.CloneOptions{
+		URL:      repoURL,
+		Progress: nil,
+		Tags:     git.AllTags,
+	}
+
+	var contents string
+	err = g.withClone(ctx, repoURL+"#full", cloneOpts, func(_ string, repo *git.Repository) error {
+		hash, err := repo.ResolveRevision(plumbing.Revision(sha))
+		if err != nil {
+			return fmt.Errorf("commit not found: %w", err)
 		}
 
-		_, err := semver.NewVersion(versionStr)
+		commit, err := repo.CommitObject(*hash)
 		if err != nil {
-			// Not a valid semver tag, skip it
-			return nil
+			return fmt.Errorf("failed to read commit: %w", err)
 		}
 
-		versions = append(versions, versionStr)
+		file, err := commit.File(filepath.ToSlash(filepath.Join(chartPath, "Chart.yaml")))
+		if err != nil {
+			return fmt.Errorf("failed to read Chart.yaml at commit %s: %w", sha, err)
+		}
+
+		contents, err = file.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read Chart.yaml at commit %s: %w", sha, err)
+		}
 		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error processing tags: %w", err)
+		return "", err
 	}
 
-	if len(versions) == 0 {
-		return nil, fmt.Errorf("no valid semantic version tags found in repository")
+	var chart ChartMetadata
+	if err := yaml.Unmarshal([]byte(contents), &chart); err != nil {
+		return "", fmt.Errorf("failed to parse Chart.yaml: %w", err)
+	}
+
+	if chart.Version == "" {
+		return "", fmt.Errorf("no version found in Chart.yaml")
 	}
 
 	g.logger.WithFields(logrus.Fields{
-		"repo":           repoURL,
-		"total_versions": len(versions),
-	}).Debug("Found versions from Git tags")
+		"commit":  sha,
+		"version": chart.Version,
+	}).Debug("Resolved commit from Chart.yaml")
 
-	return versions, nil
+	return chart.Version, nil
 }
@@ -2,10 +2,22 @@ package helm
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/auth"
 )
 
 func TestIsGitURL(t *testing.T) {
@@ -19,6 +31,11 @@ func TestIsGitURL(t *testing.T) {
 			url:      "https://github.com/myorg/charts.git",
 			expected: true,
 		},
+		{
+			name:     "SSH URL",
+			url:      "ssh://git@gitlab.example.com:2222/myorg/charts",
+			expected: true,
+		},
 		{
 			name:     "GitHub HTTPS URL without .git",
 			url:      "https://github.com/myorg/charts",
@@ -126,7 +143,7 @@ func TestGitClient_GetLatestVersion_Integration(t *testing.T) {
 
 		_, err := client.GetLatestVersion(ctx, "https://github.com/nonexistent/repo-that-does-not-exist.git", "")
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to clone repository")
+		assert.Contains(t, err.Error(), "failed to list remote tags")
 	})
 
 	t.Run("invalid URL", func(t *testing.T) {
@@ -156,7 +173,7 @@ func TestGitClient_GetAllVersions_Unit(t *testing.T) {
 
 		_, err := client.GetAllVersions(ctx, "https://github.com/definitely-does-not-exist-12345/repo.git", "")
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to clone")
+		assert.Contains(t, err.Error(), "failed to list remote tags")
 	})
 }
 
@@ -196,6 +213,78 @@ func TestGitClient_Authentication(t *testing.T) {
 	})
 }
 
+func TestGitClient_AuthMethod(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "id_rsa")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	t.Run("basic auth over HTTPS", func(t *testing.T) {
+		client := NewGitClient("user", "token", logger)
+		method, err := client.auth("https://github.com/myorg/charts.git")
+		require.NoError(t, err)
+		assert.Equal(t, &http.BasicAuth{Username: "user", Password: "token"}, method)
+	})
+
+	t.Run("SSH key for scp-like URLs", func(t *testing.T) {
+		method, err := gitAuth("git@github.com:myorg/charts.git", &auth.Credentials{SSHKey: keyFile})
+		require.NoError(t, err)
+		keys, ok := method.(*gitssh.PublicKeys)
+		require.True(t, ok, "expected SSH public keys, got %T", method)
+		assert.Equal(t, "git", keys.User)
+	})
+
+	t.Run("SSH password with the user of the URL", func(t *testing.T) {
+		client := NewGitClient("", "secret", logger)
+		method, err := client.auth("ssh://deploy@git.example.com:2222/charts.git")
+		require.NoError(t, err)
+		assert.Equal(t, &gitssh.Password{User: "deploy", Password: "secret"}, method)
+	})
+
+	t.Run("missing SSH key", func(t *testing.T) {
+		_, err := gitAuth("git@github.com:myorg/charts.git", &auth.Credentials{SSHKey: filepath.Join(t.TempDir(), "missing")})
+		assert.ErrorContains(t, err, "failed to load SSH key")
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		client := NewGitClient("", "", logger)
+		method, err := client.auth("git@github.com:myorg/charts.git")
+		require.NoError(t, err)
+		assert.Nil(t, method)
+	})
+}
+
+func TestGitClient_AuthProvider(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	provider, err := auth.NewProvider([]auth.ConfigAuth{
+		{URL: "https://git.team-a.example.com", Username: "a", Password: "token-a"},
+		{URL: "https://git.team-b.example.com", Username: "b", Password: "token-b"},
+	}, logger)
+	require.NoError(t, err)
+
+	client := NewGitClient("", "", logger)
+	client.authProvider = provider
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(team string) {
+			defer wg.Done()
+			method, err := client.auth("https://git.team-" + team + ".example.com/charts.git")
+			assert.NoError(t, err)
+			assert.Equal(t, &http.BasicAuth{Username: team, Password: "token-" + team}, method, "each repository uses its own credentials")
+		}([]string{"a", "b"}[i%2])
+	}
+	wg.Wait()
+
+	method, err := client.auth("https://git.team-c.example.com/charts.git")
+	require.NoError(t, err)
+	assert.Nil(t, method)
+}
+
 // Test version tag parsing logic
 func TestVersionTagParsing(t *testing.T) {
 	// This tests the logic that would be used in the actual implementation
@@ -288,3 +377,27 @@ func removePrefix(s, prefix string) string {
 func hasPrefix(s, prefix string) bool {
 	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
 }
+
+// TestIsBranchRevision tests detection of branch-tracking Git revisions
+func TestIsBranchRevision(t *testing.T) {
+	tests := []struct {
+		revision  string
+		chartPath string
+		expected  bool
+	}{
+		{revision: "HEAD", expected: true},
+		{revision: "", expected: true},
+		{revision: "main", expected: true},
+		{revision: "release/1.x", expected: true},
+		{revision: "1.2.3", expected: false},
+		{revision: "v1.2.3", expected: false},
+		{revision: "mychart-v1.2.3", chartPath: "charts/mychart", expected: false},
+		{revision: "a94a8fe5ccb19ba61c4c0873d391e987982fbbd3", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.revision, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isBranchRevision(tt.revision, tt.chartPath))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// gitCloneCache shares Git clones between the applications of a scan, so charts of a monorepo
+// read their Chart.yaml from a single clone instead of one clone per application
+// Clones stay on disk until release, typically at the end of the scan.
+type gitCloneCache struct {
+	logger *logrus.Entry
+
+	mu     sync.Mutex
+	clones map[string]*gitClone // By repository URL and clone kind
+}
+
+// gitClone is a cached clone
+// Its lock is held while cloning and while the clone is used: go-git repositories aren't safe for
+// concurrent use, and reading Chart.yaml takes far less than waiting for a clone of one's own.
+type gitClone struct {
+	mu   sync.Mutex
+	dir  string
+	repo *git.Repository // Nil until cloned
+}
+
+// newGitCloneCache creates an empty clone cache
+func newGitCloneCache(logger *logrus.Entry) *gitCloneCache {
+	return &gitCloneCache{
+		logger: logger,
+		clones: make(map[string]*gitClone),
+	}
+}
+
+// acquire returns the locked clone for key, cloning it on first use; the caller must unlock it
+// Failed clones aren't cached, so the next application tries again.
+func (c *gitCloneCache) acquire(ctx context.Context, key string, cloneOpts *git.CloneOptions) (*gitClone, error) {
+	c.mu.Lock()
+	clone, ok := c.clones[key]
+	if !ok {
+		clone = &gitClone{}
+		c.clones[key] = clone
+	}
+	c.mu.Unlock()
+
+	clone.mu.Lock()
+	if clone.repo != nil {
+		c.logger.WithField("repo", cloneOpts.URL).Debug("Reusing Git clone")
+		return clone, nil
+	}
+
+	dir, err := os.MkdirTemp("", gitTempDirPattern)
+	if err != nil {
+		clone.mu.Unlock()
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	repo, err := git.PlainCloneContext(ctx, dir, false, cloneOpts)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		clone.mu.Unlock()
+		return nil, fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	clone.dir = dir
+	clone.repo = repo
+	return clone, nil
+}
+
+// release removes every cached clone
+// It must not run concurrently with lookups.
+func (c *gitCloneCache) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, clone := range c.clones {
+		if clone.dir != "" {
+			if err := os.RemoveAll(clone.dir); err != nil {
+				c.logger.WithError(err).WithField("path", clone.dir).Warn("Failed to remove Git clone")
+			}
+		}
+		delete(c.clones, key)
+	}
+}
@@ -0,0 +1,107 @@
+package helm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLocalChartRepo creates a repository with two charts in one commit, tagged v1.0.0 (annotated)
+// and other-0.3.0 (lightweight), and returns its path and the commit
+func newLocalChartRepo(t *testing.T) (string, plumbing.Hash) {
+	t.Helper()
+	dir := t.TempDir()
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	for path, version := range map[string]string{"charts/app": "1.0.0", "charts/other": "0.3.0"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, path), 0o755))
+		chart := "apiVersion: v2\nname: " + filepath.Base(path) + "\nversion: " + version + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, path, "Chart.yaml"), []byte(chart), 0o644))
+	}
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add("charts")
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	commit, err := worktree.Commit("Add charts", &git.CommitOptions{Author: signature})
+	require.NoError(t, err)
+
+	_, err = repo.CreateTag("v1.0.0", commit, &git.CreateTagOptions{Tagger: signature, Message: "v1.0.0"})
+	require.NoError(t, err)
+	_, err = repo.CreateTag("other-0.3.0", commit, nil)
+	require.NoError(t, err)
+
+	return dir, commit
+}
+
+func TestGitClient_GetAllVersions_LsRemote(t *testing.T) {
+	repoDir, _ := newLocalChartRepo(t)
+	client := NewGitClient("", "", logrus.NewEntry(logrus.New()))
+
+	versions, err := client.GetAllVersions(context.Background(), repoDir, "charts/other")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1.0.0", "0.3.0"}, versions)
+}
+
+func TestGitClient_ResolveCommit_AnnotatedTag(t *testing.T) {
+	repoDir, commit := newLocalChartRepo(t)
+	client := NewGitClient("", "", logrus.NewEntry(logrus.New()))
+
+	// The annotated tag is matched through its peeled commit, without cloning
+	version, err := client.ResolveCommit(context.Background(), repoDir, "charts/app", commit.String()[:12])
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", version)
+}
+
+func TestGitCloneCache_SharedClone(t *testing.T) {
+	repoDir, _ := newLocalChartRepo(t)
+	logger := logrus.NewEntry(logrus.New())
+	client := NewGitClient("", "", logger)
+	client.clones = newGitCloneCache(logger)
+
+	ctx := context.Background()
+	version, err := client.GetChartVersionAtBranch(ctx, repoDir, "charts/app", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", version)
+	version, err = client.GetChartVersionAtBranch(ctx, repoDir, "charts/other", "")
+	require.NoError(t, err)
+	assert.Equal(t, "0.3.0", version)
+
+	// Both charts were read from a single clone
+	require.Len(t, client.clones.clones, 1)
+	var cloneDir string
+	for _, clone := range client.clones.clones {
+		cloneDir = clone.dir
+	}
+	_, err = os.Stat(filepath.Join(cloneDir, "charts", "app", "Chart.yaml"))
+	require.NoError(t, err)
+
+	client.clones.release()
+	assert.Empty(t, client.clones.clones)
+	_, err = os.Stat(cloneDir)
+	assert.True(t, os.IsNotExist(err), "clone directory should be removed on release")
+}
+
+func TestGitCloneCache_FailedCloneIsRetried(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	cache := newGitCloneCache(logger)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.acquire(context.Background(), missing, &git.CloneOptions{URL: missing})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to clone repository")
+	}
+	cache.release()
+}
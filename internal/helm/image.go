@@ -0,0 +1,124 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// dockerHubRegistry is the registry of image references without one
+const dockerHubRegistry = "docker.io"
+
+// ImageReference is a parsed container image reference
+type ImageReference struct {
+	Registry   string // e.g. "docker.io" or "ghcr.io"
+	Repository string // e.g. "library/nginx" or "org/app"
+	Tag        string // Empty when the image is referenced by digest only
+}
+
+// ParseImageReference parses an image reference like "nginx:1.25", "ghcr.io/org/app:v1.2.0" or
+// "registry:5000/app@sha256:…" following Docker's rules: the first path component is the registry
+// if it looks like a host, otherwise the image is on Docker Hub
+func ParseImageReference(image string) (ImageReference, error) {
+	name := strings.TrimSpace(image)
+	if name == "" {
+		return ImageReference{}, fmt.Errorf("empty image reference")
+	}
+	name, _, _ = strings.Cut(name, "@")
+
+	var ref ImageReference
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+
+	first, rest, found := strings.Cut(name, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, ref.Repository = first, rest
+	} else {
+		ref.Registry, ref.Repository = dockerHubRegistry, name
+	}
+	if ref.Registry == dockerHubRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Repository == "" {
+		return ImageReference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+	return ref, nil
+}
+
+// Name returns the image without its tag, e.g. "docker.io/library/nginx"
+func (r ImageReference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// String returns the full image reference
+func (r ImageReference) String() string {
+	if r.Tag == "" {
+		return r.Name()
+	}
+	return r.Name() + ":" + r.Tag
+}
+
+// GetLatestImageTag gets the latest tag of a container image respecting the version constraint
+// Only tags of the same variant as the current one are candidates, so "1.25.3-alpine" is compared
+// with "1.26.0-alpine" but not with "1.26.0".
+func (c *Checker) GetLatestImageTag(ctx context.Context, image ImageReference, constraint string) (*VersionConstraintResult, error) {
+	if _, err := semver.NewVersion(image.Tag); err != nil {
+		return nil, fmt.Errorf("%w: tag %q of %s is not a version", ErrNoValidVersions, image.Tag, image.Name())
+	}
+
+	if err := c.allow(image.Registry); err != nil {
+		return nil, err
+	}
+	ctx, done := c.withLookupTimeout(ctx, image.Registry)
+	tags, err := c.ociChecker.getTagsFromOCI(ctx, image.Registry, image.Repository)
+	err = done(err)
+	c.circuits.record(image.Registry, err)
+	if err != nil {
+		return nil, err
+	}
+
+	variant := imageTagVariant(image.Tag)
+	var candidates []string
+	for _, tag := range tags {
+		if _, err := semver.NewVersion(tag); err == nil && imageTagVariant(tag) == variant {
+			candidates = append(candidates, tag)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: no %q tags of %s", ErrNoValidVersions, variant, image.Name())
+	}
+
+	result, err := findLatestSemverWithConstraint(candidates, image.Tag, constraint, c.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine latest tag: %w", err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"image":          image.Name(),
+		"current_tag":    image.Tag,
+		"latest_tag":     result.LatestVersion,
+		"latest_tag_all": result.LatestVersionAll,
+		"constraint":     constraint,
+	}).Debug("Found latest image tag")
+
+	return result, nil
+}
+
+// imageTagVariant returns the flavor of an image tag: its prerelease part without numbers, e.g.
+// "alpine" for "1.25.3-alpine3.18" or "rc" for "2.0.0-rc.1", and "" for plain versions
+func imageTagVariant(tag string) string {
+	version, err := semver.NewVersion(tag)
+	if err != nil {
+		return ""
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' {
+			return -1
+		}
+		return r
+	}, version.Prerelease())
+}
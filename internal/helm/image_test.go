@@ -0,0 +1,105 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected ImageReference
+	}{
+		{image: "nginx", expected: ImageReference{Registry: "docker.io", Repository: "library/nginx"}},
+		{image: "nginx:1.25.3", expected: ImageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25.3"}},
+		{image: "bitnami/nginx:1.25.3-debian-12-r0", expected: ImageReference{Registry: "docker.io", Repository: "bitnami/nginx", Tag: "1.25.3-debian-12-r0"}},
+		{image: "ghcr.io/org/app:v1.2.0", expected: ImageReference{Registry: "ghcr.io", Repository: "org/app", Tag: "v1.2.0"}},
+		{image: "registry.local:5000/team/app@sha256:abc", expected: ImageReference{Registry: "registry.local:5000", Repository: "team/app"}},
+		{image: "localhost/app:1.0.0@sha256:abc", expected: ImageReference{Registry: "localhost", Repository: "app", Tag: "1.0.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := ParseImageReference(tt.image)
+			if err != nil {
+				t.Fatalf("ParseImageReference(%q) failed: %v", tt.image, err)
+			}
+			if ref != tt.expected {
+				t.Errorf("ParseImageReference(%q) = %+v, want %+v", tt.image, ref, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParseImageReference(" "); err == nil {
+		t.Error("Expected an error for an empty reference")
+	}
+}
+
+func TestImageTagVariant(t *testing.T) {
+	tests := map[string]string{
+		"1.25.3":            "",
+		"v1.25.3":           "",
+		"1.25.3-alpine":     "alpine",
+		"1.25.3-alpine3.18": "alpine",
+		"2.0.0-rc.1":        "rc",
+		"latest":            "",
+	}
+	for tag, expected := range tests {
+		if got := imageTagVariant(tag); got != expected {
+			t.Errorf("imageTagVariant(%q) = %q, want %q", tag, got, expected)
+		}
+	}
+}
+
+func TestCheckerGetLatestImageTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/team/app/tags/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"name": "team/app", "tags": ["1.24.0", "1.25.3", "1.25.4", "1.26.0", "1.25.3-alpine", "1.26.1-alpine", "2.0.0-rc.1", "latest"]}`)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, err := NewChecker(authProvider, logger)
+	if err != nil {
+		t.Fatalf("Failed to create checker: %v", err)
+	}
+	registry := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		tag, constraint, latest, latestAll string
+	}{
+		{tag: "1.25.3", constraint: "major", latest: "1.26.0", latestAll: "1.26.0"},
+		{tag: "1.25.3", constraint: "patch", latest: "1.25.4", latestAll: "1.26.0"},
+		{tag: "1.25.3-alpine", constraint: "major", latest: "1.26.1-alpine", latestAll: "1.26.1-alpine"},
+		{tag: "1.26.0", constraint: "major", latest: "1.26.0", latestAll: "1.26.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag+"/"+tt.constraint, func(t *testing.T) {
+			result, err := checker.GetLatestImageTag(context.Background(), ImageReference{Registry: registry, Repository: "team/app", Tag: tt.tag}, tt.constraint)
+			if err != nil {
+				t.Fatalf("GetLatestImageTag failed: %v", err)
+			}
+			if result.LatestVersion != tt.latest || result.LatestVersionAll != tt.latestAll {
+				t.Errorf("GetLatestImageTag() = %s (all: %s), want %s (all: %s)", result.LatestVersion, result.LatestVersionAll, tt.latest, tt.latestAll)
+			}
+		})
+	}
+
+	_, err = checker.GetLatestImageTag(context.Background(), ImageReference{Registry: registry, Repository: "team/app", Tag: "latest"}, "major")
+	if !errors.Is(err, ErrNoValidVersions) {
+		t.Errorf("Expected ErrNoValidVersions for a mutable tag, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// indexCacheFilePrefix names the files of indexes stored in the cache directory
+const indexCacheFilePrefix = "helm-index-"
+
+// indexCache keeps parsed Helm repository indexes so applications sharing a repository download its
+// index.yaml once per TTL instead of once each
+// Indexes older than the TTL are revalidated with their ETag/Last-Modified validators, and with a
+// directory set they are also stored on disk to be revalidated by later runs.
+type indexCache struct {
+	ttl    time.Duration
+	dir    string // Empty keeps indexes in memory only
+	logger *logrus.Entry
+
+	// Limits of the cache directory, 0 for none
+	maxAge  time.Duration // Indexes not refreshed for this long are removed
+	maxSize int64         // Bytes; the least recently refreshed indexes are removed beyond it
+
+	mu      sync.Mutex
+	entries map[string]*cachedIndex // By repository URL
+}
+
+// cachedIndex is the cached index of one repository
+// Its lock is held while the index is downloaded, so concurrent lookups wait for a single download.
+type cachedIndex struct {
+	mu           sync.Mutex
+	index        *Index // Nil until downloaded or loaded from disk
+	etag         string
+	lastModified string
+	fetched      time.Time // Last download or successful revalidation
+}
+
+// indexCacheMeta is stored next to an index on disk
+type indexCacheMeta struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Fetched      time.Time `json:"fetched"`
+}
+
+// newIndexCache creates an index cache, creating its directory if set
+func newIndexCache(ttl time.Duration, dir string, logger *logrus.Entry) (*indexCache, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+	}
+	return &indexCache{
+		ttl:     ttl,
+		dir:     dir,
+		logger:  logger,
+		entries: make(map[string]*cachedIndex),
+	}, nil
+}
+
+// entry returns the locked cache entry of a repository, loading it from disk on first use
+// The caller must unlock it.
+func (c *indexCache) entry(repoURL string) *cachedIndex {
+	c.mu.Lock()
+	entry, ok := c.entries[repoURL]
+	if !ok {
+		entry = &cachedIndex{}
+		c.entries[repoURL] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	if !ok && c.dir != "" {
+		c.load(repoURL, entry)
+	}
+	return entry
+}
+
+// fresh reports whether an entry can be used without revalidating it
+func (c *indexCache) fresh(entry *cachedIndex, now time.Time) bool {
+	return entry.index != nil && now.Sub(entry.fetched) < c.ttl
+}
+
+// paths returns the files holding a repository's index and its metadata on disk
+func (c *indexCache) paths(repoURL string) (indexPath, metaPath string) {
+	sum := sha256.Sum256([]byte(repoURL))
+	name := indexCacheFilePrefix + hex.EncodeToString(sum[:8])
+	return filepath.Join(c.dir, name+".yaml"), filepath.Join(c.dir, name+".json")
+}
+
+// load fills an entry from disk; a missing or unreadable copy is simply downloaded again
+func (c *indexCache) load(repoURL string, entry *cachedIndex) {
+	indexPath, metaPath := c.paths(repoURL)
+
+	raw, err := os.ReadFile(metaPath)
+	if err != nil {
+		return
+	}
+	var meta indexCacheMeta
+	if err := json.Unmarshal(raw, &meta); err != nil || meta.URL != repoURL {
+		return
+	}
+	body, err := os.ReadFile(indexPath)
+	if err != nil {
+		return
+	}
+	index, err := decodeIndex(body)
+	if err != nil {
+		c.logger.WithError(err).WithField("repo", repoURL).Debug("Ignoring unreadable cached index")
+		return
+	}
+
+	entry.index = index
+	entry.etag = meta.ETag
+	entry.lastModified = meta.LastModified
+	entry.fetched = meta.Fetched
+	c.logger.WithFields(logrus.Fields{
+		"repo":    repoURL,
+		"fetched": meta.Fetched,
+	}).Debug("Loaded Helm repository index from cache directory")
+}
+
+// store writes an index (nil body after a revalidation, keeping the stored copy) and its metadata to disk
+func (c *indexCache) store(repoURL string, entry *cachedIndex, body []byte) {
+	if c.dir == "" {
+		return
+	}
+	indexPath, metaPath := c.paths(repoURL)
+
+	if body != nil {
+		if err := writeFileAtomic(indexPath, body); err != nil {
+			c.logger.WithError(err).WithField("repo", repoURL).Warn("Failed to write cached index")
+			return
+		}
+	}
+	meta, err := json.MarshalIndent(indexCacheMeta{
+		URL:          repoURL,
+		ETag:         entry.etag,
+		LastModified: entry.lastModified,
+		Fetched:      entry.fetched,
+	}, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(metaPath, meta)
+	}
+	if err != nil {
+		c.logger.WithError(err).WithField("repo", repoURL).Warn("Failed to write cached index metadata")
+		return
+	}
+	if body != nil {
+		c.prune()
+	}
+}
+
+// prune applies the limits of the cache directory
+func (c *indexCache) prune() {
+	if c.dir == "" || (c.maxAge <= 0 && c.maxSize <= 0) {
+		return
+	}
+	if removed := pruneCacheDir(c.dir, c.maxAge, c.maxSize, time.Now(), c.logger); removed > 0 {
+		c.logger.WithField("count", removed).Debug("Removed indexes from cache directory")
+	}
+}
+
+// writeFileAtomic replaces a file through a temporary file, so concurrent runs sharing the cache
+// directory never read a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+const cacheTestIndex = `apiVersion: v1
+entries:
+  nginx:
+    - name: nginx
+      version: 1.1.0
+    - name: nginx
+      version: 1.0.0
+  redis:
+    - name: redis
+      version: 7.0.0
+`
+
+// indexServer serves cacheTestIndex with an ETag, counting full downloads and 304 answers
+type indexServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	downloads   int
+	revalidated int
+}
+
+func newIndexServer(t *testing.T) *indexServer {
+	t.Helper()
+	s := &indexServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			s.revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		s.downloads++
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, cacheTestIndex)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *indexServer) counts() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.downloads, s.revalidated
+}
+
+func newCachingChecker(t *testing.T, ttl time.Duration, dir string) *Checker {
+	t.Helper()
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, err := NewChecker(authProvider, logger)
+	if err != nil {
+		t.Fatalf("Failed to create checker: %v", err)
+	}
+	if err := checker.SetIndexCache(ttl, dir); err != nil {
+		t.Fatalf("Failed to enable index cache: %v", err)
+	}
+	return checker
+}
+
+func expectLatest(t *testing.T, checker *Checker, repoURL, chart, expected string) {
+	t.Helper()
+	version, err := checker.GetLatestVersion(context.Background(), repoURL, chart)
+	if err != nil {
+		t.Fatalf("GetLatestVersion(%s) failed: %v", chart, err)
+	}
+	if version != expected {
+		t.Errorf("GetLatestVersion(%s) = %s, want %s", chart, version, expected)
+	}
+}
+
+func TestIndexCache_SharedAcrossCharts(t *testing.T) {
+	server := newIndexServer(t)
+	checker := newCachingChecker(t, time.Hour, "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			expectLatest(t, checker, server.URL, "nginx", "1.1.0")
+		}()
+	}
+	wg.Wait()
+	expectLatest(t, checker, server.URL, "redis", "7.0.0")
+
+	if downloads, _ := server.counts(); downloads != 1 {
+		t.Errorf("Expected the index to be downloaded once, got %d downloads", downloads)
+	}
+}
+
+func TestIndexCache_Revalidation(t *testing.T) {
+	server := newIndexServer(t)
+	checker := newCachingChecker(t, time.Nanosecond, "")
+
+	expectLatest(t, checker, server.URL, "nginx", "1.1.0")
+	expectLatest(t, checker, server.URL, "redis", "7.0.0")
+
+	downloads, revalidated := server.counts()
+	if downloads != 1 || revalidated != 1 {
+		t.Errorf("Expected 1 download and 1 revalidation, got %d and %d", downloads, revalidated)
+	}
+}
+
+func TestIndexCache_Disabled(t *testing.T) {
+	server := newIndexServer(t)
+	checker := newCachingChecker(t, 0, "")
+
+	expectLatest(t, checker, server.URL, "nginx", "1.1.0")
+	expectLatest(t, checker, server.URL, "nginx", "1.1.0")
+
+	downloads, revalidated := server.counts()
+	if downloads != 2 || revalidated != 0 {
+		t.Errorf("Expected 2 downloads without cache, got %d downloads and %d revalidations", downloads, revalidated)
+	}
+}
+
+func TestIndexCache_Directory(t *testing.T) {
+	server := newIndexServer(t)
+	dir := t.TempDir()
+
+	expectLatest(t, newCachingChecker(t, time.Hour, dir), server.URL, "nginx", "1.1.0")
+
+	// A later run within the TTL doesn't contact the repository
+	expectLatest(t, newCachingChecker(t, time.Hour, dir), server.URL, "redis", "7.0.0")
+	if downloads, revalidated := server.counts(); downloads != 1 || revalidated != 0 {
+		t.Errorf("Expected the stored index to be used, got %d downloads and %d revalidations", downloads, revalidated)
+	}
+
+	// After the TTL, the stored index is revalidated instead of downloaded again
+	expectLatest(t, newCachingChecker(t, time.Nanosecond, dir), server.URL, "nginx", "1.1.0")
+	if downloads, revalidated := server.counts(); downloads != 1 || revalidated != 1 {
+		t.Errorf("Expected the stored index to be revalidated, got %d downloads and %d revalidations", downloads, revalidated)
+	}
+}
+
+func TestIndexCache_DirectoryLimits(t *testing.T) {
+	server := newIndexServer(t)
+	dir := t.TempDir()
+
+	checker := newCachingChecker(t, time.Hour, dir)
+	checker.SetIndexCacheLimits(0, 1)
+	expectLatest(t, checker, server.URL, "nginx", "1.1.0")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected the index beyond max size to be removed, got %d files", len(entries))
+	}
+}
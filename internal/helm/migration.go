@@ -0,0 +1,182 @@
+package helm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ChartMigration describes a chart that has been relocated to another repository
+type ChartMigration struct {
+	NewRepoURL string `json:"new_repo_url,omitempty"` // Repository the chart moved to (empty if unknown)
+	NewChart   string `json:"new_chart,omitempty"`    // Chart name in the new repository (empty if unchanged)
+	Reason     string `json:"reason"`                 // Human-readable explanation
+}
+
+// String returns a short description of where the chart has moved
+func (m *ChartMigration) String() string {
+	if m.NewRepoURL == "" {
+		return m.Reason
+	}
+	target := m.NewRepoURL
+	if m.NewChart != "" {
+		target = target + " (chart: " + m.NewChart + ")"
+	}
+	return "chart relocated to " + target
+}
+
+// knownMigration is an entry of the built-in relocation knowledge base
+type knownMigration struct {
+	repo      string // Normalized repository URL (host + path, no scheme)
+	chart     string // Chart name, or empty to match every chart in the repository
+	migration ChartMigration
+}
+
+// knownMigrations lists well-known chart relocations.
+// Repository-wide entries (empty chart) are consulted after chart-specific ones.
+// Only repositories that no longer publish new versions belong here, as matching charts skip the version lookup.
+var knownMigrations = []knownMigration{
+	// The old "stable" and "incubator" repositories were archived in November 2020
+	{
+		repo:  "kubernetes-charts.storage.googleapis.com",
+		chart: "prometheus-operator",
+		migration: ChartMigration{
+			NewRepoURL: "https://prometheus-community.github.io/helm-charts",
+			NewChart:   "kube-prometheus-stack",
+			Reason:     "stable repository archived, chart renamed to kube-prometheus-stack",
+		},
+	},
+	{
+		repo:  "kubernetes-charts.storage.googleapis.com",
+		chart: "prometheus",
+		migration: ChartMigration{
+			NewRepoURL: "https://prometheus-community.github.io/helm-charts",
+			Reason:     "stable repository archived",
+		},
+	},
+	{
+		repo:  "kubernetes-charts.storage.googleapis.com",
+		chart: "grafana",
+		migration: ChartMigration{
+			NewRepoURL: "https://grafana.github.io/helm-charts",
+			Reason:     "stable repository archived",
+		},
+	},
+	{
+		repo:  "kubernetes-charts.storage.googleapis.com",
+		chart: "nginx-ingress",
+		migration: ChartMigration{
+			NewRepoURL: "https://kubernetes.github.io/ingress-nginx",
+			NewChart:   "ingress-nginx",
+			Reason:     "stable repository archived, chart renamed to ingress-nginx",
+		},
+	},
+	{
+		repo:  "kubernetes-charts.storage.googleapis.com",
+		chart: "metrics-server",
+		migration: ChartMigration{
+			NewRepoURL: "https://kubernetes-sigs.github.io/metrics-server",
+			Reason:     "stable repository archived",
+		},
+	},
+	{
+		repo:  "kubernetes-charts.storage.googleapis.com",
+		chart: "external-dns",
+		migration: ChartMigration{
+			NewRepoURL: "https://kubernetes-sigs.github.io/external-dns",
+			Reason:     "stable repository archived",
+		},
+	},
+	{
+		repo:  "kubernetes-charts.storage.googleapis.com",
+		chart: "",
+		migration: ChartMigration{
+			Reason: "stable repository archived, chart must be installed from its new upstream repository",
+		},
+	},
+	{
+		repo:  "kubernetes-charts-incubator.storage.googleapis.com",
+		chart: "",
+		migration: ChartMigration{
+			Reason: "incubator repository archived, chart must be installed from its new upstream repository",
+		},
+	},
+	{
+		repo:  "charts.helm.sh/stable",
+		chart: "",
+		migration: ChartMigration{
+			Reason: "stable repository is deprecated and no longer updated",
+		},
+	},
+	{
+		repo:  "charts.helm.sh/incubator",
+		chart: "",
+		migration: ChartMigration{
+			Reason: "incubator repository is deprecated and no longer updated",
+		},
+	},
+}
+
+// repoURLPattern matches repository URLs mentioned in chart descriptions
+var repoURLPattern = regexp.MustCompile(`(?:oci|https?)://[^\s"'<>)]+`)
+
+// normalizeRepoURL normalizes a repository URL for knowledge base matching
+func normalizeRepoURL(repoURL string) string {
+	lower := strings.ToLower(strings.TrimSpace(repoURL))
+	for _, prefix := range []string{"https://", "http://", "oci://"} {
+		lower = strings.TrimPrefix(lower, prefix)
+	}
+	return strings.TrimSuffix(lower, "/")
+}
+
+// lookupKnownMigration checks the built-in knowledge base for a relocated chart
+func lookupKnownMigration(repoURL, chartName string) *ChartMigration {
+	normalized := normalizeRepoURL(repoURL)
+
+	var repoWide *ChartMigration
+	for i := range knownMigrations {
+		known := &knownMigrations[i]
+		if known.repo != normalized {
+			continue
+		}
+		if known.chart == chartName {
+			m := known.migration
+			return &m
+		}
+		if known.chart == "" && repoWide == nil {
+			m := known.migration
+			repoWide = &m
+		}
+	}
+
+	return repoWide
+}
+
+// detectDeprecatedEntry inspects index entries for the upstream deprecation flag.
+// Helm marks deprecated charts with `deprecated: true` on the newest version,
+// and maintainers usually point to the new location in the description.
+func detectDeprecatedEntry(repoURL string, entries []Entry) *ChartMigration {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// Helm repositories list the newest version first
+	newest := entries[0]
+	if !newest.Deprecated {
+		return nil
+	}
+
+	migration := &ChartMigration{
+		Reason: "chart is marked as deprecated in the repository index",
+	}
+
+	// Use the first URL mentioned in the description that points elsewhere
+	for _, candidate := range repoURLPattern.FindAllString(newest.Description, -1) {
+		candidate = strings.TrimRight(candidate, ".,;:")
+		if normalizeRepoURL(candidate) != normalizeRepoURL(repoURL) {
+			migration.NewRepoURL = candidate
+			break
+		}
+	}
+
+	return migration
+}
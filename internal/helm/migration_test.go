@@ -0,0 +1,136 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestLookupKnownMigration(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoURL     string
+		chart       string
+		expectFound bool
+		expectRepo  string
+		expectChart string
+	}{
+		{"stable chart-specific entry", "https://kubernetes-charts.storage.googleapis.com", "prometheus-operator", true, "https://prometheus-community.github.io/helm-charts", "kube-prometheus-stack"},
+		{"stable repo-wide fallback", "https://kubernetes-charts.storage.googleapis.com/", "some-chart", true, "", ""},
+		{"case insensitive", "HTTPS://Kubernetes-Charts.Storage.Googleapis.com", "grafana", true, "https://grafana.github.io/helm-charts", ""},
+		{"bitnami still publishes", "https://charts.bitnami.com/bitnami", "nginx", false, "", ""},
+		{"unknown repository", "https://charts.example.com", "nginx", false, "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migration := lookupKnownMigration(test.repoURL, test.chart)
+			if !test.expectFound {
+				if migration != nil {
+					t.Errorf("Expected no migration, got %+v", migration)
+				}
+				return
+			}
+			if migration == nil {
+				t.Fatal("Expected migration, got nil")
+			}
+			if migration.NewRepoURL != test.expectRepo {
+				t.Errorf("Expected new repo %q, got %q", test.expectRepo, migration.NewRepoURL)
+			}
+			if migration.NewChart != test.expectChart {
+				t.Errorf("Expected new chart %q, got %q", test.expectChart, migration.NewChart)
+			}
+		})
+	}
+}
+
+func TestDetectDeprecatedEntry(t *testing.T) {
+	repoURL := "https://charts.example.com"
+
+	t.Run("not deprecated", func(t *testing.T) {
+		entries := []Entry{{Name: "app", Version: "1.0.0"}}
+		if migration := detectDeprecatedEntry(repoURL, entries); migration != nil {
+			t.Errorf("Expected nil, got %+v", migration)
+		}
+	})
+
+	t.Run("deprecated with pointer", func(t *testing.T) {
+		entries := []Entry{{
+			Name:        "app",
+			Version:     "1.0.0",
+			Deprecated:  true,
+			Description: "DEPRECATED - moved to oci://ghcr.io/example/charts.",
+		}}
+		migration := detectDeprecatedEntry(repoURL, entries)
+		if migration == nil {
+			t.Fatal("Expected migration, got nil")
+		}
+		if migration.NewRepoURL != "oci://ghcr.io/example/charts" {
+			t.Errorf("Expected pointer to oci://ghcr.io/example/charts, got %q", migration.NewRepoURL)
+		}
+	})
+
+	t.Run("deprecated without pointer", func(t *testing.T) {
+		entries := []Entry{{Name: "app", Version: "1.0.0", Deprecated: true, Description: "See " + repoURL}}
+		migration := detectDeprecatedEntry(repoURL, entries)
+		if migration == nil {
+			t.Fatal("Expected migration, got nil")
+		}
+		if migration.NewRepoURL != "" {
+			t.Errorf("Expected no pointer, got %q", migration.NewRepoURL)
+		}
+		if migration.String() != migration.Reason {
+			t.Errorf("Expected String() to fall back to reason, got %q", migration.String())
+		}
+	})
+}
+
+func TestChartMigrationString(t *testing.T) {
+	migration := &ChartMigration{NewRepoURL: "https://new.example.com", NewChart: "renamed"}
+	expected := "chart relocated to https://new.example.com (chart: renamed)"
+	if migration.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, migration.String())
+	}
+}
+
+func TestCheckerGetLatestVersionWithConstraint_DeprecatedChart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		indexYAML := `apiVersion: v1
+entries:
+  legacy:
+    - name: legacy
+      version: 2.0.0
+      deprecated: true
+      description: "This chart moved to https://charts.vendor.example.com"
+    - name: legacy
+      version: 1.0.0
+`
+		w.Header().Set("Content-Type", "application/x-yaml")
+		fmt.Fprint(w, indexYAML)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, err := NewChecker(authProvider, logger)
+	if err != nil {
+		t.Fatalf("Failed to create checker: %v", err)
+	}
+
+	result, err := checker.GetLatestVersionWithConstraint(context.Background(), server.URL, "legacy", "2.0.0", "major")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Migration == nil {
+		t.Fatal("Expected deprecated chart to be flagged")
+	}
+	if result.Migration.NewRepoURL != "https://charts.vendor.example.com" {
+		t.Errorf("Expected pointer to vendor repository, got %q", result.Migration.NewRepoURL)
+	}
+}
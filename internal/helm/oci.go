@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -14,11 +15,18 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// maxTagPages caps how many pages of a paginated tags list are followed
+const maxTagPages = 1000
+
 // OCIChecker checks OCI-based Helm repositories for new chart versions
 type OCIChecker struct {
-	httpClient   *http.Client
-	authProvider *auth.Provider
-	logger       *logrus.Entry
+	httpClient    *http.Client
+	authProvider  *auth.Provider
+	tagExclusions *TagExclusions
+	tokens        *tokenCache           // Bearer tokens from registry auth services
+	tagFlights    flightGroup[[]string] // Tag list requests in flight, by repository and chart
+	retry         retryPolicy
+	logger        *logrus.Entry
 }
 
 // NewOCIChecker creates a new OCI checker
@@ -27,8 +35,10 @@ func NewOCIChecker(authProvider *auth.Provider, logger *logrus.Entry) *OCIChecke
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		authProvider: authProvider,
-		logger:       logger,
+		authProvider:  authProvider,
+		tagExclusions: defaultTagExclusions(),
+		tokens:        newTokenCache(),
+		logger:        logger,
 	}
 }
 
@@ -39,68 +49,83 @@ type TagsResponse struct {
 }
 
 // getTagsFromOCI fetches all available tags for a chart from an OCI registry
+// Concurrent lookups of a chart share one request; each caller gets its own copy of the tags.
 func (o *OCIChecker) getTagsFromOCI(ctx context.Context, repoURL, chartName string) ([]string, error) {
+	tags, err := o.tagFlights.do(ctx, repoURL+"/"+chartName, func(ctx context.Context) ([]string, error) {
+		return o.fetchTags(ctx, repoURL, chartName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), tags...), nil
+}
+
+// fetchTags fetches the tags of a chart from an OCI registry, without non-release tags
+func (o *OCIChecker) fetchTags(ctx context.Context, repoURL, chartName string) ([]string, error) {
 	o.logger.WithFields(logrus.Fields{
 		"repo":  repoURL,
 		"chart": chartName,
 	}).Debug("Checking OCI registry for tags")
 
 	// Parse OCI registry URL and build repository path
-	registry, repoPath := parseOCIURL(repoURL)
-
-	// Build full repository path: repoPath/chartName
-	var fullRepoPath string
-	if repoPath != "" {
-		fullRepoPath = fmt.Sprintf("%s/%s", repoPath, chartName)
-	} else {
-		fullRepoPath = chartName
-	}
+	registry, fullRepoPath := ociRepositoryPath(repoURL, chartName)
 
 	o.logger.WithFields(logrus.Fields{
 		"registry":       registry,
-		"repo_path":      repoPath,
 		"full_repo_path": fullRepoPath,
 	}).Debug("Parsed OCI URL")
 
-	// Determine the scheme - default to https unless explicitly http for localhost/testing
-	scheme := "https"
-	if strings.HasPrefix(registry, "localhost") || strings.HasPrefix(registry, "127.0.0.1") {
-		// Allow http for localhost/testing
-		scheme = "http"
-	}
-
 	// Build Docker Registry API v2 endpoint
-	tagsURL := fmt.Sprintf("%s://%s/v2/%s/tags/list", scheme, registry, fullRepoPath)
+	tagsURL := fmt.Sprintf("%s/v2/%s/tags/list", registryBaseURL(registry), fullRepoPath)
+
+	var tags []string
+	for page := 1; tagsURL != ""; page++ {
+		if page > maxTagPages {
+			return nil, fmt.Errorf("OCI registry returned more than %d pages of tags", maxTagPages)
+		}
 
-	o.logger.WithField("url", tagsURL).Debug("Fetching tags from OCI registry")
+		o.logger.WithFields(logrus.Fields{"url": tagsURL, "page": page}).Debug("Fetching tags from OCI registry")
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", tagsURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		var pageTags []string
+		var next string
+		err := o.retry.do(ctx, o.logger.WithField("url", tagsURL), func() (err error) {
+			pageTags, next, err = o.fetchTagsPage(ctx, tagsURL, registry, chartName)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, pageTags...)
+		tagsURL = next
 	}
 
-	// Set headers
-	req.Header.Set("User-Agent", "argazer/1.0")
-	req.Header.Set("Accept", "application/json")
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("%w: no tags found for chart %s in OCI registry", ErrNoValidVersions, chartName)
+	}
 
-	// Add authentication if available
-	creds := o.authProvider.GetCredentials(registry)
-	if creds != nil {
-		req.SetBasicAuth(creds.Username, creds.Password)
-		o.logger.WithFields(logrus.Fields{
-			"source":   creds.Source,
-			"username": creds.Username,
-			"registry": registry,
-		}).Debug("Using authentication for OCI registry")
-	} else {
-		o.logger.WithField("registry", registry).Debug("No credentials found, trying anonymous access")
+	o.logger.WithFields(logrus.Fields{
+		"chart":      chartName,
+		"tags_count": len(tags),
+		"tags":       tags,
+	}).Debug("Retrieved tags from OCI registry")
+
+	// Filter out non-version tags before finding latest
+	candidateTags := o.tagExclusions.For(repoURL).Filter(tags)
+
+	if len(candidateTags) == 0 {
+		return nil, fmt.Errorf("%w: all tags were filtered out", ErrNoValidVersions)
 	}
 
-	// Make request
-	resp, err := o.httpClient.Do(req)
+	return candidateTags, nil
+}
+
+// fetchTagsPage fetches one page of a repository's tags list
+// Registries such as Harbor and ECR paginate the list; the next page's URL comes from the Link header
+// and is empty on the last page.
+func (o *OCIChecker) fetchTagsPage(ctx context.Context, tagsURL, registry, chartName string) ([]string, string, error) {
+	resp, creds, err := o.registryRequest(ctx, "GET", tagsURL, "application/json", registry)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch tags from OCI registry: %w", err)
+		return nil, "", fmt.Errorf("failed to fetch tags from OCI registry: %w", err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -111,61 +136,161 @@ func (o *OCIChecker) getTagsFromOCI(ctx context.Context, repoURL, chartName stri
 	// Check response status
 	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
 		if creds != nil {
-			return nil, fmt.Errorf("%w for %s (status %d): check credentials", ErrAuthenticationFailed, registry, resp.StatusCode)
+			return nil, "", fmt.Errorf("%w for %s (status %d): check credentials", ErrAuthenticationFailed, registry, resp.StatusCode)
 		}
-		return nil, fmt.Errorf("%w for %s (status %d): set AG_AUTH_* environment variables or add to repository_auth in config file", ErrAuthenticationFailed, registry, resp.StatusCode)
+		return nil, "", fmt.Errorf("%w for %s (status %d): set AG_AUTH_* environment variables or add to repository_auth in config file", ErrAuthenticationFailed, registry, resp.StatusCode)
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("%w: %s/%s", ErrChartNotFound, registry, chartName)
+		return nil, "", fmt.Errorf("%w: %s/%s", ErrChartNotFound, registry, chartName)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, "", fmt.Errorf("%w: OCI registry returned status %d", ErrRepositoryUnavailable, resp.StatusCode)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OCI registry returned status %d", resp.StatusCode)
+		return nil, "", fmt.Errorf("OCI registry returned status %d", resp.StatusCode)
 	}
 
 	// Parse response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	var tagsResp TagsResponse
 	if err := json.Unmarshal(body, &tagsResp); err != nil {
-		return nil, fmt.Errorf("failed to parse tags response: %w", err)
+		return nil, "", fmt.Errorf("failed to parse tags response: %w", err)
 	}
 
-	if len(tagsResp.Tags) == 0 {
-		return nil, fmt.Errorf("no tags found for chart %s in OCI registry", chartName)
+	next, err := nextPageURL(tagsURL, resp.Header.Values("Link"))
+	if err != nil {
+		return nil, "", err
 	}
+	return tagsResp.Tags, next, nil
+}
 
-	o.logger.WithFields(logrus.Fields{
-		"chart":      chartName,
-		"tags_count": len(tagsResp.Tags),
-		"tags":       tagsResp.Tags,
-	}).Debug("Retrieved tags from OCI registry")
+// nextPageURL returns the URL of the rel="next" link in Link headers, resolved against the
+// request URL (registries send paths such as </v2/name/tags/list?n=100&last=1.2.3>), or ""
+func nextPageURL(reqURL string, links []string) (string, error) {
+	for _, header := range links {
+		for _, link := range strings.Split(header, ",") {
+			target, params, ok := strings.Cut(link, ";")
+			if !ok {
+				continue
+			}
+			target = strings.TrimSpace(target)
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			isNext := false
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.EqualFold(key, "rel") && strings.Trim(value, `"`) == "next" {
+					isNext = true
+				}
+			}
+			if !isNext {
+				continue
+			}
+
+			base, err := url.Parse(reqURL)
+			if err != nil {
+				return "", fmt.Errorf("invalid tags URL %q: %w", reqURL, err)
+			}
+			ref, err := url.Parse(target[1 : len(target)-1])
+			if err != nil {
+				return "", fmt.Errorf("invalid Link header %q: %w", header, err)
+			}
+			return base.ResolveReference(ref).String(), nil
+		}
+	}
+	return "", nil
+}
 
-	// Filter out common non-version tags before finding latest
-	var candidateTags []string
-	excludedTags := map[string]bool{
-		"latest": true,
-		"dev":    true,
-		"main":   true,
-		"master": true,
-		"stable": true,
+// registryBaseURL returns the scheme and host for Docker Registry API v2 requests
+// The scheme defaults to https unless the registry is localhost (used for testing). Docker Hub's
+// registry API is served from registry-1.docker.io rather than docker.io.
+func registryBaseURL(registry string) string {
+	if strings.HasPrefix(registry, "localhost") || strings.HasPrefix(registry, "127.0.0.1") {
+		return "http://" + registry
+	}
+	if registry == "docker.io" || registry == "index.docker.io" {
+		return "https://registry-1.docker.io"
 	}
+	return "https://" + registry
+}
 
-	for _, tag := range tagsResp.Tags {
-		if !excludedTags[tag] {
-			candidateTags = append(candidateTags, tag)
-		}
+// registryRequest performs an authenticated request against an OCI registry
+// Credentials are sent as basic auth, or exchanged for a bearer token when the registry answers with
+// a Bearer challenge (Docker Registry v2 token flow, required by GHCR, Docker Hub and others). Tokens
+// are cached per repository. The returned credentials are nil when the request was made anonymously.
+func (o *OCIChecker) registryRequest(ctx context.Context, method, reqURL, accept, registry string) (*http.Response, *auth.Credentials, error) {
+	creds := o.authProvider.GetCredentials(registry)
+	if creds != nil {
+		o.logger.WithFields(logrus.Fields{
+			"source":   creds.Source,
+			"username": creds.Username,
+			"registry": registry,
+		}).Debug("Using authentication for OCI registry")
+	} else {
+		o.logger.WithField("registry", registry).Debug("No credentials found, trying anonymous access")
 	}
 
-	if len(candidateTags) == 0 {
-		return nil, fmt.Errorf("%w: all tags were filtered out", ErrNoValidVersions)
+	scope := repositoryScope(reqURL)
+	cacheKey := tokenCacheKey(registry, scope)
+	token, _ := o.tokens.get(cacheKey, time.Now())
+
+	resp, err := o.doRegistryRequest(ctx, method, reqURL, accept, creds, token)
+	if err != nil {
+		return nil, creds, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, creds, nil
 	}
 
-	return candidateTags, nil
+	challenge, ok := parseBearerChallenge(resp.Header.Get("WWW-Authenticate"))
+	if !ok {
+		return resp, creds, nil
+	}
+	if err := resp.Body.Close(); err != nil {
+		o.logger.WithError(err).Warn("Failed to close response body")
+	}
+
+	issued, err := o.fetchRegistryToken(ctx, challenge, scope, registry, creds)
+	if err != nil {
+		return nil, creds, err
+	}
+	o.tokens.set(cacheKey, issued)
+
+	resp, err = o.doRegistryRequest(ctx, method, reqURL, accept, creds, issued.value)
+	if err != nil {
+		return nil, creds, err
+	}
+	return resp, creds, nil
+}
+
+// doRegistryRequest sends a single registry request with a bearer token, or basic auth without one
+func (o *OCIChecker) doRegistryRequest(ctx context.Context, method, reqURL, accept string, creds *auth.Credentials, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("User-Agent", "argazer/1.0")
+	req.Header.Set("Accept", accept)
+
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case creds != nil:
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	return o.httpClient.Do(req)
 }
 
 // GetLatestVersion gets the latest version of a Helm chart from an OCI registry
@@ -223,6 +348,15 @@ func (o *OCIChecker) GetLatestVersionWithConstraint(ctx context.Context, repoURL
 	return result, nil
 }
 
+// ociRepositoryPath returns the registry and full repository path (repoPath/chartName) of a chart
+func ociRepositoryPath(repoURL, chartName string) (registry string, fullRepoPath string) {
+	registry, repoPath := parseOCIURL(repoURL)
+	if repoPath != "" {
+		return registry, fmt.Sprintf("%s/%s", repoPath, chartName)
+	}
+	return registry, chartName
+}
+
 // parseOCIURL parses an OCI registry URL into registry and repository path
 // Examples:
 //   - "ghcr.io/myorg/charts" -> registry: "ghcr.io", repoPath: "myorg/charts"
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"argazer/internal/auth"
@@ -201,6 +202,41 @@ func TestOCICheckerGetLatestVersion_MixedValidInvalid(t *testing.T) {
 	}
 }
 
+// TestOCICheckerGetLatestVersion_TagExclusions tests that configured tag exclusions are applied
+func TestOCICheckerGetLatestVersion_TagExclusions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tagsJSON := `{
+  "name": "myrepo/app",
+  "tags": ["1.0.0", "1.1.0", "2.0.0-nightly.20250101", "latest"]
+}`
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, tagsJSON)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, _ := NewChecker(authProvider, logger)
+
+	filter, err := NewTagFilter(DefaultExcludedTags, []string{`-nightly\.`})
+	if err != nil {
+		t.Fatalf("NewTagFilter failed: %v", err)
+	}
+	checker.SetTagExclusions(NewTagExclusions(filter))
+
+	ctx := context.Background()
+	repoURL := server.URL[7:]
+	version, err := checker.GetLatestVersion(ctx, repoURL, "app")
+	if err != nil {
+		t.Fatalf("GetLatestVersion failed: %v", err)
+	}
+
+	expected := "1.1.0"
+	if version != expected {
+		t.Errorf("Expected version %s, got %s", expected, version)
+	}
+}
+
 // TestOCICheckerGetLatestVersion_WithAuthentication tests that auth is applied
 func TestOCICheckerGetLatestVersion_WithAuthentication(t *testing.T) {
 	receivedAuth := false
@@ -268,3 +304,253 @@ func TestParseOCIURL(t *testing.T) {
 		})
 	}
 }
+
+// newTokenAuthRegistry starts a registry that rejects requests without a bearer token and issues
+// tokens from /token, counting token requests and recording the basic auth sent to the auth service
+func newTokenAuthRegistry(t *testing.T, requireCreds bool) (*httptest.Server, *int, *string) {
+	t.Helper()
+	tokenRequests := 0
+	tokenAuth := ""
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			tokenAuth = r.Header.Get("Authorization")
+			if requireCreds && tokenAuth == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("service") != "test-registry" || r.URL.Query().Get("scope") != "repository:myrepo/app:pull" {
+				t.Errorf("unexpected token request query: %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"token": "registry-token", "expires_in": 300}`)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer registry-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test-registry",scope="repository:myrepo/app:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"name": "myrepo/app", "tags": ["1.0.0", "1.1.0"]}`)
+	}))
+	return server, &tokenRequests, &tokenAuth
+}
+
+// TestOCICheckerGetLatestVersion_AnonymousToken tests the token flow without credentials
+func TestOCICheckerGetLatestVersion_AnonymousToken(t *testing.T) {
+	server, tokenRequests, tokenAuth := newTokenAuthRegistry(t, false)
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker := NewOCIChecker(authProvider, logger)
+
+	repoURL := server.URL[7:] + "/myrepo"
+	for i := 0; i < 2; i++ {
+		version, err := checker.GetLatestVersion(context.Background(), repoURL, "app")
+		if err != nil {
+			t.Fatalf("GetLatestVersion failed: %v", err)
+		}
+		if version != "1.1.0" {
+			t.Errorf("Expected version 1.1.0, got %s", version)
+		}
+	}
+
+	if *tokenRequests != 1 {
+		t.Errorf("Expected the token to be requested once and cached, got %d requests", *tokenRequests)
+	}
+	if *tokenAuth != "" {
+		t.Errorf("Expected an anonymous token request, got Authorization %q", *tokenAuth)
+	}
+}
+
+// TestOCICheckerGetLatestVersion_CredentialedToken tests that credentials are sent to the auth service
+func TestOCICheckerGetLatestVersion_CredentialedToken(t *testing.T) {
+	server, _, tokenAuth := newTokenAuthRegistry(t, true)
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	serverHost := server.URL[7:]
+	authProvider, _ := auth.NewProvider([]auth.ConfigAuth{
+		{URL: serverHost, Username: "testuser", Password: "testpass"},
+	}, logger)
+	checker := NewOCIChecker(authProvider, logger)
+
+	version, err := checker.GetLatestVersion(context.Background(), serverHost+"/myrepo", "app")
+	if err != nil {
+		t.Fatalf("GetLatestVersion failed: %v", err)
+	}
+	if version != "1.1.0" {
+		t.Errorf("Expected version 1.1.0, got %s", version)
+	}
+	if !strings.HasPrefix(*tokenAuth, "Basic ") {
+		t.Errorf("Expected basic auth on the token request, got %q", *tokenAuth)
+	}
+}
+
+// TestOCICheckerGetLatestVersion_TokenDenied tests that a rejected token request fails authentication
+func TestOCICheckerGetLatestVersion_TokenDenied(t *testing.T) {
+	server, _, _ := newTokenAuthRegistry(t, true)
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker := NewOCIChecker(authProvider, logger)
+
+	_, err := checker.GetLatestVersion(context.Background(), server.URL[7:]+"/myrepo", "app")
+	if !errors.Is(err, ErrAuthenticationFailed) {
+		t.Errorf("Expected ErrAuthenticationFailed, got %v", err)
+	}
+}
+
+// TestParseBearerChallenge tests WWW-Authenticate header parsing
+func TestParseBearerChallenge(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bearerChallenge
+		ok     bool
+	}{
+		{
+			name:   "ghcr",
+			header: `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:org/chart:pull"`,
+			want:   bearerChallenge{Realm: "https://ghcr.io/token", Service: "ghcr.io", Scope: "repository:org/chart:pull"},
+			ok:     true,
+		},
+		{
+			name:   "comma in quoted scope",
+			header: `Bearer realm="https://auth.example.com/token", scope="repository:a/b:pull,push", service="example"`,
+			want:   bearerChallenge{Realm: "https://auth.example.com/token", Service: "example", Scope: "repository:a/b:pull,push"},
+			ok:     true,
+		},
+		{
+			name:   "unquoted values",
+			header: `bearer realm=https://auth.example.com/token,service=example`,
+			want:   bearerChallenge{Realm: "https://auth.example.com/token", Service: "example"},
+			ok:     true,
+		},
+		{
+			name:   "basic challenge",
+			header: `Basic realm="Registry"`,
+		},
+		{
+			name:   "no realm",
+			header: `Bearer service="example"`,
+		},
+		{
+			name:   "empty",
+			header: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseBearerChallenge(tt.header)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseBearerChallenge(%q) = %+v, %v; want %+v, %v", tt.header, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// TestRepositoryScope tests deriving the pull scope from registry API URLs
+func TestRepositoryScope(t *testing.T) {
+	tests := map[string]string{
+		"https://ghcr.io/v2/org/charts/app/tags/list": "repository:org/charts/app:pull",
+		"https://ghcr.io/v2/org/app/manifests/1.0.0":  "repository:org/app:pull",
+		"https://ghcr.io/v2/org/app/blobs/sha256:abc": "repository:org/app:pull",
+		"https://ghcr.io/token":                       "",
+	}
+	for reqURL, want := range tests {
+		if got := repositoryScope(reqURL); got != want {
+			t.Errorf("repositoryScope(%q) = %q, want %q", reqURL, got, want)
+		}
+	}
+}
+
+// TestOCICheckerGetLatestVersion_Paginated tests that tags from every page of a paginated list are used
+func TestOCICheckerGetLatestVersion_Paginated(t *testing.T) {
+	pages := map[string]struct {
+		tags string
+		next string
+	}{
+		"":      {tags: `["1.0.0", "1.1.0"]`, next: "1.1.0"},
+		"1.1.0": {tags: `["1.2.0", "2.0.0"]`, next: "2.0.0"},
+		"2.0.0": {tags: `["2.1.0"]`},
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, ok := pages[r.URL.Query().Get("last")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if page.next != "" {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/myrepo/app/tags/list?n=2&last=%s>; rel="next"`, page.next))
+		}
+		fmt.Fprintf(w, `{"name": "myrepo/app", "tags": %s}`, page.tags)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker := NewOCIChecker(authProvider, logger)
+
+	version, err := checker.GetLatestVersion(context.Background(), server.URL[7:]+"/myrepo", "app")
+	if err != nil {
+		t.Fatalf("GetLatestVersion failed: %v", err)
+	}
+	if version != "2.1.0" {
+		t.Errorf("Expected version 2.1.0 from the last page, got %s", version)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 page requests, got %d", requests)
+	}
+}
+
+// TestNextPageURL tests following Link headers of paginated tags lists
+func TestNextPageURL(t *testing.T) {
+	const reqURL = "https://harbor.example.com/v2/helm/app/tags/list"
+	tests := []struct {
+		name  string
+		links []string
+		want  string
+	}{
+		{
+			name:  "relative path",
+			links: []string{`</v2/helm/app/tags/list?n=100&last=1.2.3>; rel="next"`},
+			want:  "https://harbor.example.com/v2/helm/app/tags/list?n=100&last=1.2.3",
+		},
+		{
+			name:  "absolute URL, unquoted rel",
+			links: []string{`<https://other.example.com/v2/helm/app/tags/list?last=x>; rel=next`},
+			want:  "https://other.example.com/v2/helm/app/tags/list?last=x",
+		},
+		{
+			name:  "several links",
+			links: []string{`</first>; rel="first", </v2/helm/app/tags/list?last=a>; rel="next"`},
+			want:  "https://harbor.example.com/v2/helm/app/tags/list?last=a",
+		},
+		{
+			name:  "no next link",
+			links: []string{`</first>; rel="first"`},
+		},
+		{
+			name: "no header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextPageURL(reqURL, tt.links)
+			if err != nil {
+				t.Fatalf("nextPageURL failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("nextPageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
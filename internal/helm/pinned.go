@@ -0,0 +1,278 @@
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// Pinning kinds reported in VersionConstraintResult.PinnedBy
+const (
+	PinnedByDigest = "digest"
+	PinnedByCommit = "commit"
+)
+
+// OCI media types, annotations and headers used when resolving chart digests
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	manifestAccept       = ociManifestMediaType + ", application/vnd.docker.distribution.manifest.v2+json"
+	helmConfigMediaType  = "application/vnd.cncf.helm.config.v1+json"
+	ociVersionAnnotation = "org.opencontainers.image.version"
+	dockerDigestHeader   = "Docker-Content-Digest"
+)
+
+// Limits for digest resolution requests
+const (
+	maxDigestTagLookups   = 100     // Tags to compare before giving up on a digest
+	maxManifestBodyLength = 4 << 20 // Manifests and config blobs are small; cap reads at 4 MiB
+)
+
+// commitSHAPattern matches full or abbreviated Git commit SHAs
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// ociManifest represents the fields of an OCI image manifest used for digest resolution and chart downloads
+type ociManifest struct {
+	Config struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	} `json:"config"`
+	Layers []struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	} `json:"layers"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// helmChartConfig represents the Helm chart config blob stored in OCI registries
+type helmChartConfig struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// splitDigestRevision extracts the digest from a revision such as "sha256:abc" or "1.2.3@sha256:abc"
+// Returns the version prefix (if any) and the digest, or an empty digest if the revision is not a digest
+func splitDigestRevision(revision string) (version string, digest string) {
+	if i := strings.Index(revision, "@sha256:"); i >= 0 {
+		return revision[:i], revision[i+1:]
+	}
+	if strings.HasPrefix(revision, "sha256:") {
+		return "", revision
+	}
+	return "", ""
+}
+
+// isCommitSHA reports whether a revision looks like a Git commit SHA rather than a version or branch
+func isCommitSHA(revision string) bool {
+	if !commitSHAPattern.MatchString(revision) {
+		return false
+	}
+	// Purely numeric revisions are more likely versions than abbreviated SHAs
+	if _, err := semver.NewVersion(revision); err == nil && strings.Trim(revision, "0123456789") == "" {
+		return false
+	}
+	return true
+}
+
+// ResolveDigest maps a chart digest to its version
+// It reads the version from the manifest annotations or the Helm config blob, and falls
+// back to comparing the digest against the manifests of the repository's tags
+func (o *OCIChecker) ResolveDigest(ctx context.Context, repoURL, chartName, digest string) (string, error) {
+	registry, fullRepoPath := ociRepositoryPath(repoURL, chartName)
+	baseURL := registryBaseURL(registry)
+
+	o.logger.WithFields(logrus.Fields{
+		"registry": registry,
+		"chart":    chartName,
+		"digest":   digest,
+	}).Debug("Resolving chart digest")
+
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", baseURL, fullRepoPath, digest)
+	body, _, err := o.fetchRegistryBlob(ctx, manifestURL, manifestAccept, registry)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch manifest for %s: %w", digest, err)
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if version := manifest.Annotations[ociVersionAnnotation]; version != "" {
+		return version, nil
+	}
+
+	if manifest.Config.MediaType == helmConfigMediaType && manifest.Config.Digest != "" {
+		blobURL := fmt.Sprintf("%s/v2/%s/blobs/%s", baseURL, fullRepoPath, manifest.Config.Digest)
+		configBody, _, err := o.fetchRegistryBlob(ctx, blobURL, helmConfigMediaType, registry)
+		if err == nil {
+			var chartConfig helmChartConfig
+			if err := json.Unmarshal(configBody, &chartConfig); err == nil && chartConfig.Version != "" {
+				return chartConfig.Version, nil
+			}
+		} else {
+			o.logger.WithError(err).Debug("Failed to fetch Helm config blob, falling back to tag lookup")
+		}
+	}
+
+	return o.findTagForDigest(ctx, repoURL, chartName, digest)
+}
+
+// findTagForDigest looks for a tag whose manifest digest matches the given digest
+func (o *OCIChecker) findTagForDigest(ctx context.Context, repoURL, chartName, digest string) (string, error) {
+	tags, err := o.getTagsFromOCI(ctx, repoURL, chartName)
+	if err != nil {
+		return "", err
+	}
+
+	registry, fullRepoPath := ociRepositoryPath(repoURL, chartName)
+	baseURL := registryBaseURL(registry)
+
+	// Check the newest versions first: pinned and mutable tags usually point at recent releases
+	var versions []*semver.Version
+	byVersion := make(map[*semver.Version]string, len(tags))
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+		byVersion[v] = tag
+	}
+	sort.Sort(sort.Reverse(semver.Collection(versions)))
+
+	for i, v := range versions {
+		if i >= maxDigestTagLookups {
+			break
+		}
+		tag := byVersion[v]
+
+		manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", baseURL, fullRepoPath, tag)
+		_, tagDigest, err := o.fetchRegistryBlob(ctx, manifestURL, manifestAccept, registry)
+		if err != nil {
+			o.logger.WithError(err).WithField("tag", tag).Debug("Failed to fetch manifest for tag")
+			continue
+		}
+		if tagDigest == digest {
+			return tag, nil
+		}
+	}
+
+	return "", fmt.Errorf("no tag found for digest %s", digest)
+}
+
+// fetchRegistryBlob fetches a manifest or blob and returns its body and content digest
+func (o *OCIChecker) fetchRegistryBlob(ctx context.Context, reqURL, accept, registry string) ([]byte, string, error) {
+	resp, _, err := o.registryRequest(ctx, "GET", reqURL, accept, registry)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			o.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, "", fmt.Errorf("%w for %s (status %d)", ErrAuthenticationFailed, registry, resp.StatusCode)
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, "", fmt.Errorf("%w: OCI registry returned status %d", ErrRepositoryUnavailable, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("OCI registry returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestBodyLength))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Registries should report the digest; compute it from the body otherwise
+	digest := resp.Header.Get(dockerDigestHeader)
+	if digest == "" {
+		sum := sha256.Sum256(body)
+		digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	return body, digest, nil
+}
+
+// ResolveTag maps a mutable tag such as "latest" or "stable" to the semver tag sharing its digest
+// Returns the concrete version and the digest the mutable tag currently points at
+func (o *OCIChecker) ResolveTag(ctx context.Context, repoURL, chartName, tag string) (string, string, error) {
+	registry, fullRepoPath := ociRepositoryPath(repoURL, chartName)
+
+	o.logger.WithFields(logrus.Fields{
+		"registry": registry,
+		"chart":    chartName,
+		"tag":      tag,
+	}).Debug("Resolving mutable tag")
+
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", registryBaseURL(registry), fullRepoPath, tag)
+	_, digest, err := o.fetchRegistryBlob(ctx, manifestURL, manifestAccept, registry)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch manifest for tag %s: %w", tag, err)
+	}
+
+	version, err := o.findTagForDigest(ctx, repoURL, chartName, digest)
+	if err != nil {
+		return "", "", fmt.Errorf("tag %s does not match any version: %w", tag, err)
+	}
+
+	return version, digest, nil
+}
+
+// isMutableTag reports whether an OCI revision is a non-version tag that may move over time
+func isMutableTag(revision string) bool {
+	if revision == "" {
+		return false
+	}
+	if _, digest := splitDigestRevision(revision); digest != "" {
+		return false
+	}
+	_, err := semver.NewVersion(revision)
+	return err != nil
+}
+
+// resolvePinnedRevision resolves digests (OCI) and commit SHAs (Git) to chart versions
+// Returns the resolved version and the pinning kind, or empty strings if the revision isn't pinned
+func (c *Checker) resolvePinnedRevision(ctx context.Context, repoURL, chartName, revision string) (string, string, error) {
+	if isGitURL(repoURL) {
+		if !isCommitSHA(revision) {
+			return "", "", nil
+		}
+		version, err := c.gitClient.ResolveCommit(ctx, repoURL, chartName, revision)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to resolve commit %s: %w", revision, err)
+		}
+		return version, PinnedByCommit, nil
+	}
+
+	versionPrefix, digest := splitDigestRevision(revision)
+	if digest == "" {
+		return "", "", nil
+	}
+	// "1.2.3@sha256:..." already carries the version
+	if versionPrefix != "" {
+		return versionPrefix, PinnedByDigest, nil
+	}
+	if strings.HasPrefix(repoURL, "http://") || strings.HasPrefix(repoURL, "https://") {
+		return "", "", fmt.Errorf("digest pinning is only supported for OCI registries")
+	}
+
+	version, err := c.ociChecker.ResolveDigest(ctx, repoURL, chartName, digest)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve digest %s: %w", digest, err)
+	}
+	return version, PinnedByDigest, nil
+}
@@ -0,0 +1,279 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testDigest = "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+
+// TestSplitDigestRevision tests extraction of digests from target revisions
+func TestSplitDigestRevision(t *testing.T) {
+	tests := []struct {
+		revision        string
+		expectedVersion string
+		expectedDigest  string
+	}{
+		{revision: testDigest, expectedVersion: "", expectedDigest: testDigest},
+		{revision: "1.2.3@" + testDigest, expectedVersion: "1.2.3", expectedDigest: testDigest},
+		{revision: "1.2.3", expectedVersion: "", expectedDigest: ""},
+		{revision: "latest", expectedVersion: "", expectedDigest: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.revision, func(t *testing.T) {
+			version, digest := splitDigestRevision(tt.revision)
+			if version != tt.expectedVersion || digest != tt.expectedDigest {
+				t.Errorf("splitDigestRevision(%q) = (%q, %q), expected (%q, %q)",
+					tt.revision, version, digest, tt.expectedVersion, tt.expectedDigest)
+			}
+		})
+	}
+}
+
+// TestIsCommitSHA tests detection of Git commit SHAs
+func TestIsCommitSHA(t *testing.T) {
+	tests := []struct {
+		revision string
+		expected bool
+	}{
+		{revision: "a94a8fe5ccb19ba61c4c0873d391e987982fbbd3", expected: true},
+		{revision: "a94a8fe", expected: true},
+		{revision: "1.2.3", expected: false},
+		{revision: "v1.2.3", expected: false},
+		{revision: "main", expected: false},
+		{revision: "HEAD", expected: false},
+		{revision: "1234567", expected: false}, // Purely numeric, treat as version
+		{revision: "a94a8f", expected: false},  // Too short
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.revision, func(t *testing.T) {
+			if got := isCommitSHA(tt.revision); got != tt.expected {
+				t.Errorf("isCommitSHA(%q) = %v, expected %v", tt.revision, got, tt.expected)
+			}
+		})
+	}
+}
+
+// newTestOCIChecker creates an OCI checker without credentials
+func newTestOCIChecker() *OCIChecker {
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	return NewOCIChecker(authProvider, logger)
+}
+
+// TestOCICheckerResolveDigest_Annotation tests resolving a digest from the manifest version annotation
+func TestOCICheckerResolveDigest_Annotation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/myrepo/nginx/manifests/"+testDigest {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ociManifestMediaType)
+		fmt.Fprint(w, `{"annotations":{"org.opencontainers.image.version":"1.20.0"}}`)
+	}))
+	defer server.Close()
+
+	version, err := newTestOCIChecker().ResolveDigest(context.Background(), server.URL[7:]+"/myrepo", "nginx", testDigest)
+	if err != nil {
+		t.Fatalf("ResolveDigest failed: %v", err)
+	}
+	if version != "1.20.0" {
+		t.Errorf("Expected version 1.20.0, got %s", version)
+	}
+}
+
+// TestOCICheckerResolveDigest_ConfigBlob tests resolving a digest from the Helm chart config blob
+func TestOCICheckerResolveDigest_ConfigBlob(t *testing.T) {
+	configDigest := "sha256:8b1a9953c4611296a827abf8c47804d7e6c49c6b0b7e9e5c8f6b5e3c1c7e3e1a"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/myrepo/nginx/manifests/" + testDigest:
+			fmt.Fprintf(w, `{"config":{"mediaType":%q,"digest":%q}}`, helmConfigMediaType, configDigest)
+		case "/v2/myrepo/nginx/blobs/" + configDigest:
+			fmt.Fprint(w, `{"name":"nginx","version":"1.19.5"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	version, err := newTestOCIChecker().ResolveDigest(context.Background(), server.URL[7:]+"/myrepo", "nginx", testDigest)
+	if err != nil {
+		t.Fatalf("ResolveDigest failed: %v", err)
+	}
+	if version != "1.19.5" {
+		t.Errorf("Expected version 1.19.5, got %s", version)
+	}
+}
+
+// TestOCICheckerResolveDigest_TagLookup tests falling back to comparing tag digests
+func TestOCICheckerResolveDigest_TagLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/myrepo/nginx/manifests/" + testDigest:
+			fmt.Fprint(w, `{"config":{"mediaType":"application/octet-stream"}}`)
+		case "/v2/myrepo/nginx/tags/list":
+			fmt.Fprint(w, `{"name":"myrepo/nginx","tags":["1.21.0","1.20.0","latest"]}`)
+		case "/v2/myrepo/nginx/manifests/1.21.0":
+			w.Header().Set(dockerDigestHeader, "sha256:0000")
+			fmt.Fprint(w, `{}`)
+		case "/v2/myrepo/nginx/manifests/1.20.0":
+			w.Header().Set(dockerDigestHeader, testDigest)
+			fmt.Fprint(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	version, err := newTestOCIChecker().ResolveDigest(context.Background(), server.URL[7:]+"/myrepo", "nginx", testDigest)
+	if err != nil {
+		t.Fatalf("ResolveDigest failed: %v", err)
+	}
+	if version != "1.20.0" {
+		t.Errorf("Expected version 1.20.0, got %s", version)
+	}
+}
+
+// TestOCICheckerResolveDigest_NotFound tests handling of unknown digests
+func TestOCICheckerResolveDigest_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := newTestOCIChecker().ResolveDigest(context.Background(), server.URL[7:]+"/myrepo", "nginx", testDigest)
+	if err == nil {
+		t.Fatal("Expected error for unknown digest, got nil")
+	}
+}
+
+// TestCheckerGetLatestVersionWithConstraint_PinnedDigest tests comparing a digest-pinned chart by its resolved version
+func TestCheckerGetLatestVersionWithConstraint_PinnedDigest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/myrepo/nginx/manifests/" + testDigest:
+			fmt.Fprint(w, `{"annotations":{"org.opencontainers.image.version":"1.20.0"}}`)
+		case "/v2/myrepo/nginx/tags/list":
+			fmt.Fprint(w, `{"name":"myrepo/nginx","tags":["1.21.0","1.20.0","2.0.0"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, _ := NewChecker(authProvider, logger)
+
+	result, err := checker.GetLatestVersionWithConstraint(context.Background(), server.URL[7:]+"/myrepo", "nginx", testDigest, "minor")
+	if err != nil {
+		t.Fatalf("GetLatestVersionWithConstraint failed: %v", err)
+	}
+	if result.PinnedBy != PinnedByDigest {
+		t.Errorf("Expected PinnedBy %q, got %q", PinnedByDigest, result.PinnedBy)
+	}
+	if result.CurrentVersion != "1.20.0" {
+		t.Errorf("Expected CurrentVersion 1.20.0, got %s", result.CurrentVersion)
+	}
+	if result.LatestVersion != "1.21.0" {
+		t.Errorf("Expected LatestVersion 1.21.0, got %s", result.LatestVersion)
+	}
+	if !result.HasUpdateOutsideConstraint {
+		t.Error("Expected HasUpdateOutsideConstraint to be true")
+	}
+}
+
+// TestIsMutableTag tests detection of non-version OCI tags
+func TestIsMutableTag(t *testing.T) {
+	tests := []struct {
+		revision string
+		expected bool
+	}{
+		{revision: "latest", expected: true},
+		{revision: "stable", expected: true},
+		{revision: "1.2.3", expected: false},
+		{revision: "v1.2.3", expected: false},
+		{revision: testDigest, expected: false},
+		{revision: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.revision, func(t *testing.T) {
+			if got := isMutableTag(tt.revision); got != tt.expected {
+				t.Errorf("isMutableTag(%q) = %v, expected %v", tt.revision, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCheckerGetLatestVersionWithConstraint_MutableTag tests resolving "latest" to the semver tag sharing its digest
+func TestCheckerGetLatestVersionWithConstraint_MutableTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/myrepo/nginx/tags/list":
+			fmt.Fprint(w, `{"name":"myrepo/nginx","tags":["1.21.0","1.20.0","latest"]}`)
+		case "/v2/myrepo/nginx/manifests/latest", "/v2/myrepo/nginx/manifests/1.20.0":
+			w.Header().Set(dockerDigestHeader, testDigest)
+			fmt.Fprint(w, `{}`)
+		case "/v2/myrepo/nginx/manifests/1.21.0":
+			w.Header().Set(dockerDigestHeader, "sha256:0000")
+			fmt.Fprint(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, _ := NewChecker(authProvider, logger)
+
+	result, err := checker.GetLatestVersionWithConstraint(context.Background(), server.URL[7:]+"/myrepo", "nginx", "latest", "major")
+	if err != nil {
+		t.Fatalf("GetLatestVersionWithConstraint failed: %v", err)
+	}
+	if result.MutableTag != "latest" {
+		t.Errorf("Expected MutableTag latest, got %q", result.MutableTag)
+	}
+	if result.CurrentVersion != "1.20.0" {
+		t.Errorf("Expected CurrentVersion 1.20.0, got %s", result.CurrentVersion)
+	}
+	if result.LatestVersion != "1.21.0" {
+		t.Errorf("Expected LatestVersion 1.21.0, got %s", result.LatestVersion)
+	}
+}
+
+// TestOCICheckerResolveTag_NoMatch tests a mutable tag whose digest matches no version
+func TestOCICheckerResolveTag_NoMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/myrepo/nginx/tags/list":
+			fmt.Fprint(w, `{"name":"myrepo/nginx","tags":["1.20.0","latest"]}`)
+		case "/v2/myrepo/nginx/manifests/latest":
+			w.Header().Set(dockerDigestHeader, testDigest)
+			fmt.Fprint(w, `{}`)
+		case "/v2/myrepo/nginx/manifests/1.20.0":
+			w.Header().Set(dockerDigestHeader, "sha256:0000")
+			fmt.Fprint(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	_, _, err := newTestOCIChecker().ResolveTag(context.Background(), server.URL[7:]+"/myrepo", "nginx", "latest")
+	if err == nil {
+		t.Fatal("Expected error for unmatched tag, got nil")
+	}
+}
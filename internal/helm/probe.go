@@ -0,0 +1,166 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// Repository kinds, as told apart by their URL
+const (
+	RepositoryKindHelm = "helm"
+	RepositoryKindOCI  = "oci"
+	RepositoryKindGit  = "git"
+)
+
+// RepositoryKind returns the kind of repository a chart lookup goes to
+func RepositoryKind(repoURL string) string {
+	switch {
+	case isGitURL(repoURL):
+		return RepositoryKindGit
+	case isOCIRepository(repoURL):
+		return RepositoryKindOCI
+	}
+	return RepositoryKindHelm
+}
+
+// ProbeResult is the outcome of fetching a repository's version listing once
+type ProbeResult struct {
+	Kind    string        // RepositoryKindHelm, RepositoryKindOCI or RepositoryKindGit
+	Latency time.Duration // Time to fetch the whole listing
+	Bytes   int64         // Payload size; for Git, the size of the ref advertisement in pkt-line format
+}
+
+// Probe fetches the version listing of a chart's repository once, without parsing it: index.yaml for
+// Helm repositories, the tags list for OCI registries and the ref advertisement (ls-remote) for Git
+// The lookup timeout of the repository kind applies.
+func (c *Checker) Probe(ctx context.Context, repoURL, chartName string) (ProbeResult, error) {
+	repoURL = c.authProvider.ResolveRepoURL(repoURL)
+	result := ProbeResult{Kind: RepositoryKind(repoURL)}
+
+	ctx, done := c.withLookupTimeout(ctx, repoURL)
+	start := time.Now()
+	var err error
+	switch result.Kind {
+	case RepositoryKindGit:
+		result.Bytes, err = c.probeGit(ctx, repoURL)
+	case RepositoryKindOCI:
+		result.Bytes, err = c.ociChecker.probeTags(ctx, repoURL, chartName)
+	default:
+		result.Bytes, err = c.probeIndex(ctx, repoURL)
+	}
+	result.Latency = time.Since(start)
+	return result, done(err)
+}
+
+// probeIndex downloads a Helm repository's index.yaml and returns its size
+func (c *Checker) probeIndex(ctx context.Context, repoURL string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/index.yaml", repoURL), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "argazer/1.0")
+	req.Header.Set("Accept", "application/x-yaml, application/yaml, text/yaml")
+	if creds := c.authProvider.GetCredentials(repoURL); creds != nil {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch index: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("repository returned status %d for index.yaml", resp.StatusCode)
+	}
+	size, err := io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read index: %w", err)
+	}
+	return size, nil
+}
+
+// probeTags downloads the tags list of an OCI chart, following its pages, and returns its size
+func (o *OCIChecker) probeTags(ctx context.Context, repoURL, chartName string) (int64, error) {
+	registry, fullRepoPath := ociRepositoryPath(repoURL, chartName)
+	tagsURL := fmt.Sprintf("%s/v2/%s/tags/list", registryBaseURL(registry), fullRepoPath)
+
+	var total int64
+	for page := 1; tagsURL != ""; page++ {
+		if page > maxTagPages {
+			return 0, fmt.Errorf("OCI registry returned more than %d pages of tags", maxTagPages)
+		}
+		size, next, err := o.probeTagsPage(ctx, tagsURL, registry)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+		tagsURL = next
+	}
+	return total, nil
+}
+
+// probeTagsPage downloads one page of a tags list and returns its size and the next page's URL
+func (o *OCIChecker) probeTagsPage(ctx context.Context, tagsURL, registry string) (int64, string, error) {
+	resp, _, err := o.registryRequest(ctx, "GET", tagsURL, "application/json", registry)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to fetch tags from OCI registry: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			o.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", fmt.Errorf("OCI registry returned status %d", resp.StatusCode)
+	}
+	size, err := io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	next, err := nextPageURL(tagsURL, resp.Header.Values("Link"))
+	if err != nil {
+		return 0, "", err
+	}
+	return size, next, nil
+}
+
+// probeGit lists the refs of a Git repository like `git ls-remote` and returns the size of the
+// advertisement
+// go-git doesn't expose the raw response, so the size is computed from the refs: each one is a
+// pkt-line of a 4-byte length, the hash, a space, the name and a newline.
+func (c *Checker) probeGit(ctx context.Context, repoURL string) (int64, error) {
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{Name: "origin", URLs: []string{repoURL}})
+
+	listOpts := &git.ListOptions{}
+	if creds := c.authProvider.GetCredentials(repoURL); creds != nil {
+		authMethod, err := gitAuth(repoURL, creds)
+		if err != nil {
+			return 0, err
+		}
+		listOpts.Auth = authMethod
+	}
+
+	refs, err := remote.ListContext(ctx, listOpts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list remote refs: %w", err)
+	}
+
+	var size int64
+	for _, ref := range refs {
+		size += int64(4 + len(ref.Hash().String()) + 1 + len(ref.Name()) + 1)
+	}
+	return size, nil
+}
@@ -0,0 +1,81 @@
+package helm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRepositoryKind(t *testing.T) {
+	tests := []struct {
+		repoURL  string
+		expected string
+	}{
+		{repoURL: "https://charts.example.com", expected: RepositoryKindHelm},
+		{repoURL: "ghcr.io/org/charts", expected: RepositoryKindOCI},
+		{repoURL: "oci://registry.example.com/charts", expected: RepositoryKindOCI},
+		{repoURL: "https://github.com/org/charts.git", expected: RepositoryKindGit},
+		{repoURL: "git@github.com:org/charts.git", expected: RepositoryKindGit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repoURL, func(t *testing.T) {
+			if got := RepositoryKind(tt.repoURL); got != tt.expected {
+				t.Errorf("RepositoryKind(%s) = %s, want %s", tt.repoURL, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckerProbe(t *testing.T) {
+	index := "apiVersion: v1\nentries:\n  nginx:\n  - version: 1.0.0\n"
+	tags := `{"name":"charts/nginx","tags":["1.0.0","1.1.0"]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = w.Write([]byte(index))
+		case "/v2/charts/nginx/tags/list":
+			_, _ = w.Write([]byte(tags))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, _ := NewChecker(authProvider, logger)
+
+	t.Run("helm index", func(t *testing.T) {
+		result, err := checker.Probe(context.Background(), server.URL, "nginx")
+		if err != nil {
+			t.Fatalf("Probe failed: %v", err)
+		}
+		if result.Kind != RepositoryKindHelm || result.Bytes != int64(len(index)) || result.Latency <= 0 {
+			t.Errorf("Probe() = %+v, want helm with %d bytes", result, len(index))
+		}
+	})
+
+	t.Run("oci tags list", func(t *testing.T) {
+		result, err := checker.Probe(context.Background(), strings.TrimPrefix(server.URL, "http://")+"/charts", "nginx")
+		if err != nil {
+			t.Fatalf("Probe failed: %v", err)
+		}
+		if result.Kind != RepositoryKindOCI || result.Bytes != int64(len(tags)) {
+			t.Errorf("Probe() = %+v, want oci with %d bytes", result, len(tags))
+		}
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		if _, err := checker.Probe(context.Background(), server.URL+"/missing", "nginx"); err == nil {
+			t.Error("Expected an error for a repository without index.yaml")
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Limits for registry token requests
+const (
+	defaultTokenLifetime = 60 * time.Second // Lifetime assumed when the auth service doesn't report one
+	tokenExpiryMargin    = 10 * time.Second // Tokens are renewed this long before they expire
+	maxTokenBodyLength   = 1 << 20          // Token responses are small; cap reads at 1 MiB
+)
+
+// bearerChallenge is a parsed "WWW-Authenticate: Bearer" header of the Docker Registry v2 token flow
+type bearerChallenge struct {
+	Realm   string
+	Service string
+	Scope   string
+}
+
+// registryToken is a bearer token issued by a registry's auth service
+type registryToken struct {
+	value   string
+	expires time.Time
+}
+
+// tokenCache holds bearer tokens per registry and repository scope, shared by concurrent lookups
+type tokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]registryToken
+}
+
+// newTokenCache creates an empty token cache
+func newTokenCache() *tokenCache {
+	return &tokenCache{tokens: make(map[string]registryToken)}
+}
+
+// get returns a cached token that isn't about to expire
+func (c *tokenCache) get(key string, now time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token, ok := c.tokens[key]
+	if !ok || !now.Before(token.expires.Add(-tokenExpiryMargin)) {
+		return "", false
+	}
+	return token.value, true
+}
+
+// set caches a token
+func (c *tokenCache) set(key string, token registryToken) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[key] = token
+}
+
+// tokenResponse is the response of a registry auth service
+// Docker Hub and others send both token and access_token; OAuth2-style services only the latter.
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// parseBearerChallenge parses a WWW-Authenticate header, returning false unless it's a Bearer
+// challenge with a realm
+// Parameter values may be quoted and contain commas, e.g. scope="repository:a/b:pull,push".
+func parseBearerChallenge(header string) (bearerChallenge, bool) {
+	scheme, params, _ := strings.Cut(strings.TrimSpace(header), " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return bearerChallenge{}, false
+	}
+
+	var challenge bearerChallenge
+	rest := params
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		if rest == "" {
+			break
+		}
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				return bearerChallenge{}, false
+			}
+			rest = value[end+2:]
+			value = value[1 : end+1]
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+			value = strings.TrimSpace(value)
+		}
+
+		switch key {
+		case "realm":
+			challenge.Realm = value
+		case "service":
+			challenge.Service = value
+		case "scope":
+			challenge.Scope = value
+		}
+	}
+
+	if challenge.Realm == "" {
+		return bearerChallenge{}, false
+	}
+	return challenge, true
+}
+
+// repositoryScope returns the pull scope of the repository a Docker Registry API v2 URL refers to,
+// e.g. "repository:myorg/charts/app:pull" for .../v2/myorg/charts/app/tags/list
+func repositoryScope(reqURL string) string {
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		return ""
+	}
+	_, name, ok := strings.Cut(u.Path, "/v2/")
+	if !ok {
+		return ""
+	}
+	for _, suffix := range []string{"/tags/list", "/manifests/", "/blobs/"} {
+		if i := strings.Index(name, suffix); i >= 0 {
+			return "repository:" + name[:i] + ":pull"
+		}
+	}
+	return ""
+}
+
+// tokenCacheKey identifies the token for a repository of a registry
+func tokenCacheKey(registry, scope string) string {
+	return registry + "|" + scope
+}
+
+// fetchRegistryToken requests a bearer token from the auth service named in a challenge, with the
+// credentials (if any) sent as basic auth. The scope of the request is used when the challenge has none.
+func (o *OCIChecker) fetchRegistryToken(ctx context.Context, challenge bearerChallenge, scope, registry string, creds *auth.Credentials) (registryToken, error) {
+	tokenURL, err := url.Parse(challenge.Realm)
+	if err != nil {
+		return registryToken{}, fmt.Errorf("invalid token realm %q: %w", challenge.Realm, err)
+	}
+	if challenge.Scope != "" {
+		scope = challenge.Scope
+	}
+	query := tokenURL.Query()
+	if challenge.Service != "" {
+		query.Set("service", challenge.Service)
+	}
+	if scope != "" {
+		query.Set("scope", scope)
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", tokenURL.String(), nil)
+	if err != nil {
+		return registryToken{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("User-Agent", "argazer/1.0")
+	if creds != nil {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	o.logger.WithFields(logrus.Fields{
+		"registry":  registry,
+		"realm":     challenge.Realm,
+		"service":   challenge.Service,
+		"scope":     scope,
+		"anonymous": creds == nil,
+	}).Debug("Requesting bearer token for OCI registry")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return registryToken{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			o.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return registryToken{}, fmt.Errorf("%w for %s: auth service returned status %d", ErrAuthenticationFailed, registry, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return registryToken{}, fmt.Errorf("auth service returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenBodyLength))
+	if err != nil {
+		return registryToken{}, fmt.Errorf("failed to read token response: %w", err)
+	}
+	var tokenResp tokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return registryToken{}, fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	token := registryToken{value: tokenResp.Token}
+	if token.value == "" {
+		token.value = tokenResp.AccessToken
+	}
+	if token.value == "" {
+		return registryToken{}, fmt.Errorf("auth service returned no token")
+	}
+	lifetime := defaultTokenLifetime
+	if tokenResp.ExpiresIn > 0 {
+		lifetime = time.Duration(tokenResp.ExpiresIn) * time.Second
+	}
+	token.expires = time.Now().Add(lifetime)
+
+	return token, nil
+}
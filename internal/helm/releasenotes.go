@@ -0,0 +1,388 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// Artifact Hub Chart.yaml annotations describing a version's changes and related links
+const (
+	changesAnnotation = "artifacthub.io/changes"
+	linksAnnotation   = "artifacthub.io/links"
+)
+
+// Release APIs of the hosted Git platforms
+const (
+	releaseNotesGitHubAPIURL = "https://api.github.com"
+	releaseNotesGitLabAPIURL = "https://gitlab.com/api/v4"
+)
+
+// ReleaseNotes is an excerpt of the changes between two chart versions
+type ReleaseNotes struct {
+	Excerpt string // Changes of the newer versions, newest first, truncated to the configured length
+	URL     string // Page with the full release notes or changelog
+}
+
+// ReleaseNotesClient looks up the release notes of chart updates, from the Artifact Hub annotations
+// of the repository index or from the releases of the chart's GitHub or GitLab project
+type ReleaseNotesClient struct {
+	httpClient  *http.Client
+	githubAPI   string
+	gitlabAPI   string
+	githubToken string
+	gitlabToken string
+	maxLength   int
+	logger      *logrus.Entry
+}
+
+// NewReleaseNotesClient creates a release notes client keeping excerpts to maxLength characters
+// The tokens are optional and raise the API rate limits.
+func NewReleaseNotesClient(githubToken, gitlabToken string, maxLength int, logger *logrus.Entry) *ReleaseNotesClient {
+	return &ReleaseNotesClient{
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		githubAPI:   releaseNotesGitHubAPIURL,
+		gitlabAPI:   releaseNotesGitLabAPIURL,
+		githubToken: githubToken,
+		gitlabToken: gitlabToken,
+		maxLength:   maxLength,
+		logger:      logger,
+	}
+}
+
+// SetReleaseNotes enables release notes lookups with GetReleaseNotes
+func (c *Checker) SetReleaseNotes(client *ReleaseNotesClient) {
+	c.releaseNotes = client
+}
+
+// GetReleaseNotes returns the release notes of the versions after currentVersion up to result's
+// latest version, or nil if they aren't enabled or can't be found
+// Changes annotated in the repository index take precedence over GitHub and GitLab releases.
+func (c *Checker) GetReleaseNotes(ctx context.Context, repoURL, chartName, currentVersion string, result *VersionConstraintResult) (*ReleaseNotes, error) {
+	if c.releaseNotes == nil {
+		return nil, nil
+	}
+	repoURL = c.authProvider.ResolveRepoURL(repoURL)
+	return c.releaseNotes.Get(ctx, repoURL, chartName, currentVersion, result)
+}
+
+// Get returns the release notes of an update, or nil if neither the index nor a release provides them
+func (c *ReleaseNotesClient) Get(ctx context.Context, repoURL, chartName, currentVersion string, result *VersionConstraintResult) (*ReleaseNotes, error) {
+	// Charts in Git repositories are released by the repository itself
+	sources := result.Sources
+	if isGitURL(repoURL) {
+		sources = append([]string{repoURL}, sources...)
+	}
+	project := findReleaseProject(sources)
+
+	if result.Changes != "" {
+		notes := &ReleaseNotes{Excerpt: truncateExcerpt(result.Changes, c.maxLength), URL: result.ChangelogURL}
+		if notes.URL == "" && project != nil {
+			notes.URL = project.releasesURL()
+		}
+		return notes, nil
+	}
+	if project == nil {
+		return nil, nil
+	}
+
+	releases, err := c.listReleases(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases of %s: %w", project.path, err)
+	}
+
+	notes := releaseNotesBetween(releases, chartName, currentVersion, result.LatestVersion)
+	if notes == nil {
+		c.logger.WithFields(logrus.Fields{
+			"project":        project.path,
+			"chart":          chartName,
+			"latest_version": result.LatestVersion,
+		}).Debug("No release found for the update")
+		return nil, nil
+	}
+	notes.Excerpt = truncateExcerpt(notes.Excerpt, c.maxLength)
+	return notes, nil
+}
+
+// release is a GitHub or GitLab release
+type release struct {
+	Tag  string
+	Body string
+	URL  string
+}
+
+// releaseProject is a project on github.com or gitlab.com
+type releaseProject struct {
+	gitlab bool
+	path   string // owner/name on GitHub, namespace path on GitLab
+}
+
+// releasesURL returns the web page listing the project's releases
+func (p *releaseProject) releasesURL() string {
+	if p.gitlab {
+		return "https://gitlab.com/" + p.path + "/-/releases"
+	}
+	return "https://github.com/" + p.path + "/releases"
+}
+
+// findReleaseProject returns the first GitHub or GitLab project among the source URLs
+func findReleaseProject(sources []string) *releaseProject {
+	for _, source := range sources {
+		source = strings.TrimSuffix(strings.TrimSpace(source), "/")
+		if rest, ok := strings.CutPrefix(source, "git@"); ok {
+			source = "https://" + strings.Replace(rest, ":", "/", 1)
+		}
+		u, err := url.Parse(source)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+		switch strings.ToLower(u.Host) {
+		case "github.com", "www.github.com":
+			parts := strings.Split(path, "/")
+			if len(parts) >= 2 {
+				return &releaseProject{path: parts[0] + "/" + parts[1]}
+			}
+		case "gitlab.com":
+			// Subgroups make the project path open-ended, up to the "/-/" separator of its pages
+			path, _, _ = strings.Cut(path, "/-/")
+			if strings.Contains(path, "/") {
+				return &releaseProject{gitlab: true, path: path}
+			}
+		}
+	}
+	return nil
+}
+
+// listReleases fetches the most recent releases of a project
+func (c *ReleaseNotesClient) listReleases(ctx context.Context, project *releaseProject) ([]release, error) {
+	if project.gitlab {
+		var releases []struct {
+			TagName     string `json:"tag_name"`
+			Description string `json:"description"`
+			Links       struct {
+				Self string `json:"self"`
+			} `json:"_links"`
+		}
+		endpoint := fmt.Sprintf("%s/projects/%s/releases?per_page=100", c.gitlabAPI, url.PathEscape(project.path))
+		if err := c.getJSON(ctx, endpoint, "PRIVATE-TOKEN", c.gitlabToken, &releases); err != nil {
+			return nil, err
+		}
+		result := make([]release, 0, len(releases))
+		for _, r := range releases {
+			result = append(result, release{Tag: r.TagName, Body: r.Description, URL: r.Links.Self})
+		}
+		return result, nil
+	}
+
+	var releases []struct {
+		TagName string `json:"tag_name"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+		Draft   bool   `json:"draft"`
+	}
+	token := ""
+	if c.githubToken != "" {
+		token = "Bearer " + c.githubToken
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/releases?per_page=100", c.githubAPI, project.path)
+	if err := c.getJSON(ctx, endpoint, "Authorization", token, &releases); err != nil {
+		return nil, err
+	}
+	result := make([]release, 0, len(releases))
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		result = append(result, release{Tag: r.TagName, Body: r.Body, URL: r.HTMLURL})
+	}
+	return result, nil
+}
+
+// getJSON performs a GET request with an optional authentication header and decodes the JSON response
+func (c *ReleaseNotesClient) getJSON(ctx context.Context, endpoint, authHeader, authValue string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if authValue != "" {
+		req.Header.Set(authHeader, authValue)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode releases: %w", err)
+	}
+	return nil
+}
+
+// releaseNotesBetween combines the notes of the releases after currentVersion up to latestVersion,
+// newest first, linking to the latest one
+func releaseNotesBetween(releases []release, chartName, currentVersion, latestVersion string) *ReleaseNotes {
+	current, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return nil
+	}
+	latest, err := semver.NewVersion(latestVersion)
+	if err != nil {
+		return nil
+	}
+
+	type versionedRelease struct {
+		release
+		version *semver.Version
+	}
+	var matched []versionedRelease
+	for _, r := range releases {
+		version := releaseTagVersion(r.Tag, chartName)
+		if version == nil || !version.GreaterThan(current) || version.GreaterThan(latest) {
+			continue
+		}
+		matched = append(matched, versionedRelease{release: r, version: version})
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].version.GreaterThan(matched[j].version) })
+
+	var excerpt strings.Builder
+	for _, r := range matched {
+		if body := strings.TrimSpace(r.Body); body != "" {
+			fmt.Fprintf(&excerpt, "%s:\n%s\n", r.version, body)
+		}
+	}
+	return &ReleaseNotes{Excerpt: strings.TrimSpace(excerpt.String()), URL: matched[0].URL}
+}
+
+// releaseTagVersion returns the chart version a release tag stands for, e.g. "1.2.0" for "v1.2.0"
+// or "nginx-1.2.0" (the chart-releaser convention), or nil for other tags
+func releaseTagVersion(tag, chartName string) *semver.Version {
+	tag = strings.TrimPrefix(tag, chartName+"-")
+	version, err := semver.NewVersion(tag)
+	if err != nil {
+		return nil
+	}
+	return version
+}
+
+// artifactHubChanges formats the changes annotated on the versions after currentVersion up to
+// latestVersion, newest first, and returns the changelog link of the latest version if it has one
+func artifactHubChanges(entries []Entry, currentVersion, latestVersion string) (string, string) {
+	current, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return "", ""
+	}
+	latest, err := semver.NewVersion(latestVersion)
+	if err != nil {
+		return "", ""
+	}
+
+	type versionedEntry struct {
+		entry   Entry
+		version *semver.Version
+	}
+	var matched []versionedEntry
+	for _, entry := range entries {
+		version, err := semver.NewVersion(entry.Version)
+		if err != nil || !version.GreaterThan(current) || version.GreaterThan(latest) {
+			continue
+		}
+		matched = append(matched, versionedEntry{entry: entry, version: version})
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].version.GreaterThan(matched[j].version) })
+
+	var changes strings.Builder
+	changelogURL := ""
+	for _, m := range matched {
+		if m.version.Equal(latest) {
+			changelogURL = changelogLink(m.entry.Annotations[linksAnnotation])
+		}
+		lines := parseChanges(m.entry.Annotations[changesAnnotation])
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&changes, "%s:\n", m.entry.Version)
+		for _, line := range lines {
+			fmt.Fprintf(&changes, "- %s\n", line)
+		}
+	}
+	return strings.TrimSpace(changes.String()), changelogURL
+}
+
+// parseChanges parses an artifacthub.io/changes annotation, a YAML list of either plain
+// descriptions or objects with a kind and a description
+func parseChanges(annotation string) []string {
+	if annotation == "" {
+		return nil
+	}
+	var items []any
+	if err := yaml.Unmarshal([]byte(annotation), &items); err != nil {
+		return nil
+	}
+
+	var lines []string
+	for _, item := range items {
+		switch change := item.(type) {
+		case string:
+			lines = append(lines, change)
+		case map[any]any:
+			description, _ := change["description"].(string)
+			if description == "" {
+				continue
+			}
+			if kind, _ := change["kind"].(string); kind != "" {
+				description = kind + ": " + description
+			}
+			lines = append(lines, description)
+		}
+	}
+	return lines
+}
+
+// changelogLink returns the URL of a changelog or release notes link from an artifacthub.io/links annotation
+func changelogLink(annotation string) string {
+	var links []struct {
+		Name string `yaml:"name"`
+		URL  string `yaml:"url"`
+	}
+	if err := yaml.Unmarshal([]byte(annotation), &links); err != nil {
+		return ""
+	}
+	for _, link := range links {
+		name := strings.ToLower(link.Name)
+		if strings.Contains(name, "changelog") || strings.Contains(name, "release") {
+			return link.URL
+		}
+	}
+	return ""
+}
+
+// truncateExcerpt shortens release notes to at most limit characters, marking the cut with an ellipsis
+// A limit of 0 keeps the notes whole.
+func truncateExcerpt(text string, limit int) string {
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
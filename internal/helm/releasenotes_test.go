@@ -0,0 +1,182 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFindReleaseProject(t *testing.T) {
+	tests := []struct {
+		name     string
+		sources  []string
+		expected *releaseProject
+	}{
+		{name: "GitHub repository", sources: []string{"https://github.com/bitnami/charts/tree/main/bitnami/nginx"}, expected: &releaseProject{path: "bitnami/charts"}},
+		{name: "scp-like Git remote", sources: []string{"git@github.com:org/charts.git"}, expected: &releaseProject{path: "org/charts"}},
+		{name: "GitLab subgroup", sources: []string{"https://gitlab.com/group/sub/project/-/tree/main"}, expected: &releaseProject{gitlab: true, path: "group/sub/project"}},
+		{name: "first hosted project wins", sources: []string{"https://nginx.org", "https://github.com/nginx/nginx"}, expected: &releaseProject{path: "nginx/nginx"}},
+		{name: "no hosted project", sources: []string{"https://charts.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findReleaseProject(tt.sources)
+			if (got == nil) != (tt.expected == nil) || (got != nil && *got != *tt.expected) {
+				t.Errorf("findReleaseProject(%v) = %+v, want %+v", tt.sources, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReleaseNotesBetween(t *testing.T) {
+	releases := []release{
+		{Tag: "nginx-1.3.0", Body: "Breaking: new values layout", URL: "https://github.com/org/charts/releases/tag/nginx-1.3.0"},
+		{Tag: "redis-9.0.0", Body: "Other chart", URL: "https://github.com/org/charts/releases/tag/redis-9.0.0"},
+		{Tag: "nginx-1.2.0", Body: "Fix probes\n", URL: "https://github.com/org/charts/releases/tag/nginx-1.2.0"},
+		{Tag: "nginx-1.1.0", Body: "Already deployed", URL: "https://github.com/org/charts/releases/tag/nginx-1.1.0"},
+	}
+
+	notes := releaseNotesBetween(releases, "nginx", "1.1.0", "1.3.0")
+	if notes == nil {
+		t.Fatal("Expected release notes")
+	}
+	if expected := "1.3.0:\nBreaking: new values layout\n1.2.0:\nFix probes"; notes.Excerpt != expected {
+		t.Errorf("Excerpt = %q, want %q", notes.Excerpt, expected)
+	}
+	if notes.URL != "https://github.com/org/charts/releases/tag/nginx-1.3.0" {
+		t.Errorf("URL = %q, want the latest release", notes.URL)
+	}
+
+	if notes := releaseNotesBetween(releases, "nginx", "1.3.0", "1.3.0"); notes != nil {
+		t.Errorf("Expected no release notes without newer releases, got %+v", notes)
+	}
+	if notes := releaseNotesBetween([]release{{Tag: "v2.0.0", Body: "Major"}}, "app", "1.0.0", "2.0.0"); notes == nil || notes.Excerpt != "2.0.0:\nMajor" {
+		t.Errorf("Expected v-prefixed tags to match, got %+v", notes)
+	}
+}
+
+func TestArtifactHubChanges(t *testing.T) {
+	entries := []Entry{
+		{Version: "1.2.0", Annotations: map[string]string{
+			changesAnnotation: "- kind: added\n  description: Ingress support\n- kind: fixed\n  description: Probe timeouts\n",
+			linksAnnotation:   "- name: Chart source\n  url: https://github.com/org/charts\n- name: Changelog\n  url: https://example.com/CHANGELOG.md\n",
+		}},
+		{Version: "1.1.0", Annotations: map[string]string{changesAnnotation: "- Bump image to 2.4\n"}},
+		{Version: "1.0.0", Annotations: map[string]string{changesAnnotation: "- Initial release\n"}},
+	}
+
+	changes, changelogURL := artifactHubChanges(entries, "1.0.0", "1.2.0")
+	expected := "1.2.0:\n- added: Ingress support\n- fixed: Probe timeouts\n1.1.0:\n- Bump image to 2.4"
+	if changes != expected {
+		t.Errorf("changes = %q, want %q", changes, expected)
+	}
+	if changelogURL != "https://example.com/CHANGELOG.md" {
+		t.Errorf("changelogURL = %q", changelogURL)
+	}
+
+	if changes, _ := artifactHubChanges(entries, "latest", "1.2.0"); changes != "" {
+		t.Errorf("Expected no changes for a non-semver version, got %q", changes)
+	}
+}
+
+func TestTruncateExcerpt(t *testing.T) {
+	if got := truncateExcerpt("short", 10); got != "short" {
+		t.Errorf("truncateExcerpt() = %q", got)
+	}
+	if got := truncateExcerpt("a long line of notes", 8); got != "a long…" {
+		t.Errorf("truncateExcerpt() = %q", got)
+	}
+	if got := truncateExcerpt("a long line of notes", 0); got != "a long line of notes" {
+		t.Errorf("truncateExcerpt() with no limit = %q", got)
+	}
+}
+
+func TestReleaseNotesClient_Get_GitHub(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/org/charts/releases" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `[
+			{"tag_name": "nginx-1.2.0", "body": "Adds ingress support", "html_url": "https://github.com/org/charts/releases/tag/nginx-1.2.0"},
+			{"tag_name": "nginx-1.1.0", "body": "Draft", "draft": true}
+		]`)
+	}))
+	defer server.Close()
+
+	client := NewReleaseNotesClient("secret", "", 300, logrus.NewEntry(logrus.New()))
+	client.githubAPI = server.URL
+
+	result := &VersionConstraintResult{LatestVersion: "1.2.0", Sources: []string{"https://github.com/org/charts"}}
+	notes, err := client.Get(context.Background(), "https://charts.example.com", "nginx", "1.0.0", result)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if notes == nil || notes.Excerpt != "1.2.0:\nAdds ingress support" || notes.URL != "https://github.com/org/charts/releases/tag/nginx-1.2.0" {
+		t.Errorf("Unexpected release notes: %+v", notes)
+	}
+	if authorization != "Bearer secret" {
+		t.Errorf("Authorization = %q", authorization)
+	}
+}
+
+func TestReleaseNotesClient_Get_Annotations(t *testing.T) {
+	client := NewReleaseNotesClient("", "", 20, logrus.NewEntry(logrus.New()))
+	client.githubAPI = "http://127.0.0.1:0" // Annotated changes don't need the API
+
+	result := &VersionConstraintResult{
+		LatestVersion: "1.2.0",
+		Changes:       "1.2.0:\n- added: Ingress support",
+		Sources:       []string{"https://github.com/org/charts"},
+	}
+	notes, err := client.Get(context.Background(), "https://charts.example.com", "nginx", "1.0.0", result)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if notes == nil || notes.Excerpt != "1.2.0:\n- added: Ing…" {
+		t.Fatalf("Unexpected release notes: %+v", notes)
+	}
+	if notes.URL != "https://github.com/org/charts/releases" {
+		t.Errorf("URL = %q, want the project's releases page", notes.URL)
+	}
+}
+
+func TestReleaseNotesClient_Get_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "API rate limit exceeded"}`)
+	}))
+	defer server.Close()
+
+	client := NewReleaseNotesClient("", "", 300, logrus.NewEntry(logrus.New()))
+	client.githubAPI = server.URL
+
+	result := &VersionConstraintResult{LatestVersion: "1.2.0"}
+	_, err := client.Get(context.Background(), "https://github.com/org/charts.git", "nginx", "1.0.0", result)
+	if err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("Expected a status error, got %v", err)
+	}
+}
+
+func TestCheckerGetReleaseNotes_Disabled(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, err := NewChecker(authProvider, logger)
+	if err != nil {
+		t.Fatalf("Failed to create checker: %v", err)
+	}
+
+	notes, err := checker.GetReleaseNotes(context.Background(), "https://github.com/org/charts.git", "nginx", "1.0.0", &VersionConstraintResult{LatestVersion: "1.2.0"})
+	if err != nil || notes != nil {
+		t.Errorf("Expected no lookup when disabled, got %+v, %v", notes, err)
+	}
+}
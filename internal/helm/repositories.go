@@ -0,0 +1,48 @@
+package helm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RepositoryOverride holds the lookup settings of a repository and every repository below its URL
+type RepositoryOverride struct {
+	URL      string
+	Timeout  time.Duration // Replaces the timeout of the repository type (0 keeps it)
+	Disabled bool          // Lookups fail right away with ErrRepositoryDisabled
+}
+
+// SetRepositoryOverrides replaces the per-repository lookup settings
+// The override with the longest matching URL applies, regardless of scheme.
+func (c *Checker) SetRepositoryOverrides(overrides []RepositoryOverride) {
+	c.overrides = make(map[string]RepositoryOverride, len(overrides))
+	for _, override := range overrides {
+		c.overrides[normalizeTagRepoURL(override.URL)] = override
+	}
+}
+
+// repositoryOverride returns the override of a repository, if any
+func (c *Checker) repositoryOverride(repoURL string) (RepositoryOverride, bool) {
+	normalized := normalizeTagRepoURL(repoURL)
+	var found RepositoryOverride
+	matched := -1
+	for prefix, override := range c.overrides {
+		if len(prefix) <= matched {
+			continue
+		}
+		if normalized == prefix || strings.HasPrefix(normalized, prefix+"/") {
+			found, matched = override, len(prefix)
+		}
+	}
+	return found, matched >= 0
+}
+
+// allow returns an error if lookups in the repository should fail right away: the repository is
+// disabled or its circuit is open
+func (c *Checker) allow(repoURL string) error {
+	if override, ok := c.repositoryOverride(repoURL); ok && override.Disabled {
+		return fmt.Errorf("%w: %s", ErrRepositoryDisabled, override.URL)
+	}
+	return c.circuits.allow(repoURL)
+}
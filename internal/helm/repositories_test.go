@@ -0,0 +1,77 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCheckerSetRepositoryOverrides(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, _ := NewChecker(authProvider, logger)
+	checker.SetRepositoryOverrides([]RepositoryOverride{
+		{URL: server.URL, Timeout: 50 * time.Millisecond},
+		{URL: "oci://registry.example.com/legacy", Disabled: true},
+	})
+
+	_, err := checker.GetLatestVersionWithConstraint(context.Background(), server.URL+"/stable", "nginx", "1.0.0", "major")
+	if err == nil || !strings.Contains(err.Error(), "Helm repository lookup timed out after 50ms") {
+		t.Errorf("expected the repository's own timeout, got: %v", err)
+	}
+
+	_, err = checker.GetLatestVersionWithConstraint(context.Background(), "registry.example.com/legacy/charts", "nginx", "1.0.0", "major")
+	if !errors.Is(err, ErrRepositoryDisabled) || ErrorCode(err) != ErrorCodeRepoDisabled {
+		t.Errorf("expected a disabled repository error, got: %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("repositories received %d requests, want 1", got)
+	}
+}
+
+func TestCheckerRepositoryOverride(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, _ := NewChecker(authProvider, logger)
+	checker.SetRepositoryOverrides([]RepositoryOverride{
+		{URL: "oci://registry.example.com", Timeout: time.Minute},
+		{URL: "https://registry.example.com/charts/", Disabled: true},
+	})
+
+	tests := []struct {
+		repoURL  string
+		expected string
+	}{
+		{"registry.example.com/apps", "oci://registry.example.com"},
+		{"oci://registry.example.com/charts/nginx", "https://registry.example.com/charts/"},
+		{"registry.example.com.evil.io/charts", ""},
+		{"https://charts.example.com", ""},
+	}
+	for _, tt := range tests {
+		override, _ := checker.repositoryOverride(tt.repoURL)
+		if override.URL != tt.expected {
+			t.Errorf("repositoryOverride(%q) = %q, want %q", tt.repoURL, override.URL, tt.expected)
+		}
+	}
+}
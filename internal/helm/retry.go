@@ -0,0 +1,62 @@
+package helm
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// retryPolicy retries registry requests that failed transiently, with exponential backoff
+// The zero value sends each request once.
+type retryPolicy struct {
+	attempts  int           // Requests sent at most, including the first
+	baseDelay time.Duration // Delay before the first retry, doubled for each further one
+}
+
+// do runs request until it succeeds, fails permanently or runs out of attempts, and returns its last error
+// Only failures to reach the repository (REPO_UNREACHABLE and TIMEOUT error codes) are retried;
+// answers such as a missing chart or rejected credentials won't change on retry.
+func (p retryPolicy) do(ctx context.Context, logger *logrus.Entry, request func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = request(); err == nil || attempt >= p.attempts || !isRetryable(ctx, err) {
+			return err
+		}
+
+		delay := p.delay(attempt)
+		logger.WithError(err).WithFields(logrus.Fields{
+			"attempt": attempt,
+			"delay":   delay,
+		}).Debug("Registry request failed, retrying")
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// delay returns the backoff before the retry following attempt, with up to 20% jitter so workers
+// hitting the same repository don't retry in lockstep
+func (p retryPolicy) delay(attempt int) time.Duration {
+	delay := p.baseDelay << (attempt - 1)
+	if delay <= 0 {
+		return 0
+	}
+	return delay + time.Duration(rand.Int64N(int64(delay)/5+1))
+}
+
+// isRetryable reports whether a failed request may succeed when sent again
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	switch ErrorCode(err) {
+	case ErrorCodeRepoUnreachable, ErrorCodeTimeout:
+		return true
+	}
+	return false
+}
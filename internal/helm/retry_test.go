@@ -0,0 +1,104 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	policy := retryPolicy{attempts: 3, baseDelay: time.Millisecond}
+
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{"success", nil, 1},
+		{"unavailable", fmt.Errorf("%w: repository returned status 503", ErrRepositoryUnavailable), 3},
+		{"timeout", context.DeadlineExceeded, 3},
+		{"not found", fmt.Errorf("%w: nginx", ErrChartNotFound), 1},
+		{"authentication", ErrAuthenticationFailed, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := policy.do(context.Background(), logger, func() error {
+				calls++
+				return tt.err
+			})
+			if err != tt.err {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+			if calls != tt.calls {
+				t.Errorf("request sent %d times, want %d", calls, tt.calls)
+			}
+		})
+	}
+
+	calls := 0
+	_ = retryPolicy{}.do(context.Background(), logger, func() error {
+		calls++
+		return ErrRepositoryUnavailable
+	})
+	if calls != 1 {
+		t.Errorf("zero policy sent the request %d times, want 1", calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	_ = policy.do(ctx, logger, func() error {
+		calls++
+		cancel()
+		return ErrRepositoryUnavailable
+	})
+	if calls != 1 {
+		t.Errorf("canceled request sent %d times, want 1", calls)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := retryPolicy{baseDelay: time.Second}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second} {
+		if got := policy.delay(attempt); got < want || got > want+want/5 {
+			t.Errorf("delay(%d) = %s, want %s plus up to 20%%", attempt, got, want)
+		}
+	}
+}
+
+func TestCheckerSetRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, "apiVersion: v1\nentries:\n  nginx:\n    - name: nginx\n      version: 1.2.0\n")
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, _ := NewChecker(authProvider, logger)
+	checker.SetRetries(3, time.Millisecond)
+
+	version, err := checker.GetLatestVersion(context.Background(), server.URL, "nginx")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != "1.2.0" {
+		t.Errorf("got version %s, want 1.2.0", version)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("repository received %d requests, want 3", got)
+	}
+}
@@ -0,0 +1,39 @@
+package helm
+
+import (
+	"strconv"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// securityUpdateAnnotation is the Artifact Hub Chart.yaml annotation marking versions that fix vulnerabilities
+// Repository indexes copy chart annotations into their entries.
+const securityUpdateAnnotation = "artifacthub.io/containsSecurityUpdates"
+
+// containsSecurityUpdates reports whether any version newer than the current one, up to and including
+// the latest one, is annotated as containing security updates
+// It returns false when either version isn't semver.
+func containsSecurityUpdates(entries []Entry, currentVersion, latestVersion string) bool {
+	current, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return false
+	}
+	latest, err := semver.NewVersion(latestVersion)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if flagged, _ := strconv.ParseBool(entry.Annotations[securityUpdateAnnotation]); !flagged {
+			continue
+		}
+		version, err := semver.NewVersion(entry.Version)
+		if err != nil {
+			continue
+		}
+		if version.GreaterThan(current) && !version.GreaterThan(latest) {
+			return true
+		}
+	}
+	return false
+}
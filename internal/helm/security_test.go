@@ -0,0 +1,84 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestContainsSecurityUpdates(t *testing.T) {
+	flagged := map[string]string{securityUpdateAnnotation: "true"}
+	entries := []Entry{
+		{Version: "1.3.0"},
+		{Version: "1.2.0", Annotations: flagged},
+		{Version: "1.1.0"},
+		{Version: "1.0.0", Annotations: flagged},
+	}
+
+	tests := []struct {
+		name     string
+		current  string
+		latest   string
+		expected bool
+	}{
+		{name: "flagged version in range", current: "1.1.0", latest: "1.3.0", expected: true},
+		{name: "flagged version is the latest", current: "1.1.0", latest: "1.2.0", expected: true},
+		{name: "flagged version already deployed", current: "1.2.0", latest: "1.3.0", expected: false},
+		{name: "flagged version outside constraint", current: "1.1.0", latest: "1.1.0", expected: false},
+		{name: "non-semver current version", current: "stable", latest: "1.3.0", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsSecurityUpdates(entries, tt.current, tt.latest); got != tt.expected {
+				t.Errorf("containsSecurityUpdates(%s, %s) = %v, want %v", tt.current, tt.latest, got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("annotation set to false", func(t *testing.T) {
+		entries := []Entry{{Version: "2.0.0", Annotations: map[string]string{securityUpdateAnnotation: "false"}}}
+		if containsSecurityUpdates(entries, "1.0.0", "2.0.0") {
+			t.Error("Expected no security update")
+		}
+	})
+}
+
+func TestCheckerGetLatestVersionWithConstraint_SecurityUpdate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		indexYAML := `apiVersion: v1
+entries:
+  nginx:
+    - name: nginx
+      version: 1.2.1
+      annotations:
+        artifacthub.io/containsSecurityUpdates: "true"
+    - name: nginx
+      version: 1.2.0
+`
+		w.Header().Set("Content-Type", "application/x-yaml")
+		fmt.Fprint(w, indexYAML)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, err := NewChecker(authProvider, logger)
+	if err != nil {
+		t.Fatalf("Failed to create checker: %v", err)
+	}
+
+	result, err := checker.GetLatestVersionWithConstraint(context.Background(), server.URL, "nginx", "1.2.0", "major")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.SecurityUpdate {
+		t.Error("Expected the update to be flagged as a security update")
+	}
+}
@@ -0,0 +1,114 @@
+package helm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultExcludedTags name moving targets rather than releases
+var DefaultExcludedTags = []string{"latest", "dev", "main", "master", "stable"}
+
+// TagFilter excludes tags and versions that don't name a release, by exact name or regular expression
+type TagFilter struct {
+	names    map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// NewTagFilter creates a filter excluding the given names and anything matching one of the patterns
+func NewTagFilter(names, patterns []string) (*TagFilter, error) {
+	f := &TagFilter{names: make(map[string]bool, len(names))}
+	for _, name := range names {
+		f.names[name] = true
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag pattern '%s': %w", pattern, err)
+		}
+		f.patterns = append(f.patterns, re)
+	}
+	return f, nil
+}
+
+// Excludes reports whether a tag or version is excluded
+// A nil filter excludes nothing.
+func (f *TagFilter) Excludes(tag string) bool {
+	if f == nil {
+		return false
+	}
+	if f.names[tag] {
+		return true
+	}
+	for _, re := range f.patterns {
+		if re.MatchString(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the tags that aren't excluded
+func (f *TagFilter) Filter(tags []string) []string {
+	var kept []string
+	for _, tag := range tags {
+		if !f.Excludes(tag) {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// TagExclusions selects the tag filter of a repository
+// A repository override replaces the default filter for that repository and everything below it.
+type TagExclusions struct {
+	defaultFilter *TagFilter
+	repositories  map[string]*TagFilter // By normalized repository URL
+}
+
+// NewTagExclusions creates tag exclusions applying the default filter to every repository
+func NewTagExclusions(defaultFilter *TagFilter) *TagExclusions {
+	return &TagExclusions{
+		defaultFilter: defaultFilter,
+		repositories:  make(map[string]*TagFilter),
+	}
+}
+
+// defaultTagExclusions excludes DefaultExcludedTags everywhere
+func defaultTagExclusions() *TagExclusions {
+	filter, _ := NewTagFilter(DefaultExcludedTags, nil)
+	return NewTagExclusions(filter)
+}
+
+// SetRepositoryFilter overrides the filter of a repository
+func (e *TagExclusions) SetRepositoryFilter(repoURL string, filter *TagFilter) {
+	e.repositories[normalizeTagRepoURL(repoURL)] = filter
+}
+
+// For returns the filter of a repository: the override with the longest matching URL, or the default
+func (e *TagExclusions) For(repoURL string) *TagFilter {
+	if e == nil {
+		return nil
+	}
+
+	normalized := normalizeTagRepoURL(repoURL)
+	filter, matched := e.defaultFilter, -1
+	for prefix, override := range e.repositories {
+		if len(prefix) <= matched {
+			continue
+		}
+		if normalized == prefix || strings.HasPrefix(normalized, prefix+"/") {
+			filter, matched = override, len(prefix)
+		}
+	}
+	return filter
+}
+
+// normalizeTagRepoURL strips the scheme and trailing slashes so "oci://registry.example.com/charts/"
+// and "registry.example.com/charts" match the same override
+func normalizeTagRepoURL(repoURL string) string {
+	if _, rest, ok := strings.Cut(repoURL, "://"); ok {
+		repoURL = rest
+	}
+	return strings.TrimSuffix(strings.TrimRight(repoURL, "/"), ".git")
+}
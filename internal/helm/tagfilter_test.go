@@ -0,0 +1,63 @@
+package helm
+
+import (
+	"testing"
+)
+
+func TestTagFilter(t *testing.T) {
+	filter, err := NewTagFilter([]string{"latest", "stable"}, []string{`-nightly\.\d+$`, `^sha-`})
+	if err != nil {
+		t.Fatalf("NewTagFilter failed: %v", err)
+	}
+
+	tests := []struct {
+		tag      string
+		excluded bool
+	}{
+		{tag: "latest", excluded: true},
+		{tag: "stable", excluded: true},
+		{tag: "1.2.0-nightly.20250101", excluded: true},
+		{tag: "sha-3f2a9c1", excluded: true},
+		{tag: "1.2.0", excluded: false},
+		{tag: "dev", excluded: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := filter.Excludes(tt.tag); got != tt.excluded {
+				t.Errorf("Excludes(%s) = %v, want %v", tt.tag, got, tt.excluded)
+			}
+		})
+	}
+
+	if _, err := NewTagFilter(nil, []string{"("}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestTagExclusionsFor(t *testing.T) {
+	defaultFilter, _ := NewTagFilter(DefaultExcludedTags, nil)
+	nightly, _ := NewTagFilter(nil, []string{"nightly"})
+
+	exclusions := NewTagExclusions(defaultFilter)
+	exclusions.SetRepositoryFilter("oci://registry.example.com/charts/", nightly)
+
+	tests := []struct {
+		name     string
+		repoURL  string
+		expected *TagFilter
+	}{
+		{name: "override", repoURL: "registry.example.com/charts", expected: nightly},
+		{name: "below override", repoURL: "oci://registry.example.com/charts/team", expected: nightly},
+		{name: "sibling path", repoURL: "registry.example.com/charts-legacy", expected: defaultFilter},
+		{name: "other repository", repoURL: "https://charts.example.com", expected: defaultFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exclusions.For(tt.repoURL); got != tt.expected {
+				t.Errorf("For(%s) returned the wrong filter", tt.repoURL)
+			}
+		})
+	}
+}
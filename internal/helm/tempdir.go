@@ -0,0 +1,124 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// gitTempDirPattern names the temporary directories Git repositories are cloned into
+const gitTempDirPattern = "argazer-git-*"
+
+// CleanupTempDirs removes argazer-git-* directories in the system temporary directory that were
+// left behind by crashed or killed runs
+// Only directories untouched for longer than maxAge are removed, so clones of concurrent runs
+// survive. It returns the number of directories removed.
+func CleanupTempDirs(maxAge time.Duration, logger *logrus.Entry) int {
+	return cleanupTempDirs(os.TempDir(), maxAge, time.Now(), logger)
+}
+
+func cleanupTempDirs(dir string, maxAge time.Duration, now time.Time, logger *logrus.Entry) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logger.WithError(err).Debug("Failed to list temporary directory")
+		return 0
+	}
+
+	prefix := strings.TrimSuffix(gitTempDirPattern, "*")
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < maxAge {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			logger.WithError(err).WithField("path", path).Warn("Failed to remove orphaned temporary directory")
+			continue
+		}
+		logger.WithField("path", path).Debug("Removed orphaned temporary directory")
+		removed++
+	}
+	return removed
+}
+
+// cacheDirIndex groups the files of one index in the cache directory: the index, its metadata and
+// leftovers of interrupted writes
+type cacheDirIndex struct {
+	paths    []string
+	size     int64
+	modified time.Time // Latest modification of its files, i.e. its last refresh
+}
+
+// pruneCacheDir removes indexes from the cache directory that weren't refreshed for longer than
+// maxAge, then the least recently refreshed ones until the directory holds at most maxSize bytes
+// A zero maxAge or maxSize disables that limit. It returns the number of indexes removed.
+func pruneCacheDir(dir string, maxAge time.Duration, maxSize int64, now time.Time, logger *logrus.Entry) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logger.WithError(err).Debug("Failed to list cache directory")
+		return 0
+	}
+
+	byName := make(map[string]*cacheDirIndex)
+	var total int64
+	for _, entry := range entries {
+		// "helm-index-<hash>.yaml", "helm-index-<hash>.json" and ".helm-index-<hash>.yaml.tmp-*"
+		name, _, _ := strings.Cut(strings.TrimPrefix(entry.Name(), "."), ".")
+		if entry.IsDir() || !strings.HasPrefix(name, indexCacheFilePrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		index, ok := byName[name]
+		if !ok {
+			index = &cacheDirIndex{}
+			byName[name] = index
+		}
+		index.paths = append(index.paths, filepath.Join(dir, entry.Name()))
+		index.size += info.Size()
+		if info.ModTime().After(index.modified) {
+			index.modified = info.ModTime()
+		}
+		total += info.Size()
+	}
+
+	indexes := make([]*cacheDirIndex, 0, len(byName))
+	for _, index := range byName {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].modified.Before(indexes[j].modified)
+	})
+
+	removed := 0
+	for _, index := range indexes {
+		expired := maxAge > 0 && now.Sub(index.modified) >= maxAge
+		if !expired && (maxSize <= 0 || total <= maxSize) {
+			break // The remaining indexes are newer
+		}
+		for _, path := range index.paths {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				logger.WithError(err).WithField("path", path).Warn("Failed to remove cached index")
+			}
+		}
+		logger.WithFields(logrus.Fields{
+			"path":     index.paths[0],
+			"modified": index.modified,
+		}).Debug("Removed cached index")
+		total -= index.size
+		removed++
+	}
+	return removed
+}
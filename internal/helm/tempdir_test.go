@@ -0,0 +1,102 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCleanupTempDirs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	mkdir := func(name string, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Join(path, ".git"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	orphaned := mkdir("argazer-git-123", 2*time.Hour)
+	inUse := mkdir("argazer-git-456", time.Minute)
+	unrelated := mkdir("other-git-789", 2*time.Hour)
+
+	removed := cleanupTempDirs(dir, time.Hour, now, logrus.NewEntry(logrus.New()))
+	if removed != 1 {
+		t.Errorf("cleanupTempDirs() removed %d directories, want 1", removed)
+	}
+	if _, err := os.Stat(orphaned); !os.IsNotExist(err) {
+		t.Errorf("orphaned directory %s still exists", orphaned)
+	}
+	for _, path := range []string{inUse, unrelated} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("directory %s was removed: %v", path, err)
+		}
+	}
+}
+
+func TestPruneCacheDir(t *testing.T) {
+	now := time.Now()
+	write := func(dir, name string, size int, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+	logger := logrus.NewEntry(logrus.New())
+
+	t.Run("max age", func(t *testing.T) {
+		dir := t.TempDir()
+		stale := write(dir, "helm-index-aaaa.yaml", 10, 48*time.Hour)
+		staleMeta := write(dir, "helm-index-aaaa.json", 10, 48*time.Hour)
+		staleTmp := write(dir, ".helm-index-aaaa.yaml.tmp-1", 10, 48*time.Hour)
+		revalidated := write(dir, "helm-index-bbbb.yaml", 10, 48*time.Hour)
+		revalidatedMeta := write(dir, "helm-index-bbbb.json", 10, time.Hour)
+		unrelated := write(dir, "notes.txt", 10, 48*time.Hour)
+
+		if removed := pruneCacheDir(dir, 24*time.Hour, 0, now, logger); removed != 1 {
+			t.Errorf("pruneCacheDir() removed %d indexes, want 1", removed)
+		}
+		for _, path := range []string{stale, staleMeta, staleTmp} {
+			if exists(path) {
+				t.Errorf("expired file %s still exists", path)
+			}
+		}
+		for _, path := range []string{revalidated, revalidatedMeta, unrelated} {
+			if !exists(path) {
+				t.Errorf("file %s was removed", path)
+			}
+		}
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		dir := t.TempDir()
+		oldest := write(dir, "helm-index-aaaa.yaml", 600, 3*time.Hour)
+		older := write(dir, "helm-index-bbbb.yaml", 600, 2*time.Hour)
+		newest := write(dir, "helm-index-cccc.yaml", 600, time.Hour)
+
+		if removed := pruneCacheDir(dir, 0, 1000, now, logger); removed != 2 {
+			t.Errorf("pruneCacheDir() removed %d indexes, want 2", removed)
+		}
+		if exists(oldest) || exists(older) {
+			t.Error("the least recently refreshed indexes should be removed first")
+		}
+		if !exists(newest) {
+			t.Error("the newest index should be kept")
+		}
+	})
+}
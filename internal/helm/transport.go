@@ -0,0 +1,109 @@
+package helm
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// RepositoryTLS holds the TLS settings of a Helm repository or OCI registry
+// Settings apply to the whole host of the URL, since TLS is negotiated per host.
+type RepositoryTLS struct {
+	URL                string // e.g. "oci://harbor.example.com/charts" or "https://charts.example.com"
+	CAFile             string // PEM bundle of CAs trusted besides the system ones
+	InsecureSkipVerify bool   // Skip certificate verification
+}
+
+// SetTransport routes Helm repository and OCI registry requests through proxyURL and applies the TLS
+// settings of repositories
+// Without proxyURL, the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables apply.
+func (c *Checker) SetTransport(proxyURL string, repositories []RepositoryTLS) error {
+	transport, err := newRepositoryTransport(proxyURL, repositories)
+	if err != nil {
+		return err
+	}
+	c.httpClient.Transport = transport
+	c.ociChecker.httpClient.Transport = transport
+	return nil
+}
+
+// repositoryTransport sends requests with the transport of their host, or the default one
+type repositoryTransport struct {
+	defaultTransport *http.Transport
+	hosts            map[string]*http.Transport // By host, with the port when the URL has one
+}
+
+// newRepositoryTransport creates a transport with one connection pool per host with TLS settings
+func newRepositoryTransport(proxyURL string, repositories []RepositoryTLS) (*repositoryTransport, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", proxyURL)
+		}
+		base.Proxy = http.ProxyURL(proxy)
+	}
+
+	t := &repositoryTransport{defaultTransport: base, hosts: make(map[string]*http.Transport)}
+	for _, repository := range repositories {
+		host := repositoryHost(repository.URL)
+		if host == "" {
+			return nil, fmt.Errorf("invalid repository URL %q for TLS settings", repository.URL)
+		}
+
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: repository.InsecureSkipVerify,
+		}
+		if repository.CAFile != "" {
+			pem, err := os.ReadFile(repository.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file of %s: %w", repository.URL, err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("CA file %s of %s contains no PEM certificates", repository.CAFile, repository.URL)
+			}
+			tlsConfig.RootCAs = pool
+		}
+
+		transport := base.Clone()
+		transport.TLSClientConfig = tlsConfig
+		t.hosts[host] = transport
+	}
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *repositoryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport, ok := t.hosts[req.URL.Host]; ok {
+		return transport.RoundTrip(req)
+	}
+	if transport, ok := t.hosts[req.URL.Hostname()]; ok {
+		return transport.RoundTrip(req)
+	}
+	return t.defaultTransport.RoundTrip(req)
+}
+
+// repositoryHost returns the host (and port) of a repository URL, with or without scheme
+func repositoryHost(repoURL string) string {
+	if !strings.Contains(repoURL, "://") {
+		repoURL = "https://" + repoURL
+	}
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return ""
+	}
+	// Docker Hub's registry API is served from registry-1.docker.io
+	if host := parsed.Host; host == "docker.io" || host == "index.docker.io" {
+		return "registry-1.docker.io"
+	}
+	return parsed.Host
+}
@@ -0,0 +1,120 @@
+package helm
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+)
+
+const transportTestIndex = "apiVersion: v1\nentries:\n  nginx:\n    - name: nginx\n      version: 1.2.0\n"
+
+func newTransportTestChecker(t *testing.T) *Checker {
+	t.Helper()
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, err := NewChecker(authProvider, logger)
+	if err != nil {
+		t.Fatalf("failed to create checker: %v", err)
+	}
+	return checker
+}
+
+func TestCheckerSetTransport_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, transportTestIndex)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	checker := newTransportTestChecker(t)
+	if _, err := checker.GetLatestVersion(context.Background(), server.URL, "nginx"); err == nil {
+		t.Fatal("expected a certificate error without the CA file")
+	}
+
+	if err := checker.SetTransport("", []RepositoryTLS{{URL: server.URL, CAFile: caFile}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	version, err := checker.GetLatestVersion(context.Background(), server.URL, "nginx")
+	if err != nil {
+		t.Fatalf("unexpected error with the CA file: %v", err)
+	}
+	if version != "1.2.0" {
+		t.Errorf("got version %s, want 1.2.0", version)
+	}
+
+	checker = newTransportTestChecker(t)
+	if err := checker.SetTransport("", []RepositoryTLS{{URL: strings.TrimPrefix(server.URL, "https://"), InsecureSkipVerify: true}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := checker.GetLatestVersion(context.Background(), server.URL, "nginx"); err != nil {
+		t.Errorf("unexpected error skipping verification: %v", err)
+	}
+}
+
+func TestCheckerSetTransport_Proxy(t *testing.T) {
+	var proxied atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Proxies receive the absolute URL of the repository
+		if r.URL.Host == "charts.example.com" {
+			proxied.Add(1)
+		}
+		fmt.Fprint(w, transportTestIndex)
+	}))
+	defer proxy.Close()
+
+	checker := newTransportTestChecker(t)
+	if err := checker.SetTransport(proxy.URL, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := checker.GetLatestVersion(context.Background(), "http://charts.example.com", "nginx"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if proxied.Load() != 1 {
+		t.Errorf("proxy received %d requests, want 1", proxied.Load())
+	}
+}
+
+func TestCheckerSetTransport_Invalid(t *testing.T) {
+	checker := newTransportTestChecker(t)
+	if err := checker.SetTransport("://", nil); err == nil {
+		t.Error("expected an error for an invalid proxy URL")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.SetTransport("", []RepositoryTLS{{URL: "oci://harbor.example.com", CAFile: caFile}}); err == nil {
+		t.Error("expected an error for a CA file without certificates")
+	}
+}
+
+func TestRepositoryHost(t *testing.T) {
+	tests := map[string]string{
+		"oci://harbor.example.com/charts": "harbor.example.com",
+		"https://charts.example.com:8443": "charts.example.com:8443",
+		"harbor.example.com":              "harbor.example.com",
+		"docker.io":                       "registry-1.docker.io",
+	}
+	for repoURL, want := range tests {
+		if got := repositoryHost(repoURL); got != want {
+			t.Errorf("repositoryHost(%q) = %q, want %q", repoURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// helmChartLayerMediaType is the media type of the chart archive layer in OCI registries
+const helmChartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+// maxChartArchiveLength caps chart archive downloads; charts bundling large CRDs stay well below it
+const maxChartArchiveLength = 32 << 20
+
+// ValuesDiff lists the keys of a chart's default values that differ between two versions
+// Keys are dotted paths into values.yaml (e.g. "image.tag"); lists are compared as a whole.
+type ValuesDiff struct {
+	Added   []string `json:"added,omitempty"`   // Keys only in the newer version
+	Removed []string `json:"removed,omitempty"` // Keys only in the older version
+	Changed []string `json:"changed,omitempty"` // Keys whose default value changed
+}
+
+// HasChanges reports whether any key was added, removed or changed
+func (d *ValuesDiff) HasChanges() bool {
+	return d != nil && len(d.Added)+len(d.Removed)+len(d.Changed) > 0
+}
+
+// GetValuesDiff compares the default values of two versions of a chart
+func (c *Checker) GetValuesDiff(ctx context.Context, repoURL, chartName, currentVersion, latestVersion string) (*ValuesDiff, error) {
+	current, err := c.GetChartValues(ctx, repoURL, chartName, currentVersion)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := c.GetChartValues(ctx, repoURL, chartName, latestVersion)
+	if err != nil {
+		return nil, err
+	}
+	return DiffValues(current, latest)
+}
+
+// GetChartValues returns the default values.yaml of a chart version from a Helm repository, OCI
+// registry or Git repository (at the tag of the version)
+func (c *Checker) GetChartValues(ctx context.Context, repoURL, chartName, version string) ([]byte, error) {
+	values, _, err := c.getChartFiles(ctx, repoURL, chartName, version)
+	return values, err
+}
+
+// getChartFiles returns the values.yaml and Chart.yaml of a chart version
+func (c *Checker) getChartFiles(ctx context.Context, repoURL, chartName, version string) ([]byte, []byte, error) {
+	// Resolve Helm repository aliases (e.g. "@bitnami") from the local Helm configuration
+	repoURL = c.authProvider.ResolveRepoURL(repoURL)
+
+	if err := c.allow(repoURL); err != nil {
+		return nil, nil, err
+	}
+
+	ctx, done := c.withLookupTimeout(ctx, repoURL)
+	values, metadata, err := c.fetchChartFiles(ctx, repoURL, chartName, version)
+	err = done(err)
+	c.circuits.record(repoURL, err)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s %s: %w", chartName, version, err)
+	}
+	return values, metadata, nil
+}
+
+// fetchChartFiles dispatches the chart download to the Git, OCI or Helm repository
+func (c *Checker) fetchChartFiles(ctx context.Context, repoURL, chartName, version string) ([]byte, []byte, error) {
+	if isGitURL(repoURL) {
+		return c.gitClient.GetChartFiles(ctx, repoURL, chartName, version)
+	}
+
+	var archive []byte
+	var err error
+	if isOCIRepository(repoURL) {
+		archive, err = c.ociChecker.getChartArchive(ctx, repoURL, chartName, version)
+	} else {
+		archive, err = c.getChartArchiveFromRepo(ctx, repoURL, chartName, version)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return archiveChartFiles(archive)
+}
+
+// getChartArchiveFromRepo downloads the archive of a chart version listed in a Helm repository index
+func (c *Checker) getChartArchiveFromRepo(ctx context.Context, repoURL, chartName, version string) ([]byte, error) {
+	entries, err := c.getChartEntriesFromRepo(ctx, repoURL, chartName)
+	if err != nil {
+		return nil, err
+	}
+
+	var archiveURL string
+	for _, entry := range entries {
+		if entry.Version == version && len(entry.URLs) > 0 {
+			archiveURL = entry.URLs[0]
+			break
+		}
+	}
+	if archiveURL == "" {
+		return nil, fmt.Errorf("%w: %s %s has no archive in the index", ErrChartNotFound, chartName, version)
+	}
+
+	// Index URLs may be relative to the repository
+	base, err := url.Parse(strings.TrimSuffix(repoURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
+	}
+	ref, err := url.Parse(archiveURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart URL %s: %w", archiveURL, err)
+	}
+	archiveURL = base.ResolveReference(ref).String()
+
+	c.logger.WithFields(logrus.Fields{
+		"chart":   chartName,
+		"version": version,
+		"url":     archiveURL,
+	}).Debug("Downloading chart archive")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", archiveURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "argazer/1.0")
+	// Like Helm, credentials only follow archives to another host with pass_credentials_all
+	if creds := c.authProvider.GetCredentials(repoURL); creds != nil {
+		if creds.PassCredentialsAll || sameOrigin(base, req.URL) {
+			req.SetBasicAuth(creds.Username, creds.Password)
+		} else {
+			c.logger.WithField("url", archiveURL).Debug("Chart archive is on another host, not sending the repository credentials")
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download chart: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%w for %s (status %d): check credentials", ErrAuthenticationFailed, repoURL, resp.StatusCode)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("%w: repository returned status %d", ErrRepositoryUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("chart download returned status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxChartArchiveLength))
+}
+
+// sameOrigin reports whether two URLs share scheme and host (including the port)
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+// getChartArchive downloads the chart archive layer of a chart version from an OCI registry
+func (o *OCIChecker) getChartArchive(ctx context.Context, repoURL, chartName, version string) ([]byte, error) {
+	registry, fullRepoPath := ociRepositoryPath(repoURL, chartName)
+	baseURL := registryBaseURL(registry)
+
+	// Helm stores "+" of build metadata as "_" in tags
+	tag := strings.ReplaceAll(version, "+", "_")
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", baseURL, fullRepoPath, tag)
+	body, _, err := o.fetchRegistryBlob(ctx, manifestURL, manifestAccept, registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest for %s: %w", tag, err)
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	var layerDigest string
+	for _, layer := range manifest.Layers {
+		if layer.MediaType == helmChartLayerMediaType {
+			layerDigest = layer.Digest
+			break
+		}
+	}
+	if layerDigest == "" {
+		return nil, fmt.Errorf("%w: %s has no Helm chart layer", ErrInvalidRepository, tag)
+	}
+
+	o.logger.WithFields(logrus.Fields{
+		"chart":  chartName,
+		"tag":    tag,
+		"digest": layerDigest,
+	}).Debug("Downloading chart layer")
+
+	blobURL := fmt.Sprintf("%s/v2/%s/blobs/%s", baseURL, fullRepoPath, layerDigest)
+	resp, _, err := o.registryRequest(ctx, "GET", blobURL, helmChartLayerMediaType, registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart layer: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			o.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCI registry returned status %d for the chart layer", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxChartArchiveLength))
+}
+
+// GetChartFiles reads values.yaml and Chart.yaml of a chart at the tag of a version, or at a
+// revision such as a branch or commit SHA when no tag matches
+// A chart without values.yaml has no default values.
+func (g *GitClient) GetChartFiles(ctx context.Context, repoURL, chartPath, version string) ([]byte, []byte, error) {
+	g.logger.WithFields(logrus.Fields{
+		"repo":       repoURL,
+		"chart_path": chartPath,
+		"version":    version,
+	}).Debug("Reading chart files")
+
+	cloneOpts := &git.CloneOptions{
+		URL:      repoURL,
+		Progress: nil,
+		Tags:     git.AllTags,
+	}
+
+	var values, metadata string
+	err := g.withClone(ctx, repoURL+"#full", cloneOpts, func(_ string, repo *git.Repository) error {
+		commit, err := revisionCommit(repo, versionTag(repo, chartPath, version))
+		if err != nil {
+			return err
+		}
+		metadata, err = commitFile(commit, filepath.Join(chartPath, "Chart.yaml"))
+		if err != nil {
+			return fmt.Errorf("failed to read Chart.yaml at %s: %w", version, err)
+		}
+		values, err = commitFile(commit, filepath.Join(chartPath, "values.yaml"))
+		if err != nil && !errors.Is(err, object.ErrFileNotFound) {
+			return fmt.Errorf("failed to read values.yaml at %s: %w", version, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(values), []byte(metadata), nil
+}
+
+// versionTag returns the name of the tag of a chart version (e.g. "v1.2.3" or "chart-1.2.3" for
+// "1.2.3"), or the version itself when no tag matches
+func versionTag(repo *git.Repository, chartPath, version string) string {
+	want, err := semver.NewVersion(version)
+	if err != nil {
+		return version
+	}
+	tags, err := repo.Tags()
+	if err != nil {
+		return version
+	}
+	defer tags.Close()
+
+	match := version
+	_ = tags.ForEach(func(ref *plumbing.Reference) error {
+		tagName := ref.Name().Short()
+		if v, err := semver.NewVersion(versionFromTag(tagName, chartPath)); err == nil && v.Equal(want) {
+			match = tagName
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return match
+}
+
+// archiveChartFiles extracts the chart's top-level values.yaml and Chart.yaml from a chart archive (.tgz)
+// Charts without values.yaml have no default values.
+func archiveChartFiles(archive []byte) ([]byte, []byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read chart archive: %w", err)
+	}
+	defer gz.Close()
+
+	var values, metadata []byte
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read chart archive: %w", err)
+		}
+		// Archives contain the chart directory ("nginx/values.yaml"); subcharts are nested deeper
+		dir, file := path.Split(path.Clean(header.Name))
+		if dir == "" || strings.Contains(strings.TrimSuffix(dir, "/"), "/") {
+			continue
+		}
+		switch file {
+		case "values.yaml":
+			values, err = io.ReadAll(io.LimitReader(tr, maxChartArchiveLength))
+		case "Chart.yaml":
+			metadata, err = io.ReadAll(io.LimitReader(tr, maxChartArchiveLength))
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read chart archive: %w", err)
+		}
+	}
+	if metadata == nil {
+		return nil, nil, fmt.Errorf("%w: chart archive has no Chart.yaml", ErrInvalidRepository)
+	}
+	return values, metadata, nil
+}
+
+// DiffValues compares two values.yaml documents by key
+func DiffValues(current, latest []byte) (*ValuesDiff, error) {
+	currentKeys, err := flattenValues(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse current values: %w", err)
+	}
+	latestKeys, err := flattenValues(latest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse latest values: %w", err)
+	}
+
+	diff := &ValuesDiff{}
+	for key, value := range latestKeys {
+		old, ok := currentKeys[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, key)
+		case !reflect.DeepEqual(old, value):
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+	for key := range currentKeys {
+		if _, ok := latestKeys[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff, nil
+}
+
+// flattenValues maps the dotted path of each leaf value of a values.yaml document to its value
+// Empty maps are left out, so adding keys to one isn't reported as removing it.
+func flattenValues(data []byte) (map[string]interface{}, error) {
+	var values map[interface{}]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]interface{})
+	flattenInto(keys, "", values)
+	return keys, nil
+}
+
+// flattenInto adds the leaves of a values map below prefix
+func flattenInto(keys map[string]interface{}, prefix string, values map[interface{}]interface{}) {
+	for k, v := range values {
+		key := fmt.Sprint(k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := v.(map[interface{}]interface{}); ok {
+			flattenInto(keys, key, nested)
+			continue
+		}
+		keys[key] = v
+	}
+}
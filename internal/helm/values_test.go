@@ -0,0 +1,247 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"argazer/internal/auth"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// chartArchive builds a chart archive (.tgz) from file names and contents
+func chartArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestDiffValues(t *testing.T) {
+	current := []byte(`
+image:
+  repository: nginx
+  tag: "1.25"
+replicaCount: 1
+ingress:
+  enabled: false
+  hosts: [example.com]
+legacy:
+  port: 8080
+podAnnotations: {}
+`)
+	latest := []byte(`
+image:
+  repository: nginx
+  tag: "1.27"
+  pullPolicy: IfNotPresent
+replicaCount: 1
+ingress:
+  enabled: false
+  hosts: [example.com, example.org]
+podAnnotations:
+  prometheus.io/scrape: "true"
+`)
+
+	diff, err := DiffValues(current, latest)
+	require.NoError(t, err)
+	assert.Equal(t, &ValuesDiff{
+		Added:   []string{"image.pullPolicy", "podAnnotations.prometheus.io/scrape"},
+		Removed: []string{"legacy.port"},
+		Changed: []string{"image.tag", "ingress.hosts"},
+	}, diff)
+	assert.True(t, diff.HasChanges())
+
+	diff, err = DiffValues(current, current)
+	require.NoError(t, err)
+	assert.False(t, diff.HasChanges())
+
+	diff, err = DiffValues(nil, []byte("replicaCount: 1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replicaCount"}, diff.Added, "charts without values.yaml have no defaults")
+
+	_, err = DiffValues([]byte("image: [\n"), nil)
+	assert.Error(t, err)
+}
+
+func TestArchiveChartFiles(t *testing.T) {
+	archive := chartArchive(t, map[string]string{
+		"nginx/Chart.yaml":                    "name: nginx\n",
+		"nginx/charts/common/values.yaml":     "common: true\n",
+		"nginx/charts/common/Chart.yaml":      "name: common\n",
+		"nginx/values.yaml":                   "replicaCount: 1\n",
+		"nginx/templates/deployment.yaml":     "kind: Deployment\n",
+		"nginx/charts/common/templates/a.tpl": "",
+	})
+	values, metadata, err := archiveChartFiles(archive)
+	require.NoError(t, err)
+	assert.Equal(t, "replicaCount: 1\n", string(values), "subchart values are ignored")
+	assert.Equal(t, "name: nginx\n", string(metadata))
+
+	values, _, err = archiveChartFiles(chartArchive(t, map[string]string{"nginx/Chart.yaml": "name: nginx\n"}))
+	require.NoError(t, err)
+	assert.Nil(t, values)
+
+	_, _, err = archiveChartFiles(chartArchive(t, map[string]string{"nginx/values.yaml": "replicaCount: 1\n"}))
+	assert.ErrorIs(t, err, ErrInvalidRepository)
+
+	_, _, err = archiveChartFiles([]byte("not an archive"))
+	assert.Error(t, err)
+}
+
+func TestChecker_GetValuesDiff_HelmRepository(t *testing.T) {
+	archives := map[string][]byte{
+		"1.0.0": chartArchive(t, map[string]string{"nginx/Chart.yaml": "name: nginx\n", "nginx/values.yaml": "image:\n  tag: \"1.25\"\n"}),
+		"1.1.0": chartArchive(t, map[string]string{"nginx/Chart.yaml": "name: nginx\n", "nginx/values.yaml": "image:\n  tag: \"1.27\"\nservice:\n  port: 80\n"}),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			fmt.Fprint(w, `apiVersion: v1
+entries:
+  nginx:
+    - name: nginx
+      version: 1.1.0
+      urls: [charts/nginx-1.1.0.tgz]
+    - name: nginx
+      version: 1.0.0
+      urls: [charts/nginx-1.0.0.tgz]
+`)
+		case "/charts/nginx-1.0.0.tgz":
+			_, _ = w.Write(archives["1.0.0"])
+		case "/charts/nginx-1.1.0.tgz":
+			_, _ = w.Write(archives["1.1.0"])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	authProvider, _ := auth.NewProvider(nil, logger)
+	checker, err := NewChecker(authProvider, logger)
+	require.NoError(t, err)
+
+	diff, err := checker.GetValuesDiff(context.Background(), server.URL, "nginx", "1.0.0", "1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, &ValuesDiff{Added: []string{"service.port"}, Changed: []string{"image.tag"}}, diff)
+
+	_, err = checker.GetValuesDiff(context.Background(), server.URL, "nginx", "0.9.0", "1.1.0")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrChartNotFound)
+}
+
+func TestChecker_GetChartArchiveFromRepo_PassCredentialsAll(t *testing.T) {
+	archive := chartArchive(t, map[string]string{"nginx/Chart.yaml": "name: nginx\n"})
+
+	// The index points at archives on a separate host, like a CDN or object storage bucket
+	var archiveAuth []string
+	archives := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archiveAuth = append(archiveAuth, r.Header.Get("Authorization"))
+		_, _ = w.Write(archive)
+	}))
+	defer archives.Close()
+
+	repo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			fmt.Fprintf(w, `apiVersion: v1
+entries:
+  nginx:
+    - name: nginx
+      version: 1.0.0
+      urls: [%s/nginx-1.0.0.tgz]
+    - name: nginx
+      version: 0.9.0
+      urls: [charts/nginx-0.9.0.tgz]
+`, archives.URL)
+		case "/charts/nginx-0.9.0.tgz":
+			archiveAuth = append(archiveAuth, r.Header.Get("Authorization"))
+			_, _ = w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer repo.Close()
+
+	newChecker := func(passCredentialsAll bool) *Checker {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "repositories.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(`repositories:
+- name: internal
+  url: %s
+  username: helm-user
+  password: helm-pass
+  pass_credentials_all: %t
+`, repo.URL, passCredentialsAll)), 0o600))
+
+		logger := logrus.NewEntry(logrus.New())
+		authProvider, _ := auth.NewProvider(nil, logger)
+		require.NoError(t, authProvider.LoadHelmRepositoryConfig(path))
+		checker, err := NewChecker(authProvider, logger)
+		require.NoError(t, err)
+		return checker
+	}
+	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("helm-user:helm-pass"))
+
+	archiveAuth = nil
+	checker := newChecker(false)
+	_, err := checker.getChartArchiveFromRepo(context.Background(), repo.URL, "nginx", "1.0.0")
+	require.NoError(t, err)
+	_, err = checker.getChartArchiveFromRepo(context.Background(), repo.URL, "nginx", "0.9.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", basicAuth}, archiveAuth, "credentials stay on the repository's host")
+
+	archiveAuth = nil
+	checker = newChecker(true)
+	_, err = checker.getChartArchiveFromRepo(context.Background(), repo.URL, "nginx", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{basicAuth}, archiveAuth, "pass_credentials_all sends them to the other host")
+}
+
+func TestValuesImages(t *testing.T) {
+	var values map[interface{}]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+image:
+  registry: docker.io
+  repository: bitnami/nginx
+  tag: 1.25.3
+metrics:
+  image:
+    repository: nginx/nginx-prometheus-exporter
+sidecars:
+  - name: proxy
+    image:
+      repository: ghcr.io/org/proxy
+      tag: 2
+git:
+  repository: https://github.com/org/config.git
+`), &values))
+
+	images := valuesImages(values, "1.27.0")
+	assert.ElementsMatch(t, []string{
+		"docker.io/bitnami/nginx:1.25.3",
+		"nginx/nginx-prometheus-exporter:1.27.0",
+		"ghcr.io/org/proxy:2",
+	}, images, "blocks without tag use the appVersion")
+}
@@ -3,6 +3,7 @@ package helm
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/sirupsen/logrus"
@@ -10,9 +11,20 @@ import (
 
 // VersionConstraintResult holds the result of version constraint filtering
 type VersionConstraintResult struct {
-	LatestVersion              string // Latest version within constraint
-	LatestVersionAll           string // Latest version without constraint
-	HasUpdateOutsideConstraint bool   // True if newer versions exist outside constraint
+	LatestVersion              string          // Latest version within constraint
+	LatestVersionAll           string          // Latest version without constraint
+	HasUpdateOutsideConstraint bool            // True if newer versions exist outside constraint
+	Migration                  *ChartMigration // Set when the chart has been relocated or deprecated upstream
+	CurrentVersion             string          // Version a pinned revision, mutable tag or branch resolved to (empty otherwise)
+	PinnedBy                   string          // "digest" or "commit" when the revision is pinned
+	MutableTag                 string          // Mutable tag (e.g. "latest") the revision was resolved from
+	TrackingBranch             string          // Git branch the application tracks (e.g. "HEAD" or "main")
+	SecurityUpdate             bool            // True if a version up to the latest one is annotated as containing security updates
+	VersionsBehind             int             // Number of releases newer than the current version within the constraint
+	LatestReleased             time.Time       // Release date of the latest version (zero if the repository doesn't provide it)
+	Changes                    string          // Changes annotated on the versions up to the latest one (artifacthub.io/changes), newest first
+	ChangelogURL               string          // Changelog link annotated on the latest version (artifacthub.io/links)
+	Sources                    []string        // Source and home URLs of the latest version's chart
 }
 
 // findLatestSemver determines the latest semantic version from a list of version strings.
@@ -60,12 +72,34 @@ func findLatestSemver(versions []string, logger *logrus.Entry) (string, error) {
 	return validVersions[0].original, nil
 }
 
+// IsConstraintKeyword reports whether a constraint is one of the "major", "minor" or "patch" keywords
+// (or empty, meaning "major") rather than a semver range
+func IsConstraintKeyword(constraint string) bool {
+	switch constraint {
+	case "", "major", "minor", "patch":
+		return true
+	default:
+		return false
+	}
+}
+
 // findLatestSemverWithConstraint finds the latest version respecting the given constraint
+// The constraint is either a keyword relative to the current version ("major", "minor", "patch") or a
+// semver range such as ">=1.2.0 <2.0.0" or "~1.4.x" that versions must satisfy.
 func findLatestSemverWithConstraint(versions []string, currentVersion, constraint string, logger *logrus.Entry) (*VersionConstraintResult, error) {
 	if len(versions) == 0 {
 		return nil, fmt.Errorf("no versions provided")
 	}
 
+	var versionRange *semver.Constraints
+	if !IsConstraintKeyword(constraint) {
+		var err error
+		versionRange, err = semver.NewConstraint(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint '%s': %w", constraint, err)
+		}
+	}
+
 	// Parse current version
 	current, err := semver.NewVersion(currentVersion)
 	if err != nil {
@@ -121,6 +155,9 @@ func findLatestSemverWithConstraint(versions []string, currentVersion, constrain
 		case "major", "":
 			// All versions
 			matchesConstraint = true
+		default:
+			// Versions within the range
+			matchesConstraint = versionRange.Check(parsed)
 		}
 
 		if matchesConstraint {
@@ -169,6 +206,14 @@ func findLatestSemverWithConstraint(versions []string, currentVersion, constrain
 		result.LatestVersion = currentVersion
 	}
 
+	// Count the releases between the current and the latest version (sorted newest first)
+	for _, v := range constrainedVersions {
+		if v.parsed.Compare(current) <= 0 {
+			break
+		}
+		result.VersionsBehind++
+	}
+
 	// Check if there are newer versions outside constraint
 	if constraint != "major" && constraint != "" {
 		latestAllVer, _ := semver.NewVersion(latestAll)
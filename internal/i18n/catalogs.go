@@ -0,0 +1,410 @@
+package i18n
+
+// catalogs holds the messages of every supported language, keyed by language code
+// Values are fmt format strings; every catalog must define the same keys as English.
+var catalogs = map[string]map[string]string{
+	"en": {
+		LabelTotal:     "Total applications checked",
+		LabelUpToDate:  "Up to date",
+		LabelUpdates:   "Updates available",
+		LabelRelocated: "Relocated",
+		LabelTracking:  "Tracking branch",
+		LabelDrifted:   "Drifted",
+		LabelIgnored:   "Ignored",
+		LabelSkipped:   "Skipped",
+		LabelImages:    "Image updates available",
+		LabelSubcharts: "Subchart updates available",
+
+		FieldApplication:       "Application",
+		FieldProject:           "Project",
+		FieldNamespace:         "Namespace",
+		FieldInstance:          "Instance",
+		FieldCluster:           "Cluster",
+		FieldApplicationSet:    "ApplicationSet",
+		FieldLink:              "Link",
+		FieldValues:            "Values",
+		FieldChart:             "Chart",
+		FieldCurrentVersion:    "Current Version",
+		FieldLatestVersion:     "Latest Version",
+		FieldLatestVersionAll:  "Latest Version (all)",
+		FieldVersionConstraint: "Version Constraint",
+		FieldConstraint:        "Constraint",
+		FieldVersion:           "Version",
+		FieldRepository:        "Repository",
+		FieldRepo:              "Repo",
+		FieldStatus:            "Status",
+		FieldBranch:            "Branch",
+		FieldChartVersion:      "Chart Version",
+		FieldReason:            "Reason",
+		FieldError:             "Error",
+		FieldNote:              "Note",
+		FieldName:              "Field",
+		FieldValue:             "Value",
+		FieldSyncWindow:        "Sync Window",
+		FieldDeclaredVersion:   "Declared Version",
+		FieldDeployedVersion:   "Deployed Version",
+		FieldSeverity:          "Severity",
+		FieldReleaseNotes:      "Release Notes",
+		FieldChanges:           "Changes",
+		FieldArtifactHub:       "Artifact Hub",
+		FieldSecurityReport:    "Security Report",
+		FieldSecurityFixes:     "Security Fixes",
+		FieldMaintainers:       "Maintainers",
+		FieldLinks:             "Links",
+		FieldImage:             "Image",
+		FieldCurrentTag:        "Current Tag",
+		FieldLatestTag:         "Latest Tag",
+		FieldSubchart:          "Subchart",
+		FieldValuesDiff:        "Default Values",
+
+		VersionOutsideConstraint:      "Version %s available outside constraint",
+		ShortVersionOutsideConstraint: "v%s available outside constraint",
+		UpToDateWithinConstraint:      "Up to date within '%s' constraint",
+		VersionPinned:                 "%s (pinned by %s %s)",
+		VersionMutableTag:             "%s (via mutable tag '%s', pin to %s)",
+		SyncDeferredUntil:             "Deferred until %s (%s)",
+		SyncDeferredNoWindow:          "Deferred, no allowed window within 7 days (%s)",
+		SyncOutsideAllowWindows:       "outside allow windows",
+		AppSetSummary:                 "chart %s used by %d apps, %d outdated (versions: %s; latest: %s)",
+		ScanTruncated:                 "Scan truncated: %d of %d matching applications checked (max_apps, %s selection)",
+		IgnoredUntil:                  "%s (until %s)",
+		ChartDeprecated:               "deprecated",
+		ValuesDiffSummary:             "%d added, %d removed, %d changed",
+		VulnerabilitiesFixed:          "%d known vulnerabilities fixed",
+		VulnerabilitiesIntroduced:     "%d introduced",
+		GroupUnset:                    "(none)",
+
+		TableTitle:     "ARGAZER SCAN RESULTS",
+		TableUpdates:   "APPLICATIONS WITH UPDATES AVAILABLE:",
+		TableOutside:   "UP TO DATE (with updates outside constraint):",
+		TableRelocated: "CHARTS RELOCATED OR DEPRECATED:",
+		TableTracking:  "APPLICATIONS TRACKING A BRANCH:",
+		TableDrifted:   "DEPLOYED VERSION DIFFERS FROM DECLARED:",
+		TableIgnored:   "UPDATES IGNORED BY RULES:",
+		TableAppSets:   "UPDATES BY APPLICATIONSET:",
+		TableSkipped:   "APPLICATIONS SKIPPED (Unable to check):",
+		TableImages:    "CONTAINER IMAGES WITH UPDATES AVAILABLE:",
+		TableSubcharts: "SUBCHARTS WITH UPDATES AVAILABLE:",
+
+		MarkdownTitle:     "Argazer Scan Results",
+		MarkdownSummary:   "Summary",
+		MarkdownUpdates:   "Applications with Updates Available",
+		MarkdownOutside:   "Up to Date (with updates outside constraint)",
+		MarkdownRelocated: "Charts Relocated or Deprecated",
+		MarkdownTracking:  "Applications Tracking a Branch",
+		MarkdownDrifted:   "Deployed Version Differs from Declared",
+		MarkdownIgnored:   "Updates Ignored by Rules",
+		MarkdownAppSets:   "Updates by ApplicationSet",
+		MarkdownTruncated: "%d more not shown (comment size limit)",
+		MarkdownSkipped:   "Applications Skipped",
+		MarkdownImages:    "Container Images with Updates Available",
+		MarkdownSubcharts: "Subcharts with Updates Available",
+
+		SubjectUpdates:        "Argazer Notification: %d Helm Chart Update(s) Available",
+		SubjectProjectUpdates: "Argazer Notification [%s]: %d Helm Chart Update(s) Available",
+		SubjectSplitUpdates:   "Argazer Notification [%s]: %d Update(s)",
+	},
+	"de": {
+		LabelTotal:     "Geprüfte Anwendungen insgesamt",
+		LabelUpToDate:  "Aktuell",
+		LabelUpdates:   "Updates verfügbar",
+		LabelRelocated: "Verschoben",
+		LabelTracking:  "Folgen einem Branch",
+		LabelDrifted:   "Abweichend",
+		LabelIgnored:   "Ignoriert",
+		LabelSkipped:   "Übersprungen",
+		LabelImages:    "Image-Updates verfügbar",
+		LabelSubcharts: "Subchart-Updates verfügbar",
+
+		FieldApplication:       "Anwendung",
+		FieldProject:           "Projekt",
+		FieldNamespace:         "Namespace",
+		FieldInstance:          "Instanz",
+		FieldCluster:           "Cluster",
+		FieldApplicationSet:    "ApplicationSet",
+		FieldLink:              "Link",
+		FieldValues:            "Values",
+		FieldChart:             "Chart",
+		FieldCurrentVersion:    "Aktuelle Version",
+		FieldLatestVersion:     "Neueste Version",
+		FieldLatestVersionAll:  "Neueste Version (alle)",
+		FieldVersionConstraint: "Versionsbeschränkung",
+		FieldConstraint:        "Beschränkung",
+		FieldVersion:           "Version",
+		FieldRepository:        "Repository",
+		FieldRepo:              "Repo",
+		FieldStatus:            "Status",
+		FieldBranch:            "Branch",
+		FieldChartVersion:      "Chart-Version",
+		FieldReason:            "Grund",
+		FieldError:             "Fehler",
+		FieldNote:              "Hinweis",
+		FieldName:              "Feld",
+		FieldValue:             "Wert",
+		FieldSyncWindow:        "Sync-Fenster",
+		FieldDeclaredVersion:   "Deklarierte Version",
+		FieldDeployedVersion:   "Bereitgestellte Version",
+		FieldSeverity:          "Schweregrad",
+		FieldReleaseNotes:      "Versionshinweise",
+		FieldChanges:           "Änderungen",
+		FieldArtifactHub:       "Artifact Hub",
+		FieldSecurityReport:    "Sicherheitsbericht",
+		FieldSecurityFixes:     "Sicherheitskorrekturen",
+		FieldMaintainers:       "Maintainer",
+		FieldLinks:             "Links",
+		FieldImage:             "Image",
+		FieldCurrentTag:        "Aktueller Tag",
+		FieldLatestTag:         "Neuester Tag",
+		FieldSubchart:          "Subchart",
+		FieldValuesDiff:        "Standardwerte",
+
+		VersionOutsideConstraint:      "Version %s außerhalb der Beschränkung verfügbar",
+		ShortVersionOutsideConstraint: "v%s außerhalb der Beschränkung verfügbar",
+		UpToDateWithinConstraint:      "Aktuell innerhalb der Beschränkung '%s'",
+		VersionPinned:                 "%s (fixiert per %s %s)",
+		VersionMutableTag:             "%s (über veränderlichen Tag '%s', auf %s fixieren)",
+		SyncDeferredUntil:             "Zurückgestellt bis %s (%s)",
+		SyncDeferredNoWindow:          "Zurückgestellt, kein erlaubtes Fenster in den nächsten 7 Tagen (%s)",
+		SyncOutsideAllowWindows:       "außerhalb der Erlaubnisfenster",
+		AppSetSummary:                 "Chart %s in %d Anwendungen verwendet, %d veraltet (Versionen: %s; neueste: %s)",
+		ScanTruncated:                 "Scan gekürzt: %d von %d passenden Anwendungen geprüft (max_apps, Auswahl: %s)",
+		IgnoredUntil:                  "%s (bis %s)",
+		ChartDeprecated:               "veraltet",
+		ValuesDiffSummary:             "%d hinzugefügt, %d entfernt, %d geändert",
+		VulnerabilitiesFixed:          "%d bekannte Schwachstellen behoben",
+		VulnerabilitiesIntroduced:     "%d neu",
+		GroupUnset:                    "(keine)",
+
+		TableTitle:     "ARGAZER-SCANERGEBNISSE",
+		TableUpdates:   "ANWENDUNGEN MIT VERFÜGBAREN UPDATES:",
+		TableOutside:   "AKTUELL (mit Updates außerhalb der Beschränkung):",
+		TableRelocated: "VERSCHOBENE ODER VERALTETE CHARTS:",
+		TableTracking:  "ANWENDUNGEN, DIE EINEM BRANCH FOLGEN:",
+		TableDrifted:   "BEREITGESTELLTE VERSION WEICHT VON DER DEKLARIERTEN AB:",
+		TableIgnored:   "DURCH REGELN IGNORIERTE UPDATES:",
+		TableAppSets:   "UPDATES NACH APPLICATIONSET:",
+		TableSkipped:   "ÜBERSPRUNGENE ANWENDUNGEN (Prüfung nicht möglich):",
+		TableImages:    "CONTAINER-IMAGES MIT VERFÜGBAREN UPDATES:",
+		TableSubcharts: "SUBCHARTS MIT VERFÜGBAREN UPDATES:",
+
+		MarkdownTitle:     "Argazer-Scanergebnisse",
+		MarkdownSummary:   "Zusammenfassung",
+		MarkdownUpdates:   "Anwendungen mit verfügbaren Updates",
+		MarkdownOutside:   "Aktuell (mit Updates außerhalb der Beschränkung)",
+		MarkdownRelocated: "Verschobene oder veraltete Charts",
+		MarkdownTracking:  "Anwendungen, die einem Branch folgen",
+		MarkdownDrifted:   "Bereitgestellte Version weicht von der deklarierten ab",
+		MarkdownIgnored:   "Durch Regeln ignorierte Updates",
+		MarkdownAppSets:   "Updates nach ApplicationSet",
+		MarkdownTruncated: "%d weitere nicht angezeigt (Größenlimit für Kommentare)",
+		MarkdownSkipped:   "Übersprungene Anwendungen",
+		MarkdownImages:    "Container-Images mit verfügbaren Updates",
+		MarkdownSubcharts: "Subcharts mit verfügbaren Updates",
+
+		SubjectUpdates:        "Argazer-Benachrichtigung: %d Helm-Chart-Update(s) verfügbar",
+		SubjectProjectUpdates: "Argazer-Benachrichtigung [%s]: %d Helm-Chart-Update(s) verfügbar",
+		SubjectSplitUpdates:   "Argazer-Benachrichtigung [%s]: %d Update(s)",
+	},
+	"fr": {
+		LabelTotal:     "Applications vérifiées au total",
+		LabelUpToDate:  "À jour",
+		LabelUpdates:   "Mises à jour disponibles",
+		LabelRelocated: "Déplacées",
+		LabelTracking:  "Suivent une branche",
+		LabelDrifted:   "Divergentes",
+		LabelIgnored:   "Exclues",
+		LabelSkipped:   "Ignorées",
+		LabelImages:    "Mises à jour d'images disponibles",
+		LabelSubcharts: "Mises à jour de sous-charts disponibles",
+
+		FieldApplication:       "Application",
+		FieldProject:           "Projet",
+		FieldNamespace:         "Namespace",
+		FieldInstance:          "Instance",
+		FieldCluster:           "Cluster",
+		FieldApplicationSet:    "ApplicationSet",
+		FieldLink:              "Lien",
+		FieldValues:            "Valeurs",
+		FieldChart:             "Chart",
+		FieldCurrentVersion:    "Version actuelle",
+		FieldLatestVersion:     "Dernière version",
+		FieldLatestVersionAll:  "Dernière version (toutes)",
+		FieldVersionConstraint: "Contrainte de version",
+		FieldConstraint:        "Contrainte",
+		FieldVersion:           "Version",
+		FieldRepository:        "Dépôt",
+		FieldRepo:              "Dépôt",
+		FieldStatus:            "Statut",
+		FieldBranch:            "Branche",
+		FieldChartVersion:      "Version du chart",
+		FieldReason:            "Raison",
+		FieldError:             "Erreur",
+		FieldNote:              "Remarque",
+		FieldName:              "Champ",
+		FieldValue:             "Valeur",
+		FieldSyncWindow:        "Fenêtre de synchronisation",
+		FieldDeclaredVersion:   "Version déclarée",
+		FieldDeployedVersion:   "Version déployée",
+		FieldSeverity:          "Gravité",
+		FieldReleaseNotes:      "Notes de version",
+		FieldChanges:           "Modifications",
+		FieldArtifactHub:       "Artifact Hub",
+		FieldSecurityReport:    "Rapport de sécurité",
+		FieldSecurityFixes:     "Correctifs de sécurité",
+		FieldMaintainers:       "Mainteneurs",
+		FieldLinks:             "Liens",
+		FieldImage:             "Image",
+		FieldCurrentTag:        "Tag actuel",
+		FieldLatestTag:         "Dernier tag",
+		FieldSubchart:          "Sous-chart",
+		FieldValuesDiff:        "Valeurs par défaut",
+
+		VersionOutsideConstraint:      "Version %s disponible hors contrainte",
+		ShortVersionOutsideConstraint: "v%s disponible hors contrainte",
+		UpToDateWithinConstraint:      "À jour dans la contrainte '%s'",
+		VersionPinned:                 "%s (épinglée par %s %s)",
+		VersionMutableTag:             "%s (via le tag mutable '%s', épingler sur %s)",
+		SyncDeferredUntil:             "Reporté jusqu'au %s (%s)",
+		SyncDeferredNoWindow:          "Reporté, aucune fenêtre autorisée dans les 7 prochains jours (%s)",
+		SyncOutsideAllowWindows:       "hors des fenêtres autorisées",
+		AppSetSummary:                 "chart %s utilisé par %d applications, %d obsolètes (versions : %s ; dernière : %s)",
+		ScanTruncated:                 "Analyse tronquée : %d applications vérifiées sur %d correspondantes (max_apps, sélection %s)",
+		IgnoredUntil:                  "%s (jusqu'au %s)",
+		ChartDeprecated:               "obsolète",
+		ValuesDiffSummary:             "%d ajoutées, %d supprimées, %d modifiées",
+		VulnerabilitiesFixed:          "%d vulnérabilités connues corrigées",
+		VulnerabilitiesIntroduced:     "%d introduites",
+		GroupUnset:                    "(aucun)",
+
+		TableTitle:     "RÉSULTATS DE L'ANALYSE ARGAZER",
+		TableUpdates:   "APPLICATIONS AVEC MISES À JOUR DISPONIBLES:",
+		TableOutside:   "À JOUR (avec mises à jour hors contrainte):",
+		TableRelocated: "CHARTS DÉPLACÉS OU OBSOLÈTES:",
+		TableTracking:  "APPLICATIONS SUIVANT UNE BRANCHE:",
+		TableDrifted:   "VERSION DÉPLOYÉE DIFFÉRENTE DE LA VERSION DÉCLARÉE:",
+		TableIgnored:   "MISES À JOUR EXCLUES PAR DES RÈGLES:",
+		TableAppSets:   "MISES À JOUR PAR APPLICATIONSET:",
+		TableSkipped:   "APPLICATIONS IGNORÉES (vérification impossible):",
+		TableImages:    "IMAGES DE CONTENEUR AVEC MISES À JOUR DISPONIBLES:",
+		TableSubcharts: "SOUS-CHARTS AVEC MISES À JOUR DISPONIBLES:",
+
+		MarkdownTitle:     "Résultats de l'analyse Argazer",
+		MarkdownSummary:   "Résumé",
+		MarkdownUpdates:   "Applications avec mises à jour disponibles",
+		MarkdownOutside:   "À jour (avec mises à jour hors contrainte)",
+		MarkdownRelocated: "Charts déplacés ou obsolètes",
+		MarkdownTracking:  "Applications suivant une branche",
+		MarkdownDrifted:   "Version déployée différente de la version déclarée",
+		MarkdownIgnored:   "Mises à jour exclues par des règles",
+		MarkdownAppSets:   "Mises à jour par ApplicationSet",
+		MarkdownTruncated: "%d de plus non affichées (limite de taille des commentaires)",
+		MarkdownSkipped:   "Applications ignorées",
+		MarkdownImages:    "Images de conteneur avec mises à jour disponibles",
+		MarkdownSubcharts: "Sous-charts avec mises à jour disponibles",
+
+		SubjectUpdates:        "Notification Argazer: %d mise(s) à jour de chart Helm disponible(s)",
+		SubjectProjectUpdates: "Notification Argazer [%s]: %d mise(s) à jour de chart Helm disponible(s)",
+		SubjectSplitUpdates:   "Notification Argazer [%s]: %d mise(s) à jour",
+	},
+	"es": {
+		LabelTotal:     "Total de aplicaciones comprobadas",
+		LabelUpToDate:  "Actualizadas",
+		LabelUpdates:   "Actualizaciones disponibles",
+		LabelRelocated: "Reubicadas",
+		LabelTracking:  "Siguen una rama",
+		LabelDrifted:   "Divergentes",
+		LabelIgnored:   "Ignoradas",
+		LabelSkipped:   "Omitidas",
+		LabelImages:    "Actualizaciones de imágenes disponibles",
+		LabelSubcharts: "Actualizaciones de subcharts disponibles",
+
+		FieldApplication:       "Aplicación",
+		FieldProject:           "Proyecto",
+		FieldNamespace:         "Espacio de nombres",
+		FieldInstance:          "Instancia",
+		FieldCluster:           "Clúster",
+		FieldApplicationSet:    "ApplicationSet",
+		FieldLink:              "Enlace",
+		FieldValues:            "Valores",
+		FieldChart:             "Chart",
+		FieldCurrentVersion:    "Versión actual",
+		FieldLatestVersion:     "Última versión",
+		FieldLatestVersionAll:  "Última versión (todas)",
+		FieldVersionConstraint: "Restricción de versión",
+		FieldConstraint:        "Restricción",
+		FieldVersion:           "Versión",
+		FieldRepository:        "Repositorio",
+		FieldRepo:              "Repositorio",
+		FieldStatus:            "Estado",
+		FieldBranch:            "Rama",
+		FieldChartVersion:      "Versión del chart",
+		FieldReason:            "Motivo",
+		FieldError:             "Error",
+		FieldNote:              "Nota",
+		FieldName:              "Campo",
+		FieldValue:             "Valor",
+		FieldSyncWindow:        "Ventana de sincronización",
+		FieldDeclaredVersion:   "Versión declarada",
+		FieldDeployedVersion:   "Versión desplegada",
+		FieldSeverity:          "Severidad",
+		FieldReleaseNotes:      "Notas de la versión",
+		FieldChanges:           "Cambios",
+		FieldArtifactHub:       "Artifact Hub",
+		FieldSecurityReport:    "Informe de seguridad",
+		FieldSecurityFixes:     "Correcciones de seguridad",
+		FieldMaintainers:       "Mantenedores",
+		FieldLinks:             "Enlaces",
+		FieldImage:             "Imagen",
+		FieldCurrentTag:        "Tag actual",
+		FieldLatestTag:         "Último tag",
+		FieldSubchart:          "Subchart",
+		FieldValuesDiff:        "Valores predeterminados",
+
+		VersionOutsideConstraint:      "Versión %s disponible fuera de la restricción",
+		ShortVersionOutsideConstraint: "v%s disponible fuera de la restricción",
+		UpToDateWithinConstraint:      "Actualizada dentro de la restricción '%s'",
+		VersionPinned:                 "%s (fijada por %s %s)",
+		VersionMutableTag:             "%s (mediante la etiqueta mutable '%s', fijar a %s)",
+		SyncDeferredUntil:             "Aplazado hasta %s (%s)",
+		SyncDeferredNoWindow:          "Aplazado, ninguna ventana permitida en los próximos 7 días (%s)",
+		SyncOutsideAllowWindows:       "fuera de las ventanas permitidas",
+		AppSetSummary:                 "chart %s usado por %d aplicaciones, %d desactualizadas (versiones: %s; última: %s)",
+		ScanTruncated:                 "Análisis truncado: %d de %d aplicaciones coincidentes comprobadas (max_apps, selección %s)",
+		IgnoredUntil:                  "%s (hasta el %s)",
+		ChartDeprecated:               "obsoleto",
+		ValuesDiffSummary:             "%d añadidas, %d eliminadas, %d modificadas",
+		VulnerabilitiesFixed:          "%d vulnerabilidades conocidas corregidas",
+		VulnerabilitiesIntroduced:     "%d introducidas",
+		GroupUnset:                    "(ninguno)",
+
+		TableTitle:     "RESULTADOS DEL ANÁLISIS DE ARGAZER",
+		TableUpdates:   "APLICACIONES CON ACTUALIZACIONES DISPONIBLES:",
+		TableOutside:   "ACTUALIZADAS (con actualizaciones fuera de la restricción):",
+		TableRelocated: "CHARTS REUBICADOS U OBSOLETOS:",
+		TableTracking:  "APLICACIONES QUE SIGUEN UNA RAMA:",
+		TableDrifted:   "VERSIÓN DESPLEGADA DISTINTA DE LA DECLARADA:",
+		TableIgnored:   "ACTUALIZACIONES IGNORADAS POR REGLAS:",
+		TableAppSets:   "ACTUALIZACIONES POR APPLICATIONSET:",
+		TableSkipped:   "APLICACIONES OMITIDAS (no se pudieron comprobar):",
+		TableImages:    "IMÁGENES DE CONTENEDOR CON ACTUALIZACIONES DISPONIBLES:",
+		TableSubcharts: "SUBCHARTS CON ACTUALIZACIONES DISPONIBLES:",
+
+		MarkdownTitle:     "Resultados del análisis de Argazer",
+		MarkdownSummary:   "Resumen",
+		MarkdownUpdates:   "Aplicaciones con actualizaciones disponibles",
+		MarkdownOutside:   "Actualizadas (con actualizaciones fuera de la restricción)",
+		MarkdownRelocated: "Charts reubicados u obsoletos",
+		MarkdownTracking:  "Aplicaciones que siguen una rama",
+		MarkdownDrifted:   "Versión desplegada distinta de la declarada",
+		MarkdownIgnored:   "Actualizaciones ignoradas por reglas",
+		MarkdownAppSets:   "Actualizaciones por ApplicationSet",
+		MarkdownTruncated: "%d más no mostradas (límite de tamaño de comentarios)",
+		MarkdownSkipped:   "Aplicaciones omitidas",
+		MarkdownImages:    "Imágenes de contenedor con actualizaciones disponibles",
+		MarkdownSubcharts: "Subcharts con actualizaciones disponibles",
+
+		SubjectUpdates:        "Notificación de Argazer: %d actualización(es) de charts de Helm disponible(s)",
+		SubjectProjectUpdates: "Notificación de Argazer [%s]: %d actualización(es) de charts de Helm disponible(s)",
+		SubjectSplitUpdates:   "Notificación de Argazer [%s]: %d actualización(es)",
+	},
+}
@@ -0,0 +1,72 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is used when no language is configured
+const DefaultLanguage = "en"
+
+// Localizer formats messages in a single language
+// A nil Localizer formats messages in English.
+type Localizer struct {
+	language string
+	messages map[string]string
+}
+
+// IsSupported reports whether a catalog exists for the language
+func IsSupported(language string) bool {
+	_, ok := catalogs[strings.ToLower(language)]
+	return ok
+}
+
+// Languages returns the codes of all supported languages, sorted
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// New returns a localizer for the language
+// Unsupported languages fall back to English; configuration validation rejects them up front.
+func New(language string) *Localizer {
+	language = strings.ToLower(language)
+	messages, ok := catalogs[language]
+	if !ok {
+		language = DefaultLanguage
+		messages = catalogs[DefaultLanguage]
+	}
+	return &Localizer{language: language, messages: messages}
+}
+
+// Language returns the language code of the localizer
+func (l *Localizer) Language() string {
+	if l == nil {
+		return DefaultLanguage
+	}
+	return l.language
+}
+
+// T formats the message with the given key, falling back to English for missing translations
+// Unknown keys are returned as-is so a missing entry is visible rather than silently empty.
+func (l *Localizer) T(key string, args ...any) string {
+	format, ok := "", false
+	if l != nil {
+		format, ok = l.messages[key]
+	}
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,59 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogs_Complete(t *testing.T) {
+	english := catalogs[DefaultLanguage]
+	for language, messages := range catalogs {
+		assert.Len(t, messages, len(english), "catalog %s has a different number of messages", language)
+		for key, format := range english {
+			translated, ok := messages[key]
+			if !assert.True(t, ok, "catalog %s is missing %s", language, key) {
+				continue
+			}
+			assert.Equal(t, strings.Count(format, "%"), strings.Count(translated, "%"), "catalog %s has different arguments for %s", language, key)
+		}
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	assert.Equal(t, []string{"de", "en", "es", "fr"}, Languages())
+	assert.True(t, IsSupported("de"))
+	assert.True(t, IsSupported("FR"))
+	assert.False(t, IsSupported("it"))
+	assert.False(t, IsSupported(""))
+}
+
+func TestLocalizer_T(t *testing.T) {
+	de := New("de")
+	assert.Equal(t, "de", de.Language())
+	assert.Equal(t, "Projekt", de.T(FieldProject))
+	assert.Equal(t, "Argazer-Benachrichtigung [prod]: 2 Update(s)", de.T(SubjectSplitUpdates, "prod", 2))
+
+	es := New("ES")
+	assert.Equal(t, "es", es.Language())
+	assert.Equal(t, "Versión 2.0.0 disponible fuera de la restricción", es.T(VersionOutsideConstraint, "2.0.0"))
+}
+
+func TestLocalizer_Fallbacks(t *testing.T) {
+	// Unsupported languages use English
+	unknown := New("it")
+	assert.Equal(t, DefaultLanguage, unknown.Language())
+	assert.Equal(t, "Project", unknown.T(FieldProject))
+
+	// A nil localizer uses English
+	var nilLocalizer *Localizer
+	assert.Equal(t, DefaultLanguage, nilLocalizer.Language())
+	assert.Equal(t, "Argazer Notification: 3 Helm Chart Update(s) Available", nilLocalizer.T(SubjectUpdates, 3))
+
+	// Missing translations use English, unknown keys are returned as-is
+	partial := &Localizer{language: "xx", messages: map[string]string{FieldProject: "Projekt"}}
+	assert.Equal(t, "Projekt", partial.T(FieldProject))
+	assert.Equal(t, "Chart", partial.T(FieldChart))
+	assert.Equal(t, "no.such.key", partial.T("no.such.key"))
+}
@@ -0,0 +1,110 @@
+package i18n
+
+// Message keys shared by the report renderers and notifications
+const (
+	// Summary labels
+	LabelTotal     = "label.total"
+	LabelUpToDate  = "label.up_to_date"
+	LabelUpdates   = "label.updates"
+	LabelRelocated = "label.relocated"
+	LabelTracking  = "label.tracking"
+	LabelDrifted   = "label.drifted"
+	LabelIgnored   = "label.ignored"
+	LabelSkipped   = "label.skipped"
+	LabelImages    = "label.images"
+	LabelSubcharts = "label.subcharts"
+
+	// Field labels
+	FieldApplication       = "field.application"
+	FieldProject           = "field.project"
+	FieldNamespace         = "field.namespace"
+	FieldInstance          = "field.instance"
+	FieldCluster           = "field.cluster"
+	FieldApplicationSet    = "field.application_set"
+	FieldLink              = "field.link"
+	FieldValues            = "field.values"
+	FieldChart             = "field.chart"
+	FieldCurrentVersion    = "field.current_version"
+	FieldLatestVersion     = "field.latest_version"
+	FieldLatestVersionAll  = "field.latest_version_all"
+	FieldVersionConstraint = "field.version_constraint"
+	FieldConstraint        = "field.constraint"
+	FieldVersion           = "field.version"
+	FieldRepository        = "field.repository"
+	FieldRepo              = "field.repo"
+	FieldStatus            = "field.status"
+	FieldBranch            = "field.branch"
+	FieldChartVersion      = "field.chart_version"
+	FieldReason            = "field.reason"
+	FieldError             = "field.error"
+	FieldNote              = "field.note"
+	FieldName              = "field.name"
+	FieldValue             = "field.value"
+	FieldSyncWindow        = "field.sync_window"
+	FieldDeclaredVersion   = "field.declared_version"
+	FieldDeployedVersion   = "field.deployed_version"
+	FieldSeverity          = "field.severity"
+	FieldReleaseNotes      = "field.release_notes"
+	FieldChanges           = "field.changes"
+	FieldArtifactHub       = "field.artifacthub"
+	FieldSecurityReport    = "field.security_report"
+	FieldSecurityFixes     = "field.security_fixes"
+	FieldMaintainers       = "field.maintainers"
+	FieldLinks             = "field.links"
+	FieldImage             = "field.image"
+	FieldCurrentTag        = "field.current_tag"
+	FieldLatestTag         = "field.latest_tag"
+	FieldSubchart          = "field.subchart"
+	FieldValuesDiff        = "field.values_diff"
+
+	// Sentences
+	VersionOutsideConstraint      = "msg.version_outside_constraint"       // args: version
+	ShortVersionOutsideConstraint = "msg.short_version_outside_constraint" // args: version
+	UpToDateWithinConstraint      = "msg.up_to_date_within_constraint"     // args: constraint
+	VersionPinned                 = "msg.version_pinned"                   // args: version, pin kind, revision
+	VersionMutableTag             = "msg.version_mutable_tag"              // args: version, tag, recommended version
+	SyncDeferredUntil             = "msg.sync_deferred_until"              // args: time, blocking window
+	SyncDeferredNoWindow          = "msg.sync_deferred_no_window"          // args: blocking window
+	SyncOutsideAllowWindows       = "msg.sync_outside_allow_windows"
+	AppSetSummary                 = "msg.appset_summary" // args: chart, apps, outdated apps, versions, latest version
+	ScanTruncated                 = "msg.scan_truncated" // args: checked apps, matching apps, selection mode
+	IgnoredUntil                  = "msg.ignored_until"  // args: reason, last ignored day
+	ChartDeprecated               = "msg.chart_deprecated"
+	ValuesDiffSummary             = "msg.values_diff_summary"        // args: added, removed and changed key counts
+	VulnerabilitiesFixed          = "msg.vulnerabilities_fixed"      // args: count
+	VulnerabilitiesIntroduced     = "msg.vulnerabilities_introduced" // args: count
+	GroupUnset                    = "msg.group_unset"
+
+	// Table report headings
+	TableTitle     = "table.title"
+	TableUpdates   = "table.updates"
+	TableOutside   = "table.outside_constraint"
+	TableRelocated = "table.relocated"
+	TableTracking  = "table.tracking"
+	TableDrifted   = "table.drifted"
+	TableIgnored   = "table.ignored"
+	TableAppSets   = "table.appsets"
+	TableSkipped   = "table.skipped"
+	TableImages    = "table.images"
+	TableSubcharts = "table.subcharts"
+
+	// Markdown report headings
+	MarkdownTitle     = "markdown.title"
+	MarkdownSummary   = "markdown.summary"
+	MarkdownUpdates   = "markdown.updates"
+	MarkdownOutside   = "markdown.outside_constraint"
+	MarkdownRelocated = "markdown.relocated"
+	MarkdownTracking  = "markdown.tracking"
+	MarkdownDrifted   = "markdown.drifted"
+	MarkdownIgnored   = "markdown.ignored"
+	MarkdownAppSets   = "markdown.appsets"
+	MarkdownTruncated = "markdown.truncated" // args: number of rows left out
+	MarkdownSkipped   = "markdown.skipped"
+	MarkdownImages    = "markdown.images"
+	MarkdownSubcharts = "markdown.subcharts"
+
+	// Notification subjects
+	SubjectUpdates        = "subject.updates"         // args: update count
+	SubjectProjectUpdates = "subject.project_updates" // args: project label, update count
+	SubjectSplitUpdates   = "subject.split_updates"   // args: project label, update count
+)
@@ -0,0 +1,167 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// Producer defaults
+const (
+	defaultClientID        = "argazer"
+	defaultDialTimeout     = 10 * time.Second
+	defaultDeliveryTimeout = 30 * time.Second // Bounds retries when the context has no deadline
+)
+
+// Supported SASL mechanisms
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// Config holds the connection settings of a Producer
+type Config struct {
+	Brokers       []string    // Bootstrap brokers as host:port
+	ClientID      string      // Client ID reported to brokers (default: argazer)
+	TLS           *tls.Config // TLS settings; nil for plaintext connections
+	SASLMechanism string      // PLAIN, SCRAM-SHA-256, SCRAM-SHA-512, or empty to disable SASL
+	Username      string
+	Password      string
+}
+
+// Header is a record header
+type Header struct {
+	Key   string
+	Value string
+}
+
+// Record is a single message published to a topic
+type Record struct {
+	Key     []byte
+	Value   []byte
+	Headers []Header
+}
+
+// Producer publishes small batches of records with a franz-go client
+// It opens a client per Produce call, which suits infrequent notification traffic.
+type Producer struct {
+	cfg    Config
+	opts   []kgo.Opt
+	logger *logrus.Entry
+}
+
+// NewProducer creates a new producer
+func NewProducer(cfg Config, logger *logrus.Entry) (*Producer, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("at least one Kafka broker is required")
+	}
+	if cfg.ClientID == "" {
+		cfg.ClientID = defaultClientID
+	}
+
+	// Records are keyed by application, and the default partitioner hashes keys like the Java client.
+	// Idempotent writes are disabled so producing needs no cluster-level IDEMPOTENT_WRITE ACL on older brokers.
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.ClientID(cfg.ClientID),
+		kgo.DialTimeout(defaultDialTimeout),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.DisableIdempotentWrite(),
+		kgo.RecordDeliveryTimeout(defaultDeliveryTimeout),
+	}
+	if cfg.TLS != nil {
+		opts = append(opts, kgo.DialTLSConfig(cfg.TLS))
+	}
+	if cfg.SASLMechanism != "" {
+		mechanism, err := saslMechanism(cfg.SASLMechanism, cfg.Username, cfg.Password)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kgo.SASL(mechanism))
+	}
+
+	return &Producer{
+		cfg:    cfg,
+		opts:   opts,
+		logger: logger,
+	}, nil
+}
+
+// saslMechanism returns the franz-go mechanism for the given name
+func saslMechanism(mechanism, username, password string) (sasl.Mechanism, error) {
+	switch strings.ToUpper(mechanism) {
+	case SASLPlain:
+		return plain.Auth{User: username, Pass: password}.AsMechanism(), nil
+	case SASLScramSHA256:
+		return scram.Auth{User: username, Pass: password}.AsSha256Mechanism(), nil
+	case SASLScramSHA512:
+		return scram.Auth{User: username, Pass: password}.AsSha512Mechanism(), nil
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism %q (supported: %s, %s, %s)", mechanism, SASLPlain, SASLScramSHA256, SASLScramSHA512)
+	}
+}
+
+// Produce publishes records to a topic and waits for all in-sync replicas to acknowledge them
+func (p *Producer) Produce(ctx context.Context, topic string, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	client, err := kgo.NewClient(p.opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+	defer client.Close()
+
+	krecords := make([]*kgo.Record, 0, len(records))
+	for _, record := range records {
+		krecord := &kgo.Record{Topic: topic, Key: record.Key, Value: record.Value}
+		for _, header := range record.Headers {
+			krecord.Headers = append(krecord.Headers, kgo.RecordHeader{Key: header.Key, Value: []byte(header.Value)})
+		}
+		krecords = append(krecords, krecord)
+	}
+
+	var errs []error
+	failed := 0
+	for _, result := range client.ProduceSync(ctx, krecords...) {
+		if result.Err != nil {
+			failed++
+			errs = append(errs, result.Err)
+		}
+	}
+	if failed > 0 {
+		// Records of a batch usually fail for the same reason
+		return fmt.Errorf("failed to produce %d of %d record(s): %w", failed, len(records), errors.Join(dedupErrors(errs)...))
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"topic":   topic,
+		"records": len(records),
+	}).Debug("Produced Kafka records")
+	return nil
+}
+
+// dedupErrors drops errors with the same message as an earlier one
+func dedupErrors(errs []error) []error {
+	seen := make(map[string]bool, len(errs))
+	var unique []error
+	for _, err := range errs {
+		if seen[err.Error()] {
+			continue
+		}
+		seen[err.Error()] = true
+		unique = append(unique, err)
+	}
+	return unique
+}
@@ -0,0 +1,162 @@
+package kafka
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// newFakeCluster starts an in-memory Kafka cluster with the topic
+func newFakeCluster(t *testing.T, topic string, partitions int32, opts ...kfake.Opt) *kfake.Cluster {
+	t.Helper()
+	cluster, err := kfake.NewCluster(append([]kfake.Opt{kfake.NumBrokers(1), kfake.SeedTopics(partitions, topic)}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(cluster.Close)
+	return cluster
+}
+
+// consumeAll reads every record of a topic from the cluster
+func consumeAll(t *testing.T, cluster *kfake.Cluster, topic string, expected int) []*kgo.Record {
+	t.Helper()
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(cluster.ListenAddrs()...),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var records []*kgo.Record
+	for len(records) < expected {
+		fetches := client.PollFetches(ctx)
+		require.NoError(t, ctx.Err(), "timed out waiting for records")
+		records = append(records, fetches.Records()...)
+	}
+	return records
+}
+
+func testRecords(n int) []Record {
+	var records []Record
+	for i := 0; i < n; i++ {
+		records = append(records, Record{
+			Key:     []byte("app-" + strconv.Itoa(i)),
+			Value:   []byte(`{}`),
+			Headers: []Header{{Key: "event", Value: "update_available"}},
+		})
+	}
+	return records
+}
+
+func TestNewProducer_Validation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	_, err := NewProducer(Config{}, logger)
+	assert.Error(t, err, "brokers are required")
+
+	_, err = NewProducer(Config{Brokers: []string{"localhost:9092"}, SASLMechanism: "GSSAPI"}, logger)
+	assert.Error(t, err, "unsupported SASL mechanism")
+
+	for _, mechanism := range []string{SASLPlain, SASLScramSHA256, "scram-sha-512"} {
+		_, err = NewProducer(Config{Brokers: []string{"localhost:9092"}, SASLMechanism: mechanism}, logger)
+		assert.NoError(t, err, mechanism)
+	}
+
+	producer, err := NewProducer(Config{Brokers: []string{"localhost:9092"}}, logger)
+	require.NoError(t, err)
+	assert.Equal(t, defaultClientID, producer.cfg.ClientID)
+}
+
+func TestProducer_Produce(t *testing.T) {
+	cluster := newFakeCluster(t, "chart-updates", 3)
+
+	producer, err := NewProducer(Config{Brokers: cluster.ListenAddrs()}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	require.NoError(t, producer.Produce(context.Background(), "chart-updates", testRecords(10)))
+
+	records := consumeAll(t, cluster, "chart-updates", 10)
+	require.Len(t, records, 10)
+	partitions := make(map[int32]bool)
+	for _, record := range records {
+		partitions[record.Partition] = true
+		assert.Equal(t, []kgo.RecordHeader{{Key: "event", Value: []byte("update_available")}}, record.Headers)
+	}
+	assert.Greater(t, len(partitions), 1, "records are spread over partitions by key")
+}
+
+func TestProducer_Produce_SASL(t *testing.T) {
+	for _, mechanism := range []string{SASLPlain, SASLScramSHA256, SASLScramSHA512} {
+		t.Run(mechanism, func(t *testing.T) {
+			cluster := newFakeCluster(t, "chart-updates", 1, kfake.EnableSASL(), kfake.Superuser(mechanism, "user", "secret"))
+
+			producer, err := NewProducer(Config{
+				Brokers:       cluster.ListenAddrs(),
+				SASLMechanism: mechanism,
+				Username:      "user",
+				Password:      "secret",
+			}, logrus.NewEntry(logrus.New()))
+			require.NoError(t, err)
+
+			require.NoError(t, producer.Produce(context.Background(), "chart-updates", testRecords(2)))
+		})
+	}
+}
+
+func TestProducer_Produce_SASLFailure(t *testing.T) {
+	cluster := newFakeCluster(t, "chart-updates", 1, kfake.EnableSASL(), kfake.Superuser(SASLPlain, "user", "secret"))
+
+	producer, err := NewProducer(Config{
+		Brokers:       cluster.ListenAddrs(),
+		SASLMechanism: SASLPlain,
+		Username:      "user",
+		Password:      "wrong",
+	}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	// The fake cluster drops the connection instead of answering SASL_AUTHENTICATION_FAILED, so the client retries
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err = producer.Produce(ctx, "chart-updates", testRecords(1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to produce 1 of 1 record(s)")
+}
+
+func TestProducer_Produce_UnknownTopic(t *testing.T) {
+	cluster := newFakeCluster(t, "chart-updates", 1)
+
+	producer, err := NewProducer(Config{Brokers: cluster.ListenAddrs()}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	err = producer.Produce(context.Background(), "missing", testRecords(2))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, kerr.UnknownTopicOrPartition)
+	assert.Contains(t, err.Error(), "failed to produce 2 of 2 record(s)")
+}
+
+func TestProducer_Produce_UnreachableBroker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	producer, err := NewProducer(Config{Brokers: []string{addr}}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err = producer.Produce(ctx, "chart-updates", testRecords(1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to produce 1 of 1 record(s)")
+}
@@ -0,0 +1,34 @@
+//go:build darwin || linux
+
+package keychain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runCommand runs a keychain tool with the input on stdin and returns its stdout
+// The exit code is returned along with the error, so callers can tell a missing credential apart.
+func runCommand(input string, name string, args ...string) (string, int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", exitErr.ExitCode(), fmt.Errorf("%s failed: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", -1, fmt.Errorf("%w: %s is not installed", ErrUnsupported, name)
+	}
+	if err != nil {
+		return "", -1, fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return stdout.String(), 0, nil
+}
@@ -0,0 +1,92 @@
+// Package keychain stores credentials in the operating system's keychain: the macOS Keychain,
+// the Windows Credential Manager or the Secret Service (GNOME Keyring, KWallet) on Linux.
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Service is the service name argazer's credentials are stored under
+const Service = "argazer"
+
+// Prefix marks configuration values that reference a keychain credential, e.g. "keychain:argocd"
+const Prefix = "keychain:"
+
+// ErrNotFound is returned when no credential is stored under the name
+var ErrNotFound = errors.New("credential not found in keychain")
+
+// ErrUnsupported is returned when the platform has no supported keychain
+var ErrUnsupported = errors.New("OS keychain is not supported on this platform")
+
+// backend is the platform's credential store
+type backend interface {
+	set(name, secret string) error
+	get(name string) (string, error)
+	delete(name string) error
+}
+
+// store is the credential store in use, replaced by tests
+var store backend = platformBackend()
+
+// Set stores the secret under the name, replacing any existing one
+func Set(name, secret string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if secret == "" {
+		return errors.New("secret must not be empty")
+	}
+	if err := store.set(name, secret); err != nil {
+		return fmt.Errorf("failed to store %q in keychain: %w", name, err)
+	}
+	return nil
+}
+
+// Get returns the secret stored under the name
+func Get(name string) (string, error) {
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	secret, err := store.get(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %q from keychain: %w", name, err)
+	}
+	return secret, nil
+}
+
+// Delete removes the secret stored under the name
+func Delete(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if err := store.delete(name); err != nil {
+		return fmt.Errorf("failed to delete %q from keychain: %w", name, err)
+	}
+	return nil
+}
+
+// IsReference reports whether a configuration value references a keychain credential
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Resolve returns the secret a "keychain:<name>" value references, or the value itself otherwise
+func Resolve(value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	return Get(strings.TrimPrefix(value, Prefix))
+}
+
+// validateName rejects names that can't be stored safely in every backend
+func validateName(name string) error {
+	if name == "" {
+		return errors.New("credential name must not be empty")
+	}
+	if strings.ContainsAny(name, "'\"\\\n\r\t ") {
+		return fmt.Errorf("credential name %q must not contain quotes, backslashes or whitespace", name)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package keychain
+
+import (
+	"errors"
+	"strings"
+)
+
+// errSecItemNotFound is the exit code of security(1) when no matching item exists
+const errSecItemNotFound = 44
+
+// macKeychain stores generic passwords in the login keychain with security(1)
+type macKeychain struct{}
+
+func platformBackend() backend {
+	return macKeychain{}
+}
+
+func (macKeychain) set(name, secret string) error {
+	if strings.ContainsAny(secret, "\n\r") {
+		return errors.New("secret must not contain line breaks")
+	}
+	// Commands are read from stdin in interactive mode, so the secret never shows up in the process list
+	command := "add-generic-password -U -s " + Service + " -a " + name + " -l " + quoteSecurityArg(Service+": "+name) +
+		" -w " + quoteSecurityArg(secret) + "\n"
+	_, _, err := runCommand(command, "security", "-i")
+	return err
+}
+
+func (macKeychain) get(name string) (string, error) {
+	out, code, err := runCommand("", "security", "find-generic-password", "-s", Service, "-a", name, "-w")
+	if code == errSecItemNotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (macKeychain) delete(name string) error {
+	_, code, err := runCommand("", "security", "delete-generic-password", "-s", Service, "-a", name)
+	if code == errSecItemNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// quoteSecurityArg double-quotes an argument of a security(1) interactive command
+func quoteSecurityArg(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
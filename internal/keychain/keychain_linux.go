@@ -0,0 +1,37 @@
+package keychain
+
+import "strings"
+
+// secretService stores secrets with the freedesktop.org Secret Service through secret-tool(1),
+// which GNOME Keyring and KWallet both provide
+type secretService struct{}
+
+func platformBackend() backend {
+	return secretService{}
+}
+
+func (secretService) set(name, secret string) error {
+	// The secret is read from stdin, so it never shows up in the process list
+	_, _, err := runCommand(secret, "secret-tool", "store", "--label="+Service+": "+name, "service", Service, "account", name)
+	return err
+}
+
+func (secretService) get(name string) (string, error) {
+	out, code, err := runCommand("", "secret-tool", "lookup", "service", Service, "account", name)
+	if code == 1 && err != nil {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (s secretService) delete(name string) error {
+	// secret-tool clear succeeds whether or not anything matched
+	if _, err := s.get(name); err != nil {
+		return err
+	}
+	_, _, err := runCommand("", "secret-tool", "clear", "service", Service, "account", name)
+	return err
+}
@@ -0,0 +1,14 @@
+//go:build !darwin && !linux && !windows
+
+package keychain
+
+// unsupported is the backend of platforms without a supported keychain
+type unsupported struct{}
+
+func platformBackend() backend {
+	return unsupported{}
+}
+
+func (unsupported) set(name, secret string) error   { return ErrUnsupported }
+func (unsupported) get(name string) (string, error) { return "", ErrUnsupported }
+func (unsupported) delete(name string) error        { return ErrUnsupported }
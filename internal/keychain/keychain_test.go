@@ -0,0 +1,123 @@
+package keychain
+
+import (
+	"errors"
+	"testing"
+)
+
+// memoryStore is an in-memory backend for tests
+type memoryStore map[string]string
+
+func (m memoryStore) set(name, secret string) error {
+	m[name] = secret
+	return nil
+}
+
+func (m memoryStore) get(name string) (string, error) {
+	secret, ok := m[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (m memoryStore) delete(name string) error {
+	if _, ok := m[name]; !ok {
+		return ErrNotFound
+	}
+	delete(m, name)
+	return nil
+}
+
+func useMemoryStore(t *testing.T) memoryStore {
+	t.Helper()
+	previous := store
+	m := memoryStore{}
+	store = m
+	t.Cleanup(func() { store = previous })
+	return m
+}
+
+func TestSetGetDelete(t *testing.T) {
+	useMemoryStore(t)
+
+	if err := Set("argocd", "s3cret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	secret, err := Get("argocd")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret != "s3cret" {
+		t.Errorf("Get() = %q, want %q", secret, "s3cret")
+	}
+
+	if err := Delete("argocd"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := Get("argocd"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+	if err := Delete("argocd"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() of missing credential error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSet_Invalid(t *testing.T) {
+	m := useMemoryStore(t)
+
+	tests := []struct {
+		name   string
+		key    string
+		secret string
+	}{
+		{name: "empty name", key: "", secret: "x"},
+		{name: "name with space", key: "argo cd", secret: "x"},
+		{name: "name with quote", key: `argo"cd`, secret: "x"},
+		{name: "empty secret", key: "argocd", secret: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Set(tt.key, tt.secret); err == nil {
+				t.Error("Set() error = nil, want error")
+			}
+		})
+	}
+	if len(m) != 0 {
+		t.Errorf("store = %v, want nothing stored", m)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	m := useMemoryStore(t)
+	m["harbor"] = "robot-token"
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{name: "plain value", value: "plaintext", want: "plaintext"},
+		{name: "empty value", value: "", want: ""},
+		{name: "reference", value: "keychain:harbor", want: "robot-token"},
+		{name: "missing reference", value: "keychain:nexus", wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package kube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+)
+
+// serviceAccountDir is where Kubernetes mounts the pod's service account credentials
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Client is a minimal Kubernetes API client for reading resources from inside a cluster
+type Client struct {
+	host       string
+	token      string
+	namespace  string
+	httpClient *http.Client
+	logger     *logrus.Entry
+}
+
+// Secret represents the fields of a Kubernetes Secret used by argazer
+type Secret struct {
+	Metadata ObjectMeta        `json:"metadata"`
+	Data     map[string][]byte `json:"data"` // Values are base64-decoded by encoding/json
+}
+
+// ObjectMeta holds the metadata fields of a Kubernetes object
+type ObjectMeta struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// secretList represents the response of the Secret list endpoint
+type secretList struct {
+	Items []Secret `json:"items"`
+}
+
+// InCluster reports whether argazer is running inside a Kubernetes pod
+func InCluster() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" || os.Getenv("KUBERNETES_SERVICE_PORT") == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(serviceAccountDir, "token"))
+	return err == nil
+}
+
+// NewInClusterClient creates a client using the pod's service account
+// The token is read by client-go's transport, which reloads it when the kubelet rotates it.
+func NewInClusterClient(logger *logrus.Entry) (*Client, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load in-cluster configuration: %w", err)
+	}
+	config.Timeout = 30 * time.Second
+
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes HTTP client: %w", err)
+	}
+
+	namespace := ""
+	if ns, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace")); err == nil {
+		namespace = strings.TrimSpace(string(ns))
+	}
+
+	return NewClient(config.Host, "", namespace, httpClient, logger), nil
+}
+
+// NewClient creates a client for the given API server URL and bearer token (empty when httpClient authenticates)
+func NewClient(host, token, namespace string, httpClient *http.Client, logger *logrus.Entry) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &Client{
+		host:       strings.TrimSuffix(host, "/"),
+		token:      token,
+		namespace:  namespace,
+		httpClient: httpClient,
+		logger:     logger,
+	}
+}
+
+// Namespace returns the namespace the client runs in (empty if unknown)
+func (c *Client) Namespace() string {
+	return c.namespace
+}
+
+// ListSecrets lists Secrets in a namespace matching the label selector
+func (c *Client) ListSecrets(ctx context.Context, namespace, labelSelector string) ([]Secret, error) {
+	path := fmt.Sprintf("/api/v1/namespaces/%s/secrets", url.PathEscape(namespace))
+	query := url.Values{}
+	if labelSelector != "" {
+		query.Set("labelSelector", labelSelector)
+	}
+
+	var list secretList
+	if err := c.get(ctx, path, query, &list); err != nil {
+		return nil, fmt.Errorf("failed to list secrets in namespace %s: %w", namespace, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"selector":  labelSelector,
+		"count":     len(list.Items),
+	}).Debug("Listed Kubernetes secrets")
+
+	return list.Items, nil
+}
+
+// ResourcePath returns the API path of a resource collection, across all namespaces when namespace is empty
+// Resources of the core group have an empty group.
+func ResourcePath(group, version, namespace, resource string) string {
+	path := "/apis/" + group + "/" + version
+	if group == "" {
+		path = "/api/" + version
+	}
+	if namespace != "" {
+		path += "/namespaces/" + url.PathEscape(namespace)
+	}
+	return path + "/" + resource
+}
+
+// List lists the resources of a collection matching the label selector and decodes the list into out
+func (c *Client) List(ctx context.Context, path, labelSelector string, out interface{}) error {
+	query := url.Values{}
+	if labelSelector != "" {
+		query.Set("labelSelector", labelSelector)
+	}
+	if err := c.get(ctx, path, query, out); err != nil {
+		return fmt.Errorf("failed to list %s: %w", path, err)
+	}
+	return nil
+}
+
+// Get reads a single resource and decodes it into out
+func (c *Client) Get(ctx context.Context, path string, out interface{}) error {
+	if err := c.get(ctx, path, nil, out); err != nil {
+		return fmt.Errorf("failed to get %s: %w", path, err)
+	}
+	return nil
+}
+
+// Patch applies a JSON patch (RFC 6902) to a resource and decodes the patched resource into out (if not nil)
+func (c *Client) Patch(ctx context.Context, path string, patch []byte, out interface{}) error {
+	if err := c.do(ctx, http.MethodPatch, path, nil, patch, out); err != nil {
+		return fmt.Errorf("failed to patch %s: %w", path, err)
+	}
+	return nil
+}
+
+// get performs a GET request against the API server and decodes the JSON response
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// do sends a request with an optional JSON patch body and decodes the JSON response into out (if not nil)
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, patch []byte, out interface{}) error {
+	reqURL := c.host + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if patch != nil {
+		body = bytes.NewReader(patch)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "argazer/1.0")
+	if patch != nil {
+		req.Header.Set("Content-Type", "application/json-patch+json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kubernetes API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package kube
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ListSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/namespaces/argocd/secrets", r.URL.Path)
+		assert.Equal(t, "argocd.argoproj.io/secret-type=repo-creds", r.URL.Query().Get("labelSelector"))
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		// "aHR0cHM6Ly9jaGFydHMuZXhhbXBsZS5jb20=" is "https://charts.example.com"
+		w.Write([]byte(`{"items":[{"metadata":{"name":"creds","namespace":"argocd"},"data":{"url":"aHR0cHM6Ly9jaGFydHMuZXhhbXBsZS5jb20=","password":"c2VjcmV0"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", "argocd", nil, logrus.NewEntry(logrus.New()))
+	secrets, err := client.ListSecrets(context.Background(), "argocd", "argocd.argoproj.io/secret-type=repo-creds")
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, "creds", secrets[0].Metadata.Name)
+	assert.Equal(t, "https://charts.example.com", string(secrets[0].Data["url"]))
+	assert.Equal(t, "secret", string(secrets[0].Data["password"]))
+}
+
+func TestClient_ListSecrets_Forbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"forbidden"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "", nil, logrus.NewEntry(logrus.New()))
+	_, err := client.ListSecrets(context.Background(), "argocd", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403")
+}
+
+func TestInCluster_NoEnvironment(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+	assert.False(t, InCluster())
+}
+
+func TestResourcePath(t *testing.T) {
+	assert.Equal(t, "/apis/argoproj.io/v1alpha1/namespaces/argocd/applications", ResourcePath("argoproj.io", "v1alpha1", "argocd", "applications"))
+	assert.Equal(t, "/apis/argoproj.io/v1alpha1/applications", ResourcePath("argoproj.io", "v1alpha1", "", "applications"))
+	assert.Equal(t, "/api/v1/namespaces/argocd/secrets", ResourcePath("", "v1", "argocd", "secrets"))
+}
+
+func TestClient_List(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/apis/argoproj.io/v1alpha1/applications", r.URL.Path)
+		assert.Equal(t, "team=platform", r.URL.Query().Get("labelSelector"))
+		w.Write([]byte(`{"items":[{"metadata":{"name":"nginx","namespace":"argocd"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "", nil, logrus.NewEntry(logrus.New()))
+	var list struct {
+		Items []struct {
+			Metadata ObjectMeta `json:"metadata"`
+		} `json:"items"`
+	}
+	require.NoError(t, client.List(context.Background(), ResourcePath("argoproj.io", "v1alpha1", "", "applications"), "team=platform", &list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "nginx", list.Items[0].Metadata.Name)
+}
+
+func TestClient_Patch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/apis/argoproj.io/v1alpha1/namespaces/argocd/applications/nginx", r.URL.Path)
+		assert.Equal(t, "application/json-patch+json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `[{"op":"replace","path":"/spec/source/targetRevision","value":"1.1.0"}]`, string(body))
+		w.Write([]byte(`{"metadata":{"name":"nginx"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "", nil, logrus.NewEntry(logrus.New()))
+	path := ResourcePath("argoproj.io", "v1alpha1", "argocd", "applications") + "/nginx"
+	require.NoError(t, client.Patch(context.Background(), path, []byte(`[{"op":"replace","path":"/spec/source/targetRevision","value":"1.1.0"}]`), nil))
+}
@@ -0,0 +1,208 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+)
+
+// Client defaults
+const (
+	defaultClientIDPrefix = "argazer-"
+	defaultDialTimeout    = 10 * time.Second
+	defaultTimeout        = 30 * time.Second
+	keepAlive             = 60 * time.Second
+	disconnectQuiesceMs   = 250 // Time given to in-flight work before DISCONNECT
+	maxTopicLength        = 65535
+)
+
+// protocolVersion selects MQTT 3.1.1 in the paho client
+const protocolVersion = 4
+
+// Config holds the connection settings of a Client
+type Config struct {
+	BrokerURL string      // mqtt://host:1883 or mqtts://host:8883 (tcp://, ssl:// and tls:// are accepted too)
+	ClientID  string      // Client identifier (default: argazer-<random>)
+	Username  string      // Optional username
+	Password  string      // Optional password, only sent with a username
+	TLS       *tls.Config // TLS settings for mqtts:// brokers; nil uses the system defaults
+	QoS       byte        // Quality of service for published messages: 0, 1 or 2
+	Retain    bool        // Ask the broker to retain the last message of each topic
+}
+
+// Message is a message to publish
+type Message struct {
+	Topic   string
+	Payload []byte
+}
+
+// Client publishes messages over MQTT 3.1.1 with the Eclipse Paho client
+// It connects per Publish call, which suits infrequent notification traffic.
+type Client struct {
+	cfg     Config
+	address string
+	useTLS  bool
+	logger  *logrus.Entry
+}
+
+// NewClient creates a new client
+func NewClient(cfg Config, logger *logrus.Entry) (*Client, error) {
+	address, useTLS, err := parseBrokerURL(cfg.BrokerURL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.QoS > 2 {
+		return nil, fmt.Errorf("invalid MQTT QoS %d (must be 0, 1 or 2)", cfg.QoS)
+	}
+	if cfg.ClientID == "" {
+		cfg.ClientID = defaultClientIDPrefix + randomSuffix()
+	}
+	if useTLS && cfg.TLS == nil {
+		cfg.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return &Client{
+		cfg:     cfg,
+		address: address,
+		useTLS:  useTLS,
+		logger:  logger,
+	}, nil
+}
+
+// parseBrokerURL returns the host:port to dial and whether TLS is used
+func parseBrokerURL(brokerURL string) (string, bool, error) {
+	if brokerURL == "" {
+		return "", false, fmt.Errorf("MQTT broker URL is required")
+	}
+
+	u, err := url.Parse(brokerURL)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid MQTT broker URL: %w", err)
+	}
+
+	var useTLS bool
+	var defaultPort string
+	switch strings.ToLower(u.Scheme) {
+	case "mqtt", "tcp":
+		defaultPort = "1883"
+	case "mqtts", "ssl", "tls":
+		useTLS = true
+		defaultPort = "8883"
+	default:
+		return "", false, fmt.Errorf("unsupported MQTT broker URL scheme %q (use mqtt:// or mqtts://)", u.Scheme)
+	}
+
+	if u.Hostname() == "" {
+		return "", false, fmt.Errorf("MQTT broker URL %q has no host", brokerURL)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	return net.JoinHostPort(u.Hostname(), port), useTLS, nil
+}
+
+// randomSuffix returns a short random hex string so concurrent runs don't share a client ID
+func randomSuffix() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// clientOptions returns the paho options of a single, non-reconnecting session
+func (c *Client) clientOptions() *paho.ClientOptions {
+	opts := paho.NewClientOptions().
+		SetClientID(c.cfg.ClientID).
+		SetProtocolVersion(protocolVersion).
+		SetCleanSession(true).
+		SetKeepAlive(keepAlive).
+		SetConnectTimeout(defaultDialTimeout).
+		SetWriteTimeout(defaultTimeout).
+		SetAutoReconnect(false).
+		SetOrderMatters(true)
+
+	if c.useTLS {
+		opts.AddBroker("ssl://" + c.address).SetTLSConfig(c.cfg.TLS)
+	} else {
+		opts.AddBroker("tcp://" + c.address)
+	}
+
+	// MQTT only allows a password together with a username
+	if c.cfg.Username != "" {
+		opts.SetUsername(c.cfg.Username).SetPassword(c.cfg.Password)
+	}
+	return opts
+}
+
+// Publish connects to the broker, publishes the messages in order and disconnects
+// With QoS 1 or 2 it waits for the broker to acknowledge every message.
+func (c *Client) Publish(ctx context.Context, messages []Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	for _, msg := range messages {
+		if err := validateTopic(msg.Topic); err != nil {
+			return err
+		}
+	}
+
+	client := paho.NewClient(c.clientOptions())
+	if err := waitToken(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker %s: %w", c.address, err)
+	}
+	c.logger.WithField("broker", c.address).Debug("Connected to MQTT broker")
+	// DISCONNECT makes the broker discard the session cleanly
+	defer client.Disconnect(disconnectQuiesceMs)
+
+	for _, msg := range messages {
+		if err := waitToken(ctx, client.Publish(msg.Topic, c.cfg.QoS, c.cfg.Retain, msg.Payload)); err != nil {
+			return fmt.Errorf("failed to publish to MQTT topic %s: %w", msg.Topic, err)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"broker":   c.address,
+		"messages": len(messages),
+	}).Debug("Published MQTT messages")
+	return nil
+}
+
+// waitToken waits for a paho operation to complete, the context to end or the default timeout
+func waitToken(ctx context.Context, token paho.Token) error {
+	timer := time.NewTimer(defaultTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return fmt.Errorf("timed out after %s", defaultTimeout)
+	}
+}
+
+// validateTopic rejects topic names brokers would refuse for PUBLISH
+func validateTopic(topic string) error {
+	if topic == "" {
+		return fmt.Errorf("MQTT topic must not be empty")
+	}
+	if len(topic) > maxTopicLength {
+		return fmt.Errorf("MQTT topic is too long")
+	}
+	if strings.ContainsAny(topic, "+#\x00") {
+		return fmt.Errorf("MQTT topic %q must not contain wildcards", topic)
+	}
+	return nil
+}
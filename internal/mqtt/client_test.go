@@ -0,0 +1,229 @@
+package mqtt
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publishedMessage is a PUBLISH packet received by the fake broker
+type publishedMessage struct {
+	topic   string
+	payload string
+	qos     byte
+	retain  bool
+}
+
+// fakeBroker is an MQTT broker speaking just enough of the protocol for the client
+type fakeBroker struct {
+	t        *testing.T
+	listener net.Listener
+	password string // Expected password; empty accepts any credentials
+
+	mu           sync.Mutex
+	clientIDs    []string
+	usernames    []string
+	messages     []publishedMessage
+	disconnected bool
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	b := &fakeBroker{t: t, listener: listener}
+	go b.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return b
+}
+
+func (b *fakeBroker) url() string { return "mqtt://" + b.listener.Addr().String() }
+
+func (b *fakeBroker) serve() {
+	for {
+		c, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(c)
+	}
+}
+
+func (b *fakeBroker) handle(c net.Conn) {
+	defer c.Close()
+	for {
+		p, err := packets.ReadPacket(c)
+		if err != nil {
+			return
+		}
+
+		switch p := p.(type) {
+		case *packets.ConnectPacket:
+			b.handleConnect(c, p)
+		case *packets.PublishPacket:
+			b.handlePublish(c, p)
+		case *packets.PubrelPacket:
+			comp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			comp.MessageID = p.MessageID
+			_ = comp.Write(c)
+		case *packets.PingreqPacket:
+			_ = packets.NewControlPacket(packets.Pingresp).Write(c)
+		case *packets.DisconnectPacket:
+			b.mu.Lock()
+			b.disconnected = true
+			b.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) handleConnect(c net.Conn, p *packets.ConnectPacket) {
+	b.mu.Lock()
+	b.clientIDs = append(b.clientIDs, p.ClientIdentifier)
+	b.usernames = append(b.usernames, p.Username)
+	b.mu.Unlock()
+
+	ack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+	if b.password != "" && string(p.Password) != b.password {
+		ack.ReturnCode = packets.ErrRefusedBadUsernameOrPassword
+	}
+	_ = ack.Write(c)
+}
+
+func (b *fakeBroker) handlePublish(c net.Conn, p *packets.PublishPacket) {
+	b.mu.Lock()
+	b.messages = append(b.messages, publishedMessage{topic: p.TopicName, payload: string(p.Payload), qos: p.Qos, retain: p.Retain})
+	b.mu.Unlock()
+
+	switch p.Qos {
+	case 1:
+		ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+		ack.MessageID = p.MessageID
+		_ = ack.Write(c)
+	case 2:
+		rec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+		rec.MessageID = p.MessageID
+		_ = rec.Write(c)
+	}
+}
+
+func (b *fakeBroker) received() []publishedMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]publishedMessage(nil), b.messages...)
+}
+
+func TestParseBrokerURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		address string
+		useTLS  bool
+		wantErr bool
+	}{
+		{url: "mqtt://broker.local", address: "broker.local:1883"},
+		{url: "tcp://broker.local:1884", address: "broker.local:1884"},
+		{url: "mqtts://broker.local", address: "broker.local:8883", useTLS: true},
+		{url: "ssl://10.0.0.1:8884", address: "10.0.0.1:8884", useTLS: true},
+		{url: "ws://broker.local", wantErr: true},
+		{url: "mqtt://", wantErr: true},
+		{url: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			address, useTLS, err := parseBrokerURL(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.address, address)
+			assert.Equal(t, tt.useTLS, useTLS)
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	client, err := NewClient(Config{BrokerURL: "mqtt://localhost"}, logger)
+	require.NoError(t, err)
+	assert.Regexp(t, `^argazer-[0-9a-f]{8}$`, client.cfg.ClientID)
+
+	client, err = NewClient(Config{BrokerURL: "mqtts://localhost"}, logger)
+	require.NoError(t, err)
+	require.NotNil(t, client.cfg.TLS)
+
+	_, err = NewClient(Config{BrokerURL: "mqtt://localhost", QoS: 3}, logger)
+	assert.Error(t, err)
+}
+
+func TestClient_Publish(t *testing.T) {
+	for _, qos := range []byte{0, 1, 2} {
+		t.Run(string('0'+qos), func(t *testing.T) {
+			broker := newFakeBroker(t)
+			client, err := NewClient(Config{
+				BrokerURL: broker.url(),
+				ClientID:  "test-client",
+				Username:  "user",
+				Password:  "secret",
+				QoS:       qos,
+				Retain:    true,
+			}, logrus.NewEntry(logrus.New()))
+			require.NoError(t, err)
+
+			err = client.Publish(context.Background(), []Message{
+				{Topic: "argazer/default/app1", Payload: []byte(`{"app":"app1"}`)},
+				{Topic: "argazer/default/app2", Payload: []byte(`{"app":"app2"}`)},
+			})
+			require.NoError(t, err)
+
+			assert.Eventually(t, func() bool {
+				broker.mu.Lock()
+				defer broker.mu.Unlock()
+				return broker.disconnected
+			}, time.Second, 10*time.Millisecond)
+
+			messages := broker.received()
+			require.Len(t, messages, 2)
+			assert.Equal(t, publishedMessage{topic: "argazer/default/app1", payload: `{"app":"app1"}`, qos: qos, retain: true}, messages[0])
+			assert.Equal(t, "argazer/default/app2", messages[1].topic)
+			assert.Equal(t, []string{"test-client"}, broker.clientIDs)
+			assert.Equal(t, []string{"user"}, broker.usernames)
+		})
+	}
+}
+
+func TestClient_PublishBadCredentials(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.password = "secret"
+
+	client, err := NewClient(Config{BrokerURL: broker.url(), Username: "user", Password: "wrong"}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	err = client.Publish(context.Background(), []Message{{Topic: "argazer/test", Payload: []byte("x")}})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, packets.ErrorRefusedBadUsernameOrPassword)
+	assert.Empty(t, broker.received())
+}
+
+func TestClient_PublishInvalidTopic(t *testing.T) {
+	client, err := NewClient(Config{BrokerURL: "mqtt://127.0.0.1:1"}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	for _, topic := range []string{"", "argazer/+/app", "argazer/#"} {
+		err := client.Publish(context.Background(), []Message{{Topic: topic}})
+		assert.Error(t, err, "topic %q", topic)
+	}
+
+	// Nothing to publish doesn't connect
+	assert.NoError(t, client.Publish(context.Background(), nil))
+}
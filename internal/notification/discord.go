@@ -0,0 +1,174 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Embed limits enforced by Discord
+const (
+	discordMaxEmbeds      = 10   // Embeds per message
+	discordMaxFields      = 25   // Fields per embed
+	discordMaxTitle       = 256  // Characters per embed title
+	discordMaxDescription = 4096 // Characters per embed description
+	discordMaxFieldName   = 256  // Characters per field name
+	discordMaxFieldValue  = 1024 // Characters per field value
+	discordMaxTotal       = 6000 // Characters across all embeds of a message
+)
+
+// discordPayload represents the JSON payload for Discord webhooks
+type discordPayload struct {
+	Username        string                 `json:"username,omitempty"`
+	AvatarURL       string                 `json:"avatar_url,omitempty"`
+	Embeds          []discordEmbed         `json:"embeds"`
+	AllowedMentions discordAllowedMentions `json:"allowed_mentions"`
+}
+
+// discordEmbed represents a Discord message embed
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+// discordField represents a field of an embed
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// discordAllowedMentions controls which mentions in a message ping users
+// An empty parse list keeps application or chart names like "@everyone" from pinging anyone.
+type discordAllowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// DiscordNotifier handles sending notifications via Discord webhooks
+type DiscordNotifier struct {
+	*HTTPNotifier
+	style Style
+}
+
+// NewDiscordNotifier creates a new Discord notifier
+func NewDiscordNotifier(webhookURL string, logger *logrus.Entry) *DiscordNotifier {
+	return NewDiscordNotifierWithClient(webhookURL, nil, logger)
+}
+
+// NewDiscordNotifierWithClient creates a new Discord notifier with a custom HTTP client
+func NewDiscordNotifierWithClient(webhookURL string, httpClient *http.Client, logger *logrus.Entry) *DiscordNotifier {
+	return &DiscordNotifier{
+		HTTPNotifier: NewHTTPNotifier(webhookURL, httpClient, logger),
+	}
+}
+
+// SetStyle sets the embed colors and the sender name and avatar
+func (n *DiscordNotifier) SetStyle(style Style) {
+	n.style = style
+}
+
+// Send sends a notification as a single embed (implements Notifier interface)
+func (n *DiscordNotifier) Send(ctx context.Context, subject, message string) error {
+	return n.send(ctx, []discordPayload{n.payload([]discordEmbed{{
+		Title:       truncateText(subject, discordMaxTitle),
+		Description: truncateText(message, discordMaxDescription),
+		Color:       n.color(""),
+	}})})
+}
+
+// SendMessage sends a notification with one embed field per application, colored by the highest
+// severity of its updates (implements MessageNotifier)
+// Messages exceeding Discord's embed limits are sent as several webhook messages.
+func (n *DiscordNotifier) SendMessage(ctx context.Context, subject string, message FormattedMessage) error {
+	if len(message.Sections) == 0 {
+		return n.Send(ctx, subject, message.Text)
+	}
+
+	title := truncateText(subject, discordMaxTitle)
+	color := n.color(message.Severity)
+
+	var payloads []discordPayload
+	var embeds []discordEmbed
+	total := 0
+	for _, section := range message.Sections {
+		field := discordSectionField(section)
+		size := len([]rune(field.Name)) + len([]rune(field.Value))
+
+		if len(embeds) > 0 && total+size > discordMaxTotal {
+			payloads = append(payloads, n.payload(embeds))
+			embeds = nil
+		}
+		if len(embeds) == 0 {
+			embeds = []discordEmbed{{Title: title, Color: color}}
+			total = len([]rune(title))
+		}
+		if len(embeds[len(embeds)-1].Fields) == discordMaxFields {
+			if len(embeds) == discordMaxEmbeds {
+				payloads = append(payloads, n.payload(embeds))
+				embeds = []discordEmbed{{Title: title, Color: color}}
+				total = len([]rune(title))
+			} else {
+				embeds = append(embeds, discordEmbed{Color: color})
+			}
+		}
+
+		last := &embeds[len(embeds)-1]
+		last.Fields = append(last.Fields, field)
+		total += size
+	}
+	payloads = append(payloads, n.payload(embeds))
+
+	return n.send(ctx, payloads)
+}
+
+// send posts the webhook messages in order
+func (n *DiscordNotifier) send(ctx context.Context, payloads []discordPayload) error {
+	n.logger.WithField("messages", len(payloads)).Debug("Sending Discord notification")
+
+	for _, payload := range payloads {
+		if err := n.SendJSON(ctx, payload); err != nil {
+			return err
+		}
+	}
+
+	n.logger.Info("Successfully sent Discord notification")
+	return nil
+}
+
+// payload wraps embeds into a webhook message with the configured sender
+func (n *DiscordNotifier) payload(embeds []discordEmbed) discordPayload {
+	return discordPayload{
+		Username:        n.style.SenderName,
+		AvatarURL:       n.style.IconURL,
+		Embeds:          embeds,
+		AllowedMentions: discordAllowedMentions{Parse: []string{}},
+	}
+}
+
+// color returns the embed color for a severity as the integer Discord expects
+func (n *DiscordNotifier) color(severity string) int {
+	color, err := strconv.ParseUint(n.style.Color(severity), 16, 32)
+	if err != nil {
+		color, _ = strconv.ParseUint(DefaultThemeColor, 16, 32)
+	}
+	return int(color)
+}
+
+// discordSectionField turns the formatted text of an update into an embed field: the first line
+// (application and project) is the field name, the indented details are its value
+func discordSectionField(section string) discordField {
+	name, details := splitSection(section)
+	value := strings.Join(details, "\n")
+	if value == "" {
+		value = "-" // Discord rejects fields with an empty value
+	}
+	return discordField{
+		Name:  truncateText(name, discordMaxFieldName),
+		Value: truncateText(value, discordMaxFieldValue),
+	}
+}
@@ -0,0 +1,137 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDiscordServer records the webhook messages posted to it
+func newDiscordServer(t *testing.T) (*httptest.Server, func() []discordPayload) {
+	t.Helper()
+	var mu sync.Mutex
+	var payloads []discordPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload discordPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		mu.Lock()
+		payloads = append(payloads, payload)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []discordPayload {
+		mu.Lock()
+		defer mu.Unlock()
+		return payloads
+	}
+}
+
+func TestDiscordNotifier_Send(t *testing.T) {
+	server, received := newDiscordServer(t)
+	notifier := NewDiscordNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	notifier.SetStyle(Style{SenderName: "Argazer", IconURL: "https://example.com/argazer.png"})
+
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+
+	payloads := received()
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "Argazer", payloads[0].Username)
+	assert.Equal(t, "https://example.com/argazer.png", payloads[0].AvatarURL)
+	assert.Empty(t, payloads[0].AllowedMentions.Parse)
+	require.Len(t, payloads[0].Embeds, 1)
+	assert.Equal(t, "Subject", payloads[0].Embeds[0].Title)
+	assert.Equal(t, "Message", payloads[0].Embeds[0].Description)
+	assert.Equal(t, 0x0078D7, payloads[0].Embeds[0].Color)
+}
+
+func TestDiscordNotifier_SendMessage(t *testing.T) {
+	server, received := newDiscordServer(t)
+	notifier := NewDiscordNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	notifier.SetStyle(Style{Colors: map[string]string{SeverityMajor: "D70000"}})
+
+	formatter := NewMessageFormatter()
+	messages := formatter.FormatMessageGroups([]ApplicationUpdate{
+		{AppName: "app1", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", RepoURL: "https://charts.example.com"},
+		{AppName: "app2", Project: "default", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.0.1", RepoURL: "https://charts.example.com"},
+	})
+	require.Len(t, messages, 1)
+
+	var _ MessageNotifier = notifier
+	require.NoError(t, notifier.SendMessage(context.Background(), "2 updates", messages[0]))
+
+	payloads := received()
+	require.Len(t, payloads, 1)
+	require.Len(t, payloads[0].Embeds, 1)
+	embed := payloads[0].Embeds[0]
+	assert.Equal(t, "2 updates", embed.Title)
+	assert.Equal(t, 0xD70000, embed.Color)
+	require.Len(t, embed.Fields, 2)
+	assert.Equal(t, "app1 (default)", embed.Fields[0].Name)
+	assert.Equal(t, "Chart: nginx\nVersion: 1.0.0 -> 2.0.0\nRepo: https://charts.example.com", embed.Fields[0].Value)
+	assert.Equal(t, "app2 (default)", embed.Fields[1].Name)
+}
+
+func TestDiscordNotifier_SendMessage_Limits(t *testing.T) {
+	server, received := newDiscordServer(t)
+	notifier := NewDiscordNotifier(server.URL, logrus.NewEntry(logrus.New()))
+
+	var sections []string
+	for i := 0; i < 60; i++ {
+		sections = append(sections, fmt.Sprintf("app%d (default)\n  Chart: nginx\n  Version: 1.0.0 -> 1.1.0\n", i))
+	}
+	require.NoError(t, notifier.SendMessage(context.Background(), "60 updates", FormattedMessage{Sections: sections}))
+
+	payloads := received()
+	require.Len(t, payloads, 1)
+	require.Len(t, payloads[0].Embeds, 3)
+	assert.Equal(t, "60 updates", payloads[0].Embeds[0].Title)
+	assert.Empty(t, payloads[0].Embeds[1].Title)
+	assert.Len(t, payloads[0].Embeds[0].Fields, discordMaxFields)
+	assert.Len(t, payloads[0].Embeds[2].Fields, 10)
+
+	// Long details are spread over several messages of at most 6000 characters
+	sections = sections[:0]
+	for i := 0; i < 10; i++ {
+		sections = append(sections, fmt.Sprintf("app%d (default)\n  Note: %s\n", i, strings.Repeat("x", 2000)))
+	}
+	require.NoError(t, notifier.SendMessage(context.Background(), "10 updates", FormattedMessage{Sections: sections}))
+
+	payloads = received()[1:]
+	require.Len(t, payloads, 2)
+	fields := 0
+	for _, payload := range payloads {
+		size := 0
+		for _, embed := range payload.Embeds {
+			size += len([]rune(embed.Title))
+			for _, field := range embed.Fields {
+				assert.LessOrEqual(t, len([]rune(field.Value)), discordMaxFieldValue)
+				size += len([]rune(field.Name)) + len([]rune(field.Value))
+				fields++
+			}
+		}
+		assert.LessOrEqual(t, size, discordMaxTotal)
+	}
+	assert.Equal(t, 10, fields)
+}
+
+func TestDiscordNotifier_Send_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := NewDiscordNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	err := notifier.Send(context.Background(), "Subject", "Message")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 400")
+}
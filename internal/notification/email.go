@@ -1,15 +1,72 @@
 package notification
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
 	"net/smtp"
+	"net/textproto"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+// emailHTMLTemplate renders the HTML part of update emails, with inline styles as most mail
+// clients ignore style sheets
+var emailHTMLTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="UTF-8"><title>{{ .Subject }}</title></head>
+<body style="margin:0;padding:16px;font-family:-apple-system,'Segoe UI',Helvetica,Arial,sans-serif;font-size:14px;color:#24292f;">
+<h2 style="margin:0 0 16px;font-size:18px;">{{ .Subject }}</h2>
+<table role="presentation" style="border-collapse:collapse;width:100%;max-width:800px;">
+{{- range .Updates }}
+<tr><td style="border-left:4px solid #{{ .Color }};border-bottom:1px solid #d0d7de;padding:8px 12px;">
+<div style="font-weight:bold;margin-bottom:4px;">{{ .Name }}</div>
+<table role="presentation" style="border-collapse:collapse;">
+{{- range .Details }}
+<tr><td style="color:#57606a;padding:2px 12px 2px 0;vertical-align:top;white-space:nowrap;">{{ .Label }}</td><td style="padding:2px 0;">{{ if .Link }}<a href="{{ .Value }}" style="color:#0969da;">{{ .Value }}</a>{{ else }}{{ .Value }}{{ end }}</td></tr>
+{{- end }}
+</table>
+</td></tr>
+{{- end }}
+</table>
+{{- if .Summary }}
+<p style="margin:16px 0 0;color:#57606a;font-size:12px;">{{ .Summary }}</p>
+{{- end }}
+</body>
+</html>
+`))
+
+// emailHTMLData is the data of the HTML part
+type emailHTMLData struct {
+	Subject string
+	Updates []emailHTMLUpdate
+	Summary string
+}
+
+// emailHTMLUpdate is an update row of the HTML part
+type emailHTMLUpdate struct {
+	Name    string
+	Color   string // Hex color of the severity bar
+	Details []emailHTMLDetail
+}
+
+// emailHTMLDetail is a "Label: value" detail of an update
+type emailHTMLDetail struct {
+	Label string
+	Value string
+	Link  bool
+}
+
 // EmailNotifier handles sending notifications via Email
 type EmailNotifier struct {
 	smtpHost     string
@@ -19,6 +76,9 @@ type EmailNotifier struct {
 	from         string
 	to           []string
 	useTLS       bool
+	implicitTLS  bool        // Connect over TLS instead of upgrading with STARTTLS
+	tlsConfig    *tls.Config // Custom TLS configuration, nil for the defaults
+	style        Style
 	logger       *logrus.Entry
 }
 
@@ -36,15 +96,39 @@ func NewEmailNotifier(smtpHost string, smtpPort int, smtpUsername, smtpPassword,
 	}
 }
 
-// Send sends an email notification (implements Notifier interface)
+// SetTLS sets how the connection to the SMTP server is secured: implicit TLS (SMTPS) instead of
+// STARTTLS, and a custom TLS configuration (e.g. trusted CAs); a nil configuration keeps the defaults
+func (e *EmailNotifier) SetTLS(implicit bool, tlsConfig *tls.Config) {
+	e.implicitTLS = implicit
+	e.tlsConfig = tlsConfig
+}
+
+// SetStyle sets the severity colors of the HTML table
+func (e *EmailNotifier) SetStyle(style Style) {
+	e.style = style
+}
+
+// Send sends a plain text email notification (implements Notifier interface)
 func (e *EmailNotifier) Send(ctx context.Context, subject, message string) error {
-	// Prepare email headers and body
-	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s",
-		e.from,
-		strings.Join(e.to, ", "),
-		subject,
-		message,
-	)
+	return e.SendMessage(ctx, subject, FormattedMessage{Text: message})
+}
+
+// SendMessage sends an email notification with an HTML table of the updates and the plain text as
+// alternative (implements MessageNotifier)
+// Messages without per-application sections are sent as plain text only.
+func (e *EmailNotifier) SendMessage(ctx context.Context, subject string, message FormattedMessage) error {
+	html := ""
+	if len(message.Sections) > 0 {
+		var err error
+		if html, err = e.renderHTML(subject, message); err != nil {
+			return fmt.Errorf("failed to render email: %w", err)
+		}
+	}
+
+	body, err := e.buildMessage(subject, message.Text, html, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
 
 	addr := fmt.Sprintf("%s:%d", e.smtpHost, e.smtpPort)
 
@@ -61,19 +145,59 @@ func (e *EmailNotifier) Send(ctx context.Context, subject, message string) error
 		auth = smtp.PlainAuth("", e.smtpUsername, e.smtpPassword, e.smtpHost)
 	}
 
-	// Send email with TLS if enabled
+	// Send email over implicit TLS (SMTPS) or with STARTTLS if enabled
+	if e.implicitTLS {
+		return e.sendWithImplicitTLS(ctx, addr, auth, body)
+	}
 	if e.useTLS {
-		return e.sendWithTLS(addr, auth, []byte(body))
+		return e.sendWithTLS(addr, auth, body)
 	}
 
 	// Send without TLS
-	err := smtp.SendMail(addr, auth, e.from, e.to, []byte(body))
+	err = smtp.SendMail(addr, auth, e.from, e.to, body)
 	if err == nil {
 		e.logger.WithField("to", e.to).Info("Successfully sent email notification")
 	}
 	return err
 }
 
+// clientTLSConfig returns the TLS configuration used to connect to the SMTP server
+func (e *EmailNotifier) clientTLSConfig() *tls.Config {
+	if e.tlsConfig != nil {
+		tlsConfig := e.tlsConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = e.smtpHost
+		}
+		return tlsConfig
+	}
+	return &tls.Config{
+		ServerName: e.smtpHost,
+		MinVersion: tls.VersionTLS12, // Require TLS 1.2 or higher for security
+	}
+}
+
+// sendWithImplicitTLS sends email over a connection encrypted from the start (SMTPS, usually port 465)
+func (e *EmailNotifier) sendWithImplicitTLS(ctx context.Context, addr string, auth smtp.Auth, body []byte) error {
+	dialer := &tls.Dialer{Config: e.clientTLSConfig()}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, e.smtpHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			e.logger.WithError(err).Warn("Failed to close SMTP client")
+		}
+	}()
+
+	return e.deliver(client, auth, body)
+}
+
 // sendWithTLS sends email with TLS encryption
 func (e *EmailNotifier) sendWithTLS(addr string, auth smtp.Auth, body []byte) error {
 	// Connect to SMTP server
@@ -88,14 +212,15 @@ func (e *EmailNotifier) sendWithTLS(addr string, auth smtp.Auth, body []byte) er
 	}()
 
 	// Start TLS
-	tlsConfig := &tls.Config{
-		ServerName: e.smtpHost,
-		MinVersion: tls.VersionTLS12, // Require TLS 1.2 or higher for security
-	}
-	if err := client.StartTLS(tlsConfig); err != nil {
+	if err := client.StartTLS(e.clientTLSConfig()); err != nil {
 		return fmt.Errorf("failed to start TLS: %w", err)
 	}
 
+	return e.deliver(client, auth, body)
+}
+
+// deliver authenticates and sends the email over an established connection
+func (e *EmailNotifier) deliver(client *smtp.Client, auth smtp.Auth, body []byte) error {
 	// Authenticate
 	if auth != nil {
 		if err := client.Auth(auth); err != nil {
@@ -115,21 +240,114 @@ func (e *EmailNotifier) sendWithTLS(addr string, auth smtp.Auth, body []byte) er
 		}
 	}
 
-	// Send email body
+	// Send email body; the server accepts it when the data writer is closed
 	w, err := client.Data()
 	if err != nil {
 		return fmt.Errorf("failed to get data writer: %w", err)
 	}
-	defer func() {
-		if err := w.Close(); err != nil {
-			e.logger.WithError(err).Warn("Failed to close data writer")
-		}
-	}()
-
 	if _, err := w.Write(body); err != nil {
+		w.Close()
 		return fmt.Errorf("failed to write email body: %w", err)
 	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email body: %w", err)
+	}
 
 	e.logger.WithField("to", e.to).Info("Successfully sent email notification")
 	return nil
 }
+
+// buildMessage builds a MIME message with the plain text, and an HTML alternative when html is set
+// Headers are encoded for non-ASCII subjects and bodies are quoted-printable, so lines stay within
+// SMTP's length limit.
+func (e *EmailNotifier) buildMessage(subject, text, html string, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	header("From", e.from)
+	header("To", strings.Join(e.to, ", "))
+	header("Subject", mime.QEncoding.Encode("UTF-8", subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(e.from, now))
+	header("MIME-Version", "1.0")
+
+	if html == "" {
+		header("Content-Type", "text/plain; charset=UTF-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&buf)
+	header("Content-Type", "multipart/alternative; boundary="+parts.Boundary())
+	buf.WriteString("\r\n")
+
+	// Clients show the last alternative they support, so HTML goes after the plain text
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderHTML renders the HTML part: one row per update, with a bar colored by its severity
+func (e *EmailNotifier) renderHTML(subject string, message FormattedMessage) (string, error) {
+	data := emailHTMLData{Subject: subject, Summary: message.Summary}
+	for i, section := range message.Sections {
+		name, details := splitSection(section)
+		update := emailHTMLUpdate{Name: name, Color: e.style.Color(UpdateSeverity(message.Updates[i]))}
+		for _, detail := range details {
+			label, value, _ := strings.Cut(detail, ": ")
+			update.Details = append(update.Details, emailHTMLDetail{
+				Label: label,
+				Value: value,
+				Link:  strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://"),
+			})
+		}
+		data.Updates = append(data.Updates, update)
+	}
+
+	var buf bytes.Buffer
+	if err := emailHTMLTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// writeQuotedPrintable writes text with quoted-printable encoding
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(text)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// messageID returns a unique Message-ID in the domain of the sender address
+func messageID(from string, now time.Time) string {
+	domain := "argazer.localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = strings.TrimSuffix(from[at+1:], ">")
+	}
+	random := make([]byte, 8)
+	_, _ = rand.Read(random)
+	return fmt.Sprintf("<%d.%s@%s>", now.UnixNano(), hex.EncodeToString(random), domain)
+}
@@ -1,8 +1,20 @@
 package notification
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -109,3 +121,182 @@ func TestEmailNotifier_Send_NoAuth(t *testing.T) {
 	assert.Equal(t, "", notifier.smtpUsername)
 	assert.Equal(t, "", notifier.smtpPassword)
 }
+
+func TestEmailNotifier_BuildMessage_HTML(t *testing.T) {
+	notifier := NewEmailNotifier("smtp.example.com", 587, "", "", "Argazer <argazer@example.com>", []string{"a@example.com", "b@example.com"}, false, logrus.NewEntry(logrus.New()))
+	notifier.SetStyle(Style{Colors: map[string]string{SeverityMajor: "D70000"}})
+
+	messages := NewMessageFormatter().FormatMessageGroups([]ApplicationUpdate{
+		{AppName: "app1", Project: "<team>", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", RepoURL: "https://charts.example.com"},
+	})
+	require.Len(t, messages, 1)
+	messages[0].Summary = "Total applications checked: 1"
+
+	html, err := notifier.renderHTML("Mises à jour", messages[0])
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	raw, err := notifier.buildMessage("Mises à jour", messages[0].Text, html, now)
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Mises à jour", subject)
+	assert.NotEqual(t, "Mises à jour", msg.Header.Get("Subject"), "non-ASCII subjects are encoded")
+	assert.Equal(t, "a@example.com, b@example.com", msg.Header.Get("To"))
+	date, err := msg.Header.Date()
+	require.NoError(t, err)
+	assert.True(t, now.Equal(date))
+	assert.Regexp(t, `^<\d+\.[0-9a-f]{16}@example\.com>$`, msg.Header.Get("Message-ID"))
+	assert.Equal(t, "1.0", msg.Header.Get("MIME-Version"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	var types []string
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, "quoted-printable", part.Header.Get("Content-Transfer-Encoding"))
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		types = append(types, part.Header.Get("Content-Type"))
+		parts = append(parts, string(content))
+	}
+
+	assert.Equal(t, []string{"text/plain; charset=UTF-8", "text/html; charset=UTF-8"}, types)
+	assert.Equal(t, strings.ReplaceAll(messages[0].Text, "\n", "\r\n"), parts[0]) // Line breaks are CRLF in mail
+	assert.Contains(t, parts[1], "<title>Mises à jour</title>")
+	assert.Contains(t, parts[1], "border-left:4px solid #D70000")
+	assert.Contains(t, parts[1], "app1 (&lt;team&gt;)")
+	assert.Contains(t, parts[1], "1.0.0 -&gt; 2.0.0")
+	assert.Contains(t, parts[1], `<a href="https://charts.example.com"`)
+	assert.Contains(t, parts[1], "Total applications checked: 1")
+}
+
+func TestEmailNotifier_BuildMessage_PlainText(t *testing.T) {
+	notifier := NewEmailNotifier("smtp.example.com", 587, "", "", "argazer@example.com", []string{"a@example.com"}, false, logrus.NewEntry(logrus.New()))
+
+	text := strings.Repeat("long line ", 20)
+	raw, err := notifier.buildMessage("Subject", text, "", time.Now())
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	require.NoError(t, err)
+	assert.Equal(t, "Subject", msg.Header.Get("Subject"))
+	assert.Equal(t, "text/plain; charset=UTF-8", msg.Header.Get("Content-Type"))
+	for _, line := range strings.Split(string(raw), "\r\n") {
+		assert.LessOrEqual(t, len(line), 78)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	assert.Equal(t, text, string(body))
+}
+
+// serveSMTP answers a single SMTP session on the listener and returns the received message
+func serveSMTP(t *testing.T, listener net.Listener) <-chan string {
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+
+		var data strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				received <- data.String()
+				return
+			}
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"):
+				reply("250-localhost")
+				reply("250 8BITMIME")
+			case strings.HasPrefix(command, "DATA"):
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				reply("250 OK")
+			case strings.HasPrefix(command, "QUIT"):
+				reply("221 Bye")
+				received <- data.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return received
+}
+
+func TestEmailNotifier_Send_ImplicitTLS(t *testing.T) {
+	// Borrow the test certificate of an HTTPS server, valid for example.com
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: server.TLS.Certificates})
+	require.NoError(t, err)
+	defer listener.Close()
+	received := serveSMTP(t, listener)
+
+	addr := listener.Addr().(*net.TCPAddr)
+	notifier := NewEmailNotifier("127.0.0.1", addr.Port, "", "", "argazer@example.com", []string{"ops@example.com"}, false, logrus.NewEntry(logrus.New()))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	notifier.SetTLS(true, &tls.Config{RootCAs: roots, ServerName: "example.com", MinVersion: tls.VersionTLS12})
+
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+
+	select {
+	case data := <-received:
+		assert.Contains(t, data, "Subject: Subject\r\n")
+		assert.Contains(t, data, "Message")
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP server received no message")
+	}
+}
+
+func TestEmailNotifier_Send_ImplicitTLS_UnknownCA(t *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: server.TLS.Certificates})
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			// Complete the handshake so the client sees the certificate
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	notifier := NewEmailNotifier("127.0.0.1", addr.Port, "", "", "argazer@example.com", []string{"ops@example.com"}, false, logrus.NewEntry(logrus.New()))
+	notifier.SetTLS(true, nil)
+
+	err = notifier.Send(context.Background(), "Subject", "Message")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to SMTP server")
+}
@@ -0,0 +1,65 @@
+package notification
+
+import "time"
+
+// Event types published by event-based notifiers
+const (
+	EventUpdateAvailable = "chart_update_available"
+	EventNotification    = "notification"
+)
+
+// UpdateEvent is the structured representation of an application update
+type UpdateEvent struct {
+	Event                      string    `json:"event"`
+	AppName                    string    `json:"app_name"`
+	Namespace                  string    `json:"namespace,omitempty"`
+	Project                    string    `json:"project"`
+	Instance                   string    `json:"instance,omitempty"`
+	ChartName                  string    `json:"chart_name"`
+	CurrentVersion             string    `json:"current_version"`
+	LatestVersion              string    `json:"latest_version"`
+	RepoURL                    string    `json:"repo_url"`
+	ConstraintApplied          string    `json:"constraint_applied,omitempty"`
+	HasUpdateOutsideConstraint bool      `json:"has_update_outside_constraint,omitempty"`
+	LatestVersionAll           string    `json:"latest_version_all,omitempty"`
+	URL                        string    `json:"url,omitempty"`
+	Timestamp                  time.Time `json:"timestamp"`
+}
+
+// NotificationEvent is the structured form of a plain subject/message notification
+type NotificationEvent struct {
+	Event     string    `json:"event"`
+	Subject   string    `json:"subject"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewUpdateEvent creates the event for an application update
+func NewUpdateEvent(update ApplicationUpdate, timestamp time.Time) UpdateEvent {
+	return UpdateEvent{
+		Event:                      EventUpdateAvailable,
+		AppName:                    update.AppName,
+		Namespace:                  update.Namespace,
+		Project:                    update.Project,
+		Instance:                   update.Instance,
+		ChartName:                  update.ChartName,
+		CurrentVersion:             update.CurrentVersion,
+		LatestVersion:              update.LatestVersion,
+		RepoURL:                    update.RepoURL,
+		ConstraintApplied:          update.ConstraintApplied,
+		HasUpdateOutsideConstraint: update.HasUpdateOutsideConstraint,
+		LatestVersionAll:           update.LatestVersionAll,
+		URL:                        update.URL,
+		Timestamp:                  timestamp.UTC(),
+	}
+}
+
+// NewNotificationEvent creates the event for a plain notification
+func NewNotificationEvent(subject, message string, timestamp time.Time) NotificationEvent {
+	return NotificationEvent{
+		Event:     EventNotification,
+		Subject:   subject,
+		Message:   message,
+		Timestamp: timestamp.UTC(),
+	}
+}
@@ -2,12 +2,21 @@ package notification
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
+
+	"argazer/internal/i18n"
 )
 
+// openInArgoCDLabel is the label of buttons linking to an application in the ArgoCD web UI
+const openInArgoCDLabel = "Open in ArgoCD"
+
 // MessageFormatter formats application check results for notifications
 type MessageFormatter struct {
-	MaxMessageLength int // Maximum length per message (default: 3900 for Telegram)
+	MaxMessageLength int               // Maximum length per message (default: 3900 for Telegram)
+	Emojis           map[string]string // Emoji shown before each update, by severity (optional)
+	Localizer        *i18n.Localizer   // Language of the message text (default: English)
 }
 
 // NewMessageFormatter creates a new message formatter with default settings
@@ -20,7 +29,9 @@ func NewMessageFormatter() *MessageFormatter {
 // ApplicationUpdate represents an application with available updates for notification
 type ApplicationUpdate struct {
 	AppName                    string
+	Namespace                  string // Namespace of the Application resource
 	Project                    string
+	Instance                   string // ArgoCD instance of the application, empty unless several are scanned
 	ChartName                  string
 	CurrentVersion             string
 	LatestVersion              string
@@ -28,11 +39,39 @@ type ApplicationUpdate struct {
 	ConstraintApplied          string
 	HasUpdateOutsideConstraint bool
 	LatestVersionAll           string
+	SyncBlocked                bool   // Automated syncs are currently blocked by a sync window
+	SyncBlockedBy              string // Deny window blocking syncs, empty when no allow window is active
+	NextSyncWindow             string // Start of the next allowed sync period (RFC 3339), empty if none is known
+	URL                        string // Application page in the ArgoCD web UI
+	ReleaseNotes               string // Excerpt of the changes since the current version
+	ReleaseNotesURL            string // Page with the full release notes or changelog
+	FixedVulnerabilities       int    // Known vulnerabilities of the current version's images the update fixes
+}
+
+// FormattedMessage is a notification message together with the updates it contains
+type FormattedMessage struct {
+	Text     string
+	Updates  []ApplicationUpdate
+	Sections []string // Text of each update, parallel to Updates
+	Project  string   // ArgoCD project of all updates when grouped by project, empty otherwise
+	Severity string   // Highest severity of the updates
+	Summary  string   // Scan totals, shown as a footer by channels that have one
 }
 
 // FormatMessages formats application updates into notification messages
 // Messages are split if they exceed the maximum length
 func (f *MessageFormatter) FormatMessages(updates []ApplicationUpdate) []string {
+	groups := f.FormatMessageGroups(updates)
+	messages := make([]string, 0, len(groups))
+	for _, group := range groups {
+		messages = append(messages, group.Text)
+	}
+	return messages
+}
+
+// FormatMessageGroups formats application updates into messages, keeping track of which
+// updates each message contains (used to attach per-update actions)
+func (f *MessageFormatter) FormatMessageGroups(updates []ApplicationUpdate) []FormattedMessage {
 	// Build individual app update strings
 	var appMessages []string
 	for _, update := range updates {
@@ -55,65 +94,158 @@ func (f *MessageFormatter) FormatMessages(updates []ApplicationUpdate) []string
 		for _, msg := range appMessages {
 			message.WriteString(msg)
 		}
-		return []string{message.String()}
+		return []FormattedMessage{{Text: message.String(), Updates: updates, Sections: appMessages, Severity: HighestSeverity(updates)}}
 	}
 
 	// Need to split into multiple messages
-	return f.splitMessages(header, appMessages)
+	return f.splitMessages(header, appMessages, updates)
+}
+
+// FormatProjectMessageGroups formats application updates into messages per ArgoCD project
+// Projects are ordered by name; each project's messages are split independently.
+func (f *MessageFormatter) FormatProjectMessageGroups(updates []ApplicationUpdate) []FormattedMessage {
+	byProject := make(map[string][]ApplicationUpdate)
+	var projects []string
+	for _, update := range updates {
+		if _, ok := byProject[update.Project]; !ok {
+			projects = append(projects, update.Project)
+		}
+		byProject[update.Project] = append(byProject[update.Project], update)
+	}
+	sort.Strings(projects)
+
+	var messages []FormattedMessage
+	for _, project := range projects {
+		for _, message := range f.FormatMessageGroups(byProject[project]) {
+			message.Project = project
+			messages = append(messages, message)
+		}
+	}
+	return messages
 }
 
 // formatSingleUpdate formats a single application update
 func (f *MessageFormatter) formatSingleUpdate(update ApplicationUpdate) string {
 	var sb strings.Builder
 
-	// Compact format: app name as header with project
+	// Compact format: app name as header with project, prefixed by the severity emoji if configured
+	if emoji := f.Emojis[UpdateSeverity(update)]; emoji != "" {
+		sb.WriteString(emoji + " ")
+	}
+	tr := f.Localizer
 	sb.WriteString(fmt.Sprintf("%s (%s)\n", update.AppName, update.Project))
-	sb.WriteString(fmt.Sprintf("  Chart: %s\n", update.ChartName))
-	sb.WriteString(fmt.Sprintf("  Version: %s -> %s\n", update.CurrentVersion, update.LatestVersion))
+	if update.Instance != "" {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldInstance), update.Instance))
+	}
+	sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldChart), update.ChartName))
+	sb.WriteString(fmt.Sprintf("  %s: %s -> %s\n", tr.T(i18n.FieldVersion), update.CurrentVersion, update.LatestVersion))
 
 	// Show constraint if not "major" (default)
 	if update.ConstraintApplied != "major" && update.ConstraintApplied != "" {
-		sb.WriteString(fmt.Sprintf("  Constraint: %s\n", update.ConstraintApplied))
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldConstraint), update.ConstraintApplied))
 	}
 
 	// Show note if updates exist outside constraint
 	if update.HasUpdateOutsideConstraint && update.LatestVersionAll != "" && update.LatestVersionAll != update.LatestVersion {
-		sb.WriteString(fmt.Sprintf("  Note: v%s available outside constraint\n", update.LatestVersionAll))
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldNote), tr.T(i18n.ShortVersionOutsideConstraint, update.LatestVersionAll)))
 	}
 
-	sb.WriteString(fmt.Sprintf("  Repo: %s\n", update.RepoURL))
+	if update.SyncBlocked {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldSyncWindow), FormatSyncDeferral(tr, update.SyncBlockedBy, update.NextSyncWindow)))
+	}
+
+	if update.FixedVulnerabilities > 0 {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldSecurityFixes), tr.T(i18n.VulnerabilitiesFixed, update.FixedVulnerabilities)))
+	}
+
+	sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldRepo), update.RepoURL))
+	if update.ReleaseNotes != "" {
+		// Notifications keep the excerpt on one line, so it stays a single detail of the update
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldChanges), strings.Join(strings.Fields(update.ReleaseNotes), " ")))
+	}
+	if update.ReleaseNotesURL != "" {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldReleaseNotes), update.ReleaseNotesURL))
+	}
+	if update.URL != "" {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", tr.T(i18n.FieldLink), update.URL))
+	}
 	sb.WriteString("\n")
 
 	return sb.String()
 }
 
 // splitMessages splits app messages into multiple messages that fit within the max length
-func (f *MessageFormatter) splitMessages(header string, appMessages []string) []string {
-	var messages []string
+// appMessages and updates are parallel slices
+func (f *MessageFormatter) splitMessages(header string, appMessages []string, updates []ApplicationUpdate) []FormattedMessage {
+	var messages []FormattedMessage
 	var currentMessage strings.Builder
+	var currentUpdates []ApplicationUpdate
+	var currentSections []string
 	currentLength := 0
 
 	// First message gets the header
 	currentMessage.WriteString(header)
 	currentLength = len(header)
 
-	for _, appMsg := range appMessages {
+	for i, appMsg := range appMessages {
 		// Check if adding this app would exceed the limit
 		if currentLength+len(appMsg) > f.MaxMessageLength {
 			// Save current message and start a new one
-			messages = append(messages, currentMessage.String())
+			messages = append(messages, FormattedMessage{Text: currentMessage.String(), Updates: currentUpdates, Sections: currentSections, Severity: HighestSeverity(currentUpdates)})
 			currentMessage.Reset()
+			currentUpdates = nil
+			currentSections = nil
 			currentLength = 0
 		}
 
 		currentMessage.WriteString(appMsg)
+		currentUpdates = append(currentUpdates, updates[i])
+		currentSections = append(currentSections, appMsg)
 		currentLength += len(appMsg)
 	}
 
 	// Add the last message if it has content
 	if currentLength > 0 {
-		messages = append(messages, currentMessage.String())
+		messages = append(messages, FormattedMessage{Text: currentMessage.String(), Updates: currentUpdates, Sections: currentSections, Severity: HighestSeverity(currentUpdates)})
 	}
 
 	return messages
 }
+
+// FormatSyncDeferral describes an update deferred by a sync window, e.g.
+// "Deferred until 2026-10-16 06:00 UTC (deny 0 22 * * * (8h))"
+func FormatSyncDeferral(tr *i18n.Localizer, blockedBy, nextSyncWindow string) string {
+	reason := blockedBy
+	if reason == "" {
+		reason = tr.T(i18n.SyncOutsideAllowWindows)
+	}
+	if nextSyncWindow == "" {
+		return tr.T(i18n.SyncDeferredNoWindow, reason)
+	}
+
+	next := nextSyncWindow
+	if t, err := time.Parse(time.RFC3339, nextSyncWindow); err == nil {
+		next = t.UTC().Format("2006-01-02 15:04 MST")
+	}
+	return tr.T(i18n.SyncDeferredUntil, next, reason)
+}
+
+// splitSection splits the formatted text of an update into its first line (application and
+// project) and its details, without indentation
+func splitSection(section string) (string, []string) {
+	lines := strings.Split(strings.TrimRight(section, "\n"), "\n")
+	details := make([]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		details = append(details, strings.TrimSpace(line))
+	}
+	return lines[0], details
+}
+
+// truncateText shortens text to at most limit characters, marking the cut with an ellipsis
+func truncateText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
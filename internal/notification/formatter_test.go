@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+
+	"argazer/internal/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSyncDeferral(t *testing.T) {
+	assert.Equal(t, "Deferred until 2026-10-16 06:00 UTC (deny 0 22 * * * (8h))",
+		FormatSyncDeferral(nil, "deny 0 22 * * * (8h)", "2026-10-16T06:00:00Z"))
+	assert.Equal(t, "Deferred until 2026-10-16 09:00 UTC (outside allow windows)",
+		FormatSyncDeferral(nil, "", "2026-10-16T09:00:00Z"))
+	assert.Equal(t, "Deferred, no allowed window within 7 days (deny 0 0 * * * (24h))",
+		FormatSyncDeferral(nil, "deny 0 0 * * * (24h)", ""))
+	assert.Equal(t, "Zurückgestellt bis 2026-10-16 06:00 UTC (außerhalb der Erlaubnisfenster)",
+		FormatSyncDeferral(i18n.New("de"), "", "2026-10-16T06:00:00Z"))
+}
+
+func TestFormatMessageGroups_SyncWindow(t *testing.T) {
+	updates := []ApplicationUpdate{
+		{AppName: "api", Project: "prod", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", RepoURL: "https://charts.example.com"},
+		{AppName: "web", Project: "prod", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", RepoURL: "https://charts.example.com",
+			SyncBlocked: true, SyncBlockedBy: "deny 0 22 * * * (8h)", NextSyncWindow: "2026-10-16T06:00:00Z"},
+	}
+
+	groups := NewMessageFormatter().FormatMessageGroups(updates)
+	assert.Len(t, groups, 1)
+	assert.Contains(t, groups[0].Text, "web (prod)\n  Chart: nginx\n  Version: 1.0.0 -> 1.1.0\n  Sync Window: Deferred until 2026-10-16 06:00 UTC (deny 0 22 * * * (8h))\n")
+	assert.Equal(t, 1, strings.Count(groups[0].Text, "Sync Window"))
+}
+
+func TestFormatMessageGroups_Link(t *testing.T) {
+	formatter := NewMessageFormatter()
+	updates := []ApplicationUpdate{
+		{AppName: "app1", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", URL: "https://argocd.example.com/applications/argocd/app1"},
+		{AppName: "app2", Project: "default", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+	}
+
+	messages := formatter.FormatMessageGroups(updates)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Text, "  Link: https://argocd.example.com/applications/argocd/app1\n")
+	assert.Equal(t, 1, strings.Count(messages[0].Text, "Link:"))
+}
+
+func TestFormatMessageGroups_Instance(t *testing.T) {
+	formatter := NewMessageFormatter()
+	updates := []ApplicationUpdate{
+		{AppName: "app1", Project: "default", Instance: "production", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+		{AppName: "app2", Project: "default", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+	}
+
+	messages := formatter.FormatMessageGroups(updates)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Text, "app1 (default)\n  Instance: production\n")
+	assert.Equal(t, 1, strings.Count(messages[0].Text, "Instance:"))
+}
+
+func TestFormatMessageGroups_ReleaseNotes(t *testing.T) {
+	updates := []ApplicationUpdate{
+		{AppName: "web", Project: "prod", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.2.0", RepoURL: "https://charts.example.com",
+			ReleaseNotes: "1.2.0:\n- added: Ingress support\n1.1.0:\n- Bump image", ReleaseNotesURL: "https://github.com/org/charts/releases"},
+	}
+
+	messages := NewMessageFormatter().FormatMessageGroups(updates)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Text, "  Repo: https://charts.example.com\n  Changes: 1.2.0: - added: Ingress support 1.1.0: - Bump image\n  Release Notes: https://github.com/org/charts/releases\n")
+}
+
+func TestFormatMessageGroups_SecurityFixes(t *testing.T) {
+	updates := []ApplicationUpdate{
+		{AppName: "web", Project: "prod", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.0.1", RepoURL: "https://charts.example.com", FixedVulnerabilities: 3},
+	}
+
+	messages := NewMessageFormatter().FormatMessageGroups(updates)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Text, "  Security Fixes: 3 known vulnerabilities fixed\n  Repo: https://charts.example.com\n")
+	assert.Equal(t, SeveritySecurity, messages[0].Severity)
+}
@@ -0,0 +1,122 @@
+package notification
+
+import (
+	"context"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// googleChatMessage represents the JSON payload for Google Chat space webhooks
+type googleChatMessage struct {
+	CardsV2 []googleChatCardWithID `json:"cardsV2"`
+}
+
+// googleChatCardWithID represents a card of a message
+type googleChatCardWithID struct {
+	CardID string         `json:"cardId"`
+	Card   googleChatCard `json:"card"`
+}
+
+// googleChatCard represents a card with a header and sections
+type googleChatCard struct {
+	Header   googleChatHeader    `json:"header"`
+	Sections []googleChatSection `json:"sections"`
+}
+
+// googleChatHeader represents a card header
+type googleChatHeader struct {
+	Title     string `json:"title"`
+	Subtitle  string `json:"subtitle,omitempty"`
+	ImageURL  string `json:"imageUrl,omitempty"`
+	ImageType string `json:"imageType,omitempty"`
+}
+
+// googleChatSection represents a card section
+type googleChatSection struct {
+	Header  string             `json:"header,omitempty"`
+	Widgets []googleChatWidget `json:"widgets"`
+}
+
+// googleChatWidget represents a text paragraph widget
+type googleChatWidget struct {
+	TextParagraph googleChatText `json:"textParagraph"`
+}
+
+// googleChatText represents formatted text, a subset of HTML
+type googleChatText struct {
+	Text string `json:"text"`
+}
+
+// GoogleChatNotifier handles sending notifications to a Google Chat space via an incoming webhook
+type GoogleChatNotifier struct {
+	*HTTPNotifier
+	style Style
+}
+
+// NewGoogleChatNotifier creates a new Google Chat notifier
+func NewGoogleChatNotifier(webhookURL string, logger *logrus.Entry) *GoogleChatNotifier {
+	return NewGoogleChatNotifierWithClient(webhookURL, nil, logger)
+}
+
+// NewGoogleChatNotifierWithClient creates a new Google Chat notifier with a custom HTTP client
+func NewGoogleChatNotifierWithClient(webhookURL string, httpClient *http.Client, logger *logrus.Entry) *GoogleChatNotifier {
+	return &GoogleChatNotifier{
+		HTTPNotifier: NewHTTPNotifier(webhookURL, httpClient, logger),
+	}
+}
+
+// SetStyle sets the card header subtitle and image
+func (n *GoogleChatNotifier) SetStyle(style Style) {
+	n.style = style
+}
+
+// Send sends a notification as a card with a single section (implements Notifier interface)
+func (n *GoogleChatNotifier) Send(ctx context.Context, subject, message string) error {
+	return n.SendMessage(ctx, subject, FormattedMessage{Text: message})
+}
+
+// SendMessage sends a notification as a card with one section per application (implements MessageNotifier)
+func (n *GoogleChatNotifier) SendMessage(ctx context.Context, subject string, message FormattedMessage) error {
+	var sections []googleChatSection
+	for _, section := range message.Sections {
+		name, details := splitSection(section)
+		for i, detail := range details {
+			details[i] = html.EscapeString(detail)
+		}
+		sections = append(sections, googleChatSection{
+			Header:  html.EscapeString(name),
+			Widgets: []googleChatWidget{{TextParagraph: googleChatText{Text: strings.Join(details, "<br>")}}},
+		})
+	}
+	if len(sections) == 0 {
+		text := html.EscapeString(strings.TrimRight(message.Text, "\n"))
+		sections = []googleChatSection{{
+			Widgets: []googleChatWidget{{TextParagraph: googleChatText{Text: strings.ReplaceAll(text, "\n", "<br>")}}},
+		}}
+	}
+
+	header := googleChatHeader{Title: subject, Subtitle: n.style.SenderName}
+	if n.style.IconURL != "" {
+		header.ImageURL = n.style.IconURL
+		header.ImageType = "CIRCLE"
+	}
+
+	payload := googleChatMessage{
+		CardsV2: []googleChatCardWithID{{
+			CardID: "argazer-updates",
+			Card:   googleChatCard{Header: header, Sections: sections},
+		}},
+	}
+
+	n.logger.WithField("sections", len(sections)).Debug("Sending Google Chat notification")
+
+	if err := n.SendJSON(ctx, payload); err != nil {
+		return err
+	}
+
+	n.logger.Info("Successfully sent Google Chat notification")
+	return nil
+}
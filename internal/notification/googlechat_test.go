@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogleChatNotifier_SendMessage(t *testing.T) {
+	var payload googleChatMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewGoogleChatNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	notifier.SetStyle(Style{SenderName: "Argazer", IconURL: "https://example.com/argazer.png"})
+
+	formatter := NewMessageFormatter()
+	messages := formatter.FormatMessageGroups([]ApplicationUpdate{
+		{AppName: "app1", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", RepoURL: "https://charts.example.com"},
+		{AppName: "app2", Project: "<team>", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.0.1", RepoURL: "https://charts.example.com"},
+	})
+	require.Len(t, messages, 1)
+
+	var _ MessageNotifier = notifier
+	require.NoError(t, notifier.SendMessage(context.Background(), "2 updates", messages[0]))
+
+	require.Len(t, payload.CardsV2, 1)
+	card := payload.CardsV2[0].Card
+	assert.Equal(t, "2 updates", card.Header.Title)
+	assert.Equal(t, "Argazer", card.Header.Subtitle)
+	assert.Equal(t, "https://example.com/argazer.png", card.Header.ImageURL)
+	require.Len(t, card.Sections, 2)
+	assert.Equal(t, "app1 (default)", card.Sections[0].Header)
+	assert.Equal(t, "Chart: nginx<br>Version: 1.0.0 -&gt; 2.0.0<br>Repo: https://charts.example.com", card.Sections[0].Widgets[0].TextParagraph.Text)
+	assert.Equal(t, "app2 (&lt;team&gt;)", card.Sections[1].Header)
+}
+
+func TestGoogleChatNotifier_Send(t *testing.T) {
+	var payload googleChatMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewGoogleChatNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Line 1\nLine 2\n"))
+
+	require.Len(t, payload.CardsV2, 1)
+	card := payload.CardsV2[0].Card
+	assert.Equal(t, "Subject", card.Header.Title)
+	assert.Empty(t, card.Header.ImageURL)
+	require.Len(t, card.Sections, 1)
+	assert.Empty(t, card.Sections[0].Header)
+	assert.Equal(t, "Line 1<br>Line 2", card.Sections[0].Widgets[0].TextParagraph.Text)
+}
+
+func TestGoogleChatNotifier_Send_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := NewGoogleChatNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	err := notifier.Send(context.Background(), "Subject", "Message")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 400")
+}
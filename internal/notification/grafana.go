@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GrafanaAnnotationTag is the tag of every annotation, which the exported dashboard queries
+const GrafanaAnnotationTag = "argazer"
+
+// GrafanaConfig configures the annotations created in Grafana
+type GrafanaConfig struct {
+	URL          string   // Grafana base URL, e.g. https://grafana.example.com
+	Token        string   // Service account token with the annotations:write permission
+	DashboardUID string   // Dashboard the annotations belong to, empty for organization-wide annotations
+	Tags         []string // Tags added to every annotation besides "argazer"
+}
+
+// grafanaAnnotation represents the JSON payload of the Grafana create annotation API
+type grafanaAnnotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	Time         int64    `json:"time"` // Milliseconds since the epoch
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+}
+
+// GrafanaNotifier marks updates as annotations on Grafana graphs
+type GrafanaNotifier struct {
+	*HTTPNotifier
+	dashboardUID string
+	tags         []string
+}
+
+// NewGrafanaNotifier creates a new Grafana annotation notifier
+func NewGrafanaNotifier(cfg GrafanaConfig, logger *logrus.Entry) *GrafanaNotifier {
+	return NewGrafanaNotifierWithClient(cfg, nil, logger)
+}
+
+// NewGrafanaNotifierWithClient creates a new Grafana annotation notifier with a custom HTTP client
+func NewGrafanaNotifierWithClient(cfg GrafanaConfig, httpClient *http.Client, logger *logrus.Entry) *GrafanaNotifier {
+	httpNotifier := NewHTTPNotifier(strings.TrimSuffix(cfg.URL, "/")+"/api/annotations", httpClient, logger)
+	if cfg.Token != "" {
+		httpNotifier.SetHeader("Authorization", "Bearer "+cfg.Token)
+	}
+
+	return &GrafanaNotifier{
+		HTTPNotifier: httpNotifier,
+		dashboardUID: cfg.DashboardUID,
+		tags:         append([]string{GrafanaAnnotationTag}, cfg.Tags...),
+	}
+}
+
+// Send creates an annotation with the subject and message (implements Notifier interface)
+func (n *GrafanaNotifier) Send(ctx context.Context, subject, message string) error {
+	annotation := grafanaAnnotation{
+		DashboardUID: n.dashboardUID,
+		Time:         time.Now().UnixMilli(),
+		Tags:         n.tags,
+		Text:         subject + "\n" + message,
+	}
+
+	if err := n.SendJSON(ctx, annotation); err != nil {
+		return fmt.Errorf("failed to create Grafana annotation: %w", err)
+	}
+
+	n.logger.Info("Successfully created Grafana annotation")
+	return nil
+}
+
+// SendUpdates creates one annotation per update, tagged with its project and chart (implements EventNotifier)
+// The Grafana API creates a single annotation per request.
+func (n *GrafanaNotifier) SendUpdates(ctx context.Context, updates []ApplicationUpdate) error {
+	now := time.Now()
+	for _, update := range updates {
+		if err := n.SendJSON(ctx, n.updateAnnotation(update, now)); err != nil {
+			return fmt.Errorf("failed to create Grafana annotation for %s: %w", update.AppName, err)
+		}
+	}
+
+	n.logger.WithField("annotations", len(updates)).Info("Successfully created Grafana annotations")
+	return nil
+}
+
+// updateAnnotation builds the annotation of an application update
+func (n *GrafanaNotifier) updateAnnotation(update ApplicationUpdate, created time.Time) grafanaAnnotation {
+	tags := append(append([]string{}, n.tags...), "project:"+update.Project, "chart:"+update.ChartName)
+	if update.Instance != "" {
+		tags = append(tags, "instance:"+update.Instance)
+	}
+
+	text := fmt.Sprintf("Helm chart update available for %s: %s %s -> %s", update.AppName, update.ChartName, update.CurrentVersion, update.LatestVersion)
+	// Grafana renders the text as HTML
+	if update.URL != "" {
+		text += fmt.Sprintf(` (<a href="%s">Open in ArgoCD</a>)`, html.EscapeString(update.URL))
+	}
+
+	return grafanaAnnotation{
+		DashboardUID: n.dashboardUID,
+		Time:         created.UnixMilli(),
+		Tags:         tags,
+		Text:         text,
+	}
+}
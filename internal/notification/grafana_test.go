@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrafanaNotifier_SendUpdates(t *testing.T) {
+	var annotations []grafanaAnnotation
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/annotations", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var annotation grafanaAnnotation
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&annotation))
+		annotations = append(annotations, annotation)
+	}))
+	defer server.Close()
+
+	notifier := NewGrafanaNotifier(GrafanaConfig{
+		URL:          server.URL + "/",
+		Token:        "secret",
+		DashboardUID: "argazer",
+		Tags:         []string{"helm"},
+	}, logrus.NewEntry(logrus.New()))
+
+	var _ EventNotifier = notifier
+	err := notifier.SendUpdates(context.Background(), []ApplicationUpdate{
+		{AppName: "app1", Project: "payments", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", URL: "https://argocd.example.com/applications/argocd/app1?a=1&b=2"},
+		{AppName: "app2", Project: "default", Instance: "prod", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, annotations, 2)
+	assert.Equal(t, "argazer", annotations[0].DashboardUID)
+	assert.Greater(t, annotations[0].Time, int64(0))
+	assert.Equal(t, []string{"argazer", "helm", "project:payments", "chart:nginx"}, annotations[0].Tags)
+	assert.Equal(t, `Helm chart update available for app1: nginx 1.0.0 -> 2.0.0 (<a href="https://argocd.example.com/applications/argocd/app1?a=1&amp;b=2">Open in ArgoCD</a>)`, annotations[0].Text)
+	assert.Equal(t, []string{"argazer", "helm", "project:default", "chart:redis", "instance:prod"}, annotations[1].Tags)
+	assert.Equal(t, "Helm chart update available for app2: redis 1.0.0 -> 1.1.0", annotations[1].Text)
+}
+
+func TestGrafanaNotifier_Send(t *testing.T) {
+	var annotation grafanaAnnotation
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&annotation))
+	}))
+	defer server.Close()
+
+	notifier := NewGrafanaNotifier(GrafanaConfig{URL: server.URL}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, notifier.Send(context.Background(), "Test", "Hello"))
+
+	assert.Empty(t, annotation.DashboardUID)
+	assert.Equal(t, []string{"argazer"}, annotation.Tags)
+	assert.Equal(t, "Test\nHello", annotation.Text)
+}
+
+func TestGrafanaNotifier_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	notifier := NewGrafanaNotifier(GrafanaConfig{URL: server.URL}, logrus.NewEntry(logrus.New()))
+	err := notifier.SendUpdates(context.Background(), []ApplicationUpdate{{AppName: "app1"}})
+	assert.ErrorContains(t, err, "failed to create Grafana annotation for app1: failed to send message: status 403")
+}
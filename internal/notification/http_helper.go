@@ -3,6 +3,9 @@ package notification
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -20,12 +23,16 @@ const (
 	DefaultMaxRetries = 3
 	// DefaultInitialRetryDelay is the initial delay before retrying
 	DefaultInitialRetryDelay = 1 * time.Second
+	// SignatureHeader carries the HMAC-SHA256 signature of the request body, as "sha256=<hex>"
+	SignatureHeader = "X-Argazer-Signature-256"
 )
 
 // HTTPNotifier provides common functionality for HTTP-based notifiers
 type HTTPNotifier struct {
 	webhookURL string
 	httpClient *http.Client
+	headers    map[string]string // Extra headers sent with every request (e.g. Authorization)
+	secret     string            // Key signing request bodies, empty to send them unsigned
 	logger     *logrus.Entry
 }
 
@@ -44,8 +51,34 @@ func NewHTTPNotifier(webhookURL string, httpClient *http.Client, logger *logrus.
 	}
 }
 
+// SetHeader sets an extra header sent with every request
+func (n *HTTPNotifier) SetHeader(key, value string) {
+	if n.headers == nil {
+		n.headers = make(map[string]string)
+	}
+	n.headers[key] = value
+}
+
+// SetSigningSecret signs every request body with HMAC-SHA256, sent in the SignatureHeader, so
+// receivers can verify requests come from argazer
+func (n *HTTPNotifier) SetSigningSecret(secret string) {
+	n.secret = secret
+}
+
+// SignBody returns the SignatureHeader value of a request body
+func SignBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
 // SendJSON sends a JSON payload to the webhook URL with retry logic
 func (n *HTTPNotifier) SendJSON(ctx context.Context, payload interface{}) error {
+	return n.SendJSONTo(ctx, n.webhookURL, payload)
+}
+
+// SendJSONTo sends a JSON payload to the given URL with retry logic
+func (n *HTTPNotifier) SendJSONTo(ctx context.Context, url string, payload interface{}) error {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal payload: %w", err)
@@ -75,13 +108,19 @@ func (n *HTTPNotifier) SendJSON(ctx context.Context, payload interface{}) error
 		}
 
 		// Create request
-		req, err := http.NewRequestWithContext(ctx, "POST", n.webhookURL, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			return fmt.Errorf("failed to create request: %w", err)
 		}
 
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("User-Agent", UserAgent)
+		for key, value := range n.headers {
+			req.Header.Set(key, value)
+		}
+		if n.secret != "" {
+			req.Header.Set(SignatureHeader, SignBody(n.secret, jsonData))
+		}
 
 		if attempt == 0 {
 			n.logger.Debug("Sending HTTP notification")
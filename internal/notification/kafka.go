@@ -0,0 +1,93 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"argazer/internal/kafka"
+
+	"github.com/sirupsen/logrus"
+)
+
+// kafkaEventHeader is the record header carrying the event type
+const kafkaEventHeader = "event"
+
+// kafkaProducer publishes records to a topic
+type kafkaProducer interface {
+	Produce(ctx context.Context, topic string, records []kafka.Record) error
+}
+
+// KafkaNotifier publishes update events to a Kafka topic
+type KafkaNotifier struct {
+	producer kafkaProducer
+	topic    string
+	logger   *logrus.Entry
+}
+
+// NewKafkaNotifier creates a new Kafka notifier
+func NewKafkaNotifier(cfg kafka.Config, topic string, logger *logrus.Entry) (*KafkaNotifier, error) {
+	producer, err := kafka.NewProducer(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
+	}
+	return NewKafkaNotifierWithProducer(producer, topic, logger), nil
+}
+
+// NewKafkaNotifierWithProducer creates a new Kafka notifier with a custom producer
+func NewKafkaNotifierWithProducer(producer kafkaProducer, topic string, logger *logrus.Entry) *KafkaNotifier {
+	return &KafkaNotifier{
+		producer: producer,
+		topic:    topic,
+		logger:   logger,
+	}
+}
+
+// Send publishes a plain notification event (implements Notifier interface)
+func (n *KafkaNotifier) Send(ctx context.Context, subject, message string) error {
+	value, err := json.Marshal(NewNotificationEvent(subject, message, time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	record := kafka.Record{
+		Value:   value,
+		Headers: []kafka.Header{{Key: kafkaEventHeader, Value: EventNotification}},
+	}
+
+	if err := n.producer.Produce(ctx, n.topic, []kafka.Record{record}); err != nil {
+		return fmt.Errorf("failed to publish to Kafka topic %s: %w", n.topic, err)
+	}
+
+	n.logger.WithField("topic", n.topic).Info("Successfully sent Kafka notification")
+	return nil
+}
+
+// SendUpdates publishes one event per update, keyed by application name (implements EventNotifier)
+// Keying by application keeps all events of an application in the same partition.
+func (n *KafkaNotifier) SendUpdates(ctx context.Context, updates []ApplicationUpdate) error {
+	now := time.Now()
+	records := make([]kafka.Record, 0, len(updates))
+	for _, update := range updates {
+		value, err := json.Marshal(NewUpdateEvent(update, now))
+		if err != nil {
+			return fmt.Errorf("failed to marshal event for %s: %w", update.AppName, err)
+		}
+		records = append(records, kafka.Record{
+			Key:     []byte(update.AppName),
+			Value:   value,
+			Headers: []kafka.Header{{Key: kafkaEventHeader, Value: EventUpdateAvailable}},
+		})
+	}
+
+	if err := n.producer.Produce(ctx, n.topic, records); err != nil {
+		return fmt.Errorf("failed to publish to Kafka topic %s: %w", n.topic, err)
+	}
+
+	n.logger.WithFields(logrus.Fields{
+		"topic":  n.topic,
+		"events": len(records),
+	}).Info("Successfully published Kafka update events")
+	return nil
+}
@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"argazer/internal/kafka"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockKafkaProducer records produced records
+type mockKafkaProducer struct {
+	topic   string
+	records []kafka.Record
+	err     error
+}
+
+func (m *mockKafkaProducer) Produce(ctx context.Context, topic string, records []kafka.Record) error {
+	m.topic = topic
+	m.records = append(m.records, records...)
+	return m.err
+}
+
+func TestNewKafkaNotifier(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	notifier, err := NewKafkaNotifier(kafka.Config{Brokers: []string{"localhost:9092"}}, "argazer", logger)
+	require.NoError(t, err)
+	assert.Equal(t, "argazer", notifier.topic)
+
+	_, err = NewKafkaNotifier(kafka.Config{}, "argazer", logger)
+	assert.Error(t, err)
+}
+
+func TestKafkaNotifier_SendUpdates(t *testing.T) {
+	producer := &mockKafkaProducer{}
+	notifier := NewKafkaNotifierWithProducer(producer, "chart-updates", logrus.NewEntry(logrus.New()))
+
+	updates := []ApplicationUpdate{
+		{AppName: "app1", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", RepoURL: "https://charts.example.com"},
+		{AppName: "app2", Project: "prod", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", RepoURL: "oci://registry.example.com/charts", ConstraintApplied: "minor"},
+	}
+
+	require.NoError(t, notifier.SendUpdates(context.Background(), updates))
+
+	assert.Equal(t, "chart-updates", producer.topic)
+	require.Len(t, producer.records, 2)
+	assert.Equal(t, "app1", string(producer.records[0].Key))
+	assert.Equal(t, []kafka.Header{{Key: "event", Value: EventUpdateAvailable}}, producer.records[0].Headers)
+
+	var event UpdateEvent
+	require.NoError(t, json.Unmarshal(producer.records[1].Value, &event))
+	assert.Equal(t, EventUpdateAvailable, event.Event)
+	assert.Equal(t, "app2", event.AppName)
+	assert.Equal(t, "prod", event.Project)
+	assert.Equal(t, "redis", event.ChartName)
+	assert.Equal(t, "1.0.0", event.CurrentVersion)
+	assert.Equal(t, "1.1.0", event.LatestVersion)
+	assert.Equal(t, "minor", event.ConstraintApplied)
+	assert.False(t, event.Timestamp.IsZero())
+}
+
+func TestKafkaNotifier_Send(t *testing.T) {
+	producer := &mockKafkaProducer{}
+	notifier := NewKafkaNotifierWithProducer(producer, "chart-updates", logrus.NewEntry(logrus.New()))
+
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+
+	require.Len(t, producer.records, 1)
+	assert.Nil(t, producer.records[0].Key)
+
+	var event NotificationEvent
+	require.NoError(t, json.Unmarshal(producer.records[0].Value, &event))
+	assert.Equal(t, EventNotification, event.Event)
+	assert.Equal(t, "Subject", event.Subject)
+	assert.Equal(t, "Message", event.Message)
+}
+
+func TestKafkaNotifier_Error(t *testing.T) {
+	producer := &mockKafkaProducer{err: assert.AnError}
+	notifier := NewKafkaNotifierWithProducer(producer, "chart-updates", logrus.NewEntry(logrus.New()))
+
+	err := notifier.SendUpdates(context.Background(), []ApplicationUpdate{{AppName: "app1"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chart-updates")
+	assert.ErrorIs(t, err, assert.AnError)
+}
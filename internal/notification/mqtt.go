@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"argazer/internal/mqtt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMQTTTopic is the default topic template for MQTT update events
+const DefaultMQTTTopic = "argazer/{project}/{app}"
+
+// mqttNotificationLevel is appended to the static topic prefix for plain notifications
+const mqttNotificationLevel = "notification"
+
+// mqttPublisher publishes messages to a broker
+type mqttPublisher interface {
+	Publish(ctx context.Context, messages []mqtt.Message) error
+}
+
+// MQTTNotifier publishes update events to MQTT topics
+type MQTTNotifier struct {
+	publisher     mqttPublisher
+	topicTemplate string
+	logger        *logrus.Entry
+}
+
+// NewMQTTNotifier creates a new MQTT notifier
+// The topic template may contain {app}, {namespace}, {project}, {instance} and {chart} placeholders.
+func NewMQTTNotifier(cfg mqtt.Config, topicTemplate string, logger *logrus.Entry) (*MQTTNotifier, error) {
+	client, err := mqtt.NewClient(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MQTT client: %w", err)
+	}
+	return NewMQTTNotifierWithPublisher(client, topicTemplate, logger), nil
+}
+
+// NewMQTTNotifierWithPublisher creates a new MQTT notifier with a custom publisher
+func NewMQTTNotifierWithPublisher(publisher mqttPublisher, topicTemplate string, logger *logrus.Entry) *MQTTNotifier {
+	if topicTemplate == "" {
+		topicTemplate = DefaultMQTTTopic
+	}
+	return &MQTTNotifier{
+		publisher:     publisher,
+		topicTemplate: topicTemplate,
+		logger:        logger,
+	}
+}
+
+// Send publishes a plain notification event (implements Notifier interface)
+// It goes to the static prefix of the topic template followed by "/notification",
+// e.g. "argazer/notification" for the default template.
+func (n *MQTTNotifier) Send(ctx context.Context, subject, message string) error {
+	payload, err := json.Marshal(NewNotificationEvent(subject, message, time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	topic := mqttNotificationTopic(n.topicTemplate)
+	if err := n.publisher.Publish(ctx, []mqtt.Message{{Topic: topic, Payload: payload}}); err != nil {
+		return fmt.Errorf("failed to publish MQTT notification: %w", err)
+	}
+
+	n.logger.WithField("topic", topic).Info("Successfully sent MQTT notification")
+	return nil
+}
+
+// SendUpdates publishes one event per update to its rendered topic (implements EventNotifier)
+func (n *MQTTNotifier) SendUpdates(ctx context.Context, updates []ApplicationUpdate) error {
+	now := time.Now()
+	messages := make([]mqtt.Message, 0, len(updates))
+	for _, update := range updates {
+		payload, err := json.Marshal(NewUpdateEvent(update, now))
+		if err != nil {
+			return fmt.Errorf("failed to marshal event for %s: %w", update.AppName, err)
+		}
+		messages = append(messages, mqtt.Message{
+			Topic:   renderMQTTTopic(n.topicTemplate, update),
+			Payload: payload,
+		})
+	}
+
+	if err := n.publisher.Publish(ctx, messages); err != nil {
+		return fmt.Errorf("failed to publish MQTT update events: %w", err)
+	}
+
+	n.logger.WithField("events", len(messages)).Info("Successfully published MQTT update events")
+	return nil
+}
+
+// mqttTopicValue makes a value safe to use as (part of) a single topic level
+var mqttTopicValue = strings.NewReplacer("/", "_", "+", "_", "#", "_")
+
+// renderMQTTTopic substitutes the update's fields into the topic template
+func renderMQTTTopic(template string, update ApplicationUpdate) string {
+	return strings.NewReplacer(
+		"{app}", mqttTopicValue.Replace(update.AppName),
+		"{namespace}", mqttTopicValue.Replace(update.Namespace),
+		"{project}", mqttTopicValue.Replace(update.Project),
+		"{instance}", mqttTopicValue.Replace(update.Instance),
+		"{chart}", mqttTopicValue.Replace(update.ChartName),
+	).Replace(template)
+}
+
+// mqttNotificationTopic returns the topic for plain notifications: the template levels
+// before the first placeholder followed by "notification"
+func mqttNotificationTopic(template string) string {
+	levels := strings.Split(template, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "{") {
+			return strings.Join(append(levels[:i:i], mqttNotificationLevel), "/")
+		}
+	}
+	return template
+}
@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"argazer/internal/mqtt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMQTTPublisher records published messages
+type mockMQTTPublisher struct {
+	messages []mqtt.Message
+	err      error
+}
+
+func (m *mockMQTTPublisher) Publish(ctx context.Context, messages []mqtt.Message) error {
+	m.messages = append(m.messages, messages...)
+	return m.err
+}
+
+func TestNewMQTTNotifier(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	notifier, err := NewMQTTNotifier(mqtt.Config{BrokerURL: "mqtt://localhost"}, "", logger)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMQTTTopic, notifier.topicTemplate)
+
+	_, err = NewMQTTNotifier(mqtt.Config{BrokerURL: "http://localhost"}, "", logger)
+	assert.Error(t, err)
+}
+
+func TestMQTTNotifier_SendUpdates(t *testing.T) {
+	publisher := &mockMQTTPublisher{}
+	notifier := NewMQTTNotifierWithPublisher(publisher, "homelab/argazer/{project}/{app}", logrus.NewEntry(logrus.New()))
+
+	updates := []ApplicationUpdate{
+		{AppName: "app1", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0"},
+		{AppName: "app2", Project: "prod", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+	}
+
+	require.NoError(t, notifier.SendUpdates(context.Background(), updates))
+
+	require.Len(t, publisher.messages, 2)
+	assert.Equal(t, "homelab/argazer/default/app1", publisher.messages[0].Topic)
+	assert.Equal(t, "homelab/argazer/prod/app2", publisher.messages[1].Topic)
+
+	var event UpdateEvent
+	require.NoError(t, json.Unmarshal(publisher.messages[1].Payload, &event))
+	assert.Equal(t, EventUpdateAvailable, event.Event)
+	assert.Equal(t, "app2", event.AppName)
+	assert.Equal(t, "redis", event.ChartName)
+	assert.Equal(t, "1.1.0", event.LatestVersion)
+}
+
+func TestMQTTNotifier_Send(t *testing.T) {
+	publisher := &mockMQTTPublisher{}
+	notifier := NewMQTTNotifierWithPublisher(publisher, "", logrus.NewEntry(logrus.New()))
+
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+
+	require.Len(t, publisher.messages, 1)
+	assert.Equal(t, "argazer/notification", publisher.messages[0].Topic)
+
+	var event NotificationEvent
+	require.NoError(t, json.Unmarshal(publisher.messages[0].Payload, &event))
+	assert.Equal(t, EventNotification, event.Event)
+	assert.Equal(t, "Subject", event.Subject)
+}
+
+func TestMQTTNotifier_Error(t *testing.T) {
+	publisher := &mockMQTTPublisher{err: assert.AnError}
+	notifier := NewMQTTNotifierWithPublisher(publisher, "", logrus.NewEntry(logrus.New()))
+
+	err := notifier.SendUpdates(context.Background(), []ApplicationUpdate{{AppName: "app1"}})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestRenderMQTTTopic(t *testing.T) {
+	update := ApplicationUpdate{AppName: "app1", Namespace: "team-a-apps", Project: "team/a", ChartName: "nginx"}
+
+	assert.Equal(t, "argazer/team_a/app1", renderMQTTTopic(DefaultMQTTTopic, update))
+	assert.Equal(t, "charts/nginx/app1-updates", renderMQTTTopic("charts/{chart}/{app}-updates", update))
+	assert.Equal(t, "argazer/team-a-apps/app1", renderMQTTTopic("argazer/{namespace}/{app}", update))
+	assert.Equal(t, "static/topic", renderMQTTTopic("static/topic", update))
+}
+
+func TestMQTTNotificationTopic(t *testing.T) {
+	tests := map[string]string{
+		"argazer/{project}/{app}":         "argazer/notification",
+		"home/argazer/{app}":              "home/argazer/notification",
+		"{project}/{app}":                 "notification",
+		"home/argazer/updates":            "home/argazer/updates",
+		"home/argazer/app-{app}/versions": "home/argazer/notification",
+	}
+
+	for template, expected := range tests {
+		assert.Equal(t, expected, mqttNotificationTopic(template), template)
+	}
+}
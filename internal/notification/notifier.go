@@ -6,3 +6,45 @@ import "context"
 type Notifier interface {
 	Send(ctx context.Context, subject, message string) error
 }
+
+// Action is an interactive button attached to a notification
+type Action struct {
+	Label string // Button text shown to the user
+	Data  string // Opaque payload returned to argazer when the button is pressed
+	URL   string // Link opened by the button instead of returning Data
+}
+
+// InteractiveNotifier is implemented by notifiers that can attach action buttons to messages
+// Each inner slice of actions is rendered as one row of buttons.
+type InteractiveNotifier interface {
+	Notifier
+	SendWithActions(ctx context.Context, subject, message string, actions [][]Action) error
+}
+
+// EventNotifier is implemented by notifiers that publish one structured event per application update
+// (e.g. to a message broker) rather than human-readable messages
+type EventNotifier interface {
+	Notifier
+	SendUpdates(ctx context.Context, updates []ApplicationUpdate) error
+}
+
+// SeverityNotifier is implemented by notifiers that adapt a message's look (e.g. card color)
+// to the highest severity of the updates it contains
+type SeverityNotifier interface {
+	Notifier
+	SendWithSeverity(ctx context.Context, subject, message, severity string) error
+}
+
+// ReportNotifier is implemented by notifiers that post the complete, machine-readable results of a
+// scan (e.g. to a generic webhook) rather than messages about its updates
+type ReportNotifier interface {
+	Notifier
+	SendReport(ctx context.Context, report any) error
+}
+
+// MessageNotifier is implemented by notifiers that lay out the updates of a message themselves
+// (e.g. one card field per application) instead of sending its text as a whole
+type MessageNotifier interface {
+	Notifier
+	SendMessage(ctx context.Context, subject string, message FormattedMessage) error
+}
@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OpsgenieAPIURL is the Opsgenie API of US accounts; EU accounts use https://api.eu.opsgenie.com
+const OpsgenieAPIURL = "https://api.opsgenie.com"
+
+// OpsgenieDefaultPriority is the alert priority used when no priority is set for a severity
+const OpsgenieDefaultPriority = "P3"
+
+// Alert field limits enforced by Opsgenie
+const (
+	opsgenieMaxMessage     = 130
+	opsgenieMaxDescription = 15000
+)
+
+// OpsgenieConfig configures the alerts created in Opsgenie
+type OpsgenieConfig struct {
+	APIURL     string            // API base URL (default: OpsgenieAPIURL)
+	APIKey     string            // API key of an API integration
+	Responders []string          // "team:<name>", "user:<username>", "escalation:<name>", "schedule:<name>", or a team name
+	Tags       []string          // Tags added to every alert besides "argazer"
+	Priorities map[string]string // Alert priority ("P1" to "P5") by update severity
+}
+
+// opsgenieAlert represents the JSON payload of the Opsgenie create alert API
+type opsgenieAlert struct {
+	Message     string              `json:"message"`
+	Alias       string              `json:"alias,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Responders  []opsgenieResponder `json:"responders,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Details     map[string]string   `json:"details,omitempty"`
+	Source      string              `json:"source"`
+	Priority    string              `json:"priority"`
+}
+
+// opsgenieResponder represents a team, user, escalation or schedule notified of an alert
+type opsgenieResponder struct {
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// OpsgenieNotifier handles sending notifications as Opsgenie alerts
+type OpsgenieNotifier struct {
+	*HTTPNotifier
+	responders []opsgenieResponder
+	tags       []string
+	priorities map[string]string
+}
+
+// NewOpsgenieNotifier creates a new Opsgenie notifier
+func NewOpsgenieNotifier(cfg OpsgenieConfig, logger *logrus.Entry) *OpsgenieNotifier {
+	return NewOpsgenieNotifierWithClient(cfg, nil, logger)
+}
+
+// NewOpsgenieNotifierWithClient creates a new Opsgenie notifier with a custom HTTP client
+func NewOpsgenieNotifierWithClient(cfg OpsgenieConfig, httpClient *http.Client, logger *logrus.Entry) *OpsgenieNotifier {
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = OpsgenieAPIURL
+	}
+	httpNotifier := NewHTTPNotifier(strings.TrimSuffix(apiURL, "/")+"/v2/alerts", httpClient, logger)
+	httpNotifier.SetHeader("Authorization", "GenieKey "+cfg.APIKey)
+
+	responders := make([]opsgenieResponder, 0, len(cfg.Responders))
+	for _, responder := range cfg.Responders {
+		responders = append(responders, parseOpsgenieResponder(responder))
+	}
+
+	return &OpsgenieNotifier{
+		HTTPNotifier: httpNotifier,
+		responders:   responders,
+		tags:         append([]string{"argazer"}, cfg.Tags...),
+		priorities:   cfg.Priorities,
+	}
+}
+
+// Send creates an alert with the default priority (implements Notifier interface)
+func (n *OpsgenieNotifier) Send(ctx context.Context, subject, message string) error {
+	return n.SendMessage(ctx, subject, FormattedMessage{Text: message})
+}
+
+// SendMessage creates an alert prioritized by the highest severity of its updates (implements MessageNotifier)
+// The alias is derived from the updated applications and versions, so later scans reporting the same
+// updates are deduplicated by Opsgenie instead of opening new alerts.
+func (n *OpsgenieNotifier) SendMessage(ctx context.Context, subject string, message FormattedMessage) error {
+	alert := opsgenieAlert{
+		Message:     truncateText(subject, opsgenieMaxMessage),
+		Description: truncateText(message.Text, opsgenieMaxDescription),
+		Responders:  n.responders,
+		Tags:        n.tags,
+		Source:      "argazer",
+		Priority:    n.priority(message.Severity),
+	}
+
+	if len(message.Updates) > 0 {
+		alert.Alias = opsgenieAlias(message.Updates)
+		alert.Details = make(map[string]string, len(message.Updates))
+		for _, update := range message.Updates {
+			name := update.AppName
+			if update.Instance != "" {
+				name = update.Instance + "/" + name
+			}
+			alert.Details[name] = fmt.Sprintf("%s %s -> %s", update.ChartName, update.CurrentVersion, update.LatestVersion)
+		}
+	}
+
+	n.logger.WithFields(logrus.Fields{
+		"alias":    alert.Alias,
+		"priority": alert.Priority,
+	}).Debug("Creating Opsgenie alert")
+
+	if err := n.SendJSON(ctx, alert); err != nil {
+		return err
+	}
+
+	n.logger.Info("Successfully created Opsgenie alert")
+	return nil
+}
+
+// priority returns the alert priority for a severity
+func (n *OpsgenieNotifier) priority(severity string) string {
+	if priority := n.priorities[severity]; priority != "" {
+		return priority
+	}
+	return OpsgenieDefaultPriority
+}
+
+// parseOpsgenieResponder parses a "<type>:<name>" responder; a name without a type is a team
+func parseOpsgenieResponder(responder string) opsgenieResponder {
+	kind, name, ok := strings.Cut(responder, ":")
+	if !ok {
+		return opsgenieResponder{Type: "team", Name: responder}
+	}
+	if kind == "user" {
+		return opsgenieResponder{Type: kind, Username: name}
+	}
+	return opsgenieResponder{Type: kind, Name: name}
+}
+
+// opsgenieAlias identifies a set of updates by application, chart and latest version
+func opsgenieAlias(updates []ApplicationUpdate) string {
+	keys := make([]string, 0, len(updates))
+	for _, update := range updates {
+		key := update.Namespace + "/" + update.AppName + "/" + update.ChartName + "@" + update.LatestVersion
+		// Applications of different ArgoCD instances are different applications
+		if update.Instance != "" {
+			key = update.Instance + "/" + key
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return "argazer-" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,88 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpsgenieNotifier_SendMessage(t *testing.T) {
+	var alert opsgenieAlert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/alerts", r.URL.Path)
+		assert.Equal(t, "GenieKey secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := NewOpsgenieNotifier(OpsgenieConfig{
+		APIURL:     server.URL + "/",
+		APIKey:     "secret",
+		Responders: []string{"platform", "user:jane@example.com", "schedule:on-call"},
+		Tags:       []string{"helm"},
+		Priorities: map[string]string{SeverityMajor: "P2", SeverityMinor: "P4"},
+	}, logrus.NewEntry(logrus.New()))
+
+	updates := []ApplicationUpdate{
+		{AppName: "app1", Namespace: "argocd", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0"},
+		{AppName: "app2", Namespace: "argocd", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+	}
+	var _ MessageNotifier = notifier
+	err := notifier.SendMessage(context.Background(), "2 updates", FormattedMessage{Text: "details", Updates: updates, Severity: SeverityMajor})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2 updates", alert.Message)
+	assert.Equal(t, "details", alert.Description)
+	assert.Equal(t, "P2", alert.Priority)
+	assert.Equal(t, "argazer", alert.Source)
+	assert.Equal(t, []string{"argazer", "helm"}, alert.Tags)
+	assert.Equal(t, []opsgenieResponder{
+		{Type: "team", Name: "platform"},
+		{Type: "user", Username: "jane@example.com"},
+		{Type: "schedule", Name: "on-call"},
+	}, alert.Responders)
+	assert.Equal(t, map[string]string{"app1": "nginx 1.0.0 -> 2.0.0", "app2": "redis 1.0.0 -> 1.1.0"}, alert.Details)
+
+	// The same updates in another order keep the alias, a new version changes it
+	assert.Equal(t, alert.Alias, opsgenieAlias([]ApplicationUpdate{updates[1], updates[0]}))
+	newer := updates[0]
+	newer.LatestVersion = "2.1.0"
+	assert.NotEqual(t, alert.Alias, opsgenieAlias([]ApplicationUpdate{newer, updates[1]}))
+}
+
+func TestOpsgenieNotifier_Send(t *testing.T) {
+	var alert opsgenieAlert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := NewOpsgenieNotifier(OpsgenieConfig{APIURL: server.URL, APIKey: "secret"}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, notifier.Send(context.Background(), strings.Repeat("s", 200), "Message"))
+
+	assert.Len(t, []rune(alert.Message), opsgenieMaxMessage)
+	assert.Equal(t, OpsgenieDefaultPriority, alert.Priority)
+	assert.Empty(t, alert.Alias)
+	assert.Empty(t, alert.Responders)
+}
+
+func TestOpsgenieNotifier_Send_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	notifier := NewOpsgenieNotifier(OpsgenieConfig{APIURL: server.URL, APIKey: "wrong"}, logrus.NewEntry(logrus.New()))
+	err := notifier.Send(context.Background(), "Subject", "Message")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+}
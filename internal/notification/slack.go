@@ -2,20 +2,123 @@ package notification
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
 
+// Block Kit limits enforced by Slack
+const (
+	slackMaxBlocks          = 50   // Blocks per message
+	slackMaxSectionText     = 3000 // Characters per section text
+	slackMaxActionsElements = 25   // Elements per actions block
+	slackMaxHeaderText      = 150  // Characters per header block
+	slackMaxFields          = 10   // Fields per section block
+	slackMaxFieldText       = 2000 // Characters per section field
+)
+
+// slackSeverityEmojis mark each update with a colored circle by severity
+// Block Kit sections have no color, unlike Teams and Discord cards.
+var slackSeverityEmojis = map[string]string{
+	SeveritySecurity: ":rotating_light:",
+	SeverityMajor:    ":red_circle:",
+	SeverityMinor:    ":large_yellow_circle:",
+	SeverityPatch:    ":large_green_circle:",
+}
+
 // slackPayload represents the JSON payload for Slack webhooks
 type slackPayload struct {
+	Text      string       `json:"text"`
+	Blocks    []slackBlock `json:"blocks,omitempty"`
+	Username  string       `json:"username,omitempty"`
+	IconEmoji string       `json:"icon_emoji,omitempty"`
+	IconURL   string       `json:"icon_url,omitempty"`
+}
+
+// slackBlock represents a Block Kit header, section, actions or context block
+type slackBlock struct {
+	Type      string         `json:"type"`
+	Text      *slackText     `json:"text,omitempty"`
+	Fields    []slackText    `json:"fields,omitempty"`
+	Accessory *slackElement  `json:"accessory,omitempty"`
+	Elements  []slackElement `json:"elements,omitempty"`
+	Context   []slackText    `json:"-"` // Elements of a context block, which are text objects rather than buttons
+}
+
+// MarshalJSON encodes context blocks with their text elements
+func (b slackBlock) MarshalJSON() ([]byte, error) {
+	type block slackBlock // Without the MarshalJSON method
+	if b.Type != "context" {
+		return json.Marshal(block(b))
+	}
+	return json.Marshal(struct {
+		Type     string      `json:"type"`
+		Elements []slackText `json:"elements"`
+	}{Type: b.Type, Elements: b.Context})
+}
+
+// slackText represents a Block Kit text object
+type slackText struct {
+	Type string `json:"type"`
 	Text string `json:"text"`
 }
 
+// slackElement represents a Block Kit button
+type slackElement struct {
+	Type     string     `json:"type"`
+	Text     *slackText `json:"text"`
+	ActionID string     `json:"action_id,omitempty"`
+	Value    string     `json:"value,omitempty"`
+	URL      string     `json:"url,omitempty"`
+}
+
+// slackResponse represents a message posted to an interaction's response_url
+type slackResponse struct {
+	ResponseType    string `json:"response_type"`
+	ReplaceOriginal bool   `json:"replace_original"`
+	Text            string `json:"text"`
+}
+
+// SlackInteraction represents the fields of a Slack block_actions payload used by argazer
+type SlackInteraction struct {
+	Type        string              `json:"type"`
+	User        SlackUser           `json:"user"`
+	Actions     []SlackActionResult `json:"actions"`
+	ResponseURL string              `json:"response_url"`
+}
+
+// SlackActionResult represents a pressed button in an interaction payload
+type SlackActionResult struct {
+	ActionID string `json:"action_id"`
+	Value    string `json:"value"`
+}
+
+// SlackUser represents the Slack user who pressed a button
+type SlackUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// DisplayName returns the username, falling back to the name or user ID
+func (u SlackUser) DisplayName() string {
+	switch {
+	case u.Username != "":
+		return "@" + u.Username
+	case u.Name != "":
+		return u.Name
+	default:
+		return u.ID
+	}
+}
+
 // SlackNotifier handles sending notifications via Slack
 type SlackNotifier struct {
 	*HTTPNotifier
+	style Style
 }
 
 // NewSlackNotifier creates a new Slack notifier with an optional HTTP client
@@ -30,16 +133,82 @@ func NewSlackNotifierWithClient(webhookURL string, httpClient *http.Client, logg
 	}
 }
 
+// SetStyle sets the sender name and icon
+// Slack only honors them for legacy incoming webhooks; app webhooks always post as the app.
+func (n *SlackNotifier) SetStyle(style Style) {
+	n.style = style
+}
+
 // Send sends a notification via Slack (implements Notifier interface)
 func (n *SlackNotifier) Send(ctx context.Context, subject, message string) error {
+	return n.SendWithActions(ctx, subject, message, nil)
+}
+
+// SendMessage sends a notification as Block Kit blocks: a header, one section per application marked
+// by severity with its details as fields and a button to the application in ArgoCD, and the scan
+// totals as context
+// (implements MessageNotifier). The plain text is kept as the fallback for clients and notifications
+// that don't render blocks. Messages exceeding Slack's block limit are sent as several messages.
+func (n *SlackNotifier) SendMessage(ctx context.Context, subject string, message FormattedMessage) error {
+	if len(message.Sections) == 0 {
+		return n.Send(ctx, subject, message.Text)
+	}
+
+	sections := make([]slackBlock, 0, len(message.Sections))
+	for i, section := range message.Sections {
+		sections = append(sections, slackUpdateBlock(section, message.Updates[i]))
+	}
+
+	// Every message starts with the header, the last one ends with the context
+	perMessage := slackMaxBlocks - 2
+	for start := 0; start < len(sections); start += perMessage {
+		end := min(start+perMessage, len(sections))
+
+		blocks := []slackBlock{{Type: "header", Text: &slackText{Type: "plain_text", Text: truncateText(subject, slackMaxHeaderText)}}}
+		blocks = append(blocks, sections[start:end]...)
+		if end == len(sections) && message.Summary != "" {
+			blocks = append(blocks, slackBlock{Type: "context", Context: []slackText{{Type: "mrkdwn", Text: slackEscape(message.Summary)}}})
+		}
+
+		fallback := fmt.Sprintf("*%s*", subject)
+		if start == 0 {
+			fallback = fmt.Sprintf("*%s*\n\n%s", subject, message.Text)
+		}
+		if err := n.send(ctx, fallback, blocks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendWithActions sends a notification with Block Kit buttons (implements InteractiveNotifier)
+// Button presses are delivered to the Slack app's interactivity request URL
+func (n *SlackNotifier) SendWithActions(ctx context.Context, subject, message string, actions [][]Action) error {
 	// Combine subject and message for Slack with markdown formatting
 	fullMessage := message
 	if subject != "" {
 		fullMessage = fmt.Sprintf("*%s*\n\n%s", subject, message)
 	}
 
+	var blocks []slackBlock
+	if len(actions) > 0 {
+		blocks = slackBlocks(fullMessage, actions)
+	}
+
+	n.logger.WithField("actions", len(actions)).Debug("Sending Slack notification")
+
+	return n.send(ctx, fullMessage, blocks)
+}
+
+// send posts a message with the configured sender
+// With blocks, text is only used as the fallback for notifications.
+func (n *SlackNotifier) send(ctx context.Context, text string, blocks []slackBlock) error {
 	payload := slackPayload{
-		Text: fullMessage,
+		Text:      text,
+		Blocks:    blocks,
+		Username:  n.style.SenderName,
+		IconEmoji: n.style.IconEmoji,
+		IconURL:   n.style.IconURL,
 	}
 
 	if err := n.SendJSON(ctx, payload); err != nil {
@@ -49,3 +218,129 @@ func (n *SlackNotifier) Send(ctx context.Context, subject, message string) error
 	n.logger.Info("Successfully sent Slack notification")
 	return nil
 }
+
+// Respond posts an ephemeral reply to an interaction's response_url
+func (n *SlackNotifier) Respond(ctx context.Context, responseURL, text string) error {
+	payload := slackResponse{
+		ResponseType:    "ephemeral",
+		ReplaceOriginal: false,
+		Text:            text,
+	}
+
+	return n.SendJSONTo(ctx, responseURL, payload)
+}
+
+// slackBlocks renders the message as section blocks followed by one actions block per row of buttons
+// Rows are packed into fewer actions blocks when a message would exceed Slack's block limit
+func slackBlocks(message string, actions [][]Action) []slackBlock {
+	var blocks []slackBlock
+	for _, chunk := range splitSlackText(message, slackMaxSectionText) {
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: chunk},
+		})
+	}
+
+	available := slackMaxBlocks - len(blocks)
+	if available < 1 {
+		available = 1
+	}
+	rowWidth := 1
+	for _, row := range actions {
+		rowWidth = max(rowWidth, len(row))
+	}
+	rowsPerBlock := (len(actions) + available - 1) / available
+	if maxRows := max(slackMaxActionsElements/rowWidth, 1); rowsPerBlock > maxRows {
+		rowsPerBlock = maxRows
+	}
+
+	for start := 0; start < len(actions); start += rowsPerBlock {
+		end := start + rowsPerBlock
+		if end > len(actions) {
+			end = len(actions)
+		}
+
+		var elements []slackElement
+		for _, row := range actions[start:end] {
+			for _, action := range row {
+				elements = append(elements, slackElement{
+					Type:     "button",
+					Text:     &slackText{Type: "plain_text", Text: action.Label},
+					ActionID: action.Data, // Must be unique within the message, link buttons have none
+					Value:    action.Data,
+					URL:      action.URL,
+				})
+			}
+		}
+		blocks = append(blocks, slackBlock{Type: "actions", Elements: elements})
+	}
+
+	return blocks
+}
+
+// slackUpdateBlock turns the formatted text of an update into a section block: the first line
+// (application and project) in bold after the severity circle, each "Label: value" detail as a field,
+// and a button opening the application in ArgoCD in place of the link detail
+func slackUpdateBlock(section string, update ApplicationUpdate) slackBlock {
+	name, details := splitSection(section)
+	text := "*" + slackEscape(name) + "*"
+	if emoji := slackSeverityEmojis[UpdateSeverity(update)]; emoji != "" {
+		text = emoji + " " + text
+	}
+	block := slackBlock{
+		Type: "section",
+		Text: &slackText{Type: "mrkdwn", Text: truncateText(text, slackMaxSectionText)},
+	}
+
+	appURL := update.URL
+
+	for _, detail := range details {
+		if appURL != "" && strings.HasSuffix(detail, ": "+appURL) {
+			continue
+		}
+		if len(block.Fields) == slackMaxFields {
+			break
+		}
+		text := slackEscape(detail)
+		if label, value, ok := strings.Cut(detail, ": "); ok {
+			text = fmt.Sprintf("*%s*\n%s", slackEscape(label), slackEscape(value))
+		}
+		block.Fields = append(block.Fields, slackText{Type: "mrkdwn", Text: truncateText(text, slackMaxFieldText)})
+	}
+
+	if appURL != "" {
+		block.Accessory = &slackElement{
+			Type: "button",
+			Text: &slackText{Type: "plain_text", Text: openInArgoCDLabel},
+			URL:  appURL,
+		}
+	}
+	return block
+}
+
+// slackEscape escapes the characters Slack treats as control sequences in mrkdwn text
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// splitSlackText splits text at line boundaries into chunks of at most limit characters
+func splitSlackText(text string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if current.Len()+len(line) > limit && current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		// A single line longer than the limit is cut
+		for len(line) > limit {
+			chunks = append(chunks, line[:limit])
+			line = line[limit:]
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
@@ -3,8 +3,10 @@ package notification
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -64,6 +66,28 @@ func TestSlackNotifier_Send_WithSubject(t *testing.T) {
 	assert.Contains(t, receivedMessage, "Message")
 }
 
+func TestSlackNotifier_Send_WithStyle(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, logrus.NewEntry(logrus.New()))
+
+	// Sender fields are omitted unless configured
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+	assert.NotContains(t, payload, "username")
+	assert.NotContains(t, payload, "icon_emoji")
+
+	notifier.SetStyle(Style{SenderName: "Chart Bot", IconEmoji: ":package:", IconURL: "https://example.com/icon.png"})
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+	assert.Equal(t, "Chart Bot", payload["username"])
+	assert.Equal(t, ":package:", payload["icon_emoji"])
+	assert.Equal(t, "https://example.com/icon.png", payload["icon_url"])
+}
+
 func TestSlackNotifier_Send_EmptySubject(t *testing.T) {
 	receivedMessage := ""
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -115,3 +139,222 @@ func TestSlackNotifier_Send_ContextCancelled(t *testing.T) {
 	err := notifier.Send(ctx, "Test", "Message")
 	require.Error(t, err)
 }
+
+func TestSlackNotifier_SendWithActions(t *testing.T) {
+	var payload slackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewSlackNotifier(server.URL, logger)
+
+	actions := [][]Action{
+		{{Label: "Ack app1", Data: "ack:abc"}, {Label: "Snooze 30d", Data: "snooze:abc"}},
+		{{Label: "Ack app2", Data: "ack:def"}, {Label: "Snooze 30d", Data: "snooze:def"}},
+	}
+	err := notifier.SendWithActions(context.Background(), "Subject", "Message", actions)
+	require.NoError(t, err)
+
+	assert.Equal(t, "*Subject*\n\nMessage", payload.Text)
+	require.Len(t, payload.Blocks, 3)
+	assert.Equal(t, "section", payload.Blocks[0].Type)
+	assert.Equal(t, "mrkdwn", payload.Blocks[0].Text.Type)
+	assert.Equal(t, "actions", payload.Blocks[1].Type)
+	require.Len(t, payload.Blocks[1].Elements, 2)
+	assert.Equal(t, "Ack app1", payload.Blocks[1].Elements[0].Text.Text)
+	assert.Equal(t, "snooze:abc", payload.Blocks[1].Elements[1].Value)
+}
+
+func TestSlackNotifier_SendWithActions_LinkButton(t *testing.T) {
+	var raw map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewSlackNotifier(server.URL, logger)
+
+	actions := [][]Action{
+		{{Label: "Ack app1", Data: "ack:abc"}, {Label: "Open in ArgoCD", URL: "https://argocd.example.com/applications/argocd/app1"}},
+	}
+	require.NoError(t, notifier.SendWithActions(context.Background(), "Subject", "Message", actions))
+
+	blocks := raw["blocks"].([]interface{})
+	elements := blocks[1].(map[string]interface{})["elements"].([]interface{})
+	link := elements[1].(map[string]interface{})
+	assert.Equal(t, "https://argocd.example.com/applications/argocd/app1", link["url"])
+	assert.NotContains(t, link, "action_id")
+	assert.NotContains(t, link, "value")
+}
+
+func TestSlackBlocks_WideRows(t *testing.T) {
+	// Rows of three buttons must not exceed the elements limit of an actions block
+	var actions [][]Action
+	for i := 0; i < 40; i++ {
+		actions = append(actions, []Action{{Label: "Ack", Data: "ack"}, {Label: "Snooze", Data: "snooze"}, {Label: "Open", URL: "https://example.com"}})
+	}
+
+	for _, block := range slackBlocks("Message", actions) {
+		assert.LessOrEqual(t, len(block.Elements), slackMaxActionsElements)
+	}
+}
+
+func TestSlackNotifier_Send_NoBlocks(t *testing.T) {
+	var raw map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewSlackNotifier(server.URL, logger)
+
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+	assert.NotContains(t, raw, "blocks")
+}
+
+func TestSlackNotifier_Respond(t *testing.T) {
+	var payload slackResponse
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewSlackNotifier("https://hooks.slack.com/services/TEST", logger)
+
+	require.NoError(t, notifier.Respond(context.Background(), server.URL, "Acknowledged"))
+	assert.Equal(t, "ephemeral", payload.ResponseType)
+	assert.False(t, payload.ReplaceOriginal)
+	assert.Equal(t, "Acknowledged", payload.Text)
+}
+
+func TestSlackBlocks_RespectsLimits(t *testing.T) {
+	var actions [][]Action
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("%d", i)
+		actions = append(actions, []Action{{Label: "Ack", Data: "ack:" + id}, {Label: "Snooze 30d", Data: "snooze:" + id}})
+	}
+	message := strings.Repeat("line of text\n", 500)
+
+	blocks := slackBlocks(message, actions)
+
+	assert.LessOrEqual(t, len(blocks), slackMaxBlocks)
+	buttons := 0
+	for _, block := range blocks {
+		if block.Type == "section" {
+			assert.LessOrEqual(t, len(block.Text.Text), slackMaxSectionText)
+		}
+		assert.LessOrEqual(t, len(block.Elements), slackMaxActionsElements)
+		buttons += len(block.Elements)
+	}
+	assert.Equal(t, 200, buttons)
+}
+
+func TestSplitSlackText(t *testing.T) {
+	assert.Equal(t, []string{"short"}, splitSlackText("short", 10))
+	assert.Equal(t, []string{"aaaa\n", "bbbb\n", "cc"}, splitSlackText("aaaa\nbbbb\ncc", 6))
+	assert.Equal(t, []string{"abcde", "fgh"}, splitSlackText("abcdefgh", 5))
+}
+
+func TestSlackUser_DisplayName(t *testing.T) {
+	assert.Equal(t, "@alice", SlackUser{ID: "U1", Username: "alice", Name: "Alice"}.DisplayName())
+	assert.Equal(t, "Alice", SlackUser{ID: "U1", Name: "Alice"}.DisplayName())
+	assert.Equal(t, "U1", SlackUser{ID: "U1"}.DisplayName())
+}
+
+func TestSlackNotifier_SendMessage(t *testing.T) {
+	var payloads []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads = append(payloads, payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	messages := NewMessageFormatter().FormatMessageGroups([]ApplicationUpdate{
+		{AppName: "app1", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", RepoURL: "https://charts.example.com", URL: "https://argocd.example.com/applications/argocd/app1"},
+		{AppName: "app2", Project: "<team>", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.0.1", RepoURL: "https://charts.example.com"},
+	})
+	require.Len(t, messages, 1)
+	messages[0].Summary = "Total applications checked: 3"
+
+	var _ MessageNotifier = notifier
+	require.NoError(t, notifier.SendMessage(context.Background(), "2 updates", messages[0]))
+
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "*2 updates*\n\n"+messages[0].Text, payloads[0]["text"])
+
+	blocks := payloads[0]["blocks"].([]any)
+	require.Len(t, blocks, 4)
+	header := blocks[0].(map[string]any)
+	assert.Equal(t, "header", header["type"])
+	assert.Equal(t, "2 updates", header["text"].(map[string]any)["text"])
+
+	first := blocks[1].(map[string]any)
+	assert.Equal(t, ":red_circle: *app1 (default)*", first["text"].(map[string]any)["text"])
+	fields := first["fields"].([]any)
+	require.Len(t, fields, 3) // The link is a button
+	assert.Equal(t, "*Chart*\nnginx", fields[0].(map[string]any)["text"])
+	assert.Equal(t, "*Version*\n1.0.0 -&gt; 2.0.0", fields[1].(map[string]any)["text"])
+	accessory := first["accessory"].(map[string]any)
+	assert.Equal(t, "button", accessory["type"])
+	assert.Equal(t, "https://argocd.example.com/applications/argocd/app1", accessory["url"])
+
+	second := blocks[2].(map[string]any)
+	assert.Equal(t, ":large_green_circle: *app2 (&lt;team&gt;)*", second["text"].(map[string]any)["text"])
+	assert.Nil(t, second["accessory"])
+
+	footer := blocks[3].(map[string]any)
+	assert.Equal(t, "context", footer["type"])
+	assert.Equal(t, []any{map[string]any{"type": "mrkdwn", "text": "Total applications checked: 3"}}, footer["elements"])
+}
+
+func TestSlackNotifier_SendMessage_BlockLimit(t *testing.T) {
+	var payloads []slackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Text   string `json:"text"`
+			Blocks []struct {
+				Type string `json:"type"`
+			} `json:"blocks"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		blocks := make([]slackBlock, 0, len(payload.Blocks))
+		for _, block := range payload.Blocks {
+			blocks = append(blocks, slackBlock{Type: block.Type})
+		}
+		payloads = append(payloads, slackPayload{Text: payload.Text, Blocks: blocks})
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var updates []ApplicationUpdate
+	var sections []string
+	for i := 0; i < 60; i++ {
+		updates = append(updates, ApplicationUpdate{AppName: fmt.Sprintf("app%d", i)})
+		sections = append(sections, fmt.Sprintf("app%d (default)\n  Chart: nginx\n", i))
+	}
+
+	notifier := NewSlackNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	err := notifier.SendMessage(context.Background(), "60 updates", FormattedMessage{Text: "text", Updates: updates, Sections: sections, Summary: "summary"})
+	require.NoError(t, err)
+
+	require.Len(t, payloads, 2)
+	assert.Len(t, payloads[0].Blocks, slackMaxBlocks-1)
+	assert.Equal(t, "header", payloads[1].Blocks[0].Type)
+	assert.Equal(t, "context", payloads[1].Blocks[len(payloads[1].Blocks)-1].Type)
+	assert.Equal(t, "*60 updates*", payloads[1].Text)
+	for _, payload := range payloads {
+		assert.LessOrEqual(t, len(payload.Blocks), slackMaxBlocks)
+	}
+}
@@ -0,0 +1,87 @@
+package notification
+
+import "github.com/Masterminds/semver/v3"
+
+// Update severities, derived from the semver component that changed, or security for updates
+// fixing known vulnerabilities of the chart's images
+const (
+	SeveritySecurity = "security"
+	SeverityMajor    = "major"
+	SeverityMinor    = "minor"
+	SeverityPatch    = "patch"
+)
+
+// DefaultThemeColor is the Teams card color used when no severity color applies
+const DefaultThemeColor = "0078D7"
+
+// severityRank orders severities so the highest one of a message can be picked
+var severityRank = map[string]int{
+	SeverityPatch:    1,
+	SeverityMinor:    2,
+	SeverityMajor:    3,
+	SeveritySecurity: 4,
+}
+
+// UpdateSeverity returns the severity of an update, or an empty string if either version isn't semver
+// Updates fixing vulnerabilities are security updates whatever their versions.
+func UpdateSeverity(update ApplicationUpdate) string {
+	if update.FixedVulnerabilities > 0 {
+		return SeveritySecurity
+	}
+	current, err := semver.NewVersion(update.CurrentVersion)
+	if err != nil {
+		return ""
+	}
+	latest, err := semver.NewVersion(update.LatestVersion)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case latest.Major() != current.Major():
+		return SeverityMajor
+	case latest.Minor() != current.Minor():
+		return SeverityMinor
+	default:
+		return SeverityPatch
+	}
+}
+
+// HighestSeverity returns the highest severity among the updates
+func HighestSeverity(updates []ApplicationUpdate) string {
+	highest := ""
+	for _, update := range updates {
+		if severity := UpdateSeverity(update); severityRank[severity] > severityRank[highest] {
+			highest = severity
+		}
+	}
+	return highest
+}
+
+// SeverityAtLeast reports whether a severity is at or above the threshold severity
+// An unknown severity is below every threshold.
+func SeverityAtLeast(severity, threshold string) bool {
+	return severityRank[severity] > 0 && severityRank[severity] >= severityRank[threshold]
+}
+
+// Style customizes the look of notifications
+// Empty fields keep the platform defaults.
+type Style struct {
+	Emojis     map[string]string // Emoji shown before each update, by severity
+	Colors     map[string]string // Hex card color (e.g. "D70000") by severity, for platforms with colored cards
+	ThemeColor string            // Hex card color when no severity color applies
+	SenderName string            // Sender name, for platforms that allow overriding it
+	IconEmoji  string            // Sender icon as an emoji code (e.g. ":package:")
+	IconURL    string            // Sender avatar image URL
+}
+
+// Color returns the card color for a severity, falling back to the theme color
+func (s Style) Color(severity string) string {
+	if color := s.Colors[severity]; color != "" {
+		return color
+	}
+	if s.ThemeColor != "" {
+		return s.ThemeColor
+	}
+	return DefaultThemeColor
+}
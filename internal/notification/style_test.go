@@ -0,0 +1,64 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateSeverity(t *testing.T) {
+	tests := []struct {
+		current  string
+		latest   string
+		expected string
+	}{
+		{"1.2.3", "2.0.0", SeverityMajor},
+		{"1.2.3", "1.3.0", SeverityMinor},
+		{"1.2.3", "1.2.4", SeverityPatch},
+		{"v1.2.3", "v1.2.4", SeverityPatch},
+		{"1.2.3", "1.2.4-rc.1", SeverityPatch},
+		{"latest", "1.2.4", ""},
+		{"1.2.3", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+"->"+tt.latest, func(t *testing.T) {
+			assert.Equal(t, tt.expected, UpdateSeverity(ApplicationUpdate{CurrentVersion: tt.current, LatestVersion: tt.latest}))
+		})
+	}
+}
+
+func TestHighestSeverity(t *testing.T) {
+	patch := ApplicationUpdate{CurrentVersion: "1.0.0", LatestVersion: "1.0.1"}
+	minor := ApplicationUpdate{CurrentVersion: "1.0.0", LatestVersion: "1.1.0"}
+	major := ApplicationUpdate{CurrentVersion: "1.0.0", LatestVersion: "2.0.0"}
+	unknown := ApplicationUpdate{CurrentVersion: "main", LatestVersion: "1.0.0"}
+
+	assert.Equal(t, SeverityMinor, HighestSeverity([]ApplicationUpdate{patch, minor, unknown}))
+	assert.Equal(t, SeverityMajor, HighestSeverity([]ApplicationUpdate{patch, major, minor}))
+	assert.Equal(t, "", HighestSeverity([]ApplicationUpdate{unknown}))
+	assert.Equal(t, "", HighestSeverity(nil))
+
+	security := ApplicationUpdate{CurrentVersion: "1.0.0", LatestVersion: "1.0.1", FixedVulnerabilities: 2}
+	assert.Equal(t, SeveritySecurity, UpdateSeverity(security))
+	assert.Equal(t, SeveritySecurity, HighestSeverity([]ApplicationUpdate{major, security}))
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	assert.True(t, SeverityAtLeast(SeveritySecurity, SeverityMajor))
+	assert.True(t, SeverityAtLeast(SeverityMajor, SeverityMinor))
+	assert.True(t, SeverityAtLeast(SeverityMinor, SeverityMinor))
+	assert.True(t, SeverityAtLeast(SeverityPatch, SeverityPatch))
+	assert.False(t, SeverityAtLeast(SeverityPatch, SeverityMinor))
+	assert.False(t, SeverityAtLeast(SeverityMinor, SeverityMajor))
+	assert.False(t, SeverityAtLeast("", SeverityPatch))
+}
+
+func TestStyle_Color(t *testing.T) {
+	assert.Equal(t, DefaultThemeColor, Style{}.Color(SeverityMajor))
+
+	style := Style{Colors: map[string]string{SeverityMajor: "D70000"}, ThemeColor: "6264A7"}
+	assert.Equal(t, "D70000", style.Color(SeverityMajor))
+	assert.Equal(t, "6264A7", style.Color(SeverityMinor))
+	assert.Equal(t, "6264A7", style.Color(""))
+}
@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"argazer/internal/syslog"
+
+	"github.com/sirupsen/logrus"
+)
+
+// syslogSDID is the structured data element of argazer events
+// 32473 is the private enterprise number reserved for documentation (RFC 5612).
+const syslogSDID = "argazer@32473"
+
+// syslogWriter sends syslog messages
+type syslogWriter interface {
+	Write(ctx context.Context, messages []syslog.Message) error
+}
+
+// SyslogNotifier emits one RFC 5424 message per update to a syslog endpoint
+type SyslogNotifier struct {
+	writer syslogWriter
+	logger *logrus.Entry
+}
+
+// NewSyslogNotifier creates a new syslog notifier
+func NewSyslogNotifier(cfg syslog.Config, logger *logrus.Entry) (*SyslogNotifier, error) {
+	writer, err := syslog.NewWriter(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create syslog writer: %w", err)
+	}
+	return NewSyslogNotifierWithWriter(writer, logger), nil
+}
+
+// NewSyslogNotifierWithWriter creates a new syslog notifier with a custom writer
+func NewSyslogNotifierWithWriter(writer syslogWriter, logger *logrus.Entry) *SyslogNotifier {
+	return &SyslogNotifier{
+		writer: writer,
+		logger: logger,
+	}
+}
+
+// Send emits a plain notification message (implements Notifier interface)
+func (n *SyslogNotifier) Send(ctx context.Context, subject, message string) error {
+	msg := syslog.Message{
+		MsgID:   EventNotification,
+		SDID:    syslogSDID,
+		Params:  []syslog.Param{{Name: "subject", Value: subject}},
+		Text:    message,
+		Created: time.Now(),
+	}
+
+	if err := n.writer.Write(ctx, []syslog.Message{msg}); err != nil {
+		return fmt.Errorf("failed to send syslog notification: %w", err)
+	}
+
+	n.logger.Info("Successfully sent syslog notification")
+	return nil
+}
+
+// SendUpdates emits one message per update with its details as structured data (implements EventNotifier)
+func (n *SyslogNotifier) SendUpdates(ctx context.Context, updates []ApplicationUpdate) error {
+	now := time.Now()
+	messages := make([]syslog.Message, 0, len(updates))
+	for _, update := range updates {
+		messages = append(messages, syslogUpdateMessage(update, now))
+	}
+
+	if err := n.writer.Write(ctx, messages); err != nil {
+		return fmt.Errorf("failed to send syslog update events: %w", err)
+	}
+
+	n.logger.WithField("events", len(messages)).Info("Successfully sent syslog update events")
+	return nil
+}
+
+// syslogUpdateMessage builds the message for an application update
+func syslogUpdateMessage(update ApplicationUpdate, created time.Time) syslog.Message {
+	params := []syslog.Param{
+		{Name: "app", Value: update.AppName},
+		{Name: "project", Value: update.Project},
+		{Name: "chart", Value: update.ChartName},
+		{Name: "current_version", Value: update.CurrentVersion},
+		{Name: "latest_version", Value: update.LatestVersion},
+		{Name: "repo_url", Value: update.RepoURL},
+	}
+	if update.Namespace != "" {
+		params = append(params, syslog.Param{Name: "namespace", Value: update.Namespace})
+	}
+	if update.Instance != "" {
+		params = append(params, syslog.Param{Name: "instance", Value: update.Instance})
+	}
+	if update.ConstraintApplied != "" {
+		params = append(params, syslog.Param{Name: "constraint", Value: update.ConstraintApplied})
+	}
+	if update.HasUpdateOutsideConstraint {
+		params = append(params, syslog.Param{Name: "latest_version_all", Value: update.LatestVersionAll})
+	}
+
+	return syslog.Message{
+		MsgID:   EventUpdateAvailable,
+		SDID:    syslogSDID,
+		Params:  params,
+		Text:    fmt.Sprintf("Helm chart update available for %s: %s %s -> %s", update.AppName, update.ChartName, update.CurrentVersion, update.LatestVersion),
+		Created: created,
+	}
+}
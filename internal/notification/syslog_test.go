@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"argazer/internal/syslog"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSyslogWriter records written messages
+type mockSyslogWriter struct {
+	messages []syslog.Message
+	err      error
+}
+
+func (m *mockSyslogWriter) Write(ctx context.Context, messages []syslog.Message) error {
+	m.messages = append(m.messages, messages...)
+	return m.err
+}
+
+func TestNewSyslogNotifier(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	_, err := NewSyslogNotifier(syslog.Config{Address: "udp://127.0.0.1", Facility: "local0", Severity: "notice"}, logger)
+	require.NoError(t, err)
+
+	_, err = NewSyslogNotifier(syslog.Config{Address: "udp://127.0.0.1", Facility: "local9", Severity: "notice"}, logger)
+	assert.Error(t, err)
+}
+
+func TestSyslogNotifier_SendUpdates(t *testing.T) {
+	writer := &mockSyslogWriter{}
+	notifier := NewSyslogNotifierWithWriter(writer, logrus.NewEntry(logrus.New()))
+
+	updates := []ApplicationUpdate{
+		{AppName: "app1", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", RepoURL: "https://charts.example.com"},
+		{AppName: "app2", Namespace: "team-apps", Project: "prod", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", ConstraintApplied: "minor", HasUpdateOutsideConstraint: true, LatestVersionAll: "2.0.0"},
+	}
+
+	require.NoError(t, notifier.SendUpdates(context.Background(), updates))
+
+	require.Len(t, writer.messages, 2)
+
+	msg := writer.messages[0]
+	assert.Equal(t, EventUpdateAvailable, msg.MsgID)
+	assert.Equal(t, syslogSDID, msg.SDID)
+	assert.Equal(t, "Helm chart update available for app1: nginx 1.0.0 -> 2.0.0", msg.Text)
+	assert.Equal(t, []syslog.Param{
+		{Name: "app", Value: "app1"},
+		{Name: "project", Value: "default"},
+		{Name: "chart", Value: "nginx"},
+		{Name: "current_version", Value: "1.0.0"},
+		{Name: "latest_version", Value: "2.0.0"},
+		{Name: "repo_url", Value: "https://charts.example.com"},
+	}, msg.Params)
+	assert.False(t, msg.Created.IsZero())
+
+	// Namespace and constraint details are added when present
+	assert.Contains(t, writer.messages[1].Params, syslog.Param{Name: "namespace", Value: "team-apps"})
+	assert.Contains(t, writer.messages[1].Params, syslog.Param{Name: "constraint", Value: "minor"})
+	assert.Contains(t, writer.messages[1].Params, syslog.Param{Name: "latest_version_all", Value: "2.0.0"})
+}
+
+func TestSyslogNotifier_Send(t *testing.T) {
+	writer := &mockSyslogWriter{}
+	notifier := NewSyslogNotifierWithWriter(writer, logrus.NewEntry(logrus.New()))
+
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+
+	require.Len(t, writer.messages, 1)
+	assert.Equal(t, EventNotification, writer.messages[0].MsgID)
+	assert.Equal(t, "Message", writer.messages[0].Text)
+	assert.Equal(t, []syslog.Param{{Name: "subject", Value: "Subject"}}, writer.messages[0].Params)
+}
+
+func TestSyslogNotifier_Error(t *testing.T) {
+	writer := &mockSyslogWriter{err: assert.AnError}
+	notifier := NewSyslogNotifierWithWriter(writer, logrus.NewEntry(logrus.New()))
+
+	err := notifier.SendUpdates(context.Background(), []ApplicationUpdate{{AppName: "app1"}})
+	assert.ErrorIs(t, err, assert.AnError)
+}
@@ -3,6 +3,7 @@ package notification
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
@@ -17,9 +18,75 @@ type teamsMessageCard struct {
 	Text       string `json:"text"`
 }
 
+// teamsAdaptiveMessage represents a message carrying an Adaptive Card, as posted to Workflows webhooks
+type teamsAdaptiveMessage struct {
+	Type        string                    `json:"type"`
+	Attachments []teamsAdaptiveAttachment `json:"attachments"`
+}
+
+// teamsAdaptiveAttachment represents the card attachment of a message
+type teamsAdaptiveAttachment struct {
+	ContentType string            `json:"contentType"`
+	Content     teamsAdaptiveCard `json:"content"`
+}
+
+// teamsAdaptiveCard represents an Adaptive Card
+type teamsAdaptiveCard struct {
+	Schema  string                 `json:"$schema"`
+	Type    string                 `json:"type"`
+	Version string                 `json:"version"`
+	MSTeams teamsAdaptiveMSTeams   `json:"msteams"`
+	Body    []teamsAdaptiveElement `json:"body"`
+}
+
+// teamsAdaptiveMSTeams holds the Teams-specific card options
+type teamsAdaptiveMSTeams struct {
+	Width string `json:"width"`
+}
+
+// teamsAdaptiveElement represents a card element: a Container, TextBlock, FactSet or ActionSet
+type teamsAdaptiveElement struct {
+	Type      string                 `json:"type"`
+	Style     string                 `json:"style,omitempty"`
+	Bleed     bool                   `json:"bleed,omitempty"`
+	Items     []teamsAdaptiveElement `json:"items,omitempty"`
+	Text      string                 `json:"text,omitempty"`
+	Size      string                 `json:"size,omitempty"`
+	Weight    string                 `json:"weight,omitempty"`
+	Color     string                 `json:"color,omitempty"`
+	Wrap      bool                   `json:"wrap,omitempty"`
+	Separator bool                   `json:"separator,omitempty"`
+	Facts     []teamsAdaptiveFact    `json:"facts,omitempty"`
+	Actions   []teamsAdaptiveAction  `json:"actions,omitempty"`
+}
+
+// teamsAdaptiveFact represents a title and value pair of a FactSet
+type teamsAdaptiveFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// teamsAdaptiveAction represents an Action.OpenUrl button
+type teamsAdaptiveAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// teamsAdaptiveStyles are the header container styles and application heading colors by severity
+// Adaptive Cards take named styles rather than colors, so notification_color_* don't apply.
+var teamsAdaptiveStyles = map[string]string{
+	SeveritySecurity: "attention",
+	SeverityMajor:    "attention",
+	SeverityMinor:    "warning",
+	SeverityPatch:    "good",
+}
+
 // TeamsNotifier handles sending notifications via Microsoft Teams
 type TeamsNotifier struct {
 	*HTTPNotifier
+	style    Style
+	adaptive bool
 }
 
 // NewTeamsNotifier creates a new Microsoft Teams notifier
@@ -34,18 +101,64 @@ func NewTeamsNotifierWithClient(webhookURL string, httpClient *http.Client, logg
 	}
 }
 
+// SetStyle sets the card colors
+func (n *TeamsNotifier) SetStyle(style Style) {
+	n.style = style
+}
+
+// SetAdaptiveCards switches from the deprecated MessageCard format to Adaptive Cards
+// Connector webhooks accept both formats, Workflows webhooks only Adaptive Cards.
+func (n *TeamsNotifier) SetAdaptiveCards(enabled bool) {
+	n.adaptive = enabled
+}
+
 // Send sends a notification via Microsoft Teams (implements Notifier interface)
 func (n *TeamsNotifier) Send(ctx context.Context, subject, message string) error {
+	return n.SendWithSeverity(ctx, subject, message, "")
+}
+
+// SendWithSeverity sends a notification colored by update severity (implements SeverityNotifier)
+func (n *TeamsNotifier) SendWithSeverity(ctx context.Context, subject, message, severity string) error {
+	if n.adaptive {
+		return n.send(ctx, n.adaptiveCard(subject, severity, []teamsAdaptiveElement{
+			{Type: "TextBlock", Text: message, Wrap: true},
+		}))
+	}
+
 	// Prepare the payload using MessageCard format for better compatibility
 	payload := teamsMessageCard{
 		Type:       "MessageCard",
 		Context:    "https://schema.org/extensions",
 		Summary:    subject,
-		ThemeColor: "0078D7",
+		ThemeColor: n.style.Color(severity),
 		Title:      subject,
 		Text:       message,
 	}
 
+	return n.send(ctx, payload)
+}
+
+// SendMessage sends a notification with, in Adaptive Card format, a fact set per application and a
+// button opening it in ArgoCD (implements MessageNotifier)
+// MessageCards keep the text layout.
+func (n *TeamsNotifier) SendMessage(ctx context.Context, subject string, message FormattedMessage) error {
+	if !n.adaptive || len(message.Sections) == 0 {
+		return n.SendWithSeverity(ctx, subject, message.Text, message.Severity)
+	}
+
+	var body []teamsAdaptiveElement
+	for i, section := range message.Sections {
+		body = append(body, teamsAdaptiveUpdate(section, message.Updates[i])...)
+	}
+	if message.Summary != "" {
+		body = append(body, teamsAdaptiveElement{Type: "TextBlock", Text: message.Summary, Size: "Small", Wrap: true, Separator: true})
+	}
+
+	return n.send(ctx, n.adaptiveCard(subject, message.Severity, body))
+}
+
+// send posts a card to the webhook
+func (n *TeamsNotifier) send(ctx context.Context, payload any) error {
 	if err := n.SendJSON(ctx, payload); err != nil {
 		return err
 	}
@@ -53,3 +166,60 @@ func (n *TeamsNotifier) Send(ctx context.Context, subject, message string) error
 	n.logger.Info("Successfully sent Microsoft Teams notification")
 	return nil
 }
+
+// adaptiveCard wraps body elements into an Adaptive Card message, below a header styled by severity
+func (n *TeamsNotifier) adaptiveCard(subject, severity string, body []teamsAdaptiveElement) teamsAdaptiveMessage {
+	style := teamsAdaptiveStyles[severity]
+	if style == "" {
+		style = "emphasis"
+	}
+	header := teamsAdaptiveElement{
+		Type:  "Container",
+		Style: style,
+		Bleed: true,
+		Items: []teamsAdaptiveElement{{Type: "TextBlock", Text: subject, Size: "Medium", Weight: "Bolder", Wrap: true}},
+	}
+
+	return teamsAdaptiveMessage{
+		Type: "message",
+		Attachments: []teamsAdaptiveAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: teamsAdaptiveCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				MSTeams: teamsAdaptiveMSTeams{Width: "Full"},
+				Body:    append([]teamsAdaptiveElement{header}, body...),
+			},
+		}},
+	}
+}
+
+// teamsAdaptiveUpdate turns the formatted text of an update into card elements: the first line
+// (application and project) as a heading colored by severity, each "Label: value" detail as a fact,
+// and a button opening the application in ArgoCD in place of the link detail
+func teamsAdaptiveUpdate(section string, update ApplicationUpdate) []teamsAdaptiveElement {
+	name, details := splitSection(section)
+	appURL := update.URL
+
+	var facts []teamsAdaptiveFact
+	for _, detail := range details {
+		if appURL != "" && strings.HasSuffix(detail, ": "+appURL) {
+			continue
+		}
+		label, value, _ := strings.Cut(detail, ": ")
+		facts = append(facts, teamsAdaptiveFact{Title: label, Value: value})
+	}
+
+	elements := []teamsAdaptiveElement{
+		{Type: "TextBlock", Text: name, Weight: "Bolder", Color: teamsAdaptiveStyles[UpdateSeverity(update)], Wrap: true, Separator: true},
+		{Type: "FactSet", Facts: facts},
+	}
+	if appURL != "" {
+		elements = append(elements, teamsAdaptiveElement{
+			Type:    "ActionSet",
+			Actions: []teamsAdaptiveAction{{Type: "Action.OpenUrl", Title: openInArgoCDLabel, URL: appURL}},
+		})
+	}
+	return elements
+}
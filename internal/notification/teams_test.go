@@ -119,3 +119,106 @@ func TestTeamsNotifier_Send_EmptySubject(t *testing.T) {
 	assert.Equal(t, "", receivedPayload["title"])
 	assert.Equal(t, "Message only", receivedPayload["text"])
 }
+
+func TestTeamsNotifier_SendWithSeverity(t *testing.T) {
+	var receivedPayload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&receivedPayload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewTeamsNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	notifier.SetStyle(Style{
+		Colors:     map[string]string{SeverityMajor: "D70000"},
+		ThemeColor: "6264A7",
+	})
+
+	require.NoError(t, notifier.SendWithSeverity(context.Background(), "Subject", "Message", SeverityMajor))
+	assert.Equal(t, "D70000", receivedPayload["themeColor"])
+
+	// Severities without a color use the theme color
+	require.NoError(t, notifier.SendWithSeverity(context.Background(), "Subject", "Message", SeverityPatch))
+	assert.Equal(t, "6264A7", receivedPayload["themeColor"])
+}
+
+func TestTeamsNotifier_SendMessage_AdaptiveCard(t *testing.T) {
+	var payload teamsAdaptiveMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := NewTeamsNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	notifier.SetAdaptiveCards(true)
+
+	messages := NewMessageFormatter().FormatMessageGroups([]ApplicationUpdate{
+		{AppName: "app1", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", RepoURL: "https://charts.example.com", URL: "https://argocd.example.com/applications/argocd/app1"},
+		{AppName: "app2", Project: "default", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.0.1", RepoURL: "https://charts.example.com"},
+	})
+	require.Len(t, messages, 1)
+	messages[0].Summary = "Total applications checked: 2"
+
+	var _ MessageNotifier = notifier
+	require.NoError(t, notifier.SendMessage(context.Background(), "2 updates", messages[0]))
+
+	assert.Equal(t, "message", payload.Type)
+	require.Len(t, payload.Attachments, 1)
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", payload.Attachments[0].ContentType)
+	card := payload.Attachments[0].Content
+	assert.Equal(t, "AdaptiveCard", card.Type)
+
+	// Header, then a heading, fact set and, with a link, action set per application, then the totals
+	require.Len(t, card.Body, 7)
+	assert.Equal(t, "attention", card.Body[0].Style)
+	assert.Equal(t, "2 updates", card.Body[0].Items[0].Text)
+	assert.Equal(t, "app1 (default)", card.Body[1].Text)
+	assert.Equal(t, "attention", card.Body[1].Color)
+	assert.Equal(t, []teamsAdaptiveFact{
+		{Title: "Chart", Value: "nginx"},
+		{Title: "Version", Value: "1.0.0 -> 2.0.0"},
+		{Title: "Repo", Value: "https://charts.example.com"},
+	}, card.Body[2].Facts)
+	assert.Equal(t, []teamsAdaptiveAction{{Type: "Action.OpenUrl", Title: "Open in ArgoCD", URL: "https://argocd.example.com/applications/argocd/app1"}}, card.Body[3].Actions)
+	assert.Equal(t, "app2 (default)", card.Body[4].Text)
+	assert.Equal(t, "good", card.Body[4].Color)
+	assert.Equal(t, "FactSet", card.Body[5].Type)
+	assert.Equal(t, "Total applications checked: 2", card.Body[6].Text)
+}
+
+func TestTeamsNotifier_SendMessage_MessageCard(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewTeamsNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	notifier.SetStyle(Style{Colors: map[string]string{SeverityMajor: "D70000"}})
+
+	err := notifier.SendMessage(context.Background(), "Subject", FormattedMessage{Text: "Message", Sections: []string{"Message"}, Severity: SeverityMajor})
+	require.NoError(t, err)
+	assert.Equal(t, "MessageCard", payload["@type"])
+	assert.Equal(t, "Message", payload["text"])
+	assert.Equal(t, "D70000", payload["themeColor"])
+}
+
+func TestTeamsNotifier_Send_AdaptiveCard(t *testing.T) {
+	var payload teamsAdaptiveMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := NewTeamsNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	notifier.SetAdaptiveCards(true)
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+
+	body := payload.Attachments[0].Content.Body
+	require.Len(t, body, 2)
+	assert.Equal(t, "emphasis", body[0].Style)
+	assert.Equal(t, "Message", body[1].Text)
+}
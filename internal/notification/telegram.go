@@ -4,14 +4,66 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
 
 // telegramPayload represents the JSON payload for Telegram webhooks
 type telegramPayload struct {
-	ChatID string `json:"chat_id"`
-	Text   string `json:"text"`
+	ChatID      string               `json:"chat_id"`
+	Text        string               `json:"text"`
+	ReplyMarkup *telegramReplyMarkup `json:"reply_markup,omitempty"`
+}
+
+// telegramReplyMarkup represents an inline keyboard attached to a message
+type telegramReplyMarkup struct {
+	InlineKeyboard [][]telegramInlineButton `json:"inline_keyboard"`
+}
+
+// telegramInlineButton represents a single inline keyboard button
+type telegramInlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+// telegramAnswerCallback represents the payload of the answerCallbackQuery method
+type telegramAnswerCallback struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+}
+
+// TelegramUpdate represents the fields of a Telegram webhook update used by argazer
+type TelegramUpdate struct {
+	UpdateID      int64                  `json:"update_id"`
+	CallbackQuery *TelegramCallbackQuery `json:"callback_query"`
+}
+
+// TelegramCallbackQuery represents a press on an inline keyboard button
+type TelegramCallbackQuery struct {
+	ID   string       `json:"id"`
+	From TelegramUser `json:"from"`
+	Data string       `json:"data"`
+}
+
+// TelegramUser represents the Telegram user who pressed a button
+type TelegramUser struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+}
+
+// DisplayName returns the username, falling back to the first name or numeric ID
+func (u TelegramUser) DisplayName() string {
+	switch {
+	case u.Username != "":
+		return "@" + u.Username
+	case u.FirstName != "":
+		return u.FirstName
+	default:
+		return fmt.Sprintf("%d", u.ID)
+	}
 }
 
 // TelegramNotifier handles sending notifications via Telegram
@@ -35,6 +87,11 @@ func NewTelegramNotifierWithClient(webhookURL, chatID string, httpClient *http.C
 
 // Send sends a notification via Telegram (implements Notifier interface)
 func (n *TelegramNotifier) Send(ctx context.Context, subject, message string) error {
+	return n.SendWithActions(ctx, subject, message, nil)
+}
+
+// SendWithActions sends a notification with inline keyboard buttons (implements InteractiveNotifier)
+func (n *TelegramNotifier) SendWithActions(ctx context.Context, subject, message string, actions [][]Action) error {
 	// Combine subject and message for Telegram
 	fullMessage := message
 	if subject != "" {
@@ -46,7 +103,22 @@ func (n *TelegramNotifier) Send(ctx context.Context, subject, message string) er
 		Text:   fullMessage,
 	}
 
-	n.logger.WithField("chat_id", n.chatID).Debug("Sending Telegram notification")
+	if len(actions) > 0 {
+		keyboard := make([][]telegramInlineButton, 0, len(actions))
+		for _, row := range actions {
+			buttons := make([]telegramInlineButton, 0, len(row))
+			for _, action := range row {
+				buttons = append(buttons, telegramInlineButton{Text: action.Label, CallbackData: action.Data, URL: action.URL})
+			}
+			keyboard = append(keyboard, buttons)
+		}
+		payload.ReplyMarkup = &telegramReplyMarkup{InlineKeyboard: keyboard}
+	}
+
+	n.logger.WithFields(logrus.Fields{
+		"chat_id": n.chatID,
+		"actions": len(actions),
+	}).Debug("Sending Telegram notification")
 
 	if err := n.SendJSON(ctx, payload); err != nil {
 		return err
@@ -55,3 +127,24 @@ func (n *TelegramNotifier) Send(ctx context.Context, subject, message string) er
 	n.logger.WithField("chat_id", n.chatID).Info("Successfully sent Telegram notification")
 	return nil
 }
+
+// AnswerCallback acknowledges a button press so Telegram stops showing a loading indicator
+// The text is shown to the user as a short notification
+func (n *TelegramNotifier) AnswerCallback(ctx context.Context, callbackID, text string) error {
+	payload := telegramAnswerCallback{
+		CallbackQueryID: callbackID,
+		Text:            text,
+	}
+
+	return n.SendJSONTo(ctx, n.methodURL("answerCallbackQuery"), payload)
+}
+
+// methodURL derives the URL of another Bot API method from the configured sendMessage URL
+// e.g. https://api.telegram.org/botTOKEN/sendMessage -> https://api.telegram.org/botTOKEN/answerCallbackQuery
+func (n *TelegramNotifier) methodURL(method string) string {
+	base := n.webhookURL
+	if i := strings.LastIndex(base, "/"); i >= 0 {
+		base = base[:i]
+	}
+	return base + "/" + method
+}
@@ -116,3 +116,87 @@ func TestTelegramNotifier_Send_ContextCancelled(t *testing.T) {
 	err := notifier.Send(ctx, "Test", "Message")
 	require.Error(t, err)
 }
+
+func TestTelegramNotifier_SendWithActions(t *testing.T) {
+	var payload telegramPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewTelegramNotifier(server.URL+"/bot123/sendMessage", "12345", logger)
+
+	actions := [][]Action{
+		{{Label: "Ack app1", Data: "ack:abc"}, {Label: "Snooze 30d", Data: "snooze:abc"}},
+	}
+	err := notifier.SendWithActions(context.Background(), "Subject", "Message", actions)
+	require.NoError(t, err)
+
+	require.NotNil(t, payload.ReplyMarkup)
+	require.Len(t, payload.ReplyMarkup.InlineKeyboard, 1)
+	assert.Equal(t, "Ack app1", payload.ReplyMarkup.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "snooze:abc", payload.ReplyMarkup.InlineKeyboard[0][1].CallbackData)
+}
+
+func TestTelegramNotifier_SendWithActions_LinkButton(t *testing.T) {
+	var payload telegramPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewTelegramNotifier(server.URL+"/bot123/sendMessage", "12345", logger)
+
+	actions := [][]Action{
+		{{Label: "Open in ArgoCD", URL: "https://argocd.example.com/applications/argocd/app1"}},
+	}
+	require.NoError(t, notifier.SendWithActions(context.Background(), "Subject", "Message", actions))
+
+	button := payload.ReplyMarkup.InlineKeyboard[0][0]
+	assert.Equal(t, "https://argocd.example.com/applications/argocd/app1", button.URL)
+	assert.Empty(t, button.CallbackData)
+}
+
+func TestTelegramNotifier_Send_NoReplyMarkup(t *testing.T) {
+	var raw map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewTelegramNotifier(server.URL, "12345", logger)
+
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+	assert.NotContains(t, raw, "reply_markup")
+}
+
+func TestTelegramNotifier_AnswerCallback(t *testing.T) {
+	var path string
+	var payload telegramAnswerCallback
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewTelegramNotifier(server.URL+"/bot123/sendMessage", "12345", logger)
+
+	require.NoError(t, notifier.AnswerCallback(context.Background(), "cb-1", "Acknowledged"))
+	assert.Equal(t, "/bot123/answerCallbackQuery", path)
+	assert.Equal(t, "cb-1", payload.CallbackQueryID)
+	assert.Equal(t, "Acknowledged", payload.Text)
+}
+
+func TestTelegramUser_DisplayName(t *testing.T) {
+	assert.Equal(t, "@alice", TelegramUser{ID: 1, Username: "alice", FirstName: "Alice"}.DisplayName())
+	assert.Equal(t, "Alice", TelegramUser{ID: 1, FirstName: "Alice"}.DisplayName())
+	assert.Equal(t, "42", TelegramUser{ID: 42}.DisplayName())
+}
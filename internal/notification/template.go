@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Templates renders notification subjects and bodies from user-provided text/template files
+type Templates struct {
+	subject *template.Template // Nil keeps the default subject
+	body    *template.Template // Nil keeps the default layout
+}
+
+// TemplateData is the data available to notification templates
+type TemplateData struct {
+	Subject  string           // Default subject of the message
+	Text     string           // Default text of the message
+	Updates  []TemplateUpdate // Updates in the message
+	Project  string           // ArgoCD project of all updates when grouped by project, empty otherwise
+	Part     int              // Position of the message among the messages of its project (1-based)
+	Parts    int              // Number of messages the project's updates were split into
+	Severity string           // Highest severity of the updates in the message
+	Summary  TemplateSummary  // Totals of the whole scan
+	Scan     TemplateScan     // Scan metadata
+}
+
+// TemplateUpdate is an application update along with its severity
+type TemplateUpdate struct {
+	ApplicationUpdate
+	Severity string // "major", "minor", "patch", or empty when a version isn't semver
+}
+
+// TemplateSummary holds the totals of a scan
+type TemplateSummary struct {
+	Total    int // Applications checked
+	Updates  int // Applications with updates available
+	Notified int // Updates notified, across all messages (acknowledged updates are left out)
+	UpToDate int
+	Errors   int // Applications that couldn't be checked
+	Ignored  int // Updates ignored by a rule
+}
+
+// TemplateScan holds metadata of the scan
+type TemplateScan struct {
+	Time    time.Time // Time the notification is sent
+	Channel string    // Notification channel, e.g. "slack"
+	Version string    // Argazer version
+}
+
+// templateFuncs are the functions available to notification templates besides the text/template builtins
+var templateFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"replace": strings.ReplaceAll,
+	"trim":    strings.TrimSpace,
+}
+
+// LoadTemplates parses the subject and body template files; an empty path keeps the default
+func LoadTemplates(subjectPath, bodyPath string) (*Templates, error) {
+	subject, err := loadTemplate(subjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subject template: %w", err)
+	}
+	body, err := loadTemplate(bodyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load body template: %w", err)
+	}
+	return &Templates{subject: subject, body: body}, nil
+}
+
+// loadTemplate parses a template file, returning nil for an empty path
+func loadTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(data))
+}
+
+// HasBody reports whether the message body is rendered from a template
+func (t *Templates) HasBody() bool {
+	return t.body != nil
+}
+
+// Render returns the subject and body of a message, using the defaults in data where no template is set
+// The subject is trimmed to a single line, as subjects end up in email headers and card titles.
+func (t *Templates) Render(data TemplateData) (string, string, error) {
+	subject := data.Subject
+	if t.subject != nil {
+		rendered, err := execute(t.subject, data)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to render subject template: %w", err)
+		}
+		subject = strings.Join(strings.Fields(rendered), " ")
+	}
+
+	body := data.Text
+	if t.body != nil {
+		rendered, err := execute(t.body, data)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to render body template: %w", err)
+		}
+		body = rendered
+	}
+	return subject, body, nil
+}
+
+// execute renders a template to a string
+func execute(tmpl *template.Template, data TemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// NewTemplateUpdates wraps updates with their severities for templates
+func NewTemplateUpdates(updates []ApplicationUpdate) []TemplateUpdate {
+	wrapped := make([]TemplateUpdate, 0, len(updates))
+	for _, update := range updates {
+		wrapped = append(wrapped, TemplateUpdate{ApplicationUpdate: update, Severity: UpdateSeverity(update)})
+	}
+	return wrapped
+}
@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, name, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o600))
+	return path
+}
+
+func TestTemplates_Render(t *testing.T) {
+	subject := writeTemplate(t, "subject.tmpl", "{{ upper .Severity }}:\n  {{ len .Updates }} update(s) in {{ .Project }}\n")
+	body := writeTemplate(t, "body.tmpl", `{{ range .Updates }}{{ .AppName }} {{ .CurrentVersion }} -> {{ .LatestVersion }} [{{ .Severity }}]
+{{ end }}{{ .Summary.UpToDate }} up to date, argazer {{ .Scan.Version }} at {{ .Scan.Time.Format "2006-01-02" }}`)
+
+	templates, err := LoadTemplates(subject, body)
+	require.NoError(t, err)
+	assert.True(t, templates.HasBody())
+
+	data := TemplateData{
+		Subject: "default subject",
+		Text:    "default text",
+		Updates: NewTemplateUpdates([]ApplicationUpdate{
+			{AppName: "app1", CurrentVersion: "1.0.0", LatestVersion: "2.0.0"},
+			{AppName: "app2", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+		}),
+		Project:  "team-a",
+		Severity: SeverityMajor,
+		Summary:  TemplateSummary{UpToDate: 3},
+		Scan:     TemplateScan{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Version: "1.2.3"},
+	}
+
+	renderedSubject, renderedBody, err := templates.Render(data)
+	require.NoError(t, err)
+	assert.Equal(t, "MAJOR: 2 update(s) in team-a", renderedSubject)
+	assert.Equal(t, "app1 1.0.0 -> 2.0.0 [major]\napp2 1.0.0 -> 1.1.0 [minor]\n3 up to date, argazer 1.2.3 at 2026-01-02", renderedBody)
+}
+
+func TestTemplates_Render_Defaults(t *testing.T) {
+	templates, err := LoadTemplates(writeTemplate(t, "subject.tmpl", "{{ .Subject }} ({{ .Part }}/{{ .Parts }})"), "")
+	require.NoError(t, err)
+	assert.False(t, templates.HasBody())
+
+	subject, body, err := templates.Render(TemplateData{Subject: "2 updates", Text: "default text", Part: 1, Parts: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "2 updates (1/2)", subject)
+	assert.Equal(t, "default text", body)
+}
+
+func TestLoadTemplates_Errors(t *testing.T) {
+	_, err := LoadTemplates(filepath.Join(t.TempDir(), "missing.tmpl"), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load subject template")
+
+	_, err = LoadTemplates("", writeTemplate(t, "body.tmpl", "{{ .Updates "))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load body template")
+
+	templates, err := LoadTemplates("", writeTemplate(t, "body.tmpl", "{{ .Missing }}"))
+	require.NoError(t, err)
+	_, _, err = templates.Render(TemplateData{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to render body template")
+}
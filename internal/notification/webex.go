@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WebexMessagesURL is the Webex API endpoint for posting messages
+const WebexMessagesURL = "https://webexapis.com/v1/messages"
+
+// webexPayload represents the JSON payload for the Webex messages API
+type webexPayload struct {
+	RoomID   string `json:"roomId"`
+	Markdown string `json:"markdown"`
+}
+
+// WebexNotifier handles sending notifications to a Webex room via a bot token
+type WebexNotifier struct {
+	*HTTPNotifier
+	roomID string
+}
+
+// NewWebexNotifier creates a new Webex notifier
+func NewWebexNotifier(botToken, roomID string, logger *logrus.Entry) *WebexNotifier {
+	return NewWebexNotifierWithClient(WebexMessagesURL, botToken, roomID, nil, logger)
+}
+
+// NewWebexNotifierWithClient creates a new Webex notifier with a custom API URL and HTTP client
+func NewWebexNotifierWithClient(apiURL, botToken, roomID string, httpClient *http.Client, logger *logrus.Entry) *WebexNotifier {
+	httpNotifier := NewHTTPNotifier(apiURL, httpClient, logger)
+	httpNotifier.SetHeader("Authorization", "Bearer "+botToken)
+
+	return &WebexNotifier{
+		HTTPNotifier: httpNotifier,
+		roomID:       roomID,
+	}
+}
+
+// Send sends a notification via Webex (implements Notifier interface)
+func (n *WebexNotifier) Send(ctx context.Context, subject, message string) error {
+	// Webex renders markdown, so the subject becomes a bold heading
+	fullMessage := message
+	if subject != "" {
+		fullMessage = fmt.Sprintf("**%s**\n\n%s", subject, message)
+	}
+
+	payload := webexPayload{
+		RoomID:   n.roomID,
+		Markdown: fullMessage,
+	}
+
+	n.logger.WithField("room_id", n.roomID).Debug("Sending Webex notification")
+
+	if err := n.SendJSON(ctx, payload); err != nil {
+		return err
+	}
+
+	n.logger.WithField("room_id", n.roomID).Info("Successfully sent Webex notification")
+	return nil
+}
@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebexNotifier(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewWebexNotifier("TOKEN", "ROOM", logger)
+
+	require.NotNil(t, notifier)
+	assert.Equal(t, WebexMessagesURL, notifier.webhookURL)
+	assert.Equal(t, "ROOM", notifier.roomID)
+	assert.Equal(t, "Bearer TOKEN", notifier.headers["Authorization"])
+	assert.NotNil(t, notifier.httpClient)
+	assert.NotNil(t, notifier.logger)
+}
+
+func TestWebexNotifier_Send_Success(t *testing.T) {
+	var receivedPayload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer TOKEN", r.Header.Get("Authorization"))
+
+		err := json.NewDecoder(r.Body).Decode(&receivedPayload)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewWebexNotifierWithClient(server.URL, "TOKEN", "ROOM", nil, logger)
+
+	err := notifier.Send(context.Background(), "Subject", "Message")
+	require.NoError(t, err)
+	assert.Equal(t, "ROOM", receivedPayload["roomId"])
+	assert.Equal(t, "**Subject**\n\nMessage", receivedPayload["markdown"])
+}
+
+func TestWebexNotifier_Send_EmptySubject(t *testing.T) {
+	var receivedPayload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&receivedPayload)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewWebexNotifierWithClient(server.URL, "TOKEN", "ROOM", nil, logger)
+
+	err := notifier.Send(context.Background(), "", "Message only")
+	require.NoError(t, err)
+	assert.Equal(t, "Message only", receivedPayload["markdown"])
+}
+
+func TestWebexNotifier_Send_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	logger := logrus.NewEntry(logrus.New())
+	notifier := NewWebexNotifierWithClient(server.URL, "BAD", "ROOM", nil, logger)
+
+	err := notifier.Send(context.Background(), "Test", "Message")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+}
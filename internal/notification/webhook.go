@@ -45,3 +45,24 @@ func (n *WebhookNotifier) Send(ctx context.Context, subject, message string) err
 	n.logger.Info("Successfully sent webhook notification")
 	return nil
 }
+
+// StructuredWebhookNotifier posts the complete scan results as JSON to a generic webhook, and
+// plain {subject, message} payloads for other notifications (e.g. test messages)
+type StructuredWebhookNotifier struct {
+	*WebhookNotifier
+}
+
+// NewStructuredWebhookNotifier creates a webhook notifier posting scan results
+func NewStructuredWebhookNotifier(webhook *WebhookNotifier) *StructuredWebhookNotifier {
+	return &StructuredWebhookNotifier{WebhookNotifier: webhook}
+}
+
+// SendReport posts the scan results as they are encoded to JSON (implements ReportNotifier)
+func (n *StructuredWebhookNotifier) SendReport(ctx context.Context, report any) error {
+	if err := n.SendJSON(ctx, report); err != nil {
+		return err
+	}
+
+	n.logger.Info("Successfully sent webhook scan report")
+	return nil
+}
@@ -2,7 +2,9 @@ package notification
 
 import (
 	"context"
+	"crypto/hmac"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -154,3 +156,48 @@ func TestWebhookNotifier_Send_AcceptsAllSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestStructuredWebhookNotifier_SendReport(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		header = r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhookNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	webhook.SetHeader("authorization", "Bearer token")
+	webhook.SetSigningSecret("secret")
+	notifier := NewStructuredWebhookNotifier(webhook)
+
+	var _ ReportNotifier = notifier
+	report := map[string]any{"schema_version": 1, "applications": []string{"app1"}}
+	require.NoError(t, notifier.SendReport(context.Background(), report))
+
+	expected, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(body))
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+
+	// Receivers recompute the signature over the raw body
+	assert.True(t, hmac.Equal([]byte(SignBody("secret", body)), []byte(header.Get(SignatureHeader))))
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, header.Get(SignatureHeader))
+}
+
+func TestWebhookNotifier_Send_Unsigned(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, logrus.NewEntry(logrus.New()))
+	notifier.SetSigningSecret("")
+	require.NoError(t, notifier.Send(context.Background(), "Subject", "Message"))
+	assert.Empty(t, header.Get(SignatureHeader))
+}
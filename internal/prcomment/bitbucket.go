@@ -0,0 +1,269 @@
+package prcomment
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultBitbucketAPIURL is the API of Bitbucket Cloud
+const DefaultBitbucketAPIURL = "https://api.bitbucket.org/2.0"
+
+// bitbucketPageSize is the number of comments fetched per page (Bitbucket's maximum)
+const bitbucketPageSize = 100
+
+// BitbucketConfig holds the settings of a Bitbucket pull request comment
+type BitbucketConfig struct {
+	URL         string // Bitbucket Cloud API URL, or the base URL of Bitbucket Server/Data Center
+	Token       string // Access token, sent as a bearer token
+	Username    string // Username for app password (Cloud) or password (Server) authentication, used without Token
+	Password    string
+	Workspace   string // Bitbucket Cloud workspace, or Bitbucket Server project key
+	Repository  string // Repository slug
+	PullRequest int    // Pull request ID
+}
+
+// BitbucketConfigFromEnv fills unset fields from the Bitbucket Pipelines environment
+func BitbucketConfigFromEnv(cfg BitbucketConfig) BitbucketConfig {
+	if cfg.Workspace == "" {
+		cfg.Workspace = os.Getenv("BITBUCKET_WORKSPACE")
+	}
+	if cfg.Repository == "" {
+		cfg.Repository = os.Getenv("BITBUCKET_REPO_SLUG")
+	}
+	if cfg.PullRequest == 0 {
+		cfg.PullRequest, _ = strconv.Atoi(os.Getenv("BITBUCKET_PR_ID"))
+	}
+	return cfg
+}
+
+// validateBitbucketConfig checks the settings shared by Bitbucket Cloud and Server
+func validateBitbucketConfig(cfg BitbucketConfig) error {
+	if cfg.Token == "" && (cfg.Username == "" || cfg.Password == "") {
+		return fmt.Errorf("bitbucket_token, or bitbucket_username and bitbucket_password, are required to comment on pull requests")
+	}
+	if cfg.Workspace == "" {
+		return fmt.Errorf("bitbucket_workspace is required outside of Bitbucket Pipelines")
+	}
+	if cfg.Repository == "" {
+		return fmt.Errorf("bitbucket_repository is required outside of Bitbucket Pipelines")
+	}
+	if cfg.PullRequest <= 0 {
+		return fmt.Errorf("bitbucket_pr_id is required outside of Bitbucket Pipelines pull request pipelines")
+	}
+	return nil
+}
+
+// bitbucketHeaders returns the authentication headers of a Bitbucket API client
+func bitbucketHeaders(cfg BitbucketConfig) map[string]string {
+	if cfg.Token != "" {
+		return map[string]string{"Authorization": "Bearer " + cfg.Token}
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Password))
+	return map[string]string{"Authorization": "Basic " + credentials}
+}
+
+// bitbucketCloudComment represents the fields of a Bitbucket Cloud pull request comment used by argazer
+type bitbucketCloudComment struct {
+	ID      int64 `json:"id"`
+	Deleted bool  `json:"deleted"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+}
+
+// bitbucketCloudComments represents a page of Bitbucket Cloud comments
+type bitbucketCloudComments struct {
+	Values []bitbucketCloudComment `json:"values"`
+	Next   string                  `json:"next"`
+}
+
+// BitbucketCloudClient posts the report as a Bitbucket Cloud pull request comment
+type BitbucketCloudClient struct {
+	api         *apiClient
+	workspace   string
+	repository  string
+	pullRequest int
+	logger      *logrus.Entry
+}
+
+// NewBitbucketCloudClient creates a new Bitbucket Cloud pull request commenter
+func NewBitbucketCloudClient(cfg BitbucketConfig, httpClient *http.Client, logger *logrus.Entry) (*BitbucketCloudClient, error) {
+	if err := validateBitbucketConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	apiURL := cfg.URL
+	if apiURL == "" {
+		apiURL = DefaultBitbucketAPIURL
+	}
+
+	return &BitbucketCloudClient{
+		api:         newAPIClient("Bitbucket", apiURL, bitbucketHeaders(cfg), httpClient, logger),
+		workspace:   cfg.Workspace,
+		repository:  cfg.Repository,
+		pullRequest: cfg.PullRequest,
+		logger:      logger,
+	}, nil
+}
+
+// PlainMarkdown marks Bitbucket as not rendering HTML (implements PlainPoster)
+func (c *BitbucketCloudClient) PlainMarkdown() {}
+
+// Post creates the report comment or updates the one posted by a previous run (implements Poster)
+func (c *BitbucketCloudClient) Post(ctx context.Context, report string) error {
+	existing, err := c.findComment(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list comments of %s/%s#%d: %w", c.workspace, c.repository, c.pullRequest, err)
+	}
+
+	payload := map[string]interface{}{"content": map[string]string{"raw": commentBody(PlainMarker, report)}}
+	logger := c.logger.WithFields(logrus.Fields{"repository": c.workspace + "/" + c.repository, "pull_request": c.pullRequest})
+
+	if existing != 0 {
+		if err := c.api.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", c.commentsPath(), existing), payload, nil); err != nil {
+			return fmt.Errorf("failed to update comment on %s/%s#%d: %w", c.workspace, c.repository, c.pullRequest, err)
+		}
+		logger.WithField("comment_id", existing).Info("Updated pull request comment")
+		return nil
+	}
+
+	if err := c.api.do(ctx, http.MethodPost, c.commentsPath(), payload, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s/%s#%d: %w", c.workspace, c.repository, c.pullRequest, err)
+	}
+	logger.Info("Posted pull request comment")
+	return nil
+}
+
+// findComment returns the ID of argazer's comment on the pull request, or 0 if there is none
+func (c *BitbucketCloudClient) findComment(ctx context.Context) (int64, error) {
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("%s?pagelen=%d&page=%d", c.commentsPath(), bitbucketPageSize, page)
+
+		var comments bitbucketCloudComments
+		if err := c.api.do(ctx, http.MethodGet, path, nil, &comments); err != nil {
+			return 0, err
+		}
+		for _, comment := range comments.Values {
+			if !comment.Deleted && isReportComment(PlainMarker, comment.Content.Raw) {
+				return comment.ID, nil
+			}
+		}
+		if comments.Next == "" {
+			return 0, nil
+		}
+	}
+}
+
+// commentsPath returns the API path of the pull request's comments
+func (c *BitbucketCloudClient) commentsPath() string {
+	return fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments", url.PathEscape(c.workspace), url.PathEscape(c.repository), c.pullRequest)
+}
+
+// bitbucketServerComment represents the fields of a Bitbucket Server comment used by argazer
+type bitbucketServerComment struct {
+	ID      int64  `json:"id"`
+	Version int    `json:"version"` // Must be sent back on updates (optimistic locking)
+	Text    string `json:"text"`
+}
+
+// bitbucketServerActivities represents a page of Bitbucket Server pull request activities
+// Comments are only listed as activities.
+type bitbucketServerActivities struct {
+	Values []struct {
+		Action  string                  `json:"action"`
+		Comment *bitbucketServerComment `json:"comment"`
+	} `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// BitbucketServerClient posts the report as a Bitbucket Server/Data Center pull request comment
+type BitbucketServerClient struct {
+	api         *apiClient
+	project     string
+	repository  string
+	pullRequest int
+	logger      *logrus.Entry
+}
+
+// NewBitbucketServerClient creates a new Bitbucket Server/Data Center pull request commenter
+func NewBitbucketServerClient(cfg BitbucketConfig, httpClient *http.Client, logger *logrus.Entry) (*BitbucketServerClient, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("bitbucket_url is required for Bitbucket Server")
+	}
+	if err := validateBitbucketConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &BitbucketServerClient{
+		api:         newAPIClient("Bitbucket", strings.TrimSuffix(cfg.URL, "/")+"/rest/api/1.0", bitbucketHeaders(cfg), httpClient, logger),
+		project:     cfg.Workspace,
+		repository:  cfg.Repository,
+		pullRequest: cfg.PullRequest,
+		logger:      logger,
+	}, nil
+}
+
+// PlainMarkdown marks Bitbucket as not rendering HTML (implements PlainPoster)
+func (c *BitbucketServerClient) PlainMarkdown() {}
+
+// Post creates the report comment or updates the one posted by a previous run (implements Poster)
+func (c *BitbucketServerClient) Post(ctx context.Context, report string) error {
+	existing, err := c.findComment(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list comments of %s/%s#%d: %w", c.project, c.repository, c.pullRequest, err)
+	}
+
+	body := commentBody(PlainMarker, report)
+	logger := c.logger.WithFields(logrus.Fields{"repository": c.project + "/" + c.repository, "pull_request": c.pullRequest})
+
+	if existing != nil {
+		payload := map[string]interface{}{"text": body, "version": existing.Version}
+		if err := c.api.do(ctx, http.MethodPut, fmt.Sprintf("%s/comments/%d", c.pullRequestPath(), existing.ID), payload, nil); err != nil {
+			return fmt.Errorf("failed to update comment on %s/%s#%d: %w", c.project, c.repository, c.pullRequest, err)
+		}
+		logger.WithField("comment_id", existing.ID).Info("Updated pull request comment")
+		return nil
+	}
+
+	if err := c.api.do(ctx, http.MethodPost, c.pullRequestPath()+"/comments", map[string]string{"text": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s/%s#%d: %w", c.project, c.repository, c.pullRequest, err)
+	}
+	logger.Info("Posted pull request comment")
+	return nil
+}
+
+// findComment returns argazer's comment on the pull request, or nil if there is none
+func (c *BitbucketServerClient) findComment(ctx context.Context) (*bitbucketServerComment, error) {
+	start := 0
+	for {
+		path := fmt.Sprintf("%s/activities?limit=%d&start=%d", c.pullRequestPath(), bitbucketPageSize, start)
+
+		var activities bitbucketServerActivities
+		if err := c.api.do(ctx, http.MethodGet, path, nil, &activities); err != nil {
+			return nil, err
+		}
+		for _, activity := range activities.Values {
+			if activity.Action == "COMMENTED" && activity.Comment != nil && isReportComment(PlainMarker, activity.Comment.Text) {
+				return activity.Comment, nil
+			}
+		}
+		if activities.IsLastPage || activities.NextPageStart <= start {
+			return nil, nil
+		}
+		start = activities.NextPageStart
+	}
+}
+
+// pullRequestPath returns the API path of the pull request
+func (c *BitbucketServerClient) pullRequestPath() string {
+	return fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d", url.PathEscape(c.project), url.PathEscape(c.repository), c.pullRequest)
+}
@@ -0,0 +1,162 @@
+package prcomment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitbucketCloudClient_Post_Create(t *testing.T) {
+	var created map[string]map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "/repositories/acme/deploy/pullrequests/5/comments", r.URL.Path)
+
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("page") == "1" {
+				w.Write([]byte(`{"values": [{"id": 1, "content": {"raw": "Looks good"}}], "next": "https://api.bitbucket.org/2.0/next"}`))
+				return
+			}
+			// Deleted comments keep their content but can't be edited
+			w.Write([]byte(`{"values": [{"id": 2, "deleted": true, "content": {"raw": "` + PlainMarker + `"}}]}`))
+		case http.MethodPost:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 3}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewBitbucketCloudClient(BitbucketConfig{URL: server.URL, Token: "test-token", Workspace: "acme", Repository: "deploy", PullRequest: 5}, nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	require.NoError(t, client.Post(context.Background(), "# Report"))
+	assert.Equal(t, PlainMarker+"\n# Report", created["content"]["raw"])
+}
+
+func TestBitbucketCloudClient_Post_Update(t *testing.T) {
+	var updated map[string]map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "bot", user)
+		assert.Equal(t, "app-password", password)
+
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"values": [{"id": 9, "content": {"raw": "` + PlainMarker + `\nold report"}}]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/repositories/acme/deploy/pullrequests/5/comments/9":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			w.Write([]byte(`{"id": 9}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewBitbucketCloudClient(BitbucketConfig{URL: server.URL, Username: "bot", Password: "app-password", Workspace: "acme", Repository: "deploy", PullRequest: 5}, nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	require.NoError(t, client.Post(context.Background(), "new report"))
+	assert.Equal(t, PlainMarker+"\nnew report", updated["content"]["raw"])
+}
+
+func TestBitbucketServerClient_Post_Update(t *testing.T) {
+	var updated map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/1.0/projects/OPS/repos/deploy/pull-requests/12/activities":
+			if r.URL.Query().Get("start") == "0" {
+				w.Write([]byte(`{"values": [{"action": "APPROVED"}, {"action": "COMMENTED", "comment": {"id": 1, "version": 0, "text": "nice"}}], "isLastPage": false, "nextPageStart": 2}`))
+				return
+			}
+			w.Write([]byte(`{"values": [{"action": "COMMENTED", "comment": {"id": 77, "version": 3, "text": "` + PlainMarker + `\nold"}}], "isLastPage": true}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/api/1.0/projects/OPS/repos/deploy/pull-requests/12/comments/77":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			w.Write([]byte(`{"id": 77, "version": 4}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewBitbucketServerClient(BitbucketConfig{URL: server.URL + "/", Token: "test-token", Workspace: "OPS", Repository: "deploy", PullRequest: 12}, nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	require.NoError(t, client.Post(context.Background(), "new report"))
+	assert.Equal(t, PlainMarker+"\nnew report", updated["text"])
+	assert.Equal(t, float64(3), updated["version"])
+}
+
+func TestBitbucketServerClient_Post_Create(t *testing.T) {
+	var created map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"values": [], "isLastPage": true}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/1.0/projects/OPS/repos/deploy/pull-requests/12/comments":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 78}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewBitbucketServerClient(BitbucketConfig{URL: server.URL, Token: "test-token", Workspace: "OPS", Repository: "deploy", PullRequest: 12}, nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	require.NoError(t, client.Post(context.Background(), "# Report"))
+	assert.Equal(t, PlainMarker+"\n# Report", created["text"])
+}
+
+func TestNewBitbucketClient_Validation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	_, err := NewBitbucketCloudClient(BitbucketConfig{Username: "bot", Workspace: "acme", Repository: "deploy", PullRequest: 1}, nil, logger)
+	assert.ErrorContains(t, err, "bitbucket_token")
+
+	_, err = NewBitbucketCloudClient(BitbucketConfig{Token: "t", Repository: "deploy", PullRequest: 1}, nil, logger)
+	assert.ErrorContains(t, err, "bitbucket_workspace")
+
+	_, err = NewBitbucketCloudClient(BitbucketConfig{Token: "t", Workspace: "acme", Repository: "deploy"}, nil, logger)
+	assert.ErrorContains(t, err, "bitbucket_pr_id")
+
+	_, err = NewBitbucketServerClient(BitbucketConfig{Token: "t", Workspace: "OPS", Repository: "deploy", PullRequest: 1}, nil, logger)
+	assert.ErrorContains(t, err, "bitbucket_url")
+}
+
+func TestBitbucketConfigFromEnv(t *testing.T) {
+	t.Setenv("BITBUCKET_WORKSPACE", "acme")
+	t.Setenv("BITBUCKET_REPO_SLUG", "deploy")
+	t.Setenv("BITBUCKET_PR_ID", "5")
+
+	cfg := BitbucketConfigFromEnv(BitbucketConfig{Token: "t"})
+	assert.Equal(t, BitbucketConfig{Token: "t", Workspace: "acme", Repository: "deploy", PullRequest: 5}, cfg)
+
+	cfg = BitbucketConfigFromEnv(BitbucketConfig{Workspace: "other", PullRequest: 8})
+	assert.Equal(t, "other", cfg.Workspace)
+	assert.Equal(t, 8, cfg.PullRequest)
+}
+
+func TestPlainPoster(t *testing.T) {
+	var poster Poster = &BitbucketCloudClient{}
+	_, plain := poster.(PlainPoster)
+	assert.True(t, plain)
+
+	poster = &GitHubClient{}
+	_, plain = poster.(PlainPoster)
+	assert.False(t, plain)
+}
@@ -0,0 +1,188 @@
+package prcomment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultGitHubAPIURL is the API of github.com
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// githubPageSize is the number of comments fetched per page (GitHub's maximum)
+const githubPageSize = 100
+
+// GitHubConfig holds the settings of a GitHub pull request comment
+type GitHubConfig struct {
+	APIURL      string // API base URL, e.g. https://github.example.com/api/v3 for GitHub Enterprise Server
+	Token       string // Token allowed to write pull request comments
+	Repository  string // Repository as owner/name
+	PullRequest int    // Pull request number
+}
+
+// GitHubConfigFromEnv fills unset fields from the GitHub Actions environment
+// The pull request number is taken from GITHUB_REF (refs/pull/<n>/merge) or the event payload.
+func GitHubConfigFromEnv(cfg GitHubConfig) GitHubConfig {
+	if cfg.APIURL == "" {
+		cfg.APIURL = os.Getenv("GITHUB_API_URL")
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = DefaultGitHubAPIURL
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("GITHUB_TOKEN")
+	}
+	if cfg.Repository == "" {
+		cfg.Repository = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if cfg.PullRequest == 0 {
+		cfg.PullRequest = githubPullRequestFromRef(os.Getenv("GITHUB_REF"))
+	}
+	if cfg.PullRequest == 0 {
+		cfg.PullRequest = githubPullRequestFromEvent(os.Getenv("GITHUB_EVENT_PATH"))
+	}
+	return cfg
+}
+
+// githubPullRequestFromRef extracts the pull request number from a ref like refs/pull/42/merge
+func githubPullRequestFromRef(ref string) int {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "refs" || parts[1] != "pull" {
+		return 0
+	}
+	number, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// githubPullRequestFromEvent reads the pull request number from the workflow's event payload
+// Both pull_request and issue_comment events (on pull requests) carry it.
+func githubPullRequestFromEvent(path string) int {
+	if path == "" {
+		return 0
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	var event struct {
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+		Issue struct {
+			Number      int             `json:"number"`
+			PullRequest json.RawMessage `json:"pull_request"`
+		} `json:"issue"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return 0
+	}
+	if event.PullRequest.Number != 0 {
+		return event.PullRequest.Number
+	}
+	if len(event.Issue.PullRequest) > 0 {
+		return event.Issue.Number
+	}
+	return 0
+}
+
+// githubComment represents the fields of an issue comment used by argazer
+type githubComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// GitHubClient posts the report as a pull request comment
+type GitHubClient struct {
+	api         *apiClient
+	repository  string
+	pullRequest int
+	logger      *logrus.Entry
+}
+
+// NewGitHubClient creates a new GitHub pull request commenter
+func NewGitHubClient(cfg GitHubConfig, httpClient *http.Client, logger *logrus.Entry) (*GitHubClient, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("github_token is required to comment on pull requests")
+	}
+	if owner, name, ok := strings.Cut(cfg.Repository, "/"); !ok || owner == "" || name == "" {
+		return nil, fmt.Errorf("github_repository must be in the form owner/name (got: '%s')", cfg.Repository)
+	}
+	if cfg.PullRequest <= 0 {
+		return nil, fmt.Errorf("github_pr_number is required outside of GitHub Actions pull request workflows")
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = DefaultGitHubAPIURL
+	}
+
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"Authorization":        "Bearer " + cfg.Token,
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+
+	return &GitHubClient{
+		api:         newAPIClient("GitHub", apiURL, headers, httpClient, logger),
+		repository:  cfg.Repository,
+		pullRequest: cfg.PullRequest,
+		logger:      logger,
+	}, nil
+}
+
+// Post creates the report comment or updates the one posted by a previous run (implements Poster)
+func (c *GitHubClient) Post(ctx context.Context, report string) error {
+	existing, err := c.findComment(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list comments of %s#%d: %w", c.repository, c.pullRequest, err)
+	}
+
+	payload := map[string]string{"body": commentBody(Marker, report)}
+	logger := c.logger.WithFields(logrus.Fields{"repository": c.repository, "pull_request": c.pullRequest})
+
+	if existing != 0 {
+		path := fmt.Sprintf("/repos/%s/issues/comments/%d", c.repository, existing)
+		if err := c.api.do(ctx, http.MethodPatch, path, payload, nil); err != nil {
+			return fmt.Errorf("failed to update comment on %s#%d: %w", c.repository, c.pullRequest, err)
+		}
+		logger.WithField("comment_id", existing).Info("Updated pull request comment")
+		return nil
+	}
+
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", c.repository, c.pullRequest)
+	if err := c.api.do(ctx, http.MethodPost, path, payload, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s#%d: %w", c.repository, c.pullRequest, err)
+	}
+	logger.Info("Posted pull request comment")
+	return nil
+}
+
+// findComment returns the ID of argazer's comment on the pull request, or 0 if there is none
+func (c *GitHubClient) findComment(ctx context.Context) (int64, error) {
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=%d&page=%d", c.repository, c.pullRequest, githubPageSize, page)
+
+		var comments []githubComment
+		if err := c.api.do(ctx, http.MethodGet, path, nil, &comments); err != nil {
+			return 0, err
+		}
+		for _, comment := range comments {
+			if isReportComment(Marker, comment.Body) {
+				return comment.ID, nil
+			}
+		}
+		if len(comments) < githubPageSize {
+			return 0, nil
+		}
+	}
+}
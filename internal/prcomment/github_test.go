@@ -0,0 +1,140 @@
+package prcomment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGitHubClient(t *testing.T, apiURL string) *GitHubClient {
+	client, err := NewGitHubClient(GitHubConfig{APIURL: apiURL, Token: "test-token", Repository: "org/deploy", PullRequest: 42}, nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	return client
+}
+
+func TestGitHubClient_Post_Create(t *testing.T) {
+	var created string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/deploy/issues/42/comments":
+			assert.Equal(t, "1", r.URL.Query().Get("page"))
+			w.Write([]byte(`[{"id": 1, "body": "LGTM"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/org/deploy/issues/42/comments":
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			created = payload["body"]
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 2}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, newTestGitHubClient(t, server.URL).Post(context.Background(), "# Report"))
+	assert.Equal(t, Marker+"\n# Report", created)
+}
+
+func TestGitHubClient_Post_Update(t *testing.T) {
+	var updated string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("page") == "1":
+			// A full first page makes the client fetch the next one
+			comments := make([]githubComment, githubPageSize)
+			for i := range comments {
+				comments[i] = githubComment{ID: int64(i + 1), Body: fmt.Sprintf("comment %d", i)}
+			}
+			json.NewEncoder(w).Encode(comments)
+		case r.Method == http.MethodGet && r.URL.Query().Get("page") == "2":
+			w.Write([]byte(`[{"id": 512, "body": "` + Marker + `\nold report"}]`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/org/deploy/issues/comments/512":
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			updated = payload["body"]
+			w.Write([]byte(`{"id": 512}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, newTestGitHubClient(t, server.URL).Post(context.Background(), "new report"))
+	assert.Equal(t, Marker+"\nnew report", updated)
+}
+
+func TestGitHubClient_Post_Forbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Resource not accessible by integration"}`))
+	}))
+	defer server.Close()
+
+	err := newTestGitHubClient(t, server.URL).Post(context.Background(), "report")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403")
+}
+
+func TestNewGitHubClient_Validation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	_, err := NewGitHubClient(GitHubConfig{Repository: "org/deploy", PullRequest: 1}, nil, logger)
+	assert.ErrorContains(t, err, "github_token")
+
+	_, err = NewGitHubClient(GitHubConfig{Token: "t", Repository: "deploy", PullRequest: 1}, nil, logger)
+	assert.ErrorContains(t, err, "owner/name")
+
+	_, err = NewGitHubClient(GitHubConfig{Token: "t", Repository: "org/deploy"}, nil, logger)
+	assert.ErrorContains(t, err, "github_pr_number")
+}
+
+func TestGitHubConfigFromEnv(t *testing.T) {
+	t.Run("pull request ref", func(t *testing.T) {
+		t.Setenv("GITHUB_API_URL", "")
+		t.Setenv("GITHUB_TOKEN", "env-token")
+		t.Setenv("GITHUB_REPOSITORY", "org/deploy")
+		t.Setenv("GITHUB_REF", "refs/pull/17/merge")
+		t.Setenv("GITHUB_EVENT_PATH", "")
+
+		cfg := GitHubConfigFromEnv(GitHubConfig{})
+		assert.Equal(t, GitHubConfig{APIURL: DefaultGitHubAPIURL, Token: "env-token", Repository: "org/deploy", PullRequest: 17}, cfg)
+	})
+
+	t.Run("explicit settings win", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "env-token")
+		t.Setenv("GITHUB_REF", "refs/pull/17/merge")
+
+		cfg := GitHubConfigFromEnv(GitHubConfig{Token: "cfg-token", PullRequest: 3})
+		assert.Equal(t, "cfg-token", cfg.Token)
+		assert.Equal(t, 3, cfg.PullRequest)
+	})
+
+	t.Run("issue comment event", func(t *testing.T) {
+		eventPath := filepath.Join(t.TempDir(), "event.json")
+		require.NoError(t, os.WriteFile(eventPath, []byte(`{"issue": {"number": 23, "pull_request": {"url": "https://api.github.com/repos/org/deploy/pulls/23"}}}`), 0o600))
+		t.Setenv("GITHUB_REF", "refs/heads/main")
+		t.Setenv("GITHUB_EVENT_PATH", eventPath)
+
+		assert.Equal(t, 23, GitHubConfigFromEnv(GitHubConfig{}).PullRequest)
+	})
+
+	t.Run("push event", func(t *testing.T) {
+		t.Setenv("GITHUB_REF", "refs/heads/main")
+		t.Setenv("GITHUB_EVENT_PATH", "")
+
+		assert.Zero(t, GitHubConfigFromEnv(GitHubConfig{}).PullRequest)
+	})
+}
@@ -0,0 +1,138 @@
+package prcomment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultGitLabAPIURL is the API of gitlab.com
+const DefaultGitLabAPIURL = "https://gitlab.com/api/v4"
+
+// gitlabPageSize is the number of notes fetched per page (GitLab's maximum)
+const gitlabPageSize = 100
+
+// GitLabConfig holds the settings of a GitLab merge request note
+type GitLabConfig struct {
+	APIURL       string // API base URL including /api/v4
+	Token        string // Personal, project or group access token with the api scope
+	Project      string // Numeric project ID or full path (group/project)
+	MergeRequest int    // Merge request IID (the number shown in the UI)
+}
+
+// GitLabConfigFromEnv fills unset fields from the GitLab CI environment
+// CI_JOB_TOKEN can't write notes, so the token falls back to GITLAB_TOKEN only.
+func GitLabConfigFromEnv(cfg GitLabConfig) GitLabConfig {
+	if cfg.APIURL == "" {
+		cfg.APIURL = os.Getenv("CI_API_V4_URL")
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = DefaultGitLabAPIURL
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("GITLAB_TOKEN")
+	}
+	if cfg.Project == "" {
+		cfg.Project = os.Getenv("CI_PROJECT_ID")
+	}
+	if cfg.MergeRequest == 0 {
+		cfg.MergeRequest, _ = strconv.Atoi(os.Getenv("CI_MERGE_REQUEST_IID"))
+	}
+	return cfg
+}
+
+// gitlabNote represents the fields of a merge request note used by argazer
+type gitlabNote struct {
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+	System bool   `json:"system"`
+}
+
+// GitLabClient posts the report as a merge request note
+type GitLabClient struct {
+	api          *apiClient
+	project      string
+	mergeRequest int
+	logger       *logrus.Entry
+}
+
+// NewGitLabClient creates a new GitLab merge request commenter
+func NewGitLabClient(cfg GitLabConfig, httpClient *http.Client, logger *logrus.Entry) (*GitLabClient, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("gitlab_token is required to comment on merge requests")
+	}
+	if cfg.Project == "" {
+		return nil, fmt.Errorf("gitlab_project is required outside of GitLab CI")
+	}
+	if cfg.MergeRequest <= 0 {
+		return nil, fmt.Errorf("gitlab_mr_iid is required outside of GitLab CI merge request pipelines")
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = DefaultGitLabAPIURL
+	}
+
+	return &GitLabClient{
+		api:          newAPIClient("GitLab", apiURL, map[string]string{"PRIVATE-TOKEN": cfg.Token}, httpClient, logger),
+		project:      cfg.Project,
+		mergeRequest: cfg.MergeRequest,
+		logger:       logger,
+	}, nil
+}
+
+// Post creates the report note or updates the one posted by a previous run (implements Poster)
+func (c *GitLabClient) Post(ctx context.Context, report string) error {
+	existing, err := c.findNote(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list notes of %s!%d: %w", c.project, c.mergeRequest, err)
+	}
+
+	payload := map[string]string{"body": commentBody(Marker, report)}
+	logger := c.logger.WithFields(logrus.Fields{"project": c.project, "merge_request": c.mergeRequest})
+
+	if existing != 0 {
+		if err := c.api.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", c.notesPath(), existing), payload, nil); err != nil {
+			return fmt.Errorf("failed to update note on %s!%d: %w", c.project, c.mergeRequest, err)
+		}
+		logger.WithField("note_id", existing).Info("Updated merge request note")
+		return nil
+	}
+
+	if err := c.api.do(ctx, http.MethodPost, c.notesPath(), payload, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s!%d: %w", c.project, c.mergeRequest, err)
+	}
+	logger.Info("Posted merge request note")
+	return nil
+}
+
+// findNote returns the ID of argazer's note on the merge request, or 0 if there is none
+func (c *GitLabClient) findNote(ctx context.Context) (int64, error) {
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("%s?sort=asc&per_page=%d&page=%d", c.notesPath(), gitlabPageSize, page)
+
+		var notes []gitlabNote
+		if err := c.api.do(ctx, http.MethodGet, path, nil, &notes); err != nil {
+			return 0, err
+		}
+		for _, note := range notes {
+			if !note.System && isReportComment(Marker, note.Body) {
+				return note.ID, nil
+			}
+		}
+		if len(notes) < gitlabPageSize {
+			return 0, nil
+		}
+	}
+}
+
+// notesPath returns the API path of the merge request's notes
+// Project paths such as group/project are URL-encoded as GitLab requires.
+func (c *GitLabClient) notesPath() string {
+	return fmt.Sprintf("/projects/%s/merge_requests/%d/notes", url.PathEscape(c.project), c.mergeRequest)
+}
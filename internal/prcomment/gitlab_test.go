@@ -0,0 +1,113 @@
+package prcomment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGitLabClient(t *testing.T, apiURL, project string) *GitLabClient {
+	client, err := NewGitLabClient(GitLabConfig{APIURL: apiURL, Token: "test-token", Project: project, MergeRequest: 7}, nil, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	return client
+}
+
+func TestGitLabClient_Post_Create(t *testing.T) {
+	var created string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-token", r.Header.Get("PRIVATE-TOKEN"))
+		assert.Equal(t, "/projects/platform%2Fdeploy/merge_requests/7/notes", r.URL.EscapedPath())
+
+		switch r.Method {
+		case http.MethodGet:
+			// System notes are never argazer's, even if they quote the marker
+			w.Write([]byte(`[{"id": 1, "body": "` + Marker + `", "system": true}, {"id": 2, "body": "Looks good"}]`))
+		case http.MethodPost:
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			created = payload["body"]
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 3}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, newTestGitLabClient(t, server.URL, "platform/deploy").Post(context.Background(), "# Report"))
+	assert.Equal(t, Marker+"\n# Report", created)
+}
+
+func TestGitLabClient_Post_Update(t *testing.T) {
+	var updated string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/123/merge_requests/7/notes":
+			w.Write([]byte(`[{"id": 40, "body": "` + Marker + `\nold report"}]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/projects/123/merge_requests/7/notes/40":
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			updated = payload["body"]
+			w.Write([]byte(`{"id": 40}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, newTestGitLabClient(t, server.URL, "123").Post(context.Background(), "new report"))
+	assert.Equal(t, Marker+"\nnew report", updated)
+}
+
+func TestGitLabClient_Post_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"401 Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	err := newTestGitLabClient(t, server.URL, "123").Post(context.Background(), "report")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GitLab API returned status 401")
+}
+
+func TestNewGitLabClient_Validation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	_, err := NewGitLabClient(GitLabConfig{Project: "123", MergeRequest: 1}, nil, logger)
+	assert.ErrorContains(t, err, "gitlab_token")
+
+	_, err = NewGitLabClient(GitLabConfig{Token: "t", MergeRequest: 1}, nil, logger)
+	assert.ErrorContains(t, err, "gitlab_project")
+
+	_, err = NewGitLabClient(GitLabConfig{Token: "t", Project: "123"}, nil, logger)
+	assert.ErrorContains(t, err, "gitlab_mr_iid")
+}
+
+func TestGitLabConfigFromEnv(t *testing.T) {
+	t.Setenv("CI_API_V4_URL", "https://gitlab.example.com/api/v4")
+	t.Setenv("GITLAB_TOKEN", "env-token")
+	t.Setenv("CI_PROJECT_ID", "123")
+	t.Setenv("CI_MERGE_REQUEST_IID", "7")
+
+	cfg := GitLabConfigFromEnv(GitLabConfig{})
+	assert.Equal(t, GitLabConfig{APIURL: "https://gitlab.example.com/api/v4", Token: "env-token", Project: "123", MergeRequest: 7}, cfg)
+
+	cfg = GitLabConfigFromEnv(GitLabConfig{Token: "cfg-token", MergeRequest: 9})
+	assert.Equal(t, "cfg-token", cfg.Token)
+	assert.Equal(t, 9, cfg.MergeRequest)
+
+	t.Setenv("CI_API_V4_URL", "")
+	t.Setenv("CI_MERGE_REQUEST_IID", "")
+	cfg = GitLabConfigFromEnv(GitLabConfig{})
+	assert.Equal(t, DefaultGitLabAPIURL, cfg.APIURL)
+	assert.Zero(t, cfg.MergeRequest)
+}
@@ -0,0 +1,124 @@
+package prcomment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Marker identifies argazer's comment, so later runs update it instead of stacking new ones
+const Marker = "<!-- argazer-report -->"
+
+// PlainMarker is the marker for platforms that don't render HTML, where Marker would show up as text
+// An empty link reference definition is valid markdown that renders as nothing.
+const PlainMarker = "[//]: # (argazer-report)"
+
+// defaultTimeout is the timeout of the default HTTP client
+const defaultTimeout = 30 * time.Second
+
+// Poster posts the report as a comment on a pull or merge request
+type Poster interface {
+	// Post creates argazer's comment, or replaces it if a previous run already posted one
+	Post(ctx context.Context, report string) error
+}
+
+// PlainPoster is implemented by posters whose platform doesn't render HTML in comments,
+// so the report must do without collapsible <details> sections
+type PlainPoster interface {
+	Poster
+	PlainMarkdown()
+}
+
+// commentBody prefixes the report with the marker
+func commentBody(marker, report string) string {
+	return marker + "\n" + report
+}
+
+// isReportComment reports whether a comment body was posted by argazer
+func isReportComment(marker, body string) bool {
+	return strings.HasPrefix(body, marker)
+}
+
+// apiClient sends JSON requests to a code hosting API
+type apiClient struct {
+	name       string            // API name used in errors, e.g. "GitHub"
+	baseURL    string            // API base URL without trailing slash
+	headers    map[string]string // Headers sent with every request (e.g. Authorization)
+	httpClient *http.Client
+	logger     *logrus.Entry
+}
+
+// newAPIClient creates an API client, using a default HTTP client if none is given
+func newAPIClient(name, baseURL string, headers map[string]string, httpClient *http.Client, logger *logrus.Entry) *apiClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+
+	return &apiClient{
+		name:       name,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		headers:    headers,
+		httpClient: httpClient,
+		logger:     logger,
+	}
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out (if not nil)
+func (c *apiClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "argazer/1.0")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s API returned status %d: %s", c.name, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+// Package selfupdate replaces the running argazer binary with the latest release,
+// after verifying the release's signed checksums.
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultReleasesURL is the GitHub API endpoint of the latest argazer release
+const DefaultReleasesURL = "https://api.github.com/repos/kreicer/argazer/releases/latest"
+
+// Release assets holding the SHA-256 checksums of the binaries and their ed25519 signature
+const (
+	ChecksumsAsset = "checksums.txt"
+	SignatureAsset = "checksums.txt.sig"
+)
+
+// Download limits
+const (
+	defaultTimeout   = 5 * time.Minute
+	maxMetadataBytes = 1 << 20   // Release metadata, checksums and signature
+	maxBinaryBytes   = 512 << 20 // Release binary
+)
+
+// ErrNoPublicKey is returned when signatures must be verified but the build has no release signing key
+var ErrNoPublicKey = errors.New("this build has no release signing key")
+
+// Release is a published argazer release
+type Release struct {
+	Version string            // Release tag, e.g. "v1.2.0"
+	Assets  map[string]string // Download URLs keyed by asset name
+}
+
+// Updater fetches and verifies argazer releases
+type Updater struct {
+	releasesURL string
+	publicKey   ed25519.PublicKey // Nil if the build has no release signing key
+	httpClient  *http.Client
+	logger      *logrus.Entry
+}
+
+// New creates an updater
+// publicKey is the base64-encoded ed25519 key the release checksums are signed with; it may be
+// empty, in which case only unsigned updates are possible.
+func New(releasesURL, publicKey string, logger *logrus.Entry) (*Updater, error) {
+	u := &Updater{
+		releasesURL: releasesURL,
+		httpClient:  &http.Client{Timeout: defaultTimeout},
+		logger:      logger,
+	}
+	if publicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(publicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid release signing key: expected %d base64-encoded bytes", ed25519.PublicKeySize)
+		}
+		u.publicKey = key
+	}
+	return u, nil
+}
+
+// AssetName returns the name of the release binary for a platform, e.g. "argazer-linux-amd64"
+func AssetName(goos, goarch string) string {
+	name := "argazer-" + goos + "-" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// IsNewer reports whether the latest release is newer than the current version
+// Development builds without a semantic version are always considered older.
+func IsNewer(current, latest string) (bool, error) {
+	latestVersion, err := semver.NewVersion(latest)
+	if err != nil {
+		return false, fmt.Errorf("invalid release version %q: %w", latest, err)
+	}
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return true, nil
+	}
+	return latestVersion.GreaterThan(currentVersion), nil
+}
+
+// Latest returns the latest published release
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	body, err := u.fetch(ctx, u.releasesURL, "application/vnd.github+json", maxMetadataBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+
+	var payload struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name               string `json:"name"`
+			BrowserDownloadURL string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	if payload.TagName == "" {
+		return nil, errors.New("release has no tag")
+	}
+
+	release := &Release{Version: payload.TagName, Assets: make(map[string]string, len(payload.Assets))}
+	for _, asset := range payload.Assets {
+		release.Assets[asset.Name] = asset.BrowserDownloadURL
+	}
+	return release, nil
+}
+
+// Download fetches the release binary for the current platform and verifies it against the release
+// checksums, and the checksums against their signature unless verifySignature is false
+func (u *Updater) Download(ctx context.Context, release *Release, verifySignature bool) ([]byte, error) {
+	if verifySignature && u.publicKey == nil {
+		return nil, ErrNoPublicKey
+	}
+
+	assetName := AssetName(runtime.GOOS, runtime.GOARCH)
+	checksums, err := u.fetchAsset(ctx, release, ChecksumsAsset, maxMetadataBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if verifySignature {
+		signature, err := u.fetchAsset(ctx, release, SignatureAsset, maxMetadataBytes)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyChecksumsSignature(u.publicKey, checksums, signature); err != nil {
+			return nil, err
+		}
+		u.logger.WithField("version", release.Version).Debug("Release checksums signature verified")
+	} else {
+		u.logger.Warn("Skipping release signature verification; relying on the checksum only")
+	}
+
+	expected, err := checksumFor(checksums, assetName)
+	if err != nil {
+		return nil, err
+	}
+
+	binary, err := u.fetchAsset(ctx, release, assetName, maxBinaryBytes)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(binary)
+	if hex.EncodeToString(sum[:]) != expected {
+		return nil, fmt.Errorf("checksum mismatch for %s", assetName)
+	}
+	return binary, nil
+}
+
+// fetchAsset downloads a release asset
+func (u *Updater) fetchAsset(ctx context.Context, release *Release, name string, limit int64) ([]byte, error) {
+	url, ok := release.Assets[name]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no asset %s", release.Version, name)
+	}
+	body, err := u.fetch(ctx, url, "application/octet-stream", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	return body, nil
+}
+
+// fetch sends a GET request and returns the response body, failing if it exceeds the limit
+func (u *Updater) fetch(ctx context.Context, url, accept string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "argazer/1.0")
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			u.logger.WithError(err).Warn("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("response exceeds %d bytes", limit)
+	}
+	return body, nil
+}
+
+// verifyChecksumsSignature checks the base64-encoded ed25519 signature of the checksums file
+func verifyChecksumsSignature(publicKey ed25519.PublicKey, checksums, signature []byte) error {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid checksums signature: %w", err)
+	}
+	if !ed25519.Verify(publicKey, checksums, decoded) {
+		return errors.New("checksums signature verification failed")
+	}
+	return nil
+}
+
+// checksumFor returns the SHA-256 checksum of an asset from a sha256sum-formatted checksums file
+func checksumFor(checksums []byte, assetName string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == assetName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s", assetName)
+}
+
+// ReplaceExecutable atomically replaces the binary at path, keeping its permissions
+// The new binary is written next to the old one and renamed over it. Windows can't overwrite a
+// running executable, so there the old binary is moved aside to "<path>.old" first.
+func ReplaceExecutable(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat executable: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("failed to move old binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace binary: %w", err)
+	}
+	return nil
+}
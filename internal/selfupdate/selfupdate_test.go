@@ -0,0 +1,143 @@
+package selfupdate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseServer serves a release with the given binary, checksums and signature assets
+func releaseServer(t *testing.T, binary, checksums, signature []byte) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	assetName := AssetName(runtime.GOOS, runtime.GOARCH)
+	mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name":"v1.3.0","assets":[
+			{"name":%q,"browser_download_url":"%s/download/binary"},
+			{"name":"checksums.txt","browser_download_url":"%s/download/checksums"},
+			{"name":"checksums.txt.sig","browser_download_url":"%s/download/signature"}]}`,
+			assetName, server.URL, server.URL, server.URL)
+	})
+	mux.HandleFunc("/download/binary", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(binary) })
+	mux.HandleFunc("/download/checksums", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(checksums) })
+	mux.HandleFunc("/download/signature", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(signature) })
+	return server
+}
+
+func TestUpdater_Download(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	binary := []byte("new argazer binary")
+	sum := sha256.Sum256(binary)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  " + AssetName(runtime.GOOS, runtime.GOARCH) + "\n" +
+		"0000000000000000000000000000000000000000000000000000000000000000  argazer-plan9-386\n")
+	sign := func(key ed25519.PrivateKey, data []byte) []byte {
+		return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)) + "\n")
+	}
+
+	tests := []struct {
+		name            string
+		binary          []byte
+		signature       []byte
+		publicKey       string
+		verifySignature bool
+		expectedErr     string
+	}{
+		{name: "signed", binary: binary, signature: sign(privateKey, checksums), publicKey: base64.StdEncoding.EncodeToString(publicKey), verifySignature: true},
+		{name: "wrong signing key", binary: binary, signature: sign(otherKey, checksums), publicKey: base64.StdEncoding.EncodeToString(publicKey), verifySignature: true, expectedErr: "signature verification failed"},
+		{name: "tampered binary", binary: []byte("tampered"), signature: sign(privateKey, checksums), publicKey: base64.StdEncoding.EncodeToString(publicKey), verifySignature: true, expectedErr: "checksum mismatch"},
+		{name: "no signing key", binary: binary, signature: sign(privateKey, checksums), verifySignature: true, expectedErr: ErrNoPublicKey.Error()},
+		{name: "signature skipped", binary: binary, signature: []byte("garbage"), verifySignature: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := releaseServer(t, tt.binary, checksums, tt.signature)
+			updater, err := New(server.URL+"/releases/latest", tt.publicKey, logrus.NewEntry(logrus.New()))
+			require.NoError(t, err)
+
+			release, err := updater.Latest(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, "v1.3.0", release.Version)
+
+			got, err := updater.Download(context.Background(), release, tt.verifySignature)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, binary, got)
+		})
+	}
+}
+
+func TestNew_InvalidPublicKey(t *testing.T) {
+	_, err := New(DefaultReleasesURL, "not-a-key", logrus.NewEntry(logrus.New()))
+	assert.Error(t, err)
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current  string
+		latest   string
+		expected bool
+	}{
+		{current: "v1.2.0", latest: "v1.3.0", expected: true},
+		{current: "v1.3.0", latest: "v1.3.0", expected: false},
+		{current: "1.4.0", latest: "v1.3.0", expected: false},
+		{current: "dev", latest: "v1.3.0", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.current+"->"+tt.latest, func(t *testing.T) {
+			got, err := IsNewer(tt.current, tt.latest)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	_, err := IsNewer("v1.0.0", "nightly")
+	assert.Error(t, err)
+}
+
+func TestAssetName(t *testing.T) {
+	assert.Equal(t, "argazer-linux-arm64", AssetName("linux", "arm64"))
+	assert.Equal(t, "argazer-windows-amd64.exe", AssetName("windows", "amd64"))
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "argazer")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0o750))
+
+	require.NoError(t, ReplaceExecutable(path, []byte("new")))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file should be renamed away")
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"argazer/internal/notification"
+	"argazer/internal/state"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Action names used in button payloads
+const (
+	ActionAck    = "ack"
+	ActionSnooze = "snooze"
+)
+
+// SnoozeDuration is how long the "Snooze" button silences an update
+const SnoozeDuration = 30 * 24 * time.Hour
+
+// UpdateActions returns the buttons attached to an update notification
+// id is the update ID from the state store
+func UpdateActions(appName, id string) []notification.Action {
+	return []notification.Action{
+		{Label: "Ack " + appName, Data: ActionAck + ":" + id},
+		{Label: "Snooze 30d", Data: ActionSnooze + ":" + id},
+	}
+}
+
+// parseActionData splits a button payload into the action name and update ID
+func parseActionData(data string) (action, id string, ok bool) {
+	action, id, found := strings.Cut(data, ":")
+	if !found || id == "" {
+		return "", "", false
+	}
+	if action != ActionAck && action != ActionSnooze {
+		return "", "", false
+	}
+	return action, id, true
+}
+
+// applyAction records an Ack/Snooze button press in the store and returns the text shown to the user
+func applyAction(store *state.Store, data, by, source string, logger *logrus.Entry) string {
+	action, id, ok := parseActionData(data)
+	if !ok {
+		logger.WithField("data", data).Debug("Ignoring unknown action")
+		return "Unknown action"
+	}
+
+	update, ok := store.LookupNotification(id)
+	if !ok {
+		return "This notification has expired"
+	}
+
+	ack := state.Acknowledgement{
+		AppName: update.AppName,
+		Version: update.Version,
+		By:      by,
+		Source:  source,
+	}
+
+	reply := fmt.Sprintf("Acknowledged %s %s", update.AppName, update.Version)
+	if action == ActionSnooze {
+		ack.Until = time.Now().Add(SnoozeDuration)
+		reply = fmt.Sprintf("Snoozed %s %s until %s", update.AppName, update.Version, ack.Until.Format("2006-01-02"))
+	}
+
+	if err := store.Acknowledge(ack); err != nil {
+		logger.WithError(err).Error("Failed to record acknowledgement")
+		return "Failed to record acknowledgement"
+	}
+
+	return reply
+}
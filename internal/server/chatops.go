@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"argazer/internal/notification"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chatOpsUsage is the reply to "help" and to commands that can't be parsed
+const chatOpsUsage = "Usage: /argazer scan [project=<name>[,<name>...]] [app=<name>[,<name>...]]"
+
+// ChatOpsFilters limit an on-demand scan to projects and applications; empty fields keep the configured ones
+type ChatOpsFilters struct {
+	Projects []string
+	AppNames []string
+}
+
+// String describes the filters, e.g. "project=payments app=api,worker", or "all applications"
+func (f ChatOpsFilters) String() string {
+	var parts []string
+	if len(f.Projects) > 0 {
+		parts = append(parts, "project="+strings.Join(f.Projects, ","))
+	}
+	if len(f.AppNames) > 0 {
+		parts = append(parts, "app="+strings.Join(f.AppNames, ","))
+	}
+	if len(parts) == 0 {
+		return "all applications"
+	}
+	return strings.Join(parts, " ")
+}
+
+// ChatOpsScan runs a scan limited by the filters and returns its summary
+type ChatOpsScan func(ctx context.Context, filters ChatOpsFilters) (string, error)
+
+// ChatOpsConfig holds the secrets authenticating chatops requests; at least one must be set
+type ChatOpsConfig struct {
+	SlackSigningSecret string // Verifies Slack slash commands
+	Token              string // Bearer token of generic POSTs
+}
+
+// chatOpsRequest is a command, from a Slack slash command form or a generic JSON or form POST
+type chatOpsRequest struct {
+	Text        string `json:"text"`
+	ResponseURL string `json:"response_url"` // Where the summary is posted; the request waits for the scan without one
+	User        string `json:"user_name"`
+}
+
+// chatOpsReply is the JSON reply to a command, in the format of Slack slash command responses
+type chatOpsReply struct {
+	ResponseType string `json:"response_type"` // "in_channel" shows the reply to everyone, "ephemeral" to the requester only
+	Text         string `json:"text"`
+}
+
+// ChatOpsHandler runs scans on demand for Slack slash commands and generic POSTs, e.g. "/argazer scan project=payments"
+type ChatOpsHandler struct {
+	scan      ChatOpsScan
+	cfg       ChatOpsConfig
+	responder *notification.HTTPNotifier
+	running   atomic.Bool // Only one on-demand scan runs at a time
+	logger    *logrus.Entry
+	now       func() time.Time
+}
+
+// NewChatOpsHandler creates a handler running scan for authenticated commands
+func NewChatOpsHandler(scan ChatOpsScan, cfg ChatOpsConfig, logger *logrus.Entry) *ChatOpsHandler {
+	return &ChatOpsHandler{
+		scan:      scan,
+		cfg:       cfg,
+		responder: notification.NewHTTPNotifier("", nil, logger),
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *ChatOpsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCallbackBody))
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if !h.authenticate(r.Header, body) {
+		h.logger.Warn("Rejected chatops request with invalid credentials")
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	request, err := parseChatOpsRequest(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	filters, err := parseChatOpsCommand(request.Text)
+	if err != nil {
+		writeChatOpsReply(w, chatOpsReply{ResponseType: "ephemeral", Text: err.Error()})
+		return
+	}
+
+	if !h.running.CompareAndSwap(false, true) {
+		writeChatOpsReply(w, chatOpsReply{ResponseType: "ephemeral", Text: "A scan is already running, try again when it completes"})
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user":    request.User,
+		"filters": filters.String(),
+	}).Info("Running scan requested via chatops")
+
+	// Slack expects a reply within 3 seconds, so the summary is posted to the response URL later
+	if request.ResponseURL != "" {
+		go func() {
+			defer h.running.Store(false)
+			ctx := context.WithoutCancel(r.Context())
+			if err := h.responder.SendJSONTo(ctx, request.ResponseURL, h.runScan(ctx, filters)); err != nil {
+				h.logger.WithError(err).Warn("Failed to post chatops scan summary")
+			}
+		}()
+		writeChatOpsReply(w, chatOpsReply{ResponseType: "ephemeral", Text: "Scanning " + filters.String() + "…"})
+		return
+	}
+
+	defer h.running.Store(false)
+	writeChatOpsReply(w, h.runScan(r.Context(), filters))
+}
+
+// runScan runs a scan and returns the reply with its summary
+func (h *ChatOpsHandler) runScan(ctx context.Context, filters ChatOpsFilters) chatOpsReply {
+	summary, err := h.scan(ctx, filters)
+	if err != nil {
+		h.logger.WithError(err).Warn("Chatops scan failed")
+		return chatOpsReply{ResponseType: "ephemeral", Text: "Scan of " + filters.String() + " failed: " + err.Error()}
+	}
+	return chatOpsReply{ResponseType: "in_channel", Text: summary}
+}
+
+// authenticate accepts requests signed by Slack and requests with the bearer token
+func (h *ChatOpsHandler) authenticate(header http.Header, body []byte) bool {
+	if h.cfg.SlackSigningSecret != "" && header.Get(slackSignatureHeader) != "" {
+		return verifySlackSignature(h.cfg.SlackSigningSecret, header, body, h.now())
+	}
+	if h.cfg.Token == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.cfg.Token)) == 1
+}
+
+// parseChatOpsRequest reads a JSON body, or the form fields Slack sends for slash commands
+func parseChatOpsRequest(contentType string, body []byte) (chatOpsRequest, error) {
+	var request chatOpsRequest
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/json" {
+		err := json.Unmarshal(body, &request)
+		return request, err
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return request, err
+	}
+	request.Text = form.Get("text")
+	request.ResponseURL = form.Get("response_url")
+	request.User = form.Get("user_name")
+	return request, nil
+}
+
+// parseChatOpsCommand parses "scan project=payments app=api,worker"; the "/argazer" prefix of
+// generic requests is optional, Slack strips the command name itself
+func parseChatOpsCommand(text string) (ChatOpsFilters, error) {
+	var filters ChatOpsFilters
+	fields := strings.Fields(text)
+	if len(fields) > 0 && (fields[0] == "/argazer" || fields[0] == "argazer") {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 || fields[0] == "help" {
+		return filters, errors.New(chatOpsUsage)
+	}
+	if fields[0] != "scan" {
+		return filters, fmt.Errorf("Unknown command %q\n%s", fields[0], chatOpsUsage)
+	}
+
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || value == "" {
+			return filters, fmt.Errorf("Invalid filter %q\n%s", field, chatOpsUsage)
+		}
+		values := strings.Split(value, ",")
+		switch key {
+		case "project", "projects":
+			filters.Projects = append(filters.Projects, values...)
+		case "app", "apps":
+			filters.AppNames = append(filters.AppNames, values...)
+		default:
+			return filters, fmt.Errorf("Unknown filter %q\n%s", key, chatOpsUsage)
+		}
+	}
+	return filters, nil
+}
+
+// writeChatOpsReply writes a reply as JSON
+func writeChatOpsReply(w http.ResponseWriter, reply chatOpsReply) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(reply)
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newChatOpsTestHandler returns a handler whose scans record their filters and return "summary"
+func newChatOpsTestHandler(t *testing.T, cfg ChatOpsConfig) (*ChatOpsHandler, *[]ChatOpsFilters) {
+	t.Helper()
+	var scans []ChatOpsFilters
+	scan := func(ctx context.Context, filters ChatOpsFilters) (string, error) {
+		scans = append(scans, filters)
+		if len(filters.Projects) > 0 && filters.Projects[0] == "broken" {
+			return "", errors.New("connection refused")
+		}
+		return "summary", nil
+	}
+	return NewChatOpsHandler(scan, cfg, logrus.NewEntry(logrus.New())), &scans
+}
+
+// decodeChatOpsReply decodes the JSON reply of a request
+func decodeChatOpsReply(t *testing.T, rec *httptest.ResponseRecorder) chatOpsReply {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rec.Code)
+	var reply chatOpsReply
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&reply))
+	return reply
+}
+
+func TestParseChatOpsCommand(t *testing.T) {
+	filters, err := parseChatOpsCommand("/argazer scan project=payments,billing app=api")
+	require.NoError(t, err)
+	assert.Equal(t, ChatOpsFilters{Projects: []string{"payments", "billing"}, AppNames: []string{"api"}}, filters)
+	assert.Equal(t, "project=payments,billing app=api", filters.String())
+
+	filters, err = parseChatOpsCommand("scan")
+	require.NoError(t, err)
+	assert.Equal(t, "all applications", filters.String())
+
+	_, err = parseChatOpsCommand("help")
+	assert.EqualError(t, err, chatOpsUsage)
+	_, err = parseChatOpsCommand("deploy")
+	assert.ErrorContains(t, err, `Unknown command "deploy"`)
+	_, err = parseChatOpsCommand("scan chart=nginx")
+	assert.ErrorContains(t, err, `Unknown filter "chart"`)
+	_, err = parseChatOpsCommand("scan project=")
+	assert.ErrorContains(t, err, `Invalid filter "project="`)
+}
+
+func TestChatOpsHandler_Token(t *testing.T) {
+	handler, scans := newChatOpsTestHandler(t, ChatOpsConfig{Token: "secret"})
+
+	request := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/chatops", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusForbidden, request("wrong", `{"text":"scan"}`).Code)
+	assert.Empty(t, *scans)
+
+	reply := decodeChatOpsReply(t, request("secret", `{"text":"/argazer scan project=payments"}`))
+	assert.Equal(t, chatOpsReply{ResponseType: "in_channel", Text: "summary"}, reply)
+	assert.Equal(t, []ChatOpsFilters{{Projects: []string{"payments"}}}, *scans)
+
+	reply = decodeChatOpsReply(t, request("secret", `{"text":"scan project=broken"}`))
+	assert.Equal(t, chatOpsReply{ResponseType: "ephemeral", Text: "Scan of project=broken failed: connection refused"}, reply)
+
+	reply = decodeChatOpsReply(t, request("secret", `{"text":"status"}`))
+	assert.Equal(t, "ephemeral", reply.ResponseType)
+	assert.Contains(t, reply.Text, "Usage: /argazer scan")
+	assert.Len(t, *scans, 2)
+
+	handler.running.Store(true)
+	reply = decodeChatOpsReply(t, request("secret", `{"text":"scan"}`))
+	assert.Equal(t, "A scan is already running, try again when it completes", reply.Text)
+}
+
+func TestChatOpsHandler_SlackSlashCommand(t *testing.T) {
+	posted := make(chan chatOpsReply, 1)
+	responseServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reply chatOpsReply
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&reply))
+		posted <- reply
+	}))
+	defer responseServer.Close()
+
+	handler, scans := newChatOpsTestHandler(t, ChatOpsConfig{SlackSigningSecret: testSigningSecret})
+
+	form := url.Values{"command": {"/argazer"}, "text": {"scan app=api"}, "user_name": {"alice"}, "response_url": {responseServer.URL}}
+	body := form.Encode()
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/chatops", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(slackTimestampHeader, ts)
+	req.Header.Set(slackSignatureHeader, slackSignature(testSigningSecret, ts, []byte(body)))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, chatOpsReply{ResponseType: "ephemeral", Text: "Scanning app=api…"}, decodeChatOpsReply(t, rec))
+
+	select {
+	case reply := <-posted:
+		assert.Equal(t, chatOpsReply{ResponseType: "in_channel", Text: "summary"}, reply)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the summary wasn't posted to the response URL")
+	}
+	assert.Equal(t, []ChatOpsFilters{{AppNames: []string{"api"}}}, *scans)
+
+	// Without a valid signature, the request needs the token, which isn't configured
+	req = httptest.NewRequest(http.MethodPost, "/chatops", strings.NewReader(body))
+	req.Header.Set(slackTimestampHeader, ts)
+	req.Header.Set(slackSignatureHeader, "v0=invalid")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
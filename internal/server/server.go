@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take when stopping
+const shutdownTimeout = 10 * time.Second
+
+// Server is the HTTP server used in serve mode for callbacks and health checks
+type Server struct {
+	httpServer *http.Server
+	mux        *http.ServeMux
+	logger     *logrus.Entry
+}
+
+// New creates a server listening on address with a /healthz endpoint
+func New(address string, logger *logrus.Entry) *Server {
+	mux := http.NewServeMux()
+	s := &Server{
+		httpServer: &http.Server{
+			Addr:              address,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		mux:    mux,
+		logger: logger,
+	}
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	return s
+}
+
+// Handle registers a handler for the given pattern
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+	s.logger.WithField("pattern", pattern).Debug("Registered HTTP handler")
+}
+
+// Run serves requests until the context is cancelled, then shuts down gracefully
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.WithField("address", s.httpServer.Addr).Info("HTTP server listening")
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("HTTP server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	s.logger.Info("Shutting down HTTP server")
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down HTTP server: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"argazer/internal/notification"
+	"argazer/internal/state"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Headers and limits of Slack request signing
+const (
+	slackSignatureHeader = "X-Slack-Signature"
+	slackTimestampHeader = "X-Slack-Request-Timestamp"
+	slackSignatureMaxAge = 5 * time.Minute // Reject older requests to prevent replays
+)
+
+// SlackActionHandler handles Block Kit button presses sent to the Slack app's interactivity request URL
+type SlackActionHandler struct {
+	store         *state.Store
+	notifier      *notification.SlackNotifier
+	signingSecret string
+	logger        *logrus.Entry
+	now           func() time.Time
+}
+
+// NewSlackActionHandler creates a handler that records Ack/Snooze button presses in the state store
+// signingSecret is the Slack app's signing secret used to verify requests
+func NewSlackActionHandler(store *state.Store, notifier *notification.SlackNotifier, signingSecret string, logger *logrus.Entry) *SlackActionHandler {
+	return &SlackActionHandler{
+		store:         store,
+		notifier:      notifier,
+		signingSecret: signingSecret,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *SlackActionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCallbackBody))
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if !verifySlackSignature(h.signingSecret, r.Header, body, h.now()) {
+		h.logger.Warn("Rejected Slack interaction with invalid signature")
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	// Interactions are form-encoded with the JSON in the "payload" field
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var interaction notification.SlackInteraction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &interaction); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if interaction.Type != "block_actions" || len(interaction.Actions) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	reply := applyAction(h.store, interaction.Actions[0].Value, interaction.User.DisplayName(), "slack", h.logger)
+
+	if interaction.ResponseURL != "" {
+		if err := h.notifier.Respond(r.Context(), interaction.ResponseURL, reply); err != nil {
+			h.logger.WithError(err).Warn("Failed to respond to Slack interaction")
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySlackSignature checks the v0 request signature computed over the timestamp and raw body
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) bool {
+	timestamp := header.Get(slackTimestampHeader)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	age := now.Sub(time.Unix(ts, 0))
+	if age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return false
+	}
+
+	return hmac.Equal([]byte(header.Get(slackSignatureHeader)), []byte(slackSignature(secret, timestamp, body)))
+}
+
+// slackSignature computes the v0 signature Slack sends in X-Slack-Signature
+func slackSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
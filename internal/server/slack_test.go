@@ -0,0 +1,127 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"argazer/internal/notification"
+	"argazer/internal/state"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSigningSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// newSlackTestHandler returns a handler and a fake response_url that records replies
+func newSlackTestHandler(t *testing.T) (*SlackActionHandler, *state.Store, string, *[]string) {
+	t.Helper()
+	logger := logrus.NewEntry(logrus.New())
+
+	var replies []string
+	responseServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		replies = append(replies, payload["text"].(string))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(responseServer.Close)
+
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "state.json"), logger)
+	require.NoError(t, err)
+
+	notifier := notification.NewSlackNotifier("https://hooks.slack.com/services/TEST", logger)
+	return NewSlackActionHandler(store, notifier, testSigningSecret, logger), store, responseServer.URL, &replies
+}
+
+// slackActionRequest builds a signed block_actions request for the given button value
+func slackActionRequest(value, responseURL string, timestamp time.Time, secret string) *http.Request {
+	payload := `{"type":"block_actions","user":{"id":"U1","username":"alice"},"actions":[{"action_id":"` + value + `","value":"` + value + `"}],"response_url":"` + responseURL + `"}`
+	body := "payload=" + url.QueryEscape(payload)
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/actions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(slackTimestampHeader, ts)
+	req.Header.Set(slackSignatureHeader, slackSignature(secret, ts, []byte(body)))
+	return req
+}
+
+func TestSlackActionHandler_Ack(t *testing.T) {
+	handler, store, responseURL, replies := newSlackTestHandler(t)
+	id, err := store.TrackNotification("app1", "2.0.0")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, slackActionRequest("ack:"+id, responseURL, time.Now(), testSigningSecret))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, store.IsAcknowledged("app1", "2.0.0", time.Now().Add(365*24*time.Hour)))
+	require.Len(t, *replies, 1)
+	assert.Equal(t, "Acknowledged app1 2.0.0", (*replies)[0])
+}
+
+func TestSlackActionHandler_Snooze(t *testing.T) {
+	handler, store, responseURL, replies := newSlackTestHandler(t)
+	id, err := store.TrackNotification("app1", "2.0.0")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, slackActionRequest("snooze:"+id, responseURL, time.Now(), testSigningSecret))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, store.IsAcknowledged("app1", "2.0.0", time.Now()))
+	assert.False(t, store.IsAcknowledged("app1", "2.0.0", time.Now().Add(SnoozeDuration+time.Hour)))
+	require.Len(t, *replies, 1)
+	assert.Contains(t, (*replies)[0], "Snoozed app1 2.0.0 until")
+}
+
+func TestSlackActionHandler_InvalidSignature(t *testing.T) {
+	handler, store, responseURL, replies := newSlackTestHandler(t)
+	id, err := store.TrackNotification("app1", "2.0.0")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, slackActionRequest("ack:"+id, responseURL, time.Now(), "wrong-secret"))
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.False(t, store.IsAcknowledged("app1", "2.0.0", time.Now()))
+	assert.Empty(t, *replies)
+}
+
+func TestSlackActionHandler_StaleTimestamp(t *testing.T) {
+	handler, store, responseURL, _ := newSlackTestHandler(t)
+	id, err := store.TrackNotification("app1", "2.0.0")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, slackActionRequest("ack:"+id, responseURL, time.Now().Add(-10*time.Minute), testSigningSecret))
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.False(t, store.IsAcknowledged("app1", "2.0.0", time.Now()))
+}
+
+func TestSlackActionHandler_MethodNotAllowed(t *testing.T) {
+	handler, _, _, _ := newSlackTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slack/actions", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestSlackSignature(t *testing.T) {
+	// Example from Slack's request verification documentation
+	body := "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+	assert.Equal(t,
+		"v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503",
+		slackSignature(testSigningSecret, "1531420618", []byte(body)))
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"argazer/internal/notification"
+	"argazer/internal/state"
+
+	"github.com/sirupsen/logrus"
+)
+
+// telegramSecretHeader carries the secret_token configured with Telegram's setWebhook
+const telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// maxCallbackBody limits the size of inbound webhook payloads
+const maxCallbackBody = 1 << 20
+
+// TelegramCallbackHandler handles inline keyboard callbacks from the Telegram Bot API
+type TelegramCallbackHandler struct {
+	store       *state.Store
+	notifier    *notification.TelegramNotifier
+	secretToken string
+	logger      *logrus.Entry
+}
+
+// NewTelegramCallbackHandler creates a handler that records Ack/Snooze button presses in the state store
+// secretToken must match the secret_token passed to setWebhook; an empty token disables the check
+func NewTelegramCallbackHandler(store *state.Store, notifier *notification.TelegramNotifier, secretToken string, logger *logrus.Entry) *TelegramCallbackHandler {
+	return &TelegramCallbackHandler{
+		store:       store,
+		notifier:    notifier,
+		secretToken: secretToken,
+		logger:      logger,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *TelegramCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.secretToken != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(telegramSecretHeader)), []byte(h.secretToken)) != 1 {
+		h.logger.Warn("Rejected Telegram callback with invalid secret token")
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var update notification.TelegramUpdate
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCallbackBody)).Decode(&update); err != nil {
+		http.Error(w, "invalid update", http.StatusBadRequest)
+		return
+	}
+
+	// Telegram sends every update type to the webhook; only button presses are relevant
+	if update.CallbackQuery == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	reply := h.handleCallback(update.CallbackQuery)
+
+	// Telegram retries on non-2xx responses, so failures are only reported to the user
+	if err := h.notifier.AnswerCallback(r.Context(), update.CallbackQuery.ID, reply); err != nil {
+		h.logger.WithError(err).Warn("Failed to answer Telegram callback")
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleCallback applies a button press and returns the text shown to the user
+func (h *TelegramCallbackHandler) handleCallback(query *notification.TelegramCallbackQuery) string {
+	return applyAction(h.store, query.Data, query.From.DisplayName(), "telegram", h.logger)
+}
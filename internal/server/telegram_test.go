@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"argazer/internal/notification"
+	"argazer/internal/state"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTelegramTestHandler returns a handler backed by a fake Telegram API that records callback answers
+func newTelegramTestHandler(t *testing.T, secret string) (*TelegramCallbackHandler, *state.Store, *[]string) {
+	t.Helper()
+	logger := logrus.NewEntry(logrus.New())
+
+	var answers []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		answers = append(answers, payload["text"])
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(api.Close)
+
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "state.json"), logger)
+	require.NoError(t, err)
+
+	notifier := notification.NewTelegramNotifier(api.URL+"/bot123/sendMessage", "12345", logger)
+	return NewTelegramCallbackHandler(store, notifier, secret, logger), store, &answers
+}
+
+func callbackRequest(data, secret string) *http.Request {
+	body := `{"update_id":1,"callback_query":{"id":"cb-1","from":{"id":7,"username":"alice"},"data":"` + data + `"}}`
+	req := httptest.NewRequest(http.MethodPost, "/telegram/callback", strings.NewReader(body))
+	if secret != "" {
+		req.Header.Set(telegramSecretHeader, secret)
+	}
+	return req
+}
+
+func TestTelegramCallbackHandler_Ack(t *testing.T) {
+	handler, store, answers := newTelegramTestHandler(t, "s3cret")
+	id, err := store.TrackNotification("app1", "2.0.0")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, callbackRequest("ack:"+id, "s3cret"))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, store.IsAcknowledged("app1", "2.0.0", time.Now().Add(365*24*time.Hour)))
+	require.Len(t, *answers, 1)
+	assert.Equal(t, "Acknowledged app1 2.0.0", (*answers)[0])
+}
+
+func TestTelegramCallbackHandler_Snooze(t *testing.T) {
+	handler, store, answers := newTelegramTestHandler(t, "")
+	id, err := store.TrackNotification("app1", "2.0.0")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, callbackRequest("snooze:"+id, ""))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, store.IsAcknowledged("app1", "2.0.0", time.Now()))
+	assert.False(t, store.IsAcknowledged("app1", "2.0.0", time.Now().Add(SnoozeDuration+time.Hour)))
+	require.Len(t, *answers, 1)
+	assert.Contains(t, (*answers)[0], "Snoozed app1 2.0.0 until")
+}
+
+func TestTelegramCallbackHandler_InvalidSecret(t *testing.T) {
+	handler, store, answers := newTelegramTestHandler(t, "s3cret")
+	id, err := store.TrackNotification("app1", "2.0.0")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, callbackRequest("ack:"+id, "wrong"))
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.False(t, store.IsAcknowledged("app1", "2.0.0", time.Now()))
+	assert.Empty(t, *answers)
+}
+
+func TestTelegramCallbackHandler_UnknownNotification(t *testing.T) {
+	handler, _, answers := newTelegramTestHandler(t, "")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, callbackRequest("ack:deadbeef", ""))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, *answers, 1)
+	assert.Equal(t, "This notification has expired", (*answers)[0])
+}
+
+func TestTelegramCallbackHandler_IgnoresOtherUpdates(t *testing.T) {
+	handler, _, answers := newTelegramTestHandler(t, "")
+
+	req := httptest.NewRequest(http.MethodPost, "/telegram/callback", strings.NewReader(`{"update_id":1,"message":{"text":"hi"}}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, *answers)
+}
+
+func TestTelegramCallbackHandler_MethodNotAllowed(t *testing.T) {
+	handler, _, _ := newTelegramTestHandler(t, "")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/telegram/callback", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestParseActionData(t *testing.T) {
+	tests := []struct {
+		data   string
+		action string
+		id     string
+		ok     bool
+	}{
+		{"ack:abc", ActionAck, "abc", true},
+		{"snooze:abc", ActionSnooze, "abc", true},
+		{"ack:", "", "", false},
+		{"delete:abc", "", "", false},
+		{"garbage", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			action, id, ok := parseActionData(tt.data)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.action, action)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+}
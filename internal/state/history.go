@@ -0,0 +1,117 @@
+package state
+
+import (
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// scanRetention is the number of scans kept in the history
+const scanRetention = 100
+
+// ScannedUpdate is an update available when a scan ran
+type ScannedUpdate struct {
+	AppName        string `json:"app_name"`
+	Namespace      string `json:"namespace,omitempty"`
+	Project        string `json:"project,omitempty"`
+	Instance       string `json:"instance,omitempty"` // ArgoCD instance, empty unless several are scanned
+	ChartName      string `json:"chart_name"`
+	CurrentVersion string `json:"current_version"`
+	LatestVersion  string `json:"latest_version"`
+}
+
+// appKey identifies the application of an update
+func (u ScannedUpdate) appKey() string {
+	if u.Instance != "" {
+		return u.Instance + "/" + u.Namespace + "/" + u.AppName
+	}
+	return u.Namespace + "/" + u.AppName
+}
+
+// Scan records the updates available when a scan ran
+type Scan struct {
+	At      time.Time       `json:"at"`
+	Updates []ScannedUpdate `json:"updates"`
+}
+
+// ScanDiff is the difference between two scans
+// An update is identified by its application and latest version, like acknowledgements: a newer
+// release of an application that already had an update is a new update.
+type ScanDiff struct {
+	New       []ScannedUpdate `json:"new"`
+	Unchanged []ScannedUpdate `json:"unchanged"`
+	Resolved  []ScannedUpdate `json:"resolved"` // Applications of the previous scan without any update now, e.g. upgraded
+}
+
+// RecordScan appends a scan to the history, keeping the latest scans
+func (s *Store) RecordScan(scan Scan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if scan.At.IsZero() {
+		scan.At = time.Now()
+	}
+	s.data.Scans = append(s.data.Scans, scan)
+	if len(s.data.Scans) > scanRetention {
+		s.data.Scans = slices.Clone(s.data.Scans[len(s.data.Scans)-scanRetention:])
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"updates": len(scan.Updates),
+		"scans":   len(s.data.Scans),
+	}).Debug("Recorded scan")
+
+	return s.save()
+}
+
+// Scans returns the recorded scans, oldest first
+func (s *Store) Scans() []Scan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.data.Scans)
+}
+
+// LastScan returns the most recent scan, if any
+func (s *Store) LastScan() (Scan, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.data.Scans) == 0 {
+		return Scan{}, false
+	}
+	return s.data.Scans[len(s.data.Scans)-1], true
+}
+
+// DiffScans compares a scan with the previous one
+func DiffScans(previous, current Scan) ScanDiff {
+	previousUpdates := make(map[string]bool, len(previous.Updates))
+	for _, update := range previous.Updates {
+		previousUpdates[updateKey(update.appKey(), update.LatestVersion)] = true
+	}
+	currentApps := make(map[string]bool, len(current.Updates))
+
+	var diff ScanDiff
+	for _, update := range current.Updates {
+		currentApps[update.appKey()] = true
+		if previousUpdates[updateKey(update.appKey(), update.LatestVersion)] {
+			diff.Unchanged = append(diff.Unchanged, update)
+		} else {
+			diff.New = append(diff.New, update)
+		}
+	}
+	for _, update := range previous.Updates {
+		if !currentApps[update.appKey()] {
+			diff.Resolved = append(diff.Resolved, update)
+		}
+	}
+	return diff
+}
+
+// IsNew reports whether the update of an application to a version is among the diff's new updates
+func (d ScanDiff) IsNew(instance, namespace, appName, version string) bool {
+	return slices.ContainsFunc(d.New, func(u ScannedUpdate) bool {
+		return u.Instance == instance && u.Namespace == namespace && u.AppName == appName && u.LatestVersion == version
+	})
+}
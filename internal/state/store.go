@@ -0,0 +1,189 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// notificationRetention is how long notification references are kept for callbacks
+const notificationRetention = 90 * 24 * time.Hour
+
+// Acknowledgement records that an update was acknowledged or snoozed
+type Acknowledgement struct {
+	AppName string    `json:"app_name"`
+	Version string    `json:"version"`          // Latest version that was acknowledged
+	By      string    `json:"by,omitempty"`     // Who acknowledged the update (e.g. Telegram username)
+	At      time.Time `json:"at"`               // When the update was acknowledged
+	Until   time.Time `json:"until,omitempty"`  // End of the snooze (zero for a permanent acknowledgement)
+	Source  string    `json:"source,omitempty"` // Where the acknowledgement came from (e.g. "telegram")
+}
+
+// NotifiedUpdate is an update referenced by an interactive notification
+type NotifiedUpdate struct {
+	AppName string    `json:"app_name"`
+	Version string    `json:"version"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// stateData is the on-disk representation of the state file
+type stateData struct {
+	Acknowledgements map[string]Acknowledgement `json:"acknowledgements"`
+	Notifications    map[string]NotifiedUpdate  `json:"notifications"`
+	Scans            []Scan                     `json:"scans,omitempty"` // Scan history, oldest first
+}
+
+// Store persists acknowledgements, notification references and the scan history in a JSON file
+type Store struct {
+	path   string
+	mu     sync.Mutex
+	data   stateData
+	logger *logrus.Entry
+}
+
+// NewStore opens the state file at path, creating an empty state if it doesn't exist
+func NewStore(path string, logger *logrus.Entry) (*Store, error) {
+	s := &Store{
+		path: path,
+		data: stateData{
+			Acknowledgements: make(map[string]Acknowledgement),
+			Notifications:    make(map[string]NotifiedUpdate),
+		},
+		logger: logger,
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			logger.WithField("path", path).Debug("State file not found, starting with empty state")
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if s.data.Acknowledgements == nil {
+		s.data.Acknowledgements = make(map[string]Acknowledgement)
+	}
+	if s.data.Notifications == nil {
+		s.data.Notifications = make(map[string]NotifiedUpdate)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"path":             path,
+		"acknowledgements": len(s.data.Acknowledgements),
+	}).Debug("Loaded state file")
+
+	return s, nil
+}
+
+// updateKey returns the key identifying an update of an application to a version
+func updateKey(appName, version string) string {
+	return appName + "@" + version
+}
+
+// UpdateID returns a short, stable identifier for an update
+// It fits in size-limited callback payloads such as Telegram's 64-byte callback_data
+func UpdateID(appName, version string) string {
+	sum := sha256.Sum256([]byte(updateKey(appName, version)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// TrackNotification records that an update was sent with interactive actions and returns its ID
+func (s *Store) TrackNotification(appName, version string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := UpdateID(appName, version)
+	s.data.Notifications[id] = NotifiedUpdate{
+		AppName: appName,
+		Version: version,
+		SentAt:  time.Now(),
+	}
+
+	return id, s.save()
+}
+
+// LookupNotification returns the update referenced by an ID from TrackNotification
+func (s *Store) LookupNotification(id string) (NotifiedUpdate, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update, ok := s.data.Notifications[id]
+	return update, ok
+}
+
+// Acknowledge records an acknowledgement for an update
+// A zero until acknowledges the version permanently; otherwise the update is snoozed until then.
+// A newer version of the application is notified again either way.
+func (s *Store) Acknowledge(ack Acknowledgement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ack.At.IsZero() {
+		ack.At = time.Now()
+	}
+	s.data.Acknowledgements[updateKey(ack.AppName, ack.Version)] = ack
+
+	s.logger.WithFields(logrus.Fields{
+		"app":     ack.AppName,
+		"version": ack.Version,
+		"by":      ack.By,
+		"until":   ack.Until,
+	}).Info("Recorded acknowledgement")
+
+	return s.save()
+}
+
+// IsAcknowledged reports whether an update is acknowledged or snoozed at the given time
+func (s *Store) IsAcknowledged(appName, version string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ack, ok := s.data.Acknowledgements[updateKey(appName, version)]
+	if !ok {
+		return false
+	}
+	return ack.Until.IsZero() || now.Before(ack.Until)
+}
+
+// save writes the state file atomically; callers must hold s.mu
+func (s *Store) save() error {
+	// Drop references that no callback will use anymore
+	cutoff := time.Now().Add(-notificationRetention)
+	for id, update := range s.data.Notifications {
+		if update.SentAt.Before(cutoff) {
+			delete(s.data.Notifications, id)
+		}
+	}
+
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create state directory: %w", err)
+		}
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+
+	return nil
+}
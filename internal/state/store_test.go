@@ -0,0 +1,169 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := NewStore(path, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	return store, path
+}
+
+func TestNewStore_MissingFile(t *testing.T) {
+	store, path := newTestStore(t)
+
+	assert.False(t, store.IsAcknowledged("app", "1.0.0", time.Now()))
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "state file should not be created until something is saved")
+}
+
+func TestNewStore_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0600))
+
+	_, err := NewStore(path, logrus.NewEntry(logrus.New()))
+	assert.Error(t, err)
+}
+
+func TestUpdateID(t *testing.T) {
+	id := UpdateID("app", "1.0.0")
+	assert.Len(t, id, 16)
+	assert.Equal(t, id, UpdateID("app", "1.0.0"))
+	assert.NotEqual(t, id, UpdateID("app", "1.0.1"))
+}
+
+func TestStore_TrackAndLookupNotification(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	id, err := store.TrackNotification("app", "2.0.0")
+	require.NoError(t, err)
+
+	update, ok := store.LookupNotification(id)
+	require.True(t, ok)
+	assert.Equal(t, "app", update.AppName)
+	assert.Equal(t, "2.0.0", update.Version)
+
+	_, ok = store.LookupNotification("unknown")
+	assert.False(t, ok)
+}
+
+func TestStore_Acknowledge(t *testing.T) {
+	store, _ := newTestStore(t)
+	now := time.Now()
+
+	require.NoError(t, store.Acknowledge(Acknowledgement{AppName: "app", Version: "2.0.0", By: "@alice"}))
+
+	assert.True(t, store.IsAcknowledged("app", "2.0.0", now))
+	assert.True(t, store.IsAcknowledged("app", "2.0.0", now.Add(365*24*time.Hour)), "permanent acknowledgement should not expire")
+	assert.False(t, store.IsAcknowledged("app", "2.1.0", now), "newer versions should still be notified")
+	assert.False(t, store.IsAcknowledged("other", "2.0.0", now))
+}
+
+func TestStore_Snooze(t *testing.T) {
+	store, _ := newTestStore(t)
+	now := time.Now()
+
+	require.NoError(t, store.Acknowledge(Acknowledgement{AppName: "app", Version: "2.0.0", Until: now.Add(time.Hour)}))
+
+	assert.True(t, store.IsAcknowledged("app", "2.0.0", now))
+	assert.False(t, store.IsAcknowledged("app", "2.0.0", now.Add(2*time.Hour)))
+}
+
+func TestStore_Persistence(t *testing.T) {
+	store, path := newTestStore(t)
+
+	id, err := store.TrackNotification("app", "2.0.0")
+	require.NoError(t, err)
+	require.NoError(t, store.Acknowledge(Acknowledgement{AppName: "app", Version: "2.0.0"}))
+
+	reopened, err := NewStore(path, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	assert.True(t, reopened.IsAcknowledged("app", "2.0.0", time.Now()))
+	_, ok := reopened.LookupNotification(id)
+	assert.True(t, ok)
+}
+
+func TestStore_PrunesOldNotifications(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	store.data.Notifications["old"] = NotifiedUpdate{AppName: "app", Version: "1.0.0", SentAt: time.Now().Add(-2 * notificationRetention)}
+	_, err := store.TrackNotification("app", "2.0.0")
+	require.NoError(t, err)
+
+	_, ok := store.LookupNotification("old")
+	assert.False(t, ok)
+}
+
+func TestStore_RecordScan(t *testing.T) {
+	store, path := newTestStore(t)
+
+	_, ok := store.LastScan()
+	assert.False(t, ok)
+
+	for i := 0; i < scanRetention+5; i++ {
+		require.NoError(t, store.RecordScan(Scan{
+			At:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour),
+			Updates: []ScannedUpdate{{AppName: "app", LatestVersion: "1.0.0"}},
+		}))
+	}
+
+	reloaded, err := NewStore(path, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	scans := reloaded.Scans()
+	require.Len(t, scans, scanRetention)
+	assert.Equal(t, time.Date(2025, 1, 1, 5, 0, 0, 0, time.UTC), scans[0].At, "oldest scans are dropped")
+
+	last, ok := reloaded.LastScan()
+	require.True(t, ok)
+	assert.Equal(t, scans[len(scans)-1].At, last.At)
+}
+
+func TestDiffScans(t *testing.T) {
+	previous := Scan{Updates: []ScannedUpdate{
+		{AppName: "frontend", Namespace: "argocd", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+		{AppName: "api", Namespace: "argocd", CurrentVersion: "2.0.0", LatestVersion: "2.1.0"},
+		{AppName: "worker", Namespace: "team-a", CurrentVersion: "0.1.0", LatestVersion: "0.2.0"},
+	}}
+	current := Scan{Updates: []ScannedUpdate{
+		{AppName: "frontend", Namespace: "argocd", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+		{AppName: "api", Namespace: "argocd", CurrentVersion: "2.0.0", LatestVersion: "2.2.0"},
+		{AppName: "redis", Namespace: "argocd", CurrentVersion: "17.0.0", LatestVersion: "18.0.0"},
+	}}
+
+	diff := DiffScans(previous, current)
+	assert.Equal(t, []ScannedUpdate{current.Updates[1], current.Updates[2]}, diff.New, "newer release and new application")
+	assert.Equal(t, []ScannedUpdate{current.Updates[0]}, diff.Unchanged)
+	assert.Equal(t, []ScannedUpdate{previous.Updates[2]}, diff.Resolved)
+
+	assert.True(t, diff.IsNew("", "argocd", "api", "2.2.0"))
+	assert.False(t, diff.IsNew("", "argocd", "frontend", "1.1.0"))
+
+	first := DiffScans(Scan{}, current)
+	assert.Len(t, first.New, 3, "every update is new in the first scan")
+}
+
+func TestDiffScans_Instances(t *testing.T) {
+	previous := Scan{Updates: []ScannedUpdate{
+		{AppName: "web", Namespace: "argocd", Instance: "production", LatestVersion: "1.1.0"},
+	}}
+	current := Scan{Updates: []ScannedUpdate{
+		{AppName: "web", Namespace: "argocd", Instance: "production", LatestVersion: "1.1.0"},
+		{AppName: "web", Namespace: "argocd", Instance: "staging", LatestVersion: "1.1.0"},
+	}}
+
+	diff := DiffScans(previous, current)
+	assert.Equal(t, []ScannedUpdate{current.Updates[1]}, diff.New, "applications of different instances are different applications")
+	assert.True(t, diff.IsNew("staging", "argocd", "web", "1.1.0"))
+	assert.False(t, diff.IsNew("production", "argocd", "web", "1.1.0"))
+}
@@ -0,0 +1,147 @@
+package syncwindow
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Window kinds
+const (
+	KindAllow = "allow"
+	KindDeny  = "deny"
+)
+
+// DefaultHorizon is how far ahead the next allowed window is searched for
+const DefaultHorizon = 7 * 24 * time.Hour
+
+// scheduleParser parses schedules like ArgoCD does: five fields, with month and day names
+var scheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// Window is an ArgoCD sync window that applies to an application
+type Window struct {
+	Kind     string // "allow" or "deny"
+	Schedule string // Cron expression of the window start
+	Duration string // How long the window stays open, e.g. "1h"
+	TimeZone string // Time zone of the schedule (default: UTC)
+}
+
+// State is the outcome of evaluating an application's sync windows
+type State struct {
+	Blocked     bool      // Automated syncs are not allowed now
+	BlockedBy   *Window   // Active deny window, nil when blocked because no allow window is active
+	NextAllowed time.Time // Start of the next allowed period; zero if none within the horizon
+}
+
+// interval is one occurrence of a window
+type interval struct {
+	window     *Window
+	start, end time.Time
+}
+
+// Evaluate determines whether automated syncs are allowed at now and, if not, when they next are
+// It follows ArgoCD's rules for automated syncs: an active deny window blocks, an active allow window
+// permits, and when allow windows exist but none is active, syncs are blocked.
+func Evaluate(windows []Window, now time.Time, horizon time.Duration) (State, error) {
+	if len(windows) == 0 {
+		return State{}, nil
+	}
+
+	hasAllow := false
+	var intervals []interval
+	for i := range windows {
+		w := &windows[i]
+		occurrences, err := w.occurrences(now, horizon)
+		if err != nil {
+			return State{}, err
+		}
+		intervals = append(intervals, occurrences...)
+		if w.Kind == KindAllow {
+			hasAllow = true
+		}
+	}
+
+	allowed, blockedBy := evaluateAt(intervals, hasAllow, now)
+	if allowed {
+		return State{}, nil
+	}
+	state := State{Blocked: true, BlockedBy: blockedBy}
+
+	// Syncs can only become allowed when an occurrence starts or ends
+	var candidates []time.Time
+	limit := now.Add(horizon)
+	for _, iv := range intervals {
+		for _, t := range []time.Time{iv.start, iv.end} {
+			if t.After(now) && !t.After(limit) {
+				candidates = append(candidates, t)
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+	for _, t := range candidates {
+		if ok, _ := evaluateAt(intervals, hasAllow, t); ok {
+			state.NextAllowed = t
+			break
+		}
+	}
+	return state, nil
+}
+
+// evaluateAt reports whether syncs are allowed at t, and the deny window blocking them if any
+func evaluateAt(intervals []interval, hasAllow bool, t time.Time) (bool, *Window) {
+	activeAllow := false
+	for _, iv := range intervals {
+		if t.Before(iv.start) || !t.Before(iv.end) {
+			continue
+		}
+		if iv.window.Kind == KindDeny {
+			return false, iv.window
+		}
+		if iv.window.Kind == KindAllow {
+			activeAllow = true
+		}
+	}
+	return activeAllow || !hasAllow, nil
+}
+
+// occurrences returns the window's occurrences that overlap [now, now+horizon]
+// Like ArgoCD, the schedule is evaluated in UTC shifted by the time zone's current offset.
+func (w *Window) occurrences(now time.Time, horizon time.Duration) ([]interval, error) {
+	sched, err := scheduleParser.Parse(w.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse schedule '%s': %w", w.Schedule, err)
+	}
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse duration '%s': %w", w.Duration, err)
+	}
+
+	loc := time.UTC
+	if w.TimeZone != "" {
+		if loc, err = time.LoadLocation(w.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone '%s': %w", w.TimeZone, err)
+		}
+	}
+	_, seconds := now.In(loc).Zone()
+	offset := time.Duration(seconds) * time.Second
+
+	// Start early enough to catch an occurrence that is already open at now
+	var result []interval
+	end := now.UTC().Add(offset + horizon)
+	for start := sched.Next(now.UTC().Add(offset - duration)); !start.IsZero() && !start.After(end); start = sched.Next(start) {
+		result = append(result, interval{window: w, start: start.Add(-offset), end: start.Add(duration - offset)})
+	}
+	return result, nil
+}
+
+// String describes the window, e.g. "deny 0 22 * * * (8h)"
+func (w Window) String() string {
+	s := fmt.Sprintf("%s %s (%s)", w.Kind, w.Schedule, w.Duration)
+	if w.TimeZone != "" {
+		s += " " + w.TimeZone
+	}
+	return s
+}
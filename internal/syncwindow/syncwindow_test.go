@@ -0,0 +1,155 @@
+package syncwindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	nightlyDeny := Window{Kind: KindDeny, Schedule: "0 22 * * *", Duration: "8h"}
+	morningAllow := Window{Kind: KindAllow, Schedule: "0 9 * * *", Duration: "2h"}
+
+	tests := []struct {
+		name        string
+		windows     []Window
+		now         string
+		blocked     bool
+		blockedBy   *Window
+		nextAllowed string
+	}{
+		{
+			name: "no windows",
+			now:  "2026-10-15T23:00:00Z",
+		},
+		{
+			name:    "outside deny window",
+			windows: []Window{nightlyDeny},
+			now:     "2026-10-15T12:00:00Z",
+		},
+		{
+			name:        "inside deny window",
+			windows:     []Window{nightlyDeny},
+			now:         "2026-10-15T23:00:00Z",
+			blocked:     true,
+			blockedBy:   &nightlyDeny,
+			nextAllowed: "2026-10-16T06:00:00Z",
+		},
+		{
+			name:        "deny window opened the day before",
+			windows:     []Window{nightlyDeny},
+			now:         "2026-10-16T05:59:00Z",
+			blocked:     true,
+			blockedBy:   &nightlyDeny,
+			nextAllowed: "2026-10-16T06:00:00Z",
+		},
+		{
+			name:    "inside allow window",
+			windows: []Window{morningAllow},
+			now:     "2026-10-15T10:00:00Z",
+		},
+		{
+			name:        "outside allow window",
+			windows:     []Window{morningAllow},
+			now:         "2026-10-15T12:00:00Z",
+			blocked:     true,
+			nextAllowed: "2026-10-16T09:00:00Z",
+		},
+		{
+			name:        "deny overrides allow",
+			windows:     []Window{morningAllow, {Kind: KindDeny, Schedule: "0 9 * * *", Duration: "1h"}},
+			now:         "2026-10-15T09:30:00Z",
+			blocked:     true,
+			blockedBy:   &Window{Kind: KindDeny, Schedule: "0 9 * * *", Duration: "1h"},
+			nextAllowed: "2026-10-15T10:00:00Z",
+		},
+		{
+			name:        "time zone",
+			windows:     []Window{{Kind: KindDeny, Schedule: "0 22 * * *", Duration: "8h", TimeZone: "Europe/Berlin"}},
+			now:         "2026-10-15T21:00:00Z", // 23:00 in Berlin (CEST)
+			blocked:     true,
+			blockedBy:   &Window{Kind: KindDeny, Schedule: "0 22 * * *", Duration: "8h", TimeZone: "Europe/Berlin"},
+			nextAllowed: "2026-10-16T04:00:00Z",
+		},
+		{
+			name:      "no allowed window within horizon",
+			windows:   []Window{{Kind: KindDeny, Schedule: "0 0 * * *", Duration: "24h"}},
+			now:       "2026-10-15T12:00:00Z",
+			blocked:   true,
+			blockedBy: &Window{Kind: KindDeny, Schedule: "0 0 * * *", Duration: "24h"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := Evaluate(tt.windows, mustParseTime(t, tt.now), DefaultHorizon)
+			require.NoError(t, err)
+			assert.Equal(t, tt.blocked, state.Blocked)
+			assert.Equal(t, tt.blockedBy, state.BlockedBy)
+			if tt.nextAllowed == "" {
+				assert.True(t, state.NextAllowed.IsZero(), "unexpected next allowed time %s", state.NextAllowed)
+			} else {
+				assert.True(t, mustParseTime(t, tt.nextAllowed).Equal(state.NextAllowed), "next allowed: got %s, want %s", state.NextAllowed, tt.nextAllowed)
+			}
+		})
+	}
+}
+
+func TestEvaluate_InvalidWindow(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	_, err := Evaluate([]Window{{Kind: KindDeny, Schedule: "bad", Duration: "1h"}}, now, DefaultHorizon)
+	assert.Error(t, err)
+
+	// ArgoCD rejects 7 for Sunday
+	_, err = Evaluate([]Window{{Kind: KindDeny, Schedule: "0 0 * * 7", Duration: "1h"}}, now, DefaultHorizon)
+	assert.Error(t, err)
+
+	_, err = Evaluate([]Window{{Kind: KindDeny, Schedule: "0 * * * *", Duration: "soon"}}, now, DefaultHorizon)
+	assert.Error(t, err)
+
+	_, err = Evaluate([]Window{{Kind: KindDeny, Schedule: "0 * * * *", Duration: "1h", TimeZone: "Mars/Olympus"}}, now, DefaultHorizon)
+	assert.Error(t, err)
+}
+
+func TestWindow_String(t *testing.T) {
+	assert.Equal(t, "deny 0 22 * * * (8h)", Window{Kind: KindDeny, Schedule: "0 22 * * *", Duration: "8h"}.String())
+	assert.Equal(t, "allow 0 9 * * 1-5 (2h) Europe/Berlin", Window{Kind: KindAllow, Schedule: "0 9 * * 1-5", Duration: "2h", TimeZone: "Europe/Berlin"}.String())
+}
+
+func TestWindow_Occurrences(t *testing.T) {
+	now := mustParseTime(t, "2026-10-15T12:00:00Z") // Thursday
+
+	tests := []struct {
+		name     string
+		schedule string
+		starts   []string
+	}{
+		{name: "day names", schedule: "30 8 * * FRI-SAT", starts: []string{"2026-10-16T08:30:00Z", "2026-10-17T08:30:00Z"}},
+		{name: "sunday", schedule: "0 0 * * sun", starts: []string{"2026-10-18T00:00:00Z"}},
+		{name: "day of month or day of week", schedule: "0 0 17 * mon", starts: []string{"2026-10-17T00:00:00Z", "2026-10-19T00:00:00Z"}},
+		{name: "month names", schedule: "0 0 * nov *", starts: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := Window{Kind: KindDeny, Schedule: tt.schedule, Duration: "1h"}
+			occurrences, err := w.occurrences(now, DefaultHorizon)
+			require.NoError(t, err)
+			var starts []string
+			for _, occurrence := range occurrences {
+				starts = append(starts, occurrence.start.Format(time.RFC3339))
+			}
+			assert.Equal(t, tt.starts, starts)
+		})
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	ts, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return ts
+}
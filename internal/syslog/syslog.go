@@ -0,0 +1,313 @@
+package syslog
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Writer defaults
+const (
+	defaultAppName = "argazer"
+	defaultTimeout = 10 * time.Second
+	nilValue       = "-"
+)
+
+// LocalAddress selects the local syslog daemon socket
+const LocalAddress = "local"
+
+// localSockets are the usual locations of the local syslog socket
+var localSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// facilities maps facility names to their RFC 5424 codes
+var facilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11, "ntp": 12, "security": 13, "console": 14,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// severities maps severity names to their RFC 5424 codes
+var severities = map[string]int{
+	"emerg": 0, "alert": 1, "crit": 2, "err": 3, "warning": 4, "notice": 5, "info": 6, "debug": 7,
+}
+
+// ParseFacility returns the code of a facility name such as "local0"
+func ParseFacility(name string) (int, error) {
+	code, ok := facilities[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown syslog facility %q", name)
+	}
+	return code, nil
+}
+
+// ParseSeverity returns the code of a severity name such as "notice"
+func ParseSeverity(name string) (int, error) {
+	code, ok := severities[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown syslog severity %q", name)
+	}
+	return code, nil
+}
+
+// Config holds the settings of a Writer
+type Config struct {
+	Address  string      // "local", udp://host:514, tcp://host:601, tls://host:6514 or unix:///path
+	Facility string      // Facility name (e.g. "local0")
+	Severity string      // Severity name (e.g. "notice")
+	AppName  string      // APP-NAME field (default: argazer)
+	TLS      *tls.Config // TLS settings for tls:// endpoints; nil uses the system defaults
+}
+
+// Param is a structured data parameter
+type Param struct {
+	Name  string
+	Value string
+}
+
+// Message is a single RFC 5424 message
+type Message struct {
+	MsgID   string  // MSGID field identifying the message type
+	SDID    string  // Structured data element ID (e.g. "argazer@32473"); empty omits structured data
+	Params  []Param // Structured data parameters
+	Text    string  // Free-form message
+	Created time.Time
+}
+
+// Writer sends RFC 5424 messages to a syslog endpoint
+// It connects per Write call, which suits infrequent notification traffic.
+type Writer struct {
+	network  string
+	address  string
+	useTLS   bool
+	tls      *tls.Config
+	priority int
+	appName  string
+	hostname string
+	logger   *logrus.Entry
+}
+
+// NewWriter creates a new writer
+func NewWriter(cfg Config, logger *logrus.Entry) (*Writer, error) {
+	facility, err := ParseFacility(cfg.Facility)
+	if err != nil {
+		return nil, err
+	}
+	severity, err := ParseSeverity(cfg.Severity)
+	if err != nil {
+		return nil, err
+	}
+
+	network, address, useTLS, err := parseAddress(cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	appName := cfg.AppName
+	if appName == "" {
+		appName = defaultAppName
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = nilValue
+	}
+
+	tlsConfig := cfg.TLS
+	if useTLS && tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return &Writer{
+		network:  network,
+		address:  address,
+		useTLS:   useTLS,
+		tls:      tlsConfig,
+		priority: facility*8 + severity,
+		appName:  appName,
+		hostname: hostname,
+		logger:   logger,
+	}, nil
+}
+
+// parseAddress returns the network and address to dial
+// An empty network means the local syslog socket, which is looked up when writing.
+func parseAddress(address string) (network, addr string, useTLS bool, err error) {
+	if address == "" {
+		return "", "", false, fmt.Errorf("syslog address is required")
+	}
+	if address == LocalAddress {
+		return "", "", false, nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid syslog address: %w", err)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "udp":
+		return "udp", hostPort(u, "514"), false, validateHost(u, address)
+	case "tcp":
+		return "tcp", hostPort(u, "601"), false, validateHost(u, address)
+	case "tls":
+		return "tcp", hostPort(u, "6514"), true, validateHost(u, address)
+	case "unix":
+		if u.Path == "" {
+			return "", "", false, fmt.Errorf("syslog address %q has no socket path", address)
+		}
+		return "unixgram", u.Path, false, nil
+	default:
+		return "", "", false, fmt.Errorf("unsupported syslog address %q (use %q, udp://, tcp://, tls:// or unix://)", address, LocalAddress)
+	}
+}
+
+func hostPort(u *url.URL, defaultPort string) string {
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func validateHost(u *url.URL, address string) error {
+	if u.Hostname() == "" {
+		return fmt.Errorf("syslog address %q has no host", address)
+	}
+	return nil
+}
+
+// Write sends the messages over a single connection
+func (w *Writer) Write(ctx context.Context, messages []Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	conn, err := w.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			w.logger.WithError(err).Debug("Failed to close syslog connection")
+		}
+	}()
+
+	deadline := time.Now().Add(defaultTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	// Stream transports need framing; datagrams carry exactly one message each (RFC 6587)
+	stream := w.network == "tcp"
+	for _, msg := range messages {
+		line := w.Format(msg)
+		if stream {
+			line = strconv.Itoa(len(line)) + " " + line
+		}
+		if _, err := conn.Write([]byte(line)); err != nil {
+			return fmt.Errorf("failed to write syslog message: %w", err)
+		}
+	}
+
+	w.logger.WithField("messages", len(messages)).Debug("Wrote syslog messages")
+	return nil
+}
+
+// dial connects to the configured endpoint or the first available local socket
+func (w *Writer) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: defaultTimeout}
+
+	if w.network == "" {
+		var lastErr error
+		for _, socket := range localSockets {
+			for _, network := range []string{"unixgram", "unix"} {
+				conn, err := dialer.DialContext(ctx, network, socket)
+				if err == nil {
+					return conn, nil
+				}
+				lastErr = err
+			}
+		}
+		return nil, fmt.Errorf("failed to connect to local syslog: %w", lastErr)
+	}
+
+	var conn net.Conn
+	var err error
+	if w.useTLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: w.tls}
+		conn, err = tlsDialer.DialContext(ctx, w.network, w.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, w.network, w.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog at %s: %w", w.address, err)
+	}
+	return conn, nil
+}
+
+// Format renders a message in the RFC 5424 format
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (w *Writer) Format(msg Message) string {
+	created := msg.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	structuredData := nilValue
+	if msg.SDID != "" {
+		var sd strings.Builder
+		sd.WriteString("[" + msg.SDID)
+		for _, param := range msg.Params {
+			sd.WriteString(" " + param.Name + `="` + escapeParamValue(param.Value) + `"`)
+		}
+		sd.WriteString("]")
+		structuredData = sd.String()
+	}
+
+	line := fmt.Sprintf("<%d>1 %s %s %s %d %s %s",
+		w.priority,
+		created.UTC().Format(time.RFC3339Nano),
+		headerField(w.hostname, 255),
+		headerField(w.appName, 48),
+		os.Getpid(),
+		headerField(msg.MsgID, 32),
+		structuredData,
+	)
+	if msg.Text != "" {
+		line += " " + msg.Text
+	}
+	return line
+}
+
+// headerField makes a value valid for a header field: printable ASCII without spaces, length-limited
+func headerField(value string, maxLen int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+	if value == "" {
+		return nilValue
+	}
+	if len(value) > maxLen {
+		value = value[:maxLen]
+	}
+	return value
+}
+
+// escapeParamValue escapes '"', '\' and ']' in structured data values
+func escapeParamValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
@@ -0,0 +1,177 @@
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFacilityAndSeverity(t *testing.T) {
+	facility, err := ParseFacility("LOCAL3")
+	require.NoError(t, err)
+	assert.Equal(t, 19, facility)
+
+	severity, err := ParseSeverity("warning")
+	require.NoError(t, err)
+	assert.Equal(t, 4, severity)
+
+	_, err = ParseFacility("local8")
+	assert.Error(t, err)
+	_, err = ParseSeverity("loud")
+	assert.Error(t, err)
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		network string
+		addr    string
+		useTLS  bool
+		wantErr bool
+	}{
+		{address: "local"},
+		{address: "udp://siem.example.com", network: "udp", addr: "siem.example.com:514"},
+		{address: "tcp://siem.example.com:1514", network: "tcp", addr: "siem.example.com:1514"},
+		{address: "tls://siem.example.com", network: "tcp", addr: "siem.example.com:6514", useTLS: true},
+		{address: "unix:///var/run/syslog.sock", network: "unixgram", addr: "/var/run/syslog.sock"},
+		{address: "", wantErr: true},
+		{address: "udp://", wantErr: true},
+		{address: "http://siem.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			network, addr, useTLS, err := parseAddress(tt.address)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.network, network)
+			assert.Equal(t, tt.addr, addr)
+			assert.Equal(t, tt.useTLS, useTLS)
+		})
+	}
+}
+
+func TestWriter_Format(t *testing.T) {
+	w, err := NewWriter(Config{Address: "udp://127.0.0.1", Facility: "local0", Severity: "notice"}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	w.hostname = "host-1"
+
+	line := w.Format(Message{
+		MsgID: "chart_update_available",
+		SDID:  "argazer@32473",
+		Params: []Param{
+			{Name: "app", Value: "frontend"},
+			{Name: "note", Value: `quote " backslash \ bracket ]`},
+		},
+		Text:    "Helm chart update available",
+		Created: time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC),
+	})
+
+	expected := fmt.Sprintf(`<133>1 2025-11-03T09:00:00Z host-1 argazer %d chart_update_available [argazer@32473 app="frontend" note="quote \" backslash \\ bracket \]"] Helm chart update available`, os.Getpid())
+	assert.Equal(t, expected, line)
+
+	// Missing fields use the nil value
+	line = w.Format(Message{Created: time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)})
+	assert.True(t, strings.HasSuffix(line, " - -"), line)
+}
+
+func TestWriter_WriteUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	w, err := NewWriter(Config{Address: "udp://" + conn.LocalAddr().String(), Facility: "auth", Severity: "info"}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(context.Background(), []Message{{MsgID: "one"}, {MsgID: "two"}}))
+
+	// Each datagram carries exactly one message
+	buf := make([]byte, 2048)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for _, msgID := range []string{"one", "two"} {
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(buf[:n]), "<38>1 "), string(buf[:n]))
+		assert.Contains(t, string(buf[:n]), " "+msgID+" ")
+	}
+}
+
+func TestWriter_WriteTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		c, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		data, _ := io.ReadAll(c)
+		received <- data
+	}()
+
+	w, err := NewWriter(Config{Address: "tcp://" + listener.Addr().String(), Facility: "local0", Severity: "notice"}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(context.Background(), []Message{{MsgID: "one", Text: "first"}, {MsgID: "two", Text: "second"}}))
+
+	var data []byte
+	select {
+	case data = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no data received")
+	}
+
+	// Messages are framed with octet counting: "<length> <message>"
+	var frames []string
+	r := bufio.NewReader(bytes.NewReader(data))
+	for {
+		lengthField, err := r.ReadString(' ')
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		length, err := strconv.Atoi(strings.TrimSuffix(lengthField, " "))
+		require.NoError(t, err)
+
+		frame := make([]byte, length)
+		_, err = io.ReadFull(r, frame)
+		require.NoError(t, err)
+		frames = append(frames, string(frame))
+	}
+
+	require.Len(t, frames, 2)
+	assert.True(t, strings.HasPrefix(frames[0], "<133>1 "), frames[0])
+	assert.True(t, strings.HasSuffix(frames[0], " one - first"), frames[0])
+	assert.True(t, strings.HasSuffix(frames[1], " two - second"), frames[1])
+}
+
+func TestNewWriter_InvalidConfig(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	_, err := NewWriter(Config{Address: "udp://127.0.0.1", Facility: "nope", Severity: "notice"}, logger)
+	assert.Error(t, err)
+
+	_, err = NewWriter(Config{Address: "udp://127.0.0.1", Facility: "local0", Severity: "nope"}, logger)
+	assert.Error(t, err)
+
+	_, err = NewWriter(Config{Address: "ftp://127.0.0.1", Facility: "local0", Severity: "notice"}, logger)
+	assert.Error(t, err)
+}
@@ -0,0 +1,166 @@
+// Package trivy scans container images for known vulnerabilities with the Trivy CLI
+package trivy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPath is the Trivy binary looked up in PATH
+const DefaultPath = "trivy"
+
+// Severities are the vulnerability severities reported by Trivy, from lowest to highest
+var Severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// ErrNotInstalled is returned when the Trivy binary can't be found
+var ErrNotInstalled = errors.New("trivy is not installed")
+
+// Vulnerability is a known vulnerability of an image
+type Vulnerability struct {
+	ID       string `json:"id"`                // CVE or advisory ID, e.g. "CVE-2024-1234"
+	Severity string `json:"severity"`          // Trivy severity, e.g. "HIGH"
+	Package  string `json:"package,omitempty"` // Affected package
+}
+
+// report is the part of Trivy's JSON report used here
+type report struct {
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID string `json:"VulnerabilityID"`
+			PkgName         string `json:"PkgName"`
+			Severity        string `json:"Severity"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// runner runs Trivy with arguments and returns its stdout
+type runner func(ctx context.Context, path string, args ...string) ([]byte, error)
+
+// Scanner scans images with the Trivy CLI, scanning each image once
+type Scanner struct {
+	path       string
+	severities []string
+	run        runner
+	logger     *logrus.Entry
+
+	mu    sync.Mutex
+	scans map[string]*scan
+}
+
+// scan is the outcome of an image scan, shared by concurrent lookups of the image
+type scan struct {
+	done            chan struct{}
+	vulnerabilities []Vulnerability
+	err             error
+}
+
+// NewScanner creates a scanner running the Trivy binary at path, reporting vulnerabilities of the
+// given severities (all of them when empty)
+// Registry credentials are Trivy's own: its docker config, or TRIVY_USERNAME/TRIVY_PASSWORD.
+func NewScanner(path string, severities []string, logger *logrus.Entry) *Scanner {
+	if path == "" {
+		path = DefaultPath
+	}
+	return &Scanner{
+		path:       path,
+		severities: severities,
+		run:        runCommand,
+		logger:     logger,
+		scans:      make(map[string]*scan),
+	}
+}
+
+// Scan returns the vulnerabilities of an image, sorted by ID
+func (s *Scanner) Scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	s.mu.Lock()
+	existing, ok := s.scans[image]
+	if !ok {
+		existing = &scan{done: make(chan struct{})}
+		s.scans[image] = existing
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		existing.vulnerabilities, existing.err = s.scan(ctx, image)
+		close(existing.done)
+	}
+
+	select {
+	case <-existing.done:
+		return existing.vulnerabilities, existing.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// scan runs Trivy on an image
+func (s *Scanner) scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	s.logger.WithField("image", image).Debug("Scanning image with Trivy")
+
+	args := []string{"image", "--quiet", "--format", "json", "--scanners", "vuln"}
+	if len(s.severities) > 0 {
+		args = append(args, "--severity", strings.Join(s.severities, ","))
+	}
+	args = append(args, image)
+
+	output, err := s.run(ctx, s.path, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", image, err)
+	}
+
+	var parsed report
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse Trivy report of %s: %w", image, err)
+	}
+
+	// The same vulnerability is reported for each affected package and layer
+	seen := make(map[string]bool)
+	var vulnerabilities []Vulnerability
+	for _, result := range parsed.Results {
+		for _, v := range result.Vulnerabilities {
+			if seen[v.VulnerabilityID] {
+				continue
+			}
+			seen[v.VulnerabilityID] = true
+			vulnerabilities = append(vulnerabilities, Vulnerability{ID: v.VulnerabilityID, Severity: v.Severity, Package: v.PkgName})
+		}
+	}
+	sort.Slice(vulnerabilities, func(i, j int) bool { return vulnerabilities[i].ID < vulnerabilities[j].ID })
+
+	s.logger.WithFields(logrus.Fields{
+		"image":           image,
+		"vulnerabilities": len(vulnerabilities),
+	}).Debug("Scanned image with Trivy")
+
+	return vulnerabilities, nil
+}
+
+// runCommand runs a command and returns its stdout, with stderr in the error of a failed run
+func runCommand(ctx context.Context, path string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, path, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s not found", ErrNotInstalled, path)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("trivy exited with code %d: %s", exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to run trivy: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,63 @@
+package trivy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_Scan(t *testing.T) {
+	var mu sync.Mutex
+	var calls [][]string
+	scanner := NewScanner("", []string{"HIGH", "CRITICAL"}, logrus.NewEntry(logrus.New()))
+	scanner.run = func(ctx context.Context, path string, args ...string) ([]byte, error) {
+		mu.Lock()
+		calls = append(calls, append([]string{path}, args...))
+		mu.Unlock()
+		if args[len(args)-1] == "broken:1.0" {
+			return nil, errors.New("trivy exited with code 1: unable to find the image")
+		}
+		return []byte(`{
+  "Results": [
+    {"Target": "nginx:1.25 (debian 12.4)", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-2024-2", "PkgName": "openssl", "Severity": "HIGH"},
+      {"VulnerabilityID": "CVE-2024-1", "PkgName": "libc6", "Severity": "CRITICAL"},
+      {"VulnerabilityID": "CVE-2024-2", "PkgName": "libssl3", "Severity": "HIGH"}
+    ]},
+    {"Target": "usr/bin/app", "Vulnerabilities": null}
+  ]
+}`), nil
+	}
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vulnerabilities, err := scanner.Scan(context.Background(), "nginx:1.25")
+			assert.NoError(t, err)
+			assert.Equal(t, []Vulnerability{
+				{ID: "CVE-2024-1", Severity: "CRITICAL", Package: "libc6"},
+				{ID: "CVE-2024-2", Severity: "HIGH", Package: "openssl"},
+			}, vulnerabilities)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, calls, 1, "each image is scanned once")
+	assert.Equal(t, []string{"trivy", "image", "--quiet", "--format", "json", "--scanners", "vuln", "--severity", "HIGH,CRITICAL", "nginx:1.25"}, calls[0])
+
+	_, err := scanner.Scan(context.Background(), "broken:1.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to scan broken:1.0: trivy exited with code 1")
+}
+
+func TestRunCommand_NotInstalled(t *testing.T) {
+	_, err := runCommand(context.Background(), "argazer-missing-trivy")
+	assert.ErrorIs(t, err, ErrNotInstalled)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// JUnit XML report elements, as understood by Jenkins, GitLab CI and other test report viewers
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Cases      []junitTestCase  `xml:"testcase"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Details string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// renderJUnit displays results as a JUnit XML report with one test suite per ArgoCD project (of each
+// instance with argocd_instances) and one test case per application
+// Available updates are failures and applications that couldn't be checked are errors, so CI report
+// viewers flag outdated charts; ignored updates are skipped and every other application passes.
+func renderJUnit(cat categorizedResults, w io.Writer) error {
+	suites := make(map[string]*junitTestSuite)
+	add := func(result ApplicationCheckResult, testCase junitTestCase) {
+		project := instanceProject(result)
+		suite, ok := suites[project]
+		if !ok {
+			suite = &junitTestSuite{Name: project}
+			suites[project] = suite
+		}
+		testCase.Name = result.AppName
+		if result.Namespace != "" {
+			testCase.Name = result.Namespace + "/" + result.AppName
+		}
+		testCase.ClassName = "argazer." + strings.ReplaceAll(project, "/", ".")
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	for _, result := range cat.updatesAvailable {
+		add(result, junitTestCase{Failure: &junitProblem{
+			Message: fmt.Sprintf("update available: %s %s -> %s", result.ChartName, result.CurrentVersion, result.LatestVersion),
+			Type:    "update",
+			Details: junitDetails(result),
+		}})
+	}
+	for _, result := range cat.errors {
+		errorType := result.ErrorCode
+		if errorType == "" {
+			errorType = "error"
+		}
+		add(result, junitTestCase{Error: &junitProblem{
+			Message: result.Error,
+			Type:    errorType,
+			Details: junitDetails(result),
+		}})
+	}
+	for _, result := range cat.ignored {
+		add(result, junitTestCase{
+			Skipped:   &junitSkipped{Message: "update ignored: " + result.IgnoredBy},
+			SystemOut: junitDetails(result),
+		})
+	}
+	passing := [][]ApplicationCheckResult{cat.upToDateWithConstraint, cat.upToDateNoConstraint, cat.relocated, cat.trackingBranch, cat.drifted}
+	for _, results := range passing {
+		for _, result := range results {
+			add(result, junitTestCase{SystemOut: junitDetails(result)})
+		}
+	}
+
+	report := junitTestSuites{Name: "argazer"}
+	for _, suite := range suites {
+		slices.SortFunc(suite.Cases, func(a, b junitTestCase) int { return cmp.Compare(a.Name, b.Name) })
+		for _, testCase := range suite.Cases {
+			suite.Tests++
+			switch {
+			case testCase.Failure != nil:
+				suite.Failures++
+			case testCase.Error != nil:
+				suite.Errors++
+			case testCase.Skipped != nil:
+				suite.Skipped++
+			}
+		}
+		if cat.truncation != nil {
+			// max_apps left applications out of the scan
+			suite.Properties = &junitProperties{Properties: []junitProperty{
+				{Name: "argazer.matched_applications", Value: fmt.Sprint(cat.truncation.Matched)},
+				{Name: "argazer.checked_applications", Value: fmt.Sprint(cat.truncation.Checked)},
+			}}
+		}
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+		report.Suites = append(report.Suites, *suite)
+	}
+	slices.SortFunc(report.Suites, func(a, b junitTestSuite) int { return cmp.Compare(a.Name, b.Name) })
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write JUnit XML: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode JUnit XML: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write JUnit XML: %w", err)
+	}
+	return nil
+}
+
+// junitDetails describes an application's chart and check outcome, one "key: value" per line
+func junitDetails(result ApplicationCheckResult) string {
+	var lines []string
+	field := func(key, value string) {
+		if value != "" {
+			lines = append(lines, key+": "+value)
+		}
+	}
+
+	field("chart", result.ChartName)
+	field("repository", result.RepoURL)
+	field("current version", result.CurrentVersion)
+	field("latest version", result.LatestVersion)
+	if result.HasUpdateOutsideConstraint {
+		field("latest version outside constraint", result.LatestVersionAll)
+	}
+	field("constraint", result.ConstraintApplied)
+	field("severity", result.Severity)
+	if result.SecurityUpdate {
+		field("security update", "yes")
+	}
+	field("relocated to", result.RelocatedTo)
+	field("tracking branch", result.TrackingBranch)
+	field("deployed version", result.DeployedVersion)
+	field("ignored until", result.IgnoredUntil)
+	field("url", result.URL)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderJUnit(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "frontend", Namespace: "team-a", Project: "web", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.2.0", HasUpdate: true, Severity: "minor"},
+		{AppName: "api", Project: "backend", ChartName: "postgresql", CurrentVersion: "12.0.0", LatestVersion: "12.0.0"},
+		{AppName: "broken", Project: "backend", ChartName: "redis", Error: "index fetch failed <status 500>", ErrorCode: "REPO_UNREACHABLE"},
+		{AppName: "legacy", Project: "backend", ChartName: "kafka", CurrentVersion: "20.0.0", LatestVersion: "26.0.0", IgnoredBy: "migration planned"},
+		{AppName: "", ChartName: "not-helm"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, outputResults(results, "junit", nil, &buf))
+	output := buf.String()
+	assert.True(t, strings.HasPrefix(output, xml.Header))
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+
+	assert.Equal(t, 4, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Suites, 2)
+
+	backend := report.Suites[0]
+	assert.Equal(t, "backend", backend.Name)
+	assert.Equal(t, 3, backend.Tests)
+	require.Len(t, backend.Cases, 3)
+	assert.Equal(t, []string{"api", "broken", "legacy"}, []string{backend.Cases[0].Name, backend.Cases[1].Name, backend.Cases[2].Name})
+	assert.Nil(t, backend.Cases[0].Failure)
+	require.NotNil(t, backend.Cases[1].Error)
+	assert.Equal(t, "REPO_UNREACHABLE", backend.Cases[1].Error.Type)
+	assert.Equal(t, "index fetch failed <status 500>", backend.Cases[1].Error.Message)
+	require.NotNil(t, backend.Cases[2].Skipped)
+	assert.Equal(t, "update ignored: migration planned", backend.Cases[2].Skipped.Message)
+
+	web := report.Suites[1]
+	require.Len(t, web.Cases, 1)
+	frontend := web.Cases[0]
+	assert.Equal(t, "team-a/frontend", frontend.Name)
+	assert.Equal(t, "argazer.web", frontend.ClassName)
+	require.NotNil(t, frontend.Failure)
+	assert.Equal(t, "update", frontend.Failure.Type)
+	assert.Equal(t, "update available: nginx 1.0.0 -> 1.2.0", frontend.Failure.Message)
+	assert.Contains(t, frontend.Failure.Details, "severity: minor")
+}
+
+func TestRenderJUnit_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, outputResults(nil, "junit", nil, &buf))
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "argazer", report.Name)
+	assert.Zero(t, report.Tests)
+	assert.Empty(t, report.Suites)
+}
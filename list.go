@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"argazer/internal/config"
+)
+
+// listedApplication is a Helm-based application a scan would check
+type listedApplication struct {
+	AppName   string `json:"app_name"`
+	Namespace string `json:"namespace,omitempty"`
+	Project   string `json:"project"`
+	Instance  string `json:"instance,omitempty"`
+	ChartName string `json:"chart_name"`
+	Version   string `json:"version"`
+	RepoURL   string `json:"repo_url"`
+	Source    string `json:"source"` // "source" for single-source applications, else the source's name or "sources[i]"
+}
+
+// newListCmd creates the list command
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the Helm-based applications a scan would check",
+		Long: `List prints the Helm-based applications matching the filters with the chart, version, repository
+and source a scan would check, without contacting any chart repository. Use it to verify the filters
+and which source of multi-source applications is picked (source_name, argazer.io/source-name) before
+a real scan.`,
+		Args: cobra.NoArgs,
+		RunE: runList,
+	}
+}
+
+// runList lists the applications from ArgoCD
+func runList(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Setup logging
+	logger, err := setupConfiguredLogging(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Set up context with signal handling for graceful shutdown
+	ctx, cancel := setupSignalHandler(logger)
+	defer cancel()
+
+	// Initialize clients
+	clients, err := initializeClients(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	var apps []*v1alpha1.Application
+	err = withTimeout(ctx, cfg.ArgocdTimeout, func(ctx context.Context) error {
+		var err error
+		apps, _, err = fetchApplications(ctx, clients, cfg, logger)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	listed, skipped := listApplications(apps, cfg.SourceName, logger)
+	return renderList(listed, skipped, cfg.OutputFormat, os.Stdout)
+}
+
+// listApplications returns the Helm source of each application, in the order of the applications,
+// and the number of applications without one
+func listApplications(apps []*v1alpha1.Application, sourceName string, logger *logrus.Entry) (listed []listedApplication, skipped int) {
+	for _, app := range apps {
+		source := findHelmSource(app, sourceName, logger.WithField("app_name", app.Name))
+		if source == nil {
+			skipped++
+			continue
+		}
+
+		chartName := source.Chart
+		if chartName == "" {
+			chartName = source.Path
+		}
+		listed = append(listed, listedApplication{
+			AppName:   app.Name,
+			Namespace: app.Namespace,
+			Project:   app.Spec.Project,
+			Instance:  applicationInstance(app),
+			ChartName: chartName,
+			Version:   source.TargetRevision,
+			RepoURL:   source.RepoURL,
+			Source:    sourceLabel(app, source),
+		})
+	}
+	return listed, skipped
+}
+
+// sourceLabel names the source of an application: "source" for single-source applications,
+// else the source's name, or its position in spec.sources when it has none
+func sourceLabel(app *v1alpha1.Application, source *v1alpha1.ApplicationSource) string {
+	for i := range app.Spec.Sources {
+		if &app.Spec.Sources[i] != source {
+			continue
+		}
+		if source.Name != "" {
+			return source.Name
+		}
+		return fmt.Sprintf("sources[%d]", i)
+	}
+	return "source"
+}
+
+// renderList writes the listed applications as JSON or as a table
+func renderList(listed []listedApplication, skipped int, format string, w io.Writer) error {
+	if format == config.OutputFormatJSON {
+		if listed == nil {
+			listed = []listedApplication{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(listed); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "APPLICATION\tPROJECT\tCHART\tVERSION\tREPOSITORY\tSOURCE")
+	for _, app := range listed {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", resultDisplayName(ApplicationCheckResult{AppName: app.AppName, Namespace: app.Namespace, Instance: app.Instance}),
+			app.Project, app.ChartName, app.Version, app.RepoURL, app.Source)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+
+	fmt.Fprintf(w, "\n%d Helm-based application(s), %d without a Helm source skipped\n", len(listed), skipped)
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"argazer/internal/config"
+)
+
+func TestListApplications(t *testing.T) {
+	apps := []*v1alpha1.Application{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "argocd"},
+			Spec: v1alpha1.ApplicationSpec{
+				Project: "web",
+				Source:  &v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com", Chart: "nginx", TargetRevision: "15.0.0"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "manifests"},
+			Spec: v1alpha1.ApplicationSpec{
+				Project: "web",
+				Source:  &v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/manifests", Path: "apps"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api"},
+			Spec: v1alpha1.ApplicationSpec{
+				Project: "backend",
+				Sources: []v1alpha1.ApplicationSource{
+					{RepoURL: "https://github.com/org/values", Ref: "values"},
+					{RepoURL: "ghcr.io/org/charts", Chart: "api", TargetRevision: "1.2.0"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+			Spec: v1alpha1.ApplicationSpec{
+				Project: "backend",
+				Sources: []v1alpha1.ApplicationSource{
+					{Name: "upstream", RepoURL: "https://charts.example.com", Chart: "worker", TargetRevision: "2.0.0"},
+					{Name: "fork", RepoURL: "https://github.com/org/charts.git", Path: "worker", TargetRevision: "main", Helm: &v1alpha1.ApplicationSourceHelm{}},
+				},
+			},
+		},
+	}
+
+	listed, skipped := listApplications(apps, "fork", logrus.NewEntry(logrus.New()))
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, []listedApplication{
+		{AppName: "nginx", Namespace: "argocd", Project: "web", ChartName: "nginx", Version: "15.0.0", RepoURL: "https://charts.example.com", Source: "source"},
+		{AppName: "api", Project: "backend", ChartName: "api", Version: "1.2.0", RepoURL: "ghcr.io/org/charts", Source: "sources[1]"},
+		{AppName: "worker", Project: "backend", ChartName: "worker", Version: "main", RepoURL: "https://github.com/org/charts.git", Source: "fork"},
+	}, listed)
+}
+
+func TestRenderList(t *testing.T) {
+	listed := []listedApplication{
+		{AppName: "nginx", Namespace: "argocd", Project: "web", ChartName: "nginx", Version: "15.0.0", RepoURL: "https://charts.example.com", Source: "source"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderList(listed, 2, config.OutputFormatTable, &buf))
+	assert.Regexp(t, `argocd/nginx\s+web\s+nginx\s+15.0.0\s+https://charts.example.com\s+source`, buf.String())
+	assert.Contains(t, buf.String(), "1 Helm-based application(s), 2 without a Helm source skipped")
+
+	buf.Reset()
+	require.NoError(t, renderList(nil, 0, config.OutputFormatJSON, &buf))
+	assert.Equal(t, "[]\n", buf.String())
+}
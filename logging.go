@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/config"
+)
+
+// logFilter drops log entries below their component's level and samples repetitive debug lines
+// Logrus has a single level, so it's set to the most verbose level in use and the filter drops the
+// rest when formatting.
+type logFilter struct {
+	logrus.Formatter
+	defaultLevel logrus.Level
+	levels       map[string]logrus.Level // Keyed by the entry's "component" field
+	burst        int                     // Identical debug lines formatted per interval (0 disables sampling)
+	interval     time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	samples map[string]*logSample
+}
+
+// logSample counts the occurrences of a debug line in the current sampling window
+type logSample struct {
+	start      time.Time
+	count      int
+	dropped    int // Dropped in the current window
+	suppressed int // Dropped in the previous window, reported on the next logged line
+}
+
+// newLogFilter creates a filter formatting entries with the given formatter
+func newLogFilter(formatter logrus.Formatter, defaultLevel logrus.Level, levels map[string]string, burst int, interval time.Duration) (*logFilter, error) {
+	f := &logFilter{
+		Formatter:    formatter,
+		defaultLevel: defaultLevel,
+		levels:       make(map[string]logrus.Level, len(levels)),
+		burst:        burst,
+		interval:     interval,
+		now:          time.Now,
+		samples:      make(map[string]*logSample),
+	}
+	for component, name := range levels {
+		level, err := logrus.ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level for %s: %w", component, err)
+		}
+		f.levels[component] = level
+	}
+	return f, nil
+}
+
+// maxLevel returns the most verbose level any component logs at
+func (f *logFilter) maxLevel() logrus.Level {
+	level := f.defaultLevel
+	for _, l := range f.levels {
+		level = max(level, l)
+	}
+	return level
+}
+
+// Format formats the entry, or returns nothing if it's filtered out
+func (f *logFilter) Format(entry *logrus.Entry) ([]byte, error) {
+	component, _ := entry.Data["component"].(string)
+	level, ok := f.levels[component]
+	if !ok {
+		level = f.defaultLevel
+	}
+	if entry.Level > level {
+		return nil, nil
+	}
+
+	if f.burst > 0 && entry.Level >= logrus.DebugLevel {
+		suppressed, keep := f.sample(component + "\x00" + entry.Message)
+		if !keep {
+			return nil, nil
+		}
+		if suppressed > 0 {
+			sampled := *entry
+			sampled.Data = maps.Clone(entry.Data)
+			sampled.Data["suppressed"] = suppressed
+			entry = &sampled
+		}
+	}
+
+	return f.Formatter.Format(entry)
+}
+
+// sample records an occurrence of a debug line and reports whether it's within the burst, along with
+// the number of occurrences dropped in the previous window
+func (f *logFilter) sample(key string) (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	s, ok := f.samples[key]
+	if !ok || now.Sub(s.start) >= f.interval {
+		next := &logSample{start: now}
+		if ok {
+			next.suppressed = s.dropped
+		}
+		s = next
+		f.samples[key] = s
+	}
+
+	s.count++
+	if s.count > f.burst {
+		s.dropped++
+		return 0, false
+	}
+	suppressed := s.suppressed
+	s.suppressed = 0
+	return suppressed, true
+}
+
+// setupConfiguredLogging configures logging with the configured format, component levels,
+// sampling and log file
+func setupConfiguredLogging(cfg *config.Config) (*logrus.Entry, error) {
+	logger := setupLogging(cfg.Verbose, cfg.LogFormat)
+
+	if cfg.LogFile != "" {
+		file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		logrus.SetOutput(file)
+	}
+
+	if len(cfg.LogLevels) == 0 && cfg.LogSampleBurst == 0 {
+		return logger, nil
+	}
+
+	filter, err := newLogFilter(logrus.StandardLogger().Formatter, logrus.GetLevel(), cfg.LogLevels, cfg.LogSampleBurst, cfg.LogSampleInterval)
+	if err != nil {
+		return nil, err
+	}
+	logrus.SetFormatter(filter)
+	logrus.SetLevel(filter.maxLevel())
+	return logger, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFilteredLogger returns a logger writing through the filter, and its output
+func newFilteredLogger(t *testing.T, filter *logFilter) (*logrus.Entry, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(filter)
+	logger.SetLevel(filter.maxLevel())
+	return logrus.NewEntry(logger), &buf
+}
+
+func TestLogFilter_ComponentLevels(t *testing.T) {
+	filter, err := newLogFilter(&logrus.TextFormatter{DisableTimestamp: true}, logrus.InfoLevel,
+		map[string]string{"helm": "debug", "argocd": "warn"}, 0, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, logrus.DebugLevel, filter.maxLevel())
+
+	logger, buf := newFilteredLogger(t, filter)
+	logger.WithField("component", "helm").Debug("helm debug")
+	logger.WithField("component", "argocd").Info("argocd info")
+	logger.WithField("component", "argocd").Warn("argocd warning")
+	logger.WithField("component", "oci").Debug("oci debug")
+	logger.WithField("component", "oci").Info("oci info")
+	logger.Debug("base debug")
+
+	output := buf.String()
+	assert.Contains(t, output, "helm debug")
+	assert.NotContains(t, output, "argocd info")
+	assert.Contains(t, output, "argocd warning")
+	assert.NotContains(t, output, "oci debug")
+	assert.Contains(t, output, "oci info")
+	assert.NotContains(t, output, "base debug")
+}
+
+func TestLogFilter_Sampling(t *testing.T) {
+	filter, err := newLogFilter(&logrus.TextFormatter{DisableTimestamp: true}, logrus.DebugLevel, nil, 2, time.Minute)
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter.now = func() time.Time { return now }
+
+	logger, buf := newFilteredLogger(t, filter)
+	helmLogger := logger.WithField("component", "helm")
+	for range 5 {
+		helmLogger.Debug("Fetching index")
+		helmLogger.Info("Checked chart")
+	}
+	logger.WithField("component", "git").Debug("Fetching index")
+
+	output := buf.String()
+	assert.Equal(t, 2, strings.Count(output, `msg="Fetching index" component=helm`), "debug lines beyond the burst are dropped")
+	assert.Equal(t, 5, strings.Count(output, "Checked chart"), "info lines are never sampled")
+	assert.Contains(t, output, `msg="Fetching index" component=git`, "components are sampled separately")
+
+	// The next window reports how many lines were dropped
+	now = now.Add(time.Minute)
+	buf.Reset()
+	helmLogger.Debug("Fetching index")
+	assert.Contains(t, buf.String(), "suppressed=3")
+}
+
+func TestNewLogFilter_InvalidLevel(t *testing.T) {
+	_, err := newLogFilter(&logrus.JSONFormatter{}, logrus.InfoLevel, map[string]string{"helm": "chatty"}, 0, time.Minute)
+	assert.Error(t, err)
+}
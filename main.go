@@ -1,15 +1,23 @@
 package main
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/sirupsen/logrus"
@@ -18,10 +26,20 @@ import (
 
 	cmdpkg "argazer/cmd"
 	"argazer/internal/argocd"
+	"argazer/internal/artifacthub"
 	"argazer/internal/auth"
 	"argazer/internal/config"
 	"argazer/internal/helm"
+	"argazer/internal/i18n"
+	"argazer/internal/kafka"
+	"argazer/internal/mqtt"
 	"argazer/internal/notification"
+	"argazer/internal/prcomment"
+	"argazer/internal/server"
+	"argazer/internal/state"
+	"argazer/internal/syncwindow"
+	"argazer/internal/syslog"
+	"argazer/internal/trivy"
 )
 
 var (
@@ -34,7 +52,7 @@ func main() {
 		Use:   "argazer",
 		Short: "ArgoCD Application Gazer - Monitor Helm chart versions in ArgoCD applications",
 		Long: `Argazer connects to ArgoCD via API and checks all applications for Helm chart updates.
-It can filter by projects, application names, and labels, and send notifications via Telegram, Email, Slack, Microsoft Teams, or generic webhooks.`,
+It can filter by projects, application names, and labels, and send notifications via Telegram, Email, Slack, Microsoft Teams, Discord, Google Chat, Webex, Opsgenie, Kafka, MQTT, or generic webhooks.`,
 		RunE: run,
 	}
 
@@ -50,31 +68,239 @@ It can filter by projects, application names, and labels, and send notifications
 	// Add configure command
 	rootCmd.AddCommand(cmdpkg.NewConfigureCmd())
 
-	// Add flags
-	rootCmd.Flags().StringP("config", "c", "", "Configuration file path")
-	rootCmd.Flags().String("argocd-url", "", "ArgoCD server URL")
-	rootCmd.Flags().String("argocd-username", "", "ArgoCD username")
-	rootCmd.Flags().String("argocd-password", "", "ArgoCD password")
-	rootCmd.Flags().Bool("argocd-insecure", false, "Skip TLS verification")
-	rootCmd.Flags().StringSlice("projects", []string{"*"}, "Projects to check (comma-separated, or '*' for all)")
-	rootCmd.Flags().StringSlice("app-names", []string{"*"}, "Application names to check (comma-separated, or '*' for all)")
-	rootCmd.Flags().String("notification-channel", "", "Notification channel: 'telegram', 'email', 'slack', 'teams', 'webhook', or empty for console only")
-	rootCmd.Flags().Int("concurrency", 10, "Number of concurrent workers for checking applications")
-	rootCmd.Flags().String("version-constraint", "major", "Version constraint: 'major' (all), 'minor' (same major), 'patch' (same major.minor)")
-	rootCmd.Flags().StringP("output-format", "o", "table", "Output format: 'table', 'json', or 'markdown'")
-	rootCmd.Flags().StringP("log-format", "l", "json", "Log format: 'json' or 'text'")
-	rootCmd.Flags().BoolP("verbose", "v", false, "Enable verbose logging")
+	// Add config command
+	rootCmd.AddCommand(cmdpkg.NewConfigCmd())
+
+	// Add auth command
+	rootCmd.AddCommand(cmdpkg.NewAuthCmd())
+
+	// Add serve command
+	rootCmd.AddCommand(newServeCmd())
+
+	// Add bench command
+	rootCmd.AddCommand(newBenchCmd())
+
+	// Add self-update command
+	rootCmd.AddCommand(newSelfUpdateCmd())
+
+	// Add update command
+	rootCmd.AddCommand(newUpdateCmd())
+
+	// Add diff command
+	rootCmd.AddCommand(newDiffCmd())
+
+	// Add tui command
+	rootCmd.AddCommand(newTUICmd())
+
+	// Add grafana command
+	rootCmd.AddCommand(newGrafanaCmd())
+
+	// Add check-connection command
+	rootCmd.AddCommand(newCheckConnectionCmd())
+
+	// Add check-app and check-chart commands
+	rootCmd.AddCommand(newCheckAppCmd())
+	rootCmd.AddCommand(newCheckChartCmd())
+
+	// Add list command
+	rootCmd.AddCommand(newListCmd())
+
+	// Add flags (persistent so that subcommands such as serve accept them too)
+	rootCmd.PersistentFlags().StringP("config", "c", "", "Configuration file path")
+	rootCmd.PersistentFlags().String("mode", config.ModeAPI, "How applications are read: 'api' (ArgoCD API) or 'kubernetes' (Application resources, in-cluster)")
+	rootCmd.PersistentFlags().String("argocd-url", "", "ArgoCD server URL")
+	rootCmd.PersistentFlags().String("argocd-username", "", "ArgoCD username")
+	rootCmd.PersistentFlags().String("argocd-password", "", "ArgoCD password")
+	rootCmd.PersistentFlags().Bool("argocd-insecure", false, "Skip TLS verification")
+	rootCmd.PersistentFlags().Bool("argocd-repo-credentials", false, "Reuse repository credentials stored in ArgoCD for chart lookups")
+	rootCmd.PersistentFlags().Bool("argocd-repositories", false, "Reuse the credentials of repositories connected in ArgoCD for chart lookups")
+	rootCmd.PersistentFlags().Bool("kubernetes-secret-credentials", false, "Read repository credentials from ArgoCD's Kubernetes secrets (in-cluster only)")
+	rootCmd.PersistentFlags().Bool("ecr-auth", false, "Obtain AWS ECR registry tokens with the AWS credential chain")
+	rootCmd.PersistentFlags().Bool("gcp-auth", false, "Obtain Google Artifact Registry and GCR tokens with Application Default Credentials")
+	rootCmd.PersistentFlags().Bool("azure-auth", false, "Obtain Azure Container Registry tokens with the Azure identity of the environment")
+	rootCmd.PersistentFlags().Bool("check-sync-windows", false, "Annotate updates blocked by an ArgoCD sync window with the next allowed window")
+	rootCmd.PersistentFlags().Bool("track-branches", false, "Report updates of branch-tracking Git applications whose branch tip has a newer chart version than the synced commit")
+	rootCmd.PersistentFlags().StringSlice("projects", []string{"*"}, "Projects to check (comma-separated names, globs like 'team-*' or regexes like '/^team-a-/', or '*' for all)")
+	rootCmd.PersistentFlags().StringSlice("app-names", []string{"*"}, "Application names to check (comma-separated names, globs or /regexes/, or '*' for all)")
+	rootCmd.PersistentFlags().StringSlice("exclude-projects", nil, "Projects not to check, applied after --projects (comma-separated names, globs or /regexes/)")
+	rootCmd.PersistentFlags().StringSlice("exclude-app-names", nil, "Application names not to check, applied after --app-names (comma-separated names, globs or /regexes/)")
+	rootCmd.PersistentFlags().StringSlice("app-namespaces", []string{"*"}, "Namespaces of the Applications to check (comma-separated, or '*' for all)")
+	rootCmd.PersistentFlags().StringSlice("sync-status", nil, "Only check applications with these sync statuses (comma-separated: Synced, OutOfSync, Unknown)")
+	rootCmd.PersistentFlags().StringSlice("health", nil, "Only check applications with these health statuses (comma-separated: Healthy, Progressing, Degraded, Suspended, Missing, Unknown)")
+	rootCmd.PersistentFlags().Bool("app-of-apps", false, "Also check the child Applications generated by app-of-apps applications")
+	rootCmd.PersistentFlags().StringSlice("excluded-tags", helm.DefaultExcludedTags, "Non-release tags ignored when looking for the latest version (comma-separated)")
+	rootCmd.PersistentFlags().StringArray("excluded-tag-patterns", nil, "Regular expression of tags ignored when looking for the latest version (repeatable)")
+	rootCmd.PersistentFlags().String("notification-channel", "", "Notification channel: 'telegram', 'email', 'slack', 'teams', 'discord', 'googlechat', 'webex', 'opsgenie', 'kafka', 'mqtt', 'webhook', or empty for console only")
+	rootCmd.PersistentFlags().String("notification-grouping", "none", "Notification grouping: 'none' (all updates together) or 'project' (one message per ArgoCD project)")
+	rootCmd.PersistentFlags().Int("concurrency", 10, "Number of concurrent workers for checking applications")
+	rootCmd.PersistentFlags().Int("max-apps", 0, "Check at most N applications after filtering, for smoke tests (0 = all)")
+	rootCmd.PersistentFlags().String("max-apps-mode", "first", "Applications kept by --max-apps: 'first' (by namespace and name) or 'sample' (deterministic sample)")
+	rootCmd.PersistentFlags().String("sample-seed", "", "Seed of the 'sample' selection; the same seed picks the same applications")
+	rootCmd.PersistentFlags().String("version-constraint", "major", "Version constraint: 'major' (all), 'minor' (same major), 'patch' (same major.minor)")
+	rootCmd.PersistentFlags().StringP("output-format", "o", "table", "Output format: 'table', 'json', 'markdown', 'markdown-compact', 'junit', or 'html'")
+	rootCmd.PersistentFlags().StringSlice("output-file", nil, "Also write the report to a file, in the format of its extension: .txt, .json, .md, .xml or .html (repeatable)")
+	rootCmd.PersistentFlags().String("group-by", "none", "Group the table, markdown and dashboard reports by 'project', 'repo', 'chart', 'severity' or 'cluster'")
+	rootCmd.PersistentFlags().String("sort-by", "app", "Sort the rows of the table report by 'app', 'project', 'chart', 'severity', 'current' or 'latest'")
+	rootCmd.PersistentFlags().StringSlice("columns", nil, "Columns of the table report, in order (app, project, chart, current, latest, severity, constraint, repo, cluster, link, note)")
+	rootCmd.PersistentFlags().Bool("json-compact", false, "With JSON output, print one line per application (NDJSON) instead of a single document")
+	rootCmd.PersistentFlags().Bool("progress", true, "Show a progress bar on stderr while scanning, with table output in a terminal")
+	rootCmd.PersistentFlags().String("language", "en", "Language of reports and notifications: 'en', 'de', 'fr' or 'es'")
+	rootCmd.PersistentFlags().String("pr-comment", "", "Post the report as a pull/merge request comment: 'github', 'gitlab', 'bitbucket', 'bitbucket-server', or empty to disable")
+	rootCmd.PersistentFlags().Int("github-pr-number", 0, "Pull request to comment on (default: detected in GitHub Actions)")
+	rootCmd.PersistentFlags().Int("gitlab-mr-iid", 0, "Merge request to comment on (default: detected in GitLab CI)")
+	rootCmd.PersistentFlags().Int("bitbucket-pr-id", 0, "Bitbucket pull request to comment on (default: detected in Bitbucket Pipelines)")
+	rootCmd.PersistentFlags().String("exit-code-mode", "simple", "Exit code mode: 'simple' (0 on success) or 'detailed' (2 = updates available, 4 = applications skipped, 6 = both)")
+	rootCmd.PersistentFlags().String("fail-on", "", "Scan outcome that exits non-zero: 'updates' or 'outside-constraint' (2), 'errors' (4), 'none', or updates at or above 'patch', 'minor', 'major' or 'security' (2)")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Deadline for the whole run, e.g. 10m (0 = none)")
+	rootCmd.PersistentFlags().Duration("argocd-timeout", 0, "Deadline for listing applications and sync windows from ArgoCD (0 = none)")
+	rootCmd.PersistentFlags().Duration("helm-timeout", 0, "Deadline for each chart lookup in a Helm repository (0 = none)")
+	rootCmd.PersistentFlags().Duration("oci-timeout", 0, "Deadline for each chart lookup in an OCI registry (0 = none)")
+	rootCmd.PersistentFlags().Duration("git-timeout", 0, "Deadline for each chart lookup in a Git repository (0 = none)")
+	rootCmd.PersistentFlags().Duration("notify-timeout", 0, "Deadline for each notification, event batch and pull request comment (0 = none)")
+	rootCmd.PersistentFlags().Int("circuit-breaker-threshold", 3, "Skip the remaining applications of a repository after this many consecutive failures to reach it (0 = never)")
+	rootCmd.PersistentFlags().String("proxy-url", "", "Outbound proxy for Helm repositories and OCI registries (default: HTTPS_PROXY/HTTP_PROXY)")
+	rootCmd.PersistentFlags().Int("registry-retry-attempts", 3, "Requests sent at most for index.yaml and OCI tags lists failing to reach the repository (1 = no retries)")
+	rootCmd.PersistentFlags().Duration("registry-retry-delay", time.Second, "Delay before the first retry of a registry request, doubled for each further one")
+	rootCmd.PersistentFlags().Duration("temp-dir-max-age", time.Hour, "Remove leftover Git clone directories older than this at startup (0 = keep them)")
+	rootCmd.PersistentFlags().Duration("index-cache-ttl", 5*time.Minute, "Reuse a Helm repository's index.yaml for this long across applications (0 = download it for each)")
+	rootCmd.PersistentFlags().String("cache-dir", "", "Keep Helm repository indexes in this directory across runs (default: memory only)")
+	rootCmd.PersistentFlags().Duration("cache-max-age", 7*24*time.Hour, "Remove indexes from the cache directory not refreshed for this long (0 = keep them)")
+	rootCmd.PersistentFlags().Int("cache-max-size", 512, "Megabytes the cache directory may hold before the least recently refreshed indexes are removed (0 = unlimited)")
+	rootCmd.PersistentFlags().Bool("release-notes", false, "Look up the release notes of each update in Artifact Hub annotations and GitHub/GitLab releases")
+	rootCmd.PersistentFlags().Bool("check-images", false, "Check the container images of applications for newer tags")
+	rootCmd.PersistentFlags().Bool("check-dependencies", false, "Check the subcharts of Git-based charts for newer versions")
+	rootCmd.PersistentFlags().Bool("check-values-diff", false, "Compare the default values of each update with the current chart version")
+	rootCmd.PersistentFlags().Bool("check-vulnerabilities", false, "Scan the images of each update's current and latest chart version with Trivy")
+	rootCmd.PersistentFlags().String("state-file", "argazer-state.json", "Path to the state file for acknowledgements and the scan history")
+	rootCmd.PersistentFlags().Bool("history", false, "Record the updates of each scan in the state file, for argazer diff")
+	rootCmd.PersistentFlags().Bool("notify-only-new", false, "Only notify updates that weren't available in the previous scan (records the history)")
+	rootCmd.PersistentFlags().Bool("redact", false, "Mask repository hostnames, URLs and project names in reports with stable hashes")
+	rootCmd.PersistentFlags().StringP("log-format", "l", "json", "Log format: 'json' or 'text'")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().StringToString("log-levels", nil, "Log level per component, e.g. helm=debug,argocd=warn (components: "+strings.Join(config.LogComponents, ", ")+")")
+	rootCmd.PersistentFlags().Int("log-sample-burst", 0, "Identical debug lines logged per component and interval before the rest are dropped (0 disables sampling)")
+	rootCmd.PersistentFlags().Duration("log-sample-interval", time.Minute, "Sampling window of --log-sample-burst")
+	rootCmd.PersistentFlags().String("log-file", "", "Write logs to this file instead of stderr")
 
 	// Bind flags to viper
-	if err := viper.BindPFlags(rootCmd.Flags()); err != nil {
+	if err := viper.BindPFlags(rootCmd.PersistentFlags()); err != nil {
+		logrus.WithError(err).Fatal("Failed to bind flags")
+	}
+	// Flags named differently from their key are bound to the key, an alias would move its
+	// environment variable to the flag name (AG_HEALTH instead of AG_HEALTH_STATUS)
+	if err := viper.BindPFlag("health_status", rootCmd.PersistentFlags().Lookup("health")); err != nil {
+		logrus.WithError(err).Fatal("Failed to bind flags")
+	}
+	if err := viper.BindPFlag("output_files", rootCmd.PersistentFlags().Lookup("output-file")); err != nil {
 		logrus.WithError(err).Fatal("Failed to bind flags")
 	}
 
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		logrus.Fatal(err)
 	}
 }
 
+// Exit codes of a completed scan in the detailed exit code mode or with fail-on
+// They are bit flags: a scan with both updates and skipped applications exits with 6.
+// Fatal errors exit with 1 in every mode.
+const (
+	exitCodeUpdates = 2
+	exitCodeSkipped = 4
+)
+
+// exitCodeError ends the program with an exit code that reports the scan outcome, not a failure
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", e.code)
+}
+
+// scanExitCode returns the detailed exit code of a completed scan
+func scanExitCode(results []ApplicationCheckResult) int {
+	code := 0
+	for _, result := range results {
+		if result.AppName == "" {
+			continue
+		}
+		if result.Error != "" {
+			code |= exitCodeSkipped
+		} else if result.HasUpdate {
+			code |= exitCodeUpdates
+		}
+	}
+	return code
+}
+
+// outcomeExitCode returns the exit code of a completed scan for the exit code mode and fail-on
+// Fail-on decides whether updates exit with 2 in both modes; "errors" and "none" also override
+// the mode for skipped applications.
+func outcomeExitCode(results []ApplicationCheckResult, mode, failOn string) int {
+	code := 0
+	if mode == config.ExitCodeModeDetailed {
+		code = scanExitCode(results)
+	}
+
+	switch failOn {
+	case "":
+	case config.FailOnNone:
+		code = 0
+	case config.FailOnErrors:
+		code = scanExitCode(results) & exitCodeSkipped
+	default:
+		code &^= exitCodeUpdates
+		if failOnUpdates(results, failOn) {
+			code |= exitCodeUpdates
+		}
+	}
+	return code
+}
+
+// failOnUpdates reports whether any update matches fail-on
+// Security updates match every severity threshold. Updates between non-semver versions match any
+// severity threshold, as their severity can't be ruled out. "outside-constraint" also matches
+// newer versions outside the version constraint, unless the update was ignored.
+func failOnUpdates(results []ApplicationCheckResult, threshold string) bool {
+	for _, result := range results {
+		if result.Error != "" {
+			continue
+		}
+		if threshold == config.FailOnOutsideConstraint && result.HasUpdateOutsideConstraint && result.IgnoredBy == "" {
+			return true
+		}
+		if !result.HasUpdate {
+			continue
+		}
+		if result.SecurityUpdate || threshold == config.FailOnUpdates || threshold == config.FailOnOutsideConstraint {
+			return true
+		}
+		if threshold == config.FailOnSecurity {
+			continue
+		}
+		severity := notification.UpdateSeverity(notification.ApplicationUpdate{CurrentVersion: result.CurrentVersion, LatestVersion: result.LatestVersion})
+		if severity == "" || notification.SeverityAtLeast(severity, threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// exitCodeResult returns the error that ends the run with the scan's exit code, or nil for 0
+// The command's error and usage output are silenced, as the exit code isn't a failure.
+func exitCodeResult(cmd *cobra.Command, code int) error {
+	if code == 0 {
+		return nil
+	}
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return &exitCodeError{code: code}
+}
+
 func run(cmd *cobra.Command, args []string) error {
 	// Load configuration
 	cfg, err := config.Load()
@@ -83,99 +309,259 @@ func run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Set up logging
-	logger := setupLogging(cfg.Verbose, cfg.LogFormat)
+	logger, err := setupConfiguredLogging(cfg)
+	if err != nil {
+		return err
+	}
 
 	logger.WithFields(logrus.Fields{
-		"argocd_url":   cfg.ArgocdURL,
-		"projects":     cfg.Projects,
-		"app_names":    cfg.AppNames,
-		"labels":       cfg.Labels,
-		"notification": cfg.NotificationChannel,
-		"version":      version,
+		"argocd_url":     cfg.ArgocdURL,
+		"projects":       cfg.Projects,
+		"app_names":      cfg.AppNames,
+		"app_namespaces": cfg.AppNamespaces,
+		"labels":         cfg.Labels,
+		"notification":   cfg.NotificationChannel,
+		"version":        version,
 	}).Info("Starting Argazer")
 
 	// Set up context with signal handling for graceful shutdown
 	ctx, cancel := setupSignalHandler(logger)
 	defer cancel()
 
+	// Bound the whole run, so CI jobs end predictably even when a repository hangs
+	if cfg.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancelTimeout()
+	}
+
 	// Initialize clients
 	clients, err := initializeClients(ctx, cfg, logger)
 	if err != nil {
 		return err
 	}
 
-	// Fetch applications from ArgoCD
-	apps, err := fetchApplications(ctx, clients.argocd, cfg, logger)
+	// Fetch applications from ArgoCD and check them for updates
+	results, truncation, err := scan(ctx, cfg, clients, terminalProgressBar(cfg), logger)
 	if err != nil {
 		return err
 	}
 
-	// Check applications for updates (with concurrency)
-	results := checkApplicationsConcurrently(ctx, apps, clients.helm, cfg, logger)
+	// Reports may be shared outside the organization, notifications stay internal
+	reportResults := results
+	if cfg.Redact {
+		reportResults = redactResults(results)
+	}
+
+	report := processResults(reportResults)
+	report.truncation = truncation
+	report.layout = reportLayoutFromConfig(cfg)
 
 	// Output results to console
-	if err := outputResults(results, cfg.OutputFormat, os.Stdout); err != nil {
+	if err := renderResults(report, cfg.OutputFormat, i18n.New(cfg.Language), os.Stdout); err != nil {
 		return fmt.Errorf("failed to output results: %w", err)
 	}
+	if err := writeOutputFiles(report, cfg.OutputFiles, i18n.New(cfg.Language), logger); err != nil {
+		return err
+	}
+
+	// Applications checked after the deadline are reported as skipped, notifying them would fail too
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("run timed out after %s", cfg.Timeout)
+	}
+
+	// Record the scan in the history; with notify_only_new, only updates new since the previous scan are notified
+	notifyResults := results
+	var newResults []ApplicationCheckResult
+	if cfg.RecordsHistory() {
+		store, err := state.NewStore(cfg.StateFile, logger.WithField("component", "state"))
+		if err != nil {
+			return fmt.Errorf("failed to open state file: %w", err)
+		}
+		notifyResults, newResults = trackHistory(store, cfg, results, logger)
+	}
 
 	// Send notifications if configured
 	if clients.notifier != nil {
-		if err := sendNotifications(ctx, clients.notifier, results, logger); err != nil {
+		opts := notifyOptionsFromConfig(cfg)
+		opts.templates = clients.templates
+		if err := withTimeout(ctx, cfg.NotifyTimeout, func(ctx context.Context) error {
+			return sendNotificationsWithOptions(ctx, clients.notifier, notifyResults, opts, logger)
+		}); err != nil {
 			logger.WithError(err).Warn("Failed to send notifications")
 		}
 	}
 
+	// Emit update events to syslog if configured
+	if clients.syslog != nil {
+		if err := withTimeout(ctx, cfg.NotifyTimeout, func(ctx context.Context) error {
+			return sendEvents(ctx, clients.syslog, notifyResults, logger)
+		}); err != nil {
+			logger.WithError(err).Warn("Failed to send syslog events")
+		}
+	}
+
+	// Annotate Grafana graphs with the updates new since the previous scan
+	if clients.grafana != nil {
+		if err := withTimeout(ctx, cfg.NotifyTimeout, func(ctx context.Context) error {
+			return sendEvents(ctx, clients.grafana, newResults, logger)
+		}); err != nil {
+			logger.WithError(err).Warn("Failed to send Grafana annotations")
+		}
+	}
+
+	// Post the report on the pull/merge request if configured
+	if clients.prComment != nil {
+		if err := withTimeout(ctx, cfg.NotifyTimeout, func(ctx context.Context) error {
+			return postPRComment(ctx, clients.prComment, report, i18n.New(cfg.Language))
+		}); err != nil {
+			logger.WithError(err).Warn("Failed to post pull request comment")
+		}
+	}
+
 	logger.WithField("total_checked", len(results)).Info("Argazer completed")
 
-	return nil
+	return exitCodeResult(cmd, outcomeExitCode(results, cfg.ExitCodeMode, cfg.FailOn))
+}
+
+// scan fetches applications from ArgoCD and checks them for updates (with concurrency)
+// A non-nil truncation reports that max_apps left applications out.
+func scan(ctx context.Context, cfg *config.Config, clients *clients, bar *progressBar, logger *logrus.Entry) ([]ApplicationCheckResult, *scanTruncation, error) {
+	clients.scanning.Lock()
+	defer clients.scanning.Unlock()
+
+	var apps []*v1alpha1.Application
+	var truncation *scanTruncation
+	err := withTimeout(ctx, cfg.ArgocdTimeout, func(ctx context.Context) error {
+		var err error
+		apps, truncation, err = fetchApplications(ctx, clients, cfg, logger)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A repository that was down in the previous serve cycle gets another chance
+	clients.helm.ResetCircuits()
+	var results []ApplicationCheckResult
+	if bar != nil {
+		bar.start(len(apps))
+		results = checkApplicationsWithProgress(ctx, apps, clients.helm, cfg, bar.report, logger)
+		bar.finish()
+	} else {
+		results = checkApplicationsConcurrently(ctx, apps, clients.helm, cfg, logger)
+	}
+	// Git clones are only shared within a scan, so serve cycles see new commits
+	clients.helm.ReleaseClones()
+	applyIgnoreRules(results, cfg.Ignore, time.Now(), logger)
+
+	// Defer updates that would land inside a deny window
+	if cfg.CheckSyncWindows {
+		// Failures to read windows are logged per project and leave the results unannotated
+		_ = withTimeout(ctx, cfg.ArgocdTimeout, func(ctx context.Context) error {
+			annotateSyncWindows(ctx, apps, results, clients.projectSyncWindows, time.Now(), logger)
+			return nil
+		})
+	}
+
+	// Container images are checked once their applications' constraints are known
+	if cfg.CheckImages {
+		checkImages(ctx, apps, results, clients.helm.GetLatestImageTag, cfg.Concurrency, logger.WithField("component", "images"))
+	}
+
+	// Subcharts of Git-based charts are read at the revision each application deploys
+	if cfg.CheckDependencies {
+		checkDependencies(ctx, results, clients.helm.GetChartDependencies, clients.helm.GetLatestDependencyVersion, cfg.Concurrency, logger.WithField("component", "dependencies"))
+	}
+
+	// Images of both chart versions of each update are scanned with Trivy
+	if cfg.CheckVulnerabilities {
+		scanner := trivy.NewScanner(cfg.TrivyPath, cfg.TrivySeverities, logger.WithField("component", "trivy"))
+		checkSecurityFixes(ctx, results, clients.helm.GetChartImages, scanner.Scan, cfg.Concurrency, logger.WithField("component", "vulnerabilities"))
+	}
+
+	// Attach deprecation status, security report and maintainers of public charts
+	if clients.artifactHub != nil {
+		enrichArtifactHub(ctx, results, clients.artifactHub.Lookup, cfg.Concurrency, logger.WithField("component", "artifacthub"))
+	}
+
+	return results, truncation, nil
 }
 
 // clients holds all initialized clients
 type clients struct {
-	argocd   *argocd.Client
-	helm     *helm.Checker
-	notifier notification.Notifier
+	instances   []*argocdInstance // ArgoCD clients of each instance: the argocd_instances, or argocd_url
+	helm        *helm.Checker
+	notifier    notification.Notifier
+	syslog      notification.EventNotifier
+	grafana     notification.EventNotifier // Annotates new updates, nil unless grafana_url is set
+	prComment   prcomment.Poster
+	templates   *notification.Templates // Notification templates of the channel, nil when none are configured
+	artifactHub *artifacthub.Client     // Metadata lookups of public charts, nil unless enrich includes artifacthub
+
+	// Scans share the Helm checker's circuits and Git clones, so serve cycles and chatops scans run one at a time
+	scanning sync.Mutex
 }
 
 // initializeClients creates all required clients (ArgoCD, Helm, Notifier)
-// Context is reserved for future use when client initialization becomes cancellable
-func initializeClients(_ context.Context, cfg *config.Config, logger *logrus.Entry) (*clients, error) {
+func initializeClients(ctx context.Context, cfg *config.Config, logger *logrus.Entry) (*clients, error) {
 	c := &clients{}
 
-	// Create authentication provider
-	authLogger := logger.WithField("component", "auth")
-
-	// Convert config auth to auth provider format
-	var configAuth []auth.ConfigAuth
-	for _, ra := range cfg.RepositoryAuth {
-		configAuth = append(configAuth, auth.ConfigAuth{
-			URL:      ra.URL,
-			Username: ra.Username,
-			Password: ra.Password,
-		})
-	}
-
-	authProvider, err := auth.NewProvider(configAuth, authLogger)
+	authProvider, err := newAuthProvider(cfg, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create auth provider: %w", err)
+		return nil, err
 	}
 
-	// Create ArgoCD API client
+	// Create the ArgoCD clients of each instance
 	argoLogger := logger.WithField("component", "argocd")
-	argoClient, err := argocd.NewClient(cfg.ArgocdURL, cfg.ArgocdUsername, cfg.ArgocdPassword, cfg.ArgocdInsecure, argoLogger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create ArgoCD client: %w", err)
+	names, instanceCfgs := instanceConfigs(cfg)
+	for i, name := range names {
+		instance, err := newArgocdInstance(name, instanceCfgs[i], argoLogger)
+		if err != nil {
+			if name != "" {
+				return nil, fmt.Errorf("ArgoCD instance %s: %w", name, err)
+			}
+			return nil, err
+		}
+		c.instances = append(c.instances, instance)
 	}
-	c.argocd = argoClient
 
-	// Create helm checker
-	helmLogger := logger.WithField("component", "helm")
-	helmChecker, err := helm.NewChecker(authProvider, helmLogger)
+	// Reuse repository credentials ArgoCD already has
+	// Project tokens usually can't read repository credentials, so only the account clients are used.
+	// A connected repository's own credentials are loaded before the credential templates, which they
+	// take precedence over.
+	if cfg.ArgocdRepositories || cfg.ArgocdRepoCredentials {
+		for _, instance := range c.instances {
+			instanceLogger := logger
+			if instance.name != "" {
+				instanceLogger = logger.WithField("instance", instance.name)
+			}
+			if instance.argocd == nil {
+				instanceLogger.Warn("argocd_repositories and argocd_repo_credentials require argocd_username and argocd_password, skipping")
+				continue
+			}
+			if cfg.ArgocdRepositories {
+				loadArgoCDCredentials(ctx, instance.argocd.ListRepositories, authProvider, cfg.ArgocdNamespace, instanceLogger)
+			}
+			if cfg.ArgocdRepoCredentials {
+				loadArgoCDCredentials(ctx, instance.argocd.ListRepositoryCredentials, authProvider, cfg.ArgocdNamespace, instanceLogger)
+			}
+		}
+	}
+	if cfg.KubernetesSecretCredentials {
+		loadKubernetesSecretCredentials(ctx, authProvider, cfg.ArgocdNamespace, logger)
+	}
+
+	helmChecker, err := newHelmChecker(cfg, authProvider, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create helm checker: %w", err)
+		return nil, err
 	}
 	c.helm = helmChecker
 
+	if slices.Contains(cfg.Enrich, config.EnrichArtifactHub) {
+		c.artifactHub = artifacthub.NewClient(cfg.ArtifactHubAPIURL, logger.WithField("component", "artifacthub"))
+	}
+
 	// Create notifier based on configuration
 	if cfg.NotificationChannel != "" {
 		notifierLogger := logger.WithField("component", "notifier")
@@ -186,7 +572,7 @@ func initializeClients(_ context.Context, cfg *config.Config, logger *logrus.Ent
 			notifier = notification.NewTelegramNotifier(cfg.TelegramWebhook, cfg.TelegramChatID, notifierLogger)
 			logger.Info("Using Telegram notifications")
 		case "email":
-			notifier = notification.NewEmailNotifier(
+			emailNotifier := notification.NewEmailNotifier(
 				cfg.EmailSmtpHost,
 				cfg.EmailSmtpPort,
 				cfg.EmailSmtpUsername,
@@ -196,63 +582,751 @@ func initializeClients(_ context.Context, cfg *config.Config, logger *logrus.Ent
 				cfg.EmailUseTLS,
 				notifierLogger,
 			)
+			emailNotifier.SetStyle(notificationStyle(cfg))
+			tlsConfig, err := emailTLSConfig(cfg)
+			if err != nil {
+				return nil, err
+			}
+			emailNotifier.SetTLS(cfg.EmailImplicitTLS || cfg.EmailSmtpPort == 465, tlsConfig)
+			notifier = emailNotifier
 			logger.Info("Using Email notifications")
 		case "slack":
-			notifier = notification.NewSlackNotifier(cfg.SlackWebhook, notifierLogger)
+			slackNotifier := notification.NewSlackNotifier(cfg.SlackWebhook, notifierLogger)
+			slackNotifier.SetStyle(notificationStyle(cfg))
+			notifier = slackNotifier
 			logger.Info("Using Slack notifications")
 		case "teams":
-			notifier = notification.NewTeamsNotifier(cfg.TeamsWebhook, notifierLogger)
+			teamsNotifier := notification.NewTeamsNotifier(cfg.TeamsWebhook, notifierLogger)
+			teamsNotifier.SetStyle(notificationStyle(cfg))
+			teamsNotifier.SetAdaptiveCards(cfg.TeamsCardFormat == config.TeamsCardFormatAdaptive)
+			notifier = teamsNotifier
 			logger.Info("Using Microsoft Teams notifications")
+		case "discord":
+			discordNotifier := notification.NewDiscordNotifier(cfg.DiscordWebhook, notifierLogger)
+			discordNotifier.SetStyle(notificationStyle(cfg))
+			notifier = discordNotifier
+			logger.Info("Using Discord notifications")
+		case "googlechat":
+			googleChatNotifier := notification.NewGoogleChatNotifier(cfg.GoogleChatWebhook, notifierLogger)
+			googleChatNotifier.SetStyle(notificationStyle(cfg))
+			notifier = googleChatNotifier
+			logger.Info("Using Google Chat notifications")
+		case "webex":
+			notifier = notification.NewWebexNotifier(cfg.WebexBotToken, cfg.WebexRoomID, notifierLogger)
+			logger.Info("Using Webex notifications")
+		case "opsgenie":
+			notifier = notification.NewOpsgenieNotifier(opsgenieConfig(cfg), notifierLogger)
+			logger.Info("Using Opsgenie notifications")
+		case "kafka":
+			kafkaNotifier, err := notification.NewKafkaNotifier(kafkaConfig(cfg), cfg.KafkaTopic, notifierLogger)
+			if err != nil {
+				return nil, err
+			}
+			notifier = kafkaNotifier
+			logger.Info("Using Kafka notifications")
+		case "mqtt":
+			mqttNotifier, err := notification.NewMQTTNotifier(mqttConfig(cfg), cfg.MQTTTopic, notifierLogger)
+			if err != nil {
+				return nil, err
+			}
+			notifier = mqttNotifier
+			logger.Info("Using MQTT notifications")
 		case "webhook":
-			notifier = notification.NewWebhookNotifier(cfg.WebhookURL, notifierLogger)
-			logger.Info("Using generic webhook notifications")
+			webhookNotifier := notification.NewWebhookNotifier(cfg.WebhookURL, notifierLogger)
+			for key, value := range cfg.WebhookHeaders {
+				webhookNotifier.SetHeader(key, value)
+			}
+			webhookNotifier.SetSigningSecret(cfg.WebhookSecret)
+			notifier = webhookNotifier
+			if cfg.WebhookPayloadFormat == config.WebhookPayloadStructured {
+				notifier = notification.NewStructuredWebhookNotifier(webhookNotifier)
+			}
+			logger.WithField("payload_format", cfg.WebhookPayloadFormat).Info("Using generic webhook notifications")
 		default:
 			logger.Warnf("Unknown notification channel: %s", cfg.NotificationChannel)
 		}
 
 		c.notifier = notifier
+
+		if tmpl := cfg.NotificationTemplateFor(cfg.NotificationChannel); tmpl.Subject != "" || tmpl.Body != "" {
+			templates, err := notification.LoadTemplates(tmpl.Subject, tmpl.Body)
+			if err != nil {
+				return nil, err
+			}
+			c.templates = templates
+			logger.WithFields(logrus.Fields{"subject": tmpl.Subject, "body": tmpl.Body}).Info("Using notification templates")
+		}
+	}
+
+	// Create syslog sink if configured
+	if cfg.SyslogAddress != "" {
+		syslogNotifier, err := notification.NewSyslogNotifier(syslog.Config{
+			Address:  cfg.SyslogAddress,
+			Facility: cfg.SyslogFacility,
+			Severity: cfg.SyslogSeverity,
+		}, logger.WithField("component", "syslog"))
+		if err != nil {
+			return nil, err
+		}
+		c.syslog = syslogNotifier
+		logger.WithField("address", cfg.SyslogAddress).Info("Emitting update events to syslog")
+	}
+
+	// Create Grafana annotation sink if configured
+	if cfg.GrafanaURL != "" {
+		c.grafana = notification.NewGrafanaNotifier(notification.GrafanaConfig{
+			URL:          cfg.GrafanaURL,
+			Token:        cfg.GrafanaToken,
+			DashboardUID: cfg.GrafanaDashboardUID,
+			Tags:         cfg.GrafanaTags,
+		}, logger.WithField("component", "grafana"))
+		logger.WithField("url", cfg.GrafanaURL).Info("Annotating new updates in Grafana")
+	}
+
+	// Create pull/merge request commenter if configured
+	if cfg.PRComment != "" {
+		poster, err := newPRCommentPoster(cfg, logger.WithField("component", "pr-comment"))
+		if err != nil {
+			return nil, err
+		}
+		c.prComment = poster
+		logger.WithField("target", cfg.PRComment).Info("Posting the report as a pull/merge request comment")
 	}
 
 	return c, nil
 }
 
-// fetchApplications retrieves applications from ArgoCD based on filters
-func fetchApplications(ctx context.Context, client *argocd.Client, cfg *config.Config, logger *logrus.Entry) ([]*v1alpha1.Application, error) {
-	apps, err := client.ListApplications(ctx, argocd.FilterOptions{
-		Projects: cfg.Projects,
-		AppNames: cfg.AppNames,
-		Labels:   cfg.Labels,
+// newAuthProvider creates the provider of repository credentials: the configured ones, the local Helm and
+// Docker configuration and the cloud registry token sources that are enabled
+func newAuthProvider(cfg *config.Config, logger *logrus.Entry) (*auth.Provider, error) {
+	// Create authentication provider
+	authLogger := logger.WithField("component", "auth")
+
+	// Convert config auth to auth provider format
+	var configAuth []auth.ConfigAuth
+	for _, ra := range cfg.RepositoryAuth {
+		configAuth = append(configAuth, auth.ConfigAuth{
+			URL:              ra.URL,
+			Username:         ra.Username,
+			Password:         ra.Password,
+			SSHKey:           ra.SSHKey,
+			SSHKeyPassphrase: ra.SSHKeyPassphrase,
+			SSHAgent:         ra.SSHAgent,
+		})
+	}
+	for _, repository := range cfg.Repositories {
+		if repository.Username != "" || repository.Password != "" {
+			configAuth = append(configAuth, auth.ConfigAuth{
+				URL:      repository.URL,
+				Username: repository.Username,
+				Password: repository.Password,
+			})
+		}
+	}
+
+	authProvider, err := auth.NewProvider(configAuth, authLogger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth provider: %w", err)
+	}
+
+	// Reuse repositories already added with `helm repo add`
+	if cfg.UseHelmConfig {
+		if err := authProvider.LoadHelmRepositoryConfig(cfg.HelmRepositoryConfig); err != nil {
+			logger.WithError(err).Warn("Failed to load Helm repository configuration")
+		}
+	}
+
+	// Reuse registry credentials of CI runners and mounted pull secrets
+	if cfg.UseDockerConfig {
+		if err := authProvider.LoadDockerConfig(cfg.DockerConfigFiles); err != nil {
+			logger.WithError(err).Warn("Failed to load Docker registry credentials")
+		}
+	}
+
+	// Obtain ECR tokens with the pod's or runner's AWS identity
+	if cfg.ECRAuth {
+		authProvider.EnableECR()
+	}
+
+	// Obtain Google access tokens with Application Default Credentials or workload identity
+	if cfg.GCPAuth {
+		authProvider.EnableGCP()
+	}
+
+	// Exchange Azure identity tokens for ACR refresh tokens
+	if cfg.AzureAuth {
+		if err := authProvider.EnableAzure(); err != nil {
+			logger.WithError(err).Warn("Failed to enable Azure Container Registry authentication")
+		}
+	}
+
+	return authProvider, nil
+}
+
+// newHelmChecker creates the Helm checker with the configured timeouts, tag exclusions, retries,
+// repository connections and caches
+func newHelmChecker(cfg *config.Config, authProvider *auth.Provider, logger *logrus.Entry) (*helm.Checker, error) {
+	// Create helm checker
+	helmLogger := logger.WithField("component", "helm")
+	helmChecker, err := helm.NewChecker(authProvider, helmLogger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create helm checker: %w", err)
+	}
+	helmChecker.SetTimeouts(cfg.HelmTimeout, cfg.OCITimeout, cfg.GitTimeout)
+	exclusions, err := tagExclusions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	helmChecker.SetTagExclusions(exclusions)
+	helmChecker.SetCircuitBreaker(cfg.CircuitBreakerThreshold)
+	helmChecker.SetRetries(cfg.RegistryRetryAttempts, cfg.RegistryRetryDelay)
+	repositoryTLS := make([]helm.RepositoryTLS, 0, len(cfg.RepositoryTLS))
+	for _, repository := range cfg.RepositoryTLS {
+		repositoryTLS = append(repositoryTLS, helm.RepositoryTLS{URL: repository.URL, CAFile: repository.CAFile, InsecureSkipVerify: repository.InsecureSkipVerify})
+	}
+	var overrides []helm.RepositoryOverride
+	for _, repository := range cfg.Repositories {
+		if repository.CAFile != "" || repository.InsecureSkipVerify {
+			repositoryTLS = append(repositoryTLS, helm.RepositoryTLS{URL: repository.URL, CAFile: repository.CAFile, InsecureSkipVerify: repository.InsecureSkipVerify})
+		}
+		overrides = append(overrides, helm.RepositoryOverride{URL: repository.URL, Timeout: repository.Timeout, Disabled: repository.Disabled})
+	}
+	if err := helmChecker.SetTransport(cfg.ProxyURL, repositoryTLS); err != nil {
+		return nil, fmt.Errorf("failed to configure repository connections: %w", err)
+	}
+	helmChecker.SetRepositoryOverrides(overrides)
+	if err := helmChecker.SetIndexCache(cfg.IndexCacheTTL, cfg.CacheDir); err != nil {
+		return nil, err
+	}
+	helmChecker.SetIndexCacheLimits(cfg.CacheMaxAge, int64(cfg.CacheMaxSize)<<20)
+	if cfg.ReleaseNotes {
+		githubToken := cmp.Or(cfg.GitHubToken, os.Getenv("GITHUB_TOKEN"))
+		gitlabToken := cmp.Or(cfg.GitLabToken, os.Getenv("GITLAB_TOKEN"))
+		helmChecker.SetReleaseNotes(helm.NewReleaseNotesClient(githubToken, gitlabToken, cfg.ReleaseNotesMaxLength, helmLogger))
+	}
+	// Clones of crashed runs are never removed by their own deferred cleanup
+	if cfg.TempDirMaxAge > 0 {
+		if removed := helm.CleanupTempDirs(cfg.TempDirMaxAge, helmLogger); removed > 0 {
+			logger.WithField("count", removed).Info("Removed orphaned Git clone directories")
+		}
+	}
+
+	return helmChecker, nil
+}
+
+// newPRCommentPoster creates the pull/merge request commenter selected in the configuration
+func newPRCommentPoster(cfg *config.Config, logger *logrus.Entry) (prcomment.Poster, error) {
+	switch cfg.PRComment {
+	case config.PRCommentGitHub:
+		client, err := prcomment.NewGitHubClient(prcomment.GitHubConfigFromEnv(prcomment.GitHubConfig{
+			APIURL:      cfg.GitHubAPIURL,
+			Token:       cfg.GitHubToken,
+			Repository:  cfg.GitHubRepository,
+			PullRequest: cfg.GitHubPRNumber,
+		}), nil, logger)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case config.PRCommentGitLab:
+		client, err := prcomment.NewGitLabClient(prcomment.GitLabConfigFromEnv(prcomment.GitLabConfig{
+			APIURL:       cfg.GitLabAPIURL,
+			Token:        cfg.GitLabToken,
+			Project:      cfg.GitLabProject,
+			MergeRequest: cfg.GitLabMRIID,
+		}), nil, logger)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case config.PRCommentBitbucket:
+		client, err := prcomment.NewBitbucketCloudClient(bitbucketConfig(cfg), nil, logger)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case config.PRCommentBitbucketServer:
+		client, err := prcomment.NewBitbucketServerClient(bitbucketConfig(cfg), nil, logger)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unknown pr_comment target: %s", cfg.PRComment)
+	}
+}
+
+// tagExclusions builds the tag filters of chart lookups from the application config
+func tagExclusions(cfg *config.Config) (*helm.TagExclusions, error) {
+	defaultFilter, err := helm.NewTagFilter(cfg.ExcludedTags, cfg.ExcludedTagPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid excluded_tag_patterns: %w", err)
+	}
+
+	exclusions := helm.NewTagExclusions(defaultFilter)
+	for _, override := range cfg.RepositoryTagExclusions {
+		filter, err := helm.NewTagFilter(override.Tags, override.Patterns)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag exclusions for %s: %w", override.URL, err)
+		}
+		exclusions.SetRepositoryFilter(override.URL, filter)
+	}
+	return exclusions, nil
+}
+
+// kafkaConfig builds the Kafka producer configuration from the application config
+func kafkaConfig(cfg *config.Config) kafka.Config {
+	kc := kafka.Config{
+		Brokers:       cfg.KafkaBrokers,
+		SASLMechanism: cfg.KafkaSASLMechanism,
+		Username:      cfg.KafkaUsername,
+		Password:      cfg.KafkaPassword,
+	}
+	if cfg.KafkaTLS {
+		kc.TLS = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cfg.KafkaTLSInsecure,
+		}
+	}
+	return kc
+}
+
+// emailTLSConfig builds the TLS configuration of the SMTP connection, nil when the defaults apply
+// A CA bundle is trusted in addition to the system CAs, so internal relays can be verified.
+func emailTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	if !cfg.EmailTLSSkipVerify && cfg.EmailTLSCAFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.EmailSmtpHost,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.EmailTLSSkipVerify,
+	}
+	if cfg.EmailTLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.EmailTLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read email_tls_ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("email_tls_ca_file %s contains no PEM certificates", cfg.EmailTLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// opsgenieConfig builds the Opsgenie alert configuration from the application config
+func opsgenieConfig(cfg *config.Config) notification.OpsgenieConfig {
+	return notification.OpsgenieConfig{
+		APIURL:     cfg.OpsgenieAPIURL,
+		APIKey:     cfg.OpsgenieAPIKey,
+		Responders: cfg.OpsgenieResponders,
+		Tags:       cfg.OpsgenieTags,
+		Priorities: map[string]string{
+			notification.SeveritySecurity: cfg.OpsgeniePrioritySecurity,
+			notification.SeverityMajor:    cfg.OpsgeniePriorityMajor,
+			notification.SeverityMinor:    cfg.OpsgeniePriorityMinor,
+			notification.SeverityPatch:    cfg.OpsgeniePriorityPatch,
+		},
+	}
+}
+
+// mqttConfig builds the MQTT client configuration from the application config
+func mqttConfig(cfg *config.Config) mqtt.Config {
+	return mqtt.Config{
+		BrokerURL: cfg.MQTTBroker,
+		ClientID:  cfg.MQTTClientID,
+		Username:  cfg.MQTTUsername,
+		Password:  cfg.MQTTPassword,
+		TLS: &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cfg.MQTTTLSInsecure,
+		},
+		QoS:    byte(cfg.MQTTQoS),
+		Retain: cfg.MQTTRetain,
+	}
+}
+
+// loadArgoCDCredentials feeds repository credentials stored in ArgoCD, as listed by list, into the auth provider
+// Failures are logged and ignored so a missing RBAC permission doesn't abort the scan
+func loadArgoCDCredentials(ctx context.Context, list func(context.Context, string) ([]argocd.RepositoryCredential, error), authProvider *auth.Provider, namespace string, logger *logrus.Entry) {
+	creds, err := list(ctx, namespace)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to load repository credentials from ArgoCD")
+		return
+	}
+
+	added := 0
+	for _, cred := range creds {
+		if cred.Password == "" {
+			logger.WithField("url", cred.URL).Debug("ArgoCD did not expose a password for repository credentials, skipping")
+			continue
+		}
+		if authProvider.AddCredentials(cred.URL, cred.Username, cred.Password, "argocd") {
+			added++
+		}
+	}
+
+	logger.WithField("count", added).Info("Using repository credentials from ArgoCD")
+}
+
+// loadKubernetesSecretCredentials registers the repository credentials of ArgoCD's Secrets with the auth provider
+// Errors are logged rather than returned: chart lookups still work for public repositories.
+func loadKubernetesSecretCredentials(ctx context.Context, authProvider *auth.Provider, namespace string, logger *logrus.Entry) {
+	creds, err := argocd.ListSecretCredentials(ctx, namespace, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to load repository credentials from Kubernetes secrets")
+		return
+	}
+
+	added := 0
+	for _, cred := range creds {
+		if authProvider.AddCredentials(cred.URL, cred.Username, cred.Password, "kubernetes:"+cred.Secret) {
+			added++
+		}
+	}
+
+	logger.WithField("count", added).Info("Using repository credentials from Kubernetes secrets")
+}
+
+// projectSyncWindows returns the sync windows of a project of an instance using the client that can read it
+func (c *clients) projectSyncWindows(ctx context.Context, instance, project string) (v1alpha1.SyncWindows, error) {
+	client, err := c.forProject(instance, project)
+	if err != nil {
+		return nil, err
+	}
+	return client.ProjectSyncWindows(ctx, project)
+}
+
+// childApplications returns the child Applications of an app-of-apps application, read with the client
+// of its project and marked with its instance
+func (c *clients) childApplications(ctx context.Context, app *v1alpha1.Application) ([]*v1alpha1.Application, error) {
+	client, err := c.forApplication(app)
+	if err != nil {
+		return nil, err
+	}
+	children, err := client.ChildApplications(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		setApplicationInstance(child, applicationInstance(app))
+	}
+	return children, nil
+}
+
+// forApplication returns the ArgoCD client for an application, of the instance it was read from
+func (c *clients) forApplication(app *v1alpha1.Application) (*argocd.Client, error) {
+	return c.forProject(applicationInstance(app), app.Spec.Project)
+}
+
+// forProject returns the ArgoCD client for a project of an instance: its project-scoped client, or the
+// account client
+func (c *clients) forProject(instance, project string) (*argocd.Client, error) {
+	for _, i := range c.instances {
+		if i.name != instance {
+			continue
+		}
+		client := i.projectArgocd[project]
+		if client == nil {
+			client = i.argocd
+		}
+		if client != nil {
+			return client, nil
+		}
+	}
+	return nil, fmt.Errorf("no ArgoCD client for project %q", project)
+}
+
+// annotateSyncWindows marks available updates whose application is currently inside a deny window
+// (or outside all allow windows) with the next time automated syncs are allowed
+// Project windows are fetched once per project; projects that can't be read are logged and skipped.
+func annotateSyncWindows(ctx context.Context, apps []*v1alpha1.Application, results []ApplicationCheckResult, windowsFor func(ctx context.Context, instance, project string) (v1alpha1.SyncWindows, error), now time.Time, logger *logrus.Entry) {
+	appsByName := make(map[string]*v1alpha1.Application, len(apps))
+	for _, app := range apps {
+		appsByName[applicationKey(app)] = app
+	}
+
+	// Projects of different instances are different projects, even with the same name
+	projectWindows := make(map[string]v1alpha1.SyncWindows)
+	failedProjects := make(map[string]bool)
+
+	for i := range results {
+		result := &results[i]
+		projectKey := result.Instance + "/" + result.Project
+		if !result.HasUpdate || failedProjects[projectKey] {
+			continue
+		}
+		app, ok := appsByName[resultKey(*result)]
+		if !ok {
+			continue
+		}
+
+		windows, ok := projectWindows[projectKey]
+		if !ok {
+			var err error
+			windows, err = windowsFor(ctx, result.Instance, result.Project)
+			if err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{"instance": result.Instance, "project": result.Project}).Warn("Failed to load sync windows, not checking them for this project")
+				failedProjects[projectKey] = true
+				continue
+			}
+			projectWindows[projectKey] = windows
+		}
+
+		state, err := syncwindow.Evaluate(argocd.MatchingSyncWindows(windows, app), now, syncwindow.DefaultHorizon)
+		if err != nil {
+			logger.WithError(err).WithField("app_name", result.AppName).Warn("Failed to evaluate sync windows")
+			continue
+		}
+		if !state.Blocked {
+			continue
+		}
+
+		result.SyncBlocked = true
+		if state.BlockedBy != nil {
+			result.SyncBlockedBy = state.BlockedBy.String()
+		}
+		if !state.NextAllowed.IsZero() {
+			result.NextSyncWindow = state.NextAllowed.UTC().Format(time.RFC3339)
+		}
+		logger.WithFields(logrus.Fields{
+			"app_name":         result.AppName,
+			"sync_blocked_by":  result.SyncBlockedBy,
+			"next_sync_window": result.NextSyncWindow,
+		}).Info("Update deferred by sync window")
+	}
+}
+
+// scanScope is a set of projects listed with one ArgoCD client
+type scanScope struct {
+	project string               // Project whose token is used, empty for the username/password client
+	filter  argocd.FilterOptions // Filter passed to ArgoCD
+	exclude []string             // Projects dropped from the results because their own token scans them
+}
+
+// applicationFilter returns the configured application filter for a set of projects
+func applicationFilter(cfg *config.Config, projects []string) argocd.FilterOptions {
+	return argocd.FilterOptions{
+		Projects:   projects,
+		AppNames:   cfg.AppNames,
+		Namespaces: cfg.AppNamespaces,
+		Labels:     cfg.Labels,
+		SyncStatus: cfg.SyncStatus,
+		Health:     cfg.HealthStatus,
+	}
+}
+
+// scanScopes splits the configured projects between the project token clients and the username/password client
+// Projects that no client can scan are returned as uncovered.
+func scanScopes(cfg *config.Config, hasAccount bool) (scopes []scanScope, uncovered []string) {
+	allProjects := len(cfg.Projects) == 0 || slices.Contains(cfg.Projects, "*")
+
+	tokenProjects := make([]string, 0, len(cfg.ArgocdProjectTokens))
+	for project := range cfg.ArgocdProjectTokens {
+		tokenProjects = append(tokenProjects, project)
+	}
+	sort.Strings(tokenProjects)
+
+	for _, project := range tokenProjects {
+		if allProjects || argocd.MatchesName(cfg.Projects, project) {
+			scopes = append(scopes, scanScope{
+				project: project,
+				filter:  applicationFilter(cfg, []string{project}),
+			})
+		}
+	}
+
+	if allProjects {
+		if hasAccount {
+			var exclude []string
+			if len(tokenProjects) > 0 {
+				exclude = tokenProjects
+			}
+			scopes = append(scopes, scanScope{
+				filter:  applicationFilter(cfg, cfg.Projects),
+				exclude: exclude,
+			})
+		}
+		return scopes, nil
+	}
+
+	var remaining []string
+	for _, project := range cfg.Projects {
+		if _, ok := cfg.ArgocdProjectTokens[project]; !ok {
+			remaining = append(remaining, project)
+		}
+	}
+	if len(remaining) == 0 {
+		return scopes, nil
+	}
+	if !hasAccount {
+		// Without an account, a pattern only covers the projects of the tokens it matches
+		var uncovered []string
+		for _, project := range remaining {
+			matchesToken := slices.ContainsFunc(scopes, func(scope scanScope) bool {
+				return argocd.MatchesName([]string{project}, scope.project)
+			})
+			if !argocd.IsNamePattern(project) || !matchesToken {
+				uncovered = append(uncovered, project)
+			}
+		}
+		return scopes, uncovered
+	}
+
+	// Patterns scanned with the account may match projects their own token scans
+	var exclude []string
+	for _, scope := range scopes {
+		if argocd.MatchesName(remaining, scope.project) {
+			exclude = append(exclude, scope.project)
+		}
+	}
+	scopes = append(scopes, scanScope{
+		filter:  applicationFilter(cfg, remaining),
+		exclude: exclude,
 	})
+	return scopes, nil
+}
+
+// fetchApplications retrieves applications from every ArgoCD instance based on filters
+// The matching applications are then capped at max_apps, reported by a non-nil truncation.
+func fetchApplications(ctx context.Context, clients *clients, cfg *config.Config, logger *logrus.Entry) ([]*v1alpha1.Application, *scanTruncation, error) {
+	apps, err := listInstanceApplications(ctx, clients.instances, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list applications: %w", err)
+		return nil, nil, err
 	}
 
 	logger.WithField("count", len(apps)).Info("Found applications")
-	return apps, nil
+
+	// Nested deployments of app-of-apps aren't necessarily matched by the filters themselves
+	if cfg.AppOfApps {
+		matched := len(apps)
+		apps = includeChildApplications(ctx, apps, clients.childApplications, cfg.AppOfAppsMaxDepth, logger)
+		if len(apps) > matched {
+			logger.WithField("count", len(apps)-matched).Info("Found child applications of app-of-apps")
+		}
+	}
+
+	// Exclusions also drop child applications, which the filters don't apply to
+	if included := len(apps); hasExclusions(cfg) {
+		apps = excludeApplications(apps, cfg, logger)
+		if len(apps) < included {
+			logger.WithField("count", included-len(apps)).Info("Excluded applications")
+		}
+	}
+
+	apps, truncation := limitApplications(apps, cfg.MaxApps, cfg.MaxAppsMode, cfg.SampleSeed)
+	if truncation != nil {
+		logger.WithFields(logrus.Fields{
+			"matched": truncation.Matched,
+			"checked": truncation.Checked,
+			"mode":    truncation.Mode,
+		}).Warn("Scan truncated by max_apps, results are incomplete")
+	}
+	return apps, truncation, nil
+}
+
+// listScopeApplications lists the applications of a scan scope
+func listScopeApplications(ctx context.Context, client *argocd.Client, scope scanScope) ([]*v1alpha1.Application, error) {
+	if client == nil {
+		return nil, fmt.Errorf("no ArgoCD client for project %q", scope.project)
+	}
+
+	apps, err := client.ListApplications(ctx, scope.filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(scope.exclude) == 0 {
+		return apps, nil
+	}
+
+	filtered := apps[:0]
+	for _, app := range apps {
+		if !slices.Contains(scope.exclude, app.Spec.Project) {
+			filtered = append(filtered, app)
+		}
+	}
+	return filtered, nil
 }
 
 // ApplicationCheckResult holds the result of checking an application
 type ApplicationCheckResult struct {
-	AppName                    string `json:"app_name"`
-	Project                    string `json:"project"`
-	ChartName                  string `json:"chart_name"`
-	CurrentVersion             string `json:"current_version"`
-	LatestVersion              string `json:"latest_version"`
-	RepoURL                    string `json:"repo_url"`
-	HasUpdate                  bool   `json:"has_update"`
-	Error                      string `json:"error,omitempty"`               // Changed from error to string for proper JSON serialization
-	ConstraintApplied          string `json:"constraint_applied"`            // Version constraint used: "major", "minor", or "patch"
-	HasUpdateOutsideConstraint bool   `json:"has_update_outside_constraint"` // True if updates exist outside the constraint
-	LatestVersionAll           string `json:"latest_version_all,omitempty"`  // Latest version without constraint (if different)
+	AppName                    string                  `json:"app_name"`
+	Namespace                  string                  `json:"namespace,omitempty"` // Namespace of the Application resource (apps-in-any-namespace)
+	Project                    string                  `json:"project"`
+	Instance                   string                  `json:"instance,omitempty"`        // ArgoCD instance of the application (argocd_instances)
+	ApplicationSet             string                  `json:"application_set,omitempty"` // ApplicationSet that generated the application
+	Cluster                    string                  `json:"cluster,omitempty"`         // Destination cluster of the application: its name, else its server URL
+	ChartName                  string                  `json:"chart_name"`
+	CurrentVersion             string                  `json:"current_version"`
+	LatestVersion              string                  `json:"latest_version"`
+	RepoURL                    string                  `json:"repo_url"`
+	HasUpdate                  bool                    `json:"has_update"`
+	SecurityUpdate             bool                    `json:"security_update,omitempty"`         // The update includes a version annotated as containing security fixes
+	Error                      string                  `json:"error,omitempty"`                   // Changed from error to string for proper JSON serialization
+	ErrorCode                  string                  `json:"error_code,omitempty"`              // Category of the error, e.g. "AUTH_FAILED" or "TIMEOUT" (see helm.ErrorCode)
+	ConstraintApplied          string                  `json:"constraint_applied"`                // Version constraint used: "major", "minor", or "patch"
+	HasUpdateOutsideConstraint bool                    `json:"has_update_outside_constraint"`     // True if updates exist outside the constraint
+	LatestVersionAll           string                  `json:"latest_version_all,omitempty"`      // Latest version without constraint (if different)
+	RelocatedTo                string                  `json:"relocated_to,omitempty"`            // Set when the chart has moved to another repository or was deprecated
+	PinnedRevision             string                  `json:"pinned_revision,omitempty"`         // Digest or commit SHA the application is pinned to
+	PinnedBy                   string                  `json:"pinned_by,omitempty"`               // "digest" or "commit" when the revision is pinned
+	MutableTag                 string                  `json:"mutable_tag,omitempty"`             // Mutable tag (e.g. "latest") the application tracks
+	RecommendedVersion         string                  `json:"recommended_version,omitempty"`     // Concrete version to pin instead of the mutable tag
+	TrackingBranch             string                  `json:"tracking_branch,omitempty"`         // Git branch the application tracks (always deploys the branch tip)
+	SyncedRevision             string                  `json:"synced_revision,omitempty"`         // Commit a branch-tracking application was last synced to (track_branches)
+	DeployedVersion            string                  `json:"deployed_version,omitempty"`        // Chart version of the last successful sync, set when it differs from the declared one
+	IgnoredBy                  string                  `json:"ignored_by,omitempty"`              // Reason (or criteria) of the ignore rule the update matched; HasUpdate is then false
+	IgnoredUntil               string                  `json:"ignored_until,omitempty"`           // Expiry of the ignore rule (RFC 3339), empty if it doesn't expire
+	URL                        string                  `json:"url,omitempty"`                     // Application page in the ArgoCD web UI
+	ValuesSources              []argocd.ValuesRef      `json:"values_sources,omitempty"`          // Sources providing the chart's value files (multi-source `ref` pattern)
+	SyncBlocked                bool                    `json:"sync_blocked,omitempty"`            // A sync window currently blocks automated syncs of the update
+	SyncBlockedBy              string                  `json:"sync_blocked_by,omitempty"`         // Deny window blocking syncs; empty when no allow window is active
+	NextSyncWindow             string                  `json:"next_sync_window,omitempty"`        // Start of the next allowed sync period (RFC 3339), empty if none within 7 days
+	Severity                   string                  `json:"severity,omitempty"`                // Semver component the update changes: "major", "minor" or "patch"
+	VersionsBehind             int                     `json:"versions_behind,omitempty"`         // Releases newer than the current version within the constraint
+	LatestReleaseAgeDays       int                     `json:"latest_release_age_days,omitempty"` // Days since the latest version was released (Helm repositories only)
+	StalenessScore             int                     `json:"staleness_score,omitempty"`         // Upgrade debt of the update: severity weight, versions behind and release age
+	ReleaseNotes               string                  `json:"release_notes,omitempty"`           // Excerpt of the changes since the current version (release_notes)
+	ReleaseNotesURL            string                  `json:"release_notes_url,omitempty"`       // Page with the full release notes or changelog
+	ArtifactHub                *artifacthub.Package    `json:"artifacthub,omitempty"`             // Artifact Hub metadata of the chart (enrich: artifacthub)
+	Images                     []ImageCheckResult      `json:"images,omitempty"`                  // Tag checks of the application's container images (check_images)
+	Dependencies               []DependencyCheckResult `json:"dependencies,omitempty"`            // Version checks of the chart's subcharts (check_dependencies)
+	ValuesDiff                 *helm.ValuesDiff        `json:"values_diff,omitempty"`             // Default values keys changed by the update (check_values_diff)
+	SecurityFixes              *SecurityFixes          `json:"security_fixes,omitempty"`          // Image vulnerabilities fixed and introduced by the update (check_vulnerabilities)
 }
 
 // checkApplicationsConcurrently checks multiple applications in parallel using a worker pool
 func checkApplicationsConcurrently(ctx context.Context, apps []*v1alpha1.Application, helmChecker *helm.Checker, cfg *config.Config, logger *logrus.Entry) []ApplicationCheckResult {
+	return checkApplicationsWithProgress(ctx, apps, helmChecker, cfg, nil, logger)
+}
+
+// checkProgress is reported by the workers of checkApplicationsWithProgress when the check of an
+// application starts and when it completes
+type checkProgress struct {
+	app     *v1alpha1.Application
+	repoURL string                  // Repository of the Helm source being checked, empty for other applications
+	result  *ApplicationCheckResult // Set once the check completed
+}
+
+// checkApplicationsWithProgress is checkApplicationsConcurrently calling progress, from the workers, as
+// checks start and complete
+func checkApplicationsWithProgress(ctx context.Context, apps []*v1alpha1.Application, helmChecker *helm.Checker, cfg *config.Config, progress func(checkProgress), logger *logrus.Entry) []ApplicationCheckResult {
 	numWorkers := cfg.Concurrency
 	if numWorkers <= 0 {
 		numWorkers = 10 // Fallback to default
 	}
 
+	// Sources are looked up again for the progress, without repeating their debug logs
+	quietLogger := logrus.New()
+	quietLogger.SetOutput(io.Discard)
+	quiet := logrus.NewEntry(quietLogger)
+
 	logger.WithField("concurrency", numWorkers).Debug("Starting concurrent application checks")
 
 	// Create channels for work distribution
@@ -267,7 +1341,17 @@ func checkApplicationsConcurrently(ctx context.Context, apps []*v1alpha1.Applica
 			defer wg.Done()
 			workerLogger := logger.WithField("worker_id", workerID)
 			for app := range appChan {
+				if progress != nil {
+					started := checkProgress{app: app}
+					if source := findHelmSource(app, cfg.SourceName, quiet); source != nil {
+						started.repoURL = source.RepoURL
+					}
+					progress(started)
+				}
 				result := checkApplication(ctx, app, helmChecker, cfg, workerLogger)
+				if progress != nil {
+					progress(checkProgress{app: app, result: &result})
+				}
 				resultChan <- result
 			}
 		}(i)
@@ -296,9 +1380,13 @@ func checkApplicationsConcurrently(ctx context.Context, apps []*v1alpha1.Applica
 // Returns an ApplicationCheckResult with an empty AppName if the application should be skipped (non-Helm app)
 func checkApplication(ctx context.Context, app *v1alpha1.Application, helmChecker *helm.Checker, cfg *config.Config, logger *logrus.Entry) ApplicationCheckResult {
 	appLogger := logger.WithFields(logrus.Fields{
-		"app_name": app.Name,
-		"project":  app.Spec.Project,
+		"app_name":      app.Name,
+		"app_namespace": app.Namespace,
+		"project":       app.Spec.Project,
 	})
+	if instance := applicationInstance(app); instance != "" {
+		appLogger = appLogger.WithField("instance", instance)
+	}
 
 	appLogger.Info("Processing application")
 
@@ -318,35 +1406,64 @@ func checkApplication(ctx context.Context, app *v1alpha1.Application, helmChecke
 		chartName = helmSource.Path
 	}
 
+	constraint, constraintErr := applicationConstraint(app, chartName, cfg)
+
 	result := ApplicationCheckResult{
 		AppName:           app.Name,
+		Namespace:         app.Namespace,
 		Project:           app.Spec.Project,
+		Instance:          applicationInstance(app),
+		ApplicationSet:    argocd.ApplicationSetName(app),
+		Cluster:           destinationCluster(app),
 		ChartName:         chartName,
 		CurrentVersion:    helmSource.TargetRevision,
 		RepoURL:           helmSource.RepoURL,
-		ConstraintApplied: cfg.VersionConstraint,
+		ConstraintApplied: constraint,
+		ValuesSources:     argocd.ValuesRefSources(app, helmSource),
+	}
+	// Without an ArgoCD URL (Kubernetes mode) there's no UI to link to
+	if argocdURL := cfg.InstanceURL(result.Instance); argocdURL != "" {
+		result.URL = argocd.ApplicationURL(argocdURL, app.Namespace, app.Name)
 	}
 
 	appLogger = appLogger.WithFields(logrus.Fields{
 		"chart_name":    chartName,
 		"chart_version": helmSource.TargetRevision,
 		"repo_url":      helmSource.RepoURL,
-		"constraint":    cfg.VersionConstraint,
+		"constraint":    constraint,
 	})
 
 	appLogger.Info("Found Helm-based application")
 
+	// Teams can opt their application out with an annotation; it's still listed, as ignored
+	ignored, err := annotationIgnored(app)
+	if err != nil {
+		appLogger.WithError(err).Warn("Ignoring invalid annotation")
+	}
+	if ignored {
+		result.IgnoredBy = ignoreAnnotation + " annotation"
+		appLogger.Info("Application is ignored by annotation, not checking it")
+		return result
+	}
+
+	if constraintErr != nil {
+		result.Error = constraintErr.Error()
+		appLogger.WithError(constraintErr).Error("Invalid version constraint")
+		return result
+	}
+
 	// Check for newer version with constraint
 	constraintResult, err := helmChecker.GetLatestVersionWithConstraint(
 		ctx,
 		helmSource.RepoURL,
 		chartName,
 		helmSource.TargetRevision,
-		cfg.VersionConstraint,
+		constraint,
 	)
 	if err != nil {
-		appLogger.WithError(err).Error("Failed to check Helm version")
 		result.Error = err.Error()
+		result.ErrorCode = helm.ErrorCode(err)
+		appLogger.WithError(err).WithField("error_code", result.ErrorCode).Error("Failed to check Helm version")
 		return result
 	}
 
@@ -354,20 +1471,98 @@ func checkApplication(ctx context.Context, app *v1alpha1.Application, helmChecke
 	result.LatestVersionAll = constraintResult.LatestVersionAll
 	result.HasUpdateOutsideConstraint = constraintResult.HasUpdateOutsideConstraint
 
-	if constraintResult.LatestVersion != helmSource.TargetRevision {
+	// Branch-tracking applications are always at the branch tip: report the version, not an update,
+	// unless track_branches compares the tip with the commit last synced
+	if constraintResult.TrackingBranch != "" {
+		result.CurrentVersion = constraintResult.CurrentVersion
+		result.TrackingBranch = constraintResult.TrackingBranch
 		appLogger.WithFields(logrus.Fields{
-			"current_version":               helmSource.TargetRevision,
+			"branch":          result.TrackingBranch,
+			"current_version": result.CurrentVersion,
+		}).Info("Application tracks a branch")
+		if cfg.TrackBranches {
+			checkBranchUpdate(ctx, helmChecker, app, helmSource, chartName, &result, appLogger)
+		}
+		return result
+	}
+
+	// Pinned revisions (digest or commit SHA) are compared by the version they resolve to
+	currentVersion := helmSource.TargetRevision
+	if constraintResult.PinnedBy != "" {
+		currentVersion = constraintResult.CurrentVersion
+		result.CurrentVersion = currentVersion
+		result.PinnedRevision = helmSource.TargetRevision
+		result.PinnedBy = constraintResult.PinnedBy
+		appLogger.WithFields(logrus.Fields{
+			"pinned_revision": result.PinnedRevision,
+			"pinned_by":       result.PinnedBy,
+			"current_version": currentVersion,
+		}).Info("Resolved pinned revision")
+	}
+	if constraintResult.MutableTag != "" {
+		currentVersion = constraintResult.CurrentVersion
+		result.CurrentVersion = currentVersion
+		result.MutableTag = constraintResult.MutableTag
+		result.RecommendedVersion = constraintResult.LatestVersion
+		appLogger.WithFields(logrus.Fields{
+			"mutable_tag":         result.MutableTag,
+			"current_version":     currentVersion,
+			"recommended_version": result.RecommendedVersion,
+		}).Warn("Application tracks a mutable tag, pin it to a concrete version")
+	}
+
+	// Comparing the declared version with the latest one is misleading if the declared version isn't running
+	if result.PinnedBy == "" && result.MutableTag == "" {
+		if deployed := argocd.DeployedRevision(app, helmSource); argocd.RevisionDrifted(helmSource.TargetRevision, deployed) {
+			result.DeployedVersion = deployed
+			appLogger.WithFields(logrus.Fields{
+				"target_revision":  helmSource.TargetRevision,
+				"deployed_version": deployed,
+			}).Warn("Deployed chart version differs from the declared targetRevision")
+		}
+	}
+
+	if constraintResult.Migration != nil {
+		result.RelocatedTo = constraintResult.Migration.String()
+		appLogger.WithFields(logrus.Fields{
+			"relocated_to": result.RelocatedTo,
+			"reason":       constraintResult.Migration.Reason,
+		}).Warn("Chart has been relocated")
+	}
+
+	if constraintResult.LatestVersion != currentVersion {
+		appLogger.WithFields(logrus.Fields{
+			"current_version":               currentVersion,
 			"latest_version":                constraintResult.LatestVersion,
 			"latest_version_all":            constraintResult.LatestVersionAll,
 			"has_update_outside_constraint": constraintResult.HasUpdateOutsideConstraint,
 		}).Warn("Update available!")
 		result.HasUpdate = true
+		result.SecurityUpdate = constraintResult.SecurityUpdate
+		setStaleness(&result, constraintResult.VersionsBehind, constraintResult.LatestReleased, time.Now())
+
+		notes, err := helmChecker.GetReleaseNotes(ctx, helmSource.RepoURL, chartName, currentVersion, constraintResult)
+		if err != nil {
+			appLogger.WithError(err).Warn("Failed to look up release notes")
+		} else if notes != nil {
+			result.ReleaseNotes = notes.Excerpt
+			result.ReleaseNotesURL = notes.URL
+		}
+
+		if cfg.CheckValuesDiff {
+			diff, err := helmChecker.GetValuesDiff(ctx, helmSource.RepoURL, chartName, currentVersion, constraintResult.LatestVersion)
+			if err != nil {
+				appLogger.WithError(err).Warn("Failed to compare chart values")
+			} else {
+				result.ValuesDiff = diff
+			}
+		}
 	} else {
 		if constraintResult.HasUpdateOutsideConstraint {
 			appLogger.WithFields(logrus.Fields{
-				"current_version":    helmSource.TargetRevision,
+				"current_version":    currentVersion,
 				"latest_version_all": constraintResult.LatestVersionAll,
-				"constraint":         cfg.VersionConstraint,
+				"constraint":         constraint,
 			}).Info("Application is up to date within constraint, but updates exist outside constraint")
 		} else {
 			appLogger.Info("Application is up to date")
@@ -377,8 +1572,83 @@ func checkApplication(ctx context.Context, app *v1alpha1.Application, helmChecke
 	return result
 }
 
+// checkBranchUpdate reports an update of a branch-tracking application when the chart version on the
+// branch tip is newer than the one of the commit it was last synced to
+func checkBranchUpdate(ctx context.Context, helmChecker *helm.Checker, app *v1alpha1.Application, helmSource *v1alpha1.ApplicationSource, chartName string, result *ApplicationCheckResult, logger *logrus.Entry) {
+	commit := argocd.SyncedCommit(app, helmSource)
+	if commit == "" {
+		logger.Debug("Application has no synced commit, skipping branch comparison")
+		return
+	}
+
+	synced, err := helmChecker.GetChartVersionAtCommit(ctx, helmSource.RepoURL, chartName, commit)
+	if err != nil {
+		logger.WithError(err).WithField("synced_revision", commit).Warn("Failed to read chart version of the synced commit")
+		return
+	}
+	branchVersion := result.CurrentVersion
+	if setBranchUpdate(result, commit, synced, time.Now()) {
+		logger.WithFields(logrus.Fields{
+			"synced_revision": commit,
+			"synced_version":  synced,
+			"branch_version":  branchVersion,
+		}).Warn("Update available on the tracked branch!")
+	}
+}
+
+// setBranchUpdate records the synced commit of a branch-tracking result and, when the branch tip has a
+// newer chart version than that commit, turns the result into an update from the synced version
+// The severity comes from the two Chart.yaml versions; a branch has no release history to count or date.
+func setBranchUpdate(result *ApplicationCheckResult, commit, synced string, now time.Time) bool {
+	result.SyncedRevision = commit
+	if compareVersions(result.CurrentVersion, synced) <= 0 {
+		return false
+	}
+	result.LatestVersion = result.CurrentVersion
+	result.CurrentVersion = synced
+	result.HasUpdate = true
+	setStaleness(result, 0, time.Time{}, now)
+	return true
+}
+
+// formatResultError returns the error of a skipped application prefixed with its code, e.g.
+// "[TIMEOUT] Helm repository lookup timed out after 30s: ..."
+func formatResultError(result ApplicationCheckResult) string {
+	if result.ErrorCode == "" {
+		return result.Error
+	}
+	return "[" + result.ErrorCode + "] " + result.Error
+}
+
+// formatCurrentVersion returns the current version, noting the pin or mutable tag it was resolved from
+func formatCurrentVersion(result ApplicationCheckResult, tr *i18n.Localizer) string {
+	switch {
+	case result.PinnedBy != "":
+		return tr.T(i18n.VersionPinned, result.CurrentVersion, result.PinnedBy, shortRevision(result.PinnedRevision))
+	case result.MutableTag != "":
+		return tr.T(i18n.VersionMutableTag, result.CurrentVersion, result.MutableTag, result.RecommendedVersion)
+	default:
+		return result.CurrentVersion
+	}
+}
+
+// shortRevision abbreviates a digest or commit SHA for display
+func shortRevision(revision string) string {
+	prefix := ""
+	if i := strings.LastIndex(revision, ":"); i >= 0 {
+		prefix, revision = revision[:i+1], revision[i+1:]
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	return prefix + revision
+}
+
 // findHelmSource finds the Helm source in an ArgoCD application
+// The argazer.io/source-name annotation takes precedence over sourceName.
 func findHelmSource(app *v1alpha1.Application, sourceName string, logger *logrus.Entry) *v1alpha1.ApplicationSource {
+	sourceName = annotationSourceName(app, sourceName)
+
 	// Helper function to check if a source is Helm-based
 	isHelmSource := func(source *v1alpha1.ApplicationSource) bool {
 		// Check if it's a Helm repository source (has Chart field)
@@ -436,10 +1706,16 @@ func findHelmSource(app *v1alpha1.Application, sourceName string, logger *logrus
 
 // scanResults holds statistics about the scan
 type scanResults struct {
-	total    int
-	upToDate int
-	updates  int
-	skipped  int
+	total     int
+	upToDate  int
+	updates   int
+	skipped   int
+	relocated int
+	tracking  int
+	drifted   int
+	ignored   int
+	images    int // Container images with a newer tag
+	subcharts int // Subcharts with a newer version
 }
 
 // categorizedResults holds the processed and categorized check results
@@ -447,7 +1723,18 @@ type categorizedResults struct {
 	updatesAvailable       []ApplicationCheckResult
 	upToDateWithConstraint []ApplicationCheckResult
 	upToDateNoConstraint   []ApplicationCheckResult
+	relocated              []ApplicationCheckResult
+	trackingBranch         []ApplicationCheckResult
+	drifted                []ApplicationCheckResult
+	ignored                []ApplicationCheckResult
 	errors                 []ApplicationCheckResult
+	applicationSets        []applicationSetSummary
+	staleness              []projectStaleness
+	imageUpdates           []imageUpdate
+	dependencyUpdates      []dependencyUpdate
+	truncation             *scanTruncation          // Set when max_apps left matching applications out
+	layout                 reportLayout             // Grouping, sorting and columns of the table and markdown reports
+	results                []ApplicationCheckResult // All results, for the reports that aren't split by category
 	stats                  scanResults
 }
 
@@ -455,7 +1742,8 @@ type categorizedResults struct {
 // Results with empty AppName are skipped (these are non-Helm applications)
 func processResults(results []ApplicationCheckResult) categorizedResults {
 	cat := categorizedResults{
-		stats: scanResults{},
+		results: results,
+		stats:   scanResults{},
 	}
 
 	for _, result := range results {
@@ -469,6 +1757,19 @@ func processResults(results []ApplicationCheckResult) categorizedResults {
 		if result.Error != "" {
 			cat.stats.skipped++
 			cat.errors = append(cat.errors, result)
+		} else if result.RelocatedTo != "" && !result.HasUpdate {
+			// A deprecated chart with a newer version is still listed as an update
+			cat.stats.relocated++
+			cat.relocated = append(cat.relocated, result)
+		} else if result.TrackingBranch != "" && !result.HasUpdate {
+			cat.stats.tracking++
+			cat.trackingBranch = append(cat.trackingBranch, result)
+		} else if result.DeployedVersion != "" {
+			cat.stats.drifted++
+			cat.drifted = append(cat.drifted, result)
+		} else if result.IgnoredBy != "" {
+			cat.stats.ignored++
+			cat.ignored = append(cat.ignored, result)
 		} else if result.HasUpdate {
 			cat.stats.updates++
 			cat.updatesAvailable = append(cat.updatesAvailable, result)
@@ -476,131 +1777,112 @@ func processResults(results []ApplicationCheckResult) categorizedResults {
 			cat.stats.upToDate++
 			if result.HasUpdateOutsideConstraint {
 				cat.upToDateWithConstraint = append(cat.upToDateWithConstraint, result)
-			} else {
-				cat.upToDateNoConstraint = append(cat.upToDateNoConstraint, result)
-			}
-		}
-	}
-
-	return cat
-}
-
-// outputResults displays the results to console in the specified format
-func outputResults(results []ApplicationCheckResult, format string, w io.Writer) error {
-	categorized := processResults(results)
-
-	switch format {
-	case config.OutputFormatJSON:
-		return renderJSON(categorized, w)
-	case config.OutputFormatMarkdown:
-		return renderMarkdown(categorized, w)
-	case config.OutputFormatTable:
-		return renderTable(categorized, w)
-	default:
-		return fmt.Errorf("unknown output format: %s", format)
-	}
-}
-
-// renderTable displays results in a formatted table (original format)
-func renderTable(cat categorizedResults, w io.Writer) error {
-	// Display summary
-	if _, err := fmt.Fprintln(w, "\n"+strings.Repeat("=", 80)); err != nil {
-		return fmt.Errorf("failed to write table: %w", err)
-	}
-	fmt.Fprintln(w, "ARGAZER SCAN RESULTS")
-	fmt.Fprintln(w, strings.Repeat("=", 80))
-	fmt.Fprintf(w, "\nTotal applications checked: %d\n\n", cat.stats.total)
-	fmt.Fprintf(w, "Up to date: %d\n", cat.stats.upToDate)
-	fmt.Fprintf(w, "Updates available: %d\n", cat.stats.updates)
-	fmt.Fprintf(w, "Skipped: %d\n\n", cat.stats.skipped)
-
-	// Display updates
-	if cat.stats.updates > 0 {
-		fmt.Fprintln(w, strings.Repeat("-", 80))
-		fmt.Fprintln(w, "APPLICATIONS WITH UPDATES AVAILABLE:")
-		fmt.Fprintln(w, strings.Repeat("-", 80))
-
-		for _, result := range cat.updatesAvailable {
-			fmt.Fprintf(w, "\nApplication: %s\n", result.AppName)
-			fmt.Fprintf(w, "  Project: %s\n", result.Project)
-			fmt.Fprintf(w, "  Chart: %s\n", result.ChartName)
-			fmt.Fprintf(w, "  Current Version: %s\n", result.CurrentVersion)
-			fmt.Fprintf(w, "  Latest Version: %s\n", result.LatestVersion)
-			if result.ConstraintApplied != "major" && result.ConstraintApplied != "" {
-				fmt.Fprintf(w, "  Version Constraint: %s\n", result.ConstraintApplied)
-			}
-			if result.HasUpdateOutsideConstraint && result.LatestVersionAll != "" {
-				fmt.Fprintf(w, "  Note: Version %s available outside constraint\n", result.LatestVersionAll)
-			}
-			fmt.Fprintf(w, "  Repository: %s\n", result.RepoURL)
-		}
-	}
-
-	// Display apps that are up to date but have updates outside constraint
-	if len(cat.upToDateWithConstraint) > 0 {
-		fmt.Fprintln(w, "\n"+strings.Repeat("-", 80))
-		fmt.Fprintln(w, "UP TO DATE (with updates outside constraint):")
-		fmt.Fprintln(w, strings.Repeat("-", 80))
-
-		for _, result := range cat.upToDateWithConstraint {
-			fmt.Fprintf(w, "\nApplication: %s\n", result.AppName)
-			fmt.Fprintf(w, "  Project: %s\n", result.Project)
-			fmt.Fprintf(w, "  Chart: %s\n", result.ChartName)
-			fmt.Fprintf(w, "  Current Version: %s\n", result.CurrentVersion)
-			fmt.Fprintf(w, "  Status: Up to date within '%s' constraint\n", result.ConstraintApplied)
-			if result.LatestVersionAll != "" {
-				fmt.Fprintf(w, "  Note: Version %s available outside constraint\n", result.LatestVersionAll)
+			} else {
+				cat.upToDateNoConstraint = append(cat.upToDateNoConstraint, result)
 			}
-			fmt.Fprintf(w, "  Repository: %s\n", result.RepoURL)
 		}
 	}
 
-	// Display skipped applications
-	if cat.stats.skipped > 0 {
-		fmt.Fprintln(w, "\n"+strings.Repeat("-", 80))
-		fmt.Fprintln(w, "APPLICATIONS SKIPPED (Unable to check):")
-		fmt.Fprintln(w, strings.Repeat("-", 80))
+	cat.applicationSets = summarizeApplicationSets(results)
+	cat.staleness = summarizeStaleness(results)
+	cat.imageUpdates = imageUpdates(results)
+	cat.stats.images = len(cat.imageUpdates)
+	cat.dependencyUpdates = dependencyUpdates(results)
+	cat.stats.subcharts = len(cat.dependencyUpdates)
+	return cat
+}
+
+// outputResults displays the results to console in the specified format
+func outputResults(results []ApplicationCheckResult, format string, tr *i18n.Localizer, w io.Writer) error {
+	return renderResults(processResults(results), format, tr, w)
+}
 
-		for _, result := range cat.errors {
-			fmt.Fprintf(w, "\nApplication: %s\n", result.AppName)
-			fmt.Fprintf(w, "  Project: %s\n", result.Project)
-			fmt.Fprintf(w, "  Chart: %s\n", result.ChartName)
-			fmt.Fprintf(w, "  Repository: %s\n", result.RepoURL)
-			fmt.Fprintf(w, "  Reason: %s\n", result.Error)
+// renderResults displays categorized results in the specified format
+func renderResults(categorized categorizedResults, format string, tr *i18n.Localizer, w io.Writer) error {
+	switch format {
+	case config.OutputFormatJSON:
+		if categorized.layout.jsonCompact {
+			return renderJSONLines(categorized, w)
 		}
+		return renderJSON(categorized, w)
+	case config.OutputFormatMarkdown:
+		return renderMarkdown(categorized, tr, w)
+	case config.OutputFormatMarkdownCompact:
+		return renderMarkdownCompact(categorized, tr, w)
+	case config.OutputFormatJUnit:
+		return renderJUnit(categorized, w)
+	case config.OutputFormatTable:
+		return renderTable(categorized, tr, w)
+	case config.OutputFormatHTML:
+		return renderHTML(categorized, w)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
 	}
-
-	fmt.Fprintln(w, "\n"+strings.Repeat("=", 80)+"\n")
-	return nil
 }
 
+// jsonSchemaVersion is the version of the JSON report's structure, printed as its schema_version
+// Adding fields keeps the version; renaming, removing or retyping one increments it.
+const jsonSchemaVersion = 1
+
 // renderJSON displays results in JSON format
 func renderJSON(cat categorizedResults, w io.Writer) error {
 	// Create JSON output structure
 	type JSONOutput struct {
-		Summary struct {
-			Total            int `json:"total"`
-			UpToDate         int `json:"up_to_date"`
-			UpdatesAvailable int `json:"updates_available"`
-			Skipped          int `json:"skipped"`
+		SchemaVersion int `json:"schema_version"`
+		Summary       struct {
+			Total             int `json:"total"`
+			UpToDate          int `json:"up_to_date"`
+			UpdatesAvailable  int `json:"updates_available"`
+			Relocated         int `json:"relocated"`
+			TrackingBranch    int `json:"tracking_branch"`
+			Drifted           int `json:"drifted"`
+			Ignored           int `json:"ignored"`
+			Skipped           int `json:"skipped"`
+			ImageUpdates      int `json:"image_updates,omitempty"`
+			DependencyUpdates int `json:"dependency_updates,omitempty"`
 		} `json:"summary"`
 		UpdatesAvailable        []ApplicationCheckResult `json:"updates_available"`
 		UpToDateWithConstraint  []ApplicationCheckResult `json:"up_to_date_with_constraint"`
 		UpToDateNoUpdateOutside []ApplicationCheckResult `json:"up_to_date"`
+		Relocated               []ApplicationCheckResult `json:"relocated"`
+		TrackingBranch          []ApplicationCheckResult `json:"tracking_branch"`
+		Drifted                 []ApplicationCheckResult `json:"drifted"`
+		Ignored                 []ApplicationCheckResult `json:"ignored"`
 		Errors                  []ApplicationCheckResult `json:"errors"`
+		ApplicationSets         []applicationSetSummary  `json:"application_sets,omitempty"`
+		StalenessByProject      []projectStaleness       `json:"staleness_by_project"`
+		ImageUpdates            []imageUpdate            `json:"image_updates,omitempty"`
+		DependencyUpdates       []dependencyUpdate       `json:"dependency_updates,omitempty"`
+		Truncated               *scanTruncation          `json:"truncated,omitempty"`
 	}
 
 	output := JSONOutput{
+		SchemaVersion:           jsonSchemaVersion,
 		UpdatesAvailable:        cat.updatesAvailable,
 		UpToDateWithConstraint:  cat.upToDateWithConstraint,
 		UpToDateNoUpdateOutside: cat.upToDateNoConstraint,
+		Relocated:               cat.relocated,
+		TrackingBranch:          cat.trackingBranch,
+		Drifted:                 cat.drifted,
+		Ignored:                 cat.ignored,
 		Errors:                  cat.errors,
+		ApplicationSets:         cat.applicationSets,
+		StalenessByProject:      cat.staleness,
+		ImageUpdates:            cat.imageUpdates,
+		DependencyUpdates:       cat.dependencyUpdates,
+		Truncated:               cat.truncation,
 	}
 
 	output.Summary.Total = cat.stats.total
 	output.Summary.UpToDate = cat.stats.upToDate
 	output.Summary.UpdatesAvailable = cat.stats.updates
+	output.Summary.Relocated = cat.stats.relocated
+	output.Summary.TrackingBranch = cat.stats.tracking
+	output.Summary.Drifted = cat.stats.drifted
+	output.Summary.Ignored = cat.stats.ignored
 	output.Summary.Skipped = cat.stats.skipped
+	output.Summary.ImageUpdates = cat.stats.images
+	output.Summary.DependencyUpdates = cat.stats.subcharts
 
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
@@ -611,90 +1893,443 @@ func renderJSON(cat categorizedResults, w io.Writer) error {
 	return nil
 }
 
+// jsonLine is a line of the compact JSON report: an application with its status, like in the scan report
+type jsonLine struct {
+	SchemaVersion int `json:"schema_version"`
+	reportApplication
+}
+
+// renderJSONLines displays results as newline-delimited JSON, one line per application in the order of
+// the sections of the JSON document. The summary and the aggregated sections are left out.
+func renderJSONLines(cat categorizedResults, w io.Writer) error {
+	sections := [][]ApplicationCheckResult{
+		cat.updatesAvailable,
+		cat.upToDateWithConstraint,
+		cat.upToDateNoConstraint,
+		cat.relocated,
+		cat.trackingBranch,
+		cat.drifted,
+		cat.ignored,
+		cat.errors,
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, results := range sections {
+		for _, result := range results {
+			line := jsonLine{
+				SchemaVersion:     jsonSchemaVersion,
+				reportApplication: reportApplication{ApplicationCheckResult: result, Status: reportStatus(result)},
+			}
+			if err := encoder.Encode(line); err != nil {
+				return fmt.Errorf("failed to encode JSON: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
 // renderMarkdown displays results in Markdown format
-func renderMarkdown(cat categorizedResults, w io.Writer) error {
+func renderMarkdown(cat categorizedResults, tr *i18n.Localizer, w io.Writer) error {
+	// Application headings move a level down below group headings
+	appHeading := "###"
+	if groupingEnabled(cat.layout.groupBy) {
+		appHeading = "####"
+	}
+
 	// Display summary
-	if _, err := fmt.Fprintln(w, "# Argazer Scan Results"); err != nil {
+	if _, err := fmt.Fprintln(w, "# "+tr.T(i18n.MarkdownTitle)); err != nil {
 		return fmt.Errorf("failed to write markdown: %w", err)
 	}
 	fmt.Fprintln(w)
-	fmt.Fprintln(w, "## Summary")
+	fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownSummary))
 	fmt.Fprintln(w)
-	fmt.Fprintf(w, "- **Total applications checked:** %d\n", cat.stats.total)
-	fmt.Fprintf(w, "- **Up to date:** %d\n", cat.stats.upToDate)
-	fmt.Fprintf(w, "- **Updates available:** %d\n", cat.stats.updates)
-	fmt.Fprintf(w, "- **Skipped:** %d\n\n", cat.stats.skipped)
+	fmt.Fprintf(w, "- **%s:** %d\n", tr.T(i18n.LabelTotal), cat.stats.total)
+	fmt.Fprintf(w, "- **%s:** %d\n", tr.T(i18n.LabelUpToDate), cat.stats.upToDate)
+	fmt.Fprintf(w, "- **%s:** %d\n", tr.T(i18n.LabelUpdates), cat.stats.updates)
+	if cat.stats.relocated > 0 {
+		fmt.Fprintf(w, "- **%s:** %d\n", tr.T(i18n.LabelRelocated), cat.stats.relocated)
+	}
+	if cat.stats.tracking > 0 {
+		fmt.Fprintf(w, "- **%s:** %d\n", tr.T(i18n.LabelTracking), cat.stats.tracking)
+	}
+	if cat.stats.drifted > 0 {
+		fmt.Fprintf(w, "- **%s:** %d\n", tr.T(i18n.LabelDrifted), cat.stats.drifted)
+	}
+	if cat.stats.ignored > 0 {
+		fmt.Fprintf(w, "- **%s:** %d\n", tr.T(i18n.LabelIgnored), cat.stats.ignored)
+	}
+	if cat.stats.images > 0 {
+		fmt.Fprintf(w, "- **%s:** %d\n", tr.T(i18n.LabelImages), cat.stats.images)
+	}
+	if cat.stats.subcharts > 0 {
+		fmt.Fprintf(w, "- **%s:** %d\n", tr.T(i18n.LabelSubcharts), cat.stats.subcharts)
+	}
+	fmt.Fprintf(w, "- **%s:** %d\n\n", tr.T(i18n.LabelSkipped), cat.stats.skipped)
+	if cat.truncation != nil {
+		fmt.Fprintf(w, "> **%s**\n\n", formatTruncation(cat.truncation, tr))
+	}
+
+	// Display outdated charts per ApplicationSet, where the fix is a single template bump
+	if outdated := outdatedApplicationSets(cat.applicationSets); len(outdated) > 0 {
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownAppSets))
+		fmt.Fprintln(w)
+
+		for _, summary := range outdated {
+			fmt.Fprintf(w, "- **%s:** %s\n", summary.ApplicationSet, formatApplicationSetSummary(summary, tr))
+		}
+		fmt.Fprintln(w)
+	}
 
 	// Display updates
 	if cat.stats.updates > 0 {
-		fmt.Fprintln(w, "## Applications with Updates Available")
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownUpdates))
 		fmt.Fprintln(w)
 
-		for _, result := range cat.updatesAvailable {
-			fmt.Fprintf(w, "### %s\n\n", result.AppName)
-			fmt.Fprintf(w, "| Field | Value |\n")
-			fmt.Fprintf(w, "|-------|-------|\n")
-			fmt.Fprintf(w, "| **Project** | %s |\n", result.Project)
-			fmt.Fprintf(w, "| **Chart** | %s |\n", result.ChartName)
-			fmt.Fprintf(w, "| **Current Version** | %s |\n", result.CurrentVersion)
-			fmt.Fprintf(w, "| **Latest Version** | %s |\n", result.LatestVersion)
-			if result.ConstraintApplied != "major" && result.ConstraintApplied != "" {
-				fmt.Fprintf(w, "| **Version Constraint** | %s |\n", result.ConstraintApplied)
+		for _, group := range groupResults(cat.updatesAvailable, cat.layout.groupBy) {
+			if heading := groupHeading(group, cat.layout.groupBy, tr); heading != "" {
+				fmt.Fprintf(w, "### %s\n\n", heading)
 			}
-			if result.HasUpdateOutsideConstraint && result.LatestVersionAll != "" {
-				fmt.Fprintf(w, "| **Latest Version (all)** | %s |\n", result.LatestVersionAll)
+			for _, result := range group.results {
+				fmt.Fprintf(w, "%s %s\n\n", appHeading, markdownAppHeading(result))
+				fmt.Fprintf(w, "| %s | %s |\n", tr.T(i18n.FieldName), tr.T(i18n.FieldValue))
+				fmt.Fprintf(w, "|-------|-------|\n")
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldProject), result.Project)
+				if result.Namespace != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldNamespace), result.Namespace)
+				}
+				if result.Instance != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldInstance), result.Instance)
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldChart), result.ChartName)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldCurrentVersion), formatCurrentVersion(result, tr))
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldLatestVersion), result.LatestVersion)
+				if result.Severity != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldSeverity), result.Severity)
+				}
+				if result.ConstraintApplied != "major" && result.ConstraintApplied != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldVersionConstraint), result.ConstraintApplied)
+				}
+				if result.HasUpdateOutsideConstraint && result.LatestVersionAll != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldLatestVersionAll), result.LatestVersionAll)
+				}
+				if result.SyncBlocked {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldSyncWindow), notification.FormatSyncDeferral(tr, result.SyncBlockedBy, result.NextSyncWindow))
+				}
+				for _, values := range result.ValuesSources {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldValues), values)
+				}
+				if result.ValuesDiff.HasChanges() {
+					keys := valuesDiffKeys(result.ValuesDiff)
+					for i, key := range keys {
+						keys[i] = "`" + key + "`"
+					}
+					fmt.Fprintf(w, "| **%s** | %s<br>%s |\n", tr.T(i18n.FieldValuesDiff), formatValuesDiff(result.ValuesDiff, tr), strings.Join(keys, " "))
+				}
+				if result.SecurityFixes != nil {
+					ids := securityFixesIDs(result.SecurityFixes)
+					for i, id := range ids {
+						ids[i] = "`" + id + "`"
+					}
+					fmt.Fprintf(w, "| **%s** | %s<br>%s |\n", tr.T(i18n.FieldSecurityFixes), formatSecurityFixes(result.SecurityFixes, tr), strings.Join(ids, " "))
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldRepository), result.RepoURL)
+				for _, detail := range artifactHubDetails(result.ArtifactHub, tr, true) {
+					fmt.Fprintf(w, "| **%s** | %s |\n", detail.label, detail.value)
+				}
+				fmt.Fprintln(w)
+				renderMarkdownReleaseNotes(result, tr, w)
 			}
-			fmt.Fprintf(w, "| **Repository** | %s |\n\n", result.RepoURL)
 		}
 	}
 
 	// Display apps that are up to date but have updates outside constraint
 	if len(cat.upToDateWithConstraint) > 0 {
-		fmt.Fprintln(w, "## Up to Date (with updates outside constraint)")
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownOutside))
+		fmt.Fprintln(w)
+
+		for _, group := range groupResults(cat.upToDateWithConstraint, cat.layout.groupBy) {
+			if heading := groupHeading(group, cat.layout.groupBy, tr); heading != "" {
+				fmt.Fprintf(w, "### %s\n\n", heading)
+			}
+			for _, result := range group.results {
+				fmt.Fprintf(w, "%s %s\n\n", appHeading, markdownAppHeading(result))
+				fmt.Fprintf(w, "| %s | %s |\n", tr.T(i18n.FieldName), tr.T(i18n.FieldValue))
+				fmt.Fprintf(w, "|-------|-------|\n")
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldProject), result.Project)
+				if result.Namespace != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldNamespace), result.Namespace)
+				}
+				if result.Instance != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldInstance), result.Instance)
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldChart), result.ChartName)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldCurrentVersion), formatCurrentVersion(result, tr))
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldStatus), tr.T(i18n.UpToDateWithinConstraint, result.ConstraintApplied))
+				if result.LatestVersionAll != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldLatestVersionAll), result.LatestVersionAll)
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n\n", tr.T(i18n.FieldRepository), result.RepoURL)
+			}
+		}
+	}
+
+	// Display container images with newer tags
+	if cat.stats.images > 0 {
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownImages))
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n", tr.T(i18n.FieldApplication), tr.T(i18n.FieldImage), tr.T(i18n.FieldCurrentTag), tr.T(i18n.FieldLatestTag))
+		fmt.Fprintf(w, "|-------|-------|-------|-------|\n")
+		for _, update := range cat.imageUpdates {
+			app := ApplicationCheckResult{AppName: update.AppName, URL: update.URL}
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n", markdownAppHeading(app), update.Image, update.CurrentTag, update.LatestTag)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Display subcharts with newer versions
+	if cat.stats.subcharts > 0 {
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownSubcharts))
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n", tr.T(i18n.FieldApplication), tr.T(i18n.FieldSubchart), tr.T(i18n.FieldCurrentVersion), tr.T(i18n.FieldLatestVersion))
+		fmt.Fprintf(w, "|-------|-------|-------|-------|\n")
+		for _, update := range cat.dependencyUpdates {
+			app := ApplicationCheckResult{AppName: update.AppName, URL: update.URL}
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n", markdownAppHeading(app), update.Name, update.CurrentVersion, update.LatestVersion)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Display applications whose chart has moved
+	if cat.stats.relocated > 0 {
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownRelocated))
+		fmt.Fprintln(w)
+
+		for _, group := range groupResults(cat.relocated, cat.layout.groupBy) {
+			if heading := groupHeading(group, cat.layout.groupBy, tr); heading != "" {
+				fmt.Fprintf(w, "### %s\n\n", heading)
+			}
+			for _, result := range group.results {
+				fmt.Fprintf(w, "%s %s\n\n", appHeading, markdownAppHeading(result))
+				fmt.Fprintf(w, "| %s | %s |\n", tr.T(i18n.FieldName), tr.T(i18n.FieldValue))
+				fmt.Fprintf(w, "|-------|-------|\n")
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldProject), result.Project)
+				if result.Namespace != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldNamespace), result.Namespace)
+				}
+				if result.Instance != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldInstance), result.Instance)
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldChart), result.ChartName)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldCurrentVersion), formatCurrentVersion(result, tr))
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldRepository), result.RepoURL)
+				fmt.Fprintf(w, "| **%s** | %s |\n\n", tr.T(i18n.FieldStatus), result.RelocatedTo)
+			}
+		}
+	}
+
+	// Display applications that track a Git branch
+	if cat.stats.tracking > 0 {
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownTracking))
+		fmt.Fprintln(w)
+
+		for _, group := range groupResults(cat.trackingBranch, cat.layout.groupBy) {
+			if heading := groupHeading(group, cat.layout.groupBy, tr); heading != "" {
+				fmt.Fprintf(w, "### %s\n\n", heading)
+			}
+			for _, result := range group.results {
+				fmt.Fprintf(w, "%s %s\n\n", appHeading, markdownAppHeading(result))
+				fmt.Fprintf(w, "| %s | %s |\n", tr.T(i18n.FieldName), tr.T(i18n.FieldValue))
+				fmt.Fprintf(w, "|-------|-------|\n")
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldProject), result.Project)
+				if result.Namespace != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldNamespace), result.Namespace)
+				}
+				if result.Instance != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldInstance), result.Instance)
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldChart), result.ChartName)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldBranch), result.TrackingBranch)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldChartVersion), result.CurrentVersion)
+				fmt.Fprintf(w, "| **%s** | %s |\n\n", tr.T(i18n.FieldRepository), result.RepoURL)
+			}
+		}
+	}
+
+	// Display applications whose running chart version isn't the declared one
+	if cat.stats.drifted > 0 {
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownDrifted))
+		fmt.Fprintln(w)
+
+		for _, group := range groupResults(cat.drifted, cat.layout.groupBy) {
+			if heading := groupHeading(group, cat.layout.groupBy, tr); heading != "" {
+				fmt.Fprintf(w, "### %s\n\n", heading)
+			}
+			for _, result := range group.results {
+				fmt.Fprintf(w, "%s %s\n\n", appHeading, markdownAppHeading(result))
+				fmt.Fprintf(w, "| %s | %s |\n", tr.T(i18n.FieldName), tr.T(i18n.FieldValue))
+				fmt.Fprintf(w, "|-------|-------|\n")
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldProject), result.Project)
+				if result.Namespace != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldNamespace), result.Namespace)
+				}
+				if result.Instance != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldInstance), result.Instance)
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldChart), result.ChartName)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldDeclaredVersion), result.CurrentVersion)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldDeployedVersion), result.DeployedVersion)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldLatestVersion), result.LatestVersion)
+				fmt.Fprintf(w, "| **%s** | %s |\n\n", tr.T(i18n.FieldRepository), result.RepoURL)
+			}
+		}
+	}
+
+	// Display updates excluded by ignore rules
+	if cat.stats.ignored > 0 {
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownIgnored))
 		fmt.Fprintln(w)
 
-		for _, result := range cat.upToDateWithConstraint {
-			fmt.Fprintf(w, "### %s\n\n", result.AppName)
-			fmt.Fprintf(w, "| Field | Value |\n")
-			fmt.Fprintf(w, "|-------|-------|\n")
-			fmt.Fprintf(w, "| **Project** | %s |\n", result.Project)
-			fmt.Fprintf(w, "| **Chart** | %s |\n", result.ChartName)
-			fmt.Fprintf(w, "| **Current Version** | %s |\n", result.CurrentVersion)
-			fmt.Fprintf(w, "| **Status** | Up to date within '%s' constraint |\n", result.ConstraintApplied)
-			if result.LatestVersionAll != "" {
-				fmt.Fprintf(w, "| **Latest Version (all)** | %s |\n", result.LatestVersionAll)
+		for _, group := range groupResults(cat.ignored, cat.layout.groupBy) {
+			if heading := groupHeading(group, cat.layout.groupBy, tr); heading != "" {
+				fmt.Fprintf(w, "### %s\n\n", heading)
+			}
+			for _, result := range group.results {
+				fmt.Fprintf(w, "%s %s\n\n", appHeading, markdownAppHeading(result))
+				fmt.Fprintf(w, "| %s | %s |\n", tr.T(i18n.FieldName), tr.T(i18n.FieldValue))
+				fmt.Fprintf(w, "|-------|-------|\n")
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldProject), result.Project)
+				if result.Namespace != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldNamespace), result.Namespace)
+				}
+				if result.Instance != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldInstance), result.Instance)
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldChart), result.ChartName)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldCurrentVersion), formatCurrentVersion(result, tr))
+				if result.LatestVersion != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldLatestVersion), result.LatestVersion)
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldReason), formatIgnoredBy(result, tr))
+				fmt.Fprintf(w, "| **%s** | %s |\n\n", tr.T(i18n.FieldRepository), result.RepoURL)
 			}
-			fmt.Fprintf(w, "| **Repository** | %s |\n\n", result.RepoURL)
 		}
 	}
 
 	// Display skipped applications
 	if cat.stats.skipped > 0 {
-		fmt.Fprintln(w, "## Applications Skipped")
+		fmt.Fprintln(w, "## "+tr.T(i18n.MarkdownSkipped))
 		fmt.Fprintln(w)
 
-		for _, result := range cat.errors {
-			fmt.Fprintf(w, "### %s\n\n", result.AppName)
-			fmt.Fprintf(w, "| Field | Value |\n")
-			fmt.Fprintf(w, "|-------|-------|\n")
-			fmt.Fprintf(w, "| **Project** | %s |\n", result.Project)
-			fmt.Fprintf(w, "| **Chart** | %s |\n", result.ChartName)
-			fmt.Fprintf(w, "| **Repository** | %s |\n", result.RepoURL)
-			fmt.Fprintf(w, "| **Error** | %s |\n\n", result.Error)
+		for _, group := range groupResults(cat.errors, cat.layout.groupBy) {
+			if heading := groupHeading(group, cat.layout.groupBy, tr); heading != "" {
+				fmt.Fprintf(w, "### %s\n\n", heading)
+			}
+			for _, result := range group.results {
+				fmt.Fprintf(w, "%s %s\n\n", appHeading, markdownAppHeading(result))
+				fmt.Fprintf(w, "| %s | %s |\n", tr.T(i18n.FieldName), tr.T(i18n.FieldValue))
+				fmt.Fprintf(w, "|-------|-------|\n")
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldProject), result.Project)
+				if result.Namespace != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldNamespace), result.Namespace)
+				}
+				if result.Instance != "" {
+					fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldInstance), result.Instance)
+				}
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldChart), result.ChartName)
+				fmt.Fprintf(w, "| **%s** | %s |\n", tr.T(i18n.FieldRepository), result.RepoURL)
+				fmt.Fprintf(w, "| **%s** | %s |\n\n", tr.T(i18n.FieldError), formatResultError(result))
+			}
 		}
 	}
 
 	return nil
 }
 
+// markdownAppHeading returns the application name, linked to its ArgoCD page when known
+func markdownAppHeading(result ApplicationCheckResult) string {
+	if result.URL == "" {
+		return result.AppName
+	}
+	return fmt.Sprintf("[%s](%s)", result.AppName, result.URL)
+}
+
+// notifyOptions controls how notifications are built and sent
+type notifyOptions struct {
+	store          *state.Store            // Skips acknowledged updates and tracks sent ones (serve mode)
+	withActions    bool                    // Attaches Ack/Snooze buttons when the notifier supports them
+	groupByProject bool                    // Sends separate messages per ArgoCD project
+	emojis         map[string]string       // Emoji shown before each update, by severity
+	localizer      *i18n.Localizer         // Language of the message text (default: English)
+	templates      *notification.Templates // Custom subject and body templates, nil for the built-in layout
+	channel        string                  // Notification channel, exposed to templates
+}
+
+// notifyOptionsFromConfig returns the notification options set in the configuration
+func notifyOptionsFromConfig(cfg *config.Config) notifyOptions {
+	return notifyOptions{
+		groupByProject: cfg.NotificationGrouping == config.NotificationGroupingProject,
+		emojis:         notificationStyle(cfg).Emojis,
+		localizer:      i18n.New(cfg.Language),
+		channel:        cfg.NotificationChannel,
+	}
+}
+
+// notificationStyle returns the notification style set in the configuration
+func notificationStyle(cfg *config.Config) notification.Style {
+	return notification.Style{
+		Emojis: map[string]string{
+			notification.SeveritySecurity: cfg.NotificationEmojiSecurity,
+			notification.SeverityMajor:    cfg.NotificationEmojiMajor,
+			notification.SeverityMinor:    cfg.NotificationEmojiMinor,
+			notification.SeverityPatch:    cfg.NotificationEmojiPatch,
+		},
+		Colors: map[string]string{
+			notification.SeveritySecurity: cfg.NotificationColorSecurity,
+			notification.SeverityMajor:    cfg.NotificationColorMajor,
+			notification.SeverityMinor:    cfg.NotificationColorMinor,
+			notification.SeverityPatch:    cfg.NotificationColorPatch,
+		},
+		ThemeColor: cfg.NotificationThemeColor,
+		SenderName: cfg.NotificationSenderName,
+		IconEmoji:  cfg.NotificationIconEmoji,
+		IconURL:    cfg.NotificationIconURL,
+	}
+}
+
 // sendNotifications sends notifications via the configured notifier
 func sendNotifications(ctx context.Context, notifier notification.Notifier, results []ApplicationCheckResult, logger *logrus.Entry) error {
+	return sendNotificationsWithOptions(ctx, notifier, results, notifyOptions{}, logger)
+}
+
+// sendNotificationsWithOptions sends notifications, skipping updates acknowledged in the state store
+// With withActions set and a notifier that supports it, Ack/Snooze buttons are attached to each update
+func sendNotificationsWithOptions(ctx context.Context, notifier notification.Notifier, results []ApplicationCheckResult, opts notifyOptions, logger *logrus.Entry) error {
+	store := opts.store
+
+	// Report notifiers get the complete results of every scan, whether or not updates are available
+	now := time.Now()
+	if reportNotifier, ok := notifier.(notification.ReportNotifier); ok {
+		logger.Info("Sending scan report")
+		if err := reportNotifier.SendReport(ctx, buildScanReport(results, store, now)); err != nil {
+			return fmt.Errorf("failed to send scan report: %w", err)
+		}
+		return nil
+	}
+
 	// Check if there are updates in a single loop
 	var updatesAvailable []ApplicationCheckResult
 	for _, result := range results {
-		if result.HasUpdate {
-			updatesAvailable = append(updatesAvailable, result)
+		if !result.HasUpdate {
+			continue
+		}
+		if store != nil && store.IsAcknowledged(result.AppName, result.LatestVersion, now) {
+			logger.WithFields(logrus.Fields{
+				"app_name":       result.AppName,
+				"latest_version": result.LatestVersion,
+			}).Debug("Skipping acknowledged update")
+			continue
 		}
+		updatesAvailable = append(updatesAvailable, result)
 	}
 
 	if len(updatesAvailable) == 0 {
@@ -703,35 +2338,64 @@ func sendNotifications(ctx context.Context, notifier notification.Notifier, resu
 	}
 
 	// Convert to notification format
-	var updates []notification.ApplicationUpdate
-	for _, result := range updatesAvailable {
-		updates = append(updates, notification.ApplicationUpdate{
-			AppName:                    result.AppName,
-			Project:                    result.Project,
-			ChartName:                  result.ChartName,
-			CurrentVersion:             result.CurrentVersion,
-			LatestVersion:              result.LatestVersion,
-			RepoURL:                    result.RepoURL,
-			ConstraintApplied:          result.ConstraintApplied,
-			HasUpdateOutsideConstraint: result.HasUpdateOutsideConstraint,
-			LatestVersionAll:           result.LatestVersionAll,
-		})
+	updates := toApplicationUpdates(updatesAvailable)
+
+	// Event-based notifiers publish one structured event per update instead of text messages
+	if eventNotifier, ok := notifier.(notification.EventNotifier); ok {
+		logger.WithField("event_count", len(updates)).Info("Publishing update events")
+		if err := eventNotifier.SendUpdates(ctx, updates); err != nil {
+			return fmt.Errorf("failed to publish update events: %w", err)
+		}
+		logger.Info("Successfully published all update events")
+		return nil
 	}
 
 	// Build notification messages using the formatter
 	formatter := notification.NewMessageFormatter()
-	messages := formatter.FormatMessages(updates)
+	formatter.Emojis = opts.emojis
+	formatter.Localizer = opts.localizer
+	var messages []notification.FormattedMessage
+	if opts.groupByProject {
+		messages = formatter.FormatProjectMessageGroups(updates)
+	} else {
+		messages = formatter.FormatMessageGroups(updates)
+	}
+	summary := notificationSummary(results, opts.localizer)
+	for i := range messages {
+		messages[i].Summary = summary
+	}
+	subjects := notificationSubjects(messages, opts.localizer)
+	if opts.templates != nil {
+		if err := applyNotificationTemplates(opts, messages, subjects, results, now); err != nil {
+			return err
+		}
+	}
 
 	logger.WithField("message_count", len(messages)).Info("Sending notifications")
 
+	interactive, isInteractive := notifier.(notification.InteractiveNotifier)
+	messageNotifier, hasLayout := notifier.(notification.MessageNotifier)
+	severityNotifier, hasSeverity := notifier.(notification.SeverityNotifier)
+
 	// Send all messages
 	for i, msg := range messages {
-		subject := fmt.Sprintf("Argazer Notification: %d Helm Chart Update(s) Available", len(updatesAvailable))
-		if len(messages) > 1 {
-			subject = fmt.Sprintf("Argazer Notification [%d/%d]: %d Update(s)", i+1, len(messages), len(updatesAvailable))
+		subject := subjects[i]
+
+		var err error
+		if store != nil && opts.withActions && isInteractive {
+			var actions [][]notification.Action
+			actions, err = buildUpdateActions(store, msg.Updates)
+			if err == nil {
+				err = interactive.SendWithActions(ctx, subject, msg.Text, actions)
+			}
+		} else if hasLayout {
+			err = messageNotifier.SendMessage(ctx, subject, msg)
+		} else if hasSeverity {
+			err = severityNotifier.SendWithSeverity(ctx, subject, msg.Text, msg.Severity)
+		} else {
+			err = notifier.Send(ctx, subject, msg.Text)
 		}
-
-		if err := notifier.Send(ctx, subject, msg); err != nil {
+		if err != nil {
 			return fmt.Errorf("failed to send notification %d/%d: %w", i+1, len(messages), err)
 		}
 	}
@@ -740,6 +2404,278 @@ func sendNotifications(ctx context.Context, notifier notification.Notifier, resu
 	return nil
 }
 
+// notificationSubjects builds the subject of each message
+// Split messages are numbered within their project, or across all messages without grouping.
+func notificationSubjects(messages []notification.FormattedMessage, tr *i18n.Localizer) []string {
+	updateCounts := make(map[string]int)
+	messageCounts := make(map[string]int)
+	for _, msg := range messages {
+		updateCounts[msg.Project] += len(msg.Updates)
+		messageCounts[msg.Project]++
+	}
+
+	subjects := make([]string, len(messages))
+	positions := make(map[string]int)
+	for i, msg := range messages {
+		positions[msg.Project]++
+
+		label := msg.Project
+		if messageCounts[msg.Project] > 1 {
+			label = strings.TrimSpace(fmt.Sprintf("%s %d/%d", msg.Project, positions[msg.Project], messageCounts[msg.Project]))
+		}
+
+		switch {
+		case messageCounts[msg.Project] > 1:
+			subjects[i] = tr.T(i18n.SubjectSplitUpdates, label, updateCounts[msg.Project])
+		case label != "":
+			subjects[i] = tr.T(i18n.SubjectProjectUpdates, label, updateCounts[msg.Project])
+		default:
+			subjects[i] = tr.T(i18n.SubjectUpdates, updateCounts[msg.Project])
+		}
+	}
+	return subjects
+}
+
+// notificationSummary describes the scan totals in a single line, e.g.
+// "Total applications checked: 12 · Up to date: 9 · Updates available: 2 · Skipped: 1"
+func notificationSummary(results []ApplicationCheckResult, tr *i18n.Localizer) string {
+	stats := processResults(results).stats
+	parts := []string{
+		fmt.Sprintf("%s: %d", tr.T(i18n.LabelTotal), stats.total),
+		fmt.Sprintf("%s: %d", tr.T(i18n.LabelUpToDate), stats.upToDate),
+		fmt.Sprintf("%s: %d", tr.T(i18n.LabelUpdates), stats.updates),
+	}
+	if stats.ignored > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %d", tr.T(i18n.LabelIgnored), stats.ignored))
+	}
+	if stats.skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %d", tr.T(i18n.LabelSkipped), stats.skipped))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// applyNotificationTemplates renders the subject, and the text when a body template is set, of each message
+// Messages rendered from a body template drop their per-application sections so every channel sends the rendered text.
+func applyNotificationTemplates(opts notifyOptions, messages []notification.FormattedMessage, subjects []string, results []ApplicationCheckResult, now time.Time) error {
+	stats := processResults(results).stats
+	summary := notification.TemplateSummary{
+		Total:    stats.total,
+		Updates:  stats.updates,
+		UpToDate: stats.upToDate,
+		Errors:   stats.skipped,
+		Ignored:  stats.ignored,
+	}
+	messageCounts := make(map[string]int)
+	for _, msg := range messages {
+		summary.Notified += len(msg.Updates)
+		messageCounts[msg.Project]++
+	}
+	scan := notification.TemplateScan{Time: now, Channel: opts.channel, Version: version}
+
+	positions := make(map[string]int)
+	for i := range messages {
+		msg := &messages[i]
+		positions[msg.Project]++
+
+		subject, body, err := opts.templates.Render(notification.TemplateData{
+			Subject:  subjects[i],
+			Text:     msg.Text,
+			Updates:  notification.NewTemplateUpdates(msg.Updates),
+			Project:  msg.Project,
+			Part:     positions[msg.Project],
+			Parts:    messageCounts[msg.Project],
+			Severity: msg.Severity,
+			Summary:  summary,
+			Scan:     scan,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to render notification %d/%d: %w", i+1, len(messages), err)
+		}
+		subjects[i] = subject
+		if opts.templates.HasBody() {
+			msg.Text = body
+			msg.Sections = nil
+		}
+	}
+	return nil
+}
+
+// sendEvents publishes one event per available update to an event sink
+// Unlike notifications, events are not filtered by acknowledgements so the sink sees every scan.
+func sendEvents(ctx context.Context, sink notification.EventNotifier, results []ApplicationCheckResult, logger *logrus.Entry) error {
+	var updatesAvailable []ApplicationCheckResult
+	for _, result := range results {
+		if result.HasUpdate {
+			updatesAvailable = append(updatesAvailable, result)
+		}
+	}
+
+	if len(updatesAvailable) == 0 {
+		return nil
+	}
+
+	logger.WithField("event_count", len(updatesAvailable)).Debug("Publishing update events to sink")
+	return sink.SendUpdates(ctx, toApplicationUpdates(updatesAvailable))
+}
+
+// bitbucketConfig builds the Bitbucket pull request settings from the application config and Pipelines environment
+func bitbucketConfig(cfg *config.Config) prcomment.BitbucketConfig {
+	return prcomment.BitbucketConfigFromEnv(prcomment.BitbucketConfig{
+		URL:         cfg.BitbucketURL,
+		Token:       cfg.BitbucketToken,
+		Username:    cfg.BitbucketUsername,
+		Password:    cfg.BitbucketPassword,
+		Workspace:   cfg.BitbucketWorkspace,
+		Repository:  cfg.BitbucketRepository,
+		PullRequest: cfg.BitbucketPRID,
+	})
+}
+
+// postPRComment posts the compact markdown report as a pull/merge request comment
+// The compact layout keeps large scans within the comment size limit. Platforms that don't
+// render HTML get plain headings instead of collapsible sections.
+func postPRComment(ctx context.Context, poster prcomment.Poster, cat categorizedResults, tr *i18n.Localizer) error {
+	_, plain := poster.(prcomment.PlainPoster)
+
+	var report bytes.Buffer
+	if err := renderCompactReport(cat, tr, &report, !plain); err != nil {
+		return err
+	}
+	return poster.Post(ctx, report.String())
+}
+
+// outputDetail is a labeled line of an application in the table and markdown reports
+type outputDetail struct {
+	label, value string
+}
+
+// artifactHubDetails lists the Artifact Hub metadata of a chart, with links in markdown syntax if requested
+func artifactHubDetails(pkg *artifacthub.Package, tr *i18n.Localizer, markdown bool) []outputDetail {
+	if pkg == nil {
+		return nil
+	}
+	link := func(name, target string) string {
+		if markdown {
+			return fmt.Sprintf("[%s](%s)", name, target)
+		}
+		return fmt.Sprintf("%s (%s)", name, target)
+	}
+
+	page := pkg.URL
+	if pkg.Deprecated {
+		page += " (" + tr.T(i18n.ChartDeprecated) + ")"
+	}
+	details := []outputDetail{{tr.T(i18n.FieldArtifactHub), page}}
+	if pkg.SecurityReport != nil {
+		details = append(details, outputDetail{tr.T(i18n.FieldSecurityReport), pkg.SecurityReport.String()})
+	}
+	if len(pkg.Maintainers) > 0 {
+		details = append(details, outputDetail{tr.T(i18n.FieldMaintainers), strings.Join(pkg.Maintainers, ", ")})
+	}
+	if len(pkg.Links) > 0 {
+		links := make([]string, len(pkg.Links))
+		for i, l := range pkg.Links {
+			links[i] = link(l.Name, l.URL)
+		}
+		details = append(details, outputDetail{tr.T(i18n.FieldLinks), strings.Join(links, ", ")})
+	}
+	return details
+}
+
+// renderMarkdownReleaseNotes writes an update's release notes excerpt as a quote below its table
+func renderMarkdownReleaseNotes(result ApplicationCheckResult, tr *i18n.Localizer, w io.Writer) {
+	if result.ReleaseNotes == "" && result.ReleaseNotesURL == "" {
+		return
+	}
+	title := tr.T(i18n.FieldReleaseNotes)
+	if result.ReleaseNotesURL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, result.ReleaseNotesURL)
+	}
+	fmt.Fprintf(w, "**%s**\n\n", title)
+	if result.ReleaseNotes != "" {
+		for _, line := range strings.Split(result.ReleaseNotes, "\n") {
+			fmt.Fprintln(w, strings.TrimRight("> "+line, " "))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// formatValuesDiff summarizes the default values keys an update adds, removes and changes
+func formatValuesDiff(diff *helm.ValuesDiff, tr *i18n.Localizer) string {
+	return tr.T(i18n.ValuesDiffSummary, len(diff.Added), len(diff.Removed), len(diff.Changed))
+}
+
+// maxValuesDiffKeys caps the keys listed below an update in table and markdown output
+const maxValuesDiffKeys = 20
+
+// valuesDiffKeys lists the keys of a values diff marked "+" (added), "-" (removed) or "~" (changed),
+// up to maxValuesDiffKeys with a count of the rest; JSON output has all of them
+func valuesDiffKeys(diff *helm.ValuesDiff) []string {
+	var keys []string
+	for _, group := range []struct {
+		mark string
+		keys []string
+	}{{"+", diff.Added}, {"-", diff.Removed}, {"~", diff.Changed}} {
+		for _, key := range group.keys {
+			keys = append(keys, group.mark+" "+key)
+		}
+	}
+	if len(keys) > maxValuesDiffKeys {
+		keys = append(keys[:maxValuesDiffKeys], fmt.Sprintf("… (+%d)", len(keys)-maxValuesDiffKeys))
+	}
+	return keys
+}
+
+// toApplicationUpdates converts check results to the notification format
+func toApplicationUpdates(results []ApplicationCheckResult) []notification.ApplicationUpdate {
+	updates := make([]notification.ApplicationUpdate, 0, len(results))
+	for _, result := range results {
+		var fixedVulnerabilities int
+		if result.SecurityFixes != nil {
+			fixedVulnerabilities = len(result.SecurityFixes.Fixed)
+		}
+		updates = append(updates, notification.ApplicationUpdate{
+			AppName:                    result.AppName,
+			Namespace:                  result.Namespace,
+			Project:                    result.Project,
+			Instance:                   result.Instance,
+			ChartName:                  result.ChartName,
+			CurrentVersion:             result.CurrentVersion,
+			LatestVersion:              result.LatestVersion,
+			RepoURL:                    result.RepoURL,
+			ConstraintApplied:          result.ConstraintApplied,
+			HasUpdateOutsideConstraint: result.HasUpdateOutsideConstraint,
+			LatestVersionAll:           result.LatestVersionAll,
+			SyncBlocked:                result.SyncBlocked,
+			SyncBlockedBy:              result.SyncBlockedBy,
+			NextSyncWindow:             result.NextSyncWindow,
+			URL:                        result.URL,
+			ReleaseNotes:               result.ReleaseNotes,
+			ReleaseNotesURL:            result.ReleaseNotesURL,
+			FixedVulnerabilities:       fixedVulnerabilities,
+		})
+	}
+	return updates
+}
+
+// buildUpdateActions registers updates in the state store and returns one row of Ack/Snooze buttons per update,
+// followed by a link to the application in ArgoCD
+func buildUpdateActions(store *state.Store, updates []notification.ApplicationUpdate) ([][]notification.Action, error) {
+	actions := make([][]notification.Action, 0, len(updates))
+	for _, update := range updates {
+		id, err := store.TrackNotification(update.AppName, update.LatestVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record notification state: %w", err)
+		}
+		row := server.UpdateActions(update.AppName, id)
+		if update.URL != "" {
+			row = append(row, notification.Action{Label: "Open in ArgoCD", URL: update.URL})
+		}
+		actions = append(actions, row)
+	}
+	return actions, nil
+}
+
 // setupLogging configures the logging system
 func setupLogging(verbose bool, format string) *logrus.Entry {
 	if verbose {
@@ -761,6 +2697,16 @@ func setupLogging(verbose bool, format string) *logrus.Entry {
 	return logrus.WithField("service", "argazer")
 }
 
+// withTimeout runs fn with the context bounded by timeout, or unbounded when the timeout is zero
+func withTimeout(ctx context.Context, timeout time.Duration, fn func(context.Context) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fn(ctx)
+}
+
 // setupSignalHandler creates a context that is cancelled on SIGINT or SIGTERM
 // This allows for graceful shutdown of the application
 func setupSignalHandler(logger *logrus.Entry) (context.Context, context.CancelFunc) {
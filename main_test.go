@@ -1,15 +1,31 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
 	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"argazer/internal/argocd"
+	"argazer/internal/artifacthub"
 	"argazer/internal/config"
+	"argazer/internal/helm"
+	"argazer/internal/i18n"
 	"argazer/internal/notification"
+	"argazer/internal/state"
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -289,6 +305,36 @@ func TestOutputResults(t *testing.T) {
 			},
 			formats: []string{"table", "json", "markdown"},
 		},
+		{
+			name: "with relocated chart",
+			results: []ApplicationCheckResult{
+				{
+					AppName:        "app1",
+					Project:        "default",
+					ChartName:      "nginx",
+					CurrentVersion: "1.0.0",
+					LatestVersion:  "1.0.0",
+					RepoURL:        "https://charts.bitnami.com/bitnami",
+					RelocatedTo:    "chart relocated to oci://registry-1.docker.io/bitnamicharts",
+				},
+			},
+			formats: []string{"table", "json", "markdown"},
+		},
+		{
+			name: "with tracking branch",
+			results: []ApplicationCheckResult{
+				{
+					AppName:        "app1",
+					Project:        "default",
+					ChartName:      "charts/app",
+					CurrentVersion: "0.3.0",
+					LatestVersion:  "0.3.0",
+					RepoURL:        "https://github.com/example/charts.git",
+					TrackingBranch: "main",
+				},
+			},
+			formats: []string{"table", "json", "markdown"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -296,7 +342,7 @@ func TestOutputResults(t *testing.T) {
 			t.Run(tt.name+"_"+format, func(t *testing.T) {
 				// Just ensure it doesn't panic
 				assert.NotPanics(t, func() {
-					err := outputResults(tt.results, format, io.Discard)
+					err := outputResults(tt.results, format, nil, io.Discard)
 					assert.NoError(t, err)
 				})
 			})
@@ -304,6 +350,291 @@ func TestOutputResults(t *testing.T) {
 	}
 }
 
+func TestProcessResults_Relocated(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "moved", RelocatedTo: "chart relocated to https://new.example.com", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+		{AppName: "deprecated", RelocatedTo: "chart relocated to https://new.example.com", CurrentVersion: "1.0.0", LatestVersion: "1.2.0", HasUpdate: true},
+		{AppName: "current", LatestVersion: "1.0.0", CurrentVersion: "1.0.0"},
+	}
+
+	cat := processResults(results)
+	assert.Equal(t, 3, cat.stats.total)
+	assert.Equal(t, 1, cat.stats.relocated)
+	assert.Equal(t, 1, cat.stats.updates, "a relocated chart with a newer version is still an update")
+	assert.Equal(t, 1, cat.stats.upToDate)
+	require.Len(t, cat.relocated, 1)
+	assert.Equal(t, "moved", cat.relocated[0].AppName)
+	require.Len(t, cat.updatesAvailable, 1)
+	assert.Equal(t, "deprecated", cat.updatesAvailable[0].AppName)
+	assert.Equal(t, reportStatusRelocated, reportStatus(results[0]))
+	assert.Equal(t, reportStatusUpdateAvailable, reportStatus(results[1]))
+}
+
+func TestSetBranchUpdate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	result := ApplicationCheckResult{TrackingBranch: "main", CurrentVersion: "0.4.0", LatestVersion: "0.4.0"}
+	require.True(t, setBranchUpdate(&result, "a1b2c3d", "0.3.2", now))
+	assert.Equal(t, "a1b2c3d", result.SyncedRevision)
+	assert.Equal(t, "0.3.2", result.CurrentVersion)
+	assert.Equal(t, "0.4.0", result.LatestVersion)
+	assert.True(t, result.HasUpdate)
+	assert.Equal(t, "minor", result.Severity)
+	assert.Equal(t, 3, result.StalenessScore)
+
+	upToDate := ApplicationCheckResult{TrackingBranch: "main", CurrentVersion: "0.4.0", LatestVersion: "0.4.0"}
+	assert.False(t, setBranchUpdate(&upToDate, "d4e5f6a", "0.4.0", now))
+	assert.Equal(t, "d4e5f6a", upToDate.SyncedRevision)
+	assert.False(t, upToDate.HasUpdate)
+	assert.Empty(t, upToDate.Severity)
+}
+
+func TestProcessResults_TrackingBranch(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "branch", TrackingBranch: "HEAD", CurrentVersion: "0.3.0", LatestVersion: "0.3.0"},
+		{AppName: "outdated", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "branch-behind", TrackingBranch: "main", SyncedRevision: "a1b2c3d", CurrentVersion: "0.3.0", LatestVersion: "0.4.0", HasUpdate: true},
+	}
+
+	cat := processResults(results)
+	assert.Equal(t, 3, cat.stats.total)
+	assert.Equal(t, 1, cat.stats.tracking)
+	assert.Equal(t, 2, cat.stats.updates)
+	assert.Equal(t, 0, cat.stats.upToDate)
+	require.Len(t, cat.trackingBranch, 1)
+	assert.Equal(t, "branch", cat.trackingBranch[0].AppName)
+	assert.Equal(t, reportStatusTrackingBranch, reportStatus(results[0]))
+	assert.Equal(t, reportStatusUpdateAvailable, reportStatus(results[2]), "branches behind their tip are reported as updates")
+}
+
+func TestProcessResults_Drifted(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "drifted", CurrentVersion: "1.3.0", DeployedVersion: "1.2.0", LatestVersion: "1.4.0", HasUpdate: true},
+		{AppName: "outdated", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+	}
+
+	cat := processResults(results)
+	assert.Equal(t, 2, cat.stats.total)
+	assert.Equal(t, 1, cat.stats.drifted)
+	assert.Equal(t, 1, cat.stats.updates)
+	require.Len(t, cat.drifted, 1)
+	assert.Equal(t, "drifted", cat.drifted[0].AppName)
+
+	var buf bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &buf))
+	assert.Contains(t, buf.String(), "DEPLOYED VERSION DIFFERS FROM DECLARED:")
+	assert.Contains(t, buf.String(), "Deployed Version: 1.2.0")
+}
+
+func TestOutputResults_Namespace(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "web", Namespace: "team-a", Project: "default", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "web", Namespace: "team-b", Project: "default", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Contains(t, table.String(), "\nteam-a/web ")
+	assert.Contains(t, table.String(), "\nteam-b/web ")
+
+	var md bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &md))
+	assert.Contains(t, md.String(), "| **Namespace** | team-a |")
+
+	updates := toApplicationUpdates(results)
+	assert.Equal(t, "team-b", updates[1].Namespace)
+}
+
+func TestOutputResults_Severity(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "web", Project: "default", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true, Severity: "major"},
+		{AppName: "legacy", Project: "default", CurrentVersion: "latest", LatestVersion: "stable", HasUpdate: true},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Regexp(t, `\nweb\s+default\s+-\s+1.0.0\s+2.0.0\s+major\s+-\n`, table.String())
+	assert.Regexp(t, `\nlegacy\s+default\s+-\s+latest\s+stable\s+-\s+-\n`, table.String(), "updates without a semver severity leave the cell empty")
+
+	var md bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", i18n.New("de"), &md))
+	assert.Contains(t, md.String(), "| **Schweregrad** | major |")
+}
+
+func TestOutputResults_ReleaseNotes(t *testing.T) {
+	results := []ApplicationCheckResult{{
+		AppName:         "web",
+		Project:         "default",
+		CurrentVersion:  "1.0.0",
+		LatestVersion:   "1.2.0",
+		HasUpdate:       true,
+		ReleaseNotes:    "1.2.0:\n- added: Ingress support",
+		ReleaseNotesURL: "https://github.com/org/charts/releases/tag/web-1.2.0",
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Contains(t, table.String(), "  Release Notes: https://github.com/org/charts/releases/tag/web-1.2.0\n    1.2.0:\n    - added: Ingress support\n")
+
+	var md bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &md))
+	assert.Contains(t, md.String(), "**[Release Notes](https://github.com/org/charts/releases/tag/web-1.2.0)**\n\n> 1.2.0:\n> - added: Ingress support\n")
+
+	updates := toApplicationUpdates(results)
+	assert.Equal(t, "https://github.com/org/charts/releases/tag/web-1.2.0", updates[0].ReleaseNotesURL)
+}
+
+func TestOutputResults_ValuesDiff(t *testing.T) {
+	results := []ApplicationCheckResult{{
+		AppName:        "web",
+		Project:        "default",
+		CurrentVersion: "1.0.0",
+		LatestVersion:  "1.2.0",
+		HasUpdate:      true,
+		ValuesDiff: &helm.ValuesDiff{
+			Added:   []string{"image.pullPolicy"},
+			Removed: []string{"legacy.port"},
+			Changed: []string{"image.tag"},
+		},
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Contains(t, table.String(), "  Default Values: 1 added, 1 removed, 1 changed\n    + image.pullPolicy\n    - legacy.port\n    ~ image.tag\n")
+
+	var md bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &md))
+	assert.Contains(t, md.String(), "| **Default Values** | 1 added, 1 removed, 1 changed<br>`+ image.pullPolicy` `- legacy.port` `~ image.tag` |\n")
+
+	var out bytes.Buffer
+	require.NoError(t, outputResults(results, "json", nil, &out))
+	assert.Contains(t, out.String(), `"values_diff": {`)
+
+	many := &helm.ValuesDiff{}
+	for i := range maxValuesDiffKeys + 5 {
+		many.Added = append(many.Added, fmt.Sprintf("key%02d", i))
+	}
+	keys := valuesDiffKeys(many)
+	assert.Len(t, keys, maxValuesDiffKeys+1)
+	assert.Equal(t, "… (+5)", keys[maxValuesDiffKeys])
+}
+
+func TestOutputResults_ArtifactHub(t *testing.T) {
+	results := []ApplicationCheckResult{{
+		AppName:        "web",
+		Project:        "default",
+		CurrentVersion: "15.0.0",
+		LatestVersion:  "15.1.0",
+		HasUpdate:      true,
+		ArtifactHub: &artifacthub.Package{
+			URL:            "https://artifacthub.io/packages/helm/bitnami/nginx",
+			Deprecated:     true,
+			SecurityReport: &artifacthub.SecurityReport{Critical: 1, High: 2},
+			Maintainers:    []string{"Broadcom"},
+			Links:          []artifacthub.Link{{Name: "Source", URL: "https://github.com/bitnami/charts"}},
+		},
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Contains(t, table.String(), "  Artifact Hub: https://artifacthub.io/packages/helm/bitnami/nginx (deprecated)\n"+
+		"  Security Report: 1 critical, 2 high\n"+
+		"  Maintainers: Broadcom\n"+
+		"  Links: Source (https://github.com/bitnami/charts)\n")
+
+	var md bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &md))
+	assert.Contains(t, md.String(), "| **Security Report** | 1 critical, 2 high |\n")
+	assert.Contains(t, md.String(), "| **Links** | [Source](https://github.com/bitnami/charts) |\n\n")
+
+	var out bytes.Buffer
+	require.NoError(t, outputResults(results, "json", nil, &out))
+	assert.Contains(t, out.String(), `"deprecated": true`)
+}
+
+func TestRenderMarkdown_Link(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "app1", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true, URL: "https://argocd.example.com/applications/argocd/app1"},
+		{AppName: "app2", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &buf))
+	assert.Contains(t, buf.String(), "### [app1](https://argocd.example.com/applications/argocd/app1)")
+	assert.Contains(t, buf.String(), "### app2\n")
+}
+
+func TestBuildUpdateActions_Link(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "state.json"), logger)
+	require.NoError(t, err)
+
+	updates := []notification.ApplicationUpdate{
+		{AppName: "app1", LatestVersion: "1.1.0", URL: "https://argocd.example.com/applications/argocd/app1"},
+		{AppName: "app2", LatestVersion: "2.0.0"},
+	}
+
+	actions, err := buildUpdateActions(store, updates)
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	require.Len(t, actions[0], 3)
+	assert.Equal(t, "https://argocd.example.com/applications/argocd/app1", actions[0][2].URL)
+	assert.Len(t, actions[1], 2)
+}
+
+func TestOutputResults_ValuesSources(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{
+			AppName:        "app1",
+			CurrentVersion: "1.0.0",
+			LatestVersion:  "1.1.0",
+			HasUpdate:      true,
+			ValuesSources: []argocd.ValuesRef{
+				{Ref: "values", RepoURL: "https://github.com/org/config", TargetRevision: "main", Paths: []string{"apps/app1/values.yaml"}},
+			},
+		},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Contains(t, table.String(), "  Values: https://github.com/org/config@main: apps/app1/values.yaml\n")
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, outputResults(results, "json", nil, &jsonOut))
+	assert.Contains(t, jsonOut.String(), `"values_sources"`)
+	assert.Contains(t, jsonOut.String(), `"paths": [`)
+}
+
+func TestFormatResultError(t *testing.T) {
+	assert.Equal(t, "[TIMEOUT] Helm repository lookup timed out after 30s", formatResultError(ApplicationCheckResult{Error: "Helm repository lookup timed out after 30s", ErrorCode: "TIMEOUT"}))
+	assert.Equal(t, "chart not found", formatResultError(ApplicationCheckResult{Error: "chart not found"}))
+}
+
+func TestFormatCurrentVersion(t *testing.T) {
+	assert.Equal(t, "1.2.3", formatCurrentVersion(ApplicationCheckResult{CurrentVersion: "1.2.3"}, nil))
+
+	digest := ApplicationCheckResult{
+		CurrentVersion: "1.2.3",
+		PinnedRevision: "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945",
+		PinnedBy:       "digest",
+	}
+	assert.Equal(t, "1.2.3 (pinned by digest sha256:4f53cda18c2b)", formatCurrentVersion(digest, nil))
+
+	commit := ApplicationCheckResult{
+		CurrentVersion: "0.4.0",
+		PinnedRevision: "a94a8fe5ccb19ba61c4c0873d391e987982fbbd3",
+		PinnedBy:       "commit",
+	}
+	assert.Equal(t, "0.4.0 (pinned by commit a94a8fe5ccb1)", formatCurrentVersion(commit, nil))
+
+	mutable := ApplicationCheckResult{
+		CurrentVersion:     "1.20.0",
+		MutableTag:         "latest",
+		RecommendedVersion: "1.21.0",
+	}
+	assert.Equal(t, "1.20.0 (via mutable tag 'latest', pin to 1.21.0)", formatCurrentVersion(mutable, nil))
+}
+
 func TestOutputResults_InvalidFormat(t *testing.T) {
 	results := []ApplicationCheckResult{
 		{
@@ -312,7 +643,7 @@ func TestOutputResults_InvalidFormat(t *testing.T) {
 		},
 	}
 
-	err := outputResults(results, "invalid", io.Discard)
+	err := outputResults(results, "invalid", nil, io.Discard)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown output format")
 }
@@ -365,6 +696,41 @@ func TestRenderJSON(t *testing.T) {
 	}
 }
 
+func TestRenderJSON_SchemaVersion(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, renderJSON(processResults(nil), &out))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &document))
+	assert.EqualValues(t, jsonSchemaVersion, document["schema_version"])
+}
+
+func TestRenderJSONLines(t *testing.T) {
+	cat := processResults([]ApplicationCheckResult{
+		{AppName: "web", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+		{AppName: "api", Project: "default", ChartName: "api", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true, Severity: "major"},
+		{AppName: "db", Project: "default", Error: "connection refused", ErrorCode: "REPO_UNREACHABLE"},
+	})
+	cat.layout.jsonCompact = true
+
+	var out bytes.Buffer
+	require.NoError(t, renderResults(cat, config.OutputFormatJSON, i18n.New("en"), &out))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	var statuses []string
+	for _, line := range lines {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), "every line is a JSON object")
+		assert.EqualValues(t, jsonSchemaVersion, entry["schema_version"])
+		statuses = append(statuses, entry["status"].(string))
+	}
+	assert.Equal(t, []string{reportStatusUpdateAvailable, reportStatusUpToDate, reportStatusError}, statuses)
+	assert.Contains(t, lines[0], `"app_name":"api"`)
+	assert.Contains(t, lines[0], `"severity":"major"`)
+	assert.Contains(t, lines[2], `"error_code":"REPO_UNREACHABLE"`)
+}
+
 func TestRenderMarkdown(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -413,13 +779,43 @@ func TestRenderMarkdown(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			assert.NotPanics(t, func() {
 				categorized := processResults(tt.results)
-				err := renderMarkdown(categorized, io.Discard)
+				err := renderMarkdown(categorized, nil, io.Discard)
 				assert.NoError(t, err)
 			})
 		})
 	}
 }
 
+func TestOutputResults_Localized(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{
+			AppName:        "app1",
+			Project:        "default",
+			ChartName:      "chart1",
+			CurrentVersion: "1.0.0",
+			LatestVersion:  "2.0.0",
+			RepoURL:        "https://charts.example.com",
+			HasUpdate:      true,
+		},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, config.OutputFormatTable, i18n.New("de"), &table))
+	assert.Contains(t, table.String(), "ARGAZER-SCANERGEBNISSE")
+	assert.Regexp(t, `ANWENDUNG\s+PROJEKT\s+CHART\s+AKTUELLE VERSION\s+NEUESTE VERSION`, table.String())
+	assert.Regexp(t, `\napp1\s+default\s+chart1\s+1.0.0\s+2.0.0\s`, table.String())
+
+	var markdown bytes.Buffer
+	require.NoError(t, outputResults(results, config.OutputFormatMarkdown, i18n.New("fr"), &markdown))
+	assert.Contains(t, markdown.String(), "# Résultats de l'analyse Argazer")
+	assert.Contains(t, markdown.String(), "| Champ | Valeur |")
+	assert.Contains(t, markdown.String(), "| **Projet** | default |")
+
+	var english bytes.Buffer
+	require.NoError(t, outputResults(results, config.OutputFormatTable, nil, &english))
+	assert.Regexp(t, `APPLICATION\s+PROJECT\s+CHART\s+CURRENT VERSION\s+LATEST VERSION\s+SEVERITY\s+NOTE\n`, english.String())
+}
+
 func TestBuildNotificationMessages(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -582,11 +978,163 @@ func TestScanResults(t *testing.T) {
 func TestClients(t *testing.T) {
 	// Test struct creation
 	c := &clients{}
-	assert.Nil(t, c.argocd)
+	assert.Empty(t, c.instances)
 	assert.Nil(t, c.helm)
 	assert.Nil(t, c.notifier)
 }
 
+func TestScanScopes(t *testing.T) {
+	tokens := map[string]string{"team-b": "token-b", "team-a": "token-a"}
+	appNames := []string{"*"}
+
+	tests := []struct {
+		name       string
+		projects   []string
+		tokens     map[string]string
+		hasAccount bool
+		expected   []scanScope
+		uncovered  []string
+	}{
+		{
+			name:       "account only",
+			projects:   []string{"*"},
+			hasAccount: true,
+			expected: []scanScope{
+				{filter: argocd.FilterOptions{Projects: []string{"*"}, AppNames: appNames}},
+			},
+		},
+		{
+			name:     "tokens only, all projects",
+			projects: []string{"*"},
+			tokens:   tokens,
+			expected: []scanScope{
+				{project: "team-a", filter: argocd.FilterOptions{Projects: []string{"team-a"}, AppNames: appNames}},
+				{project: "team-b", filter: argocd.FilterOptions{Projects: []string{"team-b"}, AppNames: appNames}},
+			},
+		},
+		{
+			name:       "tokens and account, all projects",
+			projects:   []string{"*"},
+			tokens:     tokens,
+			hasAccount: true,
+			expected: []scanScope{
+				{project: "team-a", filter: argocd.FilterOptions{Projects: []string{"team-a"}, AppNames: appNames}},
+				{project: "team-b", filter: argocd.FilterOptions{Projects: []string{"team-b"}, AppNames: appNames}},
+				{filter: argocd.FilterOptions{Projects: []string{"*"}, AppNames: appNames}, exclude: []string{"team-a", "team-b"}},
+			},
+		},
+		{
+			name:       "tokens and account, selected projects",
+			projects:   []string{"team-a", "shared"},
+			tokens:     tokens,
+			hasAccount: true,
+			expected: []scanScope{
+				{project: "team-a", filter: argocd.FilterOptions{Projects: []string{"team-a"}, AppNames: appNames}},
+				{filter: argocd.FilterOptions{Projects: []string{"shared"}, AppNames: appNames}},
+			},
+		},
+		{
+			name:     "tokens only, project without token",
+			projects: []string{"team-b", "shared"},
+			tokens:   tokens,
+			expected: []scanScope{
+				{project: "team-b", filter: argocd.FilterOptions{Projects: []string{"team-b"}, AppNames: appNames}},
+			},
+			uncovered: []string{"shared"},
+		},
+		{
+			name:       "tokens and account, pattern",
+			projects:   []string{"team-*"},
+			tokens:     tokens,
+			hasAccount: true,
+			expected: []scanScope{
+				{project: "team-a", filter: argocd.FilterOptions{Projects: []string{"team-a"}, AppNames: appNames}},
+				{project: "team-b", filter: argocd.FilterOptions{Projects: []string{"team-b"}, AppNames: appNames}},
+				{filter: argocd.FilterOptions{Projects: []string{"team-*"}, AppNames: appNames}, exclude: []string{"team-a", "team-b"}},
+			},
+		},
+		{
+			name:     "tokens only, patterns",
+			projects: []string{"/^team-b$/", "shared-*"},
+			tokens:   tokens,
+			expected: []scanScope{
+				{project: "team-b", filter: argocd.FilterOptions{Projects: []string{"team-b"}, AppNames: appNames}},
+			},
+			uncovered: []string{"shared-*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Projects: tt.projects, AppNames: appNames, ArgocdProjectTokens: tt.tokens}
+			scopes, uncovered := scanScopes(cfg, tt.hasAccount)
+			assert.Equal(t, tt.expected, scopes)
+			assert.Equal(t, tt.uncovered, uncovered)
+		})
+	}
+}
+
+func TestAnnotateSyncWindows(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	now := time.Date(2024, 1, 10, 23, 0, 0, 0, time.UTC) // Wednesday
+
+	apps := []*v1alpha1.Application{
+		{ObjectMeta: metav1.ObjectMeta{Name: "frozen"}, Spec: v1alpha1.ApplicationSpec{Project: "prod"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "free"}, Spec: v1alpha1.ApplicationSpec{Project: "prod"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other"}, Spec: v1alpha1.ApplicationSpec{Project: "broken"}},
+	}
+	results := []ApplicationCheckResult{
+		{AppName: "frozen", Project: "prod", HasUpdate: true},
+		{AppName: "free", Project: "prod", HasUpdate: true},
+		{AppName: "other", Project: "broken", HasUpdate: true},
+	}
+
+	calls := map[string]int{}
+	windowsFor := func(_ context.Context, _, project string) (v1alpha1.SyncWindows, error) {
+		calls[project]++
+		if project == "broken" {
+			return nil, assert.AnError
+		}
+		return v1alpha1.SyncWindows{
+			&v1alpha1.SyncWindow{Kind: "deny", Schedule: "0 22 * * *", Duration: "8h", Applications: []string{"frozen"}},
+		}, nil
+	}
+
+	annotateSyncWindows(context.Background(), apps, results, windowsFor, now, logger)
+
+	assert.True(t, results[0].SyncBlocked)
+	assert.Equal(t, "deny 0 22 * * * (8h)", results[0].SyncBlockedBy)
+	assert.Equal(t, "2024-01-11T06:00:00Z", results[0].NextSyncWindow)
+
+	assert.False(t, results[1].SyncBlocked)
+	assert.Empty(t, results[1].NextSyncWindow)
+
+	assert.False(t, results[2].SyncBlocked)
+
+	assert.Equal(t, 1, calls["prod"], "project windows should be fetched once")
+	assert.Equal(t, 1, calls["broken"])
+}
+
+func TestRenderTable_SyncWindow(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{
+			AppName:        "frozen",
+			Project:        "prod",
+			ChartName:      "nginx",
+			CurrentVersion: "1.0.0",
+			LatestVersion:  "1.1.0",
+			HasUpdate:      true,
+			SyncBlocked:    true,
+			SyncBlockedBy:  "deny 0 22 * * * (8h)",
+			NextSyncWindow: "2024-01-11T06:00:00Z",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &buf))
+	assert.Contains(t, buf.String(), "Sync Window: Deferred until 2024-01-11 06:00 UTC")
+}
+
 func TestBuildNotificationMessages_LongMessages(t *testing.T) {
 	// Create many updates to force message splitting
 	var updates []notification.ApplicationUpdate
@@ -611,6 +1159,160 @@ func TestBuildNotificationMessages_LongMessages(t *testing.T) {
 	for _, msg := range messages {
 		assert.LessOrEqual(t, len(msg), 4096)
 	}
+
+	// Grouped messages should carry exactly the updates they contain
+	groups := formatter.FormatMessageGroups(updates)
+	require.Len(t, groups, len(messages))
+	total := 0
+	for i, group := range groups {
+		assert.Equal(t, messages[i], group.Text)
+		total += len(group.Updates)
+	}
+	assert.Equal(t, len(updates), total)
+}
+
+func TestBuildNotificationMessages_GroupByProject(t *testing.T) {
+	updates := []notification.ApplicationUpdate{
+		{AppName: "api", Project: "production", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0"},
+		{AppName: "grafana", Project: "monitoring", ChartName: "grafana", CurrentVersion: "6.0.0", LatestVersion: "7.0.0"},
+		{AppName: "web", Project: "production", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.2.0"},
+	}
+
+	formatter := notification.NewMessageFormatter()
+	groups := formatter.FormatProjectMessageGroups(updates)
+
+	// One message per project, ordered by project name
+	require.Len(t, groups, 2)
+	assert.Equal(t, "monitoring", groups[0].Project)
+	assert.Len(t, groups[0].Updates, 1)
+	assert.Contains(t, groups[0].Text, "grafana (monitoring)")
+	assert.NotContains(t, groups[0].Text, "production")
+
+	assert.Equal(t, "production", groups[1].Project)
+	require.Len(t, groups[1].Updates, 2)
+	assert.Equal(t, "api", groups[1].Updates[0].AppName)
+	assert.Equal(t, "web", groups[1].Updates[1].AppName)
+}
+
+func TestBuildNotificationMessages_SeverityEmojis(t *testing.T) {
+	updates := []notification.ApplicationUpdate{
+		{AppName: "api", Project: "production", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0"},
+		{AppName: "web", Project: "production", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.0.1"},
+	}
+
+	formatter := notification.NewMessageFormatter()
+	formatter.Emojis = map[string]string{notification.SeverityMajor: "🔴"}
+	groups := formatter.FormatMessageGroups(updates)
+
+	require.Len(t, groups, 1)
+	assert.Contains(t, groups[0].Text, "🔴 api (production)")
+	assert.Contains(t, groups[0].Text, "\nweb (production)", "severities without an emoji are not prefixed")
+	assert.Equal(t, notification.SeverityMajor, groups[0].Severity)
+}
+
+func TestBuildNotificationMessages_Localized(t *testing.T) {
+	updates := []notification.ApplicationUpdate{
+		{
+			AppName:                    "api",
+			Project:                    "production",
+			ChartName:                  "nginx",
+			CurrentVersion:             "1.0.0",
+			LatestVersion:              "1.1.0",
+			RepoURL:                    "https://charts.example.com",
+			ConstraintApplied:          "minor",
+			HasUpdateOutsideConstraint: true,
+			LatestVersionAll:           "2.0.0",
+		},
+	}
+
+	formatter := notification.NewMessageFormatter()
+	formatter.Localizer = i18n.New("es")
+	groups := formatter.FormatMessageGroups(updates)
+
+	require.Len(t, groups, 1)
+	assert.Contains(t, groups[0].Text, "  Versión: 1.0.0 -> 1.1.0\n")
+	assert.Contains(t, groups[0].Text, "  Restricción: minor\n")
+	assert.Contains(t, groups[0].Text, "  Nota: v2.0.0 disponible fuera de la restricción\n")
+	assert.Contains(t, groups[0].Text, "  Repositorio: https://charts.example.com\n")
+}
+
+// MockSeverityNotifier is a mock implementation of the SeverityNotifier interface for testing
+type MockSeverityNotifier struct {
+	MockNotifier
+	Severities []string
+}
+
+func (m *MockSeverityNotifier) SendWithSeverity(ctx context.Context, subject, message, severity string) error {
+	m.Severities = append(m.Severities, severity)
+	return m.SendError
+}
+
+func TestSendNotifications_Severity(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	notifier := &MockSeverityNotifier{}
+
+	results := []ApplicationCheckResult{
+		{AppName: "app1", ChartName: "chart1", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "app2", ChartName: "chart2", CurrentVersion: "1.0.0", LatestVersion: "1.0.1", HasUpdate: true},
+	}
+
+	require.NoError(t, sendNotifications(context.Background(), notifier, results, logger))
+	assert.Equal(t, []string{notification.SeverityMinor}, notifier.Severities)
+	assert.False(t, notifier.SendCalled)
+}
+
+func TestNotificationSubjects(t *testing.T) {
+	oneUpdate := []notification.ApplicationUpdate{{AppName: "app1"}}
+	twoUpdates := []notification.ApplicationUpdate{{AppName: "app1"}, {AppName: "app2"}}
+
+	tests := []struct {
+		name     string
+		messages []notification.FormattedMessage
+		expected []string
+	}{
+		{
+			name:     "single message",
+			messages: []notification.FormattedMessage{{Updates: twoUpdates}},
+			expected: []string{"Argazer Notification: 2 Helm Chart Update(s) Available"},
+		},
+		{
+			name:     "split message",
+			messages: []notification.FormattedMessage{{Updates: twoUpdates}, {Updates: oneUpdate}},
+			expected: []string{"Argazer Notification [1/2]: 3 Update(s)", "Argazer Notification [2/2]: 3 Update(s)"},
+		},
+		{
+			name: "grouped by project",
+			messages: []notification.FormattedMessage{
+				{Project: "monitoring", Updates: oneUpdate},
+				{Project: "production", Updates: twoUpdates},
+				{Project: "production", Updates: oneUpdate},
+			},
+			expected: []string{
+				"Argazer Notification [monitoring]: 1 Helm Chart Update(s) Available",
+				"Argazer Notification [production 1/2]: 3 Update(s)",
+				"Argazer Notification [production 2/2]: 3 Update(s)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, notificationSubjects(tt.messages, nil))
+		})
+	}
+}
+
+func TestNotificationSubjects_Localized(t *testing.T) {
+	update := notification.ApplicationUpdate{AppName: "app1"}
+	messages := []notification.FormattedMessage{
+		{Project: "production", Updates: []notification.ApplicationUpdate{update}},
+		{Project: "staging", Updates: []notification.ApplicationUpdate{update}},
+	}
+
+	assert.Equal(t, []string{
+		"Argazer-Benachrichtigung [production]: 1 Helm-Chart-Update(s) verfügbar",
+		"Argazer-Benachrichtigung [staging]: 1 Helm-Chart-Update(s) verfügbar",
+	}, notificationSubjects(messages, i18n.New("de")))
 }
 
 // MockNotifier is a mock implementation of the Notifier interface for testing
@@ -763,3 +1465,364 @@ func TestSendNotifications_MultipleMessages(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, notifier.SendCalled)
 }
+
+// MockInteractiveNotifier is a mock implementation of the InteractiveNotifier interface for testing
+type MockInteractiveNotifier struct {
+	MockNotifier
+	Actions [][]notification.Action
+}
+
+func (m *MockInteractiveNotifier) SendWithActions(ctx context.Context, subject, message string, actions [][]notification.Action) error {
+	m.Actions = append(m.Actions, actions...)
+	return m.SendError
+}
+
+func TestSendNotificationsWithOptions(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "state.json"), logger)
+	require.NoError(t, err)
+
+	results := []ApplicationCheckResult{
+		{AppName: "app1", ChartName: "chart1", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		{AppName: "app2", ChartName: "chart2", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+	}
+
+	t.Run("attaches actions to each update", func(t *testing.T) {
+		notifier := &MockInteractiveNotifier{}
+		err := sendNotificationsWithOptions(context.Background(), notifier, results, notifyOptions{store: store, withActions: true}, logger)
+		require.NoError(t, err)
+
+		require.Len(t, notifier.Actions, 2)
+		assert.Equal(t, "Ack app1", notifier.Actions[0][0].Label)
+		assert.Equal(t, "ack:"+state.UpdateID("app1", "2.0.0"), notifier.Actions[0][0].Data)
+		assert.False(t, notifier.SendCalled, "interactive notifiers should receive actions")
+
+		_, ok := store.LookupNotification(state.UpdateID("app2", "1.1.0"))
+		assert.True(t, ok, "notified updates should be tracked for callbacks")
+	})
+
+	t.Run("skips acknowledged updates", func(t *testing.T) {
+		require.NoError(t, store.Acknowledge(state.Acknowledgement{AppName: "app1", Version: "2.0.0"}))
+
+		notifier := &MockInteractiveNotifier{}
+		err := sendNotificationsWithOptions(context.Background(), notifier, results, notifyOptions{store: store, withActions: true}, logger)
+		require.NoError(t, err)
+
+		require.Len(t, notifier.Actions, 1)
+		assert.Equal(t, "Ack app2", notifier.Actions[0][0].Label)
+	})
+
+	t.Run("nothing to send when all acknowledged", func(t *testing.T) {
+		require.NoError(t, store.Acknowledge(state.Acknowledgement{AppName: "app2", Version: "1.1.0", Until: time.Now().Add(time.Hour)}))
+
+		notifier := &MockNotifier{}
+		err := sendNotificationsWithOptions(context.Background(), notifier, results, notifyOptions{store: store, withActions: true}, logger)
+		require.NoError(t, err)
+		assert.False(t, notifier.SendCalled)
+	})
+}
+
+// MockEventNotifier is a mock implementation of the EventNotifier interface for testing
+type MockEventNotifier struct {
+	MockNotifier
+	Updates []notification.ApplicationUpdate
+}
+
+func (m *MockEventNotifier) SendUpdates(ctx context.Context, updates []notification.ApplicationUpdate) error {
+	m.Updates = append(m.Updates, updates...)
+	return m.SendError
+}
+
+func TestSendNotifications_EventNotifier(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	notifier := &MockEventNotifier{}
+
+	results := []ApplicationCheckResult{
+		{AppName: "app1", ChartName: "chart1", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		{AppName: "app2", ChartName: "chart2", CurrentVersion: "1.0.0", LatestVersion: "1.0.0", HasUpdate: false},
+		{AppName: "app3", ChartName: "chart3", CurrentVersion: "1.0.0", LatestVersion: "1.2.0", HasUpdate: true},
+	}
+
+	err := sendNotifications(context.Background(), notifier, results, logger)
+	require.NoError(t, err)
+
+	assert.False(t, notifier.SendCalled, "event notifiers should receive one event per update instead of messages")
+	require.Len(t, notifier.Updates, 2)
+	assert.Equal(t, "app1", notifier.Updates[0].AppName)
+	assert.Equal(t, "app3", notifier.Updates[1].AppName)
+}
+
+func TestSendEvents(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	sink := &MockEventNotifier{}
+
+	results := []ApplicationCheckResult{
+		{AppName: "app1", ChartName: "chart1", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		{AppName: "app2", ChartName: "chart2", CurrentVersion: "1.0.0", LatestVersion: "1.0.0", HasUpdate: false},
+	}
+
+	require.NoError(t, sendEvents(context.Background(), sink, results, logger))
+	require.Len(t, sink.Updates, 1)
+	assert.Equal(t, "app1", sink.Updates[0].AppName)
+	assert.Equal(t, "2.0.0", sink.Updates[0].LatestVersion)
+
+	// Nothing is sent without updates
+	sink = &MockEventNotifier{}
+	require.NoError(t, sendEvents(context.Background(), sink, results[1:], logger))
+	assert.Empty(t, sink.Updates)
+}
+
+// MockPRCommentPoster is a mock implementation of the prcomment.Poster interface for testing
+type MockPRCommentPoster struct {
+	Reports []string
+}
+
+func (m *MockPRCommentPoster) Post(ctx context.Context, report string) error {
+	m.Reports = append(m.Reports, report)
+	return nil
+}
+
+func TestPostPRComment(t *testing.T) {
+	poster := &MockPRCommentPoster{}
+	results := []ApplicationCheckResult{
+		{AppName: "app1", Project: "default", ChartName: "chart1", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+	}
+
+	require.NoError(t, postPRComment(context.Background(), poster, processResults(results), nil))
+	require.Len(t, poster.Reports, 1)
+	assert.Contains(t, poster.Reports[0], "<details>")
+	assert.Contains(t, poster.Reports[0], "| app1 | default | chart1 | 1.0.0 | 2.0.0 |")
+}
+
+// MockPlainPRCommentPoster is a mock prcomment.PlainPoster for platforms without HTML rendering
+type MockPlainPRCommentPoster struct {
+	MockPRCommentPoster
+}
+
+func (m *MockPlainPRCommentPoster) PlainMarkdown() {}
+
+func TestPostPRComment_Plain(t *testing.T) {
+	poster := &MockPlainPRCommentPoster{}
+	results := []ApplicationCheckResult{
+		{AppName: "app1", Project: "default", ChartName: "chart1", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+	}
+
+	require.NoError(t, postPRComment(context.Background(), poster, processResults(results), nil))
+	require.Len(t, poster.Reports, 1)
+	assert.NotContains(t, poster.Reports[0], "<details>")
+	assert.Contains(t, poster.Reports[0], "## Applications with Updates Available (1)")
+}
+
+// RecordingNotifier records every notification it sends
+type RecordingNotifier struct {
+	Subjects []string
+	Messages []string
+}
+
+func (r *RecordingNotifier) Send(ctx context.Context, subject, message string) error {
+	r.Subjects = append(r.Subjects, subject)
+	r.Messages = append(r.Messages, message)
+	return nil
+}
+
+func TestSendNotifications_GroupByProject(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	notifier := &RecordingNotifier{}
+
+	results := []ApplicationCheckResult{
+		{AppName: "app1", Project: "team-a", ChartName: "chart1", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		{AppName: "app2", Project: "team-b", ChartName: "chart2", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "app3", Project: "team-a", ChartName: "chart3", CurrentVersion: "1.0.0", LatestVersion: "1.0.0", HasUpdate: false},
+	}
+
+	err := sendNotificationsWithOptions(context.Background(), notifier, results, notifyOptions{groupByProject: true}, logger)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"Argazer Notification [team-a]: 1 Helm Chart Update(s) Available",
+		"Argazer Notification [team-b]: 1 Helm Chart Update(s) Available",
+	}, notifier.Subjects)
+	assert.Contains(t, notifier.Messages[0], "app1 (team-a)")
+	assert.NotContains(t, notifier.Messages[0], "app2")
+	assert.Contains(t, notifier.Messages[1], "app2 (team-b)")
+}
+
+func TestSendNotifications_Templates(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	dir := t.TempDir()
+	subjectPath := filepath.Join(dir, "subject.tmpl")
+	bodyPath := filepath.Join(dir, "body.tmpl")
+	require.NoError(t, os.WriteFile(subjectPath, []byte("[{{ .Scan.Channel }}] {{ .Summary.Notified }}/{{ .Summary.Total }} outdated ({{ .Part }}/{{ .Parts }})\n"), 0o600))
+	require.NoError(t, os.WriteFile(bodyPath, []byte("{{ range .Updates }}{{ .AppName }}: {{ .LatestVersion }} ({{ .Severity }})\n{{ end }}"), 0o600))
+
+	templates, err := notification.LoadTemplates(subjectPath, bodyPath)
+	require.NoError(t, err)
+
+	results := []ApplicationCheckResult{
+		{AppName: "app1", ChartName: "chart1", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		{AppName: "app2", ChartName: "chart2", CurrentVersion: "1.0.0", LatestVersion: "1.0.1", HasUpdate: true},
+		{AppName: "app3", ChartName: "chart3", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+	}
+
+	notifier := &RecordingNotifier{}
+	err = sendNotificationsWithOptions(context.Background(), notifier, results, notifyOptions{templates: templates, channel: "webhook"}, logger)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"[webhook] 2/3 outdated (1/1)"}, notifier.Subjects)
+	assert.Equal(t, []string{"app1: 2.0.0 (major)\napp2: 1.0.1 (patch)\n"}, notifier.Messages)
+}
+
+func TestNotificationSummary(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "app1", HasUpdate: true},
+		{AppName: "app2"},
+		{AppName: "app3", Error: "chart not found"},
+	}
+	assert.Equal(t, "Total applications checked: 3 · Up to date: 1 · Updates available: 1 · Skipped: 1", notificationSummary(results, i18n.New("")))
+	assert.Equal(t, "Total applications checked: 1 · Up to date: 1 · Updates available: 0", notificationSummary(results[1:2], i18n.New("")))
+}
+
+func TestEmailTLSConfig(t *testing.T) {
+	tlsConfig, err := emailTLSConfig(&config.Config{EmailSmtpHost: "smtp.example.com"})
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig, "defaults apply without options")
+
+	tlsConfig, err = emailTLSConfig(&config.Config{EmailSmtpHost: "smtp.example.com", EmailTLSSkipVerify: true})
+	require.NoError(t, err)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+	assert.Equal(t, "smtp.example.com", tlsConfig.ServerName)
+
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	tlsConfig, err = emailTLSConfig(&config.Config{EmailSmtpHost: "example.com", EmailTLSCAFile: caFile})
+	require.NoError(t, err)
+	_, err = server.Certificate().Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs, DNSName: "example.com"})
+	assert.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+	_, err = emailTLSConfig(&config.Config{EmailTLSCAFile: caFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contains no PEM certificates")
+}
+
+func TestScanExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		results  []ApplicationCheckResult
+		expected int
+	}{
+		{name: "no applications", expected: 0},
+		{name: "up to date", results: []ApplicationCheckResult{{AppName: "app1"}}, expected: 0},
+		{name: "updates", results: []ApplicationCheckResult{{AppName: "app1", HasUpdate: true}, {AppName: "app2"}}, expected: 2},
+		{name: "skipped", results: []ApplicationCheckResult{{AppName: "app1", Error: "chart not found"}}, expected: 4},
+		{name: "updates and skipped", results: []ApplicationCheckResult{{AppName: "app1", HasUpdate: true}, {AppName: "app2", Error: "timeout"}}, expected: 6},
+		{name: "non-helm applications are ignored", results: []ApplicationCheckResult{{Error: "not a helm application"}}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, scanExitCode(tt.results))
+		})
+	}
+}
+
+func TestFailOnUpdates(t *testing.T) {
+	patch := ApplicationCheckResult{AppName: "app1", CurrentVersion: "1.2.0", LatestVersion: "1.2.1", HasUpdate: true}
+	minor := ApplicationCheckResult{AppName: "app2", CurrentVersion: "1.2.0", LatestVersion: "1.3.0", HasUpdate: true}
+	security := ApplicationCheckResult{AppName: "app3", CurrentVersion: "1.2.0", LatestVersion: "1.2.1", HasUpdate: true, SecurityUpdate: true}
+	nonSemver := ApplicationCheckResult{AppName: "app4", CurrentVersion: "stable", LatestVersion: "1.0.0", HasUpdate: true}
+	skipped := ApplicationCheckResult{AppName: "app5", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true, Error: "timeout"}
+	outside := ApplicationCheckResult{AppName: "app7", CurrentVersion: "1.2.0", LatestVersion: "1.2.0", LatestVersionAll: "2.0.0", HasUpdateOutsideConstraint: true}
+	ignoredOutside := ApplicationCheckResult{AppName: "app8", CurrentVersion: "1.2.0", LatestVersion: "1.3.0", LatestVersionAll: "2.0.0", HasUpdateOutsideConstraint: true, IgnoredBy: "frozen"}
+
+	tests := []struct {
+		name      string
+		results   []ApplicationCheckResult
+		threshold string
+		expected  bool
+	}{
+		{name: "patch fails on patch", results: []ApplicationCheckResult{patch}, threshold: config.FailOnPatch, expected: true},
+		{name: "minor ignores patch", results: []ApplicationCheckResult{patch}, threshold: config.FailOnMinor, expected: false},
+		{name: "minor fails on minor", results: []ApplicationCheckResult{patch, minor}, threshold: config.FailOnMinor, expected: true},
+		{name: "major ignores minor", results: []ApplicationCheckResult{minor}, threshold: config.FailOnMajor, expected: false},
+		{name: "major fails on security", results: []ApplicationCheckResult{security}, threshold: config.FailOnMajor, expected: true},
+		{name: "security ignores minor", results: []ApplicationCheckResult{minor}, threshold: config.FailOnSecurity, expected: false},
+		{name: "security fails on security", results: []ApplicationCheckResult{minor, security}, threshold: config.FailOnSecurity, expected: true},
+		{name: "non-semver fails on major", results: []ApplicationCheckResult{nonSemver}, threshold: config.FailOnMajor, expected: true},
+		{name: "skipped applications are ignored", results: []ApplicationCheckResult{skipped}, threshold: config.FailOnPatch, expected: false},
+		{name: "up to date", results: []ApplicationCheckResult{{AppName: "app6", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"}}, threshold: config.FailOnPatch, expected: false},
+		{name: "updates fails on any update", results: []ApplicationCheckResult{patch}, threshold: config.FailOnUpdates, expected: true},
+		{name: "updates ignores outside constraint", results: []ApplicationCheckResult{outside}, threshold: config.FailOnUpdates, expected: false},
+		{name: "outside-constraint fails outside constraint", results: []ApplicationCheckResult{outside}, threshold: config.FailOnOutsideConstraint, expected: true},
+		{name: "outside-constraint fails on update", results: []ApplicationCheckResult{minor}, threshold: config.FailOnOutsideConstraint, expected: true},
+		{name: "outside-constraint ignores ignored updates", results: []ApplicationCheckResult{ignoredOutside}, threshold: config.FailOnOutsideConstraint, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, failOnUpdates(tt.results, tt.threshold))
+		})
+	}
+}
+
+func TestOutcomeExitCode(t *testing.T) {
+	updates := []ApplicationCheckResult{{AppName: "app1", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true}}
+	both := append([]ApplicationCheckResult{{AppName: "app2", Error: "timeout"}}, updates...)
+
+	tests := []struct {
+		name     string
+		results  []ApplicationCheckResult
+		mode     string
+		failOn   string
+		expected int
+	}{
+		{name: "simple", results: both, mode: config.ExitCodeModeSimple, expected: 0},
+		{name: "detailed", results: both, mode: config.ExitCodeModeDetailed, expected: 6},
+		{name: "simple fails on updates", results: both, mode: config.ExitCodeModeSimple, failOn: config.FailOnUpdates, expected: 2},
+		{name: "detailed keeps skipped with a threshold", results: both, mode: config.ExitCodeModeDetailed, failOn: config.FailOnMajor, expected: 4},
+		{name: "simple fails on errors", results: both, mode: config.ExitCodeModeSimple, failOn: config.FailOnErrors, expected: 4},
+		{name: "errors ignores updates in detailed mode", results: updates, mode: config.ExitCodeModeDetailed, failOn: config.FailOnErrors, expected: 0},
+		{name: "none overrides detailed", results: both, mode: config.ExitCodeModeDetailed, failOn: config.FailOnNone, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, outcomeExitCode(tt.results, tt.mode, tt.failOn))
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Run("bounded", func(t *testing.T) {
+		err := withTimeout(context.Background(), 10*time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("zero timeout leaves the context unbounded", func(t *testing.T) {
+		err := withTimeout(context.Background(), 0, func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestExitCodeResult(t *testing.T) {
+	cmd := &cobra.Command{}
+	require.NoError(t, exitCodeResult(cmd, 0))
+	assert.False(t, cmd.SilenceUsage)
+
+	err := exitCodeResult(cmd, 2)
+	var exitErr *exitCodeError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 2, exitErr.code)
+	assert.True(t, cmd.SilenceErrors)
+	assert.True(t, cmd.SilenceUsage)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"argazer/internal/i18n"
+)
+
+// markdownCompactLimit keeps the compact report below GitHub's 65536-character comment limit
+// (GitLab allows 1,000,000), leaving room for text the posting tool adds around it
+const markdownCompactLimit = 60000
+
+// compactSection is a category of the compact markdown report
+type compactSection struct {
+	title  string
+	header []string   // Table columns; nil renders the rows as a bullet list
+	rows   [][]string // Cells per row; bullet lists use the first cell only
+}
+
+// renderMarkdownCompact displays results as a single summary table with one collapsible
+// <details> section per category, sized to fit a pull/merge request comment
+func renderMarkdownCompact(cat categorizedResults, tr *i18n.Localizer, w io.Writer) error {
+	return renderCompactReport(cat, tr, w, true)
+}
+
+// renderCompactReport writes the compact report, with collapsible sections or plain headings
+// for platforms that don't render HTML. Rows that would exceed markdownCompactLimit are left out
+// and counted instead.
+func renderCompactReport(cat categorizedResults, tr *i18n.Localizer, w io.Writer, collapsible bool) error {
+	var b strings.Builder
+	b.WriteString("# " + tr.T(i18n.MarkdownTitle) + "\n\n")
+
+	// Summary table with one column per category
+	labels := []string{tr.T(i18n.LabelTotal), tr.T(i18n.LabelUpToDate), tr.T(i18n.LabelUpdates)}
+	counts := []int{cat.stats.total, cat.stats.upToDate, cat.stats.updates}
+	if cat.stats.relocated > 0 {
+		labels, counts = append(labels, tr.T(i18n.LabelRelocated)), append(counts, cat.stats.relocated)
+	}
+	if cat.stats.tracking > 0 {
+		labels, counts = append(labels, tr.T(i18n.LabelTracking)), append(counts, cat.stats.tracking)
+	}
+	if cat.stats.drifted > 0 {
+		labels, counts = append(labels, tr.T(i18n.LabelDrifted)), append(counts, cat.stats.drifted)
+	}
+	if cat.stats.ignored > 0 {
+		labels, counts = append(labels, tr.T(i18n.LabelIgnored)), append(counts, cat.stats.ignored)
+	}
+	labels, counts = append(labels, tr.T(i18n.LabelSkipped)), append(counts, cat.stats.skipped)
+
+	cells := make([]string, len(counts))
+	for i, count := range counts {
+		cells[i] = strconv.Itoa(count)
+	}
+	b.WriteString(markdownTableRow(labels))
+	b.WriteString(markdownTableSeparator(len(labels)))
+	b.WriteString(markdownTableRow(cells) + "\n")
+	if cat.truncation != nil {
+		b.WriteString("> **" + formatTruncation(cat.truncation, tr) + "**\n\n")
+	}
+
+	sections := compactSections(cat, tr)
+
+	// Section frames are always written, rows only while they fit the remaining budget
+	openings := make([]string, len(sections))
+	closings := make([]string, len(sections))
+	budget := markdownCompactLimit - b.Len()
+	for i, section := range sections {
+		if collapsible {
+			openings[i] = fmt.Sprintf("<details>\n<summary>%s (%d)</summary>\n\n", section.title, len(section.rows))
+			closings[i] = "\n</details>\n\n"
+		} else {
+			openings[i] = fmt.Sprintf("## %s (%d)\n\n", section.title, len(section.rows))
+			closings[i] = "\n"
+		}
+		if section.header != nil {
+			openings[i] += markdownTableRow(section.header) + markdownTableSeparator(len(section.header))
+		}
+		budget -= len(openings[i]) + len(closings[i]) + len(compactTruncationNote(len(section.rows), tr))
+	}
+
+	for i, section := range sections {
+		b.WriteString(openings[i])
+		for j, row := range section.rows {
+			line := "- " + row[0] + "\n"
+			if section.header != nil {
+				line = markdownTableRow(row)
+			}
+			if len(line) > budget {
+				b.WriteString(compactTruncationNote(len(section.rows)-j, tr))
+				break
+			}
+			b.WriteString(line)
+			budget -= len(line)
+		}
+		b.WriteString(closings[i])
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
+
+// compactSections returns the non-empty categories of the compact markdown report
+func compactSections(cat categorizedResults, tr *i18n.Localizer) []compactSection {
+	var sections []compactSection
+	add := func(section compactSection) {
+		if len(section.rows) > 0 {
+			sections = append(sections, section)
+		}
+	}
+
+	appSets := compactSection{title: tr.T(i18n.MarkdownAppSets)}
+	for _, summary := range outdatedApplicationSets(cat.applicationSets) {
+		appSets.rows = append(appSets.rows, []string{"**" + summary.ApplicationSet + ":** " + formatApplicationSetSummary(summary, tr)})
+	}
+	add(appSets)
+
+	updates := compactSection{
+		title:  tr.T(i18n.MarkdownUpdates),
+		header: []string{tr.T(i18n.FieldApplication), tr.T(i18n.FieldProject), tr.T(i18n.FieldChart), tr.T(i18n.FieldCurrentVersion), tr.T(i18n.FieldLatestVersion), tr.T(i18n.FieldSeverity)},
+	}
+	for _, result := range cat.updatesAvailable {
+		updates.rows = append(updates.rows, []string{markdownAppHeading(result), instanceProject(result), result.ChartName, result.CurrentVersion, result.LatestVersion, result.Severity})
+	}
+	add(updates)
+
+	outside := compactSection{
+		title:  tr.T(i18n.MarkdownOutside),
+		header: []string{tr.T(i18n.FieldApplication), tr.T(i18n.FieldProject), tr.T(i18n.FieldChart), tr.T(i18n.FieldCurrentVersion), tr.T(i18n.FieldLatestVersionAll)},
+	}
+	for _, result := range cat.upToDateWithConstraint {
+		outside.rows = append(outside.rows, []string{markdownAppHeading(result), instanceProject(result), result.ChartName, result.CurrentVersion, result.LatestVersionAll})
+	}
+	add(outside)
+
+	relocated := compactSection{
+		title:  tr.T(i18n.MarkdownRelocated),
+		header: []string{tr.T(i18n.FieldApplication), tr.T(i18n.FieldProject), tr.T(i18n.FieldChart), tr.T(i18n.FieldCurrentVersion), tr.T(i18n.FieldStatus)},
+	}
+	for _, result := range cat.relocated {
+		relocated.rows = append(relocated.rows, []string{markdownAppHeading(result), instanceProject(result), result.ChartName, result.CurrentVersion, result.RelocatedTo})
+	}
+	add(relocated)
+
+	tracking := compactSection{
+		title:  tr.T(i18n.MarkdownTracking),
+		header: []string{tr.T(i18n.FieldApplication), tr.T(i18n.FieldProject), tr.T(i18n.FieldChart), tr.T(i18n.FieldBranch), tr.T(i18n.FieldChartVersion)},
+	}
+	for _, result := range cat.trackingBranch {
+		tracking.rows = append(tracking.rows, []string{markdownAppHeading(result), instanceProject(result), result.ChartName, result.TrackingBranch, result.CurrentVersion})
+	}
+	add(tracking)
+
+	drifted := compactSection{
+		title:  tr.T(i18n.MarkdownDrifted),
+		header: []string{tr.T(i18n.FieldApplication), tr.T(i18n.FieldProject), tr.T(i18n.FieldChart), tr.T(i18n.FieldDeclaredVersion), tr.T(i18n.FieldDeployedVersion), tr.T(i18n.FieldLatestVersion)},
+	}
+	for _, result := range cat.drifted {
+		drifted.rows = append(drifted.rows, []string{markdownAppHeading(result), instanceProject(result), result.ChartName, result.CurrentVersion, result.DeployedVersion, result.LatestVersion})
+	}
+	add(drifted)
+
+	ignored := compactSection{
+		title:  tr.T(i18n.MarkdownIgnored),
+		header: []string{tr.T(i18n.FieldApplication), tr.T(i18n.FieldProject), tr.T(i18n.FieldChart), tr.T(i18n.FieldCurrentVersion), tr.T(i18n.FieldLatestVersion), tr.T(i18n.FieldReason)},
+	}
+	for _, result := range cat.ignored {
+		ignored.rows = append(ignored.rows, []string{markdownAppHeading(result), instanceProject(result), result.ChartName, result.CurrentVersion, result.LatestVersion, formatIgnoredBy(result, tr)})
+	}
+	add(ignored)
+
+	skipped := compactSection{
+		title:  tr.T(i18n.MarkdownSkipped),
+		header: []string{tr.T(i18n.FieldApplication), tr.T(i18n.FieldProject), tr.T(i18n.FieldChart), tr.T(i18n.FieldError)},
+	}
+	for _, result := range cat.errors {
+		skipped.rows = append(skipped.rows, []string{markdownAppHeading(result), instanceProject(result), result.ChartName, formatResultError(result)})
+	}
+	add(skipped)
+
+	return sections
+}
+
+// compactTruncationNote returns the note written in place of rows left out
+func compactTruncationNote(omitted int, tr *i18n.Localizer) string {
+	return "\n_" + tr.T(i18n.MarkdownTruncated, omitted) + "_\n"
+}
+
+// markdownTableRow renders cells as a markdown table row
+func markdownTableRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = markdownCell(cell)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+// markdownTableSeparator renders the separator below a table header
+func markdownTableSeparator(columns int) string {
+	return strings.Repeat("|---", columns) + "|\n"
+}
+
+// markdownCell escapes characters that would break a table cell
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ").Replace(value)
+}
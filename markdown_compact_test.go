@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdownCompact(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "frontend", Project: "web", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.2.0", HasUpdate: true, Severity: "minor", URL: "https://argocd.example.com/applications/argocd/frontend"},
+		{AppName: "api", Project: "backend", ChartName: "postgresql", CurrentVersion: "12.0.0", LatestVersion: "12.0.0"},
+		{AppName: "broken", Project: "backend", ChartName: "redis", Error: "index fetch failed | status 500"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown-compact", nil, &buf))
+	output := buf.String()
+
+	assert.Contains(t, output, "| Total applications checked | Up to date | Updates available | Skipped |")
+	assert.Contains(t, output, "| 3 | 1 | 1 | 1 |")
+	assert.Contains(t, output, "<summary>Applications with Updates Available (1)</summary>")
+	assert.Contains(t, output, "| [frontend](https://argocd.example.com/applications/argocd/frontend) | web | nginx | 1.0.0 | 1.2.0 | minor |")
+	assert.Contains(t, output, `| broken | backend | redis | index fetch failed \| status 500 |`)
+	assert.Equal(t, strings.Count(output, "<details>"), strings.Count(output, "</details>"))
+	assert.NotContains(t, output, "### ")
+}
+
+func TestRenderMarkdownCompact_Truncated(t *testing.T) {
+	var results []ApplicationCheckResult
+	for i := 0; i < 2000; i++ {
+		results = append(results, ApplicationCheckResult{
+			AppName:        fmt.Sprintf("application-with-a-long-name-%04d", i),
+			Project:        "platform",
+			ChartName:      "ingress-nginx",
+			CurrentVersion: "4.0.0",
+			LatestVersion:  "4.11.3",
+			HasUpdate:      true,
+		})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown-compact", nil, &buf))
+	output := buf.String()
+
+	assert.LessOrEqual(t, len(output), markdownCompactLimit)
+	assert.Contains(t, output, "more not shown (comment size limit)")
+	assert.True(t, strings.HasSuffix(output, "</details>\n\n"))
+}
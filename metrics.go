@@ -0,0 +1,107 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"argazer/internal/helm"
+)
+
+// metricsPath is where serve mode exposes Prometheus metrics
+const metricsPath = "/metrics"
+
+// metricsHandler serves the staleness metrics of the last completed scan in the Prometheus text format
+type metricsHandler struct {
+	mu   sync.RWMutex
+	body string
+}
+
+// update replaces the served metrics with those of a completed scan
+func (h *metricsHandler) update(results []ApplicationCheckResult, scanned time.Time) {
+	var b strings.Builder
+	writeMetrics(&b, results, scanned)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.body = b.String()
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = io.WriteString(w, h.body)
+}
+
+// writeMetrics writes per-application and per-project staleness gauges
+// Up-to-date applications are included with zero values, so their series drop to 0 after an upgrade.
+func writeMetrics(w io.Writer, results []ApplicationCheckResult, scanned time.Time) {
+	type series struct {
+		labels string
+		value  int
+	}
+	var versionsBehind, releaseAge, appScores []series
+	for _, result := range results {
+		if result.AppName == "" || result.Error != "" {
+			continue
+		}
+		labels := prometheusLabels("app", result.AppName, "namespace", result.Namespace, "project", result.Project, "chart", result.ChartName)
+		versionsBehind = append(versionsBehind, series{labels, result.VersionsBehind})
+		releaseAge = append(releaseAge, series{labels, result.LatestReleaseAgeDays})
+		appScores = append(appScores, series{labels, result.StalenessScore})
+	}
+
+	// Failed checks are counted by error code, so alerts can be routed by category
+	errorCounts := make(map[string]int)
+	for _, result := range results {
+		if result.AppName == "" || result.Error == "" {
+			continue
+		}
+		errorCounts[cmp.Or(result.ErrorCode, helm.ErrorCodeUnknown)]++
+	}
+	var checkErrors []series
+	for _, code := range slices.Sorted(maps.Keys(errorCounts)) {
+		checkErrors = append(checkErrors, series{prometheusLabels("code", code), errorCounts[code]})
+	}
+
+	var outdated, projectBehind, projectScores []series
+	for _, summary := range summarizeStaleness(results) {
+		labels := prometheusLabels("project", summary.Project)
+		outdated = append(outdated, series{labels, summary.Outdated})
+		projectBehind = append(projectBehind, series{labels, summary.VersionsBehind})
+		projectScores = append(projectScores, series{labels, summary.Score})
+	}
+
+	gauge := func(name, help string, values []series) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, s := range values {
+			fmt.Fprintf(w, "%s%s %d\n", name, s.labels, s.value)
+		}
+	}
+	gauge("argazer_application_versions_behind", "Releases newer than the deployed chart version within the version constraint.", versionsBehind)
+	gauge("argazer_application_latest_release_age_days", "Days since the latest chart version was released (0 if unknown).", releaseAge)
+	gauge("argazer_application_staleness_score", "Staleness score of the application's pending update.", appScores)
+	gauge("argazer_project_outdated_applications", "Applications of the project with an update available.", outdated)
+	gauge("argazer_project_versions_behind", "Releases behind, summed over the project's applications.", projectBehind)
+	gauge("argazer_project_staleness_score", "Staleness score, summed over the project's applications.", projectScores)
+	gauge("argazer_check_errors", "Applications whose check failed, by error code.", checkErrors)
+	gauge("argazer_last_scan_timestamp_seconds", "Time the last scan completed.", []series{{"", int(scanned.Unix())}})
+}
+
+// prometheusLabels renders label name/value pairs, e.g. {app="nginx",project="default"}
+func prometheusLabels(pairs ...string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	labels := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		labels = append(labels, pairs[i]+`="`+escaper.Replace(pairs[i+1])+`"`)
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"argazer/internal/helm"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "api", Namespace: "argocd", Project: "payments", ChartName: "nginx", HasUpdate: true, VersionsBehind: 3, LatestReleaseAgeDays: 40, StalenessScore: 14},
+		{AppName: "web", Project: "payments", ChartName: "nginx"},
+		{AppName: "broken", Project: "payments", Error: "timeout"},
+		{AppName: "locked", Project: "payments", Error: "authentication failed", ErrorCode: helm.ErrorCodeAuthFailed},
+	}
+
+	var b strings.Builder
+	writeMetrics(&b, results, time.Unix(1700000000, 0))
+	out := b.String()
+
+	assert.Contains(t, out, "# TYPE argazer_application_versions_behind gauge\n")
+	assert.Contains(t, out, `argazer_application_versions_behind{app="api",namespace="argocd",project="payments",chart="nginx"} 3`+"\n")
+	assert.Contains(t, out, `argazer_application_versions_behind{app="web",namespace="",project="payments",chart="nginx"} 0`+"\n")
+	assert.Contains(t, out, `argazer_application_latest_release_age_days{app="api",namespace="argocd",project="payments",chart="nginx"} 40`+"\n")
+	assert.Contains(t, out, `argazer_project_outdated_applications{project="payments"} 1`+"\n")
+	assert.Contains(t, out, `argazer_project_staleness_score{project="payments"} 14`+"\n")
+	assert.Contains(t, out, "argazer_last_scan_timestamp_seconds 1700000000\n")
+	assert.Contains(t, out, `argazer_check_errors{code="AUTH_FAILED"} 1`+"\n")
+	assert.Contains(t, out, `argazer_check_errors{code="UNKNOWN"} 1`+"\n")
+	assert.NotContains(t, out, "broken")
+}
+
+func TestPrometheusLabels(t *testing.T) {
+	assert.Equal(t, `{app="a\"b",project="x\\y\nz"}`, prometheusLabels("app", `a"b`, "project", "x\\y\nz"))
+}
+
+func TestMetricsHandler(t *testing.T) {
+	handler := &metricsHandler{}
+	handler.update([]ApplicationCheckResult{{AppName: "api", Project: "default", HasUpdate: true, StalenessScore: 7}}, time.Now())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, rec.Body.String(), `argazer_project_staleness_score{project="default"} 7`)
+}
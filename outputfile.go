@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/config"
+	"argazer/internal/i18n"
+)
+
+// writeOutputFiles writes the report to the output files, each in the format of its extension
+// Only the report is written; logs keep going to stderr.
+func writeOutputFiles(report categorizedResults, files []string, tr *i18n.Localizer, logger *logrus.Entry) error {
+	for _, file := range files {
+		format, ok := config.OutputFileFormat(file)
+		if !ok {
+			return fmt.Errorf("cannot infer the format of output file %s", file)
+		}
+
+		var buf bytes.Buffer
+		if err := renderResults(report, format, tr, &buf); err != nil {
+			return fmt.Errorf("failed to render output file %s: %w", file, err)
+		}
+		if err := writeOutputFile(file, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write output file %s: %w", file, err)
+		}
+		logger.WithFields(logrus.Fields{"file": file, "format": format}).Debug("Wrote output file")
+	}
+	return nil
+}
+
+// writeOutputFile replaces a file through a temporary file, so a reader never sees a partial report,
+// e.g. when serve mode rewrites it every cycle
+func writeOutputFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	// Temporary files are private; reports are shared like files created by a shell redirect
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
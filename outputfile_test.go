@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/i18n"
+)
+
+func TestWriteOutputFiles(t *testing.T) {
+	dir := t.TempDir()
+	report := processResults([]ApplicationCheckResult{
+		{AppName: "web", Project: "default", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+	})
+	files := []string{filepath.Join(dir, "results.json"), filepath.Join(dir, "report.HTML"), filepath.Join(dir, "report.md")}
+
+	require.NoError(t, writeOutputFiles(report, files, i18n.New("en"), logrus.NewEntry(logrus.New())))
+
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(raw, &document), "the JSON file holds only the report")
+	assert.EqualValues(t, jsonSchemaVersion, document["schema_version"])
+
+	raw, err = os.ReadFile(files[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "<!DOCTYPE html>")
+	assert.Contains(t, string(raw), ">web<")
+	assert.NotContains(t, string(raw), `http-equiv="refresh"`, "a written page doesn't reload itself")
+
+	raw, err = os.ReadFile(files[2])
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "### web\n")
+
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3, "no temporary files are left behind")
+}
+
+func TestWriteOutputFiles_Error(t *testing.T) {
+	report := processResults(nil)
+	logger := logrus.NewEntry(logrus.New())
+
+	err := writeOutputFiles(report, []string{filepath.Join(t.TempDir(), "missing", "results.json")}, i18n.New("en"), logger)
+	assert.ErrorContains(t, err, "failed to write output file")
+
+	err = writeOutputFiles(report, []string{"results.yaml"}, i18n.New("en"), logger)
+	assert.ErrorContains(t, err, "cannot infer the format")
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/term"
+
+	"argazer/internal/config"
+)
+
+// Appearance of the progress bar
+const (
+	progressBarWidth       = 20
+	progressRedrawInterval = 200 * time.Millisecond // Keeps the spinner and ETA moving while checks are slow
+	progressDefaultWidth   = 80                     // Terminal width when it can't be read
+)
+
+// progressSpinner are the frames of the spinner, one per redraw
+var progressSpinner = []string{"|", "/", "-", `\`}
+
+// progressBar draws the progress of application checks on a single terminal line: completed checks,
+// an ETA and the repository of the last check that started
+// Log lines written through it while it's shown are printed above the bar.
+type progressBar struct {
+	out   io.Writer
+	width func() int
+	now   func() time.Time
+
+	mu        sync.Mutex
+	total     int
+	done      int
+	repoURL   string
+	startedAt time.Time
+	frame     int
+	shown     bool
+	logOutput io.Writer     // Log output the bar replaced, restored by finish
+	stop      chan struct{} // Stops the redraws
+	stopped   sync.WaitGroup
+}
+
+// terminalProgressBar returns a progress bar on stderr when the table report is written to a terminal,
+// nil otherwise: JSON, Markdown and JUnit reports are read by programs, and a redirected report must
+// stay free of it
+func terminalProgressBar(cfg *config.Config) *progressBar {
+	if !cfg.Progress || cfg.OutputFormat != config.OutputFormatTable {
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return nil
+	}
+	return newProgressBar(os.Stderr, func() int {
+		width, _, err := term.GetSize(int(os.Stderr.Fd()))
+		if err != nil || width <= 0 {
+			return progressDefaultWidth
+		}
+		return width
+	})
+}
+
+// newProgressBar creates a progress bar drawn on out, a terminal width columns wide
+func newProgressBar(out io.Writer, width func() int) *progressBar {
+	return &progressBar{out: out, width: width, now: time.Now}
+}
+
+// start shows the bar for total checks, redrawing it until finish
+// Log lines going to the same output go through the bar meanwhile.
+func (p *progressBar) start(total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.total, p.done, p.repoURL = total, 0, ""
+	p.startedAt = p.now()
+	if logrus.StandardLogger().Out == p.out {
+		p.logOutput = p.out
+		logrus.SetOutput(p)
+	}
+	p.draw()
+
+	p.stop = make(chan struct{})
+	p.stopped.Add(1)
+	go func(stop <-chan struct{}) {
+		defer p.stopped.Done()
+		ticker := time.NewTicker(progressRedrawInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				p.draw()
+				p.mu.Unlock()
+			}
+		}
+	}(p.stop)
+}
+
+// report updates the bar with the progress of a check
+func (p *progressBar) report(progress checkProgress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if progress.result != nil {
+		p.done++
+	} else if progress.repoURL != "" {
+		p.repoURL = progress.repoURL
+	}
+	p.draw()
+}
+
+// finish removes the bar and restores the log output
+func (p *progressBar) finish() {
+	close(p.stop)
+	p.stopped.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	if p.logOutput != nil {
+		logrus.SetOutput(p.logOutput)
+		p.logOutput = nil
+	}
+}
+
+// Write prints log lines above the bar
+func (p *progressBar) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clear()
+	n, err := p.out.Write(b)
+	p.draw()
+	return n, err
+}
+
+// draw replaces the current line with the bar; callers must hold p.mu
+func (p *progressBar) draw() {
+	p.frame++
+	fmt.Fprint(p.out, "\r"+p.line()+"\x1b[K")
+	p.shown = true
+}
+
+// clear erases the bar; callers must hold p.mu
+func (p *progressBar) clear() {
+	if p.shown {
+		fmt.Fprint(p.out, "\r\x1b[K")
+		p.shown = false
+	}
+}
+
+// line returns the text of the bar, e.g. "/ [#####---------------] 250/1000 applications · ETA 1m30s · https://..."
+func (p *progressBar) line() string {
+	filled := 0
+	if p.total > 0 {
+		filled = progressBarWidth * p.done / p.total
+	}
+	line := fmt.Sprintf("%s [%s%s] %d/%d applications", progressSpinner[p.frame%len(progressSpinner)],
+		strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled), p.done, p.total)
+
+	// The rate is only meaningful once a few checks completed
+	if p.done > 0 && p.done < p.total {
+		elapsed := p.now().Sub(p.startedAt)
+		eta := elapsed * time.Duration(p.total-p.done) / time.Duration(p.done)
+		line += " · ETA " + eta.Round(time.Second).String()
+	}
+	if p.repoURL != "" && p.done < p.total {
+		line += " · " + p.repoURL
+	}
+
+	// A line wider than the terminal would wrap, and \r only returns to the start of the last row
+	width := p.width() - 1
+	if utf8.RuneCountInString(line) > width {
+		line = string([]rune(line)[:max(width, 0)])
+	}
+	return line
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"argazer/internal/config"
+)
+
+func TestProgressBar_Line(t *testing.T) {
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	p := newProgressBar(&bytes.Buffer{}, func() int { return 120 })
+	p.now = func() time.Time { return now }
+	p.total, p.startedAt = 8, now
+
+	assert.Equal(t, "| [--------------------] 0/8 applications", p.line())
+
+	p.report(checkProgress{repoURL: "https://charts.bitnami.com/bitnami"})
+	p.report(checkProgress{}) // Not a Helm application
+	for i := 0; i < 2; i++ {
+		p.report(checkProgress{result: &ApplicationCheckResult{}})
+	}
+	now = now.Add(30 * time.Second)
+	p.frame = 1
+	assert.Equal(t, "/ [#####---------------] 2/8 applications · ETA 1m30s · https://charts.bitnami.com/bitnami", p.line())
+
+	p.width = func() int { return 40 }
+	assert.Equal(t, "/ [#####---------------] 2/8 applicatio", p.line(), "the bar never wraps")
+
+	p.done = 8
+	p.width = func() int { return 120 }
+	assert.Equal(t, "/ [####################] 8/8 applications", p.line())
+}
+
+func TestProgressBar_Write(t *testing.T) {
+	var out bytes.Buffer
+	p := newProgressBar(&out, func() int { return 80 })
+	p.start(2)
+	p.report(checkProgress{result: &ApplicationCheckResult{}})
+	_, err := p.Write([]byte("level=info msg=\"Processing application\"\n"))
+	assert.NoError(t, err)
+	p.finish()
+
+	output := out.String()
+	assert.Contains(t, output, "\r\x1b[Klevel=info msg=\"Processing application\"\n\r", "log lines replace the bar, which is drawn again below")
+	assert.Contains(t, output, "1/2 applications")
+	assert.True(t, strings.HasSuffix(output, "\r\x1b[K"), "the bar is removed when the checks complete")
+}
+
+func TestTerminalProgressBar(t *testing.T) {
+	assert.Nil(t, terminalProgressBar(&config.Config{Progress: false, OutputFormat: config.OutputFormatTable}))
+	assert.Nil(t, terminalProgressBar(&config.Config{Progress: true, OutputFormat: config.OutputFormatJSON}))
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// textURLPattern matches URLs in free text like error messages
+var textURLPattern = regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^\s"'()<>]+`)
+
+// redactResults returns a copy of the results with repository hostnames, URLs, project and cluster names
+// replaced by stable pseudonyms, so reports can be shared outside the organization
+// The same value always maps to the same pseudonym, keeping applications of one repository or
+// project recognizable as such.
+func redactResults(results []ApplicationCheckResult) []ApplicationCheckResult {
+	redacted := make([]ApplicationCheckResult, len(results))
+	for i, result := range results {
+		// Hostnames also show up in error messages (e.g. DNS lookup failures), outside of any URL
+		var hosts []string
+		for _, raw := range append([]string{result.RepoURL, result.URL}, valuesSourceURLs(result)...) {
+			if host := urlHost(raw); host != "" && !slices.Contains(hosts, host) {
+				hosts = append(hosts, host)
+			}
+		}
+
+		result.RepoURL = redactURL(result.RepoURL)
+		result.URL = redactURL(result.URL)
+		if result.Project != "" {
+			result.Project = redactToken("project", result.Project)
+		}
+		if urlHost(result.Cluster) != "" {
+			result.Cluster = redactURL(result.Cluster)
+		} else if result.Cluster != "" {
+			result.Cluster = redactToken("cluster", result.Cluster)
+		}
+		result.Error = redactHosts(result.Error, hosts)
+		result.RelocatedTo = redactHosts(result.RelocatedTo, hosts)
+		result.ReleaseNotes = redactHosts(result.ReleaseNotes, hosts)
+		result.ReleaseNotesURL = redactURL(result.ReleaseNotesURL)
+		if len(result.ValuesSources) > 0 {
+			result.ValuesSources = slices.Clone(result.ValuesSources)
+			for j := range result.ValuesSources {
+				result.ValuesSources[j].RepoURL = redactURL(result.ValuesSources[j].RepoURL)
+			}
+		}
+		if len(result.Images) > 0 {
+			result.Images = slices.Clone(result.Images)
+			for j, image := range result.Images {
+				var imageHosts []string
+				if host := urlHost(image.Image); host != "" {
+					imageHosts = append(imageHosts, host)
+				}
+				result.Images[j].Image = redactURL(image.Image)
+				result.Images[j].Error = redactHosts(image.Error, imageHosts)
+			}
+		}
+		if len(result.Dependencies) > 0 {
+			result.Dependencies = slices.Clone(result.Dependencies)
+			for j, dependency := range result.Dependencies {
+				var dependencyHosts []string
+				if host := urlHost(dependency.Repository); host != "" {
+					dependencyHosts = append(dependencyHosts, host)
+				}
+				result.Dependencies[j].Repository = redactURL(dependency.Repository)
+				result.Dependencies[j].Error = redactHosts(dependency.Error, dependencyHosts)
+			}
+		}
+		if result.SecurityFixes != nil {
+			fixes := *result.SecurityFixes
+			fixes.Images = make([]string, len(result.SecurityFixes.Images))
+			for j, image := range result.SecurityFixes.Images {
+				fixes.Images[j] = redactURL(image)
+			}
+			result.SecurityFixes = &fixes
+		}
+		redacted[i] = result
+	}
+	return redacted
+}
+
+// valuesSourceURLs returns the repository URLs of the sources providing the chart's value files
+func valuesSourceURLs(result ApplicationCheckResult) []string {
+	urls := make([]string, len(result.ValuesSources))
+	for i, source := range result.ValuesSources {
+		urls[i] = source.RepoURL
+	}
+	return urls
+}
+
+// redactToken returns the stable pseudonym of a value, e.g. "host-3f2a9c1e"
+func redactToken(kind, value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return kind + "-" + hex.EncodeToString(sum[:4])
+}
+
+// redactURL replaces the host of a repository or web URL with its pseudonym and drops credentials
+// The path is kept so charts stay identifiable. Besides full URLs, it handles scp-like Git remotes
+// (git@host:org/repo.git) and scheme-less OCI references (registry.example.com/charts).
+func redactURL(raw string) string {
+	host := urlHost(raw)
+	if host == "" {
+		return raw
+	}
+
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		port := u.Port()
+		u.User = nil
+		u.Host = redactToken("host", host)
+		if port != "" {
+			u.Host += ":" + port
+		}
+		return u.String()
+	}
+	return strings.Replace(raw, host, redactToken("host", host), 1)
+}
+
+// urlHost returns the hostname of a URL, scp-like Git remote or scheme-less OCI reference
+func urlHost(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+
+	// git@host:org/repo.git
+	if _, rest, ok := strings.Cut(raw, "@"); ok && !strings.Contains(raw, "://") {
+		host, _, _ := strings.Cut(rest, ":")
+		return host
+	}
+
+	// registry.example.com/charts
+	host, _, _ := strings.Cut(raw, "/")
+	host, _, _ = strings.Cut(host, ":")
+	if !strings.Contains(host, ".") {
+		return ""
+	}
+	return host
+}
+
+// redactHosts replaces the given hostnames and those of URLs in free text with their pseudonyms
+func redactHosts(text string, hosts []string) string {
+	hosts = slices.Clone(hosts)
+	for _, raw := range textURLPattern.FindAllString(text, -1) {
+		if host := urlHost(strings.TrimRight(raw, ".,;:")); host != "" && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	// Longer hostnames first, so "charts.example.com" isn't broken up by replacing "example.com"
+	slices.SortFunc(hosts, func(a, b string) int { return len(b) - len(a) })
+
+	for _, host := range hosts {
+		text = strings.ReplaceAll(text, host, redactToken("host", host))
+	}
+	return text
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"time"
+
+	"argazer/internal/state"
+)
+
+// reportSchemaVersion is the version of the scan report posted to structured webhooks
+// It is increased on changes that break receivers, not when fields are added.
+const reportSchemaVersion = 1
+
+// Application statuses of the scan report, one per result category
+const (
+	reportStatusUpdateAvailable = "update_available"
+	reportStatusUpToDate        = "up_to_date"
+	reportStatusRelocated       = "relocated"
+	reportStatusTrackingBranch  = "tracking_branch"
+	reportStatusDrifted         = "drifted"
+	reportStatusIgnored         = "ignored"
+	reportStatusError           = "error"
+)
+
+// reportStatusLabels are the short labels of the statuses in interactive views
+var reportStatusLabels = map[string]string{
+	reportStatusError:           "error",
+	reportStatusUpdateAvailable: "update",
+	reportStatusDrifted:         "drifted",
+	reportStatusRelocated:       "relocated",
+	reportStatusTrackingBranch:  "branch",
+	reportStatusIgnored:         "ignored",
+	reportStatusUpToDate:        "up to date",
+}
+
+// scanReport is the machine-readable result of a scan posted to structured webhooks
+type scanReport struct {
+	SchemaVersion int                 `json:"schema_version"`
+	GeneratedAt   time.Time           `json:"generated_at"`
+	Version       string              `json:"argazer_version"`
+	Summary       scanReportSummary   `json:"summary"`
+	Applications  []reportApplication `json:"applications"`
+}
+
+// scanReportSummary holds the totals of a scan report
+type scanReportSummary struct {
+	Total            int `json:"total"`
+	UpToDate         int `json:"up_to_date"`
+	UpdatesAvailable int `json:"updates_available"`
+	Relocated        int `json:"relocated"`
+	TrackingBranch   int `json:"tracking_branch"`
+	Drifted          int `json:"drifted"`
+	Ignored          int `json:"ignored"`
+	Skipped          int `json:"skipped"`
+}
+
+// reportApplication is an application of the scan report: its check result with its status
+type reportApplication struct {
+	ApplicationCheckResult
+	Status       string `json:"status"`                 // One of the reportStatus constants
+	Acknowledged bool   `json:"acknowledged,omitempty"` // The update was acknowledged or snoozed from a notification
+}
+
+// buildScanReport builds the scan report of the results
+// Acknowledged updates are kept and flagged, so receivers see every application of the scan.
+func buildScanReport(results []ApplicationCheckResult, store *state.Store, now time.Time) scanReport {
+	stats := processResults(results).stats
+	report := scanReport{
+		SchemaVersion: reportSchemaVersion,
+		GeneratedAt:   now.UTC(),
+		Version:       version,
+		Summary: scanReportSummary{
+			Total:            stats.total,
+			UpToDate:         stats.upToDate,
+			UpdatesAvailable: stats.updates,
+			Relocated:        stats.relocated,
+			TrackingBranch:   stats.tracking,
+			Drifted:          stats.drifted,
+			Ignored:          stats.ignored,
+			Skipped:          stats.skipped,
+		},
+		Applications: []reportApplication{},
+	}
+
+	for _, result := range results {
+		// Non-Helm applications are left out, as in every other output
+		if result.AppName == "" {
+			continue
+		}
+		app := reportApplication{ApplicationCheckResult: result, Status: reportStatus(result)}
+		if result.HasUpdate && store != nil {
+			app.Acknowledged = store.IsAcknowledged(result.AppName, result.LatestVersion, now)
+		}
+		report.Applications = append(report.Applications, app)
+	}
+	return report
+}
+
+// reportStatus returns the status of a result, following the categories of processResults
+func reportStatus(result ApplicationCheckResult) string {
+	switch {
+	case result.Error != "":
+		return reportStatusError
+	case result.RelocatedTo != "" && !result.HasUpdate:
+		return reportStatusRelocated
+	case result.TrackingBranch != "" && !result.HasUpdate:
+		return reportStatusTrackingBranch
+	case result.DeployedVersion != "":
+		return reportStatusDrifted
+	case result.IgnoredBy != "":
+		return reportStatusIgnored
+	case result.HasUpdate:
+		return reportStatusUpdateAvailable
+	default:
+		return reportStatusUpToDate
+	}
+}
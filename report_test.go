@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"argazer/internal/state"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildScanReport(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	store, err := state.NewStore(filepath.Join(t.TempDir(), "state.json"), logger)
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	require.NoError(t, store.Acknowledge(state.Acknowledgement{AppName: "app2", Version: "1.1.0", At: now}))
+
+	results := []ApplicationCheckResult{
+		{AppName: "app1", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true, Severity: "major"},
+		{AppName: "app2", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "app3", ChartName: "kafka", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+		{AppName: "app4", ChartName: "mysql", Error: "chart not found"},
+		{AppName: "app5", ChartName: "nats", CurrentVersion: "1.0.0", LatestVersion: "1.2.0", IgnoredBy: "frozen"},
+		{Error: "not a helm application"},
+	}
+
+	report := buildScanReport(results, store, now)
+	assert.Equal(t, reportSchemaVersion, report.SchemaVersion)
+	assert.Equal(t, time.UTC, report.GeneratedAt.Location())
+	assert.Equal(t, scanReportSummary{Total: 5, UpToDate: 1, UpdatesAvailable: 2, Ignored: 1, Skipped: 1}, report.Summary)
+
+	require.Len(t, report.Applications, 5)
+	statuses := make(map[string]string)
+	for _, app := range report.Applications {
+		statuses[app.AppName] = app.Status
+	}
+	assert.Equal(t, map[string]string{
+		"app1": reportStatusUpdateAvailable,
+		"app2": reportStatusUpdateAvailable,
+		"app3": reportStatusUpToDate,
+		"app4": reportStatusError,
+		"app5": reportStatusIgnored,
+	}, statuses)
+	assert.False(t, report.Applications[0].Acknowledged)
+	assert.True(t, report.Applications[1].Acknowledged)
+	assert.Equal(t, "major", report.Applications[0].Severity)
+}
+
+// RecordingReportNotifier records the reports it is sent
+type RecordingReportNotifier struct {
+	RecordingNotifier
+	Reports []any
+}
+
+func (r *RecordingReportNotifier) SendReport(ctx context.Context, report any) error {
+	r.Reports = append(r.Reports, report)
+	return nil
+}
+
+func TestSendNotifications_Report(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	notifier := &RecordingReportNotifier{}
+
+	// The report is sent even when every application is up to date
+	results := []ApplicationCheckResult{{AppName: "app1", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"}}
+	require.NoError(t, sendNotificationsWithOptions(context.Background(), notifier, results, notifyOptions{}, logger))
+
+	require.Len(t, notifier.Reports, 1)
+	report := notifier.Reports[0].(scanReport)
+	assert.Equal(t, 1, report.Summary.UpToDate)
+	assert.Empty(t, notifier.Messages)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"slices"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+
+	"argazer/internal/config"
+	"argazer/internal/i18n"
+)
+
+// scanTruncation describes a scan that max_apps limited to part of the matching applications
+type scanTruncation struct {
+	Matched int    `json:"matched"` // Applications matching the filters
+	Checked int    `json:"checked"` // Applications actually checked
+	Mode    string `json:"mode"`    // How they were selected: "first" or "sample"
+}
+
+// limitApplications keeps at most maxApps applications, either the first ones by namespace and name
+// or a deterministic sample
+// Sampling ranks every application by a hash of the seed and its name, so the same seed keeps picking
+// the same applications and adding or removing others doesn't reshuffle the sample. It returns nil
+// truncation when nothing was left out.
+func limitApplications(apps []*v1alpha1.Application, maxApps int, mode, seed string) ([]*v1alpha1.Application, *scanTruncation) {
+	if maxApps <= 0 || len(apps) <= maxApps {
+		return apps, nil
+	}
+
+	byName := func(a, b *v1alpha1.Application) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	}
+
+	selected := slices.Clone(apps)
+	if mode == config.MaxAppsModeSample {
+		rank := make(map[*v1alpha1.Application][sha256.Size]byte, len(selected))
+		for _, app := range selected {
+			rank[app] = sha256.Sum256([]byte(seed + "/" + app.Namespace + "/" + app.Name))
+		}
+		slices.SortFunc(selected, func(a, b *v1alpha1.Application) int {
+			ra, rb := rank[a], rank[b]
+			return cmp.Or(slices.Compare(ra[:], rb[:]), byName(a, b))
+		})
+		selected = selected[:maxApps]
+		slices.SortFunc(selected, byName)
+	} else {
+		slices.SortFunc(selected, byName)
+		selected = selected[:maxApps]
+	}
+
+	return selected, &scanTruncation{Matched: len(apps), Checked: maxApps, Mode: mode}
+}
+
+// formatTruncation returns the report note of a truncated scan
+func formatTruncation(truncation *scanTruncation, tr *i18n.Localizer) string {
+	return tr.T(i18n.ScanTruncated, truncation.Checked, truncation.Matched, truncation.Mode)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"argazer/internal/config"
+)
+
+func testApplications(n int) []*v1alpha1.Application {
+	apps := make([]*v1alpha1.Application, n)
+	for i := range apps {
+		apps[n-1-i] = &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("app-%02d", i), Namespace: "argocd"}}
+	}
+	return apps
+}
+
+func appNames(apps []*v1alpha1.Application) []string {
+	names := make([]string, len(apps))
+	for i, app := range apps {
+		names[i] = app.Name
+	}
+	return names
+}
+
+func TestLimitApplications(t *testing.T) {
+	t.Run("no cap", func(t *testing.T) {
+		apps := testApplications(5)
+		limited, truncation := limitApplications(apps, 0, config.MaxAppsModeFirst, "")
+		assert.Len(t, limited, 5)
+		assert.Nil(t, truncation)
+	})
+
+	t.Run("cap above the number of applications", func(t *testing.T) {
+		limited, truncation := limitApplications(testApplications(5), 10, config.MaxAppsModeFirst, "")
+		assert.Len(t, limited, 5)
+		assert.Nil(t, truncation)
+	})
+
+	t.Run("first by name", func(t *testing.T) {
+		limited, truncation := limitApplications(testApplications(5), 2, config.MaxAppsModeFirst, "")
+		assert.Equal(t, []string{"app-00", "app-01"}, appNames(limited))
+		assert.Equal(t, &scanTruncation{Matched: 5, Checked: 2, Mode: config.MaxAppsModeFirst}, truncation)
+	})
+
+	t.Run("deterministic sample", func(t *testing.T) {
+		apps := testApplications(50)
+		first, truncation := limitApplications(apps, 5, config.MaxAppsModeSample, "smoke")
+		require.Len(t, first, 5)
+		assert.Equal(t, &scanTruncation{Matched: 50, Checked: 5, Mode: config.MaxAppsModeSample}, truncation)
+		assert.True(t, slices.IsSorted(appNames(first)))
+
+		// Adding or removing applications only changes the picks they displace
+		changed := append(testApplications(50)[1:], &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "new-app", Namespace: "argocd"}})
+		again, _ := limitApplications(changed, 5, config.MaxAppsModeSample, "smoke")
+		shared := 0
+		for _, name := range appNames(again) {
+			if slices.Contains(appNames(first), name) {
+				shared++
+			}
+		}
+		assert.GreaterOrEqual(t, shared, 3)
+
+		same, _ := limitApplications(testApplications(50), 5, config.MaxAppsModeSample, "smoke")
+		assert.Equal(t, appNames(first), appNames(same))
+
+		other, _ := limitApplications(apps, 5, config.MaxAppsModeSample, "another seed")
+		assert.NotEqual(t, appNames(first), appNames(other))
+	})
+}
+
+func TestRenderResults_Truncation(t *testing.T) {
+	cat := processResults([]ApplicationCheckResult{{AppName: "app1", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"}})
+	cat.truncation = &scanTruncation{Matched: 1200, Checked: 50, Mode: config.MaxAppsModeSample}
+
+	for _, format := range []string{config.OutputFormatTable, config.OutputFormatMarkdown, config.OutputFormatMarkdownCompact} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, renderResults(cat, format, nil, &buf))
+			assert.Contains(t, buf.String(), "Scan truncated: 50 of 1200 matching applications checked (max_apps, sample selection)")
+		})
+	}
+
+	t.Run(config.OutputFormatJSON, func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, renderResults(cat, config.OutputFormatJSON, nil, &buf))
+		assert.Contains(t, buf.String(), `"truncated": {`)
+		assert.Contains(t, buf.String(), `"matched": 1200`)
+	})
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"argazer/internal/selfupdate"
+)
+
+// releasePublicKey is the base64-encoded ed25519 key release checksums are signed with, set at build time
+var releasePublicKey = ""
+
+// newSelfUpdateCmd creates the self-update command
+func newSelfUpdateCmd() *cobra.Command {
+	selfUpdateCmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update argazer to the latest release",
+		Long: `Self-update downloads the latest argazer release for this platform from GitHub, verifies it
+against the release's checksums and their ed25519 signature, and replaces the running binary in place.`,
+		RunE: runSelfUpdate,
+	}
+
+	selfUpdateCmd.Flags().Bool("check-only", false, "Only report whether a newer release is available")
+	selfUpdateCmd.Flags().Bool("skip-signature", false, "Install without verifying the checksums signature (the checksum is still verified)")
+	selfUpdateCmd.Flags().Bool("force", false, "Reinstall even if the current version is up to date")
+
+	return selfUpdateCmd
+}
+
+// runSelfUpdate checks for a newer release and installs it
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	checkOnly, _ := cmd.Flags().GetBool("check-only")
+	skipSignature, _ := cmd.Flags().GetBool("skip-signature")
+	force, _ := cmd.Flags().GetBool("force")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	logger := setupLogging(verbose, "text")
+	ctx, cancel := setupSignalHandler(logger)
+	defer cancel()
+
+	updater, err := selfupdate.New(selfupdate.DefaultReleasesURL, releasePublicKey, logger)
+	if err != nil {
+		return err
+	}
+
+	release, err := updater.Latest(ctx)
+	if err != nil {
+		return err
+	}
+	newer, err := selfupdate.IsNewer(version, release.Version)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if !newer && !force {
+		fmt.Fprintf(out, "argazer %s is up to date (latest release: %s)\n", version, release.Version)
+		return nil
+	}
+	if checkOnly {
+		fmt.Fprintf(out, "argazer %s is available (current: %s)\n", release.Version, version)
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return fmt.Errorf("failed to resolve executable: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"current": version,
+		"latest":  release.Version,
+		"path":    executable,
+	}).Info("Downloading release")
+
+	binary, err := updater.Download(ctx, release, !skipSignature)
+	if errors.Is(err, selfupdate.ErrNoPublicKey) {
+		return fmt.Errorf("%w; rerun with --skip-signature to rely on the checksum only", err)
+	}
+	if err != nil {
+		return err
+	}
+	if err := selfupdate.ReplaceExecutable(executable, binary); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Updated argazer %s -> %s\n", version, release.Version)
+	return nil
+}
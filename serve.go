@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"argazer/internal/config"
+	"argazer/internal/i18n"
+	"argazer/internal/notification"
+	"argazer/internal/server"
+	"argazer/internal/state"
+)
+
+// Paths where notification services deliver button presses
+const (
+	telegramCallbackPath = "/telegram/callback"
+	slackActionsPath     = "/slack/actions"
+)
+
+// newServeCmd creates the serve command
+func newServeCmd() *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run Argazer continuously and handle notification callbacks",
+		Long: `Serve runs the update check on a fixed interval and starts an HTTP server for
+notification callbacks (Telegram and Slack Ack/Snooze buttons), health checks,
+Prometheus metrics, a dashboard of the last scan (at /) and on-demand scans requested
+with Slack slash commands or POSTs to /chatops.
+Acknowledged and snoozed updates are recorded in the state file and not notified again.`,
+		RunE: runServe,
+	}
+
+	serveCmd.Flags().String("serve-address", ":8080", "Address for the HTTP server")
+	serveCmd.Flags().Duration("serve-interval", 24*time.Hour, "Interval between update checks")
+	serveCmd.Flags().Bool("serve-dashboard", true, "Serve a dashboard of the last scan at /")
+
+	if err := viper.BindPFlags(serveCmd.Flags()); err != nil {
+		logrus.WithError(err).Fatal("Failed to bind serve flags")
+	}
+
+	return serveCmd
+}
+
+// runServe runs periodic checks and the callback server until interrupted
+func runServe(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Setup logging
+	logger, err := setupConfiguredLogging(cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.ServeInterval <= 0 {
+		return fmt.Errorf("serve_interval must be positive, got %s", cfg.ServeInterval)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"argocd_url":   cfg.ArgocdURL,
+		"notification": cfg.NotificationChannel,
+		"address":      cfg.ServeAddress,
+		"interval":     cfg.ServeInterval.String(),
+		"state_file":   cfg.StateFile,
+		"version":      version,
+	}).Info("Starting Argazer in serve mode")
+
+	// Set up context with signal handling for graceful shutdown
+	ctx, cancel := setupSignalHandler(logger)
+	defer cancel()
+
+	store, err := state.NewStore(cfg.StateFile, logger.WithField("component", "state"))
+	if err != nil {
+		return fmt.Errorf("failed to open state file: %w", err)
+	}
+
+	// Initialize clients
+	clients, err := initializeClients(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	// Buttons are only attached when a handler for their callbacks is registered
+	withActions := false
+	srv := server.New(cfg.ServeAddress, logger.WithField("component", "server"))
+	metrics := &metricsHandler{}
+	srv.Handle(metricsPath, metrics)
+	dashboard := &dashboardHandler{groupBy: cfg.GroupBy}
+	if cfg.ServeDashboard {
+		srv.Handle(dashboardPath, dashboard)
+	}
+	if cfg.ChatOpsToken != "" || cfg.SlackSigningSecret != "" {
+		srv.Handle(chatOpsPath, server.NewChatOpsHandler(chatOpsScan(cfg, clients, logger), server.ChatOpsConfig{
+			SlackSigningSecret: cfg.SlackSigningSecret,
+			Token:              cfg.ChatOpsToken,
+		}, logger.WithField("component", "chatops")))
+	}
+	switch notifier := clients.notifier.(type) {
+	case *notification.TelegramNotifier:
+		srv.Handle(telegramCallbackPath, server.NewTelegramCallbackHandler(store, notifier, cfg.TelegramWebhookSecret, logger.WithField("component", "telegram-callback")))
+		withActions = true
+		if cfg.TelegramWebhookSecret == "" {
+			logger.Warn("telegram_webhook_secret is not set; Telegram callbacks are not authenticated")
+		}
+	case *notification.SlackNotifier:
+		// Unsigned requests can't be trusted, so interactivity requires the signing secret
+		if cfg.SlackSigningSecret != "" {
+			srv.Handle(slackActionsPath, server.NewSlackActionHandler(store, notifier, cfg.SlackSigningSecret, logger.WithField("component", "slack-actions")))
+			withActions = true
+		} else {
+			logger.Warn("slack_signing_secret is not set; Slack Ack/Snooze buttons are disabled")
+		}
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- srv.Run(ctx)
+	}()
+
+	ticker := time.NewTicker(cfg.ServeInterval)
+	defer ticker.Stop()
+
+	for {
+		runServeCycle(ctx, cfg, clients, store, metrics, dashboard, withActions, logger)
+
+		select {
+		case <-ctx.Done():
+			// Wait for the server to finish in-flight callbacks
+			if err := <-serverErr; err != nil {
+				return err
+			}
+			logger.Info("Argazer serve mode stopped")
+			return nil
+		case err := <-serverErr:
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+// runServeCycle performs a single check and notification round
+// Errors are logged rather than returned so one failed cycle doesn't stop the server
+func runServeCycle(ctx context.Context, cfg *config.Config, clients *clients, store *state.Store, metrics *metricsHandler, dashboard *dashboardHandler, withActions bool, logger *logrus.Entry) {
+	// Bound each cycle, so a hanging repository doesn't delay the next one
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	results, truncation, err := scan(ctx, cfg, clients, nil, logger)
+	if err != nil {
+		logger.WithError(err).Error("Update check failed")
+		return
+	}
+	scanned := time.Now()
+	metrics.update(results, scanned)
+
+	reportResults := results
+	if cfg.Redact {
+		reportResults = redactResults(results)
+	}
+	dashboard.update(reportResults, scanned)
+	report := processResults(reportResults)
+	report.truncation = truncation
+	report.layout = reportLayoutFromConfig(cfg)
+	if err := renderResults(report, cfg.OutputFormat, i18n.New(cfg.Language), os.Stdout); err != nil {
+		logger.WithError(err).Warn("Failed to output results")
+	}
+	if err := writeOutputFiles(report, cfg.OutputFiles, i18n.New(cfg.Language), logger); err != nil {
+		logger.WithError(err).Warn("Failed to write output files")
+	}
+
+	notifyResults, newResults := trackHistory(store, cfg, results, logger)
+
+	if clients.notifier != nil {
+		opts := notifyOptionsFromConfig(cfg)
+		opts.store = store
+		opts.withActions = withActions
+		opts.templates = clients.templates
+		if err := withTimeout(ctx, cfg.NotifyTimeout, func(ctx context.Context) error {
+			return sendNotificationsWithOptions(ctx, clients.notifier, notifyResults, opts, logger)
+		}); err != nil {
+			logger.WithError(err).Warn("Failed to send notifications")
+		}
+	}
+
+	if clients.syslog != nil {
+		if err := withTimeout(ctx, cfg.NotifyTimeout, func(ctx context.Context) error {
+			return sendEvents(ctx, clients.syslog, notifyResults, logger)
+		}); err != nil {
+			logger.WithError(err).Warn("Failed to send syslog events")
+		}
+	}
+
+	if clients.grafana != nil {
+		if err := withTimeout(ctx, cfg.NotifyTimeout, func(ctx context.Context) error {
+			return sendEvents(ctx, clients.grafana, newResults, logger)
+		}); err != nil {
+			logger.WithError(err).Warn("Failed to send Grafana annotations")
+		}
+	}
+
+	logger.WithField("total_checked", len(results)).Info("Update check completed")
+}
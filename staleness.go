@@ -0,0 +1,81 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"argazer/internal/notification"
+)
+
+// Staleness score: an update scores its severity weight, plus one point per release behind and
+// one point per stalenessDaysPerPoint days the latest release has been available
+const stalenessDaysPerPoint = 30
+
+// stalenessSeverityWeights weights updates by the semver component they change
+// Updates between non-semver versions get the major weight, as their severity can't be ruled out.
+var stalenessSeverityWeights = map[string]int{
+	notification.SeverityPatch: 1,
+	notification.SeverityMinor: 3,
+	notification.SeverityMajor: 10,
+	"":                         10,
+}
+
+// projectStaleness is the upgrade debt of the applications of one project
+type projectStaleness struct {
+	Project          string `json:"project"`
+	Apps             int    `json:"apps"`
+	Outdated         int    `json:"outdated"`
+	VersionsBehind   int    `json:"versions_behind"`
+	OldestUpdateDays int    `json:"oldest_update_days"` // Age of the latest release of the longest-pending update
+	Score            int    `json:"score"`
+}
+
+// setStaleness records how far an application with an update lags behind
+func setStaleness(result *ApplicationCheckResult, versionsBehind int, latestReleased, now time.Time) {
+	result.Severity = notification.UpdateSeverity(notification.ApplicationUpdate{CurrentVersion: result.CurrentVersion, LatestVersion: result.LatestVersion})
+	result.VersionsBehind = versionsBehind
+	if !latestReleased.IsZero() && now.After(latestReleased) {
+		result.LatestReleaseAgeDays = int(now.Sub(latestReleased).Hours() / 24)
+	}
+	result.StalenessScore = stalenessScore(result.Severity, result.VersionsBehind, result.LatestReleaseAgeDays)
+}
+
+// stalenessScore returns the staleness score of an update
+func stalenessScore(severity string, versionsBehind, ageDays int) int {
+	return stalenessSeverityWeights[severity] + versionsBehind + ageDays/stalenessDaysPerPoint
+}
+
+// summarizeStaleness aggregates the staleness of successfully checked applications per project,
+// highest score first
+func summarizeStaleness(results []ApplicationCheckResult) []projectStaleness {
+	byProject := make(map[string]*projectStaleness)
+	for _, result := range results {
+		if result.AppName == "" || result.Error != "" {
+			continue
+		}
+
+		summary, ok := byProject[result.Project]
+		if !ok {
+			summary = &projectStaleness{Project: result.Project}
+			byProject[result.Project] = summary
+		}
+		summary.Apps++
+		if !result.HasUpdate {
+			continue
+		}
+		summary.Outdated++
+		summary.VersionsBehind += result.VersionsBehind
+		summary.OldestUpdateDays = max(summary.OldestUpdateDays, result.LatestReleaseAgeDays)
+		summary.Score += result.StalenessScore
+	}
+
+	summaries := make([]projectStaleness, 0, len(byProject))
+	for _, summary := range byProject {
+		summaries = append(summaries, *summary)
+	}
+	slices.SortFunc(summaries, func(a, b projectStaleness) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Project, b.Project))
+	})
+	return summaries
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetStaleness(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("minor update with release date", func(t *testing.T) {
+		result := ApplicationCheckResult{CurrentVersion: "1.2.0", LatestVersion: "1.4.1"}
+		setStaleness(&result, 4, now.AddDate(0, 0, -65), now)
+		assert.Equal(t, "minor", result.Severity)
+		assert.Equal(t, 4, result.VersionsBehind)
+		assert.Equal(t, 65, result.LatestReleaseAgeDays)
+		assert.Equal(t, 3+4+2, result.StalenessScore)
+	})
+
+	t.Run("unknown release date", func(t *testing.T) {
+		result := ApplicationCheckResult{CurrentVersion: "1.2.0", LatestVersion: "1.2.1"}
+		setStaleness(&result, 1, time.Time{}, now)
+		assert.Equal(t, "patch", result.Severity)
+		assert.Equal(t, 0, result.LatestReleaseAgeDays)
+		assert.Equal(t, 2, result.StalenessScore)
+	})
+
+	t.Run("non-semver versions", func(t *testing.T) {
+		result := ApplicationCheckResult{CurrentVersion: "stable", LatestVersion: "2.0.0"}
+		setStaleness(&result, 0, time.Time{}, now)
+		assert.Empty(t, result.Severity)
+		assert.Equal(t, 10, result.StalenessScore)
+	})
+}
+
+func TestSummarizeStaleness(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "api", Project: "payments", HasUpdate: true, VersionsBehind: 3, LatestReleaseAgeDays: 40, StalenessScore: 14},
+		{AppName: "worker", Project: "payments", HasUpdate: true, VersionsBehind: 1, LatestReleaseAgeDays: 90, StalenessScore: 5},
+		{AppName: "web", Project: "payments"},
+		{AppName: "grafana", Project: "monitoring", HasUpdate: true, VersionsBehind: 12, LatestReleaseAgeDays: 200, StalenessScore: 28},
+		{AppName: "redis", Project: "cache"},
+		{AppName: "broken", Project: "cache", Error: "chart not found"},
+		{Project: "cache"},
+	}
+
+	summaries := summarizeStaleness(results)
+	require.Len(t, summaries, 3)
+	assert.Equal(t, projectStaleness{Project: "monitoring", Apps: 1, Outdated: 1, VersionsBehind: 12, OldestUpdateDays: 200, Score: 28}, summaries[0])
+	assert.Equal(t, projectStaleness{Project: "payments", Apps: 3, Outdated: 2, VersionsBehind: 4, OldestUpdateDays: 90, Score: 19}, summaries[1])
+	assert.Equal(t, projectStaleness{Project: "cache", Apps: 1}, summaries[2])
+}
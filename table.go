@@ -0,0 +1,322 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"argazer/internal/config"
+	"argazer/internal/i18n"
+	"argazer/internal/notification"
+)
+
+// reportLayout holds the presentation settings of the reports
+type reportLayout struct {
+	groupBy     string   // Splits each section into groups (group_by)
+	sortBy      string   // Row order of the table report, the order of the results when empty (sort_by)
+	columns     []string // Columns of the table report, config.DefaultColumns when empty (columns)
+	jsonCompact bool     // One JSON line per application instead of a document (json_compact)
+}
+
+// reportLayoutFromConfig returns the report layout set in the configuration
+func reportLayoutFromConfig(cfg *config.Config) reportLayout {
+	return reportLayout{groupBy: cfg.GroupBy, sortBy: cfg.SortBy, columns: cfg.Columns, jsonCompact: cfg.JSONCompact}
+}
+
+// tableColumnTitles are the field labels heading the columns of the table report
+var tableColumnTitles = map[string]string{
+	config.ColumnApp:        i18n.FieldApplication,
+	config.ColumnProject:    i18n.FieldProject,
+	config.ColumnChart:      i18n.FieldChart,
+	config.ColumnCurrent:    i18n.FieldCurrentVersion,
+	config.ColumnLatest:     i18n.FieldLatestVersion,
+	config.ColumnSeverity:   i18n.FieldSeverity,
+	config.ColumnConstraint: i18n.FieldConstraint,
+	config.ColumnRepo:       i18n.FieldRepository,
+	config.ColumnCluster:    i18n.FieldCluster,
+	config.ColumnLink:       i18n.FieldLink,
+	config.ColumnNote:       i18n.FieldNote,
+}
+
+// renderTable displays results as aligned tables, one per category
+func renderTable(cat categorizedResults, tr *i18n.Localizer, w io.Writer) error {
+	// Display summary
+	if _, err := fmt.Fprintln(w, "\n"+strings.Repeat("=", 80)); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+	fmt.Fprintln(w, tr.T(i18n.TableTitle))
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "\n%s: %d\n\n", tr.T(i18n.LabelTotal), cat.stats.total)
+	fmt.Fprintf(w, "%s: %d\n", tr.T(i18n.LabelUpToDate), cat.stats.upToDate)
+	fmt.Fprintf(w, "%s: %d\n", tr.T(i18n.LabelUpdates), cat.stats.updates)
+	if cat.stats.relocated > 0 {
+		fmt.Fprintf(w, "%s: %d\n", tr.T(i18n.LabelRelocated), cat.stats.relocated)
+	}
+	if cat.stats.tracking > 0 {
+		fmt.Fprintf(w, "%s: %d\n", tr.T(i18n.LabelTracking), cat.stats.tracking)
+	}
+	if cat.stats.drifted > 0 {
+		fmt.Fprintf(w, "%s: %d\n", tr.T(i18n.LabelDrifted), cat.stats.drifted)
+	}
+	if cat.stats.ignored > 0 {
+		fmt.Fprintf(w, "%s: %d\n", tr.T(i18n.LabelIgnored), cat.stats.ignored)
+	}
+	if cat.stats.images > 0 {
+		fmt.Fprintf(w, "%s: %d\n", tr.T(i18n.LabelImages), cat.stats.images)
+	}
+	if cat.stats.subcharts > 0 {
+		fmt.Fprintf(w, "%s: %d\n", tr.T(i18n.LabelSubcharts), cat.stats.subcharts)
+	}
+	fmt.Fprintf(w, "%s: %d\n", tr.T(i18n.LabelSkipped), cat.stats.skipped)
+	if cat.truncation != nil {
+		fmt.Fprintf(w, "\n%s\n", formatTruncation(cat.truncation, tr))
+	}
+
+	// Display outdated charts per ApplicationSet, where the fix is a single template bump
+	if outdated := outdatedApplicationSets(cat.applicationSets); len(outdated) > 0 {
+		writeTableHeading(w, tr.T(i18n.TableAppSets))
+		for _, summary := range outdated {
+			fmt.Fprintf(w, "\n%s: %s\n", tr.T(i18n.FieldApplicationSet), summary.ApplicationSet)
+			fmt.Fprintf(w, "  %s\n", formatApplicationSetSummary(summary, tr))
+		}
+	}
+
+	// Display updates, with the details that don't fit a cell below the table
+	writeResultsSection(w, tr.T(i18n.TableUpdates), cat.updatesAvailable, cat.layout, tr, func(result ApplicationCheckResult) string {
+		var notes []string
+		if result.HasUpdateOutsideConstraint && result.LatestVersionAll != "" {
+			notes = append(notes, tr.T(i18n.VersionOutsideConstraint, result.LatestVersionAll))
+		}
+		if result.SyncBlocked {
+			notes = append(notes, tr.T(i18n.FieldSyncWindow)+": "+notification.FormatSyncDeferral(tr, result.SyncBlockedBy, result.NextSyncWindow))
+		}
+		return strings.Join(notes, "; ")
+	})
+	writeUpdateDetails(w, sortTableResults(cat.updatesAvailable, cat.layout.sortBy), tr)
+
+	// Display apps that are up to date but have updates outside constraint
+	writeResultsSection(w, tr.T(i18n.TableOutside), cat.upToDateWithConstraint, cat.layout, tr, func(result ApplicationCheckResult) string {
+		if result.LatestVersionAll == "" {
+			return tr.T(i18n.UpToDateWithinConstraint, result.ConstraintApplied)
+		}
+		return tr.T(i18n.VersionOutsideConstraint, result.LatestVersionAll)
+	})
+
+	// Display container images with newer tags
+	if cat.stats.images > 0 {
+		writeTableHeading(w, tr.T(i18n.TableImages))
+		rows := make([][]string, len(cat.imageUpdates))
+		for i, update := range cat.imageUpdates {
+			note := ""
+			if update.HasUpdateOutsideConstraint && update.LatestTagAll != "" {
+				note = tr.T(i18n.VersionOutsideConstraint, update.LatestTagAll)
+			}
+			app := ApplicationCheckResult{AppName: update.AppName, Namespace: update.Namespace, Instance: update.Instance}
+			rows[i] = []string{resultDisplayName(app), update.Project, update.Image, update.CurrentTag, update.LatestTag, note}
+		}
+		writeTable(w, []string{tr.T(i18n.FieldApplication), tr.T(i18n.FieldProject), tr.T(i18n.FieldImage), tr.T(i18n.FieldCurrentTag), tr.T(i18n.FieldLatestTag), tr.T(i18n.FieldNote)}, rows)
+	}
+
+	// Display subcharts with newer versions
+	if cat.stats.subcharts > 0 {
+		writeTableHeading(w, tr.T(i18n.TableSubcharts))
+		rows := make([][]string, len(cat.dependencyUpdates))
+		for i, update := range cat.dependencyUpdates {
+			note := ""
+			if update.HasUpdateOutsideConstraint && update.LatestVersionAll != "" {
+				note = tr.T(i18n.VersionOutsideConstraint, update.LatestVersionAll)
+			}
+			app := ApplicationCheckResult{AppName: update.AppName, Namespace: update.Namespace, Instance: update.Instance}
+			rows[i] = []string{resultDisplayName(app), update.Project, update.Name, update.Repository, update.CurrentVersion, update.LatestVersion, note}
+		}
+		writeTable(w, []string{tr.T(i18n.FieldApplication), tr.T(i18n.FieldProject), tr.T(i18n.FieldSubchart), tr.T(i18n.FieldRepository), tr.T(i18n.FieldCurrentVersion), tr.T(i18n.FieldLatestVersion), tr.T(i18n.FieldNote)}, rows)
+	}
+
+	// Display applications whose chart has moved
+	writeResultsSection(w, tr.T(i18n.TableRelocated), cat.relocated, cat.layout, tr, func(result ApplicationCheckResult) string {
+		return result.RelocatedTo
+	})
+
+	// Display applications that track a Git branch
+	writeResultsSection(w, tr.T(i18n.TableTracking), cat.trackingBranch, cat.layout, tr, func(result ApplicationCheckResult) string {
+		return tr.T(i18n.FieldBranch) + ": " + result.TrackingBranch
+	})
+
+	// Display applications whose running chart version isn't the declared one
+	writeResultsSection(w, tr.T(i18n.TableDrifted), cat.drifted, cat.layout, tr, func(result ApplicationCheckResult) string {
+		return tr.T(i18n.FieldDeployedVersion) + ": " + result.DeployedVersion
+	})
+
+	// Display updates excluded by ignore rules
+	writeResultsSection(w, tr.T(i18n.TableIgnored), cat.ignored, cat.layout, tr, func(result ApplicationCheckResult) string {
+		return tr.T(i18n.FieldReason) + ": " + formatIgnoredBy(result, tr)
+	})
+
+	// Display skipped applications
+	writeResultsSection(w, tr.T(i18n.TableSkipped), cat.errors, cat.layout, tr, formatResultError)
+
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 80)+"\n")
+	return nil
+}
+
+// writeTableHeading writes the heading of a category of the table report
+func writeTableHeading(w io.Writer, title string) {
+	fmt.Fprintln(w, "\n"+strings.Repeat("-", 80))
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+}
+
+// writeResultsSection writes a category of the table report as one table per group, in the configured
+// columns and order; note fills the note column with what sets the category apart
+func writeResultsSection(w io.Writer, title string, results []ApplicationCheckResult, layout reportLayout, tr *i18n.Localizer, note func(ApplicationCheckResult) string) {
+	if len(results) == 0 {
+		return
+	}
+	writeTableHeading(w, title)
+
+	columns := layout.columns
+	if len(columns) == 0 {
+		columns = config.DefaultColumns
+	}
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = tr.T(tableColumnTitles[column])
+	}
+
+	for _, group := range groupResults(results, layout.groupBy) {
+		if heading := groupHeading(group, layout.groupBy, tr); heading != "" {
+			fmt.Fprintf(w, "\n== %s ==\n", heading)
+		}
+		sorted := sortTableResults(group.results, layout.sortBy)
+		rows := make([][]string, len(sorted))
+		for i, result := range sorted {
+			rows[i] = make([]string, len(columns))
+			for j, column := range columns {
+				rows[i][j] = tableCell(result, column, tr, note)
+			}
+		}
+		writeTable(w, headers, rows)
+	}
+}
+
+// tableCell returns the value of a result in a column of the table report
+func tableCell(result ApplicationCheckResult, column string, tr *i18n.Localizer, note func(ApplicationCheckResult) string) string {
+	switch column {
+	case config.ColumnApp:
+		return resultDisplayName(result)
+	case config.ColumnProject:
+		return result.Project
+	case config.ColumnChart:
+		return result.ChartName
+	case config.ColumnCurrent:
+		return formatCurrentVersion(result, tr)
+	case config.ColumnLatest:
+		return result.LatestVersion
+	case config.ColumnSeverity:
+		return result.Severity
+	case config.ColumnConstraint:
+		return result.ConstraintApplied
+	case config.ColumnRepo:
+		return result.RepoURL
+	case config.ColumnCluster:
+		return result.Cluster
+	case config.ColumnLink:
+		return result.URL
+	case config.ColumnNote:
+		return note(result)
+	}
+	return ""
+}
+
+// writeTable writes rows below uppercase headers in aligned columns; empty cells show "-"
+func writeTable(w io.Writer, headers []string, rows [][]string) {
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	cells := make([]string, len(headers))
+	for i, header := range headers {
+		cells[i] = strings.ToUpper(header)
+	}
+	fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	for _, row := range rows {
+		for i, cell := range row {
+			// Tabs and line breaks (e.g. in error messages) would break the alignment
+			cell = strings.Join(strings.Fields(cell), " ")
+			if cell == "" {
+				cell = "-"
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	tw.Flush()
+}
+
+// writeUpdateDetails writes, below the updates table, the details of updates that don't fit a cell:
+// value files, default values changes, security fixes, Artifact Hub metadata and release notes
+func writeUpdateDetails(w io.Writer, results []ApplicationCheckResult, tr *i18n.Localizer) {
+	for _, result := range results {
+		var b strings.Builder
+		for _, values := range result.ValuesSources {
+			fmt.Fprintf(&b, "  %s: %s\n", tr.T(i18n.FieldValues), values)
+		}
+		if result.ValuesDiff.HasChanges() {
+			fmt.Fprintf(&b, "  %s: %s\n", tr.T(i18n.FieldValuesDiff), formatValuesDiff(result.ValuesDiff, tr))
+			for _, key := range valuesDiffKeys(result.ValuesDiff) {
+				fmt.Fprintf(&b, "    %s\n", key)
+			}
+		}
+		if result.SecurityFixes != nil {
+			fmt.Fprintf(&b, "  %s: %s\n", tr.T(i18n.FieldSecurityFixes), formatSecurityFixes(result.SecurityFixes, tr))
+			for _, id := range securityFixesIDs(result.SecurityFixes) {
+				fmt.Fprintf(&b, "    %s\n", id)
+			}
+		}
+		for _, detail := range artifactHubDetails(result.ArtifactHub, tr, false) {
+			fmt.Fprintf(&b, "  %s: %s\n", detail.label, detail.value)
+		}
+		if result.ReleaseNotes != "" || result.ReleaseNotesURL != "" {
+			fmt.Fprintln(&b, strings.TrimRight(fmt.Sprintf("  %s: %s", tr.T(i18n.FieldReleaseNotes), result.ReleaseNotesURL), " "))
+			for _, line := range strings.Split(result.ReleaseNotes, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					fmt.Fprintf(&b, "    %s\n", line)
+				}
+			}
+		}
+
+		if b.Len() > 0 {
+			fmt.Fprintf(w, "\n%s:\n%s", resultDisplayName(result), b.String())
+		}
+	}
+}
+
+// sortTableResults returns the results in the sort_by order, ties by application name; the results as-is
+// without sort_by. Severities go from major to patch, versions from the oldest.
+func sortTableResults(results []ApplicationCheckResult, sortBy string) []ApplicationCheckResult {
+	if sortBy == "" {
+		return results
+	}
+	sorted := slices.Clone(results)
+	slices.SortStableFunc(sorted, func(a, b ApplicationCheckResult) int {
+		var order int
+		switch sortBy {
+		case config.SortByProject:
+			order = strings.Compare(instanceProject(a), instanceProject(b))
+		case config.SortByChart:
+			order = strings.Compare(a.ChartName, b.ChartName)
+		case config.SortBySeverity:
+			order = cmp.Compare(dashboardSeverityOrder[a.Severity], dashboardSeverityOrder[b.Severity])
+		case config.SortByCurrent:
+			order = compareVersions(a.CurrentVersion, b.CurrentVersion)
+		case config.SortByLatest:
+			order = compareVersions(a.LatestVersion, b.LatestVersion)
+		}
+		if order != 0 {
+			return order
+		}
+		return strings.Compare(resultDisplayName(a), resultDisplayName(b))
+	})
+	return sorted
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/config"
+	"argazer/internal/i18n"
+)
+
+func TestSortTableResults(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "web", Project: "frontend", ChartName: "nginx", CurrentVersion: "1.10.0", LatestVersion: "1.11.0", Severity: "minor"},
+		{AppName: "api", Project: "backend", ChartName: "api", CurrentVersion: "1.9.0", LatestVersion: "2.0.0", Severity: "major"},
+		{AppName: "cache", Project: "backend", ChartName: "redis", CurrentVersion: "1.9.0", LatestVersion: "1.9.1", Severity: "patch"},
+	}
+	names := func(results []ApplicationCheckResult) []string {
+		var names []string
+		for _, result := range results {
+			names = append(names, result.AppName)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"web", "api", "cache"}, names(sortTableResults(results, "")), "results keep their order without sort_by")
+	assert.Equal(t, []string{"api", "cache", "web"}, names(sortTableResults(results, config.SortByApp)))
+	assert.Equal(t, []string{"api", "cache", "web"}, names(sortTableResults(results, config.SortByProject)), "ties are ordered by application name")
+	assert.Equal(t, []string{"api", "web", "cache"}, names(sortTableResults(results, config.SortByChart)))
+	assert.Equal(t, []string{"api", "web", "cache"}, names(sortTableResults(results, config.SortBySeverity)))
+	assert.Equal(t, []string{"api", "cache", "web"}, names(sortTableResults(results, config.SortByCurrent)), "versions compare as semver")
+	assert.Equal(t, []string{"cache", "web", "api"}, names(sortTableResults(results, config.SortByLatest)))
+	assert.Equal(t, []string{"web", "api", "cache"}, names(results), "the input is left untouched")
+}
+
+func TestRenderTable_Columns(t *testing.T) {
+	cat := processResults([]ApplicationCheckResult{
+		{AppName: "web", Project: "default", ChartName: "nginx", RepoURL: "https://charts.example.com", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "api", Project: "default", ChartName: "api", RepoURL: "https://charts.example.com", CurrentVersion: "2.0.0", LatestVersion: "3.0.0", HasUpdate: true},
+	})
+	cat.layout = reportLayout{sortBy: config.SortByLatest, columns: []string{config.ColumnApp, config.ColumnRepo, config.ColumnLink}}
+
+	var table bytes.Buffer
+	require.NoError(t, renderResults(cat, config.OutputFormatTable, i18n.New("en"), &table))
+	assert.Regexp(t, `\nAPPLICATION\s+REPOSITORY\s+LINK\nweb\s+https://charts.example.com\s+-\napi\s+https://charts.example.com\s+-\n`, table.String())
+	assert.NotContains(t, table.String(), "SEVERITY")
+}
@@ -0,0 +1,828 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"argazer/internal/config"
+	"argazer/internal/gitops"
+	"argazer/internal/i18n"
+	"argazer/internal/server"
+	"argazer/internal/state"
+)
+
+// Layout of the interactive view
+const (
+	tuiMaxColumnWidth = 40
+	tuiDetailHeight   = 10 // Lines of the detail pane, including its separator
+	tuiProgressWidth  = 20
+)
+
+// ANSI escape sequences used to style the view
+const (
+	tuiBold    = "\x1b[1m"
+	tuiReverse = "\x1b[7m"
+	tuiReset   = "\x1b[0m"
+)
+
+// tuiSortColumns are the columns the table can be sorted by, in the order the sort key cycles through
+var tuiSortColumns = []string{"status", "application", "project", "chart"}
+
+// tuiStatusOrder ranks the statuses when sorting by status, the ones needing attention first
+var tuiStatusOrder = map[string]int{
+	"error":      0,
+	"update":     1,
+	"drifted":    2,
+	"relocated":  3,
+	"branch":     4,
+	"snoozed":    5,
+	"ignored":    6,
+	"updated":    7,
+	"up to date": 8,
+}
+
+// tuiCommand is what a key press asks for beyond changing the view
+type tuiCommand int
+
+const (
+	tuiNone          tuiCommand = iota
+	tuiQuit                     // Leave the view
+	tuiRescan                   // Check the applications again
+	tuiSnooze                   // Snooze the update of the selected application
+	tuiRequestUpdate            // Ask to confirm the update of the selected application
+	tuiUpdate                   // Apply the update that was confirmed
+)
+
+// tuiScanEvent reports the progress of a scan to the view
+type tuiScanEvent struct {
+	total   int                     // Number of applications to check, once they're fetched
+	result  *ApplicationCheckResult // A checked application
+	done    bool                    // The scan completed, with apps and results or err
+	apps    []*v1alpha1.Application
+	results []ApplicationCheckResult
+	err     error
+}
+
+// tuiUpdateOutcome is the outcome of an update applied from the view
+type tuiUpdateOutcome struct {
+	update pendingUpdate
+	url    string // Pull request URL with gitops_provider
+	err    error
+}
+
+// tuiModel is the state of the interactive view, changed by key presses and scan events and drawn
+// as a whole after each change
+type tuiModel struct {
+	results     []ApplicationCheckResult
+	rows        []int  // Indices in results of the rows shown, filtered and sorted
+	cursor      int    // Selected row
+	selectedKey string // resultKey of the selected application, kept while no row is shown
+	offset      int    // First row on screen
+	pageSize    int    // Rows on screen at the last draw
+	filter      string
+	filtering   bool   // The filter is being typed
+	confirming  string // Pending update confirmation prompt
+	sortColumn  int    // Index in tuiSortColumns
+	sortReverse bool
+	detail      bool // The detail pane of the selected application is shown
+	message     string
+
+	scanning       bool
+	checked, total int
+
+	snoozed      map[string]bool                   // Snoozed updates by resultKey
+	updated      map[string]string                 // Outcome of the updates applied from the view by resultKey
+	acknowledged func(ApplicationCheckResult) bool // Reports updates acknowledged or snoozed in the state file
+	tr           *i18n.Localizer
+}
+
+// newTUICmd creates the tui command
+func newTUICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "Browse scan results interactively",
+		Long: `Tui checks the selected applications like a regular run and shows the results in an interactive
+table while the scan progresses. The table can be filtered and sorted, and shows the versions,
+repository and error of the selected application in a detail pane.
+Updates can be snoozed for 30 days, like with the notification buttons of serve mode (recorded in
+the state file), or applied like argazer update does, after confirmation.
+
+Keys: ↑/↓ (j/k) move, / filter, s sort column, S reverse order, enter details, z snooze, u update,
+r rescan, q quit.`,
+		RunE: runTUI,
+	}
+}
+
+// runTUI runs the interactive view until the user quits
+func runTUI(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Set up logging
+	logger, err := setupConfiguredLogging(cfg)
+	if err != nil {
+		return err
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return errors.New("tui requires an interactive terminal")
+	}
+	// Log lines would garble the view, so they're only written to log_file
+	if cfg.LogFile == "" {
+		logrus.SetOutput(io.Discard)
+	}
+
+	// Set up context with signal handling for graceful shutdown
+	ctx, cancel := setupSignalHandler(logger)
+	defer cancel()
+
+	store, err := state.NewStore(cfg.StateFile, logger.WithField("component", "state"))
+	if err != nil {
+		return fmt.Errorf("failed to open state file: %w", err)
+	}
+
+	clients, err := initializeClients(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	var creator *gitops.Creator
+	if cfg.GitOpsProvider != "" {
+		if creator, err = newGitOpsCreator(cfg, logger.WithField("component", "gitops")); err != nil {
+			return err
+		}
+	}
+
+	app := &tuiApp{
+		ctx: ctx,
+		model: newTUIModel(func(result ApplicationCheckResult) bool {
+			return store.IsAcknowledged(result.AppName, result.LatestVersion, time.Now())
+		}, i18n.New(cfg.Language)),
+		scan: func(ctx context.Context, events chan<- tuiScanEvent) {
+			runTUIScan(ctx, cfg, clients, events, logger)
+		},
+		snooze: func(result ApplicationCheckResult, until time.Time) error {
+			return store.Acknowledge(state.Acknowledgement{
+				AppName: result.AppName,
+				Version: result.LatestVersion,
+				Until:   until,
+				Source:  "tui",
+			})
+		},
+		pendingUpdate: func(apps []*v1alpha1.Application, result ApplicationCheckResult) (pendingUpdate, bool) {
+			updates := pendingUpdates(apps, []ApplicationCheckResult{result}, cfg.SourceName, logger)
+			if len(updates) == 0 {
+				return pendingUpdate{}, false
+			}
+			return updates[0], true
+		},
+		applyUpdate: func(ctx context.Context, update pendingUpdate) (string, error) {
+			return applyUpdate(ctx, cfg, clients, creator, update)
+		},
+		pullRequests: creator != nil,
+		logger:       logger,
+	}
+
+	// Signals cancel ctx, which stops the program
+	program := tea.NewProgram(app, tea.WithContext(ctx), tea.WithoutSignalHandler(), tea.WithAltScreen(), tea.WithOutput(cmd.OutOrStdout()))
+	if _, err := program.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to run the interactive view: %w", err)
+	}
+	return nil
+}
+
+// tuiApp runs the interactive view as a Bubble Tea program: it passes key presses to the model and
+// carries out the scans, snoozes and updates they ask for
+// The actions are functions so the view can be driven without ArgoCD or a state file.
+type tuiApp struct {
+	ctx           context.Context
+	model         *tuiModel
+	width, height int // Terminal size, 80x24 until reported
+
+	scan          func(ctx context.Context, events chan<- tuiScanEvent)
+	snooze        func(result ApplicationCheckResult, until time.Time) error
+	pendingUpdate func(apps []*v1alpha1.Application, result ApplicationCheckResult) (pendingUpdate, bool)
+	applyUpdate   func(ctx context.Context, update pendingUpdate) (string, error)
+	pullRequests  bool // Updates are applied by pull request (gitops_provider)
+	logger        *logrus.Entry
+
+	apps    []*v1alpha1.Application // Applications of the last completed scan, needed to apply updates
+	pending pendingUpdate           // Update waiting for confirmation
+}
+
+// tuiScanMsg delivers an event of the scan sending to events
+type tuiScanMsg struct {
+	event  tuiScanEvent
+	events <-chan tuiScanEvent
+}
+
+func (a *tuiApp) Init() tea.Cmd {
+	return a.startScan()
+}
+
+// startScan clears the view and checks the applications again, delivering the progress as messages
+func (a *tuiApp) startScan() tea.Cmd {
+	a.model.startScan()
+	events := make(chan tuiScanEvent)
+	go a.scan(a.ctx, events)
+	return a.nextScanEvent(events)
+}
+
+// nextScanEvent waits for the next event of a scan
+func (a *tuiApp) nextScanEvent(events <-chan tuiScanEvent) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case event := <-events:
+			return tuiScanMsg{event: event, events: events}
+		case <-a.ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (a *tuiApp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		a.width, a.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		// Runes typed or pasted in a row arrive as one message
+		if msg.Type == tea.KeyRunes && !msg.Alt {
+			var cmds []tea.Cmd
+			for _, r := range msg.Runes {
+				cmds = append(cmds, a.handleKey(string(r)))
+			}
+			return a, tea.Batch(cmds...)
+		}
+		return a, a.handleKey(msg.String())
+	case tuiScanMsg:
+		if msg.event.done && msg.event.err == nil {
+			a.apps = msg.event.apps
+		}
+		a.model.scanEvent(msg.event)
+		if !msg.event.done {
+			return a, a.nextScanEvent(msg.events)
+		}
+	case tuiUpdateOutcome:
+		a.updateOutcome(msg)
+	}
+	return a, nil
+}
+
+// handleKey applies a key press to the model and carries out what it asks for
+func (a *tuiApp) handleKey(key string) tea.Cmd {
+	m := a.model
+	switch m.handleKey(key) {
+	case tuiQuit:
+		return tea.Quit
+	case tuiRescan:
+		return a.startScan()
+	case tuiSnooze:
+		result := *m.selected()
+		until := time.Now().Add(server.SnoozeDuration)
+		if err := a.snooze(result, until); err != nil {
+			m.message = fmt.Sprintf("Failed to snooze %s: %v", resultDisplayName(result), err)
+			return nil
+		}
+		m.markSnoozed(result, until)
+	case tuiRequestUpdate:
+		result := *m.selected()
+		update, ok := a.pendingUpdate(a.apps, result)
+		if !ok {
+			m.message = fmt.Sprintf("%s can't be updated automatically (pinned revision, mutable tag, tracked branch or relocated chart)", resultDisplayName(result))
+			return nil
+		}
+		a.pending = update
+		if a.pullRequests {
+			m.confirm(fmt.Sprintf("Open a pull request updating %s from %s to %s?", resultDisplayName(result), result.CurrentVersion, result.LatestVersion))
+		} else {
+			m.confirm(fmt.Sprintf("Update %s from %s to %s?", resultDisplayName(result), result.CurrentVersion, result.LatestVersion))
+		}
+	case tuiUpdate:
+		update := a.pending
+		m.message = fmt.Sprintf("Updating %s...", resultDisplayName(update.result))
+		return func() tea.Msg {
+			url, err := a.applyUpdate(a.ctx, update)
+			return tuiUpdateOutcome{update: update, url: url, err: err}
+		}
+	}
+	return nil
+}
+
+// updateOutcome shows the outcome of an update applied from the view
+func (a *tuiApp) updateOutcome(outcome tuiUpdateOutcome) {
+	result := outcome.update.result
+	switch {
+	case errors.Is(outcome.err, gitops.ErrNoRepository):
+		a.model.message = fmt.Sprintf("Skipped %s: no gitops_repositories entry matches it", resultDisplayName(result))
+	case outcome.err != nil:
+		a.logger.WithError(outcome.err).WithField("app_name", result.AppName).Error("Failed to update application")
+		a.model.message = fmt.Sprintf("Failed to update %s: %v", resultDisplayName(result), outcome.err)
+	case outcome.url != "":
+		a.model.markUpdated(result, "pull request "+outcome.url)
+	default:
+		a.model.markUpdated(result, fmt.Sprintf("updated from %s to %s", result.CurrentVersion, result.LatestVersion))
+	}
+}
+
+func (a *tuiApp) View() string {
+	width, height := a.width, a.height
+	if width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	return strings.Join(a.model.view(width, height), "\n")
+}
+
+// runTUIScan fetches and checks the applications, sending the progress to events
+// The applications are needed to apply updates, so this doesn't go through scan.
+func runTUIScan(ctx context.Context, cfg *config.Config, clients *clients, events chan<- tuiScanEvent, logger *logrus.Entry) {
+	send := func(event tuiScanEvent) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	scanCtx := ctx
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		scanCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	var apps []*v1alpha1.Application
+	if err := withTimeout(scanCtx, cfg.ArgocdTimeout, func(ctx context.Context) error {
+		var err error
+		apps, _, err = fetchApplications(ctx, clients, cfg, logger)
+		return err
+	}); err != nil {
+		send(tuiScanEvent{done: true, err: err})
+		return
+	}
+	send(tuiScanEvent{total: len(apps)})
+
+	// A repository that was down in the previous scan gets another chance
+	clients.helm.ResetCircuits()
+	results := checkApplicationsWithProgress(scanCtx, apps, clients.helm, cfg, func(progress checkProgress) {
+		if progress.result != nil {
+			send(tuiScanEvent{result: progress.result})
+		}
+	}, logger)
+	clients.helm.ReleaseClones()
+	applyIgnoreRules(results, cfg.Ignore, time.Now(), logger)
+
+	send(tuiScanEvent{done: true, apps: apps, results: results})
+}
+
+// resultDisplayName returns the name of an application with its namespace and instance, e.g. "prod:argocd/nginx"
+func resultDisplayName(result ApplicationCheckResult) string {
+	name := result.AppName
+	if result.Namespace != "" {
+		name = result.Namespace + "/" + name
+	}
+	if result.Instance != "" {
+		name = result.Instance + ":" + name
+	}
+	return name
+}
+
+// newTUIModel creates an empty view
+func newTUIModel(acknowledged func(ApplicationCheckResult) bool, tr *i18n.Localizer) *tuiModel {
+	return &tuiModel{
+		snoozed:      make(map[string]bool),
+		updated:      make(map[string]string),
+		acknowledged: acknowledged,
+		tr:           tr,
+	}
+}
+
+// startScan clears the results for a new scan
+func (m *tuiModel) startScan() {
+	m.scanning = true
+	m.checked, m.total = 0, 0
+	m.results = nil
+	m.snoozed = make(map[string]bool)
+	m.updated = make(map[string]string)
+	m.message = ""
+	m.refresh()
+}
+
+// scanEvent applies the progress of the scan
+func (m *tuiModel) scanEvent(event tuiScanEvent) {
+	switch {
+	case event.done:
+		m.finishScan(event.results, event.err)
+	case event.result != nil:
+		m.checked++
+		m.addResult(*event.result)
+		m.refresh()
+	default:
+		m.total = event.total
+	}
+}
+
+// finishScan replaces the results of the scan by its final ones, which have the ignore rules applied
+func (m *tuiModel) finishScan(results []ApplicationCheckResult, err error) {
+	m.scanning = false
+	if err != nil {
+		m.message = fmt.Sprintf("Scan failed: %v", err)
+		return
+	}
+
+	m.results = nil
+	for _, result := range results {
+		m.addResult(result)
+	}
+	m.refresh()
+	m.message = fmt.Sprintf("Checked %d applications", len(m.results))
+}
+
+// addResult adds the result of a checked application, skipping applications without Helm sources
+func (m *tuiModel) addResult(result ApplicationCheckResult) {
+	if result.AppName == "" {
+		return
+	}
+	if m.acknowledged != nil && result.HasUpdate && m.acknowledged(result) {
+		m.snoozed[resultKey(result)] = true
+	}
+	m.results = append(m.results, result)
+}
+
+// status returns the status of a result as shown in the table
+func (m *tuiModel) status(result ApplicationCheckResult) string {
+	key := resultKey(result)
+	if _, ok := m.updated[key]; ok {
+		return "updated"
+	}
+	if m.snoozed[key] && result.HasUpdate && result.Error == "" {
+		return "snoozed"
+	}
+	return reportStatusLabels[reportStatus(result)]
+}
+
+// selected returns the result of the selected row, nil when no row is shown
+func (m *tuiModel) selected() *ApplicationCheckResult {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return &m.results[m.rows[m.cursor]]
+}
+
+// refresh filters and sorts the rows, keeping the selected application selected
+// When the filter hides it, the first row is selected; when no row is shown at all (e.g. while a
+// filter is typed), the selection is kept for when rows come back.
+func (m *tuiModel) refresh() {
+	filter := strings.ToLower(m.filter)
+	m.rows = nil
+	for i, result := range m.results {
+		if filter == "" || strings.Contains(m.searchText(result), filter) {
+			m.rows = append(m.rows, i)
+		}
+	}
+	sort.SliceStable(m.rows, func(i, j int) bool {
+		return m.less(m.results[m.rows[i]], m.results[m.rows[j]])
+	})
+
+	cursor := 0
+	for i, index := range m.rows {
+		if resultKey(m.results[index]) == m.selectedKey {
+			cursor = i
+			break
+		}
+	}
+	m.selectRow(cursor)
+}
+
+// selectRow selects a row, remembering its application; without rows, the selection is left as is
+func (m *tuiModel) selectRow(row int) {
+	m.cursor = row
+	if result := m.selected(); result != nil {
+		m.selectedKey = resultKey(*result)
+	}
+}
+
+// searchText returns the lowercase text the filter is matched against
+func (m *tuiModel) searchText(result ApplicationCheckResult) string {
+	return strings.ToLower(strings.Join([]string{resultDisplayName(result), instanceProject(result), result.ChartName, result.RepoURL, m.status(result)}, " "))
+}
+
+// less orders results by the sort column, then by name
+func (m *tuiModel) less(a, b ApplicationCheckResult) bool {
+	order := 0
+	switch tuiSortColumns[m.sortColumn] {
+	case "status":
+		order = tuiStatusOrder[m.status(a)] - tuiStatusOrder[m.status(b)]
+	case "project":
+		order = strings.Compare(instanceProject(a), instanceProject(b))
+	case "chart":
+		order = strings.Compare(a.ChartName, b.ChartName)
+	}
+	if order == 0 {
+		order = strings.Compare(resultDisplayName(a), resultDisplayName(b))
+	}
+	if m.sortReverse {
+		return order > 0
+	}
+	return order < 0
+}
+
+// move moves the selection by delta rows
+func (m *tuiModel) move(delta int) {
+	m.selectRow(max(0, min(m.cursor+delta, len(m.rows)-1)))
+}
+
+// handleKey applies a key press and returns what it asks for beyond changing the view
+func (m *tuiModel) handleKey(key string) tuiCommand {
+	if key == "ctrl+c" {
+		return tuiQuit
+	}
+
+	if m.filtering {
+		switch key {
+		case "enter":
+			m.filtering = false
+		case "esc":
+			m.filtering = false
+			m.filter = ""
+		case "backspace":
+			_, size := utf8.DecodeLastRuneInString(m.filter)
+			m.filter = m.filter[:len(m.filter)-size]
+		default:
+			if utf8.RuneCountInString(key) == 1 {
+				m.filter += key
+			}
+		}
+		m.refresh()
+		return tuiNone
+	}
+
+	if m.confirming != "" {
+		m.confirming = ""
+		if key == "y" || key == "Y" {
+			return tuiUpdate
+		}
+		m.message = "Update cancelled"
+		return tuiNone
+	}
+
+	m.message = ""
+	switch key {
+	case "q":
+		return tuiQuit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-max(m.pageSize, 1))
+	case "pgdown":
+		m.move(max(m.pageSize, 1))
+	case "home", "g":
+		m.selectRow(0)
+	case "end", "G":
+		m.move(len(m.rows))
+	case "/":
+		m.filtering = true
+	case "s":
+		m.sortColumn = (m.sortColumn + 1) % len(tuiSortColumns)
+		m.refresh()
+	case "S":
+		m.sortReverse = !m.sortReverse
+		m.refresh()
+	case "enter", "tab":
+		m.detail = !m.detail
+	case "esc":
+		m.detail = false
+		if m.filter != "" {
+			m.filter = ""
+			m.refresh()
+		}
+	case "r":
+		if m.scanning {
+			m.message = "A scan is already running"
+			return tuiNone
+		}
+		return tuiRescan
+	case "z":
+		if m.actionable() {
+			return tuiSnooze
+		}
+	case "u":
+		if m.actionable() {
+			return tuiRequestUpdate
+		}
+	}
+	return tuiNone
+}
+
+// actionable reports whether the selected application has an update that can be snoozed or applied,
+// explaining why not in the message otherwise
+func (m *tuiModel) actionable() bool {
+	result := m.selected()
+	switch {
+	case result == nil:
+		m.message = "No application selected"
+	case m.scanning:
+		m.message = "Wait for the scan to complete"
+	case !result.HasUpdate || result.Error != "":
+		m.message = fmt.Sprintf("%s has no update", resultDisplayName(*result))
+	case m.updated[resultKey(*result)] != "":
+		m.message = fmt.Sprintf("%s was already updated", resultDisplayName(*result))
+	default:
+		return true
+	}
+	return false
+}
+
+// confirm asks the user to confirm an update with y
+func (m *tuiModel) confirm(prompt string) {
+	m.confirming = prompt
+}
+
+// markSnoozed shows the update of an application as snoozed
+func (m *tuiModel) markSnoozed(result ApplicationCheckResult, until time.Time) {
+	m.snoozed[resultKey(result)] = true
+	m.message = fmt.Sprintf("Snoozed %s %s until %s", resultDisplayName(result), result.LatestVersion, until.Format("2006-01-02"))
+	m.refresh()
+}
+
+// markUpdated shows an application as updated, with the outcome of the update
+func (m *tuiModel) markUpdated(result ApplicationCheckResult, outcome string) {
+	m.updated[resultKey(result)] = outcome
+	m.message = fmt.Sprintf("%s: %s", resultDisplayName(result), outcome)
+	m.refresh()
+}
+
+// view returns the lines of the view for a terminal of the given size
+func (m *tuiModel) view(width, height int) []string {
+	var detail []string
+	if m.detail {
+		detail = m.detailLines(width)
+	}
+	// Title, table header, message and help lines
+	m.pageSize = max(height-4-len(detail), 1)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.rows)-m.pageSize))
+
+	lines := []string{tuiBold + tuiTruncate(m.title(), width) + tuiReset}
+
+	header := []string{"STATUS", "APPLICATION", "PROJECT", "CHART", "CURRENT", "LATEST"}
+	cells := make([][]string, len(m.rows))
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = len(h)
+	}
+	for i, index := range m.rows {
+		result := m.results[index]
+		latest := result.LatestVersion
+		if latest == "" {
+			latest = "-"
+		}
+		cells[i] = []string{m.status(result), resultDisplayName(result), instanceProject(result), result.ChartName, result.CurrentVersion, latest}
+		for j, cell := range cells[i] {
+			widths[j] = max(widths[j], min(utf8.RuneCountInString(cell), tuiMaxColumnWidth))
+		}
+	}
+
+	lines = append(lines, tuiBold+tuiTruncate(tuiRow(header, widths), width)+tuiReset)
+	for i := m.offset; i < m.offset+m.pageSize; i++ {
+		switch {
+		case i >= len(m.rows):
+			if i == 0 && !m.scanning {
+				lines = append(lines, "No applications match")
+			} else {
+				lines = append(lines, "")
+			}
+		case i == m.cursor:
+			row := tuiTruncate(tuiRow(cells[i], widths), width)
+			lines = append(lines, tuiReverse+row+strings.Repeat(" ", width-utf8.RuneCountInString(row))+tuiReset)
+		default:
+			lines = append(lines, tuiTruncate(tuiRow(cells[i], widths), width))
+		}
+	}
+	lines = append(lines, detail...)
+
+	switch {
+	case m.filtering:
+		lines = append(lines, tuiTruncate("Filter: "+m.filter+"█", width), "enter apply  esc clear")
+	case m.confirming != "":
+		lines = append(lines, tuiBold+tuiTruncate(m.confirming+" [y/N]", width)+tuiReset, "")
+	default:
+		lines = append(lines, tuiTruncate(m.message, width),
+			tuiTruncate("↑↓ move  / filter  s/S sort  enter details  z snooze  u update  r rescan  q quit", width))
+	}
+	return lines
+}
+
+// title returns the first line of the view: the scan progress, or a summary of the results
+func (m *tuiModel) title() string {
+	if m.scanning {
+		if m.total == 0 {
+			return "argazer · fetching applications..."
+		}
+		done := tuiProgressWidth * m.checked / m.total
+		return fmt.Sprintf("argazer · checking applications [%s%s] %d/%d",
+			strings.Repeat("#", done), strings.Repeat("-", tuiProgressWidth-done), m.checked, m.total)
+	}
+
+	updates, errs := 0, 0
+	for _, result := range m.results {
+		switch m.status(result) {
+		case "update":
+			updates++
+		case "error":
+			errs++
+		}
+	}
+	title := fmt.Sprintf("argazer · %d applications, %d updates, %d errors · sorted by %s",
+		len(m.results), updates, errs, tuiSortColumns[m.sortColumn])
+	if m.sortReverse {
+		title += " (reversed)"
+	}
+	if m.filter != "" && !m.filtering {
+		title += fmt.Sprintf(" · filter %q (%d shown)", m.filter, len(m.rows))
+	}
+	return title
+}
+
+// detailLines returns the detail pane of the selected application
+func (m *tuiModel) detailLines(width int) []string {
+	lines := []string{strings.Repeat("─", width)}
+	result := m.selected()
+	if result == nil {
+		lines = append(lines, "No application selected")
+	} else {
+		field := func(label, value string) {
+			if value != "" {
+				lines = append(lines, tuiTruncate(fmt.Sprintf("%-17s %s", label+":", value), width))
+			}
+		}
+		field("Application", resultDisplayName(*result))
+		field("Project", instanceProject(*result))
+		field("Repository", result.RepoURL)
+		field("Chart", result.ChartName)
+		field("Current version", formatCurrentVersion(*result, m.tr))
+		field("Deployed version", result.DeployedVersion)
+		latest := result.LatestVersion
+		if latest != "" && result.ConstraintApplied != "" {
+			latest += " (constraint: " + result.ConstraintApplied + ")"
+		}
+		field("Latest version", latest)
+		if result.HasUpdateOutsideConstraint {
+			field("Latest overall", result.LatestVersionAll)
+		}
+		field("Branch", result.TrackingBranch)
+		field("Relocated to", result.RelocatedTo)
+		field("Ignored", result.IgnoredBy)
+		field("Error", formatResultError(*result))
+		field("Updated", m.updated[resultKey(*result)])
+		field("URL", result.URL)
+	}
+
+	for len(lines) < tuiDetailHeight {
+		lines = append(lines, "")
+	}
+	return lines[:tuiDetailHeight]
+}
+
+// tuiRow joins the cells of a table row, padded to the column widths
+func tuiRow(cells []string, widths []int) string {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = tuiTruncate(cell, widths[i])
+		padded[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+	}
+	return strings.TrimRight(strings.Join(padded, "  "), " ")
+}
+
+// tuiTruncate shortens text to width characters, ending it with an ellipsis when cut
+func tuiTruncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/i18n"
+)
+
+func newTestTUIModel(results ...ApplicationCheckResult) *tuiModel {
+	m := newTUIModel(nil, i18n.New("en"))
+	m.startScan()
+	m.scanEvent(tuiScanEvent{done: true, results: results})
+	return m
+}
+
+// tuiRowNames returns the application names of the rows shown, in order
+func tuiRowNames(m *tuiModel) []string {
+	names := make([]string, 0, len(m.rows))
+	for _, index := range m.rows {
+		names = append(names, m.results[index].AppName)
+	}
+	return names
+}
+
+// newTestTUIApp returns a view whose scans send results, with stubbed snoozes and updates
+func newTestTUIApp(ctx context.Context, results ...ApplicationCheckResult) *tuiApp {
+	return &tuiApp{
+		ctx:   ctx,
+		model: newTUIModel(nil, i18n.New("en")),
+		scan: func(ctx context.Context, events chan<- tuiScanEvent) {
+			events <- tuiScanEvent{done: true, results: results}
+		},
+		snooze: func(ApplicationCheckResult, time.Time) error { return nil },
+		pendingUpdate: func(_ []*v1alpha1.Application, result ApplicationCheckResult) (pendingUpdate, bool) {
+			return pendingUpdate{result: result}, true
+		},
+		applyUpdate: func(context.Context, pendingUpdate) (string, error) {
+			return "https://github.com/org/deploy/pull/7", nil
+		},
+		pullRequests: true,
+		logger:       logrus.NewEntry(logrus.New()),
+	}
+}
+
+// runTUICmd runs a command and the commands of the messages it returns, like the program would
+func runTUICmd(app *tuiApp, cmd tea.Cmd) {
+	for cmd != nil {
+		msg := cmd()
+		if msg == nil {
+			return
+		}
+		_, cmd = app.Update(msg)
+	}
+}
+
+func TestTUIApp_Program(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	app := newTestTUIApp(ctx)
+	// The scan doesn't complete, so the keys apply to the view the same way whenever they're read
+	app.scan = func(ctx context.Context, events chan<- tuiScanEvent) { <-ctx.Done() }
+
+	var out bytes.Buffer
+	program := tea.NewProgram(app, tea.WithContext(ctx), tea.WithoutSignalHandler(), tea.WithInput(strings.NewReader("/wéb\x7f\x7fe\r\x1b[Bq")), tea.WithOutput(&out))
+	_, err := program.Run()
+	require.NoError(t, err)
+
+	assert.Equal(t, "we", app.model.filter, "runes, backspace and enter are read from the terminal")
+	assert.False(t, app.model.filtering)
+	assert.Contains(t, out.String(), "argazer · fetching applications...")
+}
+
+func TestTUIApp_Update(t *testing.T) {
+	app := newTestTUIApp(context.Background(),
+		ApplicationCheckResult{AppName: "cache", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		ApplicationCheckResult{AppName: "web", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+	)
+	runTUICmd(app, app.Init())
+	assert.Equal(t, []string{"cache", "web"}, tuiRowNames(app.model))
+	assert.False(t, app.model.scanning)
+
+	app.Update(tea.WindowSizeMsg{Width: 60, Height: 12})
+	assert.Len(t, strings.Split(app.View(), "\n"), 12)
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	assert.Nil(t, cmd)
+	assert.Equal(t, "Open a pull request updating cache from 1.0.0 to 2.0.0?", app.model.confirming)
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	runTUICmd(app, cmd)
+	assert.Equal(t, "updated", app.model.status(app.model.results[0]))
+	assert.Contains(t, app.model.updated[resultKey(app.model.results[0])], "pull/7")
+
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, "web", app.model.selected().AppName)
+	app.snooze = func(ApplicationCheckResult, time.Time) error { return errors.New("read-only") }
+	app.Update(tea.KeyMsg{Type: tea.KeyUp})
+	app.model.updated = map[string]string{}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	assert.Equal(t, "Failed to snooze cache: read-only", app.model.message)
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+func TestTUIModel_Scan(t *testing.T) {
+	m := newTUIModel(func(result ApplicationCheckResult) bool { return result.AppName == "redis" }, i18n.New("en"))
+	m.startScan()
+	assert.Contains(t, m.title(), "fetching applications")
+
+	m.scanEvent(tuiScanEvent{total: 4})
+	m.scanEvent(tuiScanEvent{result: &ApplicationCheckResult{AppName: "redis", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true}})
+	m.scanEvent(tuiScanEvent{result: &ApplicationCheckResult{}}) // Not a Helm application
+	assert.Contains(t, m.title(), "[##########----------] 2/4")
+	assert.Equal(t, []string{"redis"}, tuiRowNames(m))
+	assert.Equal(t, "snoozed", m.status(m.results[0]), "snoozed updates are read from the state file")
+
+	assert.Equal(t, tuiNone, m.handleKey("u"))
+	assert.Equal(t, "Wait for the scan to complete", m.message)
+
+	m.scanEvent(tuiScanEvent{done: true, err: errors.New("connection refused")})
+	assert.False(t, m.scanning)
+	assert.Equal(t, "Scan failed: connection refused", m.message)
+	assert.Equal(t, tuiRescan, m.handleKey("r"))
+}
+
+func TestTUIModel_SortAndFilter(t *testing.T) {
+	m := newTestTUIModel(
+		ApplicationCheckResult{AppName: "web", Project: "frontend", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+		ApplicationCheckResult{AppName: "cache", Project: "backend", ChartName: "redis", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		ApplicationCheckResult{AppName: "db", Project: "backend", ChartName: "postgresql", Error: "timeout"},
+	)
+	assert.Equal(t, []string{"db", "cache", "web"}, tuiRowNames(m), "errors and updates come first")
+
+	m.handleKey("s")
+	assert.Equal(t, []string{"cache", "db", "web"}, tuiRowNames(m))
+	m.handleKey("S")
+	assert.Equal(t, []string{"web", "db", "cache"}, tuiRowNames(m))
+	m.handleKey("s")
+	assert.Equal(t, []string{"web", "db", "cache"}, tuiRowNames(m))
+	assert.Contains(t, m.title(), "sorted by project (reversed)")
+
+	m.handleKey("down")
+	require.Equal(t, "cache", m.selected().AppName)
+	for _, key := range []string{"/", "b", "a", "c", "k", "x", "backspace", "enter"} {
+		m.handleKey(key)
+	}
+	assert.Equal(t, "back", m.filter)
+	assert.Equal(t, []string{"db", "cache"}, tuiRowNames(m))
+	assert.Equal(t, "cache", m.selected().AppName, "the selection follows the application")
+	assert.Contains(t, m.title(), `filter "back" (2 shown)`)
+
+	m.handleKey("esc")
+	assert.Empty(t, m.filter)
+	assert.Len(t, m.rows, 3)
+	assert.Equal(t, "cache", m.selected().AppName)
+
+	results := m.results
+	m.startScan()
+	assert.Nil(t, m.selected())
+	m.scanEvent(tuiScanEvent{done: true, results: results})
+	assert.Equal(t, "cache", m.selected().AppName, "the selection survives a rescan")
+}
+
+func TestTUIModel_Actions(t *testing.T) {
+	m := newTestTUIModel(
+		ApplicationCheckResult{AppName: "cache", Namespace: "argocd", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", HasUpdate: true},
+		ApplicationCheckResult{AppName: "web", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"},
+	)
+
+	m.handleKey("G")
+	assert.Equal(t, tuiNone, m.handleKey("z"))
+	assert.Equal(t, "web has no update", m.message)
+
+	m.handleKey("g")
+	assert.Equal(t, tuiSnooze, m.handleKey("z"))
+	m.markSnoozed(*m.selected(), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "Snoozed argocd/cache 2.0.0 until 2024-02-01", m.message)
+	assert.Equal(t, "snoozed", m.status(m.results[0]))
+
+	assert.Equal(t, tuiRequestUpdate, m.handleKey("u"))
+	m.confirm("Update argocd/cache from 1.0.0 to 2.0.0?")
+	assert.Equal(t, tuiNone, m.handleKey("n"))
+	assert.Equal(t, "Update cancelled", m.message)
+
+	assert.Equal(t, tuiRequestUpdate, m.handleKey("u"))
+	m.confirm("Update argocd/cache from 1.0.0 to 2.0.0?")
+	assert.Equal(t, tuiUpdate, m.handleKey("y"))
+	m.markUpdated(*m.selected(), "updated from 1.0.0 to 2.0.0")
+	assert.Equal(t, "updated", m.status(m.results[0]))
+	assert.Equal(t, tuiNone, m.handleKey("u"))
+	assert.Equal(t, "argocd/cache was already updated", m.message)
+
+	assert.Equal(t, tuiQuit, m.handleKey("q"))
+}
+
+func TestTUIModel_View(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "cache", Project: "backend", ChartName: "redis", RepoURL: "https://charts.bitnami.com/bitnami", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", ConstraintApplied: "major", HasUpdate: true},
+	}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		results = append(results, ApplicationCheckResult{AppName: "app-" + name, ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.0.0"})
+	}
+	m := newTestTUIModel(results...)
+
+	lines := m.view(80, 8)
+	require.Len(t, lines, 8)
+	assert.Contains(t, lines[0], "9 applications, 1 updates, 0 errors")
+	assert.Contains(t, lines[1], "STATUS      APPLICATION  PROJECT  CHART  CURRENT  LATEST")
+	assert.Contains(t, lines[2], tuiReverse+"update      cache")
+	assert.Contains(t, lines[7], "q quit")
+
+	m.handleKey("end")
+	lines = m.view(80, 8)
+	assert.Contains(t, lines[5], "app-h", "the view scrolls to the selection")
+
+	m.handleKey("home")
+	m.handleKey("enter")
+	lines = m.view(80, 20)
+	require.Len(t, lines, 20)
+	detail := strings.Join(lines, "\n")
+	assert.Contains(t, detail, "Repository:       https://charts.bitnami.com/bitnami")
+	assert.Contains(t, detail, "Latest version:   2.0.0 (constraint: major)")
+
+	for _, line := range m.view(20, 20) {
+		plain := strings.NewReplacer(tuiBold, "", tuiReverse, "", tuiReset, "").Replace(line)
+		assert.LessOrEqual(t, len([]rune(plain)), 20)
+	}
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"argazer/internal/config"
+	"argazer/internal/gitops"
+	"argazer/internal/i18n"
+	"argazer/internal/notification"
+	"argazer/internal/prcomment"
+)
+
+// pendingUpdate is an application whose Helm source can be bumped to the latest version
+type pendingUpdate struct {
+	app         *v1alpha1.Application
+	sourceIndex int // Index in spec.sources, -1 for spec.source
+	result      ApplicationCheckResult
+}
+
+// newUpdateCmd creates the update command
+func newUpdateCmd() *cobra.Command {
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Bump applications' chart versions to the latest version within the constraint",
+		Long: `Update checks the selected applications like a regular run and, for every application with an
+update inside the version constraint, sets the Helm source's targetRevision to the latest version
+through the ArgoCD API (or the Kubernetes API in kubernetes mode). ArgoCD then syncs the change
+according to the application's sync policy.
+With gitops_provider set, a pull/merge request changing the Application manifest in Git is opened
+instead (see gitops_repositories).
+Pinned revisions, mutable tags, tracked branches and relocated charts are never changed, and
+applications inside a deny sync window (or outside all of their allow windows) are skipped until the
+next allowed window.`,
+		RunE: runUpdate,
+	}
+
+	updateCmd.Flags().Bool("dry-run", false, "Only show the updates that would be applied")
+	updateCmd.Flags().Bool("confirm", false, "Ask for confirmation before updating each application")
+	updateCmd.Flags().String("gitops-provider", "", "Open pull/merge requests on github or gitlab instead of patching applications")
+
+	if err := viper.BindPFlags(updateCmd.Flags()); err != nil {
+		logrus.WithError(err).Fatal("Failed to bind update flags")
+	}
+
+	return updateCmd
+}
+
+// runUpdate checks applications for updates and applies the ones within the constraint
+func runUpdate(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	confirm, _ := cmd.Flags().GetBool("confirm")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Set up logging
+	logger, err := setupConfiguredLogging(cfg)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"argocd_url": cfg.ArgocdURL,
+		"projects":   cfg.Projects,
+		"dry_run":    dryRun,
+		"version":    version,
+	}).Info("Starting Argazer update")
+
+	// Set up context with signal handling for graceful shutdown
+	ctx, cancel := setupSignalHandler(logger)
+	defer cancel()
+
+	if cfg.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancelTimeout()
+	}
+
+	clients, err := initializeClients(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	// Open pull requests against the manifests instead of patching the applications
+	var creator *gitops.Creator
+	if cfg.GitOpsProvider != "" {
+		if creator, err = newGitOpsCreator(cfg, logger.WithField("component", "gitops")); err != nil {
+			return err
+		}
+	}
+
+	// The applications are needed to patch them, so this doesn't go through scan
+	var apps []*v1alpha1.Application
+	if err := withTimeout(ctx, cfg.ArgocdTimeout, func(ctx context.Context) error {
+		var err error
+		apps, _, err = fetchApplications(ctx, clients, cfg, logger)
+		return err
+	}); err != nil {
+		return err
+	}
+	results := checkApplicationsConcurrently(ctx, apps, clients.helm, cfg, logger)
+	clients.helm.ReleaseClones()
+	applyIgnoreRules(results, cfg.Ignore, time.Now(), logger)
+
+	// Updates wait for the next allowed sync window like ArgoCD's own syncs. Projects whose windows
+	// can't be read are logged and left unblocked.
+	_ = withTimeout(ctx, cfg.ArgocdTimeout, func(ctx context.Context) error {
+		annotateSyncWindows(ctx, apps, results, clients.projectSyncWindows, time.Now(), logger)
+		return nil
+	})
+
+	updates := pendingUpdates(apps, results, cfg.SourceName, logger)
+	out := cmd.OutOrStdout()
+	if len(updates) == 0 {
+		fmt.Fprintln(out, "All applications are up to date within the version constraint")
+		return nil
+	}
+
+	run := &updateRun{
+		out:          out,
+		tr:           i18n.New(cfg.Language),
+		dryRun:       dryRun,
+		confirm:      confirm,
+		pullRequests: creator != nil,
+		apply: func(update pendingUpdate) (string, error) {
+			return applyUpdate(ctx, cfg, clients, creator, update)
+		},
+		logger: logger,
+	}
+	applied, deferred, failed, err := run.run(updates)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"updated":  applied,
+		"deferred": deferred,
+		"failed":   failed,
+	}).Info("Argazer update completed")
+
+	if failed > 0 {
+		return fmt.Errorf("failed to update %d of %d applications", failed, len(updates))
+	}
+	return nil
+}
+
+// updateRun applies pending updates one at a time and reports the outcome of each
+type updateRun struct {
+	out          io.Writer
+	tr           *i18n.Localizer
+	dryRun       bool // Only report the updates that would be applied
+	confirm      bool // Ask before applying each update
+	pullRequests bool // apply opens pull requests instead of patching applications
+	apply        func(update pendingUpdate) (string, error)
+	logger       *logrus.Entry
+}
+
+// run applies the updates, skipping those blocked by a sync window, and returns how many were applied,
+// deferred and failed
+func (r *updateRun) run(updates []pendingUpdate) (applied, deferred, failed int, err error) {
+	for _, update := range updates {
+		name := update.app.Name
+		if update.app.Namespace != "" {
+			name = update.app.Namespace + "/" + name
+		}
+		if instance := applicationInstance(update.app); instance != "" {
+			name = instance + ":" + name
+		}
+		from, to := update.result.CurrentVersion, update.result.LatestVersion
+
+		// Also reported in dry runs, which would otherwise promise a change that isn't made
+		if update.result.SyncBlocked {
+			deferred++
+			fmt.Fprintf(r.out, "Skipped %s (%s -> %s): %s\n", name, from, to,
+				notification.FormatSyncDeferral(r.tr, update.result.SyncBlockedBy, update.result.NextSyncWindow))
+			continue
+		}
+
+		if r.dryRun {
+			if r.pullRequests {
+				fmt.Fprintf(r.out, "Would open a pull request for %s: %s -> %s\n", name, from, to)
+			} else {
+				fmt.Fprintf(r.out, "Would update %s: %s -> %s\n", name, from, to)
+			}
+			continue
+		}
+
+		if r.confirm {
+			proceed := false
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf("Update %s from %s to %s?", name, from, to),
+				Default: false,
+			}
+			if err := survey.AskOne(prompt, &proceed); err != nil {
+				return applied, deferred, failed, fmt.Errorf("failed to read confirmation: %w", err)
+			}
+			if !proceed {
+				fmt.Fprintf(r.out, "Skipped %s\n", name)
+				continue
+			}
+		}
+
+		url, err := r.apply(update)
+		if errors.Is(err, gitops.ErrNoRepository) {
+			fmt.Fprintf(r.out, "Skipped %s: no gitops_repositories entry matches it\n", name)
+			continue
+		}
+		if err != nil {
+			failed++
+			if r.pullRequests {
+				r.logger.WithError(err).WithField("app_name", update.app.Name).Error("Failed to open pull request")
+				fmt.Fprintf(r.out, "Failed to open a pull request for %s: %v\n", name, err)
+			} else {
+				r.logger.WithError(err).WithField("app_name", update.app.Name).Error("Failed to update application")
+				fmt.Fprintf(r.out, "Failed to update %s: %v\n", name, err)
+			}
+			continue
+		}
+
+		applied++
+		if url != "" {
+			fmt.Fprintf(r.out, "Pull request for %s (%s -> %s): %s\n", name, from, to, url)
+		} else {
+			fmt.Fprintf(r.out, "Updated %s: %s -> %s\n", name, from, to)
+		}
+	}
+	return applied, deferred, failed, nil
+}
+
+// applyUpdate bumps an application to its latest version: through a pull request with the creator, which
+// returns its URL, or by patching the application
+func applyUpdate(ctx context.Context, cfg *config.Config, clients *clients, creator *gitops.Creator, update pendingUpdate) (string, error) {
+	if creator != nil {
+		return creator.Create(ctx, gitopsUpdate(update.result))
+	}
+
+	client, err := clients.forApplication(update.app)
+	if err != nil {
+		return "", err
+	}
+	return "", withTimeout(ctx, cfg.ArgocdTimeout, func(ctx context.Context) error {
+		return client.UpdateTargetRevision(ctx, update.app, update.sourceIndex, update.result.CurrentVersion, update.result.LatestVersion)
+	})
+}
+
+// pendingUpdates returns the updates that can be applied automatically: available within the constraint,
+// and not on a pinned revision, mutable tag, tracked branch or relocated chart
+func pendingUpdates(apps []*v1alpha1.Application, results []ApplicationCheckResult, sourceName string, logger *logrus.Entry) []pendingUpdate {
+	appsByName := make(map[string]*v1alpha1.Application, len(apps))
+	for _, app := range apps {
+		appsByName[applicationKey(app)] = app
+	}
+
+	var updates []pendingUpdate
+	for _, result := range results {
+		if result.AppName == "" || !result.HasUpdate || result.Error != "" || result.LatestVersion == "" {
+			continue
+		}
+		if result.PinnedBy != "" || result.MutableTag != "" || result.TrackingBranch != "" || result.RelocatedTo != "" {
+			continue
+		}
+
+		app := appsByName[resultKey(result)]
+		if app == nil {
+			continue
+		}
+		source := findHelmSource(app, sourceName, logger)
+		if source == nil || source.TargetRevision != result.CurrentVersion {
+			continue
+		}
+
+		updates = append(updates, pendingUpdate{
+			app:         app,
+			sourceIndex: sourceIndex(app, source),
+			result:      result,
+		})
+	}
+
+	// Results arrive in completion order; prompts and output follow the application names
+	sort.Slice(updates, func(i, j int) bool {
+		a, b := updates[i].app, updates[j].app
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return updates
+}
+
+// sourceIndex returns the index of a source in the application's spec.sources, or -1 for spec.source
+func sourceIndex(app *v1alpha1.Application, source *v1alpha1.ApplicationSource) int {
+	for i := range app.Spec.Sources {
+		if &app.Spec.Sources[i] == source {
+			return i
+		}
+	}
+	return -1
+}
+
+// newGitOpsCreator creates the pull/merge request creator for the configured provider
+// Tokens and API URLs are shared with pr_comment, including their CI environment defaults.
+func newGitOpsCreator(cfg *config.Config, logger *logrus.Entry) (*gitops.Creator, error) {
+	var provider gitops.Provider
+	switch cfg.GitOpsProvider {
+	case config.GitOpsGitHub:
+		github := prcomment.GitHubConfigFromEnv(prcomment.GitHubConfig{APIURL: cfg.GitHubAPIURL, Token: cfg.GitHubToken})
+		p, err := gitops.NewGitHubProvider(github.APIURL, github.Token, nil, logger)
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	case config.GitOpsGitLab:
+		gitlab := prcomment.GitLabConfigFromEnv(prcomment.GitLabConfig{APIURL: cfg.GitLabAPIURL, Token: cfg.GitLabToken})
+		p, err := gitops.NewGitLabProvider(gitlab.APIURL, gitlab.Token, nil, logger)
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	default:
+		return nil, fmt.Errorf("unknown gitops_provider: %s", cfg.GitOpsProvider)
+	}
+
+	repositories := make([]gitops.Repository, 0, len(cfg.GitOpsRepositories))
+	for _, repo := range cfg.GitOpsRepositories {
+		repositories = append(repositories, gitops.Repository{
+			Project:    repo.Project,
+			App:        repo.App,
+			Repository: repo.Repository,
+			Path:       repo.Path,
+			BaseBranch: repo.BaseBranch,
+		})
+	}
+
+	return gitops.NewCreator(provider, repositories, gitops.Templates{
+		Branch: cfg.GitOpsBranchTemplate,
+		Title:  cfg.GitOpsTitleTemplate,
+		Body:   cfg.GitOpsBodyTemplate,
+	}, logger)
+}
+
+// gitopsUpdate converts a check result to the data pull request templates are rendered with
+func gitopsUpdate(result ApplicationCheckResult) gitops.Update {
+	return gitops.Update{
+		AppName:        result.AppName,
+		Namespace:      result.Namespace,
+		Project:        result.Project,
+		ChartName:      result.ChartName,
+		RepoURL:        result.RepoURL,
+		CurrentVersion: result.CurrentVersion,
+		LatestVersion:  result.LatestVersion,
+		Severity:       result.Severity,
+		URL:            result.URL,
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"argazer/internal/i18n"
+)
+
+func TestPendingUpdates(t *testing.T) {
+	single := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "argocd"},
+		Spec: v1alpha1.ApplicationSpec{
+			Source: &v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com", Chart: "nginx", TargetRevision: "1.0.0"},
+		},
+	}
+	multi := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "argocd"},
+		Spec: v1alpha1.ApplicationSpec{
+			Sources: []v1alpha1.ApplicationSource{
+				{RepoURL: "https://github.com/example/values.git", Ref: "values"},
+				{RepoURL: "https://charts.example.com", Chart: "redis", TargetRevision: "2.0.0"},
+			},
+		},
+	}
+	pinned := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "pinned", Namespace: "argocd"},
+		Spec: v1alpha1.ApplicationSpec{
+			Source: &v1alpha1.ApplicationSource{RepoURL: "oci://registry.example.com/charts", Chart: "api", TargetRevision: "sha256:abc"},
+		},
+	}
+	failing := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: "argocd"},
+		Spec: v1alpha1.ApplicationSpec{
+			Source: &v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com", Chart: "broken", TargetRevision: "1.0.0"},
+		},
+	}
+
+	results := []ApplicationCheckResult{
+		{AppName: "nginx", Namespace: "argocd", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "app", Namespace: "argocd", CurrentVersion: "2.0.0", LatestVersion: "2.3.0", HasUpdate: true},
+		{AppName: "pinned", Namespace: "argocd", CurrentVersion: "sha256:abc", LatestVersion: "1.2.0", HasUpdate: true, PinnedBy: "digest"},
+		{AppName: "failing", Namespace: "argocd", CurrentVersion: "1.0.0", HasUpdate: true, Error: "timeout"},
+		{AppName: "outside", Namespace: "argocd", CurrentVersion: "1.0.0", LatestVersion: "1.0.0", HasUpdateOutsideConstraint: true},
+	}
+
+	updates := pendingUpdates([]*v1alpha1.Application{single, multi, pinned, failing}, results, "", logrus.NewEntry(logrus.New()))
+	require.Len(t, updates, 2)
+
+	assert.Equal(t, "app", updates[0].app.Name)
+	assert.Equal(t, 1, updates[0].sourceIndex)
+	assert.Equal(t, "2.0.0", updates[0].result.CurrentVersion)
+	assert.Equal(t, "2.3.0", updates[0].result.LatestVersion)
+
+	assert.Equal(t, "nginx", updates[1].app.Name)
+	assert.Equal(t, -1, updates[1].sourceIndex)
+	assert.Equal(t, "1.1.0", updates[1].result.LatestVersion)
+}
+
+func TestUpdateRun_SyncWindows(t *testing.T) {
+	app := func(name string) *v1alpha1.Application {
+		return &v1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "argocd"},
+			Spec: v1alpha1.ApplicationSpec{
+				Project: "prod",
+				Source:  &v1alpha1.ApplicationSource{RepoURL: "https://charts.example.com", Chart: name, TargetRevision: "1.0.0"},
+			},
+		}
+	}
+	apps := []*v1alpha1.Application{app("frozen"), app("free")}
+	windowsFor := func(context.Context, string, string) (v1alpha1.SyncWindows, error) {
+		return v1alpha1.SyncWindows{
+			&v1alpha1.SyncWindow{Kind: "deny", Schedule: "0 22 * * *", Duration: "8h", Applications: []string{"frozen"}},
+		}, nil
+	}
+	logger := logrus.NewEntry(logrus.New())
+
+	for _, dryRun := range []bool{false, true} {
+		results := []ApplicationCheckResult{
+			{AppName: "frozen", Namespace: "argocd", Project: "prod", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+			{AppName: "free", Namespace: "argocd", Project: "prod", CurrentVersion: "1.0.0", LatestVersion: "1.2.0", HasUpdate: true},
+		}
+		annotateSyncWindows(context.Background(), apps, results, windowsFor, time.Date(2024, 1, 10, 23, 0, 0, 0, time.UTC), logger)
+		updates := pendingUpdates(apps, results, "", logger)
+		require.Len(t, updates, 2)
+
+		var applied []string
+		var out bytes.Buffer
+		run := &updateRun{
+			out:    &out,
+			tr:     i18n.New("en"),
+			dryRun: dryRun,
+			apply: func(update pendingUpdate) (string, error) {
+				applied = append(applied, update.app.Name)
+				return "", nil
+			},
+			logger: logger,
+		}
+		updated, deferred, failed, err := run.run(updates)
+		require.NoError(t, err)
+		assert.Equal(t, 1, deferred)
+		assert.Zero(t, failed)
+		assert.Contains(t, out.String(), "Skipped argocd/frozen (1.0.0 -> 1.1.0): Deferred until 2024-01-11 06:00 UTC (deny 0 22 * * * (8h))\n")
+		assert.NotContains(t, out.String(), "update argocd/frozen")
+
+		if dryRun {
+			assert.Empty(t, applied)
+			assert.Zero(t, updated)
+			assert.Contains(t, out.String(), "Would update argocd/free: 1.0.0 -> 1.2.0\n")
+		} else {
+			assert.Equal(t, []string{"free"}, applied, "blocked applications are never changed")
+			assert.Equal(t, 1, updated)
+			assert.Contains(t, out.String(), "Updated argocd/free: 1.0.0 -> 1.2.0\n")
+		}
+	}
+}
+
+func TestGitOpsUpdate(t *testing.T) {
+	update := gitopsUpdate(ApplicationCheckResult{
+		AppName:        "nginx",
+		Namespace:      "argocd",
+		Project:        "web",
+		ChartName:      "nginx",
+		RepoURL:        "https://charts.example.com",
+		CurrentVersion: "1.0.0",
+		LatestVersion:  "1.1.0",
+		Severity:       "minor",
+		URL:            "https://argocd.example.com/applications/argocd/nginx",
+	})
+	assert.Equal(t, "nginx", update.AppName)
+	assert.Equal(t, "web", update.Project)
+	assert.Equal(t, "1.0.0", update.CurrentVersion)
+	assert.Equal(t, "1.1.0", update.LatestVersion)
+	assert.Equal(t, "minor", update.Severity)
+	assert.Equal(t, "https://argocd.example.com/applications/argocd/nginx", update.URL)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"argazer/internal/helm"
+	"argazer/internal/i18n"
+	"argazer/internal/trivy"
+)
+
+// SecurityFixes compares the known vulnerabilities of the images of an update's current and latest
+// chart versions
+type SecurityFixes struct {
+	Images     []string              `json:"images"`               // Images of the latest version, as scanned
+	Fixed      []trivy.Vulnerability `json:"fixed,omitempty"`      // Vulnerabilities of the current version's images the update fixes
+	Introduced []trivy.Vulnerability `json:"introduced,omitempty"` // Vulnerabilities only found in the latest version's images
+}
+
+// chartImagesLookup returns the images a chart version deploys by default
+type chartImagesLookup func(ctx context.Context, repoURL, chartName, version string) ([]helm.ImageReference, error)
+
+// imageScan returns the known vulnerabilities of an image
+type imageScan func(ctx context.Context, image string) ([]trivy.Vulnerability, error)
+
+// checkSecurityFixes scans the images of the current and latest chart version of each update and
+// records the vulnerabilities the update fixes and introduces
+// Each chart update is compared once, with up to concurrency comparisons in flight. Updates whose
+// images can't be read or scanned are logged and left without comparison.
+func checkSecurityFixes(ctx context.Context, results []ApplicationCheckResult, imagesOf chartImagesLookup, scan imageScan, concurrency int, logger *logrus.Entry) {
+	type chartUpdate struct {
+		repoURL, chartName, current, latest string
+	}
+
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+
+	var updates []chartUpdate
+	seen := make(map[chartUpdate]bool)
+	resultUpdates := make(map[int]chartUpdate)
+	for i, result := range results {
+		if !result.HasUpdate || result.Error != "" {
+			continue
+		}
+		update := chartUpdate{result.RepoURL, result.ChartName, result.CurrentVersion, result.LatestVersion}
+		if !seen[update] {
+			seen[update] = true
+			updates = append(updates, update)
+		}
+		resultUpdates[i] = update
+	}
+	if len(updates) == 0 {
+		return
+	}
+
+	// Workers write to a separate map, the update list isn't modified while iterated
+	updateFixes := make(map[chartUpdate]*SecurityFixes, len(updates))
+	var mu sync.Mutex
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, update := range updates {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			updateLogger := logger.WithFields(logrus.Fields{
+				"chart":           update.chartName,
+				"current_version": update.current,
+				"latest_version":  update.latest,
+			})
+
+			current, _, err := chartVulnerabilities(ctx, update.repoURL, update.chartName, update.current, imagesOf, scan)
+			if err != nil {
+				updateLogger.WithError(err).Warn("Failed to scan images of the current chart version")
+				return
+			}
+			latest, images, err := chartVulnerabilities(ctx, update.repoURL, update.chartName, update.latest, imagesOf, scan)
+			if err != nil {
+				updateLogger.WithError(err).Warn("Failed to scan images of the latest chart version")
+				return
+			}
+
+			fixes := &SecurityFixes{
+				Images:     images,
+				Fixed:      missingVulnerabilities(current, latest),
+				Introduced: missingVulnerabilities(latest, current),
+			}
+			updateLogger.WithFields(logrus.Fields{
+				"fixed":      len(fixes.Fixed),
+				"introduced": len(fixes.Introduced),
+			}).Debug("Compared image vulnerabilities")
+
+			mu.Lock()
+			updateFixes[update] = fixes
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for i, update := range resultUpdates {
+		results[i].SecurityFixes = updateFixes[update]
+	}
+}
+
+// chartVulnerabilities scans the images of a chart version and returns their vulnerabilities, sorted
+// by ID, with the images scanned
+func chartVulnerabilities(ctx context.Context, repoURL, chartName, version string, imagesOf chartImagesLookup, scan imageScan) ([]trivy.Vulnerability, []string, error) {
+	references, err := imagesOf(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[string]bool)
+	var vulnerabilities []trivy.Vulnerability
+	images := make([]string, 0, len(references))
+	for _, reference := range references {
+		image := reference.String()
+		images = append(images, image)
+		found, err := scan(ctx, image)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, v := range found {
+			if !seen[v.ID] {
+				seen[v.ID] = true
+				vulnerabilities = append(vulnerabilities, v)
+			}
+		}
+	}
+	sort.Slice(vulnerabilities, func(i, j int) bool { return vulnerabilities[i].ID < vulnerabilities[j].ID })
+	return vulnerabilities, images, nil
+}
+
+// missingVulnerabilities returns the vulnerabilities of from that aren't in to
+func missingVulnerabilities(from, to []trivy.Vulnerability) []trivy.Vulnerability {
+	ids := make(map[string]bool, len(to))
+	for _, v := range to {
+		ids[v.ID] = true
+	}
+	var missing []trivy.Vulnerability
+	for _, v := range from {
+		if !ids[v.ID] {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// formatSecurityFixes summarizes the vulnerabilities an update fixes and introduces
+func formatSecurityFixes(fixes *SecurityFixes, tr *i18n.Localizer) string {
+	summary := tr.T(i18n.VulnerabilitiesFixed, len(fixes.Fixed))
+	if len(fixes.Introduced) > 0 {
+		summary += ", " + tr.T(i18n.VulnerabilitiesIntroduced, len(fixes.Introduced))
+	}
+	return summary
+}
+
+// securityFixesIDs lists the IDs of the vulnerabilities an update fixes ("-") and introduces ("+"),
+// capped like values diff keys
+func securityFixesIDs(fixes *SecurityFixes) []string {
+	var ids []string
+	for _, v := range fixes.Fixed {
+		ids = append(ids, "- "+v.ID+" ("+strings.ToLower(v.Severity)+")")
+	}
+	for _, v := range fixes.Introduced {
+		ids = append(ids, "+ "+v.ID+" ("+strings.ToLower(v.Severity)+")")
+	}
+	if len(ids) > maxValuesDiffKeys {
+		ids = append(ids[:maxValuesDiffKeys], fmt.Sprintf("… (+%d)", len(ids)-maxValuesDiffKeys))
+	}
+	return ids
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"argazer/internal/helm"
+	"argazer/internal/trivy"
+)
+
+func TestCheckSecurityFixes(t *testing.T) {
+	results := []ApplicationCheckResult{
+		{AppName: "web", RepoURL: "https://charts.example.com", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "web-staging", RepoURL: "https://charts.example.com", ChartName: "nginx", CurrentVersion: "1.0.0", LatestVersion: "1.1.0", HasUpdate: true},
+		{AppName: "api", RepoURL: "https://charts.example.com", ChartName: "api", CurrentVersion: "2.0.0", LatestVersion: "2.1.0", HasUpdate: true},
+		{AppName: "db", RepoURL: "https://charts.example.com", ChartName: "postgresql", CurrentVersion: "12.0.0", LatestVersion: "12.0.0"},
+	}
+
+	var mu sync.Mutex
+	var lookups []string
+	imagesOf := func(ctx context.Context, repoURL, chartName, version string) ([]helm.ImageReference, error) {
+		mu.Lock()
+		lookups = append(lookups, chartName+" "+version)
+		mu.Unlock()
+		if chartName == "api" {
+			return nil, errors.New("chart not found")
+		}
+		tag := map[string]string{"1.0.0": "1.25", "1.1.0": "1.27"}[version]
+		return []helm.ImageReference{
+			{Registry: "docker.io", Repository: "library/nginx", Tag: tag},
+			{Registry: "docker.io", Repository: "library/busybox", Tag: "1.36"},
+		}, nil
+	}
+	scan := func(ctx context.Context, image string) ([]trivy.Vulnerability, error) {
+		switch image {
+		case "docker.io/library/nginx:1.25":
+			return []trivy.Vulnerability{{ID: "CVE-2024-2", Severity: "CRITICAL"}, {ID: "CVE-2024-3", Severity: "HIGH"}}, nil
+		case "docker.io/library/nginx:1.27":
+			return []trivy.Vulnerability{{ID: "CVE-2025-1", Severity: "HIGH"}}, nil
+		default:
+			return []trivy.Vulnerability{{ID: "CVE-2024-1", Severity: "HIGH"}}, nil
+		}
+	}
+
+	checkSecurityFixes(context.Background(), results, imagesOf, scan, 2, logrus.NewEntry(logrus.New()))
+
+	assert.ElementsMatch(t, []string{"nginx 1.0.0", "nginx 1.1.0", "api 2.0.0"}, lookups, "each chart update is compared once")
+	assert.Equal(t, &SecurityFixes{
+		Images:     []string{"docker.io/library/nginx:1.27", "docker.io/library/busybox:1.36"},
+		Fixed:      []trivy.Vulnerability{{ID: "CVE-2024-2", Severity: "CRITICAL"}, {ID: "CVE-2024-3", Severity: "HIGH"}},
+		Introduced: []trivy.Vulnerability{{ID: "CVE-2025-1", Severity: "HIGH"}},
+	}, results[0].SecurityFixes)
+	assert.Equal(t, results[0].SecurityFixes, results[1].SecurityFixes)
+	assert.Nil(t, results[2].SecurityFixes, "unreadable charts are left without comparison")
+	assert.Nil(t, results[3].SecurityFixes)
+
+	updates := toApplicationUpdates(results)
+	assert.Equal(t, 2, updates[0].FixedVulnerabilities)
+	assert.Zero(t, updates[2].FixedVulnerabilities)
+}
+
+func TestOutputResults_SecurityFixes(t *testing.T) {
+	results := []ApplicationCheckResult{{
+		AppName:        "web",
+		Project:        "default",
+		CurrentVersion: "1.0.0",
+		LatestVersion:  "1.1.0",
+		HasUpdate:      true,
+		SecurityFixes: &SecurityFixes{
+			Images:     []string{"docker.io/library/nginx:1.27"},
+			Fixed:      []trivy.Vulnerability{{ID: "CVE-2024-2", Severity: "CRITICAL"}},
+			Introduced: []trivy.Vulnerability{{ID: "CVE-2025-1", Severity: "HIGH"}},
+		},
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, outputResults(results, "table", nil, &table))
+	assert.Contains(t, table.String(), "  Security Fixes: 1 known vulnerabilities fixed, 1 introduced\n    - CVE-2024-2 (critical)\n    + CVE-2025-1 (high)\n")
+
+	var md bytes.Buffer
+	require.NoError(t, outputResults(results, "markdown", nil, &md))
+	assert.Contains(t, md.String(), "| **Security Fixes** | 1 known vulnerabilities fixed, 1 introduced<br>`- CVE-2024-2 (critical)` `+ CVE-2025-1 (high)` |\n")
+
+	var out bytes.Buffer
+	require.NoError(t, outputResults(results, "json", nil, &out))
+	assert.Contains(t, out.String(), `"security_fixes": {`)
+}